@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descAdminNetworkPolicyAnnotationsName     = "kube_adminnetworkpolicy_annotations"
+	descAdminNetworkPolicyAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descAdminNetworkPolicyLabelsName          = "kube_adminnetworkpolicy_labels"
+	descAdminNetworkPolicyLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descAdminNetworkPolicyLabelsDefaultLabels = []string{"adminnetworkpolicy"}
+)
+
+func adminNetworkPolicyMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_adminnetworkpolicy_info",
+			"Information about adminnetworkpolicy.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapAdminNetworkPolicyFunc(func(p *adminNetworkPolicy) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   adminNetworkPolicySubjectLabelKeys,
+							LabelValues: p.Spec.Subject.labelValues(),
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_adminnetworkpolicy_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapAdminNetworkPolicyFunc(func(p *adminNetworkPolicy) *metric.Family {
+				ms := []*metric.Metric{}
+				if !p.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(p.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descAdminNetworkPolicyAnnotationsName,
+			descAdminNetworkPolicyAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapAdminNetworkPolicyFunc(func(p *adminNetworkPolicy) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", p.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descAdminNetworkPolicyLabelsName,
+			descAdminNetworkPolicyLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapAdminNetworkPolicyFunc(func(p *adminNetworkPolicy) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", p.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_adminnetworkpolicy_priority",
+			"The priority of the adminnetworkpolicy, policies with lower values take precedence.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapAdminNetworkPolicyFunc(func(p *adminNetworkPolicy) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(p.Spec.Priority),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_adminnetworkpolicy_rules",
+			"Number of ingress and egress rules of the adminnetworkpolicy per action.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapAdminNetworkPolicyFunc(func(p *adminNetworkPolicy) *metric.Family {
+				return &metric.Family{
+					Metrics: adminNetworkPolicyRuleMetrics(p.Spec.Ingress, p.Spec.Egress),
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_adminnetworkpolicy_status_condition",
+			"The condition of the adminnetworkpolicy.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapAdminNetworkPolicyFunc(func(p *adminNetworkPolicy) *metric.Family {
+				return &metric.Family{
+					Metrics: addStatusConditionMetrics(p.Status.Conditions, nil, nil),
+				}
+			}),
+		),
+	}
+}
+
+func wrapAdminNetworkPolicyFunc(f func(*adminNetworkPolicy) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		p := &adminNetworkPolicy{}
+		fromUnstructured(obj, p)
+
+		metricFamily := f(p)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descAdminNetworkPolicyLabelsDefaultLabels, []string{p.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
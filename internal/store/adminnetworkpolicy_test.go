@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestAdminNetworkPolicyStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: mustUnstructured(t, `{
+				"apiVersion": "policy.networking.k8s.io/v1alpha1",
+				"kind": "AdminNetworkPolicy",
+				"metadata": {"name": "cluster-control", "creationTimestamp": "2017-08-01T06:30:18Z"},
+				"spec": {
+					"priority": 20,
+					"subject": {
+						"pods": {
+							"namespaceSelector": {"matchLabels": {"team": "payments"}},
+							"podSelector": {"matchExpressions": [{"key": "app", "operator": "In", "values": ["api", "db"]}]}
+						}
+					},
+					"ingress": [
+						{"name": "allow-monitoring", "action": "Allow", "from": [{"namespaces": {"matchLabels": {"kubernetes.io/metadata.name": "monitoring"}}}]},
+						{"name": "pass-same-team", "action": "Pass", "from": [{"namespaces": {"matchLabels": {"team": "payments"}}}]},
+						{"name": "deny-all", "action": "Deny", "from": [{"namespaces": {}}]}
+					],
+					"egress": [
+						{"name": "allow-dns", "action": "Allow", "to": [{"namespaces": {}}]},
+						{"name": "allow-api", "action": "Allow", "to": [{"namespaces": {}}]}
+					]
+				},
+				"status": {
+					"conditions": [{"type": "Ready", "status": "True", "lastTransitionTime": "2017-08-01T06:30:18Z", "reason": "SetupSucceeded", "message": ""}]
+				}
+			}`),
+			Want: `
+				# HELP kube_adminnetworkpolicy_created Unix creation timestamp
+				# HELP kube_adminnetworkpolicy_info Information about adminnetworkpolicy.
+				# HELP kube_adminnetworkpolicy_priority The priority of the adminnetworkpolicy, policies with lower values take precedence.
+				# HELP kube_adminnetworkpolicy_rules Number of ingress and egress rules of the adminnetworkpolicy per action.
+				# HELP kube_adminnetworkpolicy_status_condition The condition of the adminnetworkpolicy.
+				# TYPE kube_adminnetworkpolicy_created gauge
+				# TYPE kube_adminnetworkpolicy_info gauge
+				# TYPE kube_adminnetworkpolicy_priority gauge
+				# TYPE kube_adminnetworkpolicy_rules gauge
+				# TYPE kube_adminnetworkpolicy_status_condition gauge
+				kube_adminnetworkpolicy_created{adminnetworkpolicy="cluster-control"} 1.501569018e+09
+				kube_adminnetworkpolicy_info{adminnetworkpolicy="cluster-control",subject_namespace_selector="team=payments",subject_pod_selector="app in (api,db)",subject_type="pods"} 1
+				kube_adminnetworkpolicy_priority{adminnetworkpolicy="cluster-control"} 20
+				kube_adminnetworkpolicy_rules{action="Allow",adminnetworkpolicy="cluster-control",direction="egress"} 2
+				kube_adminnetworkpolicy_rules{action="Allow",adminnetworkpolicy="cluster-control",direction="ingress"} 1
+				kube_adminnetworkpolicy_rules{action="Deny",adminnetworkpolicy="cluster-control",direction="ingress"} 1
+				kube_adminnetworkpolicy_rules{action="Pass",adminnetworkpolicy="cluster-control",direction="ingress"} 1
+				kube_adminnetworkpolicy_status_condition{adminnetworkpolicy="cluster-control",condition="Ready",status="false"} 0
+				kube_adminnetworkpolicy_status_condition{adminnetworkpolicy="cluster-control",condition="Ready",status="true"} 1
+				kube_adminnetworkpolicy_status_condition{adminnetworkpolicy="cluster-control",condition="Ready",status="unknown"} 0
+			`,
+			MetricNames: []string{
+				"kube_adminnetworkpolicy_created",
+				"kube_adminnetworkpolicy_info",
+				"kube_adminnetworkpolicy_priority",
+				"kube_adminnetworkpolicy_rules",
+				"kube_adminnetworkpolicy_status_condition",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(adminNetworkPolicyMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(adminNetworkPolicyMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descBaselineAdminNetworkPolicyAnnotationsName     = "kube_baselineadminnetworkpolicy_annotations"
+	descBaselineAdminNetworkPolicyAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descBaselineAdminNetworkPolicyLabelsName          = "kube_baselineadminnetworkpolicy_labels"
+	descBaselineAdminNetworkPolicyLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descBaselineAdminNetworkPolicyLabelsDefaultLabels = []string{"baselineadminnetworkpolicy"}
+)
+
+func baselineAdminNetworkPolicyMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_baselineadminnetworkpolicy_info",
+			"Information about baselineadminnetworkpolicy.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapBaselineAdminNetworkPolicyFunc(func(p *baselineAdminNetworkPolicy) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   adminNetworkPolicySubjectLabelKeys,
+							LabelValues: p.Spec.Subject.labelValues(),
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_baselineadminnetworkpolicy_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapBaselineAdminNetworkPolicyFunc(func(p *baselineAdminNetworkPolicy) *metric.Family {
+				ms := []*metric.Metric{}
+				if !p.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(p.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descBaselineAdminNetworkPolicyAnnotationsName,
+			descBaselineAdminNetworkPolicyAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapBaselineAdminNetworkPolicyFunc(func(p *baselineAdminNetworkPolicy) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", p.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descBaselineAdminNetworkPolicyLabelsName,
+			descBaselineAdminNetworkPolicyLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapBaselineAdminNetworkPolicyFunc(func(p *baselineAdminNetworkPolicy) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", p.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_baselineadminnetworkpolicy_rules",
+			"Number of ingress and egress rules of the baselineadminnetworkpolicy per action.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapBaselineAdminNetworkPolicyFunc(func(p *baselineAdminNetworkPolicy) *metric.Family {
+				return &metric.Family{
+					Metrics: adminNetworkPolicyRuleMetrics(p.Spec.Ingress, p.Spec.Egress),
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_baselineadminnetworkpolicy_status_condition",
+			"The condition of the baselineadminnetworkpolicy.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapBaselineAdminNetworkPolicyFunc(func(p *baselineAdminNetworkPolicy) *metric.Family {
+				return &metric.Family{
+					Metrics: addStatusConditionMetrics(p.Status.Conditions, nil, nil),
+				}
+			}),
+		),
+	}
+}
+
+func wrapBaselineAdminNetworkPolicyFunc(f func(*baselineAdminNetworkPolicy) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		p := &baselineAdminNetworkPolicy{}
+		fromUnstructured(obj, p)
+
+		metricFamily := f(p)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descBaselineAdminNetworkPolicyLabelsDefaultLabels, []string{p.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
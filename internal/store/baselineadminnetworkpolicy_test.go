@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestBaselineAdminNetworkPolicyStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: mustUnstructured(t, `{
+				"apiVersion": "policy.networking.k8s.io/v1alpha1",
+				"kind": "BaselineAdminNetworkPolicy",
+				"metadata": {"name": "default", "creationTimestamp": "2017-08-01T06:30:18Z"},
+				"spec": {
+					"subject": {"namespaces": {}},
+					"ingress": [
+						{"name": "deny-all", "action": "Deny", "from": [{"namespaces": {}}]}
+					]
+				}
+			}`),
+			Want: `
+				# HELP kube_baselineadminnetworkpolicy_created Unix creation timestamp
+				# HELP kube_baselineadminnetworkpolicy_info Information about baselineadminnetworkpolicy.
+				# HELP kube_baselineadminnetworkpolicy_rules Number of ingress and egress rules of the baselineadminnetworkpolicy per action.
+				# HELP kube_baselineadminnetworkpolicy_status_condition The condition of the baselineadminnetworkpolicy.
+				# TYPE kube_baselineadminnetworkpolicy_created gauge
+				# TYPE kube_baselineadminnetworkpolicy_info gauge
+				# TYPE kube_baselineadminnetworkpolicy_rules gauge
+				# TYPE kube_baselineadminnetworkpolicy_status_condition gauge
+				kube_baselineadminnetworkpolicy_created{baselineadminnetworkpolicy="default"} 1.501569018e+09
+				kube_baselineadminnetworkpolicy_info{baselineadminnetworkpolicy="default",subject_namespace_selector="<none>",subject_pod_selector="",subject_type="namespaces"} 1
+				kube_baselineadminnetworkpolicy_rules{action="Deny",baselineadminnetworkpolicy="default",direction="ingress"} 1
+			`,
+			MetricNames: []string{
+				"kube_baselineadminnetworkpolicy_created",
+				"kube_baselineadminnetworkpolicy_info",
+				"kube_baselineadminnetworkpolicy_rules",
+				"kube_baselineadminnetworkpolicy_status_condition",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(baselineAdminNetworkPolicyMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(baselineAdminNetworkPolicyMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
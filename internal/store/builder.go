@@ -23,28 +23,48 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscaling "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	certv1 "k8s.io/api/certificates/v1"
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	v1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	nodev1 "k8s.io/api/node/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	resourcev1 "k8s.io/api/resource/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
 	vpaclientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	vpascheme "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/scheme"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/transport"
 	"k8s.io/klog/v2"
 
 	ksmtypes "k8s.io/kube-state-metrics/v2/pkg/builder/types"
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
@@ -62,9 +82,17 @@ type Builder struct {
 	kubeClient            clientset.Interface
 	customResourceClients map[string]interface{}
 	vpaClient             vpaclientset.Interface
+	metadataClient        metadata.Interface
+	dynamicClient         dynamic.Interface
 	namespaces            options.NamespaceList
 	// namespaceFilter is inside fieldSelectorFilter
 	fieldSelectorFilter           string
+	fieldSelectors                map[string]string
+	maxObjects                    map[string]int
+	lazyResources                 map[string]struct{}
+	metricPrefixes                map[string]string
+	aggregatedResources           map[string][]string
+	objectCountResources          []metav1.APIResource
 	ctx                           context.Context
 	enabledResources              []string
 	familyGeneratorFilter         generator.FamilyGeneratorFilter
@@ -72,16 +100,35 @@ type Builder struct {
 	shardingMetrics               *sharding.Metrics
 	shard                         int32
 	totalShards                   int
+	shardingStrategy              sharding.Strategy
 	buildStoresFunc               ksmtypes.BuildStoresFunc
 	buildCustomResourceStoresFunc ksmtypes.BuildCustomResourceStoresFunc
 	allowAnnotationsList          map[string][]string
 	allowLabelsList               map[string][]string
+	labelsDenylist                map[string][]string
 	useAPIServerCache             bool
+	useWatchList                  bool
+	keepFamilies                  bool
+	listPageSize                  int64
+	startupLimiter                *watch.StartupLimiter
+	watchBackoff                  watch.Backoff
+	staleObjectGCInterval         time.Duration
+	watchHealth                   *watch.Health
+	tracer                        trace.Tracer
+	listWatchFuncs                map[string]ksmtypes.ListWatchFunc
+	tweakListOptions              map[string]func(*metav1.ListOptions)
+	customResourceFactories       []customresource.RegistryFactory
+	podStatusReasons              []string
+	resolvePodWorkloads           bool
+	recommendedLabels             bool
+	topKResources                 map[string]int
+	topKFamilies                  map[string]int
+	excludeCompleted              map[string]time.Duration
 }
 
 // NewBuilder returns a new builder.
 func NewBuilder() *Builder {
-	b := &Builder{}
+	b := &Builder{tracer: noop.NewTracerProvider().Tracer("")}
 	return b
 }
 
@@ -113,11 +160,121 @@ func (b *Builder) WithFieldSelectorFilter(fieldSelectorFilter string) {
 	b.fieldSelectorFilter = fieldSelectorFilter
 }
 
+// WithFieldSelectors sets the field selectors of the objects to be watched
+// per resource. They are ANDed with the fieldSelector property.
+func (b *Builder) WithFieldSelectors(fs map[string]string) error {
+	for resource, selector := range fs {
+		if !resourceExists(resource) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", resource, strings.Join(availableResources(), ","))
+		}
+		if _, err := fields.ParseSelector(selector); err != nil {
+			return fmt.Errorf("invalid field selector for resource %s: %w", resource, err)
+		}
+	}
+	b.fieldSelectors = fs
+	return nil
+}
+
 // WithNamespaces sets the namespaces property of a Builder.
 func (b *Builder) WithNamespaces(n options.NamespaceList) {
 	b.namespaces = n
 }
 
+// WithMaxObjects sets the maximum number of objects metrics are generated for
+// per resource.
+func (b *Builder) WithMaxObjects(m map[string]int) error {
+	for resource := range m {
+		if !resourceExists(resource) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", resource, strings.Join(availableResources(), ","))
+		}
+	}
+	b.maxObjects = m
+	return nil
+}
+
+// WithTopK sets the number of most recently updated objects whose series are
+// written per resource or per metric family, e.g. only the series of the 500
+// most recently updated Jobs. Keys which are not resources are metric family
+// names, which contain an underscore unlike resources.
+func (b *Builder) WithTopK(k map[string]int) error {
+	resources := map[string]int{}
+	families := map[string]int{}
+	for key, n := range k {
+		if resourceExists(key) {
+			resources[key] = n
+		} else if strings.Contains(key, "_") {
+			families[key] = n
+		} else {
+			return fmt.Errorf("%s is neither a resource nor a metric family. Available resources: %s", key, strings.Join(availableResources(), ","))
+		}
+	}
+	b.topKResources = resources
+	b.topKFamilies = families
+	return nil
+}
+
+// WithExcludeCompleted sets the durations after which objects which reached a
+// terminal state are excluded per resource, e.g. Succeeded and Failed Pods.
+func (b *Builder) WithExcludeCompleted(e map[string]time.Duration) error {
+	for resource := range e {
+		if _, ok := completedAtFuncs[resource]; !ok {
+			resources := make([]string, 0, len(completedAtFuncs))
+			for r := range completedAtFuncs {
+				resources = append(resources, r)
+			}
+			sort.Strings(resources)
+			return fmt.Errorf("completed objects of resource %s cannot be excluded. Supported resources: %s", resource, strings.Join(resources, ","))
+		}
+	}
+	b.excludeCompleted = e
+	return nil
+}
+
+// WithLazyResources sets the resources whose metrics are generated when they
+// are written instead of when their objects change.
+func (b *Builder) WithLazyResources(r []string) error {
+	lazy := make(map[string]struct{}, len(r))
+	for _, resource := range r {
+		if !resourceExists(resource) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", resource, strings.Join(availableResources(), ","))
+		}
+		lazy[resource] = struct{}{}
+	}
+	b.lazyResources = lazy
+	return nil
+}
+
+// WithMetricPrefixes sets the prefixes replacing the kube_ prefix of the
+// metric families of the given resources.
+func (b *Builder) WithMetricPrefixes(p map[string]string) error {
+	for resource := range p {
+		if !resourceExists(resource) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", resource, strings.Join(availableResources(), ","))
+		}
+	}
+	b.metricPrefixes = p
+	return nil
+}
+
+// WithAggregatedResources sets the resources whose metrics are summed by the
+// given labels instead of being written per object.
+func (b *Builder) WithAggregatedResources(a map[string][]string) error {
+	for resource := range a {
+		if !resourceExists(resource) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", resource, strings.Join(availableResources(), ","))
+		}
+	}
+	b.aggregatedResources = a
+	return nil
+}
+
+// WithObjectCountResources sets the resources whose objects are counted by
+// the kube_objectcount metric. Only the metadata of their objects is watched,
+// which requires the metadataClient.
+func (b *Builder) WithObjectCountResources(r []metav1.APIResource) {
+	b.objectCountResources = r
+}
+
 // MergeFieldSelectors merges multiple fieldSelectors using AND operator.
 func (b *Builder) MergeFieldSelectors(selectors []string) (string, error) {
 	return options.MergeFieldSelectors(selectors)
@@ -125,6 +282,9 @@ func (b *Builder) MergeFieldSelectors(selectors []string) (string, error) {
 
 // WithSharding sets the shard and totalShards property of a Builder.
 func (b *Builder) WithSharding(shard int32, totalShards int) {
+	if b.shard != shard || b.totalShards != totalShards {
+		b.shardingMetrics.LastRebalance.SetToCurrentTime()
+	}
 	b.shard = shard
 	labels := map[string]string{sharding.LabelOrdinal: strconv.Itoa(int(shard))}
 	b.shardingMetrics.Ordinal.Reset()
@@ -133,6 +293,11 @@ func (b *Builder) WithSharding(shard int32, totalShards int) {
 	b.shardingMetrics.Total.Set(float64(totalShards))
 }
 
+// WithShardingStrategy sets the strategy by which objects are assigned to shards.
+func (b *Builder) WithShardingStrategy(s sharding.Strategy) {
+	b.shardingStrategy = s
+}
+
 // WithContext sets the ctx property of a Builder.
 func (b *Builder) WithContext(ctx context.Context) {
 	b.ctx = ctx
@@ -148,16 +313,178 @@ func (b *Builder) WithVPAClient(c vpaclientset.Interface) {
 	b.vpaClient = c
 }
 
+// WithMetadataClient sets the metadataClient property of a Builder. If set,
+// collectors which only expose metrics derived from the object metadata watch
+// PartialObjectMetadata instead of full objects.
+func (b *Builder) WithMetadataClient(c metadata.Interface) {
+	b.metadataClient = c
+}
+
+// WithDynamicClient sets the dynamicClient property of a Builder so that the
+// collectors of resources without typed clients, e.g. of the Gateway API, can
+// query their objects.
+func (b *Builder) WithDynamicClient(c dynamic.Interface) {
+	b.dynamicClient = c
+}
+
 // WithCustomResourceClients sets the customResourceClients property of a Builder.
 func (b *Builder) WithCustomResourceClients(cs map[string]interface{}) {
 	b.customResourceClients = cs
 }
 
+// WithRESTConfig creates the kube client, the VPA client, the dynamic client
+// and the clients of the custom resource store factories configured before from the given config.
+// All requests of the clients are passed through the given transport wrappers,
+// e.g. to impersonate a user or to add audit headers. It replaces the clients
+// configured before.
+func (b *Builder) WithRESTConfig(cfg *rest.Config, wrappers ...transport.WrapperFunc) error {
+	cfg = rest.CopyConfig(cfg)
+	for _, w := range wrappers {
+		cfg.Wrap(w)
+	}
+
+	kubeClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create kube client: %w", err)
+	}
+	vpaClient, err := vpaclientset.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create VPA client: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	customResourceClients := make(map[string]interface{}, len(b.customResourceFactories))
+	for _, f := range b.customResourceFactories {
+		c, err := f.CreateClient(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create client of custom resource %s: %w", f.Name(), err)
+		}
+		customResourceClients[f.Name()] = c
+	}
+
+	b.kubeClient = kubeClient
+	b.vpaClient = vpaClient
+	b.dynamicClient = dynamicClient
+	b.customResourceClients = customResourceClients
+	return nil
+}
+
+// WithListWatchFuncs replaces the constructors of the ListerWatchers of the
+// given built-in resources, e.g. to list objects from another source than the
+// apiserver.
+func (b *Builder) WithListWatchFuncs(fs map[string]ksmtypes.ListWatchFunc) error {
+	for resource := range fs {
+		if !resourceExists(resource) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", resource, strings.Join(availableResources(), ","))
+		}
+	}
+	b.listWatchFuncs = fs
+	return nil
+}
+
+// WithTweakListOptions sets functions modifying the options of all list and
+// watch requests per resource, e.g. to add a label selector.
+func (b *Builder) WithTweakListOptions(fs map[string]func(*metav1.ListOptions)) error {
+	for resource := range fs {
+		if !resourceExists(resource) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", resource, strings.Join(availableResources(), ","))
+		}
+	}
+	b.tweakListOptions = fs
+	return nil
+}
+
 // WithUsingAPIServerCache configures whether using APIServer cache or not.
 func (b *Builder) WithUsingAPIServerCache(u bool) {
 	b.useAPIServerCache = u
 }
 
+// WithUsingWatchList configures whether the initial list of the reflectors is
+// streamed via watch list requests or not.
+func (b *Builder) WithUsingWatchList(u bool) {
+	b.useWatchList = u
+}
+
+// WithKeepFamilies configures whether the stores keep the metric families of
+// each object in addition to their text exposition format, e.g. to export
+// them via OTLP.
+func (b *Builder) WithKeepFamilies(k bool) {
+	b.keepFamilies = k
+}
+
+// WithListPageSize sets the number of objects requested per page of the lists
+// of the reflectors. The default page size of client-go is used if it is 0.
+func (b *Builder) WithListPageSize(n int64) {
+	b.listPageSize = n
+}
+
+// WithStartupLimits limits the number of concurrent initial lists of the
+// reflectors and delays each of them by a random duration of up to the given
+// jitter. The number of concurrent initial lists is not limited if it is 0.
+func (b *Builder) WithStartupLimits(concurrency int, jitter time.Duration) {
+	b.startupLimiter = watch.NewStartupLimiter(concurrency, jitter)
+}
+
+// WithWatchBackoff configures the backoff of the reflectors between failed
+// list and watch attempts.
+func (b *Builder) WithWatchBackoff(backoff watch.Backoff) {
+	b.watchBackoff = backoff
+}
+
+// WithUTF8LabelNames configures whether label names generated from
+// Kubernetes label and annotation keys which are not valid without UTF-8
+// support, e.g. label_app.kubernetes.io/name, are kept verbatim instead of
+// being sanitized. The setting applies to all Builders of the process and has
+// to be set before Build is called.
+func (b *Builder) WithUTF8LabelNames(enabled bool) {
+	utf8LabelNames = enabled
+}
+
+// WithMaxLabelValueLength configures the maximum length of label values
+// sourced from Kubernetes labels, annotations and custom resource fields.
+// Longer values are truncated and suffixed with their hash. Label values are
+// not truncated if it is 0. The setting applies to all Builders of the
+// process and has to be set before Build is called.
+func (b *Builder) WithMaxLabelValueLength(n int) {
+	metric.SetMaxLabelValueLength(n)
+}
+
+// WithPodStatusReasons configures additional pod status reasons which are
+// exposed by kube_pod_status_reason besides the well-known ones.
+func (b *Builder) WithPodStatusReasons(reasons []string) {
+	b.podStatusReasons = reasons
+}
+
+// WithPodWorkloadResolution configures whether kube_pod_workload_info is
+// exposed. It resolves the workload of each Pod by following the controllers
+// of its owners, which requires watching the metadata of ReplicaSets and Jobs
+// with the metadataClient.
+func (b *Builder) WithPodWorkloadResolution(enabled bool) {
+	b.resolvePodWorkloads = enabled
+}
+
+// WithStaleObjectGC configures the interval of listing the metadata of the
+// objects of each store to prune objects which no longer exist, e.g. as their
+// delete watch event was missed. An interval of 0 disables it. It requires
+// the metadata client.
+func (b *Builder) WithStaleObjectGC(interval time.Duration) {
+	b.staleObjectGCInterval = interval
+}
+
+// WithWatchHealth configures the Health tracking whether the list and watch
+// requests of all reflectors succeed.
+func (b *Builder) WithWatchHealth(h *watch.Health) {
+	b.watchHealth = h
+}
+
+// WithTracer configures the tracer of the list and watch requests of all
+// reflectors.
+func (b *Builder) WithTracer(t trace.Tracer) {
+	b.tracer = t
+}
+
 // WithFamilyGeneratorFilter configures the family generator filter which decides which
 // metrics are to be exposed by the store build by the Builder.
 func (b *Builder) WithFamilyGeneratorFilter(l generator.FamilyGeneratorFilter) {
@@ -186,18 +513,20 @@ func (b *Builder) DefaultGenerateCustomResourceStoresFunc() ksmtypes.BuildCustom
 
 // WithCustomResourceStoreFactories returns configures a custom resource stores factory
 func (b *Builder) WithCustomResourceStoreFactories(fs ...customresource.RegistryFactory) {
+	b.customResourceFactories = append(b.customResourceFactories, fs...)
 	for i := range fs {
 		f := fs[i]
 		if _, ok := availableStores[f.Name()]; ok {
 			klog.InfoS("The internal resource store already exists and is overridden by a custom resource store with the same name, please make sure it meets your expectation", "registryName", f.Name())
 		}
-		availableStores[f.Name()] = func(b *Builder) []cache.Store {
+		availableStores[f.Name()] = func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
 			return b.buildCustomResourceStoresFunc(
 				f.Name(),
 				f.MetricFamilyGenerators(b.allowAnnotationsList[f.Name()], b.allowLabelsList[f.Name()]),
 				f.ExpectedType(),
 				f.ListWatch,
 				b.useAPIServerCache,
+				opts,
 			)
 		}
 	}
@@ -212,25 +541,84 @@ func (b *Builder) WithAllowAnnotations(annotations map[string][]string) {
 
 // WithAllowLabels configures which labels can be returned for metrics
 func (b *Builder) WithAllowLabels(labels map[string][]string) error {
+	allowLabelsList := labels
 	if len(labels) > 0 {
 		for label := range labels {
 			if !resourceExists(label) && label != "*" {
 				return fmt.Errorf("resource %s does not exist. Available resources: %s", label, strings.Join(availableResources(), ","))
 			}
 		}
-		b.allowLabelsList = labels
 		// "*" takes precedence over other specifications
 		if allowedLabels, ok := labels["*"]; ok {
 			m := make(map[string][]string)
 			for _, resource := range b.enabledResources {
 				m[resource] = allowedLabels
 			}
-			b.allowLabelsList = m
+			allowLabelsList = m
 		}
 	}
+	if b.recommendedLabels {
+		allowLabelsList = withRecommendedLabels(b.enabledResources, allowLabelsList)
+	}
+	if len(labels) > 0 || b.recommendedLabels {
+		b.allowLabelsList = allowLabelsList
+	}
 	return nil
 }
 
+// recommendedLabelKeys are the keys of the recommended labels of Kubernetes
+// objects, see
+// https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/.
+var recommendedLabelKeys = []string{
+	"app.kubernetes.io/name",
+	"app.kubernetes.io/instance",
+	"app.kubernetes.io/version",
+	"app.kubernetes.io/component",
+	"app.kubernetes.io/part-of",
+	"app.kubernetes.io/managed-by",
+}
+
+// WithRecommendedLabels configures whether the recommended labels, e.g.
+// app.kubernetes.io/name, are allowed for the metrics of all enabled
+// resources in addition to the labels passed to WithAllowLabels. It has to be
+// called before WithAllowLabels.
+func (b *Builder) WithRecommendedLabels(enabled bool) {
+	b.recommendedLabels = enabled
+}
+
+// withRecommendedLabels returns a copy of the given labels allowlist which
+// additionally allows the recommended labels for each of the given resources.
+func withRecommendedLabels(resources []string, labels map[string][]string) map[string][]string {
+	m := make(map[string][]string, len(labels)+len(resources))
+	for resource, allowedLabels := range labels {
+		m[resource] = allowedLabels
+	}
+	for _, resource := range resources {
+		allowedLabels := m[resource]
+		if len(allowedLabels) > 0 && allowedLabels[0] == options.LabelWildcard {
+			continue
+		}
+		allowed := make(map[string]struct{}, len(allowedLabels))
+		for _, l := range allowedLabels {
+			allowed[l] = struct{}{}
+		}
+		allowedLabels = append([]string{}, allowedLabels...)
+		for _, l := range recommendedLabelKeys {
+			if _, ok := allowed[l]; !ok {
+				allowedLabels = append(allowedLabels, l)
+			}
+		}
+		m[resource] = allowedLabels
+	}
+	return m
+}
+
+// WithLabelsDenylist configures the labels which are dropped from the metrics
+// of each metric family. Labels listed for "*" are dropped from all families.
+func (b *Builder) WithLabelsDenylist(l map[string][]string) {
+	b.labelsDenylist = l
+}
+
 // Build initializes and registers all enabled stores.
 // It returns metrics writers which can be used to write out
 // metrics from the stores.
@@ -245,11 +633,20 @@ func (b *Builder) Build() metricsstore.MetricsWriterList {
 	for _, c := range b.enabledResources {
 		constructor, ok := availableStores[c]
 		if ok {
-			stores := cacheStoresToMetricStores(constructor(b))
+			stores := cacheStoresToMetricStores(constructor(b, b.storeOptions(c)))
 			activeStoreNames = append(activeStoreNames, c)
-			metricsWriters = append(metricsWriters, metricsstore.NewMetricsWriter(stores...))
+			mw := metricsstore.NewResourceMetricsWriter(c, stores...)
+			if b.topKResources[c] > 0 || len(b.topKFamilies) > 0 {
+				mw.TopK(b.topKResources[c], b.topKFamilies)
+			}
+			metricsWriters = append(metricsWriters, mw)
 		}
 	}
+	if stores := b.buildObjectCountStores(); len(stores) > 0 {
+		mw := metricsstore.NewResourceMetricsWriter(objectCountResource, cacheStoresToMetricStores(stores)...)
+		activeStoreNames = append(activeStoreNames, objectCountResource)
+		metricsWriters = append(metricsWriters, mw)
+	}
 
 	klog.InfoS("Active resources", "activeStoreNames", strings.Join(activeStoreNames, ","))
 
@@ -270,54 +667,232 @@ func (b *Builder) BuildStores() [][]cache.Store {
 	for _, c := range b.enabledResources {
 		constructor, ok := availableStores[c]
 		if ok {
-			stores := constructor(b)
+			stores := constructor(b, b.storeOptions(c))
 			activeStoreNames = append(activeStoreNames, c)
 			allStores = append(allStores, stores)
 		}
 	}
+	if stores := b.buildObjectCountStores(); len(stores) > 0 {
+		activeStoreNames = append(activeStoreNames, objectCountResource)
+		allStores = append(allStores, stores)
+	}
 
 	klog.InfoS("Active resources", "activeStoreNames", strings.Join(activeStoreNames, ","))
 
 	return allStores
 }
 
-var availableStores = map[string]func(f *Builder) []cache.Store{
-	"certificatesigningrequests":      func(b *Builder) []cache.Store { return b.buildCsrStores() },
-	"clusterroles":                    func(b *Builder) []cache.Store { return b.buildClusterRoleStores() },
-	"configmaps":                      func(b *Builder) []cache.Store { return b.buildConfigMapStores() },
-	"clusterrolebindings":             func(b *Builder) []cache.Store { return b.buildClusterRoleBindingStores() },
-	"cronjobs":                        func(b *Builder) []cache.Store { return b.buildCronJobStores() },
-	"daemonsets":                      func(b *Builder) []cache.Store { return b.buildDaemonSetStores() },
-	"deployments":                     func(b *Builder) []cache.Store { return b.buildDeploymentStores() },
-	"endpoints":                       func(b *Builder) []cache.Store { return b.buildEndpointsStores() },
-	"endpointslices":                  func(b *Builder) []cache.Store { return b.buildEndpointSlicesStores() },
-	"horizontalpodautoscalers":        func(b *Builder) []cache.Store { return b.buildHPAStores() },
-	"ingresses":                       func(b *Builder) []cache.Store { return b.buildIngressStores() },
-	"ingressclasses":                  func(b *Builder) []cache.Store { return b.buildIngressClassStores() },
-	"jobs":                            func(b *Builder) []cache.Store { return b.buildJobStores() },
-	"leases":                          func(b *Builder) []cache.Store { return b.buildLeasesStores() },
-	"limitranges":                     func(b *Builder) []cache.Store { return b.buildLimitRangeStores() },
-	"mutatingwebhookconfigurations":   func(b *Builder) []cache.Store { return b.buildMutatingWebhookConfigurationStores() },
-	"namespaces":                      func(b *Builder) []cache.Store { return b.buildNamespaceStores() },
-	"networkpolicies":                 func(b *Builder) []cache.Store { return b.buildNetworkPolicyStores() },
-	"nodes":                           func(b *Builder) []cache.Store { return b.buildNodeStores() },
-	"persistentvolumeclaims":          func(b *Builder) []cache.Store { return b.buildPersistentVolumeClaimStores() },
-	"persistentvolumes":               func(b *Builder) []cache.Store { return b.buildPersistentVolumeStores() },
-	"poddisruptionbudgets":            func(b *Builder) []cache.Store { return b.buildPodDisruptionBudgetStores() },
-	"pods":                            func(b *Builder) []cache.Store { return b.buildPodStores() },
-	"replicasets":                     func(b *Builder) []cache.Store { return b.buildReplicaSetStores() },
-	"replicationcontrollers":          func(b *Builder) []cache.Store { return b.buildReplicationControllerStores() },
-	"resourcequotas":                  func(b *Builder) []cache.Store { return b.buildResourceQuotaStores() },
-	"roles":                           func(b *Builder) []cache.Store { return b.buildRoleStores() },
-	"rolebindings":                    func(b *Builder) []cache.Store { return b.buildRoleBindingStores() },
-	"secrets":                         func(b *Builder) []cache.Store { return b.buildSecretStores() },
-	"serviceaccounts":                 func(b *Builder) []cache.Store { return b.buildServiceAccountStores() },
-	"services":                        func(b *Builder) []cache.Store { return b.buildServiceStores() },
-	"statefulsets":                    func(b *Builder) []cache.Store { return b.buildStatefulSetStores() },
-	"storageclasses":                  func(b *Builder) []cache.Store { return b.buildStorageClassStores() },
-	"validatingwebhookconfigurations": func(b *Builder) []cache.Store { return b.buildValidatingWebhookConfigurationStores() },
-	"volumeattachments":               func(b *Builder) []cache.Store { return b.buildVolumeAttachmentStores() },
-	"verticalpodautoscalers":          func(b *Builder) []cache.Store { return b.buildVPAStores() },
+// storeOptions returns the options of the stores of a resource. The field
+// selector of the resource is ANDed to the fieldSelector property, and the
+// stores share the object limit of the resource.
+func (b *Builder) storeOptions(resource string) ksmtypes.StoreOptions {
+	opts := ksmtypes.StoreOptions{
+		FieldSelector:    b.fieldSelectorFilter,
+		ListWatchFunc:    b.listWatchFuncs[resource],
+		TweakListOptions: b.tweakListOptions[resource],
+		MetricPrefix:     b.metricPrefixes[resource],
+	}
+	if _, ok := b.lazyResources[resource]; ok {
+		opts.Lazy = true
+	}
+	if max, ok := b.maxObjects[resource]; ok {
+		opts.ObjectLimit = metricsstore.NewObjectLimit(max)
+	}
+	if labels, ok := b.aggregatedResources[resource]; ok {
+		opts.Aggregate = true
+		opts.AggregateBy = labels
+	}
+	if after, ok := b.excludeCompleted[resource]; ok {
+		opts.Expiry = completedExpiry(completedAtFuncs[resource], after)
+	}
+	if selector, ok := b.fieldSelectors[resource]; ok {
+		merged, err := options.MergeTwoFieldSelectors(b.fieldSelectorFilter, selector)
+		if err != nil {
+			// Both selectors were validated before, so this is not expected.
+			klog.ErrorS(err, "Failed to merge field selectors, ignoring the field selector of the resource", "resource", resource)
+		} else {
+			opts.FieldSelector = merged
+		}
+	}
+	return opts
+}
+
+var availableStores = map[string]func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store{
+	"adminnetworkpolicies": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildAdminNetworkPolicyStores(opts)
+	},
+	"baselineadminnetworkpolicies": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildBaselineAdminNetworkPolicyStores(opts)
+	},
+	"certificatesigningrequests": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildCsrStores(opts) },
+	"clusterroles":               func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildClusterRoleStores(opts) },
+	"configmaps":                 func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildConfigMapStores(opts) },
+	"clusterrolebindings": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildClusterRoleBindingStores(opts)
+	},
+	"clustertrustbundles": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildClusterTrustBundleStores(opts)
+	},
+	"cronjobs": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildCronJobStores(opts) },
+	"customresourcedefinitions": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildCustomResourceDefinitionStores(opts)
+	},
+	"daemonsets":               func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildDaemonSetStores(opts) },
+	"deviceclasses":            func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildDeviceClassStores(opts) },
+	"deployments":              func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildDeploymentStores(opts) },
+	"endpoints":                func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildEndpointsStores(opts) },
+	"endpointslices":           func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildEndpointSlicesStores(opts) },
+	"events":                   func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildEventStores(opts) },
+	"flowschemas":              func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildFlowSchemaStores(opts) },
+	"gatewayclasses":           func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildGatewayClassStores(opts) },
+	"gateways":                 func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildGatewayStores(opts) },
+	"horizontalpodautoscalers": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildHPAStores(opts) },
+	"httproutes":               func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildHTTPRouteStores(opts) },
+	"ingresses":                func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildIngressStores(opts) },
+	"ingressclasses":           func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildIngressClassStores(opts) },
+	"jobs":                     func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildJobStores(opts) },
+	"leases":                   func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildLeasesStores(opts) },
+	"limitranges":              func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildLimitRangeStores(opts) },
+	"mutatingwebhookconfigurations": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildMutatingWebhookConfigurationStores(opts)
+	},
+	"namespaces":      func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildNamespaceStores(opts) },
+	"networkpolicies": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildNetworkPolicyStores(opts) },
+	"nodes":           func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildNodeStores(opts) },
+	"persistentvolumeclaims": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildPersistentVolumeClaimStores(opts)
+	},
+	"persistentvolumes": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildPersistentVolumeStores(opts) },
+	"poddisruptionbudgets": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildPodDisruptionBudgetStores(opts)
+	},
+	"pods": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildPodStores(opts) },
+	"prioritylevelconfigurations": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildPriorityLevelConfigurationStores(opts)
+	},
+	"replicasets": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildReplicaSetStores(opts) },
+	"replicationcontrollers": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildReplicationControllerStores(opts)
+	},
+	"resourceclaims": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildResourceClaimStores(opts) },
+	"resourceclaimtemplates": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildResourceClaimTemplateStores(opts)
+	},
+	"resourcequotas":  func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildResourceQuotaStores(opts) },
+	"resourceslices":  func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildResourceSliceStores(opts) },
+	"roles":           func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildRoleStores(opts) },
+	"runtimeclasses":  func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildRuntimeClassStores(opts) },
+	"rolebindings":    func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildRoleBindingStores(opts) },
+	"secrets":         func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildSecretStores(opts) },
+	"serviceaccounts": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildServiceAccountStores(opts) },
+	"services":        func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildServiceStores(opts) },
+	"statefulsets":    func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildStatefulSetStores(opts) },
+	"storageclasses":  func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildStorageClassStores(opts) },
+	"validatingwebhookconfigurations": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildValidatingWebhookConfigurationStores(opts)
+	},
+	"volumeattachments": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildVolumeAttachmentStores(opts) },
+	"volumeattributesclasses": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store {
+		return b.buildVolumeAttributesClassStores(opts)
+	},
+	"verticalpodautoscalers": func(b *Builder, opts ksmtypes.StoreOptions) []cache.Store { return b.buildVPAStores(opts) },
+}
+
+// resourceGroups are the API groups of the available resources.
+var resourceGroups = map[string]string{
+	"adminnetworkpolicies":            "policy.networking.k8s.io",
+	"baselineadminnetworkpolicies":    "policy.networking.k8s.io",
+	"certificatesigningrequests":      "certificates.k8s.io",
+	"clusterroles":                    "rbac.authorization.k8s.io",
+	"configmaps":                      "",
+	"clusterrolebindings":             "rbac.authorization.k8s.io",
+	"clustertrustbundles":             "certificates.k8s.io",
+	"cronjobs":                        "batch",
+	"customresourcedefinitions":       "apiextensions.k8s.io",
+	"daemonsets":                      "apps",
+	"deviceclasses":                   "resource.k8s.io",
+	"deployments":                     "apps",
+	"endpoints":                       "",
+	"endpointslices":                  "discovery.k8s.io",
+	"events":                          "",
+	"flowschemas":                     "flowcontrol.apiserver.k8s.io",
+	"gatewayclasses":                  "gateway.networking.k8s.io",
+	"gateways":                        "gateway.networking.k8s.io",
+	"horizontalpodautoscalers":        "autoscaling",
+	"httproutes":                      "gateway.networking.k8s.io",
+	"ingresses":                       "networking.k8s.io",
+	"ingressclasses":                  "networking.k8s.io",
+	"jobs":                            "batch",
+	"leases":                          "coordination.k8s.io",
+	"limitranges":                     "",
+	"mutatingwebhookconfigurations":   "admissionregistration.k8s.io",
+	"namespaces":                      "",
+	"networkpolicies":                 "networking.k8s.io",
+	"nodes":                           "",
+	"persistentvolumeclaims":          "",
+	"persistentvolumes":               "",
+	"poddisruptionbudgets":            "policy",
+	"pods":                            "",
+	"prioritylevelconfigurations":     "flowcontrol.apiserver.k8s.io",
+	"replicasets":                     "apps",
+	"replicationcontrollers":          "",
+	"resourceclaims":                  "resource.k8s.io",
+	"resourceclaimtemplates":          "resource.k8s.io",
+	"resourcequotas":                  "",
+	"resourceslices":                  "resource.k8s.io",
+	"roles":                           "rbac.authorization.k8s.io",
+	"runtimeclasses":                  "node.k8s.io",
+	"rolebindings":                    "rbac.authorization.k8s.io",
+	"secrets":                         "",
+	"serviceaccounts":                 "",
+	"services":                        "",
+	"statefulsets":                    "apps",
+	"storageclasses":                  "storage.k8s.io",
+	"validatingwebhookconfigurations": "admissionregistration.k8s.io",
+	"volumeattachments":               "storage.k8s.io",
+	"volumeattributesclasses":         "storage.k8s.io",
+	"verticalpodautoscalers":          "autoscaling.k8s.io",
+}
+
+// clusterScopedResources are the available resources which are not
+// namespaced.
+var clusterScopedResources = map[string]struct{}{
+	"adminnetworkpolicies":            {},
+	"baselineadminnetworkpolicies":    {},
+	"certificatesigningrequests":      {},
+	"clusterroles":                    {},
+	"clusterrolebindings":             {},
+	"clustertrustbundles":             {},
+	"customresourcedefinitions":       {},
+	"deviceclasses":                   {},
+	"flowschemas":                     {},
+	"gatewayclasses":                  {},
+	"ingressclasses":                  {},
+	"mutatingwebhookconfigurations":   {},
+	"namespaces":                      {},
+	"nodes":                           {},
+	"persistentvolumes":               {},
+	"prioritylevelconfigurations":     {},
+	"resourceslices":                  {},
+	"runtimeclasses":                  {},
+	"storageclasses":                  {},
+	"validatingwebhookconfigurations": {},
+	"volumeattachments":               {},
+	"volumeattributesclasses":         {},
+}
+
+// ResourceGroup returns the API group of the given resource, and whether it
+// is an available resource.
+func ResourceGroup(resource string) (string, bool) {
+	group, ok := resourceGroups[resource]
+	return group, ok
+}
+
+// ResourceNamespaced returns whether the given available resource is
+// namespaced.
+func ResourceNamespaced(resource string) bool {
+	_, ok := clusterScopedResources[resource]
+	return !ok
 }
 
 func resourceExists(name string) bool {
@@ -333,148 +908,250 @@ func availableResources() []string {
 	return c
 }
 
-func (b *Builder) buildConfigMapStores() []cache.Store {
-	return b.buildStoresFunc(configMapMetricFamilies(b.allowAnnotationsList["configmaps"], b.allowLabelsList["configmaps"]), &v1.ConfigMap{}, createConfigMapListWatch, b.useAPIServerCache)
+func (b *Builder) buildConfigMapStores(opts ksmtypes.StoreOptions) []cache.Store {
+	if b.metadataClient != nil {
+		return b.buildStoresFunc(configMapMetricFamilies(b.allowAnnotationsList["configmaps"], b.allowLabelsList["configmaps"]), metadataExpectedType(v1.SchemeGroupVersion.WithKind("ConfigMap")), createMetadataListWatchFunc(b.metadataClient, v1.SchemeGroupVersion.WithResource("configmaps")), b.useAPIServerCache, opts)
+	}
+	return b.buildStoresFunc(configMapMetricFamilies(b.allowAnnotationsList["configmaps"], b.allowLabelsList["configmaps"]), &v1.ConfigMap{}, createConfigMapListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildCronJobStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(cronJobMetricFamilies(b.allowAnnotationsList["cronjobs"], b.allowLabelsList["cronjobs"]), &batchv1.CronJob{}, createCronJobListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildDaemonSetStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(daemonSetMetricFamilies(b.allowAnnotationsList["daemonsets"], b.allowLabelsList["daemonsets"]), &appsv1.DaemonSet{}, createDaemonSetListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildDeploymentStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(deploymentMetricFamilies(b.allowAnnotationsList["deployments"], b.allowLabelsList["deployments"]), &appsv1.Deployment{}, createDeploymentListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildCronJobStores() []cache.Store {
-	return b.buildStoresFunc(cronJobMetricFamilies(b.allowAnnotationsList["cronjobs"], b.allowLabelsList["cronjobs"]), &batchv1.CronJob{}, createCronJobListWatch, b.useAPIServerCache)
+func (b *Builder) buildEndpointsStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(endpointMetricFamilies(b.allowAnnotationsList["endpoints"], b.allowLabelsList["endpoints"]), &v1.Endpoints{}, createEndpointsListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildDaemonSetStores() []cache.Store {
-	return b.buildStoresFunc(daemonSetMetricFamilies(b.allowAnnotationsList["daemonsets"], b.allowLabelsList["daemonsets"]), &appsv1.DaemonSet{}, createDaemonSetListWatch, b.useAPIServerCache)
+func (b *Builder) buildEndpointSlicesStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(endpointSliceMetricFamilies(b.allowAnnotationsList["endpointslices"], b.allowLabelsList["endpointslices"]), &discoveryv1.EndpointSlice{}, createEndpointSliceListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildDeploymentStores() []cache.Store {
-	return b.buildStoresFunc(deploymentMetricFamilies(b.allowAnnotationsList["deployments"], b.allowLabelsList["deployments"]), &appsv1.Deployment{}, createDeploymentListWatch, b.useAPIServerCache)
+func (b *Builder) buildHPAStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(hpaMetricFamilies(b.allowAnnotationsList["horizontalpodautoscalers"], b.allowLabelsList["horizontalpodautoscalers"]), &autoscaling.HorizontalPodAutoscaler{}, createHPAListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildEndpointsStores() []cache.Store {
-	return b.buildStoresFunc(endpointMetricFamilies(b.allowAnnotationsList["endpoints"], b.allowLabelsList["endpoints"]), &v1.Endpoints{}, createEndpointsListWatch, b.useAPIServerCache)
+func (b *Builder) buildIngressStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(ingressMetricFamilies(b.allowAnnotationsList["ingresses"], b.allowLabelsList["ingresses"]), &networkingv1.Ingress{}, createIngressListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildEndpointSlicesStores() []cache.Store {
-	return b.buildStoresFunc(endpointSliceMetricFamilies(b.allowAnnotationsList["endpointslices"], b.allowLabelsList["endpointslices"]), &discoveryv1.EndpointSlice{}, createEndpointSliceListWatch, b.useAPIServerCache)
+func (b *Builder) buildJobStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(jobMetricFamilies(b.allowAnnotationsList["jobs"], b.allowLabelsList["jobs"]), &batchv1.Job{}, createJobListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildHPAStores() []cache.Store {
-	return b.buildStoresFunc(hpaMetricFamilies(b.allowAnnotationsList["horizontalpodautoscalers"], b.allowLabelsList["horizontalpodautoscalers"]), &autoscaling.HorizontalPodAutoscaler{}, createHPAListWatch, b.useAPIServerCache)
+func (b *Builder) buildLimitRangeStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(limitRangeMetricFamilies, &v1.LimitRange{}, createLimitRangeListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildIngressStores() []cache.Store {
-	return b.buildStoresFunc(ingressMetricFamilies(b.allowAnnotationsList["ingresses"], b.allowLabelsList["ingresses"]), &networkingv1.Ingress{}, createIngressListWatch, b.useAPIServerCache)
+func (b *Builder) buildMutatingWebhookConfigurationStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(mutatingWebhookConfigurationMetricFamilies, &admissionregistrationv1.MutatingWebhookConfiguration{}, createMutatingWebhookConfigurationListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildJobStores() []cache.Store {
-	return b.buildStoresFunc(jobMetricFamilies(b.allowAnnotationsList["jobs"], b.allowLabelsList["jobs"]), &batchv1.Job{}, createJobListWatch, b.useAPIServerCache)
+func (b *Builder) buildNamespaceStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(namespaceMetricFamilies(b.allowAnnotationsList["namespaces"], b.allowLabelsList["namespaces"]), &v1.Namespace{}, createNamespaceListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildLimitRangeStores() []cache.Store {
-	return b.buildStoresFunc(limitRangeMetricFamilies, &v1.LimitRange{}, createLimitRangeListWatch, b.useAPIServerCache)
+func (b *Builder) buildNetworkPolicyStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(networkPolicyMetricFamilies(b.allowAnnotationsList["networkpolicies"], b.allowLabelsList["networkpolicies"]), &networkingv1.NetworkPolicy{}, createNetworkPolicyListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildNodeStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(nodeMetricFamilies(b.allowAnnotationsList["nodes"], b.allowLabelsList["nodes"]), &v1.Node{}, createNodeListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildPersistentVolumeClaimStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(persistentVolumeClaimMetricFamilies(b.allowAnnotationsList["persistentvolumeclaims"], b.allowLabelsList["persistentvolumeclaims"]), &v1.PersistentVolumeClaim{}, createPersistentVolumeClaimListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildPersistentVolumeStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(persistentVolumeMetricFamilies(b.allowAnnotationsList["persistentvolumes"], b.allowLabelsList["persistentvolumes"]), &v1.PersistentVolume{}, createPersistentVolumeListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildPodDisruptionBudgetStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(podDisruptionBudgetMetricFamilies(b.allowAnnotationsList["poddisruptionbudgets"], b.allowLabelsList["poddisruptionbudgets"]), &policyv1.PodDisruptionBudget{}, createPodDisruptionBudgetListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildReplicaSetStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(replicaSetMetricFamilies(b.allowAnnotationsList["replicasets"], b.allowLabelsList["replicasets"]), &appsv1.ReplicaSet{}, createReplicaSetListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildReplicationControllerStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(replicationControllerMetricFamilies, &v1.ReplicationController{}, createReplicationControllerListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildResourceQuotaStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(resourceQuotaMetricFamilies, &v1.ResourceQuota{}, createResourceQuotaListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildSecretStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(secretMetricFamilies(b.allowAnnotationsList["secrets"], b.allowLabelsList["secrets"]), &v1.Secret{}, createSecretListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildServiceAccountStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(serviceAccountMetricFamilies(b.allowAnnotationsList["serviceaccounts"], b.allowLabelsList["serviceaccounts"]), &v1.ServiceAccount{}, createServiceAccountListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildServiceStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(serviceMetricFamilies(b.allowAnnotationsList["services"], b.allowLabelsList["services"]), &v1.Service{}, createServiceListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildStatefulSetStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(statefulSetMetricFamilies(b.allowAnnotationsList["statefulsets"], b.allowLabelsList["statefulsets"]), &appsv1.StatefulSet{}, createStatefulSetListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildStorageClassStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(storageClassMetricFamilies(b.allowAnnotationsList["storageclasses"], b.allowLabelsList["storageclasses"]), &storagev1.StorageClass{}, createStorageClassListWatch, b.useAPIServerCache, opts)
+}
+
+func (b *Builder) buildPodStores(opts ksmtypes.StoreOptions) []cache.Store {
+	metricFamilies := podMetricFamilies(b.allowAnnotationsList["pods"], b.allowLabelsList["pods"], b.podStatusReasons)
+	if b.resolvePodWorkloads {
+		owners := b.startOwnerIndex()
+		metricFamilies = append(metricFamilies, createPodWorkloadInfoFamilyGenerator(owners))
+		listWatchFunc := ksmtypes.ListWatchFunc(createPodListWatch)
+		if opts.ListWatchFunc != nil {
+			listWatchFunc = opts.ListWatchFunc
+		}
+		opts.ListWatchFunc = func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+			return owners.waitForSync(b.ctx, listWatchFunc(kubeClient, ns, fieldSelector))
+		}
+	}
+	return b.buildStoresFunc(metricFamilies, &v1.Pod{}, createPodListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildMutatingWebhookConfigurationStores() []cache.Store {
-	return b.buildStoresFunc(mutatingWebhookConfigurationMetricFamilies, &admissionregistrationv1.MutatingWebhookConfiguration{}, createMutatingWebhookConfigurationListWatch, b.useAPIServerCache)
+// startOwnerIndex starts the reflectors of the index of the controllers of
+// the owners of Pods. Unlike the reflectors of the stores, they are not
+// sharded, as the owners of the Pods of a shard may be in any shard.
+func (b *Builder) startOwnerIndex() *ownerIndex {
+	if b.metadataClient == nil {
+		klog.InfoS("Metadata client is not set, kube_pod_workload_info only exposes the direct controllers of Pods")
+		return newOwnerIndex(0)
+	}
+	namespaces := []string{v1.NamespaceAll}
+	if !b.namespaces.IsAllNamespaces() {
+		namespaces = b.namespaces
+	}
+	owners := newOwnerIndex(len(ownerIndexResources) * len(namespaces))
+	for _, r := range ownerIndexResources {
+		expectedType := metadataExpectedType(r.GroupVersion().WithKind(r.kind))
+		resource := reflectorResource(expectedType)
+		for _, ns := range namespaces {
+			listWatcher := createMetadataListWatchFunc(b.metadataClient, r.GroupVersionResource)(b.kubeClient, ns, "")
+			listWatcher = watch.NewInstrumentedListerWatcherForGVR(listWatcher, b.listWatchMetrics, resource, r.GroupVersionResource, b.useAPIServerCache, b.tracer)
+			reflector := cache.NewReflector(listWatcher, expectedType, owners.store(r.GroupVersion().WithKind(r.kind).GroupKind(), ns), 0)
+			reflector.WatchListPageSize = b.listPageSize
+			go b.watchBackoff.RunReflector(reflector, b.ctx.Done())
+		}
+	}
+	return owners
 }
 
-func (b *Builder) buildNamespaceStores() []cache.Store {
-	return b.buildStoresFunc(namespaceMetricFamilies(b.allowAnnotationsList["namespaces"], b.allowLabelsList["namespaces"]), &v1.Namespace{}, createNamespaceListWatch, b.useAPIServerCache)
+func (b *Builder) buildCsrStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(csrMetricFamilies(b.allowAnnotationsList["certificatesigningrequests"], b.allowLabelsList["certificatesigningrequests"]), &certv1.CertificateSigningRequest{}, createCSRListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildNetworkPolicyStores() []cache.Store {
-	return b.buildStoresFunc(networkPolicyMetricFamilies(b.allowAnnotationsList["networkpolicies"], b.allowLabelsList["networkpolicies"]), &networkingv1.NetworkPolicy{}, createNetworkPolicyListWatch, b.useAPIServerCache)
+func (b *Builder) buildValidatingWebhookConfigurationStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(validatingWebhookConfigurationMetricFamilies, &admissionregistrationv1.ValidatingWebhookConfiguration{}, createValidatingWebhookConfigurationListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildNodeStores() []cache.Store {
-	return b.buildStoresFunc(nodeMetricFamilies(b.allowAnnotationsList["nodes"], b.allowLabelsList["nodes"]), &v1.Node{}, createNodeListWatch, b.useAPIServerCache)
+func (b *Builder) buildVolumeAttachmentStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(volumeAttachmentMetricFamilies, &storagev1.VolumeAttachment{}, createVolumeAttachmentListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildPersistentVolumeClaimStores() []cache.Store {
-	return b.buildStoresFunc(persistentVolumeClaimMetricFamilies(b.allowAnnotationsList["persistentvolumeclaims"], b.allowLabelsList["persistentvolumeclaims"]), &v1.PersistentVolumeClaim{}, createPersistentVolumeClaimListWatch, b.useAPIServerCache)
+func (b *Builder) buildVPAStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(vpaMetricFamilies(b.allowAnnotationsList["verticalpodautoscalers"], b.allowLabelsList["verticalpodautoscalers"]), &vpaautoscaling.VerticalPodAutoscaler{}, createVPAListWatchFunc(b.vpaClient), b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildPersistentVolumeStores() []cache.Store {
-	return b.buildStoresFunc(persistentVolumeMetricFamilies(b.allowAnnotationsList["persistentvolumes"], b.allowLabelsList["persistentvolumes"]), &v1.PersistentVolume{}, createPersistentVolumeListWatch, b.useAPIServerCache)
+func (b *Builder) buildFlowSchemaStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(flowSchemaMetricFamilies(b.allowAnnotationsList["flowschemas"], b.allowLabelsList["flowschemas"]), &flowcontrolv1.FlowSchema{}, createFlowSchemaListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildPodDisruptionBudgetStores() []cache.Store {
-	return b.buildStoresFunc(podDisruptionBudgetMetricFamilies(b.allowAnnotationsList["poddisruptionbudgets"], b.allowLabelsList["poddisruptionbudgets"]), &policyv1.PodDisruptionBudget{}, createPodDisruptionBudgetListWatch, b.useAPIServerCache)
+func (b *Builder) buildPriorityLevelConfigurationStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(priorityLevelConfigurationMetricFamilies(b.allowAnnotationsList["prioritylevelconfigurations"], b.allowLabelsList["prioritylevelconfigurations"]), &flowcontrolv1.PriorityLevelConfiguration{}, createPriorityLevelConfigurationListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildReplicaSetStores() []cache.Store {
-	return b.buildStoresFunc(replicaSetMetricFamilies(b.allowAnnotationsList["replicasets"], b.allowLabelsList["replicasets"]), &appsv1.ReplicaSet{}, createReplicaSetListWatch, b.useAPIServerCache)
+func (b *Builder) buildGatewayStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(gatewayMetricFamilies(b.allowAnnotationsList["gateways"], b.allowLabelsList["gateways"]), unstructuredExpectedType(gatewayAPIGroupVersion.WithKind("Gateway")), createUnstructuredListWatchFunc(b.dynamicClient, gatewayAPIGroupVersion.WithResource("gateways")), b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildReplicationControllerStores() []cache.Store {
-	return b.buildStoresFunc(replicationControllerMetricFamilies, &v1.ReplicationController{}, createReplicationControllerListWatch, b.useAPIServerCache)
+func (b *Builder) buildGatewayClassStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(gatewayClassMetricFamilies(b.allowAnnotationsList["gatewayclasses"], b.allowLabelsList["gatewayclasses"]), unstructuredExpectedType(gatewayAPIGroupVersion.WithKind("GatewayClass")), createUnstructuredListWatchFunc(b.dynamicClient, gatewayAPIGroupVersion.WithResource("gatewayclasses")), b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildResourceQuotaStores() []cache.Store {
-	return b.buildStoresFunc(resourceQuotaMetricFamilies, &v1.ResourceQuota{}, createResourceQuotaListWatch, b.useAPIServerCache)
+func (b *Builder) buildHTTPRouteStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(httpRouteMetricFamilies(b.allowAnnotationsList["httproutes"], b.allowLabelsList["httproutes"]), unstructuredExpectedType(gatewayAPIGroupVersion.WithKind("HTTPRoute")), createUnstructuredListWatchFunc(b.dynamicClient, gatewayAPIGroupVersion.WithResource("httproutes")), b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildSecretStores() []cache.Store {
-	return b.buildStoresFunc(secretMetricFamilies(b.allowAnnotationsList["secrets"], b.allowLabelsList["secrets"]), &v1.Secret{}, createSecretListWatch, b.useAPIServerCache)
+func (b *Builder) buildAdminNetworkPolicyStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(adminNetworkPolicyMetricFamilies(b.allowAnnotationsList["adminnetworkpolicies"], b.allowLabelsList["adminnetworkpolicies"]), unstructuredExpectedType(networkPolicyAPIGroupVersion.WithKind("AdminNetworkPolicy")), createUnstructuredListWatchFunc(b.dynamicClient, networkPolicyAPIGroupVersion.WithResource("adminnetworkpolicies")), b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildServiceAccountStores() []cache.Store {
-	return b.buildStoresFunc(serviceAccountMetricFamilies(b.allowAnnotationsList["serviceaccounts"], b.allowLabelsList["serviceaccounts"]), &v1.ServiceAccount{}, createServiceAccountListWatch, b.useAPIServerCache)
+func (b *Builder) buildBaselineAdminNetworkPolicyStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(baselineAdminNetworkPolicyMetricFamilies(b.allowAnnotationsList["baselineadminnetworkpolicies"], b.allowLabelsList["baselineadminnetworkpolicies"]), unstructuredExpectedType(networkPolicyAPIGroupVersion.WithKind("BaselineAdminNetworkPolicy")), createUnstructuredListWatchFunc(b.dynamicClient, networkPolicyAPIGroupVersion.WithResource("baselineadminnetworkpolicies")), b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildServiceStores() []cache.Store {
-	return b.buildStoresFunc(serviceMetricFamilies(b.allowAnnotationsList["services"], b.allowLabelsList["services"]), &v1.Service{}, createServiceListWatch, b.useAPIServerCache)
+func (b *Builder) buildCustomResourceDefinitionStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(customResourceDefinitionMetricFamilies(b.allowAnnotationsList["customresourcedefinitions"], b.allowLabelsList["customresourcedefinitions"]), unstructuredExpectedType(apiextensionsGroupVersion.WithKind("CustomResourceDefinition")), createUnstructuredListWatchFunc(b.dynamicClient, apiextensionsGroupVersion.WithResource("customresourcedefinitions")), b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildStatefulSetStores() []cache.Store {
-	return b.buildStoresFunc(statefulSetMetricFamilies(b.allowAnnotationsList["statefulsets"], b.allowLabelsList["statefulsets"]), &appsv1.StatefulSet{}, createStatefulSetListWatch, b.useAPIServerCache)
+func (b *Builder) buildDeviceClassStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(deviceClassMetricFamilies(b.allowAnnotationsList["deviceclasses"], b.allowLabelsList["deviceclasses"]), &resourcev1.DeviceClass{}, createDeviceClassListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildStorageClassStores() []cache.Store {
-	return b.buildStoresFunc(storageClassMetricFamilies(b.allowAnnotationsList["storageclasses"], b.allowLabelsList["storageclasses"]), &storagev1.StorageClass{}, createStorageClassListWatch, b.useAPIServerCache)
+func (b *Builder) buildResourceClaimStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(resourceClaimMetricFamilies(b.allowAnnotationsList["resourceclaims"], b.allowLabelsList["resourceclaims"]), &resourcev1.ResourceClaim{}, createResourceClaimListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildPodStores() []cache.Store {
-	return b.buildStoresFunc(podMetricFamilies(b.allowAnnotationsList["pods"], b.allowLabelsList["pods"]), &v1.Pod{}, createPodListWatch, b.useAPIServerCache)
+func (b *Builder) buildResourceClaimTemplateStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(resourceClaimTemplateMetricFamilies(b.allowAnnotationsList["resourceclaimtemplates"], b.allowLabelsList["resourceclaimtemplates"]), &resourcev1.ResourceClaimTemplate{}, createResourceClaimTemplateListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildCsrStores() []cache.Store {
-	return b.buildStoresFunc(csrMetricFamilies(b.allowAnnotationsList["certificatesigningrequests"], b.allowLabelsList["certificatesigningrequests"]), &certv1.CertificateSigningRequest{}, createCSRListWatch, b.useAPIServerCache)
+func (b *Builder) buildResourceSliceStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(resourceSliceMetricFamilies(b.allowAnnotationsList["resourceslices"], b.allowLabelsList["resourceslices"]), &resourcev1.ResourceSlice{}, createResourceSliceListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildValidatingWebhookConfigurationStores() []cache.Store {
-	return b.buildStoresFunc(validatingWebhookConfigurationMetricFamilies, &admissionregistrationv1.ValidatingWebhookConfiguration{}, createValidatingWebhookConfigurationListWatch, b.useAPIServerCache)
+func (b *Builder) buildVolumeAttributesClassStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(volumeAttributesClassMetricFamilies(b.allowAnnotationsList["volumeattributesclasses"], b.allowLabelsList["volumeattributesclasses"]), &storagev1.VolumeAttributesClass{}, createVolumeAttributesClassListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildVolumeAttachmentStores() []cache.Store {
-	return b.buildStoresFunc(volumeAttachmentMetricFamilies, &storagev1.VolumeAttachment{}, createVolumeAttachmentListWatch, b.useAPIServerCache)
+func (b *Builder) buildLeasesStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(leaseMetricFamilies, &coordinationv1.Lease{}, createLeaseListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildVPAStores() []cache.Store {
-	return b.buildStoresFunc(vpaMetricFamilies(b.allowAnnotationsList["verticalpodautoscalers"], b.allowLabelsList["verticalpodautoscalers"]), &vpaautoscaling.VerticalPodAutoscaler{}, createVPAListWatchFunc(b.vpaClient), b.useAPIServerCache)
+func (b *Builder) buildClusterRoleStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(clusterRoleMetricFamilies(b.allowAnnotationsList["clusterroles"], b.allowLabelsList["clusterroles"]), &rbacv1.ClusterRole{}, createClusterRoleListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildLeasesStores() []cache.Store {
-	return b.buildStoresFunc(leaseMetricFamilies, &coordinationv1.Lease{}, createLeaseListWatch, b.useAPIServerCache)
+func (b *Builder) buildRoleStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(roleMetricFamilies(b.allowAnnotationsList["roles"], b.allowLabelsList["roles"]), &rbacv1.Role{}, createRoleListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildClusterRoleStores() []cache.Store {
-	return b.buildStoresFunc(clusterRoleMetricFamilies(b.allowAnnotationsList["clusterroles"], b.allowLabelsList["clusterroles"]), &rbacv1.ClusterRole{}, createClusterRoleListWatch, b.useAPIServerCache)
+func (b *Builder) buildClusterRoleBindingStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(clusterRoleBindingMetricFamilies(b.allowAnnotationsList["clusterrolebindings"], b.allowLabelsList["clusterrolebindings"]), &rbacv1.ClusterRoleBinding{}, createClusterRoleBindingListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildRoleStores() []cache.Store {
-	return b.buildStoresFunc(roleMetricFamilies(b.allowAnnotationsList["roles"], b.allowLabelsList["roles"]), &rbacv1.Role{}, createRoleListWatch, b.useAPIServerCache)
+func (b *Builder) buildClusterTrustBundleStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(clusterTrustBundleMetricFamilies(b.allowAnnotationsList["clustertrustbundles"], b.allowLabelsList["clustertrustbundles"]), &certv1beta1.ClusterTrustBundle{}, createClusterTrustBundleListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildClusterRoleBindingStores() []cache.Store {
-	return b.buildStoresFunc(clusterRoleBindingMetricFamilies(b.allowAnnotationsList["clusterrolebindings"], b.allowLabelsList["clusterrolebindings"]), &rbacv1.ClusterRoleBinding{}, createClusterRoleBindingListWatch, b.useAPIServerCache)
+func (b *Builder) buildRoleBindingStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(roleBindingMetricFamilies(b.allowAnnotationsList["rolebindings"], b.allowLabelsList["rolebindings"]), &rbacv1.RoleBinding{}, createRoleBindingListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildRoleBindingStores() []cache.Store {
-	return b.buildStoresFunc(roleBindingMetricFamilies(b.allowAnnotationsList["rolebindings"], b.allowLabelsList["rolebindings"]), &rbacv1.RoleBinding{}, createRoleBindingListWatch, b.useAPIServerCache)
+func (b *Builder) buildRuntimeClassStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(runtimeClassMetricFamilies(b.allowAnnotationsList["runtimeclasses"], b.allowLabelsList["runtimeclasses"]), &nodev1.RuntimeClass{}, createRuntimeClassListWatch, b.useAPIServerCache, opts)
 }
 
-func (b *Builder) buildIngressClassStores() []cache.Store {
-	return b.buildStoresFunc(ingressClassMetricFamilies(b.allowAnnotationsList["ingressclasses"], b.allowLabelsList["ingressclasses"]), &networkingv1.IngressClass{}, createIngressClassListWatch, b.useAPIServerCache)
+func (b *Builder) buildIngressClassStores(opts ksmtypes.StoreOptions) []cache.Store {
+	return b.buildStoresFunc(ingressClassMetricFamilies(b.allowAnnotationsList["ingressclasses"], b.allowLabelsList["ingressclasses"]), &networkingv1.IngressClass{}, createIngressClassListWatch, b.useAPIServerCache, opts)
 }
 
 func (b *Builder) buildStores(
@@ -482,21 +1159,28 @@ func (b *Builder) buildStores(
 	expectedType interface{},
 	listWatchFunc func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher,
 	useAPIServerCache bool,
+	opts ksmtypes.StoreOptions,
 ) []cache.Store {
 	metricFamilies = generator.FilterFamilyGenerators(b.familyGeneratorFilter, metricFamilies)
+	metricFamilies = generator.DropLabels(b.labelsDenylist, metricFamilies)
+	metricFamilies = generator.WithPrefix(opts.MetricPrefix, metricFamilies)
 	composedMetricGenFuncs := generator.ComposeMetricGenFuncs(metricFamilies)
 	familyHeaders := generator.ExtractMetricFamilyHeaders(metricFamilies)
+	if opts.ListWatchFunc != nil {
+		listWatchFunc = opts.ListWatchFunc
+	}
 
 	if b.namespaces.IsAllNamespaces() {
 		store := metricsstore.NewMetricsStore(
 			familyHeaders,
 			composedMetricGenFuncs,
 		)
-		if b.fieldSelectorFilter != "" {
-			klog.Infof("FieldSelector is used %s", b.fieldSelectorFilter)
+		if opts.FieldSelector != "" {
+			klog.Infof("FieldSelector is used %s", opts.FieldSelector)
 		}
-		listWatcher := listWatchFunc(b.kubeClient, v1.NamespaceAll, b.fieldSelectorFilter)
-		b.startReflector(expectedType, store, listWatcher, useAPIServerCache)
+		listWatcher := listWatchFunc(b.kubeClient, v1.NamespaceAll, opts.FieldSelector)
+		b.startReflector(expectedType, store, listWatcher, useAPIServerCache, opts)
+		b.startGarbageCollector(expectedType, store, v1.NamespaceAll)
 		return []cache.Store{store}
 	}
 
@@ -506,25 +1190,87 @@ func (b *Builder) buildStores(
 			familyHeaders,
 			composedMetricGenFuncs,
 		)
-		if b.fieldSelectorFilter != "" {
-			klog.Infof("FieldSelector is used %s", b.fieldSelectorFilter)
+		if opts.FieldSelector != "" {
+			klog.Infof("FieldSelector is used %s", opts.FieldSelector)
 		}
-		listWatcher := listWatchFunc(b.kubeClient, ns, b.fieldSelectorFilter)
-		b.startReflector(expectedType, store, listWatcher, useAPIServerCache)
+		listWatcher := listWatchFunc(b.kubeClient, ns, opts.FieldSelector)
+		b.startReflector(expectedType, store, listWatcher, useAPIServerCache, opts)
+		b.startGarbageCollector(expectedType, store, ns)
 		stores = append(stores, store)
 	}
 
 	return stores
 }
 
+// buildEventStores builds the stores of the occurrences of events. The events
+// are not stored, only their occurrences are counted by eventCountStores,
+// which write the counts to the returned stores.
+func (b *Builder) buildEventStores(opts ksmtypes.StoreOptions) []cache.Store {
+	metricFamilies := generator.FilterFamilyGenerators(b.familyGeneratorFilter, eventMetricFamilies())
+	metricFamilies = generator.DropLabels(b.labelsDenylist, metricFamilies)
+	metricFamilies = generator.WithPrefix(opts.MetricPrefix, metricFamilies)
+	composedMetricGenFuncs := generator.ComposeMetricGenFuncs(metricFamilies)
+	familyHeaders := generator.ExtractMetricFamilyHeaders(metricFamilies)
+	listWatchFunc := createEventListWatch
+	if opts.ListWatchFunc != nil {
+		listWatchFunc = opts.ListWatchFunc
+	}
+
+	namespaces := []string{v1.NamespaceAll}
+	if !b.namespaces.IsAllNamespaces() {
+		namespaces = b.namespaces
+	}
+	stores := make([]cache.Store, 0, len(namespaces))
+	for _, ns := range namespaces {
+		store := metricsstore.NewMetricsStore(familyHeaders, composedMetricGenFuncs)
+		listWatcher := listWatchFunc(b.kubeClient, ns, opts.FieldSelector)
+		b.startReflector(&v1.Event{}, newEventCountStore(store), listWatcher, b.useAPIServerCache, opts)
+		stores = append(stores, store)
+	}
+	return stores
+}
+
+// buildObjectCountStores builds the stores of the objects counted by the
+// kube_objectcount metric. Objects of namespaced resources are watched in the
+// enabled namespaces, objects of cluster-scoped resources in all namespaces.
+func (b *Builder) buildObjectCountStores() []cache.Store {
+	if len(b.objectCountResources) == 0 || b.metadataClient == nil {
+		return nil
+	}
+	var stores []cache.Store
+	for _, r := range b.objectCountResources {
+		metricFamilies := generator.FilterFamilyGenerators(b.familyGeneratorFilter, objectCountMetricFamilies(r))
+		if len(metricFamilies) == 0 {
+			return nil
+		}
+		composedMetricGenFuncs := generator.ComposeMetricGenFuncs(metricFamilies)
+		familyHeaders := generator.ExtractMetricFamilyHeaders(metricFamilies)
+
+		gvr := schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Name}
+		namespaces := []string{v1.NamespaceAll}
+		if r.Namespaced && !b.namespaces.IsAllNamespaces() {
+			namespaces = b.namespaces
+		}
+		for _, ns := range namespaces {
+			store := metricsstore.NewMetricsStore(familyHeaders, composedMetricGenFuncs)
+			listWatcher := createMetadataListWatchFunc(b.metadataClient, gvr)(b.kubeClient, ns, "")
+			b.startReflector(metadataExpectedType(gvr.GroupVersion().WithKind(r.Kind)), store, listWatcher, b.useAPIServerCache, ksmtypes.StoreOptions{Aggregate: true, AggregateBy: objectCountLabels})
+			stores = append(stores, store)
+		}
+	}
+	return stores
+}
+
 // TODO(Garrybest): Merge `buildStores` and `buildCustomResourceStores`
 func (b *Builder) buildCustomResourceStores(resourceName string,
 	metricFamilies []generator.FamilyGenerator,
 	expectedType interface{},
 	listWatchFunc func(customResourceClient interface{}, ns string, fieldSelector string) cache.ListerWatcher,
 	useAPIServerCache bool,
+	opts ksmtypes.StoreOptions,
 ) []cache.Store {
 	metricFamilies = generator.FilterFamilyGenerators(b.familyGeneratorFilter, metricFamilies)
+	metricFamilies = generator.DropLabels(b.labelsDenylist, metricFamilies)
 	composedMetricGenFuncs := generator.ComposeMetricGenFuncs(metricFamilies)
 	familyHeaders := generator.ExtractMetricFamilyHeaders(metricFamilies)
 
@@ -539,11 +1285,11 @@ func (b *Builder) buildCustomResourceStores(resourceName string,
 			familyHeaders,
 			composedMetricGenFuncs,
 		)
-		if b.fieldSelectorFilter != "" {
-			klog.Infof("FieldSelector is used %s", b.fieldSelectorFilter)
+		if opts.FieldSelector != "" {
+			klog.Infof("FieldSelector is used %s", opts.FieldSelector)
 		}
-		listWatcher := listWatchFunc(customResourceClient, v1.NamespaceAll, b.fieldSelectorFilter)
-		b.startReflector(expectedType, store, listWatcher, useAPIServerCache)
+		listWatcher := listWatchFunc(customResourceClient, v1.NamespaceAll, opts.FieldSelector)
+		b.startReflector(expectedType, store, listWatcher, useAPIServerCache, opts)
 		return []cache.Store{store}
 	}
 
@@ -553,9 +1299,9 @@ func (b *Builder) buildCustomResourceStores(resourceName string,
 			familyHeaders,
 			composedMetricGenFuncs,
 		)
-		klog.Infof("FieldSelector is used %s", b.fieldSelectorFilter)
-		listWatcher := listWatchFunc(customResourceClient, ns, b.fieldSelectorFilter)
-		b.startReflector(expectedType, store, listWatcher, useAPIServerCache)
+		klog.Infof("FieldSelector is used %s", opts.FieldSelector)
+		listWatcher := listWatchFunc(customResourceClient, ns, opts.FieldSelector)
+		b.startReflector(expectedType, store, listWatcher, useAPIServerCache, opts)
 		stores = append(stores, store)
 	}
 
@@ -563,16 +1309,104 @@ func (b *Builder) buildCustomResourceStores(resourceName string,
 }
 
 // startReflector starts a Kubernetes client-go reflector with the given
-// listWatcher and registers it with the given store.
+// listWatcher and registers it with the given store, which is configured with
+// the given options.
 func (b *Builder) startReflector(
 	expectedType interface{},
 	store cache.Store,
 	listWatcher cache.ListerWatcher,
 	useAPIServerCache bool,
+	opts ksmtypes.StoreOptions,
 ) {
-	instrumentedListWatch := watch.NewInstrumentedListerWatcher(listWatcher, b.listWatchMetrics, reflect.TypeOf(expectedType).String(), useAPIServerCache)
-	reflector := cache.NewReflector(sharding.NewShardedListWatch(b.shard, b.totalShards, instrumentedListWatch), expectedType, store, 0)
-	go reflector.Run(b.ctx.Done())
+	resource := reflectorResource(expectedType)
+	var resyncPeriod time.Duration
+	if s, ok := store.(*metricsstore.MetricsStore); ok {
+		if opts.ObjectLimit != nil {
+			s.SetObjectLimit(opts.ObjectLimit)
+		}
+		if opts.Lazy {
+			s.SetLazy()
+		}
+		if b.keepFamilies {
+			s.SetKeepFamilies()
+		}
+		if opts.Aggregate {
+			s.SetAggregateBy(opts.AggregateBy)
+		}
+		if opts.Expiry != nil {
+			s.SetExpiry(opts.Expiry)
+			// Resyncs drop the metrics of objects which expire
+			// without being updated.
+			resyncPeriod = completedObjectsResyncPeriod
+		}
+	}
+	if opts.TweakListOptions != nil {
+		listWatcher = watch.NewTweakedListerWatcher(listWatcher, opts.TweakListOptions)
+	}
+	if b.useWatchList {
+		listWatcher = watch.NewWatchListListerWatcher(listWatcher)
+	}
+	if b.watchHealth != nil {
+		listWatcher = watch.NewHealthTrackingListerWatcher(listWatcher, b.watchHealth.Track(b.ctx, resource))
+	}
+	instrumentedListWatch := watch.NewInstrumentedListerWatcherForGVR(listWatcher, b.listWatchMetrics, resource, groupVersionResource(expectedType), useAPIServerCache, b.tracer)
+	shardedListWatch := sharding.NewShardedListWatchWithStrategy(b.shard, b.totalShards, b.shardingStrategy, instrumentedListWatch)
+	reflector := cache.NewReflector(watch.NewStartupListerWatcher(b.ctx, shardedListWatch, b.startupLimiter, b.listWatchMetrics, resource), expectedType, store, resyncPeriod)
+	reflector.WatchListPageSize = b.listPageSize
+	go b.watchBackoff.RunReflector(reflector, b.ctx.Done())
+}
+
+// startGarbageCollector starts pruning objects of the given store which no
+// longer exist in the given namespace, if configured.
+func (b *Builder) startGarbageCollector(expectedType interface{}, store *metricsstore.MetricsStore, ns string) {
+	if b.staleObjectGCInterval <= 0 || b.metadataClient == nil {
+		return
+	}
+	gvr := groupVersionResource(expectedType)
+	if gvr.Empty() {
+		return
+	}
+	gc := watch.NewGarbageCollector(store, createMetadataListUIDsFunc(b.metadataClient, gvr, ns), b.listWatchMetrics, reflectorResource(expectedType))
+	go gc.Run(b.ctx, b.staleObjectGCInterval)
+}
+
+// reflectorResource returns the resource label of the metrics of the
+// reflector of the given expected type, e.g. *v1.Pod.
+func reflectorResource(expectedType interface{}) string {
+	if m, ok := expectedType.(*metav1.PartialObjectMetadata); ok && m.Kind != "" {
+		// Label metadata-only stores like stores of full objects, e.g. *v1.ConfigMap.
+		return fmt.Sprintf("*%s.%s", m.GroupVersionKind().Version, m.Kind)
+	}
+	if u, ok := expectedType.(*unstructured.Unstructured); ok && isBuiltInUnstructured(u) {
+		// Label the built-in stores of unstructured objects by their kind
+		// instead of as custom resource stores.
+		return fmt.Sprintf("*%s.%s", u.GroupVersionKind().Version, u.GetKind())
+	}
+	return reflect.TypeOf(expectedType).String()
+}
+
+// groupVersionResource returns the group, version and resource of the expected
+// type of a reflector. The resource is guessed from the kind. Unknown types
+// result in an empty group, version and resource.
+func groupVersionResource(expectedType interface{}) schema.GroupVersionResource {
+	obj, ok := expectedType.(runtime.Object)
+	if !ok {
+		return schema.GroupVersionResource{}
+	}
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		for _, s := range []*runtime.Scheme{scheme.Scheme, vpascheme.Scheme} {
+			if gvks, _, err := s.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+				gvk = gvks[0]
+				break
+			}
+		}
+	}
+	if gvk.Empty() {
+		return schema.GroupVersionResource{}
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	return gvr
 }
 
 // cacheStoresToMetricStores converts []cache.Store into []*metricsstore.MetricsStore
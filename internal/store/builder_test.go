@@ -17,9 +17,26 @@ limitations under the License.
 package store
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	ksmtypes "k8s.io/kube-state-metrics/v2/pkg/builder/types"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
 )
 
@@ -33,11 +50,12 @@ type expectedError struct {
 
 func TestWithAllowLabels(t *testing.T) {
 	tests := []struct {
-		Desc             string
-		LabelsAllowlist  map[string][]string
-		EnabledResources []string
-		Wanted           LabelsAllowList
-		err              expectedError
+		Desc              string
+		LabelsAllowlist   map[string][]string
+		EnabledResources  []string
+		RecommendedLabels bool
+		Wanted            LabelsAllowList
+		err               expectedError
 	}{
 		{
 			Desc:             "wildcard key-value as the only element",
@@ -86,6 +104,25 @@ func TestWithAllowLabels(t *testing.T) {
 				expectedResourceError: true,
 			},
 		},
+		{
+			Desc:              "recommended labels without allow list",
+			EnabledResources:  []string{"pods", "deployments"},
+			RecommendedLabels: true,
+			Wanted: LabelsAllowList(map[string][]string{
+				"deployments": recommendedLabelKeys,
+				"pods":        recommendedLabelKeys,
+			}),
+		},
+		{
+			Desc:              "recommended labels with allow list",
+			LabelsAllowlist:   map[string][]string{"pods": {"app", "app.kubernetes.io/name"}, "deployments": {"*"}},
+			EnabledResources:  []string{"pods", "deployments"},
+			RecommendedLabels: true,
+			Wanted: LabelsAllowList(map[string][]string{
+				"deployments": {"*"},
+				"pods":        append([]string{"app", "app.kubernetes.io/name"}, recommendedLabelKeys[1:]...),
+			}),
+		},
 	}
 
 	for _, test := range tests {
@@ -99,6 +136,7 @@ func TestWithAllowLabels(t *testing.T) {
 		}
 
 		// Resolve the allow list.
+		b.WithRecommendedLabels(test.RecommendedLabels)
 		err = b.WithAllowLabels(test.LabelsAllowlist)
 		if err != nil && !test.err.expectedLabelError {
 			t.Log("Did not expect error while parsing allow list labels (--metric-labels-allowlist).")
@@ -113,3 +151,202 @@ func TestWithAllowLabels(t *testing.T) {
 		}
 	}
 }
+
+func TestStoreOptionsFieldSelectors(t *testing.T) {
+	b := NewBuilder()
+	b.WithFieldSelectorFilter("metadata.namespace!=kube-system")
+	if err := b.WithFieldSelectors(map[string]string{"pods": "status.phase!=Succeeded"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		resource string
+		want     string
+	}{
+		{resource: "pods", want: "metadata.namespace!=kube-system,status.phase!=Succeeded"},
+		{resource: "jobs", want: "metadata.namespace!=kube-system"},
+	}
+	for _, test := range tests {
+		if got := b.storeOptions(test.resource).FieldSelector; got != test.want {
+			t.Errorf("%s: expected field selector %q, got %q", test.resource, test.want, got)
+		}
+	}
+
+	if err := b.WithFieldSelectors(map[string]string{"foo": "status.phase!=Succeeded"}); err == nil {
+		t.Error("expected error for unknown resource")
+	}
+}
+
+func TestBuildWithListWatchHooks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listed := make(chan metav1.ListOptions, 1)
+	b := NewBuilder()
+	b.WithMetrics(prometheus.NewRegistry())
+	b.WithContext(ctx)
+	b.WithNamespaces(options.DefaultNamespaces)
+	b.WithSharding(0, 1)
+	b.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter())
+	b.WithGenerateStoresFunc(b.DefaultGenerateStoresFunc())
+	if err := b.WithEnabledResources([]string{"pods"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WithListWatchFuncs(map[string]ksmtypes.ListWatchFunc{
+		"pods": func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+			return &cache.ListWatch{
+				ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+					select {
+					case listed <- opts:
+					default:
+					}
+					return &v1.PodList{}, nil
+				},
+				WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+					return watch.NewFake(), nil
+				},
+			}
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WithTweakListOptions(map[string]func(*metav1.ListOptions){
+		"pods": func(opts *metav1.ListOptions) { opts.LabelSelector = "app=foo" },
+	}); err != nil {
+		t.Fatal(err)
+	}
+	b.Build()
+
+	select {
+	case opts := <-listed:
+		if opts.LabelSelector != "app=foo" {
+			t.Errorf("expected the tweaked label selector, got %q", opts.LabelSelector)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected pods to be listed by the configured ListerWatcher")
+	}
+
+	if err := b.WithListWatchFuncs(map[string]ksmtypes.ListWatchFunc{"foo": nil}); err == nil {
+		t.Error("expected error for unknown resource")
+	}
+	if err := b.WithTweakListOptions(map[string]func(*metav1.ListOptions){"foo": nil}); err == nil {
+		t.Error("expected error for unknown resource")
+	}
+}
+
+func TestBuildWithListWatchHookSidecarContainers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	always := v1.ContainerRestartPolicyAlways
+	b := NewBuilder()
+	b.WithMetrics(prometheus.NewRegistry())
+	b.WithContext(ctx)
+	b.WithNamespaces(options.DefaultNamespaces)
+	b.WithSharding(0, 1)
+	b.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter())
+	b.WithGenerateStoresFunc(b.DefaultGenerateStoresFunc())
+	if err := b.WithEnabledResources([]string{"pods"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WithListWatchFuncs(map[string]ksmtypes.ListWatchFunc{
+		"pods": func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+			return &cache.ListWatch{
+				ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+					return &v1.PodList{Items: []v1.Pod{{
+						ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns1", UID: "uid1"},
+						Spec: v1.PodSpec{
+							InitContainers: []v1.Container{{Name: "proxy", RestartPolicy: &always}},
+						},
+						Status: v1.PodStatus{
+							InitContainerStatuses: []v1.ContainerStatus{{Name: "proxy"}},
+						},
+					}}}, nil
+				},
+				WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+					return watch.NewFake(), nil
+				},
+			}
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	writers := b.Build()
+
+	want := `kube_pod_init_container_info{namespace="ns1",pod="pod1",uid="uid1",container="proxy",image_spec="",image="",image_id="",container_id="",restart_policy="Always"} 1`
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var buf strings.Builder
+		for _, w := range writers {
+			if err := w.WriteAll(&buf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if strings.Contains(buf.String(), want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the sidecar container of the pod listed by the configured ListerWatcher, got:\n%s", buf.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGroupVersionResource(t *testing.T) {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"})
+
+	tests := []struct {
+		expectedType interface{}
+		want         schema.GroupVersionResource
+	}{
+		{&v1.Pod{}, schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+		{&appsv1.Deployment{}, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+		{&vpaautoscaling.VerticalPodAutoscaler{}, schema.GroupVersionResource{Group: "autoscaling.k8s.io", Version: "v1beta2", Resource: "verticalpodautoscalers"}},
+		{crd, schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "foos"}},
+		{"unknown", schema.GroupVersionResource{}},
+	}
+	for _, test := range tests {
+		if got := groupVersionResource(test.expectedType); got != test.want {
+			t.Errorf("%T: expected %v, got %v", test.expectedType, test.want, got)
+		}
+	}
+}
+
+func TestResourceGroups(t *testing.T) {
+	for resource := range availableStores {
+		if _, ok := ResourceGroup(resource); !ok {
+			t.Errorf("no API group of resource %s", resource)
+		}
+	}
+	for resource := range resourceGroups {
+		if !resourceExists(resource) {
+			t.Errorf("API group of unknown resource %s", resource)
+		}
+	}
+	for resource := range clusterScopedResources {
+		if !resourceExists(resource) {
+			t.Errorf("unknown cluster-scoped resource %s", resource)
+		}
+	}
+}
+
+func TestReflectorResource(t *testing.T) {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"})
+
+	tests := []struct {
+		expectedType interface{}
+		want         string
+	}{
+		{&v1.Pod{}, "*v1.Pod"},
+		{metadataExpectedType(v1.SchemeGroupVersion.WithKind("ConfigMap")), "*v1.ConfigMap"},
+		{unstructuredExpectedType(gatewayAPIGroupVersion.WithKind("Gateway")), "*v1.Gateway"},
+		{crd, "*unstructured.Unstructured"},
+	}
+	for _, test := range tests {
+		if got := reflectorResource(test.expectedType); got != test.want {
+			t.Errorf("expected %q, got %q", test.want, got)
+		}
+	}
+}
@@ -18,6 +18,8 @@ package store
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 
 	basemetrics "k8s.io/component-base/metrics"
 
@@ -130,6 +132,42 @@ func csrMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_certificatesigningrequest_requested_duration_seconds",
+			"The duration of the certificate requested by the certificatesigningrequest. Signers may issue certificates of other durations.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCSRFunc(func(csr *certv1.CertificateSigningRequest) *metric.Family {
+				ms := []*metric.Metric{}
+				if csr.Spec.ExpirationSeconds != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(*csr.Spec.ExpirationSeconds),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_certificatesigningrequest_cert_not_after",
+			"Unix timestamp the issued certificate expires at.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCSRFunc(func(csr *certv1.CertificateSigningRequest) *metric.Family {
+				ms := []*metric.Metric{}
+				if cert := issuedCertificate(csr.Status.Certificate); cert != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(cert.NotAfter.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 	}
 }
 
@@ -183,3 +221,26 @@ func addCSRConditionMetrics(cs certv1.CertificateSigningRequestStatus) []*metric
 		},
 	}
 }
+
+// issuedCertificate returns the first certificate of the PEM encoded issued
+// certificate chain of a certificatesigningrequest, i.e. the issued leaf
+// certificate. It returns nil if no certificate was issued or it can not be
+// parsed.
+func issuedCertificate(certificate []byte) *x509.Certificate {
+	for len(certificate) > 0 {
+		var block *pem.Block
+		block, certificate = pem.Decode(certificate)
+		if block == nil {
+			return nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil
+		}
+		return cert
+	}
+	return nil
+}
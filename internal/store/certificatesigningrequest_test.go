@@ -17,6 +17,12 @@ limitations under the License.
 package store
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
 	"testing"
 	"time"
 
@@ -234,3 +240,73 @@ func TestCsrStore(t *testing.T) {
 		}
 	}
 }
+
+func TestCsrStoreIssuedCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Unix(1500000000, 0),
+		NotAfter:     time.Unix(1500086400, 0),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expirationSeconds := int32(86400)
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &certv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "kubelet-serving",
+				},
+				Spec: certv1.CertificateSigningRequestSpec{
+					SignerName:        "kubernetes.io/kubelet-serving",
+					ExpirationSeconds: &expirationSeconds,
+				},
+				Status: certv1.CertificateSigningRequestStatus{
+					Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+				},
+			},
+			Want: `
+				# HELP kube_certificatesigningrequest_cert_not_after Unix timestamp the issued certificate expires at.
+				# HELP kube_certificatesigningrequest_requested_duration_seconds The duration of the certificate requested by the certificatesigningrequest. Signers may issue certificates of other durations.
+				# TYPE kube_certificatesigningrequest_cert_not_after gauge
+				# TYPE kube_certificatesigningrequest_requested_duration_seconds gauge
+				kube_certificatesigningrequest_cert_not_after{certificatesigningrequest="kubelet-serving",signer_name="kubernetes.io/kubelet-serving"} 1.5000864e+09
+				kube_certificatesigningrequest_requested_duration_seconds{certificatesigningrequest="kubelet-serving",signer_name="kubernetes.io/kubelet-serving"} 86400
+			`,
+			MetricNames: []string{"kube_certificatesigningrequest_cert_not_after", "kube_certificatesigningrequest_requested_duration_seconds"},
+		},
+		{
+			Obj: &certv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "invalid",
+				},
+				Spec: certv1.CertificateSigningRequestSpec{
+					SignerName: "signer",
+				},
+				Status: certv1.CertificateSigningRequestStatus{
+					Certificate: []byte("just for test"),
+				},
+			},
+			Want: `
+				# HELP kube_certificatesigningrequest_cert_not_after Unix timestamp the issued certificate expires at.
+				# HELP kube_certificatesigningrequest_requested_duration_seconds The duration of the certificate requested by the certificatesigningrequest. Signers may issue certificates of other durations.
+				# TYPE kube_certificatesigningrequest_cert_not_after gauge
+				# TYPE kube_certificatesigningrequest_requested_duration_seconds gauge
+			`,
+			MetricNames: []string{"kube_certificatesigningrequest_cert_not_after", "kube_certificatesigningrequest_requested_duration_seconds"},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(csrMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(csrMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descClusterTrustBundleAnnotationsName     = "kube_clustertrustbundle_annotations"
+	descClusterTrustBundleAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descClusterTrustBundleLabelsName          = "kube_clustertrustbundle_labels"
+	descClusterTrustBundleLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descClusterTrustBundleLabelsDefaultLabels = []string{"clustertrustbundle"}
+)
+
+// trustAnchors returns the number of certificates of the trust bundle of the
+// ClusterTrustBundle and a
+// SHA-256 hash of them. The hash only changes if the certificates change, not
+// if only their PEM encoding changes, e.g. by text between the blocks.
+func trustAnchors(b *certv1beta1.ClusterTrustBundle) (int, string) {
+	n := 0
+	h := sha256.New()
+	rest := []byte(b.Spec.TrustBundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		n++
+		h.Write(block.Bytes)
+	}
+	return n, hex.EncodeToString(h.Sum(nil))
+}
+
+func clusterTrustBundleMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_clustertrustbundle_info",
+			"Information about clustertrustbundle.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapClusterTrustBundleFunc(func(b *certv1beta1.ClusterTrustBundle) *metric.Family {
+				_, hash := trustAnchors(b)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"signer_name", "trust_bundle_hash"},
+							LabelValues: []string{b.Spec.SignerName, hash},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_clustertrustbundle_trust_anchors",
+			"Number of certificates in the trust bundle of the clustertrustbundle.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapClusterTrustBundleFunc(func(b *certv1beta1.ClusterTrustBundle) *metric.Family {
+				n, _ := trustAnchors(b)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(n),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_clustertrustbundle_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapClusterTrustBundleFunc(func(b *certv1beta1.ClusterTrustBundle) *metric.Family {
+				ms := []*metric.Metric{}
+				if !b.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(b.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descClusterTrustBundleAnnotationsName,
+			descClusterTrustBundleAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapClusterTrustBundleFunc(func(b *certv1beta1.ClusterTrustBundle) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", b.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descClusterTrustBundleLabelsName,
+			descClusterTrustBundleLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapClusterTrustBundleFunc(func(b *certv1beta1.ClusterTrustBundle) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", b.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+	}
+}
+
+func wrapClusterTrustBundleFunc(f func(*certv1beta1.ClusterTrustBundle) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		b := obj.(*certv1beta1.ClusterTrustBundle)
+
+		metricFamily := f(b)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descClusterTrustBundleLabelsDefaultLabels, []string{b.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
+
+func createClusterTrustBundleListWatch(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.CertificatesV1beta1().ClusterTrustBundles().List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.CertificatesV1beta1().ClusterTrustBundles().Watch(context.TODO(), opts)
+		},
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	certv1beta1 "k8s.io/api/certificates/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestClusterTrustBundleStore(t *testing.T) {
+	startTime := 1501569018
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &certv1beta1.ClusterTrustBundle{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "example.com:signer:abc",
+					CreationTimestamp: metav1.Time{Time: time.Unix(int64(startTime), 0)},
+				},
+				Spec: certv1beta1.ClusterTrustBundleSpec{
+					SignerName:  "example.com/signer",
+					TrustBundle: "-----BEGIN CERTIFICATE-----\nYWJj\n-----END CERTIFICATE-----\n# comment\n-----BEGIN CERTIFICATE-----\nZGVm\n-----END CERTIFICATE-----\n",
+				},
+			},
+			Want: `
+				# HELP kube_clustertrustbundle_created Unix creation timestamp
+				# HELP kube_clustertrustbundle_info Information about clustertrustbundle.
+				# HELP kube_clustertrustbundle_trust_anchors Number of certificates in the trust bundle of the clustertrustbundle.
+				# TYPE kube_clustertrustbundle_created gauge
+				# TYPE kube_clustertrustbundle_info gauge
+				# TYPE kube_clustertrustbundle_trust_anchors gauge
+				kube_clustertrustbundle_created{clustertrustbundle="example.com:signer:abc"} 1.501569018e+09
+				kube_clustertrustbundle_info{clustertrustbundle="example.com:signer:abc",signer_name="example.com/signer",trust_bundle_hash="bef57ec7f53a6d40beb640a780a639c83bc29ac8a9816f1fc6c5c6dcd93c4721"} 1
+				kube_clustertrustbundle_trust_anchors{clustertrustbundle="example.com:signer:abc"} 2
+			`,
+			MetricNames: []string{
+				"kube_clustertrustbundle_created",
+				"kube_clustertrustbundle_info",
+				"kube_clustertrustbundle_trust_anchors",
+			},
+		},
+		{
+			Obj: &certv1beta1.ClusterTrustBundle{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "empty",
+				},
+			},
+			Want: `
+				# HELP kube_clustertrustbundle_info Information about clustertrustbundle.
+				# HELP kube_clustertrustbundle_trust_anchors Number of certificates in the trust bundle of the clustertrustbundle.
+				# TYPE kube_clustertrustbundle_info gauge
+				# TYPE kube_clustertrustbundle_trust_anchors gauge
+				kube_clustertrustbundle_info{clustertrustbundle="empty",signer_name="",trust_bundle_hash="e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"} 1
+				kube_clustertrustbundle_trust_anchors{clustertrustbundle="empty"} 0
+			`,
+			MetricNames: []string{
+				"kube_clustertrustbundle_info",
+				"kube_clustertrustbundle_trust_anchors",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(clusterTrustBundleMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(clusterTrustBundleMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"time"
+
+	v1batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+// completedObjectsResyncPeriod is the period in which objects which completed
+// less than the configured duration ago are checked again.
+const completedObjectsResyncPeriod = time.Minute
+
+// completedAtFuncs return the time objects of a resource reached a terminal
+// state, and whether they did, per resource supporting the exclusion of
+// completed objects.
+var completedAtFuncs = map[string]func(obj interface{}) (time.Time, bool){
+	"jobs": jobCompletedAt,
+	"pods": podCompletedAt,
+}
+
+// completedExpiry returns an expiry func of objects which completed more than
+// the given duration ago.
+func completedExpiry(completedAt func(obj interface{}) (time.Time, bool), after time.Duration) metricsstore.ExpiryFunc {
+	return func(obj interface{}) (time.Time, bool) {
+		t, ok := completedAt(obj)
+		if !ok {
+			return time.Time{}, false
+		}
+		return t.Add(after), true
+	}
+}
+
+// podCompletedAt returns the time a Succeeded or Failed Pod terminated, which
+// is the time its last container terminated.
+func podCompletedAt(obj interface{}) (time.Time, bool) {
+	p, ok := obj.(*v1.Pod)
+	if !ok || (p.Status.Phase != v1.PodSucceeded && p.Status.Phase != v1.PodFailed) {
+		return time.Time{}, false
+	}
+	var t metav1.Time
+	for _, statuses := range [][]v1.ContainerStatus{p.Status.InitContainerStatuses, p.Status.ContainerStatuses} {
+		for _, cs := range statuses {
+			if cs.State.Terminated != nil && t.Before(&cs.State.Terminated.FinishedAt) {
+				t = cs.State.Terminated.FinishedAt
+			}
+		}
+	}
+	if t.IsZero() {
+		// Pods can fail without containers, e.g. when they are evicted.
+		for _, c := range p.Status.Conditions {
+			if t.Before(&c.LastTransitionTime) {
+				t = c.LastTransitionTime
+			}
+		}
+	}
+	if t.IsZero() {
+		t = p.CreationTimestamp
+	}
+	return t.Time, true
+}
+
+// jobCompletedAt returns the time a Job completed or failed.
+func jobCompletedAt(obj interface{}) (time.Time, bool) {
+	j, ok := obj.(*v1batch.Job)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, c := range j.Status.Conditions {
+		if (c.Type != v1batch.JobComplete && c.Type != v1batch.JobFailed) || c.Status != v1.ConditionTrue {
+			continue
+		}
+		if c.Type == v1batch.JobComplete && j.Status.CompletionTime != nil {
+			return j.Status.CompletionTime.Time, true
+		}
+		if !c.LastTransitionTime.IsZero() {
+			return c.LastTransitionTime.Time, true
+		}
+		return j.CreationTimestamp.Time, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	v1batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompletedExpiry(t *testing.T) {
+	created := metav1.Unix(1000, 0)
+	finished := metav1.Unix(2000, 0)
+	transitioned := metav1.Unix(3000, 0)
+
+	tests := []struct {
+		name        string
+		obj         interface{}
+		wantExpires time.Time
+		wantOK      bool
+	}{
+		{
+			name: "running pod",
+			obj:  &v1.Pod{Status: v1.PodStatus{Phase: v1.PodRunning}},
+		},
+		{
+			name: "succeeded pod",
+			obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Status: v1.PodStatus{
+					Phase: v1.PodSucceeded,
+					InitContainerStatuses: []v1.ContainerStatus{
+						{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{FinishedAt: created}}},
+					},
+					ContainerStatuses: []v1.ContainerStatus{
+						{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{FinishedAt: finished}}},
+					},
+				},
+			},
+			wantExpires: finished.Add(time.Minute),
+			wantOK:      true,
+		},
+		{
+			name: "evicted pod",
+			obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Status: v1.PodStatus{
+					Phase:      v1.PodFailed,
+					Conditions: []v1.PodCondition{{Type: v1.DisruptionTarget, LastTransitionTime: transitioned}},
+				},
+			},
+			wantExpires: transitioned.Add(time.Minute),
+			wantOK:      true,
+		},
+		{
+			name: "failed pod without status",
+			obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Status:     v1.PodStatus{Phase: v1.PodFailed},
+			},
+			wantExpires: created.Add(time.Minute),
+			wantOK:      true,
+		},
+		{
+			name: "active job",
+			obj: &v1batch.Job{
+				Status: v1batch.JobStatus{
+					Conditions: []v1batch.JobCondition{{Type: v1batch.JobComplete, Status: v1.ConditionFalse}},
+				},
+			},
+		},
+		{
+			name: "completed job",
+			obj: &v1batch.Job{
+				Status: v1batch.JobStatus{
+					CompletionTime: &finished,
+					Conditions:     []v1batch.JobCondition{{Type: v1batch.JobComplete, Status: v1.ConditionTrue, LastTransitionTime: transitioned}},
+				},
+			},
+			wantExpires: finished.Add(time.Minute),
+			wantOK:      true,
+		},
+		{
+			name: "failed job",
+			obj: &v1batch.Job{
+				Status: v1batch.JobStatus{
+					Conditions: []v1batch.JobCondition{{Type: v1batch.JobFailed, Status: v1.ConditionTrue, LastTransitionTime: transitioned}},
+				},
+			},
+			wantExpires: transitioned.Add(time.Minute),
+			wantOK:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var completedAt func(interface{}) (time.Time, bool)
+			switch test.obj.(type) {
+			case *v1.Pod:
+				completedAt = completedAtFuncs["pods"]
+			case *v1batch.Job:
+				completedAt = completedAtFuncs["jobs"]
+			}
+			expires, ok := completedExpiry(completedAt, time.Minute)(test.obj)
+			if ok != test.wantOK || !expires.Equal(test.wantExpires) {
+				t.Errorf("expected %v, %t, got %v, %t", test.wantExpires, test.wantOK, expires, ok)
+			}
+		})
+	}
+}
@@ -19,7 +19,7 @@ package store
 import (
 	"context"
 
-	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -42,8 +42,8 @@ func configMapMetricFamilies(allowAnnotationsList, allowLabelsList []string) []g
 			"Kubernetes annotations converted to Prometheus labels.",
 			metric.Gauge,
 			"",
-			wrapConfigMapFunc(func(c *v1.ConfigMap) *metric.Family {
-				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", c.Annotations, allowAnnotationsList)
+			wrapConfigMapFunc(func(c metav1.Object) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", c.GetAnnotations(), allowAnnotationsList)
 				return &metric.Family{
 					Metrics: []*metric.Metric{
 						{
@@ -61,8 +61,8 @@ func configMapMetricFamilies(allowAnnotationsList, allowLabelsList []string) []g
 			metric.Gauge,
 			basemetrics.STABLE,
 			"",
-			wrapConfigMapFunc(func(c *v1.ConfigMap) *metric.Family {
-				labelKeys, labelValues := createPrometheusLabelKeysValues("label", c.Labels, allowLabelsList)
+			wrapConfigMapFunc(func(c metav1.Object) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", c.GetLabels(), allowLabelsList)
 				return &metric.Family{
 					Metrics: []*metric.Metric{
 						{
@@ -80,7 +80,7 @@ func configMapMetricFamilies(allowAnnotationsList, allowLabelsList []string) []g
 			metric.Gauge,
 			basemetrics.STABLE,
 			"",
-			wrapConfigMapFunc(func(c *v1.ConfigMap) *metric.Family {
+			wrapConfigMapFunc(func(c metav1.Object) *metric.Family {
 				return &metric.Family{
 					Metrics: []*metric.Metric{{
 						LabelKeys:   []string{},
@@ -96,14 +96,14 @@ func configMapMetricFamilies(allowAnnotationsList, allowLabelsList []string) []g
 			metric.Gauge,
 			basemetrics.STABLE,
 			"",
-			wrapConfigMapFunc(func(c *v1.ConfigMap) *metric.Family {
+			wrapConfigMapFunc(func(c metav1.Object) *metric.Family {
 				ms := []*metric.Metric{}
 
-				if !c.CreationTimestamp.IsZero() {
+				if created := c.GetCreationTimestamp(); !created.IsZero() {
 					ms = append(ms, &metric.Metric{
 						LabelKeys:   []string{},
 						LabelValues: []string{},
-						Value:       float64(c.CreationTimestamp.Unix()),
+						Value:       float64(created.Unix()),
 					})
 				}
 
@@ -117,9 +117,9 @@ func configMapMetricFamilies(allowAnnotationsList, allowLabelsList []string) []g
 			"Resource version representing a specific version of the configmap.",
 			metric.Gauge,
 			"",
-			wrapConfigMapFunc(func(c *v1.ConfigMap) *metric.Family {
+			wrapConfigMapFunc(func(c metav1.Object) *metric.Family {
 				return &metric.Family{
-					Metrics: resourceVersionMetric(c.ObjectMeta.ResourceVersion),
+					Metrics: resourceVersionMetric(c.GetResourceVersion()),
 				}
 			}),
 		),
@@ -139,14 +139,20 @@ func createConfigMapListWatch(kubeClient clientset.Interface, ns string, fieldSe
 	}
 }
 
-func wrapConfigMapFunc(f func(*v1.ConfigMap) *metric.Family) func(interface{}) *metric.Family {
+// wrapConfigMapFunc wraps a function generating metrics from the metadata of
+// a configmap. The object is either a *v1.ConfigMap or a
+// *metav1.PartialObjectMetadata if the metadata client is used.
+func wrapConfigMapFunc(f func(metav1.Object) *metric.Family) func(interface{}) *metric.Family {
 	return func(obj interface{}) *metric.Family {
-		configMap := obj.(*v1.ConfigMap)
+		configMap, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
 
 		metricFamily := f(configMap)
 
 		for _, m := range metricFamily.Metrics {
-			m.LabelKeys, m.LabelValues = mergeKeyValues(descConfigMapLabelsDefaultLabels, []string{configMap.Namespace, configMap.Name}, m.LabelKeys, m.LabelValues)
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descConfigMapLabelsDefaultLabels, []string{configMap.GetNamespace(), configMap.GetName()}, m.LabelKeys, m.LabelValues)
 		}
 
 		return metricFamily
@@ -94,6 +94,29 @@ func TestConfigMapStore(t *testing.T) {
 				`,
 			MetricNames: []string{"kube_configmap_info", "kube_configmap_created", "kube_configmap_metadata_resource_version"},
 		},
+		{
+			// Metadata-only object as returned by the metadata client.
+			Obj: &metav1.PartialObjectMetadata{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "configmap3",
+					Namespace:         "ns3",
+					CreationTimestamp: metav1StartTime,
+					ResourceVersion:   "10597",
+				},
+			},
+			Want: `
+				# HELP kube_configmap_created [STABLE] Unix creation timestamp
+				# HELP kube_configmap_info [STABLE] Information about configmap.
+				# HELP kube_configmap_metadata_resource_version Resource version representing a specific version of the configmap.
+				# TYPE kube_configmap_created gauge
+				# TYPE kube_configmap_info gauge
+				# TYPE kube_configmap_metadata_resource_version gauge
+				kube_configmap_info{configmap="configmap3",namespace="ns3"} 1
+				kube_configmap_created{configmap="configmap3",namespace="ns3"} 1.501569018e+09
+				kube_configmap_metadata_resource_version{configmap="configmap3",namespace="ns3"} 10597
+				`,
+			MetricNames: []string{"kube_configmap_info", "kube_configmap_created", "kube_configmap_metadata_resource_version"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(configMapMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
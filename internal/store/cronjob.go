@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -30,6 +31,7 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	basemetrics "k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
 
 	"k8s.io/kube-state-metrics/v2/pkg/metric"
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
@@ -41,6 +43,10 @@ var (
 	descCronJobLabelsName          = "kube_cronjob_labels"
 	descCronJobLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
 	descCronJobLabelsDefaultLabels = []string{"namespace", "cronjob"}
+
+	// cronJobUpcomingSchedules is the number of upcoming schedule times
+	// exposed per cron job.
+	cronJobUpcomingSchedules = 3
 )
 
 func cronJobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
@@ -92,8 +98,8 @@ func cronJobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gen
 				return &metric.Family{
 					Metrics: []*metric.Metric{
 						{
-							LabelKeys:   []string{"schedule", "concurrency_policy"},
-							LabelValues: []string{j.Spec.Schedule, string(j.Spec.ConcurrencyPolicy)},
+							LabelKeys:   []string{"schedule", "concurrency_policy", "timezone"},
+							LabelValues: []string{j.Spec.Schedule, string(j.Spec.ConcurrencyPolicy), stringValue(j.Spec.TimeZone)},
 							Value:       1,
 						},
 					},
@@ -237,14 +243,16 @@ func cronJobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gen
 				ms := []*metric.Metric{}
 
 				// If the cron job is suspended, don't track the next scheduled time
-				nextScheduledTime, err := getNextScheduledTime(j.Spec.Schedule, j.Status.LastScheduleTime, j.CreationTimestamp)
+				nextScheduledTimes, err := getNextScheduledTimes(j, 1)
 				if err != nil {
-					panic(err)
-				} else if !*j.Spec.Suspend {
+					klog.ErrorS(err, "Failed to get next scheduled time", "cronjob", klog.KObj(j))
+					return &metric.Family{}
+				}
+				if !*j.Spec.Suspend {
 					ms = append(ms, &metric.Metric{
 						LabelKeys:   []string{},
 						LabelValues: []string{},
-						Value:       float64(nextScheduledTime.Unix()),
+						Value:       float64(nextScheduledTimes[0].Unix()),
 					})
 				}
 
@@ -253,6 +261,34 @@ func cronJobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gen
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_cronjob_upcoming_schedule_time",
+			"Upcoming times the cronjob should be scheduled in the time zone of the cronjob, starting with the next schedule time.",
+			metric.Gauge,
+			"",
+			wrapCronJobFunc(func(j *batchv1.CronJob) *metric.Family {
+				ms := []*metric.Metric{}
+
+				nextScheduledTimes, err := getNextScheduledTimes(j, cronJobUpcomingSchedules)
+				if err != nil {
+					klog.ErrorS(err, "Failed to get upcoming scheduled times", "cronjob", klog.KObj(j))
+					return &metric.Family{}
+				}
+				if !*j.Spec.Suspend {
+					for i, t := range nextScheduledTimes {
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"occurrence"},
+							LabelValues: []string{strconv.Itoa(i + 1)},
+							Value:       float64(t.Unix()),
+						})
+					}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_cronjob_metadata_resource_version",
 			"Resource version representing a specific version of the cronjob.",
@@ -337,16 +373,37 @@ func createCronJobListWatch(kubeClient clientset.Interface, ns string, fieldSele
 	}
 }
 
-func getNextScheduledTime(schedule string, lastScheduleTime *metav1.Time, createdTime metav1.Time) (time.Time, error) {
-	sched, err := cron.ParseStandard(schedule)
+// getNextScheduledTimes returns the next n times the cron job should be
+// scheduled after its last schedule time, or after its creation time if it's
+// never been scheduled. The schedule is evaluated in the time zone of the cron
+// job if set.
+func getNextScheduledTimes(j *batchv1.CronJob, n int) ([]time.Time, error) {
+	sched, err := cron.ParseStandard(j.Spec.Schedule)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("Failed to parse cron job schedule '%s': %w", schedule, err)
+		return nil, fmt.Errorf("Failed to parse cron job schedule '%s': %w", j.Spec.Schedule, err)
 	}
-	if !lastScheduleTime.IsZero() {
-		return sched.Next(lastScheduleTime.Time), nil
+	loc := time.Local
+	if j.Spec.TimeZone != nil {
+		loc, err = time.LoadLocation(*j.Spec.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load cron job time zone '%s': %w", *j.Spec.TimeZone, err)
+		}
 	}
-	if !createdTime.IsZero() {
-		return sched.Next(createdTime.Time), nil
+
+	var t time.Time
+	switch {
+	case !j.Status.LastScheduleTime.IsZero():
+		t = j.Status.LastScheduleTime.Time
+	case !j.CreationTimestamp.IsZero():
+		t = j.CreationTimestamp.Time
+	default:
+		return nil, errors.New("createdTime and lastScheduleTime are both zero")
+	}
+
+	times := make([]time.Time, n)
+	for i := range times {
+		t = sched.Next(t.In(loc))
+		times[i] = t
 	}
-	return time.Time{}, errors.New("createdTime and lastScheduleTime are both zero")
+	return times, nil
 }
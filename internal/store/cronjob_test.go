@@ -21,6 +21,7 @@ import (
 	"math"
 	"testing"
 	"time"
+	_ "time/tzdata"
 
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
@@ -35,6 +36,8 @@ var (
 	StartingDeadlineSeconds300 int64 = 300
 	SuccessfulJobHistoryLimit3 int32 = 3
 	FailedJobHistoryLimit1     int32 = 1
+	TimeZoneShanghai                 = "Asia/Shanghai"
+	TimeZoneInvalid                  = "Invalid/Zone"
 
 	// "1520742896" is "2018/3/11 12:34:56" in "Asia/Shanghai".
 	ActiveRunningCronJob1LastScheduleTime          = time.Unix(1520742896, 0)
@@ -159,7 +162,7 @@ func TestCronJobStore(t *testing.T) {
 				# TYPE kube_cronjob_status_active gauge
                 # TYPE kube_cronjob_metadata_resource_version gauge
 				# TYPE kube_cronjob_status_last_schedule_time gauge
-				kube_cronjob_info{concurrency_policy="Forbid",cronjob="ActiveRunningCronJob1",namespace="ns1",schedule="0 */6 * * *"} 1
+				kube_cronjob_info{concurrency_policy="Forbid",cronjob="ActiveRunningCronJob1",namespace="ns1",schedule="0 */6 * * *",timezone=""} 1
 				kube_cronjob_annotations{annotation_app_k8s_io_owner="@foo",cronjob="ActiveRunningCronJob1",namespace="ns1"} 1
 				kube_cronjob_labels{cronjob="ActiveRunningCronJob1",namespace="ns1"} 1
 				kube_cronjob_spec_failed_job_history_limit{cronjob="ActiveRunningCronJob1",namespace="ns1"} 1
@@ -234,7 +237,7 @@ func TestCronJobStore(t *testing.T) {
                 # TYPE kube_cronjob_metadata_resource_version gauge
 				# TYPE kube_cronjob_status_last_schedule_time gauge
 				# TYPE kube_cronjob_status_last_successful_time gauge
-				kube_cronjob_info{concurrency_policy="Forbid",cronjob="SuspendedCronJob1",namespace="ns1",schedule="0 */3 * * *"} 1
+				kube_cronjob_info{concurrency_policy="Forbid",cronjob="SuspendedCronJob1",namespace="ns1",schedule="0 */3 * * *",timezone=""} 1
 				kube_cronjob_labels{cronjob="SuspendedCronJob1",namespace="ns1"} 1
 				kube_cronjob_spec_failed_job_history_limit{cronjob="SuspendedCronJob1",namespace="ns1"} 1
 				kube_cronjob_spec_starting_deadline_seconds{cronjob="SuspendedCronJob1",namespace="ns1"} 300
@@ -294,7 +297,7 @@ func TestCronJobStore(t *testing.T) {
                 # TYPE kube_cronjob_metadata_resource_version gauge
 				# TYPE kube_cronjob_status_last_schedule_time gauge
 				# TYPE kube_cronjob_status_last_successful_time gauge
-				kube_cronjob_info{concurrency_policy="Forbid",cronjob="SuspendedCronJob1",namespace="ns1",schedule="0 */3 * * *"} 1
+				kube_cronjob_info{concurrency_policy="Forbid",cronjob="SuspendedCronJob1",namespace="ns1",schedule="0 */3 * * *",timezone=""} 1
 				kube_cronjob_labels{cronjob="SuspendedCronJob1",namespace="ns1"} 1
 				kube_cronjob_spec_failed_job_history_limit{cronjob="SuspendedCronJob1",namespace="ns1"} 1
 				kube_cronjob_spec_starting_deadline_seconds{cronjob="SuspendedCronJob1",namespace="ns1"} 300
@@ -362,7 +365,7 @@ func TestCronJobStore(t *testing.T) {
 				kube_cronjob_spec_failed_job_history_limit{cronjob="ActiveCronJob1NoLastScheduled",namespace="ns1"} 1
 				kube_cronjob_spec_successful_job_history_limit{cronjob="ActiveCronJob1NoLastScheduled",namespace="ns1"} 3
 				kube_cronjob_spec_suspend{cronjob="ActiveCronJob1NoLastScheduled",namespace="ns1"} 0
-				kube_cronjob_info{concurrency_policy="Forbid",cronjob="ActiveCronJob1NoLastScheduled",namespace="ns1",schedule="25 * * * *"} 1
+				kube_cronjob_info{concurrency_policy="Forbid",cronjob="ActiveCronJob1NoLastScheduled",namespace="ns1",schedule="25 * * * *",timezone=""} 1
 				kube_cronjob_created{cronjob="ActiveCronJob1NoLastScheduled",namespace="ns1"} 1.520766296e+09
 				kube_cronjob_labels{cronjob="ActiveCronJob1NoLastScheduled",namespace="ns1"} 1
 ` +
@@ -370,6 +373,61 @@ func TestCronJobStore(t *testing.T) {
 					float64(ActiveCronJob1NoLastScheduledNextScheduleTime.Unix())/math.Pow10(9)),
 			MetricNames: []string{"kube_cronjob_status_last_successful_time", "kube_cronjob_next_schedule_time", "kube_cronjob_spec_starting_deadline_seconds", "kube_cronjob_status_active", "kube_cronjob_metadata_resource_version", "kube_cronjob_spec_suspend", "kube_cronjob_info", "kube_cronjob_created", "kube_cronjob_labels", "kube_cronjob_spec_successful_job_history_limit", "kube_cronjob_spec_failed_job_history_limit"},
 		},
+		{
+			Obj: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "TimeZoneCronJob1",
+					Namespace: "ns1",
+				},
+				Status: batchv1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: ActiveRunningCronJob1LastScheduleTime},
+				},
+				Spec: batchv1.CronJobSpec{
+					ConcurrencyPolicy: "Forbid",
+					Suspend:           &SuspendFalse,
+					Schedule:          "0 6 * * *",
+					TimeZone:          &TimeZoneShanghai,
+				},
+			},
+			Want: `
+				# HELP kube_cronjob_info [STABLE] Info about cronjob.
+				# HELP kube_cronjob_next_schedule_time [STABLE] Next time the cronjob should be scheduled. The time after lastScheduleTime, or after the cron job's creation time if it's never been scheduled. Use this to determine if the job is delayed.
+				# HELP kube_cronjob_upcoming_schedule_time Upcoming times the cronjob should be scheduled in the time zone of the cronjob, starting with the next schedule time.
+				# TYPE kube_cronjob_info gauge
+				# TYPE kube_cronjob_next_schedule_time gauge
+				# TYPE kube_cronjob_upcoming_schedule_time gauge
+				kube_cronjob_info{concurrency_policy="Forbid",cronjob="TimeZoneCronJob1",namespace="ns1",schedule="0 6 * * *",timezone="Asia/Shanghai"} 1
+				kube_cronjob_next_schedule_time{cronjob="TimeZoneCronJob1",namespace="ns1"} 1.5208056e+09
+				kube_cronjob_upcoming_schedule_time{cronjob="TimeZoneCronJob1",namespace="ns1",occurrence="1"} 1.5208056e+09
+				kube_cronjob_upcoming_schedule_time{cronjob="TimeZoneCronJob1",namespace="ns1",occurrence="2"} 1.520892e+09
+				kube_cronjob_upcoming_schedule_time{cronjob="TimeZoneCronJob1",namespace="ns1",occurrence="3"} 1.5209784e+09
+			`,
+			MetricNames: []string{"kube_cronjob_info", "kube_cronjob_next_schedule_time", "kube_cronjob_upcoming_schedule_time"},
+		},
+		{
+			Obj: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "InvalidTimeZoneCronJob1",
+					Namespace: "ns1",
+				},
+				Status: batchv1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: ActiveRunningCronJob1LastScheduleTime},
+				},
+				Spec: batchv1.CronJobSpec{
+					ConcurrencyPolicy: "Forbid",
+					Suspend:           &SuspendFalse,
+					Schedule:          "0 6 * * *",
+					TimeZone:          &TimeZoneInvalid,
+				},
+			},
+			Want: `
+				# HELP kube_cronjob_next_schedule_time [STABLE] Next time the cronjob should be scheduled. The time after lastScheduleTime, or after the cron job's creation time if it's never been scheduled. Use this to determine if the job is delayed.
+				# HELP kube_cronjob_upcoming_schedule_time Upcoming times the cronjob should be scheduled in the time zone of the cronjob, starting with the next schedule time.
+				# TYPE kube_cronjob_next_schedule_time gauge
+				# TYPE kube_cronjob_upcoming_schedule_time gauge
+			`,
+			MetricNames: []string{"kube_cronjob_next_schedule_time", "kube_cronjob_upcoming_schedule_time"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(cronJobMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
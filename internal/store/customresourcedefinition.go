@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descCustomResourceDefinitionAnnotationsName     = "kube_customresourcedefinition_annotations"
+	descCustomResourceDefinitionAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descCustomResourceDefinitionLabelsName          = "kube_customresourcedefinition_labels"
+	descCustomResourceDefinitionLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descCustomResourceDefinitionLabelsDefaultLabels = []string{"customresourcedefinition"}
+
+	apiextensionsGroupVersion = schema.GroupVersion{Group: "apiextensions.k8s.io", Version: "v1"}
+)
+
+// customResourceDefinition is the subset of the fields of a
+// CustomResourceDefinition of the version v1 of apiextensions.k8s.io which the
+// metrics are derived from. It is watched as unstructured object.
+type customResourceDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind   string `json:"kind"`
+			Plural string `json:"plural"`
+		} `json:"names"`
+		Scope    string `json:"scope"`
+		Versions []struct {
+			Name    string `json:"name"`
+			Served  bool   `json:"served"`
+			Storage bool   `json:"storage"`
+		} `json:"versions"`
+	} `json:"spec"`
+	Status struct {
+		Conditions     []metav1.Condition `json:"conditions"`
+		StoredVersions []string           `json:"storedVersions"`
+	} `json:"status"`
+}
+
+func customResourceDefinitionMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_customresourcedefinition_info",
+			"Information about customresourcedefinition.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCustomResourceDefinitionFunc(func(c *customResourceDefinition) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"group", "kind", "plural", "scope"},
+							LabelValues: []string{c.Spec.Group, c.Spec.Names.Kind, c.Spec.Names.Plural, c.Spec.Scope},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_customresourcedefinition_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCustomResourceDefinitionFunc(func(c *customResourceDefinition) *metric.Family {
+				ms := []*metric.Metric{}
+				if !c.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(c.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descCustomResourceDefinitionAnnotationsName,
+			descCustomResourceDefinitionAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCustomResourceDefinitionFunc(func(c *customResourceDefinition) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", c.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descCustomResourceDefinitionLabelsName,
+			descCustomResourceDefinitionLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCustomResourceDefinitionFunc(func(c *customResourceDefinition) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", c.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_customresourcedefinition_version_info",
+			"Information about the versions of the customresourcedefinition.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCustomResourceDefinitionFunc(func(c *customResourceDefinition) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(c.Spec.Versions))
+				for _, v := range c.Spec.Versions {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"version", "served", "storage"},
+						LabelValues: []string{v.Name, strconv.FormatBool(v.Served), strconv.FormatBool(v.Storage)},
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_customresourcedefinition_stored_version_info",
+			"The versions the custom resources were ever persisted in, as listed in status.storedVersions.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCustomResourceDefinitionFunc(func(c *customResourceDefinition) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(c.Status.StoredVersions))
+				for _, v := range c.Status.StoredVersions {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"version"},
+						LabelValues: []string{v},
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_customresourcedefinition_status_condition",
+			"The condition of a customresourcedefinition, e.g. Established, NamesAccepted or Terminating.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCustomResourceDefinitionFunc(func(c *customResourceDefinition) *metric.Family {
+				return &metric.Family{
+					Metrics: addStatusConditionMetrics(c.Status.Conditions, nil, nil),
+				}
+			}),
+		),
+	}
+}
+
+func wrapCustomResourceDefinitionFunc(f func(*customResourceDefinition) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		c := &customResourceDefinition{}
+		fromUnstructured(obj, c)
+
+		metricFamily := f(c)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descCustomResourceDefinitionLabelsDefaultLabels, []string{c.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestCustomResourceDefinitionStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: mustUnstructured(t, `{
+				"apiVersion": "apiextensions.k8s.io/v1",
+				"kind": "CustomResourceDefinition",
+				"metadata": {"name": "foos.example.com", "creationTimestamp": "2017-08-01T06:30:18Z"},
+				"spec": {
+					"group": "example.com",
+					"names": {"kind": "Foo", "plural": "foos"},
+					"scope": "Namespaced",
+					"versions": [
+						{"name": "v1beta1", "served": false, "storage": false},
+						{"name": "v1", "served": true, "storage": true}
+					]
+				},
+				"status": {
+					"conditions": [
+						{"type": "Established", "status": "True", "lastTransitionTime": "2017-08-01T06:30:18Z", "reason": "InitialNamesAccepted", "message": ""},
+						{"type": "Terminating", "status": "False", "lastTransitionTime": "2017-08-01T06:30:18Z", "reason": "", "message": ""}
+					],
+					"storedVersions": ["v1beta1", "v1"]
+				}
+			}`),
+			Want: `
+				# HELP kube_customresourcedefinition_created Unix creation timestamp
+				# HELP kube_customresourcedefinition_info Information about customresourcedefinition.
+				# HELP kube_customresourcedefinition_status_condition The condition of a customresourcedefinition, e.g. Established, NamesAccepted or Terminating.
+				# HELP kube_customresourcedefinition_stored_version_info The versions the custom resources were ever persisted in, as listed in status.storedVersions.
+				# HELP kube_customresourcedefinition_version_info Information about the versions of the customresourcedefinition.
+				# TYPE kube_customresourcedefinition_created gauge
+				# TYPE kube_customresourcedefinition_info gauge
+				# TYPE kube_customresourcedefinition_status_condition gauge
+				# TYPE kube_customresourcedefinition_stored_version_info gauge
+				# TYPE kube_customresourcedefinition_version_info gauge
+				kube_customresourcedefinition_created{customresourcedefinition="foos.example.com"} 1.501569018e+09
+				kube_customresourcedefinition_info{customresourcedefinition="foos.example.com",group="example.com",kind="Foo",plural="foos",scope="Namespaced"} 1
+				kube_customresourcedefinition_status_condition{condition="Established",customresourcedefinition="foos.example.com",status="false"} 0
+				kube_customresourcedefinition_status_condition{condition="Established",customresourcedefinition="foos.example.com",status="true"} 1
+				kube_customresourcedefinition_status_condition{condition="Established",customresourcedefinition="foos.example.com",status="unknown"} 0
+				kube_customresourcedefinition_status_condition{condition="Terminating",customresourcedefinition="foos.example.com",status="false"} 1
+				kube_customresourcedefinition_status_condition{condition="Terminating",customresourcedefinition="foos.example.com",status="true"} 0
+				kube_customresourcedefinition_status_condition{condition="Terminating",customresourcedefinition="foos.example.com",status="unknown"} 0
+				kube_customresourcedefinition_stored_version_info{customresourcedefinition="foos.example.com",version="v1"} 1
+				kube_customresourcedefinition_stored_version_info{customresourcedefinition="foos.example.com",version="v1beta1"} 1
+				kube_customresourcedefinition_version_info{customresourcedefinition="foos.example.com",served="false",storage="false",version="v1beta1"} 1
+				kube_customresourcedefinition_version_info{customresourcedefinition="foos.example.com",served="true",storage="true",version="v1"} 1
+			`,
+			MetricNames: []string{
+				"kube_customresourcedefinition_created",
+				"kube_customresourcedefinition_info",
+				"kube_customresourcedefinition_status_condition",
+				"kube_customresourcedefinition_stored_version_info",
+				"kube_customresourcedefinition_version_info",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(customResourceDefinitionMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(customResourceDefinitionMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
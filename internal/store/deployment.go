@@ -39,6 +39,16 @@ var (
 	descDeploymentLabelsName          = "kube_deployment_labels"
 	descDeploymentLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
 	descDeploymentLabelsDefaultLabels = []string{"namespace", "deployment"}
+
+	// deploymentConditionReasons are the reasons the deployment controller
+	// sets for the Available and Progressing conditions.
+	deploymentConditionReasons = map[v1.DeploymentConditionType][]string{
+		v1.DeploymentAvailable: {"MinimumReplicasAvailable", "MinimumReplicasUnavailable"},
+		v1.DeploymentProgressing: {
+			"NewReplicaSetCreated", "FoundNewReplicaSet", "ReplicaSetUpdated", "NewReplicaSetAvailable",
+			"ProgressDeadlineExceeded", "DeploymentPaused", "DeploymentResumed", "ReplicaSetCreateError",
+		},
+	}
 )
 
 func deploymentMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
@@ -185,6 +195,61 @@ func deploymentMetricFamilies(allowAnnotationsList, allowLabelsList []string) []
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_deployment_status_condition_reason",
+			"The reason of the Available and Progressing status conditions of a deployment.",
+			metric.Gauge,
+			"",
+			wrapDeploymentFunc(func(d *v1.Deployment) *metric.Family {
+				ms := []*metric.Metric{}
+
+				for _, c := range d.Status.Conditions {
+					reasons, ok := deploymentConditionReasons[c.Type]
+					if !ok || c.Reason == "" {
+						continue
+					}
+					known := false
+					for _, reason := range reasons {
+						known = known || reason == c.Reason
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"condition", "reason"},
+							LabelValues: []string{string(c.Type), reason},
+							Value:       boolFloat64(reason == c.Reason),
+						})
+					}
+					if !known {
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"condition", "reason"},
+							LabelValues: []string{string(c.Type), c.Reason},
+							Value:       1,
+						})
+					}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_deployment_spec_progress_deadline_seconds",
+			"The maximum time in seconds for a deployment to make progress before it is considered to be failed.",
+			metric.Gauge,
+			"",
+			wrapDeploymentFunc(func(d *v1.Deployment) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if d.Spec.ProgressDeadlineSeconds != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(*d.Spec.ProgressDeadlineSeconds),
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_deployment_spec_replicas",
 			"Number of desired pods for a deployment.",
@@ -37,6 +37,8 @@ var (
 
 	depl1MaxSurge = intstr.FromInt(10)
 	depl2MaxSurge = intstr.FromString("20%")
+
+	depl3ProgressDeadlineSeconds int32 = 600
 )
 
 func TestDeploymentStore(t *testing.T) {
@@ -45,6 +47,10 @@ func TestDeploymentStore(t *testing.T) {
 	const metadata = `
 		# HELP kube_deployment_annotations Kubernetes annotations converted to Prometheus labels.
 		# TYPE kube_deployment_annotations gauge
+		# HELP kube_deployment_spec_progress_deadline_seconds The maximum time in seconds for a deployment to make progress before it is considered to be failed.
+		# TYPE kube_deployment_spec_progress_deadline_seconds gauge
+		# HELP kube_deployment_status_condition_reason The reason of the Available and Progressing status conditions of a deployment.
+		# TYPE kube_deployment_status_condition_reason gauge
 		# HELP kube_deployment_created [STABLE] Unix creation timestamp
 		# TYPE kube_deployment_created gauge
 		# HELP kube_deployment_metadata_generation [STABLE] Sequence number representing a specific generation of the desired state.
@@ -194,6 +200,46 @@ func TestDeploymentStore(t *testing.T) {
         kube_deployment_status_condition{deployment="depl2",namespace="ns2",condition="ReplicaFailure",status="unknown"} 0
 `,
 		},
+		{
+			Obj: &v1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "depl3",
+					Namespace: "ns3",
+				},
+				Spec: v1.DeploymentSpec{
+					Replicas:                &depl2Replicas,
+					ProgressDeadlineSeconds: &depl3ProgressDeadlineSeconds,
+				},
+				Status: v1.DeploymentStatus{
+					Conditions: []v1.DeploymentCondition{
+						{Type: v1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+						{Type: v1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+						{Type: v1.DeploymentReplicaFailure, Status: corev1.ConditionTrue, Reason: "FailedCreate"},
+					},
+				},
+			},
+			Want: `
+        # HELP kube_deployment_spec_progress_deadline_seconds The maximum time in seconds for a deployment to make progress before it is considered to be failed.
+        # HELP kube_deployment_status_condition_reason The reason of the Available and Progressing status conditions of a deployment.
+        # TYPE kube_deployment_spec_progress_deadline_seconds gauge
+        # TYPE kube_deployment_status_condition_reason gauge
+        kube_deployment_spec_progress_deadline_seconds{deployment="depl3",namespace="ns3"} 600
+        kube_deployment_status_condition_reason{condition="Available",deployment="depl3",namespace="ns3",reason="MinimumReplicasAvailable"} 1
+        kube_deployment_status_condition_reason{condition="Available",deployment="depl3",namespace="ns3",reason="MinimumReplicasUnavailable"} 0
+        kube_deployment_status_condition_reason{condition="Progressing",deployment="depl3",namespace="ns3",reason="DeploymentPaused"} 0
+        kube_deployment_status_condition_reason{condition="Progressing",deployment="depl3",namespace="ns3",reason="DeploymentResumed"} 0
+        kube_deployment_status_condition_reason{condition="Progressing",deployment="depl3",namespace="ns3",reason="FoundNewReplicaSet"} 0
+        kube_deployment_status_condition_reason{condition="Progressing",deployment="depl3",namespace="ns3",reason="NewReplicaSetAvailable"} 0
+        kube_deployment_status_condition_reason{condition="Progressing",deployment="depl3",namespace="ns3",reason="NewReplicaSetCreated"} 0
+        kube_deployment_status_condition_reason{condition="Progressing",deployment="depl3",namespace="ns3",reason="ProgressDeadlineExceeded"} 1
+        kube_deployment_status_condition_reason{condition="Progressing",deployment="depl3",namespace="ns3",reason="ReplicaSetCreateError"} 0
+        kube_deployment_status_condition_reason{condition="Progressing",deployment="depl3",namespace="ns3",reason="ReplicaSetUpdated"} 0
+`,
+			MetricNames: []string{
+				"kube_deployment_spec_progress_deadline_seconds",
+				"kube_deployment_status_condition_reason",
+			},
+		},
 	}
 
 	for i, c := range cases {
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestDeviceClassStore(t *testing.T) {
+	startTime := 1501569018
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &resourcev1.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "gpu.example.com",
+					CreationTimestamp: metav1.Time{Time: time.Unix(int64(startTime), 0)},
+					Labels: map[string]string{
+						"vendor": "example",
+					},
+				},
+			},
+			AllowLabelsList: []string{"vendor"},
+			Want: `
+				# HELP kube_deviceclass_created Unix creation timestamp
+				# HELP kube_deviceclass_labels Kubernetes labels converted to Prometheus labels.
+				# TYPE kube_deviceclass_created gauge
+				# TYPE kube_deviceclass_labels gauge
+				kube_deviceclass_created{deviceclass="gpu.example.com"} 1.501569018e+09
+				kube_deviceclass_labels{deviceclass="gpu.example.com",label_vendor="example"} 1
+			`,
+			MetricNames: []string{
+				"kube_deviceclass_created",
+				"kube_deviceclass_labels",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(deviceClassMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(deviceClassMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -15,6 +15,7 @@ package store
 
 import (
 	"context"
+	"sort"
 	"strconv"
 
 	basemetrics "k8s.io/component-base/metrics"
@@ -147,6 +148,87 @@ func endpointSliceMetricFamilies(allowAnnotationsList, allowLabelsList []string)
 			}),
 		),
 
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_endpointslice_hints",
+			"Topology aware routing hints of the endpoints of the endpointslice.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapEndpointSliceFunc(func(e *discoveryv1.EndpointSlice) *metric.Family {
+				m := []*metric.Metric{}
+				for _, ep := range e.Endpoints {
+					if ep.Hints == nil {
+						continue
+					}
+					for _, z := range ep.Hints.ForZones {
+						for _, address := range ep.Addresses {
+							m = append(m, &metric.Metric{
+								LabelKeys:   []string{"address", "for_zone", "for_node"},
+								LabelValues: []string{address, z.Name, ""},
+								Value:       1,
+							})
+						}
+					}
+					for _, n := range ep.Hints.ForNodes {
+						for _, address := range ep.Addresses {
+							m = append(m, &metric.Metric{
+								LabelKeys:   []string{"address", "for_zone", "for_node"},
+								LabelValues: []string{address, "", n.Name},
+								Value:       1,
+							})
+						}
+					}
+				}
+				return &metric.Family{
+					Metrics: m,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_endpointslice_zone_endpoints",
+			"Number of endpoints of the endpointslice per zone, by the zone of the endpoints and by the zone they are hinted for.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapEndpointSliceFunc(func(e *discoveryv1.EndpointSlice) *metric.Family {
+				type zoneKey struct {
+					zone, source string
+				}
+				counts := map[zoneKey]int{}
+				for _, ep := range e.Endpoints {
+					if ep.Zone != nil {
+						counts[zoneKey{*ep.Zone, "endpoint"}]++
+					}
+					if ep.Hints != nil {
+						for _, z := range ep.Hints.ForZones {
+							counts[zoneKey{z.Name, "hint"}]++
+						}
+					}
+				}
+				keys := make([]zoneKey, 0, len(counts))
+				for k := range counts {
+					keys = append(keys, k)
+				}
+				sort.Slice(keys, func(i, j int) bool {
+					if keys[i].zone != keys[j].zone {
+						return keys[i].zone < keys[j].zone
+					}
+					return keys[i].source < keys[j].source
+				})
+
+				m := make([]*metric.Metric, 0, len(keys))
+				for _, k := range keys {
+					m = append(m, &metric.Metric{
+						LabelKeys:   []string{"zone", "source"},
+						LabelValues: []string{k.zone, k.source},
+						Value:       float64(counts[k]),
+					})
+				}
+				return &metric.Family{
+					Metrics: m,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_endpointslice_ports",
 			"Ports attached to the endpointslice.",
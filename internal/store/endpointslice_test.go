@@ -28,6 +28,8 @@ func TestEndpointSliceStore(t *testing.T) {
 	metav1StartTime := metav1.Unix(int64(startTime), 0)
 	portname := "http"
 	portnumber := int32(80)
+	zoneA := "zone-a"
+	zoneB := "zone-b"
 	portprotocol := corev1.Protocol("TCP")
 	nodename := "node"
 	hostname := "host"
@@ -150,6 +152,51 @@ func TestEndpointSliceStore(t *testing.T) {
 				"kube_endpointslice_annotations", "kube_endpointslice_labels",
 			},
 		},
+		{
+			Obj: &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test_endpointslice-hints",
+					Namespace: "ns1",
+				},
+				AddressType: "IPv4",
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"10.0.0.1"},
+						Zone:      &zoneA,
+						Hints:     &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: zoneA}}},
+					},
+					{
+						Addresses: []string{"10.0.0.2"},
+						Zone:      &zoneA,
+						Hints: &discoveryv1.EndpointHints{
+							ForZones: []discoveryv1.ForZone{{Name: zoneB}},
+							ForNodes: []discoveryv1.ForNode{{Name: "node-a"}},
+						},
+					},
+					{
+						Addresses: []string{"10.0.0.3"},
+						Zone:      &zoneB,
+					},
+				},
+			},
+			Want: `
+					# HELP kube_endpointslice_hints Topology aware routing hints of the endpoints of the endpointslice.
+					# HELP kube_endpointslice_zone_endpoints Number of endpoints of the endpointslice per zone, by the zone of the endpoints and by the zone they are hinted for.
+					# TYPE kube_endpointslice_hints gauge
+					# TYPE kube_endpointslice_zone_endpoints gauge
+					kube_endpointslice_hints{address="10.0.0.1",endpointslice="test_endpointslice-hints",for_node="",for_zone="zone-a"} 1
+					kube_endpointslice_hints{address="10.0.0.2",endpointslice="test_endpointslice-hints",for_node="",for_zone="zone-b"} 1
+					kube_endpointslice_hints{address="10.0.0.2",endpointslice="test_endpointslice-hints",for_node="node-a",for_zone=""} 1
+					kube_endpointslice_zone_endpoints{endpointslice="test_endpointslice-hints",source="endpoint",zone="zone-a"} 2
+					kube_endpointslice_zone_endpoints{endpointslice="test_endpointslice-hints",source="hint",zone="zone-a"} 1
+					kube_endpointslice_zone_endpoints{endpointslice="test_endpointslice-hints",source="endpoint",zone="zone-b"} 1
+					kube_endpointslice_zone_endpoints{endpointslice="test_endpointslice-hints",source="hint",zone="zone-b"} 1
+				`,
+			MetricNames: []string{
+				"kube_endpointslice_hints",
+				"kube_endpointslice_zone_endpoints",
+			},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(endpointSliceMetricFamilies(c.AllowAnnotationsList, nil))
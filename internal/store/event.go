@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+// eventCountMaxSeries is the maximum number of series of kube_event_count_total
+// of each store. Events which would add further series are counted in the
+// series with empty labels instead.
+const eventCountMaxSeries = 1000
+
+var eventCountLabels = []string{"namespace", "involved_kind", "reason", "type"}
+
+// eventCount is the number of occurrences of the events with the same labels.
+// It is added to the MetricsStore of the events in place of the events.
+type eventCount struct {
+	metav1.ObjectMeta
+	labelValues []string
+	count       float64
+}
+
+func eventMetricFamilies() []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_event_count_total",
+			"Number of occurrences of events observed since kube-state-metrics started, by namespace, kind of the involved object, reason and type.",
+			metric.Counter,
+			basemetrics.ALPHA,
+			"",
+			func(obj interface{}) *metric.Family {
+				c := obj.(*eventCount)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   eventCountLabels,
+							LabelValues: c.labelValues,
+							Value:       c.count,
+						},
+					},
+				}
+			},
+		),
+	}
+}
+
+// eventCountStore counts the occurrences of the events it is given by their
+// labels and writes the counts to a MetricsStore. Unlike the events, the
+// counts are never removed, so that they only increase.
+type eventCountStore struct {
+	metrics *metricsstore.MetricsStore
+
+	mtx sync.Mutex
+	// events are the last observed counts of the events by their UID.
+	events map[types.UID]int32
+	counts map[string]*eventCount
+}
+
+func newEventCountStore(metrics *metricsstore.MetricsStore) *eventCountStore {
+	return &eventCountStore{
+		metrics: metrics,
+		events:  map[types.UID]int32{},
+		counts:  map[string]*eventCount{},
+	}
+}
+
+// eventOccurrences returns the number of occurrences of the event, which is
+// kept in the series of the event by newer clients.
+func eventOccurrences(e *v1.Event) int32 {
+	n := e.Count
+	if e.Series != nil && e.Series.Count > n {
+		n = e.Series.Count
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// observe counts the occurrences of the event since it was observed last and
+// returns the updated count, if any.
+func (s *eventCountStore) observe(e *v1.Event) *eventCount {
+	n := eventOccurrences(e)
+	last, ok := s.events[e.UID]
+	s.events[e.UID] = n
+	if ok && n <= last {
+		return nil
+	}
+
+	labelValues := []string{e.Namespace, e.InvolvedObject.Kind, e.Reason, e.Type}
+	key := strings.Join(labelValues, "\xff")
+	c, ok := s.counts[key]
+	if !ok {
+		if len(s.counts) >= eventCountMaxSeries-1 {
+			labelValues = make([]string, len(eventCountLabels))
+			key = strings.Join(labelValues, "\xff")
+			if _, ok := s.counts[key]; !ok {
+				klog.InfoS("Reached the maximum number of event count series, counting further events without labels", "maxSeries", eventCountMaxSeries)
+			}
+		}
+		if c, ok = s.counts[key]; !ok {
+			c = &eventCount{ObjectMeta: metav1.ObjectMeta{UID: types.UID(key)}, labelValues: labelValues}
+			s.counts[key] = c
+		}
+	}
+	c.count += float64(n - last)
+	return c
+}
+
+func (s *eventCountStore) counted(obj interface{}) error {
+	e, ok := obj.(*v1.Event)
+	if !ok {
+		return nil
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if c := s.observe(e); c != nil {
+		return s.metrics.Add(c)
+	}
+	return nil
+}
+
+// Add implements the Add method of the store interface.
+func (s *eventCountStore) Add(obj interface{}) error {
+	return s.counted(obj)
+}
+
+// Update implements the Update method of the store interface.
+func (s *eventCountStore) Update(obj interface{}) error {
+	return s.counted(obj)
+}
+
+// Delete implements the Delete method of the store interface. It only
+// forgets the event, its occurrences stay counted.
+func (s *eventCountStore) Delete(obj interface{}) error {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+	e, ok := obj.(*v1.Event)
+	if !ok {
+		return nil
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.events, e.UID)
+	return nil
+}
+
+// List implements the List method of the store interface.
+func (s *eventCountStore) List() []interface{} {
+	return nil
+}
+
+// ListKeys implements the ListKeys method of the store interface.
+func (s *eventCountStore) ListKeys() []string {
+	return nil
+}
+
+// Get implements the Get method of the store interface.
+func (s *eventCountStore) Get(obj interface{}) (item interface{}, exists bool, err error) {
+	return nil, false, nil
+}
+
+// GetByKey implements the GetByKey method of the store interface.
+func (s *eventCountStore) GetByKey(key string) (item interface{}, exists bool, err error) {
+	return nil, false, nil
+}
+
+// Replace counts the occurrences of the given events and forgets all other
+// events.
+func (s *eventCountStore) Replace(list []interface{}, resourceVersion string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	uids := make(map[types.UID]struct{}, len(list))
+	for _, obj := range list {
+		if e, ok := obj.(*v1.Event); ok {
+			uids[e.UID] = struct{}{}
+			s.observe(e)
+		}
+	}
+	for uid := range s.events {
+		if _, ok := uids[uid]; !ok {
+			delete(s.events, uid)
+		}
+	}
+
+	counts := make([]interface{}, 0, len(s.counts))
+	for _, c := range s.counts {
+		counts = append(counts, c)
+	}
+	return s.metrics.Replace(counts, resourceVersion)
+}
+
+// Resync implements the Resync method of the store interface.
+func (s *eventCountStore) Resync() error {
+	return nil
+}
+
+func createEventListWatch(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = fieldSelector
+			return kubeClient.CoreV1().Events(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = fieldSelector
+			return kubeClient.CoreV1().Events(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+func newTestEvent(uid, reason string, count int32) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: uid, Namespace: "default", UID: types.UID(uid)},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pod1"},
+		Reason:         reason,
+		Type:           v1.EventTypeWarning,
+		Count:          count,
+	}
+}
+
+func TestEventCountStore(t *testing.T) {
+	families := eventMetricFamilies()
+	metrics := metricsstore.NewMetricsStore(generator.ExtractMetricFamilyHeaders(families), generator.ComposeMetricGenFuncs(families))
+	s := newEventCountStore(metrics)
+
+	const header = `# HELP kube_event_count_total Number of occurrences of events observed since kube-state-metrics started, by namespace, kind of the involved object, reason and type.
+# TYPE kube_event_count_total counter
+`
+	series := func(reason string, value int) string {
+		return fmt.Sprintf("kube_event_count_total{namespace=\"default\",involved_kind=\"Pod\",reason=%q,type=\"Warning\"} %d\n", reason, value)
+	}
+
+	tests := []struct {
+		desc string
+		f    func() error
+		want []string
+	}{
+		{
+			desc: "new events are counted with all their occurrences",
+			f:    func() error { return s.Add(newTestEvent("a", "BackOff", 3)) },
+			want: []string{series("BackOff", 3)},
+		},
+		{
+			desc: "updated events are counted with their new occurrences",
+			f:    func() error { return s.Update(newTestEvent("a", "BackOff", 5)) },
+			want: []string{series("BackOff", 5)},
+		},
+		{
+			desc: "events with the same labels are summed",
+			f:    func() error { return s.Add(newTestEvent("b", "BackOff", 0)) },
+			want: []string{series("BackOff", 6)},
+		},
+		{
+			desc: "deleted events stay counted",
+			f:    func() error { return s.Delete(newTestEvent("a", "BackOff", 5)) },
+			want: []string{series("BackOff", 6)},
+		},
+		{
+			desc: "events with other labels are counted separately",
+			f:    func() error { return s.Add(newTestEvent("c", "FailedScheduling", 1)) },
+			want: []string{series("BackOff", 6), series("FailedScheduling", 1)},
+		},
+		{
+			desc: "relisted events are only counted with their new occurrences",
+			f: func() error {
+				return s.Replace([]interface{}{newTestEvent("b", "BackOff", 2), newTestEvent("c", "FailedScheduling", 1)}, "")
+			},
+			want: []string{series("BackOff", 7), series("FailedScheduling", 1)},
+		},
+	}
+	for _, test := range tests {
+		if err := test.f(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.desc, err)
+		}
+		buf := &bytes.Buffer{}
+		if err := metricsstore.NewMetricsWriter(metrics).WriteAll(buf); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.desc, err)
+		}
+		got := strings.TrimPrefix(buf.String(), header)
+		for _, want := range test.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: expected %q in:\n%s", test.desc, want, got)
+			}
+		}
+		if n := strings.Count(got, "\n"); n != len(test.want) {
+			t.Errorf("%s: expected %d series, got:\n%s", test.desc, len(test.want), got)
+		}
+	}
+	if len(s.events) != 2 {
+		t.Errorf("expected the deleted event to be forgotten, got %v", s.events)
+	}
+}
+
+func TestEventCountStoreMaxSeries(t *testing.T) {
+	families := eventMetricFamilies()
+	metrics := metricsstore.NewMetricsStore(generator.ExtractMetricFamilyHeaders(families), generator.ComposeMetricGenFuncs(families))
+	s := newEventCountStore(metrics)
+
+	for i := 0; i < eventCountMaxSeries+10; i++ {
+		if err := s.Add(newTestEvent(fmt.Sprint(i), fmt.Sprintf("Reason%d", i), 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.counts) != eventCountMaxSeries {
+		t.Errorf("expected %d series, got %d", eventCountMaxSeries, len(s.counts))
+	}
+	if c := s.counts[strings.Repeat("\xff", len(eventCountLabels)-1)]; c == nil || c.count != 11 {
+		t.Errorf("expected 11 events to be counted without labels, got %v", c)
+	}
+}
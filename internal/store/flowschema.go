@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descFlowSchemaAnnotationsName     = "kube_flowschema_annotations"
+	descFlowSchemaAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descFlowSchemaLabelsName          = "kube_flowschema_labels"
+	descFlowSchemaLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descFlowSchemaLabelsDefaultLabels = []string{"flowschema"}
+)
+
+func flowSchemaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_flowschema_info",
+			"Information about flowschema.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapFlowSchemaFunc(func(f *flowcontrolv1.FlowSchema) *metric.Family {
+				distinguisherMethod := ""
+				if f.Spec.DistinguisherMethod != nil {
+					distinguisherMethod = string(f.Spec.DistinguisherMethod.Type)
+				}
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"priority_level_configuration", "distinguisher_method"},
+							LabelValues: []string{f.Spec.PriorityLevelConfiguration.Name, distinguisherMethod},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_flowschema_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapFlowSchemaFunc(func(f *flowcontrolv1.FlowSchema) *metric.Family {
+				ms := []*metric.Metric{}
+				if !f.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(f.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descFlowSchemaAnnotationsName,
+			descFlowSchemaAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapFlowSchemaFunc(func(f *flowcontrolv1.FlowSchema) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", f.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descFlowSchemaLabelsName,
+			descFlowSchemaLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapFlowSchemaFunc(func(f *flowcontrolv1.FlowSchema) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", f.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_flowschema_matching_precedence",
+			"The matching precedence of the flowschema. Requests are classified by the matching flowschema with the lowest matching precedence.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapFlowSchemaFunc(func(f *flowcontrolv1.FlowSchema) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(f.Spec.MatchingPrecedence),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_flowschema_status_condition",
+			"The condition of the flowschema, e.g. whether it references a missing priority level configuration.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapFlowSchemaFunc(func(f *flowcontrolv1.FlowSchema) *metric.Family {
+				ms := []*metric.Metric{}
+				for _, c := range f.Status.Conditions {
+					for _, m := range addConditionMetrics(v1.ConditionStatus(c.Status)) {
+						m.LabelKeys = []string{"condition", "status"}
+						m.LabelValues = append([]string{string(c.Type)}, m.LabelValues...)
+						ms = append(ms, m)
+					}
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+	}
+}
+
+func wrapFlowSchemaFunc(f func(*flowcontrolv1.FlowSchema) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		flowSchema := obj.(*flowcontrolv1.FlowSchema)
+
+		metricFamily := f(flowSchema)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descFlowSchemaLabelsDefaultLabels, []string{flowSchema.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
+
+func createFlowSchemaListWatch(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.FlowcontrolV1().FlowSchemas().List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.FlowcontrolV1().FlowSchemas().Watch(context.TODO(), opts)
+		},
+	}
+}
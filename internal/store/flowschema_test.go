@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestFlowSchemaStore(t *testing.T) {
+	startTime := 1501569018
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &flowcontrolv1.FlowSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "workload-leader-election",
+				},
+				Spec: flowcontrolv1.FlowSchemaSpec{
+					PriorityLevelConfiguration: flowcontrolv1.PriorityLevelConfigurationReference{Name: "leader-election"},
+					MatchingPrecedence:         200,
+					DistinguisherMethod:        &flowcontrolv1.FlowDistinguisherMethod{Type: flowcontrolv1.FlowDistinguisherMethodByUserType},
+				},
+				Status: flowcontrolv1.FlowSchemaStatus{
+					Conditions: []flowcontrolv1.FlowSchemaCondition{
+						{Type: flowcontrolv1.FlowSchemaConditionDangling, Status: flowcontrolv1.ConditionFalse},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_flowschema_info Information about flowschema.
+				# HELP kube_flowschema_matching_precedence The matching precedence of the flowschema. Requests are classified by the matching flowschema with the lowest matching precedence.
+				# HELP kube_flowschema_status_condition The condition of the flowschema, e.g. whether it references a missing priority level configuration.
+				# TYPE kube_flowschema_info gauge
+				# TYPE kube_flowschema_matching_precedence gauge
+				# TYPE kube_flowschema_status_condition gauge
+				kube_flowschema_info{distinguisher_method="ByUser",flowschema="workload-leader-election",priority_level_configuration="leader-election"} 1
+				kube_flowschema_matching_precedence{flowschema="workload-leader-election"} 200
+				kube_flowschema_status_condition{condition="Dangling",flowschema="workload-leader-election",status="false"} 1
+				kube_flowschema_status_condition{condition="Dangling",flowschema="workload-leader-election",status="true"} 0
+				kube_flowschema_status_condition{condition="Dangling",flowschema="workload-leader-election",status="unknown"} 0
+			`,
+			MetricNames: []string{
+				"kube_flowschema_info",
+				"kube_flowschema_matching_precedence",
+				"kube_flowschema_status_condition",
+			},
+		},
+		{
+			Obj: &flowcontrolv1.FlowSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "exempt",
+					CreationTimestamp: metav1.Time{Time: time.Unix(int64(startTime), 0)},
+				},
+				Spec: flowcontrolv1.FlowSchemaSpec{
+					PriorityLevelConfiguration: flowcontrolv1.PriorityLevelConfigurationReference{Name: "exempt"},
+					MatchingPrecedence:         1,
+				},
+			},
+			Want: `
+				# HELP kube_flowschema_created Unix creation timestamp
+				# HELP kube_flowschema_info Information about flowschema.
+				# TYPE kube_flowschema_created gauge
+				# TYPE kube_flowschema_info gauge
+				kube_flowschema_created{flowschema="exempt"} 1.501569018e+09
+				kube_flowschema_info{distinguisher_method="",flowschema="exempt",priority_level_configuration="exempt"} 1
+			`,
+			MetricNames: []string{
+				"kube_flowschema_created",
+				"kube_flowschema_info",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(flowSchemaMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(flowSchemaMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
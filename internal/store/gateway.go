@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"strconv"
+
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descGatewayAnnotationsName     = "kube_gateway_annotations"
+	descGatewayAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descGatewayLabelsName          = "kube_gateway_labels"
+	descGatewayLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descGatewayLabelsDefaultLabels = []string{"namespace", "gateway"}
+)
+
+func gatewayMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gateway_info",
+			"Information about gateway.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"gatewayclass"},
+							LabelValues: []string{g.Spec.GatewayClassName},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gateway_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				ms := []*metric.Metric{}
+				if !g.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(g.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descGatewayAnnotationsName,
+			descGatewayAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", g.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descGatewayLabelsName,
+			descGatewayLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", g.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gateway_listeners",
+			"Number of listeners of the gateway.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(len(g.Spec.Listeners)),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gateway_listener_info",
+			"Information about the listeners of the gateway.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(g.Spec.Listeners))
+				for _, l := range g.Spec.Listeners {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"listener", "protocol", "port", "hostname"},
+						LabelValues: []string{l.Name, l.Protocol, strconv.FormatInt(int64(l.Port), 10), stringValue(l.Hostname)},
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gateway_listener_attached_routes",
+			"Number of routes attached to the listeners of the gateway.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(g.Status.Listeners))
+				for _, l := range g.Status.Listeners {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"listener"},
+						LabelValues: []string{l.Name},
+						Value:       float64(l.AttachedRoutes),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gateway_status_condition",
+			"The condition of the gateway.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				return &metric.Family{
+					Metrics: addStatusConditionMetrics(g.Status.Conditions, nil, nil),
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gateway_listener_status_condition",
+			"The condition of the listeners of the gateway.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayFunc(func(g *gateway) *metric.Family {
+				ms := []*metric.Metric{}
+				for _, l := range g.Status.Listeners {
+					ms = append(ms, addStatusConditionMetrics(l.Conditions, []string{"listener"}, []string{l.Name})...)
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+	}
+}
+
+func wrapGatewayFunc(f func(*gateway) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		g := &gateway{}
+		fromUnstructured(obj, g)
+
+		metricFamily := f(g)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descGatewayLabelsDefaultLabels, []string{g.Namespace, g.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+// mustUnstructured decodes the JSON of an object.
+func mustUnstructured(t *testing.T, data string) *unstructured.Unstructured {
+	t.Helper()
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestGatewayStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: mustUnstructured(t, `{
+				"apiVersion": "gateway.networking.k8s.io/v1",
+				"kind": "Gateway",
+				"metadata": {"namespace": "ns1", "name": "gw1", "creationTimestamp": "2017-08-01T06:30:18Z"},
+				"spec": {
+					"gatewayClassName": "istio",
+					"listeners": [
+						{"name": "http", "port": 80, "protocol": "HTTP"},
+						{"name": "https", "hostname": "*.example.com", "port": 443, "protocol": "HTTPS"}
+					]
+				},
+				"status": {
+					"conditions": [
+						{"type": "Accepted", "status": "True"},
+						{"type": "Programmed", "status": "False"}
+					],
+					"listeners": [
+						{"name": "http", "attachedRoutes": 2, "conditions": [{"type": "Programmed", "status": "True"}]},
+						{"name": "https", "attachedRoutes": 0}
+					]
+				}
+			}`),
+			Want: `
+				# HELP kube_gateway_created Unix creation timestamp
+				# HELP kube_gateway_info Information about gateway.
+				# HELP kube_gateway_listener_attached_routes Number of routes attached to the listeners of the gateway.
+				# HELP kube_gateway_listener_info Information about the listeners of the gateway.
+				# HELP kube_gateway_listener_status_condition The condition of the listeners of the gateway.
+				# HELP kube_gateway_listeners Number of listeners of the gateway.
+				# HELP kube_gateway_status_condition The condition of the gateway.
+				# TYPE kube_gateway_created gauge
+				# TYPE kube_gateway_info gauge
+				# TYPE kube_gateway_listener_attached_routes gauge
+				# TYPE kube_gateway_listener_info gauge
+				# TYPE kube_gateway_listener_status_condition gauge
+				# TYPE kube_gateway_listeners gauge
+				# TYPE kube_gateway_status_condition gauge
+				kube_gateway_created{gateway="gw1",namespace="ns1"} 1.501569018e+09
+				kube_gateway_info{gateway="gw1",gatewayclass="istio",namespace="ns1"} 1
+				kube_gateway_listener_attached_routes{gateway="gw1",listener="http",namespace="ns1"} 2
+				kube_gateway_listener_attached_routes{gateway="gw1",listener="https",namespace="ns1"} 0
+				kube_gateway_listener_info{gateway="gw1",hostname="",listener="http",namespace="ns1",port="80",protocol="HTTP"} 1
+				kube_gateway_listener_info{gateway="gw1",hostname="*.example.com",listener="https",namespace="ns1",port="443",protocol="HTTPS"} 1
+				kube_gateway_listener_status_condition{condition="Programmed",gateway="gw1",listener="http",namespace="ns1",status="false"} 0
+				kube_gateway_listener_status_condition{condition="Programmed",gateway="gw1",listener="http",namespace="ns1",status="true"} 1
+				kube_gateway_listener_status_condition{condition="Programmed",gateway="gw1",listener="http",namespace="ns1",status="unknown"} 0
+				kube_gateway_listeners{gateway="gw1",namespace="ns1"} 2
+				kube_gateway_status_condition{condition="Accepted",gateway="gw1",namespace="ns1",status="false"} 0
+				kube_gateway_status_condition{condition="Accepted",gateway="gw1",namespace="ns1",status="true"} 1
+				kube_gateway_status_condition{condition="Accepted",gateway="gw1",namespace="ns1",status="unknown"} 0
+				kube_gateway_status_condition{condition="Programmed",gateway="gw1",namespace="ns1",status="false"} 1
+				kube_gateway_status_condition{condition="Programmed",gateway="gw1",namespace="ns1",status="true"} 0
+				kube_gateway_status_condition{condition="Programmed",gateway="gw1",namespace="ns1",status="unknown"} 0
+			`,
+			MetricNames: []string{
+				"kube_gateway_created",
+				"kube_gateway_info",
+				"kube_gateway_listener_attached_routes",
+				"kube_gateway_listener_info",
+				"kube_gateway_listener_status_condition",
+				"kube_gateway_listeners",
+				"kube_gateway_status_condition",
+			},
+		},
+		{
+			AllowAnnotationsList: []string{"app.k8s.io/owner"},
+			Obj: mustUnstructured(t, `{
+				"apiVersion": "gateway.networking.k8s.io/v1",
+				"kind": "Gateway",
+				"metadata": {"namespace": "ns1", "name": "gw2", "annotations": {"app.k8s.io/owner": "team"}, "labels": {"app": "web"}},
+				"spec": {"gatewayClassName": "istio", "listeners": []}
+			}`),
+			Want: `
+				# HELP kube_gateway_annotations Kubernetes annotations converted to Prometheus labels.
+				# HELP kube_gateway_labels Kubernetes labels converted to Prometheus labels.
+				# HELP kube_gateway_listeners Number of listeners of the gateway.
+				# TYPE kube_gateway_annotations gauge
+				# TYPE kube_gateway_labels gauge
+				# TYPE kube_gateway_listeners gauge
+				kube_gateway_annotations{annotation_app_k8s_io_owner="team",gateway="gw2",namespace="ns1"} 1
+				kube_gateway_labels{gateway="gw2",namespace="ns1"} 1
+				kube_gateway_listeners{gateway="gw2",namespace="ns1"} 0
+			`,
+			MetricNames: []string{
+				"kube_gateway_annotations",
+				"kube_gateway_labels",
+				"kube_gateway_listeners",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(gatewayMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(gatewayMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// gatewayAPIGroupVersion is the version of the Gateway API objects which are
+// watched. The objects are watched as unstructured objects and decoded into
+// the subset of their fields which the metrics are derived from.
+var gatewayAPIGroupVersion = schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1"}
+
+type gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		GatewayClassName string            `json:"gatewayClassName"`
+		Listeners        []gatewayListener `json:"listeners,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []metav1.Condition      `json:"conditions,omitempty"`
+		Listeners  []gatewayListenerStatus `json:"listeners,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type gatewayListener struct {
+	Name     string  `json:"name"`
+	Hostname *string `json:"hostname,omitempty"`
+	Port     int32   `json:"port"`
+	Protocol string  `json:"protocol"`
+}
+
+type gatewayListenerStatus struct {
+	Name           string             `json:"name"`
+	AttachedRoutes int32              `json:"attachedRoutes"`
+	Conditions     []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type gatewayClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		ControllerName string `json:"controllerName"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type httpRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		ParentRefs []gatewayParentReference `json:"parentRefs,omitempty"`
+		Hostnames  []string                 `json:"hostnames,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Parents []struct {
+			ParentRef      gatewayParentReference `json:"parentRef"`
+			ControllerName string                 `json:"controllerName"`
+			Conditions     []metav1.Condition     `json:"conditions,omitempty"`
+		} `json:"parents,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type gatewayParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+}
+
+// labelValues returns the kind, namespace, name and section name of the
+// parent of a route in the given namespace, defaulting the kind and namespace
+// like the Gateway API.
+func (r gatewayParentReference) labelValues(routeNamespace string) []string {
+	kind, namespace := "Gateway", routeNamespace
+	if r.Kind != nil {
+		kind = *r.Kind
+	}
+	if r.Namespace != nil {
+		namespace = *r.Namespace
+	}
+	return []string{kind, namespace, r.Name, stringValue(r.SectionName)}
+}
+
+var gatewayParentReferenceLabelKeys = []string{"parent_kind", "parent_namespace", "parent_name", "parent_section_name"}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descGatewayClassAnnotationsName     = "kube_gatewayclass_annotations"
+	descGatewayClassAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descGatewayClassLabelsName          = "kube_gatewayclass_labels"
+	descGatewayClassLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descGatewayClassLabelsDefaultLabels = []string{"gatewayclass"}
+)
+
+func gatewayClassMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gatewayclass_info",
+			"Information about gatewayclass.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayClassFunc(func(c *gatewayClass) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"controller"},
+							LabelValues: []string{c.Spec.ControllerName},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gatewayclass_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayClassFunc(func(c *gatewayClass) *metric.Family {
+				ms := []*metric.Metric{}
+				if !c.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(c.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descGatewayClassAnnotationsName,
+			descGatewayClassAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayClassFunc(func(c *gatewayClass) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", c.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descGatewayClassLabelsName,
+			descGatewayClassLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayClassFunc(func(c *gatewayClass) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", c.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_gatewayclass_status_condition",
+			"The condition of the gatewayclass.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapGatewayClassFunc(func(c *gatewayClass) *metric.Family {
+				return &metric.Family{
+					Metrics: addStatusConditionMetrics(c.Status.Conditions, nil, nil),
+				}
+			}),
+		),
+	}
+}
+
+func wrapGatewayClassFunc(f func(*gatewayClass) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		c := &gatewayClass{}
+		fromUnstructured(obj, c)
+
+		metricFamily := f(c)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descGatewayClassLabelsDefaultLabels, []string{c.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
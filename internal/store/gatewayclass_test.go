@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestGatewayClassStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: mustUnstructured(t, `{
+				"apiVersion": "gateway.networking.k8s.io/v1",
+				"kind": "GatewayClass",
+				"metadata": {"name": "istio", "creationTimestamp": "2017-08-01T06:30:18Z"},
+				"spec": {"controllerName": "istio.io/gateway-controller"},
+				"status": {"conditions": [{"type": "Accepted", "status": "Unknown"}]}
+			}`),
+			Want: `
+				# HELP kube_gatewayclass_created Unix creation timestamp
+				# HELP kube_gatewayclass_info Information about gatewayclass.
+				# HELP kube_gatewayclass_status_condition The condition of the gatewayclass.
+				# TYPE kube_gatewayclass_created gauge
+				# TYPE kube_gatewayclass_info gauge
+				# TYPE kube_gatewayclass_status_condition gauge
+				kube_gatewayclass_created{gatewayclass="istio"} 1.501569018e+09
+				kube_gatewayclass_info{controller="istio.io/gateway-controller",gatewayclass="istio"} 1
+				kube_gatewayclass_status_condition{condition="Accepted",gatewayclass="istio",status="false"} 0
+				kube_gatewayclass_status_condition{condition="Accepted",gatewayclass="istio",status="true"} 0
+				kube_gatewayclass_status_condition{condition="Accepted",gatewayclass="istio",status="unknown"} 1
+			`,
+			MetricNames: []string{
+				"kube_gatewayclass_created",
+				"kube_gatewayclass_info",
+				"kube_gatewayclass_status_condition",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(gatewayClassMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(gatewayClassMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package store
 
 import (
 	"context"
+	"strconv"
 
 	autoscaling "k8s.io/api/autoscaling/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,7 +51,7 @@ var (
 	descHorizontalPodAutoscalerLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
 	descHorizontalPodAutoscalerLabelsDefaultLabels = []string{"namespace", "horizontalpodautoscaler"}
 
-	targetMetricLabels = []string{"metric_name", "metric_target_type"}
+	targetMetricLabels = []string{"metric_name", "metric_target_type", "metric_source_type", "container"}
 )
 
 func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
@@ -126,6 +127,63 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_horizontalpodautoscaler_spec_behavior_stabilization_window_seconds",
+			"The number of seconds for which past recommendations are considered while scaling up or down.",
+			metric.Gauge,
+			"",
+			wrapHPAFunc(func(a *autoscaling.HorizontalPodAutoscaler) *metric.Family {
+				ms := []*metric.Metric{}
+				for _, r := range hpaScalingRules(a) {
+					if r.rules.StabilizationWindowSeconds != nil {
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"direction"},
+							LabelValues: []string{r.direction},
+							Value:       float64(*r.rules.StabilizationWindowSeconds),
+						})
+					}
+				}
+				return &metric.Family{Metrics: ms}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_horizontalpodautoscaler_spec_behavior_select_policy",
+			"The policy which is used to select the allowed change while scaling up or down.",
+			metric.Gauge,
+			"",
+			wrapHPAFunc(func(a *autoscaling.HorizontalPodAutoscaler) *metric.Family {
+				ms := []*metric.Metric{}
+				for _, r := range hpaScalingRules(a) {
+					if r.rules.SelectPolicy != nil {
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"direction", "select_policy"},
+							LabelValues: []string{r.direction, string(*r.rules.SelectPolicy)},
+							Value:       1,
+						})
+					}
+				}
+				return &metric.Family{Metrics: ms}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_horizontalpodautoscaler_spec_behavior_policy",
+			"The amount of change which is allowed within the period of a policy while scaling up or down.",
+			metric.Gauge,
+			"",
+			wrapHPAFunc(func(a *autoscaling.HorizontalPodAutoscaler) *metric.Family {
+				ms := []*metric.Metric{}
+				for _, r := range hpaScalingRules(a) {
+					for _, p := range r.rules.Policies {
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"direction", "policy_type", "period_seconds"},
+							LabelValues: []string{r.direction, string(p.Type), strconv.FormatInt(int64(p.PeriodSeconds), 10)},
+							Value:       float64(p.Value),
+						})
+					}
+				}
+				return &metric.Family{Metrics: ms}
+			}),
+		),
 		*generator.NewFamilyGenerator(
 			"kube_horizontalpodautoscaler_spec_target_metric",
 			"The metric specifications used by this autoscaler when calculating the desired replica count.",
@@ -134,7 +192,7 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 			wrapHPAFunc(func(a *autoscaling.HorizontalPodAutoscaler) *metric.Family {
 				ms := make([]*metric.Metric, 0, len(a.Spec.Metrics))
 				for _, m := range a.Spec.Metrics {
-					var metricName string
+					var metricName, container string
 					var metricTarget autoscaling.MetricTarget
 					// The variable maps the type of metric to the corresponding value
 					metricMap := make(map[metricTargetType]float64)
@@ -151,6 +209,7 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 						metricTarget = m.Resource.Target
 					case autoscaling.ContainerResourceMetricSourceType:
 						metricName = string(m.ContainerResource.Name)
+						container = m.ContainerResource.Container
 						metricTarget = m.ContainerResource.Target
 					case autoscaling.ExternalMetricSourceType:
 						metricName = m.External.Metric.Name
@@ -173,7 +232,7 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 					for metricTypeIndex, metricValue := range metricMap {
 						ms = append(ms, &metric.Metric{
 							LabelKeys:   targetMetricLabels,
-							LabelValues: []string{metricName, metricTypeIndex.String()},
+							LabelValues: []string{metricName, metricTypeIndex.String(), string(m.Type), container},
 							Value:       metricValue,
 						})
 					}
@@ -189,7 +248,7 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 			wrapHPAFunc(func(a *autoscaling.HorizontalPodAutoscaler) *metric.Family {
 				ms := make([]*metric.Metric, 0, len(a.Status.CurrentMetrics))
 				for _, m := range a.Status.CurrentMetrics {
-					var metricName string
+					var metricName, container string
 					var currentMetric autoscaling.MetricValueStatus
 					// The variable maps the type of metric to the corresponding value
 					metricMap := make(map[metricTargetType]float64)
@@ -206,6 +265,7 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 						currentMetric = m.Resource.Current
 					case autoscaling.ContainerResourceMetricSourceType:
 						metricName = string(m.ContainerResource.Name)
+						container = m.ContainerResource.Container
 						currentMetric = m.ContainerResource.Current
 					case autoscaling.ExternalMetricSourceType:
 						metricName = m.External.Metric.Name
@@ -228,7 +288,7 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 					for metricTypeIndex, metricValue := range metricMap {
 						ms = append(ms, &metric.Metric{
 							LabelKeys:   targetMetricLabels,
-							LabelValues: []string{metricName, metricTypeIndex.String()},
+							LabelValues: []string{metricName, metricTypeIndex.String(), string(m.Type), container},
 							Value:       metricValue,
 						})
 					}
@@ -305,6 +365,21 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_horizontalpodautoscaler_status_last_scale_time",
+			"Unix timestamp of the last time the autoscaler scaled the number of pods.",
+			metric.Gauge,
+			"",
+			wrapHPAFunc(func(a *autoscaling.HorizontalPodAutoscaler) *metric.Family {
+				ms := []*metric.Metric{}
+				if a.Status.LastScaleTime != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(a.Status.LastScaleTime.Unix()),
+					})
+				}
+				return &metric.Family{Metrics: ms}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_horizontalpodautoscaler_status_condition",
 			"The condition of this autoscaler.",
@@ -333,6 +408,28 @@ func hpaMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 	}
 }
 
+// hpaScalingRule are the scaling rules of an autoscaler for one direction.
+type hpaScalingRule struct {
+	direction string
+	rules     *autoscaling.HPAScalingRules
+}
+
+// hpaScalingRules returns the scaling rules of the behavior of the autoscaler
+// which are set.
+func hpaScalingRules(a *autoscaling.HorizontalPodAutoscaler) []hpaScalingRule {
+	if a.Spec.Behavior == nil {
+		return nil
+	}
+	var rules []hpaScalingRule
+	if a.Spec.Behavior.ScaleUp != nil {
+		rules = append(rules, hpaScalingRule{direction: "scale_up", rules: a.Spec.Behavior.ScaleUp})
+	}
+	if a.Spec.Behavior.ScaleDown != nil {
+		rules = append(rules, hpaScalingRule{direction: "scale_down", rules: a.Spec.Behavior.ScaleDown})
+	}
+	return rules
+}
+
 func wrapHPAFunc(f func(*autoscaling.HorizontalPodAutoscaler) *metric.Family) func(interface{}) *metric.Family {
 	return func(obj interface{}) *metric.Family {
 		hpa := obj.(*autoscaling.HorizontalPodAutoscaler)
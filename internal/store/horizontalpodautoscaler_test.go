@@ -18,6 +18,7 @@ package store
 
 import (
 	"testing"
+	"time"
 
 	autoscaling "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
@@ -29,6 +30,8 @@ import (
 
 var (
 	hpa1MinReplicas int32 = 2
+
+	hpa3SelectPolicyMax = autoscaling.MaxChangePolicySelect
 )
 
 func TestHPAStore(t *testing.T) {
@@ -215,21 +218,21 @@ func TestHPAStore(t *testing.T) {
 				kube_horizontalpodautoscaler_metadata_generation{horizontalpodautoscaler="hpa1",namespace="ns1"} 2
 				kube_horizontalpodautoscaler_spec_max_replicas{horizontalpodautoscaler="hpa1",namespace="ns1"} 4
 				kube_horizontalpodautoscaler_spec_min_replicas{horizontalpodautoscaler="hpa1",namespace="ns1"} 2
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="cpu",metric_target_type="utilization",namespace="ns1"} 80
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="events",metric_target_type="average",namespace="ns1"} 30
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="hits",metric_target_type="average",namespace="ns1"} 12
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="hits",metric_target_type="value",namespace="ns1"} 10
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="connections",metric_target_type="average",namespace="ns1"} 0.7
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="connections",metric_target_type="value",namespace="ns1"} 0.5
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="cpu",metric_target_type="utilization",namespace="ns1"} 80
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="memory",metric_target_type="average",namespace="ns1"} 819200
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="memory",metric_target_type="utilization",namespace="ns1"} 80
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="sqs_jobs",metric_target_type="value",namespace="ns1"} 30
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa1",metric_name="transactions_processed",metric_target_type="average",namespace="ns1"} 33
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa1",metric_name="cpu",metric_target_type="average",namespace="ns1"} 0.007
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa1",metric_name="cpu",metric_target_type="utilization",namespace="ns1"} 80
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa1",metric_name="memory",metric_target_type="average",namespace="ns1"} 2.6335914666e+07
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa1",metric_name="memory",metric_target_type="utilization",namespace="ns1"} 80
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="connections",metric_source_type="Object",metric_target_type="average",namespace="ns1"} 0.7
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="connections",metric_source_type="Object",metric_target_type="value",namespace="ns1"} 0.5
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="cpu",metric_source_type="Resource",metric_target_type="utilization",namespace="ns1"} 80
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="events",metric_source_type="External",metric_target_type="average",namespace="ns1"} 30
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="hits",metric_source_type="Object",metric_target_type="average",namespace="ns1"} 12
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="hits",metric_source_type="Object",metric_target_type="value",namespace="ns1"} 10
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="memory",metric_source_type="Resource",metric_target_type="average",namespace="ns1"} 819200
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="memory",metric_source_type="Resource",metric_target_type="utilization",namespace="ns1"} 80
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="sqs_jobs",metric_source_type="External",metric_target_type="value",namespace="ns1"} 30
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="transactions_processed",metric_source_type="Pods",metric_target_type="average",namespace="ns1"} 33
+				kube_horizontalpodautoscaler_spec_target_metric{container="container1",horizontalpodautoscaler="hpa1",metric_name="cpu",metric_source_type="ContainerResource",metric_target_type="utilization",namespace="ns1"} 80
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="cpu",metric_source_type="Resource",metric_target_type="average",namespace="ns1"} 0.007
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="cpu",metric_source_type="Resource",metric_target_type="utilization",namespace="ns1"} 80
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="memory",metric_source_type="Resource",metric_target_type="average",namespace="ns1"} 2.6335914666e+07
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa1",metric_name="memory",metric_source_type="Resource",metric_target_type="utilization",namespace="ns1"} 80
 				kube_horizontalpodautoscaler_status_condition{condition="AbleToScale",horizontalpodautoscaler="hpa1",namespace="ns1",status="false"} 0
 				kube_horizontalpodautoscaler_status_condition{condition="AbleToScale",horizontalpodautoscaler="hpa1",namespace="ns1",status="true"} 1
 				kube_horizontalpodautoscaler_status_condition{condition="AbleToScale",horizontalpodautoscaler="hpa1",namespace="ns1",status="unknown"} 0
@@ -393,19 +396,19 @@ func TestHPAStore(t *testing.T) {
 				kube_horizontalpodautoscaler_metadata_generation{horizontalpodautoscaler="hpa2",namespace="ns1"} 2
 				kube_horizontalpodautoscaler_spec_max_replicas{horizontalpodautoscaler="hpa2",namespace="ns1"} 4
 				kube_horizontalpodautoscaler_spec_min_replicas{horizontalpodautoscaler="hpa2",namespace="ns1"} 2
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa2",metric_name="cpu",metric_target_type="utilization",namespace="ns1"} 80
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa2",metric_name="memory",metric_target_type="utilization",namespace="ns1"} 75
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa2",metric_name="traefik_backend_errors_per_second",metric_target_type="value",namespace="ns1"} 100
-				kube_horizontalpodautoscaler_spec_target_metric{horizontalpodautoscaler="hpa2",metric_name="traefik_backend_requests_per_second",metric_target_type="value",namespace="ns1"} 100
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="memory",metric_target_type="average",namespace="ns1"} 8.47775744e+08
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="memory",metric_target_type="utilization",namespace="ns1"} 28
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="cpu",metric_target_type="average",namespace="ns1"} 0.062
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="cpu",metric_target_type="utilization",namespace="ns1"} 6
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="cpu",metric_target_type="average",namespace="ns1"} 0.08
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="cpu",metric_target_type="utilization",namespace="ns1"} 10
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="traefik_backend_requests_per_second",metric_target_type="value",namespace="ns1"} 0
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="traefik_backend_requests_per_second",metric_target_type="average",namespace="ns1"} 2.9
-				kube_horizontalpodautoscaler_status_target_metric{horizontalpodautoscaler="hpa2",metric_name="traefik_backend_errors_per_second",metric_target_type="value",namespace="ns1"} 0
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="cpu",metric_source_type="Resource",metric_target_type="utilization",namespace="ns1"} 80
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="memory",metric_source_type="Resource",metric_target_type="utilization",namespace="ns1"} 75
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="traefik_backend_errors_per_second",metric_source_type="External",metric_target_type="value",namespace="ns1"} 100
+				kube_horizontalpodautoscaler_spec_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="traefik_backend_requests_per_second",metric_source_type="External",metric_target_type="value",namespace="ns1"} 100
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="cpu",metric_source_type="Resource",metric_target_type="average",namespace="ns1"} 0.062
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="cpu",metric_source_type="Resource",metric_target_type="utilization",namespace="ns1"} 6
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="memory",metric_source_type="Resource",metric_target_type="average",namespace="ns1"} 8.47775744e+08
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="memory",metric_source_type="Resource",metric_target_type="utilization",namespace="ns1"} 28
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="traefik_backend_errors_per_second",metric_source_type="External",metric_target_type="value",namespace="ns1"} 0
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="traefik_backend_requests_per_second",metric_source_type="External",metric_target_type="average",namespace="ns1"} 2.9
+				kube_horizontalpodautoscaler_status_target_metric{container="",horizontalpodautoscaler="hpa2",metric_name="traefik_backend_requests_per_second",metric_source_type="External",metric_target_type="value",namespace="ns1"} 0
+				kube_horizontalpodautoscaler_status_target_metric{container="container1",horizontalpodautoscaler="hpa2",metric_name="cpu",metric_source_type="ContainerResource",metric_target_type="average",namespace="ns1"} 0.08
+				kube_horizontalpodautoscaler_status_target_metric{container="container1",horizontalpodautoscaler="hpa2",metric_name="cpu",metric_source_type="ContainerResource",metric_target_type="utilization",namespace="ns1"} 10
 				kube_horizontalpodautoscaler_status_condition{condition="AbleToScale",horizontalpodautoscaler="hpa2",namespace="ns1",status="false"} 0
 				kube_horizontalpodautoscaler_status_condition{condition="AbleToScale",horizontalpodautoscaler="hpa2",namespace="ns1",status="true"} 1
 				kube_horizontalpodautoscaler_status_condition{condition="AbleToScale",horizontalpodautoscaler="hpa2",namespace="ns1",status="unknown"} 0
@@ -426,6 +429,61 @@ func TestHPAStore(t *testing.T) {
 				"kube_horizontalpodautoscaler_labels",
 			},
 		},
+		{
+			// Verify populating behavior metrics.
+			Obj: &autoscaling.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "hpa3",
+					Namespace: "ns1",
+				},
+				Spec: autoscaling.HorizontalPodAutoscalerSpec{
+					MaxReplicas: 4,
+					MinReplicas: &hpa1MinReplicas,
+					Behavior: &autoscaling.HorizontalPodAutoscalerBehavior{
+						ScaleUp: &autoscaling.HPAScalingRules{
+							StabilizationWindowSeconds: int32ptr(0),
+							SelectPolicy:               &hpa3SelectPolicyMax,
+							Policies: []autoscaling.HPAScalingPolicy{
+								{Type: autoscaling.PodsScalingPolicy, Value: 4, PeriodSeconds: 15},
+								{Type: autoscaling.PercentScalingPolicy, Value: 100, PeriodSeconds: 15},
+							},
+						},
+						ScaleDown: &autoscaling.HPAScalingRules{
+							StabilizationWindowSeconds: int32ptr(300),
+							Policies: []autoscaling.HPAScalingPolicy{
+								{Type: autoscaling.PercentScalingPolicy, Value: 10, PeriodSeconds: 60},
+							},
+						},
+					},
+				},
+				Status: autoscaling.HorizontalPodAutoscalerStatus{
+					LastScaleTime: &metav1.Time{Time: time.Unix(1501569018, 0)},
+				},
+			},
+			Want: `
+				# HELP kube_horizontalpodautoscaler_spec_behavior_policy The amount of change which is allowed within the period of a policy while scaling up or down.
+				# HELP kube_horizontalpodautoscaler_spec_behavior_select_policy The policy which is used to select the allowed change while scaling up or down.
+				# HELP kube_horizontalpodautoscaler_spec_behavior_stabilization_window_seconds The number of seconds for which past recommendations are considered while scaling up or down.
+				# HELP kube_horizontalpodautoscaler_status_last_scale_time Unix timestamp of the last time the autoscaler scaled the number of pods.
+				# TYPE kube_horizontalpodautoscaler_spec_behavior_policy gauge
+				# TYPE kube_horizontalpodautoscaler_spec_behavior_select_policy gauge
+				# TYPE kube_horizontalpodautoscaler_spec_behavior_stabilization_window_seconds gauge
+				# TYPE kube_horizontalpodautoscaler_status_last_scale_time gauge
+				kube_horizontalpodautoscaler_spec_behavior_policy{direction="scale_down",horizontalpodautoscaler="hpa3",namespace="ns1",period_seconds="60",policy_type="Percent"} 10
+				kube_horizontalpodautoscaler_spec_behavior_policy{direction="scale_up",horizontalpodautoscaler="hpa3",namespace="ns1",period_seconds="15",policy_type="Percent"} 100
+				kube_horizontalpodautoscaler_spec_behavior_policy{direction="scale_up",horizontalpodautoscaler="hpa3",namespace="ns1",period_seconds="15",policy_type="Pods"} 4
+				kube_horizontalpodautoscaler_spec_behavior_select_policy{direction="scale_up",horizontalpodautoscaler="hpa3",namespace="ns1",select_policy="Max"} 1
+				kube_horizontalpodautoscaler_spec_behavior_stabilization_window_seconds{direction="scale_down",horizontalpodautoscaler="hpa3",namespace="ns1"} 300
+				kube_horizontalpodautoscaler_spec_behavior_stabilization_window_seconds{direction="scale_up",horizontalpodautoscaler="hpa3",namespace="ns1"} 0
+				kube_horizontalpodautoscaler_status_last_scale_time{horizontalpodautoscaler="hpa3",namespace="ns1"} 1.501569018e+09
+			`,
+			MetricNames: []string{
+				"kube_horizontalpodautoscaler_spec_behavior_policy",
+				"kube_horizontalpodautoscaler_spec_behavior_select_policy",
+				"kube_horizontalpodautoscaler_spec_behavior_stabilization_window_seconds",
+				"kube_horizontalpodautoscaler_status_last_scale_time",
+			},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(hpaMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
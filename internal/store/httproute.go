@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descHTTPRouteAnnotationsName     = "kube_httproute_annotations"
+	descHTTPRouteAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descHTTPRouteLabelsName          = "kube_httproute_labels"
+	descHTTPRouteLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descHTTPRouteLabelsDefaultLabels = []string{"namespace", "httproute"}
+)
+
+func httpRouteMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_httproute_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapHTTPRouteFunc(func(r *httpRoute) *metric.Family {
+				ms := []*metric.Metric{}
+				if !r.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(r.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descHTTPRouteAnnotationsName,
+			descHTTPRouteAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapHTTPRouteFunc(func(r *httpRoute) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", r.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descHTTPRouteLabelsName,
+			descHTTPRouteLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapHTTPRouteFunc(func(r *httpRoute) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", r.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_httproute_hostname_info",
+			"Information about the hostnames of the httproute.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapHTTPRouteFunc(func(r *httpRoute) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(r.Spec.Hostnames))
+				for _, h := range r.Spec.Hostnames {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"hostname"},
+						LabelValues: []string{h},
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_httproute_parent_info",
+			"Information about the parents, e.g. gateways, the httproute attaches to.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapHTTPRouteFunc(func(r *httpRoute) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(r.Spec.ParentRefs))
+				for _, p := range r.Spec.ParentRefs {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   gatewayParentReferenceLabelKeys,
+						LabelValues: p.labelValues(r.Namespace),
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_httproute_parent_status_condition",
+			"The condition of the httproute reported by the controller of a parent, e.g. whether the parent accepted the httproute.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapHTTPRouteFunc(func(r *httpRoute) *metric.Family {
+				ms := []*metric.Metric{}
+				keys := append(append([]string{}, gatewayParentReferenceLabelKeys...), "controller")
+				for _, p := range r.Status.Parents {
+					values := append(p.ParentRef.labelValues(r.Namespace), p.ControllerName)
+					ms = append(ms, addStatusConditionMetrics(p.Conditions, keys, values)...)
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+	}
+}
+
+func wrapHTTPRouteFunc(f func(*httpRoute) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		r := &httpRoute{}
+		fromUnstructured(obj, r)
+
+		metricFamily := f(r)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descHTTPRouteLabelsDefaultLabels, []string{r.Namespace, r.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
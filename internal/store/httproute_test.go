@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestHTTPRouteStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: mustUnstructured(t, `{
+				"apiVersion": "gateway.networking.k8s.io/v1",
+				"kind": "HTTPRoute",
+				"metadata": {"namespace": "ns1", "name": "route1"},
+				"spec": {
+					"hostnames": ["www.example.com"],
+					"parentRefs": [
+						{"name": "gw1"},
+						{"name": "gw2", "namespace": "infra", "sectionName": "https"}
+					]
+				},
+				"status": {
+					"parents": [
+						{
+							"parentRef": {"name": "gw1"},
+							"controllerName": "istio.io/gateway-controller",
+							"conditions": [{"type": "Accepted", "status": "True"}]
+						},
+						{
+							"parentRef": {"name": "gw2", "namespace": "infra", "sectionName": "https"},
+							"controllerName": "istio.io/gateway-controller",
+							"conditions": [{"type": "Accepted", "status": "False", "reason": "NotAllowedByListeners"}]
+						}
+					]
+				}
+			}`),
+			Want: `
+				# HELP kube_httproute_hostname_info Information about the hostnames of the httproute.
+				# HELP kube_httproute_parent_info Information about the parents, e.g. gateways, the httproute attaches to.
+				# HELP kube_httproute_parent_status_condition The condition of the httproute reported by the controller of a parent, e.g. whether the parent accepted the httproute.
+				# TYPE kube_httproute_hostname_info gauge
+				# TYPE kube_httproute_parent_info gauge
+				# TYPE kube_httproute_parent_status_condition gauge
+				kube_httproute_hostname_info{hostname="www.example.com",httproute="route1",namespace="ns1"} 1
+				kube_httproute_parent_info{httproute="route1",namespace="ns1",parent_kind="Gateway",parent_name="gw1",parent_namespace="ns1",parent_section_name=""} 1
+				kube_httproute_parent_info{httproute="route1",namespace="ns1",parent_kind="Gateway",parent_name="gw2",parent_namespace="infra",parent_section_name="https"} 1
+				kube_httproute_parent_status_condition{condition="Accepted",controller="istio.io/gateway-controller",httproute="route1",namespace="ns1",parent_kind="Gateway",parent_name="gw1",parent_namespace="ns1",parent_section_name="",status="false"} 0
+				kube_httproute_parent_status_condition{condition="Accepted",controller="istio.io/gateway-controller",httproute="route1",namespace="ns1",parent_kind="Gateway",parent_name="gw1",parent_namespace="ns1",parent_section_name="",status="true"} 1
+				kube_httproute_parent_status_condition{condition="Accepted",controller="istio.io/gateway-controller",httproute="route1",namespace="ns1",parent_kind="Gateway",parent_name="gw1",parent_namespace="ns1",parent_section_name="",status="unknown"} 0
+				kube_httproute_parent_status_condition{condition="Accepted",controller="istio.io/gateway-controller",httproute="route1",namespace="ns1",parent_kind="Gateway",parent_name="gw2",parent_namespace="infra",parent_section_name="https",status="false"} 1
+				kube_httproute_parent_status_condition{condition="Accepted",controller="istio.io/gateway-controller",httproute="route1",namespace="ns1",parent_kind="Gateway",parent_name="gw2",parent_namespace="infra",parent_section_name="https",status="true"} 0
+				kube_httproute_parent_status_condition{condition="Accepted",controller="istio.io/gateway-controller",httproute="route1",namespace="ns1",parent_kind="Gateway",parent_name="gw2",parent_namespace="infra",parent_section_name="https",status="unknown"} 0
+			`,
+			MetricNames: []string{
+				"kube_httproute_hostname_info",
+				"kube_httproute_parent_info",
+				"kube_httproute_parent_status_condition",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(httpRouteMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(httpRouteMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -92,6 +92,43 @@ var (
 				}
 			}),
 		),
+		*generator.NewOptInFamilyGenerator(
+			"kube_lease_info",
+			"Information about the Lease.",
+			metric.Gauge,
+			"",
+			wrapLeaseFunc(func(l *coordinationv1.Lease) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"namespace"},
+							LabelValues: []string{l.Namespace},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewOptInFamilyGenerator(
+			"kube_lease_duration_seconds",
+			"Duration candidates for the Lease need to wait to force acquire it after its last renewal.",
+			metric.Gauge,
+			"",
+			wrapLeaseFunc(func(l *coordinationv1.Lease) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if l.Spec.LeaseDurationSeconds != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"namespace"},
+						LabelValues: []string{l.Namespace},
+						Value:       float64(*l.Spec.LeaseDurationSeconds),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 	}
 )
 
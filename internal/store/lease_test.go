@@ -33,7 +33,14 @@ func TestLeaseStore(t *testing.T) {
         # HELP kube_lease_renew_time Kube lease renew time.
         # TYPE kube_lease_renew_time gauge
 	`
+	const optInMetadata = `
+        # HELP kube_lease_info Information about the Lease.
+        # TYPE kube_lease_info gauge
+        # HELP kube_lease_duration_seconds Duration candidates for the Lease need to wait to force acquire it after its last renewal.
+        # TYPE kube_lease_duration_seconds gauge
+	`
 	leaseOwner := "kube-master"
+	leaseDuration := int32(40)
 	var (
 		cases = []generateMetricsTestCase{
 			{
@@ -51,17 +58,22 @@ func TestLeaseStore(t *testing.T) {
 						},
 					},
 					Spec: coordinationv1.LeaseSpec{
-						RenewTime:      &metav1.MicroTime{Time: time.Unix(1500000000, 0)},
-						HolderIdentity: &leaseOwner,
+						RenewTime:            &metav1.MicroTime{Time: time.Unix(1500000000, 0)},
+						HolderIdentity:       &leaseOwner,
+						LeaseDurationSeconds: &leaseDuration,
 					},
 				},
-				Want: metadata + `
+				Want: metadata + optInMetadata + `
                     kube_lease_owner{lease="kube-master",owner_kind="Node",owner_name="kube-master",namespace="default",lease_holder="kube-master"} 1
                     kube_lease_renew_time{lease="kube-master"} 1.5e+09
+                    kube_lease_info{lease="kube-master",namespace="default"} 1
+                    kube_lease_duration_seconds{lease="kube-master",namespace="default"} 40
 			`,
 				MetricNames: []string{
 					"kube_lease_owner",
 					"kube_lease_renew_time",
+					"kube_lease_info",
+					"kube_lease_duration_seconds",
 				},
 			},
 			{
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/pager"
+
+	ksmwatch "k8s.io/kube-state-metrics/v2/pkg/watch"
+)
+
+// metadataExpectedType returns the expected type of stores watching only the
+// metadata of objects of the given kind. The kind is used to label the list
+// and watch metrics like for stores of full objects.
+func metadataExpectedType(gvk schema.GroupVersionKind) *metav1.PartialObjectMetadata {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: apiVersion, Kind: kind}}
+}
+
+// createMetadataListWatchFunc returns a function creating a ListWatch of the
+// metadata of the given resource, which avoids decoding full objects for
+// metrics only derived from their metadata.
+func createMetadataListWatchFunc(metadataClient metadata.Interface, resource schema.GroupVersionResource) func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+	return func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+		return &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = fieldSelector
+				return metadataClient.Resource(resource).Namespace(ns).List(context.TODO(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = fieldSelector
+				return metadataClient.Resource(resource).Namespace(ns).Watch(context.TODO(), opts)
+			},
+		}
+	}
+}
+
+// createMetadataListUIDsFunc returns a function listing the ids of all objects
+// of the given resource in the given namespace. Only the metadata of the
+// objects is listed, in pages.
+func createMetadataListUIDsFunc(metadataClient metadata.Interface, resource schema.GroupVersionResource, ns string) ksmwatch.ListUIDsFunc {
+	return func(ctx context.Context) (map[types.UID]struct{}, error) {
+		p := pager.New(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return metadataClient.Resource(resource).Namespace(ns).List(ctx, opts)
+		})
+		uids := map[types.UID]struct{}{}
+		err := p.EachListItem(ctx, metav1.ListOptions{}, func(obj runtime.Object) error {
+			o, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			uids[o.GetUID()] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return uids, nil
+	}
+}
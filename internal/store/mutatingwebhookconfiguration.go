@@ -78,6 +78,82 @@ var (
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_mutatingwebhookconfiguration_webhook_info",
+			"Information about the webhooks of the MutatingWebhookConfiguration.",
+			metric.Gauge,
+			"",
+			wrapMutatingWebhookConfigurationFunc(func(mwc *admissionregistrationv1.MutatingWebhookConfiguration) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(mwc.Webhooks))
+				for _, w := range mwc.Webhooks {
+					failurePolicy := ""
+					if w.FailurePolicy != nil {
+						failurePolicy = string(*w.FailurePolicy)
+					}
+					matchPolicy := ""
+					if w.MatchPolicy != nil {
+						matchPolicy = string(*w.MatchPolicy)
+					}
+					sideEffects := ""
+					if w.SideEffects != nil {
+						sideEffects = string(*w.SideEffects)
+					}
+					reinvocationPolicy := ""
+					if w.ReinvocationPolicy != nil {
+						reinvocationPolicy = string(*w.ReinvocationPolicy)
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   append([]string{"webhook", "failure_policy", "match_policy", "side_effects", "reinvocation_policy"}, webhookServiceLabelKeys...),
+						LabelValues: append([]string{w.Name, failurePolicy, matchPolicy, sideEffects, reinvocationPolicy}, webhookServiceLabelValues(w.ClientConfig)...),
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_mutatingwebhookconfiguration_webhook_timeout_seconds",
+			"Timeout of the calls of the webhooks of the MutatingWebhookConfiguration.",
+			metric.Gauge,
+			"",
+			wrapMutatingWebhookConfigurationFunc(func(mwc *admissionregistrationv1.MutatingWebhookConfiguration) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(mwc.Webhooks))
+				for _, w := range mwc.Webhooks {
+					if w.TimeoutSeconds == nil {
+						continue
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"webhook"},
+						LabelValues: []string{w.Name},
+						Value:       float64(*w.TimeoutSeconds),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_mutatingwebhookconfiguration_webhook_rules",
+			"Number of rules matching the requests sent to the webhooks of the MutatingWebhookConfiguration.",
+			metric.Gauge,
+			"",
+			wrapMutatingWebhookConfigurationFunc(func(mwc *admissionregistrationv1.MutatingWebhookConfiguration) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(mwc.Webhooks))
+				for _, w := range mwc.Webhooks {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"webhook"},
+						LabelValues: []string{w.Name},
+						Value:       float64(len(w.Rules)),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 	}
 )
 
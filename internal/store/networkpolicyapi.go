@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// networkPolicyAPIGroupVersion is the version of the objects of the Network
+// Policy API which are watched. The objects are watched as unstructured
+// objects and decoded into the subset of their fields which the metrics are
+// derived from.
+var networkPolicyAPIGroupVersion = schema.GroupVersion{Group: "policy.networking.k8s.io", Version: "v1alpha1"}
+
+type adminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Priority int32                     `json:"priority"`
+		Subject  adminNetworkPolicySubject `json:"subject"`
+		Ingress  []adminNetworkPolicyRule  `json:"ingress,omitempty"`
+		Egress   []adminNetworkPolicyRule  `json:"egress,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+type baselineAdminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Subject adminNetworkPolicySubject `json:"subject"`
+		Ingress []adminNetworkPolicyRule  `json:"ingress,omitempty"`
+		Egress  []adminNetworkPolicyRule  `json:"egress,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+// adminNetworkPolicySubject selects the pods an AdminNetworkPolicy or
+// BaselineAdminNetworkPolicy applies to, either all pods of the selected
+// namespaces or the selected pods of the selected namespaces.
+type adminNetworkPolicySubject struct {
+	Namespaces *metav1.LabelSelector `json:"namespaces,omitempty"`
+	Pods       *struct {
+		NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+		PodSelector       metav1.LabelSelector `json:"podSelector"`
+	} `json:"pods,omitempty"`
+}
+
+type adminNetworkPolicyRule struct {
+	Name   string `json:"name,omitempty"`
+	Action string `json:"action"`
+}
+
+// adminNetworkPolicySubjectLabelKeys are the labels summarizing the subject
+// of an AdminNetworkPolicy or BaselineAdminNetworkPolicy.
+var adminNetworkPolicySubjectLabelKeys = []string{"subject_type", "subject_namespace_selector", "subject_pod_selector"}
+
+// labelValues returns the values of adminNetworkPolicySubjectLabelKeys, with
+// the selectors formatted like label selectors, e.g. "team=payments".
+func (s adminNetworkPolicySubject) labelValues() []string {
+	switch {
+	case s.Namespaces != nil:
+		return []string{"namespaces", metav1.FormatLabelSelector(s.Namespaces), ""}
+	case s.Pods != nil:
+		return []string{"pods", metav1.FormatLabelSelector(&s.Pods.NamespaceSelector), metav1.FormatLabelSelector(&s.Pods.PodSelector)}
+	default:
+		return []string{"", "", ""}
+	}
+}
+
+// adminNetworkPolicyRuleMetrics returns the number of ingress and egress rules
+// per action, ordered by direction and action.
+func adminNetworkPolicyRuleMetrics(ingress, egress []adminNetworkPolicyRule) []*metric.Metric {
+	ms := []*metric.Metric{}
+	for _, d := range []struct {
+		direction string
+		rules     []adminNetworkPolicyRule
+	}{
+		{"ingress", ingress},
+		{"egress", egress},
+	} {
+		counts := map[string]int{}
+		for _, r := range d.rules {
+			counts[r.Action]++
+		}
+		actions := make([]string, 0, len(counts))
+		for action := range counts {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		for _, action := range actions {
+			ms = append(ms, &metric.Metric{
+				LabelKeys:   []string{"direction", "action"},
+				LabelValues: []string{d.direction, action},
+				Value:       float64(counts[action]),
+			})
+		}
+	}
+	return ms
+}
@@ -45,6 +45,7 @@ var (
 func nodeMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
 	return []generator.FamilyGenerator{
 		createNodeAnnotationsGenerator(allowAnnotationsList),
+		createNodeBootIDFamilyGenerator(),
 		createNodeCreatedFamilyGenerator(),
 		createNodeDeletionTimestampFamilyGenerator(),
 		createNodeInfoFamilyGenerator(),
@@ -55,9 +56,36 @@ func nodeMetricFamilies(allowAnnotationsList, allowLabelsList []string) []genera
 		createNodeStatusAllocatableFamilyGenerator(),
 		createNodeStatusCapacityFamilyGenerator(),
 		createNodeStatusConditionFamilyGenerator(),
+		createNodeStatusCachedImagesFamilyGenerator(),
+		createNodeStatusImagesSizeFamilyGenerator(),
+		createNodeStatusSwapCapacityFamilyGenerator(),
 	}
 }
 
+func createNodeBootIDFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_node_boot_id",
+		"The boot ID of the node, which changes whenever the node reboots.",
+		metric.Gauge,
+		"",
+		wrapNodeFunc(func(n *v1.Node) *metric.Family {
+			var ms []*metric.Metric
+
+			if n.Status.NodeInfo.BootID != "" {
+				ms = append(ms, &metric.Metric{
+					LabelKeys:   []string{"boot_id"},
+					LabelValues: []string{n.Status.NodeInfo.BootID},
+					Value:       1,
+				})
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func createNodeDeletionTimestampFamilyGenerator() generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		"kube_node_deletion_timestamp",
@@ -473,6 +501,68 @@ func createNodeStatusConditionFamilyGenerator() generator.FamilyGenerator {
 	)
 }
 
+func createNodeStatusCachedImagesFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewOptInFamilyGenerator(
+		"kube_node_status_cached_images",
+		"Number of container images cached on the node, as reported by the kubelet.",
+		metric.Gauge,
+		"",
+		wrapNodeFunc(func(n *v1.Node) *metric.Family {
+			return &metric.Family{
+				Metrics: []*metric.Metric{
+					{
+						Value: float64(len(n.Status.Images)),
+					},
+				},
+			}
+		}),
+	)
+}
+
+func createNodeStatusImagesSizeFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewOptInFamilyGenerator(
+		"kube_node_status_images_size_bytes",
+		"Total size of the container images cached on the node, as reported by the kubelet.",
+		metric.Gauge,
+		"",
+		wrapNodeFunc(func(n *v1.Node) *metric.Family {
+			var size int64
+			for _, image := range n.Status.Images {
+				size += image.SizeBytes
+			}
+			return &metric.Family{
+				Metrics: []*metric.Metric{
+					{
+						Value: float64(size),
+					},
+				},
+			}
+		}),
+	)
+}
+
+func createNodeStatusSwapCapacityFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_node_status_swap_capacity_bytes",
+		"The swap memory available on the node, as reported by the kubelet.",
+		metric.Gauge,
+		"",
+		wrapNodeFunc(func(n *v1.Node) *metric.Family {
+			var ms []*metric.Metric
+
+			if swap := n.Status.NodeInfo.Swap; swap != nil && swap.Capacity != nil {
+				ms = append(ms, &metric.Metric{
+					Value: float64(*swap.Capacity),
+				})
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func wrapNodeFunc(f func(*v1.Node) *metric.Family) func(interface{}) *metric.Family {
 	return func(obj interface{}) *metric.Family {
 		node := obj.(*v1.Node)
@@ -276,6 +276,29 @@ func TestNodeStore(t *testing.T) {
 			`,
 			MetricNames: []string{"kube_node_spec_taint"},
 		},
+		// Verify the opt-in image metrics.
+		{
+			Obj: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "127.0.0.1",
+				},
+				Status: v1.NodeStatus{
+					Images: []v1.ContainerImage{
+						{Names: []string{"registry.k8s.io/pause:3.9"}, SizeBytes: 321520},
+						{Names: []string{"registry.k8s.io/kube-proxy:v1.26.0"}, SizeBytes: 21541940},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_node_status_cached_images Number of container images cached on the node, as reported by the kubelet.
+				# HELP kube_node_status_images_size_bytes Total size of the container images cached on the node, as reported by the kubelet.
+				# TYPE kube_node_status_cached_images gauge
+				# TYPE kube_node_status_images_size_bytes gauge
+				kube_node_status_cached_images{node="127.0.0.1"} 2
+				kube_node_status_images_size_bytes{node="127.0.0.1"} 2.186346e+07
+			`,
+			MetricNames: []string{"kube_node_status_cached_images", "kube_node_status_images_size_bytes"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(nodeMetricFamilies(nil, nil))
@@ -285,3 +308,84 @@ func TestNodeStore(t *testing.T) {
 		}
 	}
 }
+
+func TestNodeBootID(t *testing.T) {
+	g := createNodeBootIDFamilyGenerator()
+
+	for _, c := range []generateMetricsTestCase{
+		{
+			Obj: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{BootID: "boot1"},
+				},
+			},
+			Want: `
+				# HELP kube_node_boot_id The boot ID of the node, which changes whenever the node reboots.
+				# TYPE kube_node_boot_id gauge
+				kube_node_boot_id{boot_id="boot1",node="node1"} 1
+			`,
+		},
+		{
+			Obj: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+			Want: `
+				# HELP kube_node_boot_id The boot ID of the node, which changes whenever the node reboots.
+				# TYPE kube_node_boot_id gauge
+			`,
+		},
+	} {
+		c.MetricNames = []string{"kube_node_boot_id"}
+		c.Func = generator.ComposeMetricGenFuncs([]generator.FamilyGenerator{g})
+		c.Headers = generator.ExtractMetricFamilyHeaders([]generator.FamilyGenerator{g})
+		if err := c.run(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestNodeStatusSwapCapacity(t *testing.T) {
+	g := createNodeStatusSwapCapacityFamilyGenerator()
+	swapCapacity := int64(4294967296)
+
+	for _, c := range []generateMetricsTestCase{
+		{
+			Obj: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{Swap: &v1.NodeSwapStatus{Capacity: &swapCapacity}},
+				},
+			},
+			Want: `
+				# HELP kube_node_status_swap_capacity_bytes The swap memory available on the node, as reported by the kubelet.
+				# TYPE kube_node_status_swap_capacity_bytes gauge
+				kube_node_status_swap_capacity_bytes{node="node1"} 4.294967296e+09
+			`,
+		},
+		{
+			Obj: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{Swap: &v1.NodeSwapStatus{}},
+				},
+			},
+			Want: `
+				# HELP kube_node_status_swap_capacity_bytes The swap memory available on the node, as reported by the kubelet.
+				# TYPE kube_node_status_swap_capacity_bytes gauge
+			`,
+		},
+		{
+			Obj: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node3"}},
+			Want: `
+				# HELP kube_node_status_swap_capacity_bytes The swap memory available on the node, as reported by the kubelet.
+				# TYPE kube_node_status_swap_capacity_bytes gauge
+			`,
+		},
+	} {
+		c.MetricNames = []string{"kube_node_status_swap_capacity_bytes"}
+		c.Func = generator.ComposeMetricGenFuncs([]generator.FamilyGenerator{g})
+		c.Headers = generator.ExtractMetricFamilyHeaders([]generator.FamilyGenerator{g})
+		if err := c.run(); err != nil {
+			t.Error(err)
+		}
+	}
+}
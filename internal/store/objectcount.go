@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+// objectCountResource is the resource name of the metrics writer of the
+// object counts, e.g. in the metrics of the stores.
+const objectCountResource = "objectcounts"
+
+// objectCountLabels are the labels the objects are counted by.
+var objectCountLabels = []string{"group", "version", "kind", "namespace"}
+
+// objectCountMetricFamilies returns the family counting the objects of the
+// given resource. Each object contributes a series of value 1, which are
+// summed by objectCountLabels when they are written.
+func objectCountMetricFamilies(r metav1.APIResource) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_objectcount",
+			"Number of objects per kind and namespace, counted from the metadata of the objects.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			func(obj interface{}) *metric.Family {
+				o, err := meta.Accessor(obj)
+				if err != nil {
+					return &metric.Family{}
+				}
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   objectCountLabels,
+							LabelValues: []string{r.Group, r.Version, r.Kind, o.GetNamespace()},
+							Value:       1,
+						},
+					},
+				}
+			},
+		),
+	}
+}
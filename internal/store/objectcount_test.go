@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+func TestObjectCount(t *testing.T) {
+	newStore := func(r metav1.APIResource, objs ...metav1.ObjectMeta) *metricsstore.MetricsStore {
+		families := objectCountMetricFamilies(r)
+		store := metricsstore.NewMetricsStore(generator.ExtractMetricFamilyHeaders(families), generator.ComposeMetricGenFuncs(families))
+		store.SetAggregateBy(objectCountLabels)
+		for _, o := range objs {
+			o.UID = types.UID(o.Namespace + "/" + o.Name)
+			if err := store.Add(&metav1.PartialObjectMetadata{ObjectMeta: o}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return store
+	}
+	pods := newStore(metav1.APIResource{Name: "pods", Version: "v1", Kind: "Pod", Namespaced: true},
+		metav1.ObjectMeta{Namespace: "ns1", Name: "a"},
+		metav1.ObjectMeta{Namespace: "ns1", Name: "b"},
+		metav1.ObjectMeta{Namespace: "ns2", Name: "a"},
+	)
+	deployments := newStore(metav1.APIResource{Name: "deployments", Group: "apps", Version: "v1", Kind: "Deployment", Namespaced: true},
+		metav1.ObjectMeta{Namespace: "ns1", Name: "a"},
+	)
+	nodes := newStore(metav1.APIResource{Name: "nodes", Version: "v1", Kind: "Node"},
+		metav1.ObjectMeta{Name: "a"},
+		metav1.ObjectMeta{Name: "b"},
+	)
+
+	mw := metricsstore.NewResourceMetricsWriter(objectCountResource, pods, deployments, nodes)
+	w := strings.Builder{}
+	if err := mw.WriteAll(&w); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP kube_objectcount Number of objects per kind and namespace, counted from the metadata of the objects.
+# TYPE kube_objectcount gauge
+kube_objectcount{group="apps",version="v1",kind="Deployment",namespace="ns1"} 1
+kube_objectcount{group="",version="v1",kind="Node",namespace=""} 2
+kube_objectcount{group="",version="v1",kind="Pod",namespace="ns1"} 2
+kube_objectcount{group="",version="v1",kind="Pod",namespace="ns2"} 1
+`
+	if w.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, w.String())
+	}
+}
@@ -19,6 +19,7 @@ package store
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	basemetrics "k8s.io/component-base/metrics"
 
@@ -179,7 +180,8 @@ func persistentVolumeMetricFamilies(allowAnnotationsList, allowLabelsList []stri
 					nfsServer, nfsPath,
 					csiDriver, csiVolumeHandle,
 					localFS, localPath,
-					hostPath, hostPathType string
+					hostPath, hostPathType,
+					volumeAttributesClass string
 				)
 
 				switch {
@@ -229,6 +231,9 @@ func persistentVolumeMetricFamilies(allowAnnotationsList, allowLabelsList []stri
 						hostPathType = string(*p.Spec.PersistentVolumeSource.HostPath.Type)
 					}
 				}
+				if p.Spec.VolumeAttributesClassName != nil {
+					volumeAttributesClass = *p.Spec.VolumeAttributesClassName
+				}
 
 				return &metric.Family{
 					Metrics: []*metric.Metric{
@@ -253,6 +258,7 @@ func persistentVolumeMetricFamilies(allowAnnotationsList, allowLabelsList []stri
 								"local_fs",
 								"host_path",
 								"host_path_type",
+								"volumeattributesclass",
 							},
 							LabelValues: []string{
 								p.Spec.StorageClassName,
@@ -274,6 +280,7 @@ func persistentVolumeMetricFamilies(allowAnnotationsList, allowLabelsList []stri
 								localFS,
 								hostPath,
 								hostPathType,
+								volumeAttributesClass,
 							},
 							Value: 1,
 						},
@@ -298,6 +305,45 @@ func persistentVolumeMetricFamilies(allowAnnotationsList, allowLabelsList []stri
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_persistentvolume_node_affinity",
+			"Information about the node selector requirements of the node affinity of the persistentvolume.",
+			metric.Gauge,
+			"",
+			wrapPersistentVolumeFunc(func(p *v1.PersistentVolume) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if p.Spec.NodeAffinity == nil || p.Spec.NodeAffinity.Required == nil {
+					return &metric.Family{
+						Metrics: ms,
+					}
+				}
+
+				for i, term := range p.Spec.NodeAffinity.Required.NodeSelectorTerms {
+					termIndex := strconv.Itoa(i)
+					for _, r := range term.MatchExpressions {
+						ms = append(ms, &metric.Metric{
+							LabelValues: []string{termIndex, "expression", r.Key, string(r.Operator), strings.Join(r.Values, ",")},
+							Value:       1,
+						})
+					}
+					for _, r := range term.MatchFields {
+						ms = append(ms, &metric.Metric{
+							LabelValues: []string{termIndex, "field", r.Key, string(r.Operator), strings.Join(r.Values, ",")},
+							Value:       1,
+						})
+					}
+				}
+
+				for _, m := range ms {
+					m.LabelKeys = []string{"term", "match", "key", "operator", "values"}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGenerator(
 			"kube_persistentvolume_created",
 			"Unix creation timestamp",
@@ -38,6 +38,28 @@ var (
 	descPersistentVolumeClaimLabelsName          = "kube_persistentvolumeclaim_labels"
 	descPersistentVolumeClaimLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
 	descPersistentVolumeClaimLabelsDefaultLabels = []string{"namespace", "persistentvolumeclaim"}
+
+	persistentVolumeClaimResizeConditions = []v1.PersistentVolumeClaimConditionType{
+		v1.PersistentVolumeClaimResizing,
+		v1.PersistentVolumeClaimFileSystemResizePending,
+	}
+	// persistentVolumeClaimResourceStatuses are the statuses of the
+	// expansion of a resource in status.allocatedResourceStatuses.
+	persistentVolumeClaimResourceStatuses = []v1.ClaimResourceStatus{
+		v1.PersistentVolumeClaimControllerResizeInProgress,
+		v1.PersistentVolumeClaimControllerResizeInfeasible,
+		v1.PersistentVolumeClaimNodeResizePending,
+		v1.PersistentVolumeClaimNodeResizeInProgress,
+		v1.PersistentVolumeClaimNodeResizeInfeasible,
+	}
+	// persistentVolumeClaimModifyVolumeStatuses are the statuses of the
+	// modification of a volume to another VolumeAttributesClass in
+	// status.modifyVolumeStatus.
+	persistentVolumeClaimModifyVolumeStatuses = []v1.PersistentVolumeClaimModifyVolumeStatus{
+		v1.PersistentVolumeClaimModifyVolumePending,
+		v1.PersistentVolumeClaimModifyVolumeInProgress,
+		v1.PersistentVolumeClaimModifyVolumeInfeasible,
+	}
 )
 
 func persistentVolumeClaimMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
@@ -207,6 +229,156 @@ func persistentVolumeClaimMetricFamilies(allowAnnotationsList, allowLabelsList [
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_persistentvolumeclaim_status_resize_condition",
+			"Whether a resize related condition of the persistent volume claim is true.",
+			metric.Gauge,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				ms := make([]*metric.Metric, len(persistentVolumeClaimResizeConditions))
+
+				for i, t := range persistentVolumeClaimResizeConditions {
+					value := false
+					for _, c := range p.Status.Conditions {
+						if c.Type == t {
+							value = c.Status == v1.ConditionTrue
+							break
+						}
+					}
+					ms[i] = &metric.Metric{
+						LabelKeys:   []string{"condition"},
+						LabelValues: []string{string(t)},
+						Value:       boolFloat64(value),
+					}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_persistentvolumeclaim_status_allocated_resources_storage_bytes",
+			"The storage capacity allocated to the persistent volume claim, which is set once a volume expansion was started.",
+			metric.Gauge,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if storage, ok := p.Status.AllocatedResources[v1.ResourceStorage]; ok {
+					ms = append(ms, &metric.Metric{
+						Value: float64(storage.Value()),
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_persistentvolumeclaim_status_allocated_resource_status",
+			"The status of the expansion of a resource of the persistent volume claim.",
+			metric.Gauge,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				ms := []*metric.Metric{}
+				for resource, status := range p.Status.AllocatedResourceStatuses {
+					known := false
+					for _, s := range persistentVolumeClaimResourceStatuses {
+						known = known || status == s
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"resource", "status"},
+							LabelValues: []string{string(resource), string(s)},
+							Value:       boolFloat64(status == s),
+						})
+					}
+					if !known {
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"resource", "status"},
+							LabelValues: []string{string(resource), string(status)},
+							Value:       1,
+						})
+					}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_persistentvolumeclaim_volumeattributesclass_info",
+			"The VolumeAttributesClass requested by the persistent volume claim.",
+			metric.Gauge,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				if p.Spec.VolumeAttributesClassName == nil {
+					return &metric.Family{}
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{{
+						LabelKeys:   []string{"volumeattributesclass"},
+						LabelValues: []string{*p.Spec.VolumeAttributesClassName},
+						Value:       1,
+					}},
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_persistentvolumeclaim_status_current_volumeattributesclass_info",
+			"The VolumeAttributesClass currently applied to the volume of the persistent volume claim.",
+			metric.Gauge,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				if p.Status.CurrentVolumeAttributesClassName == nil {
+					return &metric.Family{}
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{{
+						LabelKeys:   []string{"volumeattributesclass"},
+						LabelValues: []string{*p.Status.CurrentVolumeAttributesClassName},
+						Value:       1,
+					}},
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_persistentvolumeclaim_status_modify_volume_status",
+			"The status of the modification of the volume of the persistent volume claim to another VolumeAttributesClass.",
+			metric.Gauge,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				s := p.Status.ModifyVolumeStatus
+				if s == nil {
+					return &metric.Family{}
+				}
+
+				ms := []*metric.Metric{}
+				known := false
+				for _, status := range persistentVolumeClaimModifyVolumeStatuses {
+					known = known || s.Status == status
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"volumeattributesclass", "status"},
+						LabelValues: []string{s.TargetVolumeAttributesClassName, string(status)},
+						Value:       boolFloat64(s.Status == status),
+					})
+				}
+				if !known {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"volumeattributesclass", "status"},
+						LabelValues: []string{s.TargetVolumeAttributesClassName, string(s.Status)},
+						Value:       1,
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGenerator(
 			"kube_persistentvolumeclaim_created",
 			"Unix creation timestamp",
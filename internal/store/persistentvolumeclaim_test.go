@@ -29,6 +29,8 @@ import (
 
 func TestPersistentVolumeClaimStore(t *testing.T) {
 	storageClassName := "rbd"
+	goldClassName := "gold"
+	silverClassName := "silver"
 	cases := []generateMetricsTestCase{
 		// Verify phase enumerations.
 		{
@@ -50,7 +52,7 @@ func TestPersistentVolumeClaimStore(t *testing.T) {
 						v1.ReadWriteOnce,
 					},
 					StorageClassName: &storageClassName,
-					Resources: v1.ResourceRequirements{
+					Resources: v1.VolumeResourceRequirements{
 						Requests: v1.ResourceList{
 							v1.ResourceStorage: resource.MustParse("1Gi"),
 						},
@@ -122,7 +124,7 @@ func TestPersistentVolumeClaimStore(t *testing.T) {
 						v1.ReadWriteOnce,
 					},
 					StorageClassName: &storageClassName,
-					Resources: v1.ResourceRequirements{
+					Resources: v1.VolumeResourceRequirements{
 						Requests: v1.ResourceList{
 							v1.ResourceStorage: resource.MustParse("1Gi"),
 						},
@@ -276,6 +278,99 @@ func TestPersistentVolumeClaimStore(t *testing.T) {
 `,
 			MetricNames: []string{"kube_persistentvolumeclaim_created", "kube_persistentvolumeclaim_info", "kube_persistentvolumeclaim_status_phase", "kube_persistentvolumeclaim_resource_requests_storage_bytes", "kube_persistentvolumeclaim_annotations", "kube_persistentvolumeclaim_labels", "kube_persistentvolumeclaim_access_mode", "kube_persistentvolumeclaim_status_condition"},
 		},
+		{
+			Obj: &v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "postgres-data",
+					Namespace: "default",
+					UID:       "uid-postgres-data",
+				},
+				Status: v1.PersistentVolumeClaimStatus{
+					Phase: v1.ClaimBound,
+					AllocatedResources: v1.ResourceList{
+						v1.ResourceStorage: resource.MustParse("20Gi"),
+					},
+					AllocatedResourceStatuses: map[v1.ResourceName]v1.ClaimResourceStatus{
+						v1.ResourceStorage: v1.PersistentVolumeClaimNodeResizeInfeasible,
+					},
+					Conditions: []v1.PersistentVolumeClaimCondition{
+						{Type: v1.PersistentVolumeClaimFileSystemResizePending, Status: v1.ConditionTrue},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_persistentvolumeclaim_status_allocated_resource_status The status of the expansion of a resource of the persistent volume claim.
+				# HELP kube_persistentvolumeclaim_status_allocated_resources_storage_bytes The storage capacity allocated to the persistent volume claim, which is set once a volume expansion was started.
+				# HELP kube_persistentvolumeclaim_status_resize_condition Whether a resize related condition of the persistent volume claim is true.
+				# TYPE kube_persistentvolumeclaim_status_allocated_resource_status gauge
+				# TYPE kube_persistentvolumeclaim_status_allocated_resources_storage_bytes gauge
+				# TYPE kube_persistentvolumeclaim_status_resize_condition gauge
+				kube_persistentvolumeclaim_status_allocated_resource_status{namespace="default",persistentvolumeclaim="postgres-data",resource="storage",status="ControllerResizeInfeasible"} 0
+				kube_persistentvolumeclaim_status_allocated_resource_status{namespace="default",persistentvolumeclaim="postgres-data",resource="storage",status="ControllerResizeInProgress"} 0
+				kube_persistentvolumeclaim_status_allocated_resource_status{namespace="default",persistentvolumeclaim="postgres-data",resource="storage",status="NodeResizeInfeasible"} 1
+				kube_persistentvolumeclaim_status_allocated_resource_status{namespace="default",persistentvolumeclaim="postgres-data",resource="storage",status="NodeResizeInProgress"} 0
+				kube_persistentvolumeclaim_status_allocated_resource_status{namespace="default",persistentvolumeclaim="postgres-data",resource="storage",status="NodeResizePending"} 0
+				kube_persistentvolumeclaim_status_allocated_resources_storage_bytes{namespace="default",persistentvolumeclaim="postgres-data"} 2.147483648e+10
+				kube_persistentvolumeclaim_status_resize_condition{namespace="default",persistentvolumeclaim="postgres-data",condition="FileSystemResizePending"} 1
+				kube_persistentvolumeclaim_status_resize_condition{namespace="default",persistentvolumeclaim="postgres-data",condition="Resizing"} 0
+`,
+			MetricNames: []string{"kube_persistentvolumeclaim_status_allocated_resource_status", "kube_persistentvolumeclaim_status_allocated_resources_storage_bytes", "kube_persistentvolumeclaim_status_resize_condition"},
+		},
+		{
+			Obj: &v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "redis-data",
+					Namespace: "default",
+				},
+				Status: v1.PersistentVolumeClaimStatus{
+					Phase: v1.ClaimBound,
+				},
+			},
+			Want: `
+				# HELP kube_persistentvolumeclaim_status_allocated_resource_status The status of the expansion of a resource of the persistent volume claim.
+				# HELP kube_persistentvolumeclaim_status_allocated_resources_storage_bytes The storage capacity allocated to the persistent volume claim, which is set once a volume expansion was started.
+				# HELP kube_persistentvolumeclaim_status_resize_condition Whether a resize related condition of the persistent volume claim is true.
+				# TYPE kube_persistentvolumeclaim_status_allocated_resource_status gauge
+				# TYPE kube_persistentvolumeclaim_status_allocated_resources_storage_bytes gauge
+				# TYPE kube_persistentvolumeclaim_status_resize_condition gauge
+				kube_persistentvolumeclaim_status_resize_condition{namespace="default",persistentvolumeclaim="redis-data",condition="FileSystemResizePending"} 0
+				kube_persistentvolumeclaim_status_resize_condition{namespace="default",persistentvolumeclaim="redis-data",condition="Resizing"} 0
+`,
+			MetricNames: []string{"kube_persistentvolumeclaim_status_allocated_resource_status", "kube_persistentvolumeclaim_status_allocated_resources_storage_bytes", "kube_persistentvolumeclaim_status_resize_condition"},
+		},
+		{
+			Obj: &v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "etcd-data",
+					Namespace: "default",
+				},
+				Spec: v1.PersistentVolumeClaimSpec{
+					VolumeAttributesClassName: &goldClassName,
+				},
+				Status: v1.PersistentVolumeClaimStatus{
+					Phase:                            v1.ClaimBound,
+					CurrentVolumeAttributesClassName: &silverClassName,
+					ModifyVolumeStatus: &v1.ModifyVolumeStatus{
+						TargetVolumeAttributesClassName: goldClassName,
+						Status:                          v1.PersistentVolumeClaimModifyVolumeInProgress,
+					},
+				},
+			},
+			Want: `
+				# HELP kube_persistentvolumeclaim_status_current_volumeattributesclass_info The VolumeAttributesClass currently applied to the volume of the persistent volume claim.
+				# HELP kube_persistentvolumeclaim_status_modify_volume_status The status of the modification of the volume of the persistent volume claim to another VolumeAttributesClass.
+				# HELP kube_persistentvolumeclaim_volumeattributesclass_info The VolumeAttributesClass requested by the persistent volume claim.
+				# TYPE kube_persistentvolumeclaim_status_current_volumeattributesclass_info gauge
+				# TYPE kube_persistentvolumeclaim_status_modify_volume_status gauge
+				# TYPE kube_persistentvolumeclaim_volumeattributesclass_info gauge
+				kube_persistentvolumeclaim_status_current_volumeattributesclass_info{namespace="default",persistentvolumeclaim="etcd-data",volumeattributesclass="silver"} 1
+				kube_persistentvolumeclaim_status_modify_volume_status{namespace="default",persistentvolumeclaim="etcd-data",status="InProgress",volumeattributesclass="gold"} 1
+				kube_persistentvolumeclaim_status_modify_volume_status{namespace="default",persistentvolumeclaim="etcd-data",status="Infeasible",volumeattributesclass="gold"} 0
+				kube_persistentvolumeclaim_status_modify_volume_status{namespace="default",persistentvolumeclaim="etcd-data",status="Pending",volumeattributesclass="gold"} 0
+				kube_persistentvolumeclaim_volumeattributesclass_info{namespace="default",persistentvolumeclaim="etcd-data",volumeattributesclass="gold"} 1
+`,
+			MetricNames: []string{"kube_persistentvolumeclaim_status_current_volumeattributesclass_info", "kube_persistentvolumeclaim_status_modify_volume_status", "kube_persistentvolumeclaim_volumeattributesclass_info"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(persistentVolumeClaimMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
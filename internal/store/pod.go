@@ -38,17 +38,29 @@ import (
 var (
 	descPodLabelsDefaultLabels = []string{"namespace", "pod", "uid"}
 	podStatusReasons           = []string{"Evicted", "NodeAffinity", "NodeLost", "Shutdown", "UnexpectedAdmissionError"}
+	// podResizeConditions maps the pod conditions of in-place pod resizes to
+	// their status label.
+	podResizeConditions = map[v1.PodConditionType]string{
+		v1.PodResizePending:    "pending",
+		v1.PodResizeInProgress: "in_progress",
+	}
 )
 
-func podMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+func podMetricFamilies(allowAnnotationsList, allowLabelsList, statusReasons []string) []generator.FamilyGenerator {
 	return []generator.FamilyGenerator{
 		createPodCompletionTimeFamilyGenerator(),
 		createPodContainerInfoFamilyGenerator(),
 		createPodContainerResourceLimitsFamilyGenerator(),
 		createPodContainerResourceRequestsFamilyGenerator(),
 		createPodContainerStateStartedFamilyGenerator(),
+		createPodContainerStatusAllocatedResourcesFamilyGenerator(),
+		createPodContainerStatusResourceLimitsFamilyGenerator(),
+		createPodContainerStatusResourceRequestsFamilyGenerator(),
 		createPodContainerStatusLastTerminatedReasonFamilyGenerator(),
 		createPodContainerStatusLastTerminatedExitCodeFamilyGenerator(),
+		createPodContainerStatusLastTerminatedFinishedFamilyGenerator(),
+		createPodContainerStatusLastTerminatedInfoFamilyGenerator(),
+		createPodContainerStatusLastTerminatedStartedFamilyGenerator(),
 		createPodContainerStatusReadyFamilyGenerator(),
 		createPodContainerStatusRestartsTotalFamilyGenerator(),
 		createPodContainerStatusRunningFamilyGenerator(),
@@ -56,8 +68,18 @@ func podMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 		createPodContainerStatusTerminatedReasonFamilyGenerator(),
 		createPodContainerStatusWaitingFamilyGenerator(),
 		createPodContainerStatusWaitingReasonFamilyGenerator(),
+		createPodContainerWindowsOptionsInfoFamilyGenerator(),
 		createPodCreatedFamilyGenerator(),
 		createPodDeletionTimestampFamilyGenerator(),
+		createPodEffectiveResourceRequestsFamilyGenerator(),
+		createPodEphemeralContainerInfoFamilyGenerator(),
+		createPodEphemeralContainerStateStartedFamilyGenerator(),
+		createPodEphemeralContainerStatusRestartsTotalFamilyGenerator(),
+		createPodEphemeralContainerStatusRunningFamilyGenerator(),
+		createPodEphemeralContainerStatusTerminatedFamilyGenerator(),
+		createPodEphemeralContainerStatusTerminatedReasonFamilyGenerator(),
+		createPodEphemeralContainerStatusWaitingFamilyGenerator(),
+		createPodEphemeralContainerStatusWaitingReasonFamilyGenerator(),
 		createPodInfoFamilyGenerator(),
 		createPodIPFamilyGenerator(),
 		createPodInitContainerInfoFamilyGenerator(),
@@ -78,6 +100,8 @@ func podMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 		createPodOwnerFamilyGenerator(),
 		createPodRestartPolicyFamilyGenerator(),
 		createPodRuntimeClassNameInfoFamilyGenerator(),
+		createPodSpecOSInfoFamilyGenerator(),
+		createPodSpecPreemptionPolicyFamilyGenerator(),
 		createPodSpecVolumesPersistentVolumeClaimsInfoFamilyGenerator(),
 		createPodSpecVolumesPersistentVolumeClaimsReadonlyFamilyGenerator(),
 		createPodStartTimeFamilyGenerator(),
@@ -86,7 +110,9 @@ func podMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 		createPodStatusReadyFamilyGenerator(),
 		createPodStatusReadyTimeFamilyGenerator(),
 		createPodStatusContainerReadyTimeFamilyGenerator(),
-		createPodStatusReasonFamilyGenerator(),
+		createPodStatusReasonFamilyGenerator(statusReasons),
+		createPodStatusResizeFamilyGenerator(),
+		createPodStatusResourceClaimInfoFamilyGenerator(),
 		createPodStatusScheduledFamilyGenerator(),
 		createPodStatusScheduledTimeFamilyGenerator(),
 		createPodStatusUnschedulableFamilyGenerator(),
@@ -318,6 +344,101 @@ func createPodContainerStateStartedFamilyGenerator() generator.FamilyGenerator {
 	)
 }
 
+func createPodContainerStatusAllocatedResourcesFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_container_status_allocated_resources",
+		"The resources allocated to a container by the node, which differ from its requests while an in-place resize is pending.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			for _, cs := range p.Status.ContainerStatuses {
+				ms = append(ms, containerResourceMetrics(cs.Name, p.Spec.NodeName, cs.AllocatedResources)...)
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodContainerStatusResourceLimitsFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_container_status_resource_limits",
+		"The resource limits actually applied to a running container, which differ from its limits while an in-place resize is in progress.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			for _, cs := range p.Status.ContainerStatuses {
+				if cs.Resources != nil {
+					ms = append(ms, containerResourceMetrics(cs.Name, p.Spec.NodeName, cs.Resources.Limits)...)
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodContainerStatusResourceRequestsFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_container_status_resource_requests",
+		"The resource requests actually applied to a running container, which differ from its requests while an in-place resize is in progress.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			for _, cs := range p.Status.ContainerStatuses {
+				if cs.Resources != nil {
+					ms = append(ms, containerResourceMetrics(cs.Name, p.Spec.NodeName, cs.Resources.Requests)...)
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+// containerResourceMetrics returns a metric with the container, node,
+// resource and unit labels for each of the given resources of a container.
+func containerResourceMetrics(container, node string, resources v1.ResourceList) []*metric.Metric {
+	ms := []*metric.Metric{}
+	for resourceName, val := range resources {
+		switch {
+		case resourceName == v1.ResourceCPU:
+			ms = append(ms, &metric.Metric{
+				LabelValues: []string{container, node, sanitizeLabelName(string(resourceName)), string(constant.UnitCore)},
+				Value:       float64(val.MilliValue()) / 1000,
+			})
+		case resourceName == v1.ResourceStorage, resourceName == v1.ResourceEphemeralStorage, resourceName == v1.ResourceMemory,
+			isHugePageResourceName(resourceName), isAttachableVolumeResourceName(resourceName):
+			ms = append(ms, &metric.Metric{
+				LabelValues: []string{container, node, sanitizeLabelName(string(resourceName)), string(constant.UnitByte)},
+				Value:       float64(val.Value()),
+			})
+		case isExtendedResourceName(resourceName):
+			ms = append(ms, &metric.Metric{
+				LabelValues: []string{container, node, sanitizeLabelName(string(resourceName)), string(constant.UnitInteger)},
+				Value:       float64(val.Value()),
+			})
+		}
+	}
+
+	for _, metric := range ms {
+		metric.LabelKeys = []string{"container", "node", "resource", "unit"}
+	}
+	return ms
+}
+
 func createPodContainerStatusLastTerminatedReasonFamilyGenerator() generator.FamilyGenerator {
 	return *generator.NewFamilyGenerator(
 		"kube_pod_container_status_last_terminated_reason",
@@ -368,6 +489,81 @@ func createPodContainerStatusLastTerminatedExitCodeFamilyGenerator() generator.F
 	)
 }
 
+func createPodContainerStatusLastTerminatedFinishedFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_container_status_last_terminated_finished",
+		"Finish time in unix timestamp of the last terminated state of the container.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, 0, len(p.Status.ContainerStatuses))
+			for _, cs := range p.Status.ContainerStatuses {
+				if t := cs.LastTerminationState.Terminated; t != nil && !t.FinishedAt.IsZero() {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"container"},
+						LabelValues: []string{cs.Name},
+						Value:       float64(t.FinishedAt.Unix()),
+					})
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodContainerStatusLastTerminatedInfoFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_container_status_last_terminated_info",
+		"Information about the last terminated state of the container.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, 0, len(p.Status.ContainerStatuses))
+			for _, cs := range p.Status.ContainerStatuses {
+				if t := cs.LastTerminationState.Terminated; t != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"container", "reason", "signal"},
+						LabelValues: []string{cs.Name, t.Reason, strconv.FormatInt(int64(t.Signal), 10)},
+						Value:       1,
+					})
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodContainerStatusLastTerminatedStartedFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_container_status_last_terminated_started",
+		"Start time in unix timestamp of the last terminated state of the container.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, 0, len(p.Status.ContainerStatuses))
+			for _, cs := range p.Status.ContainerStatuses {
+				if t := cs.LastTerminationState.Terminated; t != nil && !t.StartedAt.IsZero() {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"container"},
+						LabelValues: []string{cs.Name},
+						Value:       float64(t.StartedAt.Unix()),
+					})
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func createPodContainerStatusReadyFamilyGenerator() generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		"kube_pod_container_status_ready",
@@ -542,6 +738,57 @@ func createPodContainerStatusWaitingReasonFamilyGenerator() generator.FamilyGene
 	)
 }
 
+func createPodContainerWindowsOptionsInfoFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_container_windows_options_info",
+		"The Windows specific security options of a container, including the ones inherited from the pod.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			var podOptions *v1.WindowsSecurityContextOptions
+			if p.Spec.SecurityContext != nil {
+				podOptions = p.Spec.SecurityContext.WindowsOptions
+			}
+			containers := append(append([]v1.Container{}, p.Spec.InitContainers...), p.Spec.Containers...)
+			for _, c := range containers {
+				var containerOptions *v1.WindowsSecurityContextOptions
+				if c.SecurityContext != nil {
+					containerOptions = c.SecurityContext.WindowsOptions
+				}
+				if podOptions == nil && containerOptions == nil {
+					continue
+				}
+
+				var hostProcess *bool
+				var runAsUserName *string
+				for _, o := range []*v1.WindowsSecurityContextOptions{podOptions, containerOptions} {
+					if o == nil {
+						continue
+					}
+					if o.HostProcess != nil {
+						hostProcess = o.HostProcess
+					}
+					if o.RunAsUserName != nil {
+						runAsUserName = o.RunAsUserName
+					}
+				}
+
+				ms = append(ms, &metric.Metric{
+					LabelKeys:   []string{"container", "host_process", "run_as_user_name"},
+					LabelValues: []string{c.Name, strconv.FormatBool(hostProcess != nil && *hostProcess), stringValue(runAsUserName)},
+					Value:       1,
+				})
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func createPodCreatedFamilyGenerator() generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		"kube_pod_created",
@@ -591,6 +838,317 @@ func createPodDeletionTimestampFamilyGenerator() generator.FamilyGenerator {
 	)
 }
 
+func createPodEffectiveResourceRequestsFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_effective_resource_requests",
+		"The resources requested by a pod, including its sidecar containers, as computed by the scheduler without the pod overhead.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			for resourceName, val := range podEffectiveResourceRequests(p) {
+				switch {
+				case resourceName == v1.ResourceCPU:
+					ms = append(ms, &metric.Metric{
+						LabelValues: []string{p.Spec.NodeName, sanitizeLabelName(string(resourceName)), string(constant.UnitCore)},
+						Value:       float64(val.MilliValue()) / 1000,
+					})
+				case resourceName == v1.ResourceStorage, resourceName == v1.ResourceEphemeralStorage, resourceName == v1.ResourceMemory,
+					isHugePageResourceName(resourceName), isAttachableVolumeResourceName(resourceName):
+					ms = append(ms, &metric.Metric{
+						LabelValues: []string{p.Spec.NodeName, sanitizeLabelName(string(resourceName)), string(constant.UnitByte)},
+						Value:       float64(val.Value()),
+					})
+				case isExtendedResourceName(resourceName):
+					ms = append(ms, &metric.Metric{
+						LabelValues: []string{p.Spec.NodeName, sanitizeLabelName(string(resourceName)), string(constant.UnitInteger)},
+						Value:       float64(val.Value()),
+					})
+				}
+			}
+
+			for _, metric := range ms {
+				metric.LabelKeys = []string{"node", "resource", "unit"}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+// podEffectiveResourceRequests returns the resources requested by the pod
+// like the scheduler computes them: the maximum of the requests of all
+// containers and sidecar containers, which keep running alongside them, and
+// of the requests of each init container together with the sidecar
+// containers started before it.
+func podEffectiveResourceRequests(p *v1.Pod) v1.ResourceList {
+	requests := v1.ResourceList{}
+	for _, c := range p.Spec.Containers {
+		addResourceList(requests, c.Resources.Requests)
+	}
+
+	sidecars := v1.ResourceList{}
+	initRequests := v1.ResourceList{}
+	for _, c := range p.Spec.InitContainers {
+		if isSidecarContainer(c) {
+			addResourceList(requests, c.Resources.Requests)
+			addResourceList(sidecars, c.Resources.Requests)
+			maxResourceList(initRequests, sidecars)
+			continue
+		}
+		current := sidecars.DeepCopy()
+		addResourceList(current, c.Resources.Requests)
+		maxResourceList(initRequests, current)
+	}
+	maxResourceList(requests, initRequests)
+	return requests
+}
+
+// isSidecarContainer returns whether the init container is a sidecar
+// container, i.e. keeps running alongside the containers of the pod.
+func isSidecarContainer(c v1.Container) bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
+
+// containerRestartPolicy returns the restart policy of the container, which
+// is only set for sidecar containers, or an empty string.
+func containerRestartPolicy(c v1.Container) string {
+	if c.RestartPolicy == nil {
+		return ""
+	}
+	return string(*c.RestartPolicy)
+}
+
+// addResourceList adds the quantities of src to the ones of dst.
+func addResourceList(dst, src v1.ResourceList) {
+	for name, q := range src {
+		sum := dst[name].DeepCopy()
+		sum.Add(q)
+		dst[name] = sum
+	}
+}
+
+// maxResourceList sets the quantities of dst to the ones of src which are
+// greater.
+func maxResourceList(dst, src v1.ResourceList) {
+	for name, q := range src {
+		if current, ok := dst[name]; !ok || q.Cmp(current) > 0 {
+			dst[name] = q.DeepCopy()
+		}
+	}
+}
+
+func createPodEphemeralContainerInfoFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_ephemeral_container_info",
+		"Information about an ephemeral container in a pod.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+			labelKeys := []string{"container", "image_spec", "image", "image_id", "container_id", "target_container"}
+
+			for _, c := range p.Spec.EphemeralContainers {
+				for _, cs := range p.Status.EphemeralContainerStatuses {
+					if cs.Name != c.Name {
+						continue
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   labelKeys,
+						LabelValues: []string{cs.Name, c.Image, cs.Image, cs.ImageID, cs.ContainerID, c.TargetContainerName},
+						Value:       1,
+					})
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodEphemeralContainerStateStartedFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_ephemeral_container_state_started",
+		"Start time in unix timestamp for an ephemeral container.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			for _, cs := range p.Status.EphemeralContainerStatuses {
+				if cs.State.Running != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"container"},
+						LabelValues: []string{cs.Name},
+						Value:       float64((cs.State.Running.StartedAt).Unix()),
+					})
+				} else if cs.State.Terminated != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"container"},
+						LabelValues: []string{cs.Name},
+						Value:       float64((cs.State.Terminated.StartedAt).Unix()),
+					})
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodEphemeralContainerStatusRestartsTotalFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_ephemeral_container_status_restarts_total",
+		"The number of restarts for the ephemeral container.",
+		metric.Counter,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, len(p.Status.EphemeralContainerStatuses))
+
+			for i, cs := range p.Status.EphemeralContainerStatuses {
+				ms[i] = &metric.Metric{
+					LabelKeys:   []string{"container"},
+					LabelValues: []string{cs.Name},
+					Value:       float64(cs.RestartCount),
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodEphemeralContainerStatusRunningFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_ephemeral_container_status_running",
+		"Describes whether the ephemeral container is currently in running state.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, len(p.Status.EphemeralContainerStatuses))
+
+			for i, cs := range p.Status.EphemeralContainerStatuses {
+				ms[i] = &metric.Metric{
+					LabelKeys:   []string{"container"},
+					LabelValues: []string{cs.Name},
+					Value:       boolFloat64(cs.State.Running != nil),
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodEphemeralContainerStatusTerminatedFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_ephemeral_container_status_terminated",
+		"Describes whether the ephemeral container is currently in terminated state.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, len(p.Status.EphemeralContainerStatuses))
+
+			for i, cs := range p.Status.EphemeralContainerStatuses {
+				ms[i] = &metric.Metric{
+					LabelKeys:   []string{"container"},
+					LabelValues: []string{cs.Name},
+					Value:       boolFloat64(cs.State.Terminated != nil),
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodEphemeralContainerStatusTerminatedReasonFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_ephemeral_container_status_terminated_reason",
+		"Describes the reason the ephemeral container is currently in terminated state.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, 0, len(p.Status.EphemeralContainerStatuses))
+			for _, cs := range p.Status.EphemeralContainerStatuses {
+				if cs.State.Terminated != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"container", "reason"},
+						LabelValues: []string{cs.Name, cs.State.Terminated.Reason},
+						Value:       1,
+					})
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodEphemeralContainerStatusWaitingFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_ephemeral_container_status_waiting",
+		"Describes whether the ephemeral container is currently in waiting state.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, len(p.Status.EphemeralContainerStatuses))
+
+			for i, cs := range p.Status.EphemeralContainerStatuses {
+				ms[i] = &metric.Metric{
+					LabelKeys:   []string{"container"},
+					LabelValues: []string{cs.Name},
+					Value:       boolFloat64(cs.State.Waiting != nil),
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodEphemeralContainerStatusWaitingReasonFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_ephemeral_container_status_waiting_reason",
+		"Describes the reason the ephemeral container is currently in waiting state.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, 0, len(p.Status.EphemeralContainerStatuses))
+			for _, cs := range p.Status.EphemeralContainerStatuses {
+				if cs.State.Waiting != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"container", "reason"},
+						LabelValues: []string{cs.Name, cs.State.Waiting.Reason},
+						Value:       1,
+					})
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func createPodInfoFamilyGenerator() generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		"kube_pod_info",
@@ -667,7 +1225,7 @@ func createPodInitContainerInfoFamilyGenerator() generator.FamilyGenerator {
 		"",
 		wrapPodFunc(func(p *v1.Pod) *metric.Family {
 			ms := []*metric.Metric{}
-			labelKeys := []string{"container", "image_spec", "image", "image_id", "container_id"}
+			labelKeys := []string{"container", "image_spec", "image", "image_id", "container_id", "restart_policy"}
 
 			for _, c := range p.Spec.InitContainers {
 				for _, cs := range p.Status.InitContainerStatuses {
@@ -676,7 +1234,7 @@ func createPodInitContainerInfoFamilyGenerator() generator.FamilyGenerator {
 					}
 					ms = append(ms, &metric.Metric{
 						LabelKeys:   labelKeys,
-						LabelValues: []string{cs.Name, c.Image, cs.Image, cs.ImageID, cs.ContainerID},
+						LabelValues: []string{cs.Name, c.Image, cs.Image, cs.ImageID, cs.ContainerID, containerRestartPolicy(c)},
 						Value:       1,
 					})
 				}
@@ -1198,6 +1756,62 @@ func createPodRuntimeClassNameInfoFamilyGenerator() generator.FamilyGenerator {
 	)
 }
 
+func createPodSpecOSInfoFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_spec_os_info",
+		"The operating system the pod is targeted at.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			if p.Spec.OS != nil {
+				ms = append(ms, &metric.Metric{
+					LabelKeys:   []string{"os_name"},
+					LabelValues: []string{string(p.Spec.OS.Name)},
+					Value:       1,
+				})
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodSpecPreemptionPolicyFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_spec_preemption_policy",
+		"The pods preemption policy.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			if p.Spec.PreemptionPolicy == nil {
+				return &metric.Family{
+					Metrics: []*metric.Metric{},
+				}
+			}
+			policy := *p.Spec.PreemptionPolicy
+
+			policies := []v1.PreemptionPolicy{v1.PreemptLowerPriority, v1.PreemptNever}
+			ms := make([]*metric.Metric, len(policies))
+
+			for i, pp := range policies {
+				ms[i] = &metric.Metric{
+					LabelKeys:   []string{"preemption_policy"},
+					LabelValues: []string{string(pp)},
+					Value:       boolFloat64(policy == pp),
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func createPodSpecVolumesPersistentVolumeClaimsInfoFamilyGenerator() generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		"kube_pod_spec_volumes_persistentvolumeclaims_info",
@@ -1445,7 +2059,21 @@ func createPodStatusReadyFamilyGenerator() generator.FamilyGenerator {
 	)
 }
 
-func createPodStatusReasonFamilyGenerator() generator.FamilyGenerator {
+// createPodStatusReasonFamilyGenerator exposes the well-known pod status
+// reasons as well as the given additional ones.
+func createPodStatusReasonFamilyGenerator(additionalReasons []string) generator.FamilyGenerator {
+	reasons := append([]string{}, podStatusReasons...)
+	known := make(map[string]struct{}, len(reasons))
+	for _, reason := range reasons {
+		known[reason] = struct{}{}
+	}
+	for _, reason := range additionalReasons {
+		if _, ok := known[reason]; ok || reason == "" {
+			continue
+		}
+		known[reason] = struct{}{}
+		reasons = append(reasons, reason)
+	}
 	return *generator.NewFamilyGenerator(
 		"kube_pod_status_reason",
 		"The pod status reasons",
@@ -1454,7 +2082,7 @@ func createPodStatusReasonFamilyGenerator() generator.FamilyGenerator {
 		wrapPodFunc(func(p *v1.Pod) *metric.Family {
 			ms := []*metric.Metric{}
 
-			for _, reason := range podStatusReasons {
+			for _, reason := range reasons {
 				metric := &metric.Metric{}
 				metric.LabelKeys = []string{"reason"}
 				metric.LabelValues = []string{reason}
@@ -1473,6 +2101,62 @@ func createPodStatusReasonFamilyGenerator() generator.FamilyGenerator {
 	)
 }
 
+func createPodStatusResizeFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_status_resize",
+		"Describes whether an in-place resize of the pod is pending or in progress.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			for _, c := range p.Status.Conditions {
+				status, ok := podResizeConditions[c.Type]
+				if !ok || c.Status != v1.ConditionTrue {
+					continue
+				}
+				ms = append(ms, &metric.Metric{
+					LabelKeys:   []string{"status", "reason"},
+					LabelValues: []string{status, c.Reason},
+					Value:       1,
+				})
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
+func createPodStatusResourceClaimInfoFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_pod_status_resource_claim_info",
+		"Information about the ResourceClaims generated for the resource claims of a pod.",
+		metric.Gauge,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := []*metric.Metric{}
+
+			for _, s := range p.Status.ResourceClaimStatuses {
+				// No ResourceClaim is generated if it was not necessary.
+				if s.ResourceClaimName == nil {
+					continue
+				}
+				ms = append(ms, &metric.Metric{
+					LabelKeys:   []string{"pod_claim", "resourceclaim"},
+					LabelValues: []string{s.Name, *s.ResourceClaimName},
+					Value:       1,
+				})
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func createPodStatusScheduledFamilyGenerator() generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		"kube_pod_status_scheduled",
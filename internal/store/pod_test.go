@@ -31,6 +31,11 @@ import (
 func TestPodStore(t *testing.T) {
 	var test = true
 	runtimeclass := "foo"
+	generatedClaim := "pod1-gpu-x7k2p"
+	preemptNever := v1.PreemptNever
+	hostProcess := true
+	runAsUserName := "NT AUTHORITY\\SYSTEM"
+	containerRunAsUserName := "NT AUTHORITY\\Local service"
 	startTime := 1501569018
 	metav1StartTime := metav1.Unix(int64(startTime), 0)
 
@@ -124,7 +129,7 @@ func TestPodStore(t *testing.T) {
 				# TYPE kube_pod_init_container_info gauge
 				kube_pod_container_info{container="container2",container_id="docker://cd456",image_spec="k8s.gcr.io/hyperkube2_spec",image="k8s.gcr.io/hyperkube2",image_id="docker://sha256:bbb",namespace="ns2",pod="pod2",uid="uid2"} 1
 				kube_pod_container_info{container="container3",container_id="docker://ef789",image_spec="k8s.gcr.io/hyperkube3_spec",image="k8s.gcr.io/hyperkube3",image_id="docker://sha256:ccc",namespace="ns2",pod="pod2",uid="uid2"} 1
-				kube_pod_init_container_info{container="initContainer",container_id="docker://ef123",image_spec="k8s.gcr.io/initfoo_spec",image="k8s.gcr.io/initfoo",image_id="docker://sha256:wxyz",namespace="ns2",pod="pod2",uid="uid2"} 1`,
+				kube_pod_init_container_info{container="initContainer",container_id="docker://ef123",image_spec="k8s.gcr.io/initfoo_spec",image="k8s.gcr.io/initfoo",image_id="docker://sha256:wxyz",namespace="ns2",pod="pod2",restart_policy="",uid="uid2"} 1`,
 			MetricNames: []string{"kube_pod_container_info", "kube_pod_init_container_info"},
 		},
 		{
@@ -2042,21 +2047,482 @@ func TestPodStore(t *testing.T) {
 				"kube_pod_tolerations",
 			},
 		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Spec: v1.PodSpec{
+					EphemeralContainers: []v1.EphemeralContainer{
+						{
+							EphemeralContainerCommon: v1.EphemeralContainerCommon{
+								Name:  "debugger1",
+								Image: "busybox:1.36_spec",
+							},
+							TargetContainerName: "container1",
+						},
+						{
+							EphemeralContainerCommon: v1.EphemeralContainerCommon{
+								Name:  "debugger2",
+								Image: "busybox:1.36_spec",
+							},
+						},
+						{
+							EphemeralContainerCommon: v1.EphemeralContainerCommon{
+								Name:  "debugger3",
+								Image: "busybox:1.36_spec",
+							},
+						},
+					},
+				},
+				Status: v1.PodStatus{
+					EphemeralContainerStatuses: []v1.ContainerStatus{
+						{
+							Name:        "debugger1",
+							Image:       "docker.io/library/busybox:1.36",
+							ImageID:     "docker://sha256:abc",
+							ContainerID: "docker://ab123",
+							State: v1.ContainerState{
+								Running: &v1.ContainerStateRunning{
+									StartedAt: metav1.Time{Time: time.Unix(1501777018, 0)},
+								},
+							},
+						},
+						{
+							Name:         "debugger2",
+							Image:        "docker.io/library/busybox:1.36",
+							ImageID:      "docker://sha256:abc",
+							ContainerID:  "docker://cd456",
+							RestartCount: 1,
+							State: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{
+									StartedAt: metav1.Time{Time: time.Unix(1501777018, 0)},
+									Reason:    "Completed",
+								},
+							},
+						},
+						{
+							Name: "debugger3",
+							State: v1.ContainerState{
+								Waiting: &v1.ContainerStateWaiting{
+									Reason: "ImagePullBackOff",
+								},
+							},
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_ephemeral_container_info Information about an ephemeral container in a pod.
+				# HELP kube_pod_ephemeral_container_state_started Start time in unix timestamp for an ephemeral container.
+				# HELP kube_pod_ephemeral_container_status_restarts_total The number of restarts for the ephemeral container.
+				# HELP kube_pod_ephemeral_container_status_running Describes whether the ephemeral container is currently in running state.
+				# HELP kube_pod_ephemeral_container_status_terminated Describes whether the ephemeral container is currently in terminated state.
+				# HELP kube_pod_ephemeral_container_status_terminated_reason Describes the reason the ephemeral container is currently in terminated state.
+				# HELP kube_pod_ephemeral_container_status_waiting Describes whether the ephemeral container is currently in waiting state.
+				# HELP kube_pod_ephemeral_container_status_waiting_reason Describes the reason the ephemeral container is currently in waiting state.
+				# TYPE kube_pod_ephemeral_container_info gauge
+				# TYPE kube_pod_ephemeral_container_state_started gauge
+				# TYPE kube_pod_ephemeral_container_status_restarts_total counter
+				# TYPE kube_pod_ephemeral_container_status_running gauge
+				# TYPE kube_pod_ephemeral_container_status_terminated gauge
+				# TYPE kube_pod_ephemeral_container_status_terminated_reason gauge
+				# TYPE kube_pod_ephemeral_container_status_waiting gauge
+				# TYPE kube_pod_ephemeral_container_status_waiting_reason gauge
+				kube_pod_ephemeral_container_info{container="debugger1",container_id="docker://ab123",image="docker.io/library/busybox:1.36",image_id="docker://sha256:abc",image_spec="busybox:1.36_spec",namespace="ns1",pod="pod1",target_container="container1",uid="uid1"} 1
+				kube_pod_ephemeral_container_info{container="debugger2",container_id="docker://cd456",image="docker.io/library/busybox:1.36",image_id="docker://sha256:abc",image_spec="busybox:1.36_spec",namespace="ns1",pod="pod1",target_container="",uid="uid1"} 1
+				kube_pod_ephemeral_container_info{container="debugger3",container_id="",image="",image_id="",image_spec="busybox:1.36_spec",namespace="ns1",pod="pod1",target_container="",uid="uid1"} 1
+				kube_pod_ephemeral_container_state_started{container="debugger1",namespace="ns1",pod="pod1",uid="uid1"} 1.501777018e+09
+				kube_pod_ephemeral_container_state_started{container="debugger2",namespace="ns1",pod="pod1",uid="uid1"} 1.501777018e+09
+				kube_pod_ephemeral_container_status_restarts_total{container="debugger1",namespace="ns1",pod="pod1",uid="uid1"} 0
+				kube_pod_ephemeral_container_status_restarts_total{container="debugger2",namespace="ns1",pod="pod1",uid="uid1"} 1
+				kube_pod_ephemeral_container_status_restarts_total{container="debugger3",namespace="ns1",pod="pod1",uid="uid1"} 0
+				kube_pod_ephemeral_container_status_running{container="debugger1",namespace="ns1",pod="pod1",uid="uid1"} 1
+				kube_pod_ephemeral_container_status_running{container="debugger2",namespace="ns1",pod="pod1",uid="uid1"} 0
+				kube_pod_ephemeral_container_status_running{container="debugger3",namespace="ns1",pod="pod1",uid="uid1"} 0
+				kube_pod_ephemeral_container_status_terminated{container="debugger1",namespace="ns1",pod="pod1",uid="uid1"} 0
+				kube_pod_ephemeral_container_status_terminated{container="debugger2",namespace="ns1",pod="pod1",uid="uid1"} 1
+				kube_pod_ephemeral_container_status_terminated{container="debugger3",namespace="ns1",pod="pod1",uid="uid1"} 0
+				kube_pod_ephemeral_container_status_terminated_reason{container="debugger2",namespace="ns1",pod="pod1",reason="Completed",uid="uid1"} 1
+				kube_pod_ephemeral_container_status_waiting{container="debugger1",namespace="ns1",pod="pod1",uid="uid1"} 0
+				kube_pod_ephemeral_container_status_waiting{container="debugger2",namespace="ns1",pod="pod1",uid="uid1"} 0
+				kube_pod_ephemeral_container_status_waiting{container="debugger3",namespace="ns1",pod="pod1",uid="uid1"} 1
+				kube_pod_ephemeral_container_status_waiting_reason{container="debugger3",namespace="ns1",pod="pod1",reason="ImagePullBackOff",uid="uid1"} 1
+			`,
+			MetricNames: []string{
+				"kube_pod_ephemeral_container_info",
+				"kube_pod_ephemeral_container_state_started",
+				"kube_pod_ephemeral_container_status_restarts_total",
+				"kube_pod_ephemeral_container_status_running",
+				"kube_pod_ephemeral_container_status_terminated",
+				"kube_pod_ephemeral_container_status_terminated_reason",
+				"kube_pod_ephemeral_container_status_waiting",
+				"kube_pod_ephemeral_container_status_waiting_reason",
+			},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Status: v1.PodStatus{
+					Conditions: []v1.PodCondition{
+						{
+							Type:   "PodResizePending",
+							Status: v1.ConditionTrue,
+							Reason: "Deferred",
+						},
+						{
+							Type:   "PodResizeInProgress",
+							Status: v1.ConditionFalse,
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_status_resize Describes whether an in-place resize of the pod is pending or in progress.
+				# TYPE kube_pod_status_resize gauge
+				kube_pod_status_resize{namespace="ns1",pod="pod1",reason="Deferred",status="pending",uid="uid1"} 1
+			`,
+			MetricNames: []string{"kube_pod_status_resize"},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Spec: v1.PodSpec{
+					NodeName: "node1",
+					Containers: []v1.Container{
+						{
+							Name: "container1",
+							Resources: v1.ResourceRequirements{
+								Requests: map[v1.ResourceName]resource.Quantity{
+									v1.ResourceCPU: resource.MustParse("400m"),
+								},
+							},
+						},
+					},
+				},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "container1",
+							AllocatedResources: map[v1.ResourceName]resource.Quantity{
+								v1.ResourceCPU: resource.MustParse("400m"),
+							},
+							Resources: &v1.ResourceRequirements{
+								Requests: map[v1.ResourceName]resource.Quantity{
+									v1.ResourceCPU: resource.MustParse("200m"),
+								},
+								Limits: map[v1.ResourceName]resource.Quantity{
+									v1.ResourceMemory: resource.MustParse("1Gi"),
+								},
+							},
+						},
+						{
+							Name: "container2",
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_container_status_allocated_resources The resources allocated to a container by the node, which differ from its requests while an in-place resize is pending.
+				# HELP kube_pod_container_status_resource_limits The resource limits actually applied to a running container, which differ from its limits while an in-place resize is in progress.
+				# HELP kube_pod_container_status_resource_requests The resource requests actually applied to a running container, which differ from its requests while an in-place resize is in progress.
+				# TYPE kube_pod_container_status_allocated_resources gauge
+				# TYPE kube_pod_container_status_resource_limits gauge
+				# TYPE kube_pod_container_status_resource_requests gauge
+				kube_pod_container_status_allocated_resources{container="container1",namespace="ns1",node="node1",pod="pod1",resource="cpu",uid="uid1",unit="core"} 0.4
+				kube_pod_container_status_resource_limits{container="container1",namespace="ns1",node="node1",pod="pod1",resource="memory",uid="uid1",unit="byte"} 1.073741824e+09
+				kube_pod_container_status_resource_requests{container="container1",namespace="ns1",node="node1",pod="pod1",resource="cpu",uid="uid1",unit="core"} 0.2
+			`,
+			MetricNames: []string{
+				"kube_pod_container_status_allocated_resources",
+				"kube_pod_container_status_resource_limits",
+				"kube_pod_container_status_resource_requests",
+			},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Status: v1.PodStatus{
+					ResourceClaimStatuses: []v1.PodResourceClaimStatus{
+						{
+							Name:              "gpu",
+							ResourceClaimName: &generatedClaim,
+						},
+						{
+							Name: "unneeded",
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_status_resource_claim_info Information about the ResourceClaims generated for the resource claims of a pod.
+				# TYPE kube_pod_status_resource_claim_info gauge
+				kube_pod_status_resource_claim_info{namespace="ns1",pod="pod1",pod_claim="gpu",resourceclaim="pod1-gpu-x7k2p",uid="uid1"} 1
+			`,
+			MetricNames: []string{"kube_pod_status_resource_claim_info"},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Spec: v1.PodSpec{
+					PreemptionPolicy: &preemptNever,
+				},
+			},
+			Want: `
+				# HELP kube_pod_spec_preemption_policy The pods preemption policy.
+				# TYPE kube_pod_spec_preemption_policy gauge
+				kube_pod_spec_preemption_policy{namespace="ns1",pod="pod1",preemption_policy="Never",uid="uid1"} 1
+				kube_pod_spec_preemption_policy{namespace="ns1",pod="pod1",preemption_policy="PreemptLowerPriority",uid="uid1"} 0
+			`,
+			MetricNames: []string{"kube_pod_spec_preemption_policy"},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Spec: v1.PodSpec{
+					OS: &v1.PodOS{Name: v1.Windows},
+					SecurityContext: &v1.PodSecurityContext{
+						WindowsOptions: &v1.WindowsSecurityContextOptions{
+							HostProcess:   &hostProcess,
+							RunAsUserName: &runAsUserName,
+						},
+					},
+					InitContainers: []v1.Container{
+						{Name: "init"},
+					},
+					Containers: []v1.Container{
+						{Name: "container1"},
+						{
+							Name: "container2",
+							SecurityContext: &v1.SecurityContext{
+								WindowsOptions: &v1.WindowsSecurityContextOptions{
+									RunAsUserName: &containerRunAsUserName,
+								},
+							},
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_container_windows_options_info The Windows specific security options of a container, including the ones inherited from the pod.
+				# HELP kube_pod_spec_os_info The operating system the pod is targeted at.
+				# TYPE kube_pod_container_windows_options_info gauge
+				# TYPE kube_pod_spec_os_info gauge
+				kube_pod_container_windows_options_info{container="container1",host_process="true",namespace="ns1",pod="pod1",run_as_user_name="NT AUTHORITY\\SYSTEM",uid="uid1"} 1
+				kube_pod_container_windows_options_info{container="container2",host_process="true",namespace="ns1",pod="pod1",run_as_user_name="NT AUTHORITY\\Local service",uid="uid1"} 1
+				kube_pod_container_windows_options_info{container="init",host_process="true",namespace="ns1",pod="pod1",run_as_user_name="NT AUTHORITY\\SYSTEM",uid="uid1"} 1
+				kube_pod_spec_os_info{namespace="ns1",os_name="windows",pod="pod1",uid="uid1"} 1
+			`,
+			MetricNames: []string{"kube_pod_container_windows_options_info", "kube_pod_spec_os_info"},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: "container1"},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_container_windows_options_info The Windows specific security options of a container, including the ones inherited from the pod.
+				# HELP kube_pod_spec_os_info The operating system the pod is targeted at.
+				# TYPE kube_pod_container_windows_options_info gauge
+				# TYPE kube_pod_spec_os_info gauge
+			`,
+			MetricNames: []string{"kube_pod_container_windows_options_info", "kube_pod_spec_os_info"},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "container1",
+							LastTerminationState: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{
+									StartedAt:  metav1.Time{Time: time.Unix(1501777018, 0)},
+									FinishedAt: metav1.Time{Time: time.Unix(1501777118, 0)},
+									Reason:     "Error",
+									ExitCode:   137,
+									Signal:     9,
+								},
+							},
+						},
+						{
+							Name: "container2",
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_container_status_last_terminated_finished Finish time in unix timestamp of the last terminated state of the container.
+				# HELP kube_pod_container_status_last_terminated_info Information about the last terminated state of the container.
+				# HELP kube_pod_container_status_last_terminated_started Start time in unix timestamp of the last terminated state of the container.
+				# TYPE kube_pod_container_status_last_terminated_finished gauge
+				# TYPE kube_pod_container_status_last_terminated_info gauge
+				# TYPE kube_pod_container_status_last_terminated_started gauge
+				kube_pod_container_status_last_terminated_finished{container="container1",namespace="ns1",pod="pod1",uid="uid1"} 1.501777118e+09
+				kube_pod_container_status_last_terminated_info{container="container1",namespace="ns1",pod="pod1",reason="Error",signal="9",uid="uid1"} 1
+				kube_pod_container_status_last_terminated_started{container="container1",namespace="ns1",pod="pod1",uid="uid1"} 1.501777018e+09
+			`,
+			MetricNames: []string{
+				"kube_pod_container_status_last_terminated_finished",
+				"kube_pod_container_status_last_terminated_info",
+				"kube_pod_container_status_last_terminated_started",
+			},
+		},
 	}
 
 	for i, c := range cases {
-		c.Func = generator.ComposeMetricGenFuncs(podMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
-		c.Headers = generator.ExtractMetricFamilyHeaders(podMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Func = generator.ComposeMetricGenFuncs(podMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList, nil))
+		c.Headers = generator.ExtractMetricFamilyHeaders(podMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList, nil))
 		if err := c.run(); err != nil {
 			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
 		}
 	}
 }
 
+func TestPodStatusReasonAdditionalReasons(t *testing.T) {
+	g := createPodStatusReasonFamilyGenerator([]string{"Terminated", "Evicted", ""})
+	c := generateMetricsTestCase{
+		Obj: &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod1",
+				Namespace: "ns1",
+				UID:       "uid1",
+			},
+			Status: v1.PodStatus{
+				Phase:  v1.PodFailed,
+				Reason: "Terminated",
+			},
+		},
+		Want: `
+			# HELP kube_pod_status_reason The pod status reasons
+			# TYPE kube_pod_status_reason gauge
+			kube_pod_status_reason{namespace="ns1",pod="pod1",reason="Evicted",uid="uid1"} 0
+			kube_pod_status_reason{namespace="ns1",pod="pod1",reason="NodeAffinity",uid="uid1"} 0
+			kube_pod_status_reason{namespace="ns1",pod="pod1",reason="NodeLost",uid="uid1"} 0
+			kube_pod_status_reason{namespace="ns1",pod="pod1",reason="Shutdown",uid="uid1"} 0
+			kube_pod_status_reason{namespace="ns1",pod="pod1",reason="Terminated",uid="uid1"} 1
+			kube_pod_status_reason{namespace="ns1",pod="pod1",reason="UnexpectedAdmissionError",uid="uid1"} 0
+		`,
+		MetricNames: []string{"kube_pod_status_reason"},
+		Func:        generator.ComposeMetricGenFuncs([]generator.FamilyGenerator{g}),
+		Headers:     generator.ExtractMetricFamilyHeaders([]generator.FamilyGenerator{g}),
+	}
+	if err := c.run(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPodSidecarContainers(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	requests := func(cpu, memory string) v1.ResourceRequirements {
+		return v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+	families := []generator.FamilyGenerator{
+		createPodEffectiveResourceRequestsFamilyGenerator(),
+		createPodInitContainerInfoFamilyGenerator(),
+	}
+	c := generateMetricsTestCase{
+		Obj: &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod1",
+				Namespace: "ns1",
+				UID:       "uid1",
+			},
+			Spec: v1.PodSpec{
+				NodeName: "node1",
+				InitContainers: []v1.Container{
+					{Name: "setup", Image: "setup", Resources: requests("2", "100Mi")},
+					{Name: "proxy", Image: "proxy", Resources: requests("500m", "64Mi"), RestartPolicy: &always},
+					{Name: "migrate", Image: "migrate", Resources: requests("250m", "1Gi")},
+				},
+				Containers: []v1.Container{
+					{Name: "app", Image: "app", Resources: requests("1", "256Mi")},
+				},
+			},
+			Status: v1.PodStatus{
+				InitContainerStatuses: []v1.ContainerStatus{
+					{Name: "setup", Image: "setup"},
+					{Name: "proxy", Image: "proxy"},
+					{Name: "migrate", Image: "migrate"},
+				},
+			},
+		},
+		Want: `
+			# HELP kube_pod_effective_resource_requests The resources requested by a pod, including its sidecar containers, as computed by the scheduler without the pod overhead.
+			# HELP kube_pod_init_container_info [STABLE] Information about an init container in a pod.
+			# TYPE kube_pod_effective_resource_requests gauge
+			# TYPE kube_pod_init_container_info gauge
+			kube_pod_effective_resource_requests{namespace="ns1",node="node1",pod="pod1",resource="cpu",uid="uid1",unit="core"} 2
+			kube_pod_effective_resource_requests{namespace="ns1",node="node1",pod="pod1",resource="memory",uid="uid1",unit="byte"} 1.140850688e+09
+			kube_pod_init_container_info{container="migrate",container_id="",image_spec="migrate",image="migrate",image_id="",namespace="ns1",pod="pod1",restart_policy="",uid="uid1"} 1
+			kube_pod_init_container_info{container="proxy",container_id="",image_spec="proxy",image="proxy",image_id="",namespace="ns1",pod="pod1",restart_policy="Always",uid="uid1"} 1
+			kube_pod_init_container_info{container="setup",container_id="",image_spec="setup",image="setup",image_id="",namespace="ns1",pod="pod1",restart_policy="",uid="uid1"} 1
+		`,
+		MetricNames: []string{"kube_pod_effective_resource_requests", "kube_pod_init_container_info"},
+		Func:        generator.ComposeMetricGenFuncs(families),
+		Headers:     generator.ExtractMetricFamilyHeaders(families),
+	}
+	if err := c.run(); err != nil {
+		t.Error(err)
+	}
+
+	// Without the restart policy, the proxy is a regular init container,
+	// whose requests are not added to the ones of the containers.
+	c.Obj.(*v1.Pod).Spec.InitContainers[1].RestartPolicy = nil
+	c.Obj.(*v1.Pod).Spec.InitContainers[0].Resources = requests("100m", "100Mi")
+	c.Want = `
+			# HELP kube_pod_effective_resource_requests The resources requested by a pod, including its sidecar containers, as computed by the scheduler without the pod overhead.
+			# TYPE kube_pod_effective_resource_requests gauge
+			kube_pod_effective_resource_requests{namespace="ns1",node="node1",pod="pod1",resource="cpu",uid="uid1",unit="core"} 1
+			kube_pod_effective_resource_requests{namespace="ns1",node="node1",pod="pod1",resource="memory",uid="uid1",unit="byte"} 1.073741824e+09
+		`
+	c.MetricNames = []string{"kube_pod_effective_resource_requests"}
+	if err := c.run(); err != nil {
+		t.Error(err)
+	}
+}
+
 func BenchmarkPodStore(b *testing.B) {
 	b.ReportAllocs()
 
-	f := generator.ComposeMetricGenFuncs(podMetricFamilies(nil, nil))
+	f := generator.ComposeMetricGenFuncs(podMetricFamilies(nil, nil, nil))
 
 	pod := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2137,7 +2603,7 @@ func BenchmarkPodStore(b *testing.B) {
 		},
 	}
 
-	expectedFamilies := 50
+	expectedFamilies := 69
 	for n := 0; n < b.N; n++ {
 		families := f(pod)
 		if len(families) != expectedFamilies {
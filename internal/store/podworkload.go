@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"sync"
+
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ownerIndexResources are the resources whose controllers are resolved by
+// kube_pod_workload_info, e.g. the Deployment of the ReplicaSet of a Pod.
+var ownerIndexResources = []struct {
+	schema.GroupVersionResource
+	kind string
+}{
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, "ReplicaSet"},
+	{schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, "Job"},
+}
+
+// ownerIndexMaxDepth limits the number of owners followed to resolve the
+// workload of a Pod.
+const ownerIndexMaxDepth = 8
+
+// ownerKey identifies an object by its namespace, group, kind and name.
+type ownerKey struct {
+	namespace string
+	groupKind schema.GroupKind
+	name      string
+}
+
+// ownerIndex indexes the controllers of objects which own Pods. It is fed by
+// reflectors watching the metadata of the objects of ownerIndexResources.
+type ownerIndex struct {
+	mtx    sync.RWMutex
+	owners map[ownerKey]metav1.OwnerReference
+
+	// pending is the number of stores which did not receive their initial
+	// list yet, synced is closed once it reaches 0.
+	pending int
+	synced  chan struct{}
+}
+
+func newOwnerIndex(stores int) *ownerIndex {
+	i := &ownerIndex{
+		owners:  map[ownerKey]metav1.OwnerReference{},
+		pending: stores,
+		synced:  make(chan struct{}),
+	}
+	if stores == 0 {
+		close(i.synced)
+	}
+	return i
+}
+
+// controllerOf returns the controller of the given object, if it is known.
+func (i *ownerIndex) controllerOf(key ownerKey) (metav1.OwnerReference, bool) {
+	i.mtx.RLock()
+	defer i.mtx.RUnlock()
+	ref, ok := i.owners[key]
+	return ref, ok
+}
+
+// resolve returns the kind and name of the workload of the given Pod. The
+// controller of the Pod is followed through the index, e.g. from a ReplicaSet
+// to its Deployment. Pods without controller are their own workload.
+func (i *ownerIndex) resolve(p *v1.Pod) (kind, name string) {
+	ref := metav1.GetControllerOf(p)
+	if ref == nil {
+		return "Pod", p.Name
+	}
+	for depth := 0; depth < ownerIndexMaxDepth; depth++ {
+		owner, ok := i.controllerOf(ownerKey{
+			namespace: p.Namespace,
+			groupKind: schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind(),
+			name:      ref.Name,
+		})
+		if !ok {
+			break
+		}
+		ref = &owner
+	}
+	return ref.Kind, ref.Name
+}
+
+// store returns a cache.Store indexing the controllers of objects of the
+// given kind in the given namespace, which is empty for all namespaces.
+func (i *ownerIndex) store(groupKind schema.GroupKind, namespace string) cache.Store {
+	return &ownerIndexStore{index: i, groupKind: groupKind, namespace: namespace}
+}
+
+// waitForSync returns a ListerWatcher whose lists wait until all stores of
+// the index received their initial list or ctx is done, so that the workloads
+// of the initially listed Pods are resolved.
+func (i *ownerIndex) waitForSync(ctx context.Context, lw cache.ListerWatcher) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			select {
+			case <-i.synced:
+			case <-ctx.Done():
+			}
+			return lw.List(opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return lw.Watch(opts)
+		},
+	}
+}
+
+// ownerIndexStore implements the cache.Store interface to add the
+// controllers of objects of one kind in one or all namespaces to an
+// ownerIndex.
+type ownerIndexStore struct {
+	index     *ownerIndex
+	groupKind schema.GroupKind
+	namespace string
+	replaced  bool
+}
+
+func (s *ownerIndexStore) key(o metav1.Object) ownerKey {
+	return ownerKey{namespace: o.GetNamespace(), groupKind: s.groupKind, name: o.GetName()}
+}
+
+// set indexes the controller of the given object, it has to be called with
+// the lock held.
+func (s *ownerIndexStore) set(obj interface{}) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	if ref := metav1.GetControllerOfNoCopy(o); ref != nil {
+		s.index.owners[s.key(o)] = *ref
+		return
+	}
+	delete(s.index.owners, s.key(o))
+}
+
+// Add implements the Add method of the store interface.
+func (s *ownerIndexStore) Add(obj interface{}) error {
+	s.index.mtx.Lock()
+	defer s.index.mtx.Unlock()
+	s.set(obj)
+	return nil
+}
+
+// Update implements the Update method of the store interface.
+func (s *ownerIndexStore) Update(obj interface{}) error {
+	return s.Add(obj)
+}
+
+// Delete implements the Delete method of the store interface.
+func (s *ownerIndexStore) Delete(obj interface{}) error {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	s.index.mtx.Lock()
+	defer s.index.mtx.Unlock()
+	delete(s.index.owners, s.key(o))
+	return nil
+}
+
+// List implements the List method of the store interface.
+func (s *ownerIndexStore) List() []interface{} {
+	return nil
+}
+
+// ListKeys implements the ListKeys method of the store interface.
+func (s *ownerIndexStore) ListKeys() []string {
+	return nil
+}
+
+// Get implements the Get method of the store interface.
+func (s *ownerIndexStore) Get(obj interface{}) (item interface{}, exists bool, err error) {
+	return nil, false, nil
+}
+
+// GetByKey implements the GetByKey method of the store interface.
+func (s *ownerIndexStore) GetByKey(key string) (item interface{}, exists bool, err error) {
+	return nil, false, nil
+}
+
+// Replace replaces the indexed controllers of objects of the kind of the
+// store in its namespace with the ones of the given objects.
+func (s *ownerIndexStore) Replace(list []interface{}, _ string) error {
+	s.index.mtx.Lock()
+	defer s.index.mtx.Unlock()
+	for k := range s.index.owners {
+		if k.groupKind == s.groupKind && (s.namespace == "" || k.namespace == s.namespace) {
+			delete(s.index.owners, k)
+		}
+	}
+	for _, obj := range list {
+		s.set(obj)
+	}
+	if !s.replaced {
+		s.replaced = true
+		s.index.pending--
+		if s.index.pending == 0 {
+			close(s.index.synced)
+		}
+	}
+	return nil
+}
+
+// Resync implements the Resync method of the store interface.
+func (s *ownerIndexStore) Resync() error {
+	return nil
+}
+
+func createPodWorkloadInfoFamilyGenerator(owners *ownerIndex) generator.FamilyGenerator {
+	return *generator.NewFamilyGeneratorWithStability(
+		"kube_pod_workload_info",
+		"Information about the workload of the Pod, resolved by following the controllers of its owners.",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			kind, name := owners.resolve(p)
+			return &metric.Family{
+				Metrics: []*metric.Metric{
+					{
+						LabelKeys:   []string{"workload_kind", "workload_name"},
+						LabelValues: []string{kind, name},
+						Value:       1,
+					},
+				},
+			}
+		}),
+	)
+}
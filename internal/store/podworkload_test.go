@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func controllerRef(apiVersion, kind, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, Controller: &controller}}
+}
+
+func TestPodWorkloadInfo(t *testing.T) {
+	owners := newOwnerIndex(2)
+	replicaSets := owners.store(schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}, "")
+	jobs := owners.store(schema.GroupKind{Group: "batch", Kind: "Job"}, "")
+	if err := replicaSets.Replace([]interface{}{
+		&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "rs1", Namespace: "ns1", OwnerReferences: controllerRef("apps/v1", "Deployment", "deploy1")}},
+		&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "rs2", Namespace: "ns1"}},
+	}, ""); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-owners.synced:
+		t.Fatal("owner index synced before all stores were replaced")
+	default:
+	}
+	if err := jobs.Replace(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-owners.synced:
+	default:
+		t.Fatal("owner index not synced after all stores were replaced")
+	}
+	if err := jobs.Add(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "ns1", OwnerReferences: controllerRef("batch/v1", "CronJob", "cronjob1")}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Add(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "job2", Namespace: "ns1", OwnerReferences: controllerRef("batch/v1", "CronJob", "cronjob2")}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Delete(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "job2", Namespace: "ns1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	g := createPodWorkloadInfoFamilyGenerator(owners)
+	pod := func(name string, owners []metav1.OwnerReference) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", UID: "uid1", OwnerReferences: owners}}
+	}
+	cases := []generateMetricsTestCase{
+		{
+			Obj: pod("pod1", controllerRef("apps/v1", "ReplicaSet", "rs1")),
+			Want: `
+				# HELP kube_pod_workload_info Information about the workload of the Pod, resolved by following the controllers of its owners.
+				# TYPE kube_pod_workload_info gauge
+				kube_pod_workload_info{namespace="ns1",pod="pod1",uid="uid1",workload_kind="Deployment",workload_name="deploy1"} 1
+			`,
+		},
+		{
+			Obj: pod("pod2", controllerRef("apps/v1", "ReplicaSet", "rs2")),
+			Want: `
+				# HELP kube_pod_workload_info Information about the workload of the Pod, resolved by following the controllers of its owners.
+				# TYPE kube_pod_workload_info gauge
+				kube_pod_workload_info{namespace="ns1",pod="pod2",uid="uid1",workload_kind="ReplicaSet",workload_name="rs2"} 1
+			`,
+		},
+		{
+			Obj: pod("pod3", controllerRef("batch/v1", "Job", "job1")),
+			Want: `
+				# HELP kube_pod_workload_info Information about the workload of the Pod, resolved by following the controllers of its owners.
+				# TYPE kube_pod_workload_info gauge
+				kube_pod_workload_info{namespace="ns1",pod="pod3",uid="uid1",workload_kind="CronJob",workload_name="cronjob1"} 1
+			`,
+		},
+		{
+			Obj: pod("pod4", controllerRef("batch/v1", "Job", "job2")),
+			Want: `
+				# HELP kube_pod_workload_info Information about the workload of the Pod, resolved by following the controllers of its owners.
+				# TYPE kube_pod_workload_info gauge
+				kube_pod_workload_info{namespace="ns1",pod="pod4",uid="uid1",workload_kind="Job",workload_name="job2"} 1
+			`,
+		},
+		{
+			Obj: pod("pod5", controllerRef("apps/v1", "StatefulSet", "sts1")),
+			Want: `
+				# HELP kube_pod_workload_info Information about the workload of the Pod, resolved by following the controllers of its owners.
+				# TYPE kube_pod_workload_info gauge
+				kube_pod_workload_info{namespace="ns1",pod="pod5",uid="uid1",workload_kind="StatefulSet",workload_name="sts1"} 1
+			`,
+		},
+		{
+			Obj: pod("pod6", nil),
+			Want: `
+				# HELP kube_pod_workload_info Information about the workload of the Pod, resolved by following the controllers of its owners.
+				# TYPE kube_pod_workload_info gauge
+				kube_pod_workload_info{namespace="ns1",pod="pod6",uid="uid1",workload_kind="Pod",workload_name="pod6"} 1
+			`,
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs([]generator.FamilyGenerator{g})
+		c.Headers = generator.ExtractMetricFamilyHeaders([]generator.FamilyGenerator{g})
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descPriorityLevelConfigurationAnnotationsName     = "kube_prioritylevelconfiguration_annotations"
+	descPriorityLevelConfigurationAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descPriorityLevelConfigurationLabelsName          = "kube_prioritylevelconfiguration_labels"
+	descPriorityLevelConfigurationLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descPriorityLevelConfigurationLabelsDefaultLabels = []string{"prioritylevelconfiguration"}
+)
+
+func priorityLevelConfigurationMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_prioritylevelconfiguration_info",
+			"Information about prioritylevelconfiguration.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPriorityLevelConfigurationFunc(func(p *flowcontrolv1.PriorityLevelConfiguration) *metric.Family {
+				limitResponse := ""
+				if p.Spec.Limited != nil {
+					limitResponse = string(p.Spec.Limited.LimitResponse.Type)
+				}
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"type", "limit_response"},
+							LabelValues: []string{string(p.Spec.Type), limitResponse},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_prioritylevelconfiguration_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPriorityLevelConfigurationFunc(func(p *flowcontrolv1.PriorityLevelConfiguration) *metric.Family {
+				ms := []*metric.Metric{}
+				if !p.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(p.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descPriorityLevelConfigurationAnnotationsName,
+			descPriorityLevelConfigurationAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPriorityLevelConfigurationFunc(func(p *flowcontrolv1.PriorityLevelConfiguration) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", p.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descPriorityLevelConfigurationLabelsName,
+			descPriorityLevelConfigurationLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPriorityLevelConfigurationFunc(func(p *flowcontrolv1.PriorityLevelConfiguration) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", p.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_prioritylevelconfiguration_nominal_concurrency_shares",
+			"The nominal concurrency shares of the limited prioritylevelconfiguration.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPriorityLevelConfigurationFunc(func(p *flowcontrolv1.PriorityLevelConfiguration) *metric.Family {
+				ms := []*metric.Metric{}
+				if p.Spec.Limited != nil && p.Spec.Limited.NominalConcurrencyShares != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(*p.Spec.Limited.NominalConcurrencyShares),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_prioritylevelconfiguration_lendable_percent",
+			"The percentage of the nominal concurrency limit of the limited prioritylevelconfiguration which can be borrowed by other priority levels.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPriorityLevelConfigurationFunc(func(p *flowcontrolv1.PriorityLevelConfiguration) *metric.Family {
+				ms := []*metric.Metric{}
+				if p.Spec.Limited != nil && p.Spec.Limited.LendablePercent != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(*p.Spec.Limited.LendablePercent),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_prioritylevelconfiguration_borrowing_limit_percent",
+			"The limit of the concurrency the limited prioritylevelconfiguration can borrow from other priority levels, as percentage of its nominal concurrency limit.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPriorityLevelConfigurationFunc(func(p *flowcontrolv1.PriorityLevelConfiguration) *metric.Family {
+				ms := []*metric.Metric{}
+				if p.Spec.Limited != nil && p.Spec.Limited.BorrowingLimitPercent != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(*p.Spec.Limited.BorrowingLimitPercent),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_prioritylevelconfiguration_status_condition",
+			"The condition of the prioritylevelconfiguration.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPriorityLevelConfigurationFunc(func(p *flowcontrolv1.PriorityLevelConfiguration) *metric.Family {
+				ms := []*metric.Metric{}
+				for _, c := range p.Status.Conditions {
+					for _, m := range addConditionMetrics(v1.ConditionStatus(c.Status)) {
+						m.LabelKeys = []string{"condition", "status"}
+						m.LabelValues = append([]string{string(c.Type)}, m.LabelValues...)
+						ms = append(ms, m)
+					}
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+	}
+}
+
+func wrapPriorityLevelConfigurationFunc(f func(*flowcontrolv1.PriorityLevelConfiguration) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		priorityLevelConfiguration := obj.(*flowcontrolv1.PriorityLevelConfiguration)
+
+		metricFamily := f(priorityLevelConfiguration)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descPriorityLevelConfigurationLabelsDefaultLabels, []string{priorityLevelConfiguration.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
+
+func createPriorityLevelConfigurationListWatch(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.FlowcontrolV1().PriorityLevelConfigurations().List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.FlowcontrolV1().PriorityLevelConfigurations().Watch(context.TODO(), opts)
+		},
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestPriorityLevelConfigurationStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &flowcontrolv1.PriorityLevelConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "workload-low",
+				},
+				Spec: flowcontrolv1.PriorityLevelConfigurationSpec{
+					Type: flowcontrolv1.PriorityLevelEnablementLimited,
+					Limited: &flowcontrolv1.LimitedPriorityLevelConfiguration{
+						NominalConcurrencyShares: pointer.Int32(100),
+						LendablePercent:          pointer.Int32(50),
+						LimitResponse:            flowcontrolv1.LimitResponse{Type: flowcontrolv1.LimitResponseTypeQueue},
+					},
+				},
+				Status: flowcontrolv1.PriorityLevelConfigurationStatus{
+					Conditions: []flowcontrolv1.PriorityLevelConfigurationCondition{
+						{Type: "ConcurrencyShared", Status: flowcontrolv1.ConditionTrue},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_prioritylevelconfiguration_borrowing_limit_percent The limit of the concurrency the limited prioritylevelconfiguration can borrow from other priority levels, as percentage of its nominal concurrency limit.
+				# HELP kube_prioritylevelconfiguration_info Information about prioritylevelconfiguration.
+				# HELP kube_prioritylevelconfiguration_lendable_percent The percentage of the nominal concurrency limit of the limited prioritylevelconfiguration which can be borrowed by other priority levels.
+				# HELP kube_prioritylevelconfiguration_nominal_concurrency_shares The nominal concurrency shares of the limited prioritylevelconfiguration.
+				# HELP kube_prioritylevelconfiguration_status_condition The condition of the prioritylevelconfiguration.
+				# TYPE kube_prioritylevelconfiguration_borrowing_limit_percent gauge
+				# TYPE kube_prioritylevelconfiguration_info gauge
+				# TYPE kube_prioritylevelconfiguration_lendable_percent gauge
+				# TYPE kube_prioritylevelconfiguration_nominal_concurrency_shares gauge
+				# TYPE kube_prioritylevelconfiguration_status_condition gauge
+				kube_prioritylevelconfiguration_info{limit_response="Queue",prioritylevelconfiguration="workload-low",type="Limited"} 1
+				kube_prioritylevelconfiguration_lendable_percent{prioritylevelconfiguration="workload-low"} 50
+				kube_prioritylevelconfiguration_nominal_concurrency_shares{prioritylevelconfiguration="workload-low"} 100
+				kube_prioritylevelconfiguration_status_condition{condition="ConcurrencyShared",prioritylevelconfiguration="workload-low",status="false"} 0
+				kube_prioritylevelconfiguration_status_condition{condition="ConcurrencyShared",prioritylevelconfiguration="workload-low",status="true"} 1
+				kube_prioritylevelconfiguration_status_condition{condition="ConcurrencyShared",prioritylevelconfiguration="workload-low",status="unknown"} 0
+			`,
+			MetricNames: []string{
+				"kube_prioritylevelconfiguration_borrowing_limit_percent",
+				"kube_prioritylevelconfiguration_info",
+				"kube_prioritylevelconfiguration_lendable_percent",
+				"kube_prioritylevelconfiguration_nominal_concurrency_shares",
+				"kube_prioritylevelconfiguration_status_condition",
+			},
+		},
+		{
+			Obj: &flowcontrolv1.PriorityLevelConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exempt",
+				},
+				Spec: flowcontrolv1.PriorityLevelConfigurationSpec{
+					Type: flowcontrolv1.PriorityLevelEnablementExempt,
+				},
+			},
+			Want: `
+				# HELP kube_prioritylevelconfiguration_info Information about prioritylevelconfiguration.
+				# HELP kube_prioritylevelconfiguration_nominal_concurrency_shares The nominal concurrency shares of the limited prioritylevelconfiguration.
+				# TYPE kube_prioritylevelconfiguration_info gauge
+				# TYPE kube_prioritylevelconfiguration_nominal_concurrency_shares gauge
+				kube_prioritylevelconfiguration_info{limit_response="",prioritylevelconfiguration="exempt",type="Exempt"} 1
+			`,
+			MetricNames: []string{
+				"kube_prioritylevelconfiguration_info",
+				"kube_prioritylevelconfiguration_nominal_concurrency_shares",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(priorityLevelConfigurationMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(priorityLevelConfigurationMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+
+	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descResourceClaimAnnotationsName     = "kube_resourceclaim_annotations"
+	descResourceClaimAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descResourceClaimLabelsName          = "kube_resourceclaim_labels"
+	descResourceClaimLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descResourceClaimLabelsDefaultLabels = []string{"namespace", "resourceclaim"}
+
+	// resourceClaimPodClaimNameAnnotation is set on resourceclaims generated
+	// for pods to the name of the resource claim in the pod spec.
+	resourceClaimPodClaimNameAnnotation = "resource.kubernetes.io/pod-claim-name"
+)
+
+func resourceClaimMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceclaim_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceClaimFunc(func(c *resourcev1.ResourceClaim) *metric.Family {
+				ms := []*metric.Metric{}
+				if !c.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(c.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descResourceClaimAnnotationsName,
+			descResourceClaimAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceClaimFunc(func(c *resourcev1.ResourceClaim) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", c.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descResourceClaimLabelsName,
+			descResourceClaimLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceClaimFunc(func(c *resourcev1.ResourceClaim) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", c.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceclaim_request_info",
+			"Information about the device requests of the resourceclaim.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceClaimFunc(func(c *resourcev1.ResourceClaim) *metric.Family {
+				return &metric.Family{
+					Metrics: deviceRequestMetrics(c.Spec),
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceclaim_allocated",
+			"Whether devices are allocated for the resourceclaim.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceClaimFunc(func(c *resourcev1.ResourceClaim) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: boolFloat64(c.Status.Allocation != nil),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceclaim_allocation_device_info",
+			"Information about the devices allocated for the requests of the resourceclaim.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceClaimFunc(func(c *resourcev1.ResourceClaim) *metric.Family {
+				ms := []*metric.Metric{}
+				if c.Status.Allocation != nil {
+					for _, r := range c.Status.Allocation.Devices.Results {
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"request", "driver", "pool", "device"},
+							LabelValues: []string{r.Request, r.Driver, r.Pool, r.Device},
+							Value:       1,
+						})
+					}
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceclaim_pod_claim_info",
+			"Information about the pod and its resource claim the resourceclaim was generated for.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceClaimFunc(func(c *resourcev1.ResourceClaim) *metric.Family {
+				ms := []*metric.Metric{}
+				owner := metav1.GetControllerOf(c)
+				if owner != nil && owner.APIVersion == "v1" && owner.Kind == "Pod" {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"pod", "pod_uid", "pod_claim"},
+						LabelValues: []string{owner.Name, string(owner.UID), c.Annotations[resourceClaimPodClaimNameAnnotation]},
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceclaim_reserved_for",
+			"The consumers, e.g. pods, the resourceclaim is reserved for.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceClaimFunc(func(c *resourcev1.ResourceClaim) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(c.Status.ReservedFor))
+				for _, r := range c.Status.ReservedFor {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"consumer_api_group", "consumer_resource", "consumer_name", "consumer_uid"},
+						LabelValues: []string{r.APIGroup, r.Resource, r.Name, string(r.UID)},
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+	}
+}
+
+// deviceRequestMetrics returns one metric for each device request of a
+// ResourceClaim spec. The alternatives of requests for the first available
+// devices are named like the request and the alternative, separated by a
+// slash, like in the allocation results.
+func deviceRequestMetrics(spec resourcev1.ResourceClaimSpec) []*metric.Metric {
+	ms := []*metric.Metric{}
+	add := func(name string, deviceClassName string, allocationMode resourcev1.DeviceAllocationMode) {
+		if allocationMode == "" {
+			allocationMode = resourcev1.DeviceAllocationModeExactCount
+		}
+		ms = append(ms, &metric.Metric{
+			LabelKeys:   []string{"request", "device_class", "allocation_mode"},
+			LabelValues: []string{name, deviceClassName, string(allocationMode)},
+			Value:       1,
+		})
+	}
+	for _, r := range spec.Devices.Requests {
+		if r.Exactly != nil {
+			add(r.Name, r.Exactly.DeviceClassName, r.Exactly.AllocationMode)
+		}
+		for _, s := range r.FirstAvailable {
+			add(r.Name+"/"+s.Name, s.DeviceClassName, s.AllocationMode)
+		}
+	}
+	return ms
+}
+
+func wrapResourceClaimFunc(f func(*resourcev1.ResourceClaim) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		c := obj.(*resourcev1.ResourceClaim)
+
+		metricFamily := f(c)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descResourceClaimLabelsDefaultLabels, []string{c.Namespace, c.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
+
+func createResourceClaimListWatch(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = fieldSelector
+			return kubeClient.ResourceV1().ResourceClaims(ns).List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = fieldSelector
+			return kubeClient.ResourceV1().ResourceClaims(ns).Watch(context.TODO(), opts)
+		},
+	}
+}
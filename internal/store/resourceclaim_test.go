@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestResourceClaimStore(t *testing.T) {
+	startTime := 1501569018
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &resourcev1.ResourceClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns1",
+					Name:      "gpu-claim",
+				},
+				Spec: resourcev1.ResourceClaimSpec{
+					Devices: resourcev1.DeviceClaim{
+						Requests: []resourcev1.DeviceRequest{
+							{
+								Name:    "gpu",
+								Exactly: &resourcev1.ExactDeviceRequest{DeviceClassName: "gpu.example.com"},
+							},
+							{
+								Name: "nic",
+								FirstAvailable: []resourcev1.DeviceSubRequest{
+									{Name: "fast", DeviceClassName: "nic.example.com", AllocationMode: resourcev1.DeviceAllocationModeAll},
+									{Name: "slow", DeviceClassName: "nic.example.com", Count: 2},
+								},
+							},
+						},
+					},
+				},
+				Status: resourcev1.ResourceClaimStatus{
+					Allocation: &resourcev1.AllocationResult{
+						Devices: resourcev1.DeviceAllocationResult{
+							Results: []resourcev1.DeviceRequestAllocationResult{
+								{Request: "gpu", Driver: "gpu.example.com", Pool: "node1", Device: "gpu-0"},
+							},
+						},
+					},
+					ReservedFor: []resourcev1.ResourceClaimConsumerReference{
+						{Resource: "pods", Name: "pod1", UID: "uid1"},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_resourceclaim_allocated Whether devices are allocated for the resourceclaim.
+				# HELP kube_resourceclaim_allocation_device_info Information about the devices allocated for the requests of the resourceclaim.
+				# HELP kube_resourceclaim_request_info Information about the device requests of the resourceclaim.
+				# HELP kube_resourceclaim_reserved_for The consumers, e.g. pods, the resourceclaim is reserved for.
+				# TYPE kube_resourceclaim_allocated gauge
+				# TYPE kube_resourceclaim_allocation_device_info gauge
+				# TYPE kube_resourceclaim_request_info gauge
+				# TYPE kube_resourceclaim_reserved_for gauge
+				kube_resourceclaim_allocated{namespace="ns1",resourceclaim="gpu-claim"} 1
+				kube_resourceclaim_allocation_device_info{device="gpu-0",driver="gpu.example.com",namespace="ns1",pool="node1",request="gpu",resourceclaim="gpu-claim"} 1
+				kube_resourceclaim_request_info{allocation_mode="ExactCount",device_class="gpu.example.com",namespace="ns1",request="gpu",resourceclaim="gpu-claim"} 1
+				kube_resourceclaim_request_info{allocation_mode="All",device_class="nic.example.com",namespace="ns1",request="nic/fast",resourceclaim="gpu-claim"} 1
+				kube_resourceclaim_request_info{allocation_mode="ExactCount",device_class="nic.example.com",namespace="ns1",request="nic/slow",resourceclaim="gpu-claim"} 1
+				kube_resourceclaim_reserved_for{consumer_api_group="",consumer_name="pod1",consumer_resource="pods",consumer_uid="uid1",namespace="ns1",resourceclaim="gpu-claim"} 1
+			`,
+			MetricNames: []string{
+				"kube_resourceclaim_allocated",
+				"kube_resourceclaim_allocation_device_info",
+				"kube_resourceclaim_request_info",
+				"kube_resourceclaim_reserved_for",
+			},
+		},
+		{
+			Obj: &resourcev1.ResourceClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "ns1",
+					Name:              "pending",
+					CreationTimestamp: metav1.Time{Time: time.Unix(int64(startTime), 0)},
+				},
+				Spec: resourcev1.ResourceClaimSpec{
+					Devices: resourcev1.DeviceClaim{
+						Requests: []resourcev1.DeviceRequest{
+							{
+								Name:    "gpu",
+								Exactly: &resourcev1.ExactDeviceRequest{DeviceClassName: "gpu.example.com"},
+							},
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_resourceclaim_allocated Whether devices are allocated for the resourceclaim.
+				# HELP kube_resourceclaim_created Unix creation timestamp
+				# TYPE kube_resourceclaim_allocated gauge
+				# TYPE kube_resourceclaim_created gauge
+				kube_resourceclaim_allocated{namespace="ns1",resourceclaim="pending"} 0
+				kube_resourceclaim_created{namespace="ns1",resourceclaim="pending"} 1.501569018e+09
+			`,
+			MetricNames: []string{
+				"kube_resourceclaim_allocated",
+				"kube_resourceclaim_created",
+			},
+		},
+		{
+			Obj: &resourcev1.ResourceClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns1",
+					Name:      "pod1-gpu-x7k2p",
+					Annotations: map[string]string{
+						"resource.kubernetes.io/pod-claim-name": "gpu",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: "v1", Kind: "Pod", Name: "pod1", UID: "uid1", Controller: pointer.Bool(true)},
+					},
+				},
+				Spec: resourcev1.ResourceClaimSpec{
+					Devices: resourcev1.DeviceClaim{
+						Requests: []resourcev1.DeviceRequest{
+							{
+								Name:    "gpu",
+								Exactly: &resourcev1.ExactDeviceRequest{DeviceClassName: "gpu.example.com"},
+							},
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_resourceclaim_pod_claim_info Information about the pod and its resource claim the resourceclaim was generated for.
+				# TYPE kube_resourceclaim_pod_claim_info gauge
+				kube_resourceclaim_pod_claim_info{namespace="ns1",pod="pod1",pod_claim="gpu",pod_uid="uid1",resourceclaim="pod1-gpu-x7k2p"} 1
+			`,
+			MetricNames: []string{
+				"kube_resourceclaim_pod_claim_info",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(resourceClaimMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(resourceClaimMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
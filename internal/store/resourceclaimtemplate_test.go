@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestResourceClaimTemplateStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &resourcev1.ResourceClaimTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns1",
+					Name:      "gpu-template",
+				},
+				Spec: resourcev1.ResourceClaimTemplateSpec{
+					Spec: resourcev1.ResourceClaimSpec{
+						Devices: resourcev1.DeviceClaim{
+							Requests: []resourcev1.DeviceRequest{
+								{
+									Name:    "gpu",
+									Exactly: &resourcev1.ExactDeviceRequest{DeviceClassName: "gpu.example.com"},
+								},
+							},
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_resourceclaimtemplate_request_info Information about the device requests of the resourceclaims created from the resourceclaimtemplate.
+				# TYPE kube_resourceclaimtemplate_request_info gauge
+				kube_resourceclaimtemplate_request_info{allocation_mode="ExactCount",device_class="gpu.example.com",namespace="ns1",request="gpu",resourceclaimtemplate="gpu-template"} 1
+			`,
+			MetricNames: []string{
+				"kube_resourceclaimtemplate_request_info",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(resourceClaimTemplateMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(resourceClaimTemplateMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package store
 
 import (
 	"context"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -82,6 +83,83 @@ var (
 					m.LabelKeys = []string{"resource", "type"}
 				}
 
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourcequota_scope",
+			"The scopes of the objects tracked by the resource quota.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceQuotaFunc(func(r *v1.ResourceQuota) *metric.Family {
+				ms := make([]*metric.Metric, len(r.Spec.Scopes))
+				for i, scope := range r.Spec.Scopes {
+					ms[i] = &metric.Metric{
+						LabelKeys:   []string{"scope"},
+						LabelValues: []string{string(scope)},
+						Value:       1,
+					}
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourcequota_scope_selector",
+			"The match expressions of the scope selector of the resource quota.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceQuotaFunc(func(r *v1.ResourceQuota) *metric.Family {
+				ms := []*metric.Metric{}
+				if r.Spec.ScopeSelector == nil {
+					return &metric.Family{
+						Metrics: ms,
+					}
+				}
+				for _, e := range r.Spec.ScopeSelector.MatchExpressions {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"scope_name", "operator", "values"},
+						LabelValues: []string{string(e.ScopeName), string(e.Operator), strings.Join(e.Values, ",")},
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourcequota_priority_class",
+			"Information about resource quota of the priority classes its scope selector is restricted to.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceQuotaFunc(func(r *v1.ResourceQuota) *metric.Family {
+				ms := []*metric.Metric{}
+				for _, priorityClass := range resourceQuotaPriorityClasses(r) {
+					for res, qty := range r.Status.Hard {
+						ms = append(ms, &metric.Metric{
+							LabelValues: []string{priorityClass, string(res), "hard"},
+							Value:       float64(qty.MilliValue()) / 1000,
+						})
+					}
+					for res, qty := range r.Status.Used {
+						ms = append(ms, &metric.Metric{
+							LabelValues: []string{priorityClass, string(res), "used"},
+							Value:       float64(qty.MilliValue()) / 1000,
+						})
+					}
+				}
+
+				for _, m := range ms {
+					m.LabelKeys = []string{"priority_class", "resource", "type"}
+				}
+
 				return &metric.Family{
 					Metrics: ms,
 				}
@@ -90,6 +168,21 @@ var (
 	}
 )
 
+// resourceQuotaPriorityClasses returns the priority classes the scope
+// selector of the resource quota is restricted to by the In operator.
+func resourceQuotaPriorityClasses(r *v1.ResourceQuota) []string {
+	if r.Spec.ScopeSelector == nil {
+		return nil
+	}
+	var priorityClasses []string
+	for _, e := range r.Spec.ScopeSelector.MatchExpressions {
+		if e.ScopeName == v1.ResourceQuotaScopePriorityClass && e.Operator == v1.ScopeSelectorOpIn {
+			priorityClasses = append(priorityClasses, e.Values...)
+		}
+	}
+	return priorityClasses
+}
+
 func wrapResourceQuotaFunc(f func(*v1.ResourceQuota) *metric.Family) func(interface{}) *metric.Family {
 	return func(obj interface{}) *metric.Family {
 		resourceQuota := obj.(*v1.ResourceQuota)
@@ -35,6 +35,12 @@ func TestResourceQuotaStore(t *testing.T) {
 	# TYPE kube_resourcequota gauge
 	# HELP kube_resourcequota_created [STABLE] Unix creation timestamp
 	# TYPE kube_resourcequota_created gauge
+	# HELP kube_resourcequota_priority_class Information about resource quota of the priority classes its scope selector is restricted to.
+	# TYPE kube_resourcequota_priority_class gauge
+	# HELP kube_resourcequota_scope The scopes of the objects tracked by the resource quota.
+	# TYPE kube_resourcequota_scope gauge
+	# HELP kube_resourcequota_scope_selector The match expressions of the scope selector of the resource quota.
+	# TYPE kube_resourcequota_scope_selector gauge
 	`
 	cases := []generateMetricsTestCase{
 		// Verify populating base metric and that metric for unset fields are skipped.
@@ -132,6 +138,50 @@ func TestResourceQuotaStore(t *testing.T) {
 			kube_resourcequota{namespace="testNS",resource="storage",resourcequota="quotaTest",type="used"} 9e+09
 			`,
 		},
+		// Verify scope and priority class metrics.
+		{
+			Obj: &v1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "quotaTest",
+					Namespace: "testNS",
+				},
+				Spec: v1.ResourceQuotaSpec{
+					Scopes: []v1.ResourceQuotaScope{v1.ResourceQuotaScopeNotTerminating},
+					ScopeSelector: &v1.ScopeSelector{
+						MatchExpressions: []v1.ScopedResourceSelectorRequirement{
+							{
+								ScopeName: v1.ResourceQuotaScopePriorityClass,
+								Operator:  v1.ScopeSelectorOpIn,
+								Values:    []string{"high", "medium"},
+							},
+							{
+								ScopeName: v1.ResourceQuotaScopeBestEffort,
+								Operator:  v1.ScopeSelectorOpDoesNotExist,
+							},
+						},
+					},
+				},
+				Status: v1.ResourceQuotaStatus{
+					Hard: v1.ResourceList{
+						v1.ResourcePods: resource.MustParse("10"),
+					},
+					Used: v1.ResourceList{
+						v1.ResourcePods: resource.MustParse("3"),
+					},
+				},
+			},
+			Want: metadata + `
+			kube_resourcequota{namespace="testNS",resource="pods",resourcequota="quotaTest",type="hard"} 10
+			kube_resourcequota{namespace="testNS",resource="pods",resourcequota="quotaTest",type="used"} 3
+			kube_resourcequota_priority_class{namespace="testNS",priority_class="high",resource="pods",resourcequota="quotaTest",type="hard"} 10
+			kube_resourcequota_priority_class{namespace="testNS",priority_class="high",resource="pods",resourcequota="quotaTest",type="used"} 3
+			kube_resourcequota_priority_class{namespace="testNS",priority_class="medium",resource="pods",resourcequota="quotaTest",type="hard"} 10
+			kube_resourcequota_priority_class{namespace="testNS",priority_class="medium",resource="pods",resourcequota="quotaTest",type="used"} 3
+			kube_resourcequota_scope{namespace="testNS",resourcequota="quotaTest",scope="NotTerminating"} 1
+			kube_resourcequota_scope_selector{namespace="testNS",operator="In",resourcequota="quotaTest",scope_name="PriorityClass",values="high,medium"} 1
+			kube_resourcequota_scope_selector{namespace="testNS",operator="DoesNotExist",resourcequota="quotaTest",scope_name="BestEffort",values=""} 1
+			`,
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(resourceQuotaMetricFamilies)
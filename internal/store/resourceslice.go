@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+var (
+	descResourceSliceAnnotationsName     = "kube_resourceslice_annotations"
+	descResourceSliceAnnotationsHelp     = "Kubernetes annotations converted to Prometheus labels."
+	descResourceSliceLabelsName          = "kube_resourceslice_labels"
+	descResourceSliceLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
+	descResourceSliceLabelsDefaultLabels = []string{"resourceslice"}
+)
+
+func resourceSliceMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceslice_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceSliceFunc(func(s *resourcev1.ResourceSlice) *metric.Family {
+				ms := []*metric.Metric{}
+				if !s.CreationTimestamp.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(s.CreationTimestamp.Unix()),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descResourceSliceAnnotationsName,
+			descResourceSliceAnnotationsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceSliceFunc(func(s *resourcev1.ResourceSlice) *metric.Family {
+				annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", s.Annotations, allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			descResourceSliceLabelsName,
+			descResourceSliceLabelsHelp,
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceSliceFunc(func(s *resourcev1.ResourceSlice) *metric.Family {
+				labelKeys, labelValues := createPrometheusLabelKeysValues("label", s.Labels, allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceslice_info",
+			"Information about resourceslice.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceSliceFunc(func(s *resourcev1.ResourceSlice) *metric.Family {
+				allNodes := false
+				if s.Spec.AllNodes != nil {
+					allNodes = *s.Spec.AllNodes
+				}
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"driver", "pool", "node", "all_nodes"},
+							LabelValues: []string{s.Spec.Driver, s.Spec.Pool.Name, stringValue(s.Spec.NodeName), strconv.FormatBool(allNodes)},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceslice_devices",
+			"Number of devices of the resourceslice.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceSliceFunc(func(s *resourcev1.ResourceSlice) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"driver", "node"},
+							LabelValues: []string{s.Spec.Driver, stringValue(s.Spec.NodeName)},
+							Value:       float64(len(s.Spec.Devices)),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourceslice_device_capacity",
+			"The capacity of the devices of the resourceslice.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceSliceFunc(func(s *resourcev1.ResourceSlice) *metric.Family {
+				ms := []*metric.Metric{}
+				for _, d := range s.Spec.Devices {
+					names := make([]string, 0, len(d.Capacity))
+					for name := range d.Capacity {
+						names = append(names, string(name))
+					}
+					sort.Strings(names)
+					for _, name := range names {
+						value := d.Capacity[resourcev1.QualifiedName(name)].Value
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"driver", "node", "device", "capacity"},
+							LabelValues: []string{s.Spec.Driver, stringValue(s.Spec.NodeName), d.Name, name},
+							Value:       float64(value.MilliValue()) / 1000,
+						})
+					}
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+	}
+}
+
+func wrapResourceSliceFunc(f func(*resourcev1.ResourceSlice) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		s := obj.(*resourcev1.ResourceSlice)
+
+		metricFamily := f(s)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys, m.LabelValues = mergeKeyValues(descResourceSliceLabelsDefaultLabels, []string{s.Name}, m.LabelKeys, m.LabelValues)
+		}
+
+		return metricFamily
+	}
+}
+
+func createResourceSliceListWatch(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.ResourceV1().ResourceSlices().List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.ResourceV1().ResourceSlices().Watch(context.TODO(), opts)
+		},
+	}
+}
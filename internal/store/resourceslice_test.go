@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	resourcev1 "k8s.io/api/resource/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestResourceSliceStore(t *testing.T) {
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &resourcev1.ResourceSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node1-gpu.example.com-abcde",
+				},
+				Spec: resourcev1.ResourceSliceSpec{
+					Driver:   "gpu.example.com",
+					Pool:     resourcev1.ResourcePool{Name: "node1", Generation: 1, ResourceSliceCount: 1},
+					NodeName: pointer.String("node1"),
+					Devices: []resourcev1.Device{
+						{
+							Name: "gpu-0",
+							Capacity: map[resourcev1.QualifiedName]resourcev1.DeviceCapacity{
+								"memory": {Value: resource.MustParse("40Gi")},
+								"cores":  {Value: resource.MustParse("108")},
+							},
+						},
+						{
+							Name: "gpu-1",
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_resourceslice_device_capacity The capacity of the devices of the resourceslice.
+				# HELP kube_resourceslice_devices Number of devices of the resourceslice.
+				# HELP kube_resourceslice_info Information about resourceslice.
+				# TYPE kube_resourceslice_device_capacity gauge
+				# TYPE kube_resourceslice_devices gauge
+				# TYPE kube_resourceslice_info gauge
+				kube_resourceslice_device_capacity{capacity="cores",device="gpu-0",driver="gpu.example.com",node="node1",resourceslice="node1-gpu.example.com-abcde"} 108
+				kube_resourceslice_device_capacity{capacity="memory",device="gpu-0",driver="gpu.example.com",node="node1",resourceslice="node1-gpu.example.com-abcde"} 4.294967296e+10
+				kube_resourceslice_devices{driver="gpu.example.com",node="node1",resourceslice="node1-gpu.example.com-abcde"} 2
+				kube_resourceslice_info{all_nodes="false",driver="gpu.example.com",node="node1",pool="node1",resourceslice="node1-gpu.example.com-abcde"} 1
+			`,
+			MetricNames: []string{
+				"kube_resourceslice_device_capacity",
+				"kube_resourceslice_devices",
+				"kube_resourceslice_info",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(resourceSliceMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(resourceSliceMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
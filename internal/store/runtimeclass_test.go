@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestRuntimeClassStore(t *testing.T) {
+	startTime := 1501569018
+	metav1StartTime := metav1.Unix(int64(startTime), 0)
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &nodev1.RuntimeClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "gvisor",
+					CreationTimestamp: metav1StartTime,
+				},
+				Handler: "runsc",
+				Overhead: &nodev1.Overhead{
+					PodFixed: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("250m"),
+						v1.ResourceMemory: resource.MustParse("120Mi"),
+					},
+				},
+				Scheduling: &nodev1.Scheduling{
+					NodeSelector: map[string]string{"runtime": "gvisor"},
+					Tolerations: []v1.Toleration{
+						{Key: "runtime", Value: "gvisor", Effect: v1.TaintEffectNoSchedule},
+						{Key: "sandboxed", Operator: v1.TolerationOpExists},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_runtimeclass_created Unix creation timestamp
+				# HELP kube_runtimeclass_info Information about runtimeclass.
+				# HELP kube_runtimeclass_overhead_cpu_cores The pod overhead in regards to cpu cores associated with running a pod of the runtimeclass.
+				# HELP kube_runtimeclass_overhead_memory_bytes The pod overhead in regards to memory associated with running a pod of the runtimeclass.
+				# HELP kube_runtimeclass_scheduling_node_selectors Number of node labels pods of the runtimeclass are restricted to nodes by.
+				# HELP kube_runtimeclass_scheduling_tolerations Number of tolerations added to pods of the runtimeclass.
+				# TYPE kube_runtimeclass_created gauge
+				# TYPE kube_runtimeclass_info gauge
+				# TYPE kube_runtimeclass_overhead_cpu_cores gauge
+				# TYPE kube_runtimeclass_overhead_memory_bytes gauge
+				# TYPE kube_runtimeclass_scheduling_node_selectors gauge
+				# TYPE kube_runtimeclass_scheduling_tolerations gauge
+				kube_runtimeclass_created{runtimeclass="gvisor"} 1.501569018e+09
+				kube_runtimeclass_info{handler="runsc",runtimeclass="gvisor"} 1
+				kube_runtimeclass_overhead_cpu_cores{runtimeclass="gvisor"} 0.25
+				kube_runtimeclass_overhead_memory_bytes{runtimeclass="gvisor"} 1.2582912e+08
+				kube_runtimeclass_scheduling_node_selectors{runtimeclass="gvisor"} 1
+				kube_runtimeclass_scheduling_tolerations{runtimeclass="gvisor"} 2
+			`,
+			MetricNames: []string{
+				"kube_runtimeclass_created",
+				"kube_runtimeclass_info",
+				"kube_runtimeclass_overhead_cpu_cores",
+				"kube_runtimeclass_overhead_memory_bytes",
+				"kube_runtimeclass_scheduling_node_selectors",
+				"kube_runtimeclass_scheduling_tolerations",
+			},
+		},
+		{
+			Obj: &nodev1.RuntimeClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "runc",
+				},
+				Handler: "runc",
+			},
+			Want: `
+				# HELP kube_runtimeclass_info Information about runtimeclass.
+				# HELP kube_runtimeclass_overhead_cpu_cores The pod overhead in regards to cpu cores associated with running a pod of the runtimeclass.
+				# HELP kube_runtimeclass_scheduling_tolerations Number of tolerations added to pods of the runtimeclass.
+				# TYPE kube_runtimeclass_info gauge
+				# TYPE kube_runtimeclass_overhead_cpu_cores gauge
+				# TYPE kube_runtimeclass_scheduling_tolerations gauge
+				kube_runtimeclass_info{handler="runc",runtimeclass="runc"} 1
+				kube_runtimeclass_scheduling_tolerations{runtimeclass="runc"} 0
+			`,
+			MetricNames: []string{
+				"kube_runtimeclass_info",
+				"kube_runtimeclass_overhead_cpu_cores",
+				"kube_runtimeclass_scheduling_tolerations",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(runtimeClassMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(runtimeClassMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
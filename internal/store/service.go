@@ -18,6 +18,7 @@ package store
 
 import (
 	"context"
+	"strconv"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -177,6 +178,72 @@ func serviceMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gen
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_service_status_load_balancer_port",
+			"Status of the service ports of a service load balancer ingress. One series for each port.",
+			metric.Gauge,
+			"",
+			wrapSvcFunc(func(s *v1.Service) *metric.Family {
+				ms := []*metric.Metric{}
+
+				for _, ingress := range s.Status.LoadBalancer.Ingress {
+					for _, port := range ingress.Ports {
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"ip", "hostname", "port", "protocol", "error"},
+							LabelValues: []string{ingress.IP, ingress.Hostname, strconv.FormatInt(int64(port.Port), 10), string(port.Protocol), stringValue(port.Error)},
+							Value:       boolFloat64(port.Error == nil || *port.Error == ""),
+						})
+					}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_service_status_load_balancer_ingress_ip_mode",
+			"How the traffic of a service load balancer ingress is delivered to the nodes. One series for each ingress which specifies it.",
+			metric.Gauge,
+			"",
+			wrapSvcFunc(func(s *v1.Service) *metric.Family {
+				ms := []*metric.Metric{}
+
+				for _, ingress := range s.Status.LoadBalancer.Ingress {
+					if ingress.IPMode == nil {
+						continue
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"ip", "hostname", "ip_mode"},
+						LabelValues: []string{ingress.IP, ingress.Hostname, string(*ingress.IPMode)},
+						Value:       1,
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_service_spec_traffic_distribution",
+			"The preference of the service for distributing traffic to its endpoints.",
+			metric.Gauge,
+			"",
+			wrapSvcFunc(func(s *v1.Service) *metric.Family {
+				if s.Spec.TrafficDistribution == nil {
+					return &metric.Family{}
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{{
+						LabelKeys:   []string{"traffic_distribution"},
+						LabelValues: []string{*s.Spec.TrafficDistribution},
+						Value:       1,
+					}},
+				}
+			}),
+		),
 	}
 }
 
@@ -44,7 +44,16 @@ func TestServiceStore(t *testing.T) {
 		# TYPE kube_service_spec_external_ip gauge
 		# HELP kube_service_status_load_balancer_ingress [STABLE] Service load balancer ingress status
 		# TYPE kube_service_status_load_balancer_ingress gauge
+		# HELP kube_service_status_load_balancer_port Status of the service ports of a service load balancer ingress. One series for each port.
+		# TYPE kube_service_status_load_balancer_port gauge
+		# HELP kube_service_status_load_balancer_ingress_ip_mode How the traffic of a service load balancer ingress is delivered to the nodes. One series for each ingress which specifies it.
+		# TYPE kube_service_status_load_balancer_ingress_ip_mode gauge
+		# HELP kube_service_spec_traffic_distribution The preference of the service for distributing traffic to its endpoints.
+		# TYPE kube_service_spec_traffic_distribution gauge
 	`
+	portError := "MixedProtocolNotSupported"
+	ipModeProxy := v1.LoadBalancerIPModeProxy
+	preferClose := v1.ServiceTrafficDistributionPreferClose
 	cases := []generateMetricsTestCase{
 		{
 			Obj: &v1.Service{
@@ -173,7 +182,8 @@ func TestServiceStore(t *testing.T) {
 					},
 				},
 				Spec: v1.ServiceSpec{
-					Type: v1.ServiceTypeLoadBalancer,
+					Type:                v1.ServiceTypeLoadBalancer,
+					TrafficDistribution: &preferClose,
 				},
 				Status: v1.ServiceStatus{
 					LoadBalancer: v1.LoadBalancerStatus{
@@ -181,6 +191,14 @@ func TestServiceStore(t *testing.T) {
 							{
 								IP:       "1.2.3.8",
 								Hostname: "www.example.com",
+								IPMode:   &ipModeProxy,
+								Ports: []v1.PortStatus{
+									{Port: 80, Protocol: v1.ProtocolTCP},
+									{Port: 53, Protocol: v1.ProtocolUDP, Error: &portError},
+								},
+							},
+							{
+								IP: "1.2.3.9",
 							},
 						},
 					},
@@ -192,7 +210,12 @@ func TestServiceStore(t *testing.T) {
 				kube_service_info{cluster_ip="",external_name="",load_balancer_ip="",namespace="default",service="test-service5",uid="uid5"} 1
 				kube_service_labels{namespace="default",service="test-service5",uid="uid5"} 1
 				kube_service_spec_type{namespace="default",service="test-service5",type="LoadBalancer",uid="uid5"} 1
+				kube_service_spec_traffic_distribution{namespace="default",service="test-service5",traffic_distribution="PreferClose",uid="uid5"} 1
 				kube_service_status_load_balancer_ingress{hostname="www.example.com",ip="1.2.3.8",namespace="default",service="test-service5",uid="uid5"} 1
+				kube_service_status_load_balancer_ingress{hostname="",ip="1.2.3.9",namespace="default",service="test-service5",uid="uid5"} 1
+				kube_service_status_load_balancer_ingress_ip_mode{hostname="www.example.com",ip="1.2.3.8",ip_mode="Proxy",namespace="default",service="test-service5",uid="uid5"} 1
+				kube_service_status_load_balancer_port{error="",hostname="www.example.com",ip="1.2.3.8",namespace="default",port="80",protocol="TCP",service="test-service5",uid="uid5"} 1
+				kube_service_status_load_balancer_port{error="MixedProtocolNotSupported",hostname="www.example.com",ip="1.2.3.8",namespace="default",port="53",protocol="UDP",service="test-service5",uid="uid5"} 0
 			`,
 		},
 		{
@@ -42,6 +42,9 @@ func serviceAccountMetricFamilies(allowAnnotationsList, allowLabelsList []string
 		createServiceAccountDeletedFamilyGenerator(),
 		createServiceAccountSecretFamilyGenerator(),
 		createServiceAccountImagePullSecretFamilyGenerator(),
+		createServiceAccountSecretsFamilyGenerator(),
+		createServiceAccountImagePullSecretsFamilyGenerator(),
+		createServiceAccountAutomountTokenFamilyGenerator(),
 		createServiceAccountAnnotationsGenerator(allowAnnotationsList),
 		createServiceAccountLabelsGenerator(allowLabelsList),
 	}
@@ -169,6 +172,67 @@ func createServiceAccountImagePullSecretFamilyGenerator() generator.FamilyGenera
 	)
 }
 
+func createServiceAccountSecretsFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_serviceaccount_secrets",
+		"Number of secrets referenced by a service account",
+		metric.Gauge,
+		"",
+		wrapServiceAccountFunc(func(sa *v1.ServiceAccount) *metric.Family {
+			return &metric.Family{
+				Metrics: []*metric.Metric{{
+					Value: float64(len(sa.Secrets)),
+				}},
+			}
+		}),
+	)
+}
+
+func createServiceAccountImagePullSecretsFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_serviceaccount_image_pull_secrets",
+		"Number of secrets referenced by a service account for the purpose of pulling images",
+		metric.Gauge,
+		"",
+		wrapServiceAccountFunc(func(sa *v1.ServiceAccount) *metric.Family {
+			return &metric.Family{
+				Metrics: []*metric.Metric{{
+					Value: float64(len(sa.ImagePullSecrets)),
+				}},
+			}
+		}),
+	)
+}
+
+func createServiceAccountAutomountTokenFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGenerator(
+		"kube_serviceaccount_automount_token",
+		"Whether a service account token is mounted into pods of a service account by default",
+		metric.Gauge,
+		"",
+		wrapServiceAccountFunc(func(sa *v1.ServiceAccount) *metric.Family {
+			state := "unset"
+			if sa.AutomountServiceAccountToken != nil {
+				state = strconv.FormatBool(*sa.AutomountServiceAccountToken)
+			}
+
+			states := []string{"true", "false", "unset"}
+			ms := make([]*metric.Metric, len(states))
+			for i, s := range states {
+				ms[i] = &metric.Metric{
+					LabelKeys:   []string{"state"},
+					LabelValues: []string{s},
+					Value:       boolFloat64(s == state),
+				}
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func createServiceAccountAnnotationsGenerator(allowAnnotations []string) generator.FamilyGenerator {
 	return *generator.NewFamilyGenerator(
 		"kube_serviceaccount_annotations",
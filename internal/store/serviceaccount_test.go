@@ -59,16 +59,22 @@ func TestServiceAccountStore(t *testing.T) {
 			# HELP kube_serviceaccount_deleted Unix deletion timestamp
 			# HELP kube_serviceaccount_secret Secret being referenced by a service account
 			# HELP kube_serviceaccount_image_pull_secret Secret being referenced by a service account for the purpose of pulling images
+			# HELP kube_serviceaccount_secrets Number of secrets referenced by a service account
+			# HELP kube_serviceaccount_image_pull_secrets Number of secrets referenced by a service account for the purpose of pulling images
 			# TYPE kube_serviceaccount_info gauge
 			# TYPE kube_serviceaccount_created gauge
 			# TYPE kube_serviceaccount_deleted gauge
 			# TYPE kube_serviceaccount_secret gauge
-            # TYPE kube_serviceaccount_image_pull_secret gauge
+			# TYPE kube_serviceaccount_image_pull_secret gauge
+			# TYPE kube_serviceaccount_secrets gauge
+			# TYPE kube_serviceaccount_image_pull_secrets gauge
 			kube_serviceaccount_info{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",automount_token="true"} 1
 			kube_serviceaccount_created{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID"} 1.5e+09
 			kube_serviceaccount_deleted{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID"} 3e+09
 			kube_serviceaccount_secret{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",name="secretName"} 1
-			kube_serviceaccount_image_pull_secret{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",name="imagePullSecretName"} 1`,
+			kube_serviceaccount_image_pull_secret{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",name="imagePullSecretName"} 1
+			kube_serviceaccount_secrets{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID"} 1
+			kube_serviceaccount_image_pull_secrets{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID"} 1`,
 			MetricNames: []string{
 				"kube_serviceaccount_info",
 				"kube_serviceaccount_created",
@@ -77,6 +83,62 @@ func TestServiceAccountStore(t *testing.T) {
 				"kube_serviceaccount_image_pull_secret",
 			},
 		},
+		{
+			Obj: &v1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "serviceAccountName",
+					Namespace: "serviceAccountNS",
+					UID:       "serviceAccountUID",
+				},
+				AutomountServiceAccountToken: pointer.Bool(false),
+				Secrets: []v1.ObjectReference{
+					{Name: "secretName"},
+					{Name: "otherSecretName"},
+				},
+				ImagePullSecrets: []v1.LocalObjectReference{
+					{Name: "imagePullSecretName"},
+				},
+			},
+			Want: `
+			# HELP kube_serviceaccount_automount_token Whether a service account token is mounted into pods of a service account by default
+			# HELP kube_serviceaccount_image_pull_secrets Number of secrets referenced by a service account for the purpose of pulling images
+			# HELP kube_serviceaccount_secrets Number of secrets referenced by a service account
+			# TYPE kube_serviceaccount_automount_token gauge
+			# TYPE kube_serviceaccount_image_pull_secrets gauge
+			# TYPE kube_serviceaccount_secrets gauge
+			kube_serviceaccount_automount_token{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",state="false"} 1
+			kube_serviceaccount_automount_token{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",state="true"} 0
+			kube_serviceaccount_automount_token{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",state="unset"} 0
+			kube_serviceaccount_image_pull_secrets{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID"} 1
+			kube_serviceaccount_secrets{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID"} 2`,
+			MetricNames: []string{
+				"kube_serviceaccount_secrets",
+				"kube_serviceaccount_image_pull_secrets",
+				"kube_serviceaccount_automount_token",
+			},
+		},
+		{
+			Obj: &v1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "serviceAccountName",
+					Namespace: "serviceAccountNS",
+					UID:       "serviceAccountUID",
+				},
+			},
+			Want: `
+			# HELP kube_serviceaccount_automount_token Whether a service account token is mounted into pods of a service account by default
+			# HELP kube_serviceaccount_secrets Number of secrets referenced by a service account
+			# TYPE kube_serviceaccount_automount_token gauge
+			# TYPE kube_serviceaccount_secrets gauge
+			kube_serviceaccount_automount_token{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",state="false"} 0
+			kube_serviceaccount_automount_token{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",state="true"} 0
+			kube_serviceaccount_automount_token{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID",state="unset"} 1
+			kube_serviceaccount_secrets{namespace="serviceAccountNS",serviceaccount="serviceAccountName",uid="serviceAccountUID"} 0`,
+			MetricNames: []string{
+				"kube_serviceaccount_secrets",
+				"kube_serviceaccount_automount_token",
+			},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(serviceAccountMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
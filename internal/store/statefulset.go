@@ -27,6 +27,7 @@ import (
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/watch"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -219,6 +220,85 @@ func statefulSetMetricFamilies(allowAnnotationsList, allowLabelsList []string) [
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_statefulset_ordinals_start",
+			"Number of the first replica ordinal of the StatefulSet.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapStatefulSetFunc(func(s *v1.StatefulSet) *metric.Family {
+				start := int32(0)
+				if s.Spec.Ordinals != nil {
+					start = s.Spec.Ordinals.Start
+				}
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(start),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_statefulset_spec_update_strategy",
+			"The update strategy of the StatefulSet.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapStatefulSetFunc(func(s *v1.StatefulSet) *metric.Family {
+				strategies := []v1.StatefulSetUpdateStrategyType{v1.RollingUpdateStatefulSetStrategyType, v1.OnDeleteStatefulSetStrategyType}
+				ms := make([]*metric.Metric, len(strategies))
+				for i, strategy := range strategies {
+					ms[i] = &metric.Metric{
+						LabelKeys:   []string{"strategy"},
+						LabelValues: []string{string(strategy)},
+						Value:       boolFloat64(s.Spec.UpdateStrategy.Type == strategy),
+					}
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_statefulset_spec_strategy_rollingupdate_max_unavailable",
+			"Maximum number of unavailable replicas during a rolling update of a StatefulSet.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapStatefulSetFunc(func(s *v1.StatefulSet) *metric.Family {
+				if s.Spec.UpdateStrategy.Type != v1.RollingUpdateStatefulSetStrategyType {
+					return &metric.Family{}
+				}
+
+				// Like the StatefulSet controller, update one replica at a
+				// time unless maxUnavailable is set.
+				maxUnavailable := 1
+				if ru := s.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.MaxUnavailable != nil {
+					replicas := 1
+					if s.Spec.Replicas != nil {
+						replicas = int(*s.Spec.Replicas)
+					}
+					var err error
+					maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(ru.MaxUnavailable, replicas, true)
+					if err != nil {
+						panic(err)
+					}
+					if maxUnavailable < 1 {
+						maxUnavailable = 1
+					}
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(maxUnavailable),
+						},
+					},
+				}
+			}),
+		),
 		*generator.NewFamilyGenerator(
 			descStatefulSetAnnotationsName,
 			descStatefulSetAnnotationsHelp,
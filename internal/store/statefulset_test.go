@@ -22,6 +22,7 @@ import (
 
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
@@ -33,6 +34,8 @@ var (
 
 	statefulSet1ObservedGeneration int64 = 1
 	statefulSet2ObservedGeneration int64 = 2
+
+	statefulSet5MaxUnavailable = intstr.FromString("30%")
 )
 
 func TestStatefulSetStore(t *testing.T) {
@@ -335,6 +338,67 @@ func TestStatefulSetStore(t *testing.T) {
 				"kube_statefulset_persistentvolumeclaim_retention_policy",
 			},
 		},
+		{
+			Obj: &v1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "statefulset5",
+					Namespace: "ns5",
+				},
+				Spec: v1.StatefulSetSpec{
+					Replicas: &statefulSet3Replicas,
+					Ordinals: &v1.StatefulSetOrdinals{
+						Start: 5,
+					},
+					UpdateStrategy: v1.StatefulSetUpdateStrategy{
+						Type: v1.RollingUpdateStatefulSetStrategyType,
+						RollingUpdate: &v1.RollingUpdateStatefulSetStrategy{
+							MaxUnavailable: &statefulSet5MaxUnavailable,
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_statefulset_ordinals_start Number of the first replica ordinal of the StatefulSet.
+				# HELP kube_statefulset_spec_strategy_rollingupdate_max_unavailable Maximum number of unavailable replicas during a rolling update of a StatefulSet.
+				# HELP kube_statefulset_spec_update_strategy The update strategy of the StatefulSet.
+				# TYPE kube_statefulset_ordinals_start gauge
+				# TYPE kube_statefulset_spec_strategy_rollingupdate_max_unavailable gauge
+				# TYPE kube_statefulset_spec_update_strategy gauge
+				kube_statefulset_ordinals_start{namespace="ns5",statefulset="statefulset5"} 5
+				kube_statefulset_spec_strategy_rollingupdate_max_unavailable{namespace="ns5",statefulset="statefulset5"} 3
+				kube_statefulset_spec_update_strategy{namespace="ns5",statefulset="statefulset5",strategy="OnDelete"} 0
+				kube_statefulset_spec_update_strategy{namespace="ns5",statefulset="statefulset5",strategy="RollingUpdate"} 1
+			`,
+			MetricNames: []string{
+				"kube_statefulset_ordinals_start",
+				"kube_statefulset_spec_strategy_rollingupdate_max_unavailable",
+				"kube_statefulset_spec_update_strategy",
+			},
+		},
+		{
+			Obj: &v1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "statefulset6",
+					Namespace: "ns6",
+				},
+				Spec: v1.StatefulSetSpec{
+					UpdateStrategy: v1.StatefulSetUpdateStrategy{
+						Type: v1.OnDeleteStatefulSetStrategyType,
+					},
+				},
+			},
+			Want: `
+				# HELP kube_statefulset_ordinals_start Number of the first replica ordinal of the StatefulSet.
+				# HELP kube_statefulset_spec_strategy_rollingupdate_max_unavailable Maximum number of unavailable replicas during a rolling update of a StatefulSet.
+				# TYPE kube_statefulset_ordinals_start gauge
+				# TYPE kube_statefulset_spec_strategy_rollingupdate_max_unavailable gauge
+				kube_statefulset_ordinals_start{namespace="ns6",statefulset="statefulset6"} 0
+			`,
+			MetricNames: []string{
+				"kube_statefulset_ordinals_start",
+				"kube_statefulset_spec_strategy_rollingupdate_max_unavailable",
+			},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(statefulSetMetricFamilies(nil, nil))
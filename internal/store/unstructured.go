@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// unstructuredResources are the resources of the built-in collectors which
+// watch unstructured objects with the dynamic client, by their kind. The
+// Gateway API and the AdminNetworkPolicy API are defined by custom resources
+// outside of k8s.io/api, and the typed client of CustomResourceDefinitions is
+// part of k8s.io/apiextensions-apiserver, which kube-state-metrics does not
+// depend on.
+var unstructuredResources = map[schema.GroupVersionKind]string{
+	apiextensionsGroupVersion.WithKind("CustomResourceDefinition"):      "customresourcedefinitions",
+	gatewayAPIGroupVersion.WithKind("Gateway"):                          "gateways",
+	gatewayAPIGroupVersion.WithKind("GatewayClass"):                     "gatewayclasses",
+	gatewayAPIGroupVersion.WithKind("HTTPRoute"):                        "httproutes",
+	networkPolicyAPIGroupVersion.WithKind("AdminNetworkPolicy"):         "adminnetworkpolicies",
+	networkPolicyAPIGroupVersion.WithKind("BaselineAdminNetworkPolicy"): "baselineadminnetworkpolicies",
+}
+
+// UnstructuredResources returns the resources of the built-in collectors which
+// watch unstructured objects with the dynamic client, by their kind, e.g. to
+// serve them by a fake dynamic client.
+func UnstructuredResources() map[schema.GroupVersionKind]schema.GroupVersionResource {
+	resources := make(map[schema.GroupVersionKind]schema.GroupVersionResource, len(unstructuredResources))
+	for gvk, resource := range unstructuredResources {
+		resources[gvk] = gvk.GroupVersion().WithResource(resource)
+	}
+	return resources
+}
+
+// isBuiltInUnstructured returns whether the given expected type is the one of
+// a built-in collector instead of a custom resource store.
+func isBuiltInUnstructured(u *unstructured.Unstructured) bool {
+	_, ok := unstructuredResources[u.GroupVersionKind()]
+	return ok
+}
+
+// unstructuredExpectedType returns the expected type of the stores of
+// unstructured objects of the given kind.
+func unstructuredExpectedType(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	return u
+}
+
+// fromUnstructured decodes an unstructured object into the given type.
+// Objects which can not be decoded are logged and decoded partially.
+func fromUnstructured(obj interface{}, into interface{}) {
+	u := obj.(*unstructured.Unstructured)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, into); err != nil {
+		klog.ErrorS(err, "Failed to decode object", "kind", u.GetKind(), "namespace", u.GetNamespace(), "name", u.GetName())
+	}
+}
+
+// createUnstructuredListWatchFunc returns a function creating a ListWatch of
+// the given resource with the dynamic client.
+func createUnstructuredListWatchFunc(dynamicClient dynamic.Interface, resource schema.GroupVersionResource) func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+	return func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+		api := dynamicClient.Resource(resource).Namespace(ns)
+		return &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = fieldSelector
+				return api.List(context.TODO(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = fieldSelector
+				return api.Watch(context.TODO(), opts)
+			},
+		}
+	}
+}
+
+// addStatusConditionMetrics returns the metrics of the status conditions of an
+// unstructured object, labeled with the given keys and values.
+func addStatusConditionMetrics(conditions []metav1.Condition, keys, values []string) []*metric.Metric {
+	ms := []*metric.Metric{}
+	for _, c := range conditions {
+		for _, m := range addConditionMetrics(v1.ConditionStatus(c.Status)) {
+			m.LabelKeys = append(append([]string{}, keys...), "condition", "status")
+			m.LabelValues = append(append(append([]string{}, values...), c.Type), m.LabelValues...)
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 
@@ -117,12 +118,20 @@ func mapToPrometheusLabels(labels map[string]string, prefix string) ([]string, [
 			}
 		}
 		labelKeys = append(labelKeys, labelKey)
-		labelValues = append(labelValues, labels[k])
+		labelValues = append(labelValues, metric.TruncateLabelValue(labels[k]))
 	}
 	return labelKeys, labelValues
 }
 
+// utf8LabelNames is whether label names generated from Kubernetes label and
+// annotation keys which are not valid without UTF-8 support are kept verbatim,
+// see Builder.WithUTF8LabelNames.
+var utf8LabelNames bool
+
 func labelName(prefix, labelName string) string {
+	if utf8LabelNames && invalidLabelCharRE.MatchString(labelName) {
+		return prefix + "_" + labelName
+	}
 	return prefix + "_" + lintLabelName(sanitizeLabelName(labelName))
 }
 
@@ -184,6 +193,14 @@ func createPrometheusLabelKeysValues(prefix string, allKubeData map[string]strin
 		}
 
 		for _, l := range allowList {
+			if l != options.LabelWildcard && strings.Contains(l, options.LabelWildcard) {
+				for k, v := range allKubeData {
+					if matchKeyPattern(l, k) {
+						allowedKubeData[k] = v
+					}
+				}
+				continue
+			}
 			v, found := allKubeData[l]
 			if found {
 				allowedKubeData[l] = v
@@ -193,6 +210,34 @@ func createPrometheusLabelKeysValues(prefix string, allKubeData map[string]strin
 	return kubeMapToPrometheusLabels(prefix, allowedKubeData)
 }
 
+// matchKeyPattern returns whether the given label or annotation key matches
+// the pattern, in which each '*' matches any sequence of characters, e.g.
+// prometheus.io/* matches prometheus.io/scrape.
+func matchKeyPattern(pattern, key string) bool {
+	// Match the pattern greedily, backtracking to the last '*' on mismatches.
+	p, k := 0, 0
+	star, match := -1, 0
+	for k < len(key) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, match = p, k
+			p++
+		case p < len(pattern) && pattern[p] == key[k]:
+			p++
+			k++
+		case star >= 0:
+			match++
+			p, k = star+1, match
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
 // mergeKeyValues merges label keys and values slice pairs into a single slice pair.
 // Arguments are passed as equal-length pairs of slices, where the first slice contains keys and second contains values.
 // Example: mergeKeyValues(keys1, values1, keys2, values2) => (keys1+keys2, values1+values2)
@@ -214,3 +259,28 @@ func mergeKeyValues(keyValues ...[]string) (keys, values []string) {
 
 	return keys, values
 }
+
+// webhookServiceLabelKeys are the labels of the service a webhook of an
+// admission webhook configuration is called at.
+var webhookServiceLabelKeys = []string{"service_namespace", "service_name", "service_port"}
+
+// webhookServiceLabelValues returns the values of webhookServiceLabelKeys of
+// the given client config. They are empty if the webhook is called at a URL.
+func webhookServiceLabelValues(c admissionregistrationv1.WebhookClientConfig) []string {
+	if c.Service == nil {
+		return []string{"", "", ""}
+	}
+	port := ""
+	if c.Service.Port != nil {
+		port = strconv.FormatInt(int64(*c.Service.Port), 10)
+	}
+	return []string{c.Service.Namespace, c.Service.Name, port}
+}
+
+// stringValue returns the value of s, or an empty string if s is nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -266,6 +266,23 @@ func TestKubeLabelsToPrometheusLabels(t *testing.T) {
 
 }
 
+func TestKubeLabelsToPrometheusUTF8Labels(t *testing.T) {
+	utf8LabelNames = true
+	defer func() { utf8LabelNames = false }()
+
+	keys, values := kubeMapToPrometheusLabels("label", map[string]string{
+		"app.kubernetes.io/name": "foo",
+		"camelCase":              "bar",
+	})
+	expectKeys := []string{"label_app.kubernetes.io/name", "label_camel_case"}
+	if !reflect.DeepEqual(keys, expectKeys) {
+		t.Errorf("expected label keys %v, got %v", expectKeys, keys)
+	}
+	if expectValues := []string{"foo", "bar"}; !reflect.DeepEqual(values, expectValues) {
+		t.Errorf("expected label values %v, got %v", expectValues, values)
+	}
+}
+
 func TestMergeKeyValues(t *testing.T) {
 	testCases := []struct {
 		name               string
@@ -319,3 +336,41 @@ func TestMergeKeyValues(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchKeyPattern(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{pattern: "prometheus.io/*", key: "prometheus.io/scrape", want: true},
+		{pattern: "prometheus.io/*", key: "prometheus.io/", want: true},
+		{pattern: "prometheus.io/*", key: "example.com/prometheus.io/scrape", want: false},
+		{pattern: "*.example.com/*", key: "team.example.com/owner", want: true},
+		{pattern: "*.example.com/*", key: "example.com/owner", want: false},
+		{pattern: "*/owner", key: "a.b/c/owner", want: true},
+		{pattern: "team-*-owner", key: "team-a-b-owner", want: true},
+		{pattern: "team-*-owner", key: "team-a-b-owners", want: false},
+		{pattern: "*", key: "app", want: true},
+	}
+	for _, tc := range testCases {
+		if got := matchKeyPattern(tc.pattern, tc.key); got != tc.want {
+			t.Errorf("matchKeyPattern(%q, %q) = %v, want %v", tc.pattern, tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestCreatePrometheusLabelKeysValuesPatterns(t *testing.T) {
+	annotations := map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "8080",
+		"team.example.com/a":   "b",
+		"other":                "c",
+	}
+	keys, values := createPrometheusLabelKeysValues("annotation", annotations, []string{"prometheus.io/*", "other"})
+	wantKeys := []string{"annotation_other", "annotation_prometheus_io_port", "annotation_prometheus_io_scrape"}
+	wantValues := []string{"c", "8080", "true"}
+	if !reflect.DeepEqual(keys, wantKeys) || !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("got keys %v and values %v, want keys %v and values %v", keys, values, wantKeys, wantValues)
+	}
+}
@@ -78,6 +78,78 @@ var (
 				}
 			}),
 		),
+		*generator.NewFamilyGenerator(
+			"kube_validatingwebhookconfiguration_webhook_info",
+			"Information about the webhooks of the ValidatingWebhookConfiguration.",
+			metric.Gauge,
+			"",
+			wrapValidatingWebhookConfigurationFunc(func(vwc *admissionregistrationv1.ValidatingWebhookConfiguration) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(vwc.Webhooks))
+				for _, w := range vwc.Webhooks {
+					failurePolicy := ""
+					if w.FailurePolicy != nil {
+						failurePolicy = string(*w.FailurePolicy)
+					}
+					matchPolicy := ""
+					if w.MatchPolicy != nil {
+						matchPolicy = string(*w.MatchPolicy)
+					}
+					sideEffects := ""
+					if w.SideEffects != nil {
+						sideEffects = string(*w.SideEffects)
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   append([]string{"webhook", "failure_policy", "match_policy", "side_effects"}, webhookServiceLabelKeys...),
+						LabelValues: append([]string{w.Name, failurePolicy, matchPolicy, sideEffects}, webhookServiceLabelValues(w.ClientConfig)...),
+						Value:       1,
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_validatingwebhookconfiguration_webhook_timeout_seconds",
+			"Timeout of the calls of the webhooks of the ValidatingWebhookConfiguration.",
+			metric.Gauge,
+			"",
+			wrapValidatingWebhookConfigurationFunc(func(vwc *admissionregistrationv1.ValidatingWebhookConfiguration) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(vwc.Webhooks))
+				for _, w := range vwc.Webhooks {
+					if w.TimeoutSeconds == nil {
+						continue
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"webhook"},
+						LabelValues: []string{w.Name},
+						Value:       float64(*w.TimeoutSeconds),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGenerator(
+			"kube_validatingwebhookconfiguration_webhook_rules",
+			"Number of rules matching the requests sent to the webhooks of the ValidatingWebhookConfiguration.",
+			metric.Gauge,
+			"",
+			wrapValidatingWebhookConfigurationFunc(func(vwc *admissionregistrationv1.ValidatingWebhookConfiguration) *metric.Family {
+				ms := make([]*metric.Metric, 0, len(vwc.Webhooks))
+				for _, w := range vwc.Webhooks {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"webhook"},
+						LabelValues: []string{w.Name},
+						Value:       float64(len(w.Rules)),
+					})
+				}
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 	}
 )
 
@@ -28,6 +28,12 @@ import (
 func TestValidatingWebhookConfigurationStore(t *testing.T) {
 	startTime := 1501569018
 	metav1StartTime := metav1.Unix(int64(startTime), 0)
+	port := int32(8443)
+	url := "https://webhook.example.com/validate"
+	failurePolicy := admissionregistrationv1.Ignore
+	matchPolicy := admissionregistrationv1.Equivalent
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	timeoutSeconds := int32(5)
 
 	cases := []generateMetricsTestCase{
 		{
@@ -69,6 +75,49 @@ func TestValidatingWebhookConfigurationStore(t *testing.T) {
 			`,
 			MetricNames: []string{"kube_validatingwebhookconfiguration_created", "kube_validatingwebhookconfiguration_info", "kube_validatingwebhookconfiguration_metadata_resource_version"},
 		},
+		{
+			Obj: &admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "validatingwebhookconfiguration3",
+				},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{
+					{
+						Name: "pods.example.com",
+						ClientConfig: admissionregistrationv1.WebhookClientConfig{
+							Service: &admissionregistrationv1.ServiceReference{Namespace: "webhooks", Name: "pods", Port: &port},
+						},
+						Rules: []admissionregistrationv1.RuleWithOperations{
+							{Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create}},
+							{Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Update}},
+						},
+						FailurePolicy:  &failurePolicy,
+						MatchPolicy:    &matchPolicy,
+						SideEffects:    &sideEffects,
+						TimeoutSeconds: &timeoutSeconds,
+					},
+					{
+						Name: "external.example.com",
+						ClientConfig: admissionregistrationv1.WebhookClientConfig{
+							URL: &url,
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_validatingwebhookconfiguration_webhook_info Information about the webhooks of the ValidatingWebhookConfiguration.
+				# HELP kube_validatingwebhookconfiguration_webhook_rules Number of rules matching the requests sent to the webhooks of the ValidatingWebhookConfiguration.
+				# HELP kube_validatingwebhookconfiguration_webhook_timeout_seconds Timeout of the calls of the webhooks of the ValidatingWebhookConfiguration.
+				# TYPE kube_validatingwebhookconfiguration_webhook_info gauge
+				# TYPE kube_validatingwebhookconfiguration_webhook_rules gauge
+				# TYPE kube_validatingwebhookconfiguration_webhook_timeout_seconds gauge
+				kube_validatingwebhookconfiguration_webhook_info{failure_policy="",match_policy="",namespace="",validatingwebhookconfiguration="validatingwebhookconfiguration3",service_name="",service_namespace="",service_port="",side_effects="",webhook="external.example.com"} 1
+				kube_validatingwebhookconfiguration_webhook_info{failure_policy="Ignore",match_policy="Equivalent",namespace="",validatingwebhookconfiguration="validatingwebhookconfiguration3",service_name="pods",service_namespace="webhooks",service_port="8443",side_effects="None",webhook="pods.example.com"} 1
+				kube_validatingwebhookconfiguration_webhook_rules{namespace="",validatingwebhookconfiguration="validatingwebhookconfiguration3",webhook="external.example.com"} 0
+				kube_validatingwebhookconfiguration_webhook_rules{namespace="",validatingwebhookconfiguration="validatingwebhookconfiguration3",webhook="pods.example.com"} 2
+				kube_validatingwebhookconfiguration_webhook_timeout_seconds{namespace="",validatingwebhookconfiguration="validatingwebhookconfiguration3",webhook="pods.example.com"} 5
+			`,
+			MetricNames: []string{"kube_validatingwebhookconfiguration_webhook_info", "kube_validatingwebhookconfiguration_webhook_rules", "kube_validatingwebhookconfiguration_webhook_timeout_seconds"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(validatingWebhookConfigurationMetricFamilies)
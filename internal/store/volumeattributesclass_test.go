@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestVolumeAttributesClassStore(t *testing.T) {
+	startTime := 1501569018
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &storagev1.VolumeAttributesClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "gold",
+					CreationTimestamp: metav1.Time{Time: time.Unix(int64(startTime), 0)},
+				},
+				DriverName: "pd.csi.storage.gke.io",
+				Parameters: map[string]string{
+					"iops": "5000",
+				},
+			},
+			Want: `
+				# HELP kube_volumeattributesclass_created Unix creation timestamp
+				# HELP kube_volumeattributesclass_info Information about volumeattributesclass.
+				# TYPE kube_volumeattributesclass_created gauge
+				# TYPE kube_volumeattributesclass_info gauge
+				kube_volumeattributesclass_created{volumeattributesclass="gold"} 1.501569018e+09
+				kube_volumeattributesclass_info{driver_name="pd.csi.storage.gke.io",volumeattributesclass="gold"} 1
+			`,
+			MetricNames: []string{
+				"kube_volumeattributesclass_created",
+				"kube_volumeattributesclass_info",
+			},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(volumeAttributesClassMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		c.Headers = generator.ExtractMetricFamilyHeaders(volumeAttributesClassMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
@@ -19,16 +19,19 @@ package internal
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/oklog/run"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 	"k8s.io/klog/v2"
 
 	"k8s.io/kube-state-metrics/v2/pkg/app"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
 )
 
@@ -36,13 +39,23 @@ import (
 func RunKubeStateMetricsWrapper(opts *options.Options) {
 
 	KSMRunOrDie := func(ctx context.Context) {
-		if err := app.RunKubeStateMetricsWrapper(ctx, opts); err != nil {
+		err := app.RunKubeStateMetricsWrapper(ctx, opts)
+		var signalErr run.SignalError
+		if errors.As(err, &signalErr) {
+			klog.InfoS("Received termination signal, exiting", "signal", signalErr.Signal)
+			klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+		}
+		if err != nil {
 			klog.ErrorS(err, "Failed to run kube-state-metrics")
 			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 		}
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	// Changes of the options config file are applied by KSM itself. The
+	// CustomResourceConfigFile is read from it as well, without modifying the
+	// options the config file is applied to.
+	crcFile := opts.CustomResourceConfigFile
 	if file := options.GetConfigFile(*opts); file != "" {
 		cfgViper := viper.New()
 		cfgViper.SetConfigType("yaml")
@@ -55,33 +68,27 @@ func RunKubeStateMetricsWrapper(opts *options.Options) {
 			}
 			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 		}
-		cfgViper.OnConfigChange(func(e fsnotify.Event) {
-			klog.Infof("Changes detected: %s\n", e.Name)
-			cancel()
-			// Wait for the ports to be released.
-			<-time.After(3 * time.Second)
-			ctx, cancel = context.WithCancel(context.Background())
-			go KSMRunOrDie(ctx)
-		})
-		cfgViper.WatchConfig()
 
-		// Merge configFile values with opts so we get the CustomResourceConfigFile from config as well
 		configFile, err := os.ReadFile(filepath.Clean(file))
 		if err != nil {
 			klog.ErrorS(err, "failed to read options configuration file", "file", file)
 		}
-
-		yaml.Unmarshal(configFile, opts)
+		var config struct {
+			CustomResourceConfigFile string `yaml:"custom_resource_config_file"`
+		}
+		if yaml.Unmarshal(configFile, &config) == nil && config.CustomResourceConfigFile != "" {
+			crcFile = config.CustomResourceConfigFile
+		}
 	}
-	if opts.CustomResourceConfigFile != "" {
+	if crcFile != "" {
 		crcViper := viper.New()
 		crcViper.SetConfigType("yaml")
-		crcViper.SetConfigFile(opts.CustomResourceConfigFile)
+		crcViper.SetConfigFile(crcFile)
 		if err := crcViper.ReadInConfig(); err != nil {
 			if errors.Is(err, viper.ConfigFileNotFoundError{}) {
-				klog.ErrorS(err, "Custom resource configuration file not found", "file", opts.CustomResourceConfigFile)
+				klog.ErrorS(err, "Custom resource configuration file not found", "file", crcFile)
 			} else {
-				klog.ErrorS(err, "Error reading Custom resource configuration file", "file", opts.CustomResourceConfigFile)
+				klog.ErrorS(err, "Error reading Custom resource configuration file", "file", crcFile)
 			}
 			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 		}
@@ -99,3 +106,90 @@ func RunKubeStateMetricsWrapper(opts *options.Options) {
 	KSMRunOrDie(ctx)
 	select {}
 }
+
+// RunDoctor runs the checks of the doctor subcommand, prints its report and
+// exits with 1 if errors were found.
+func RunDoctor(opts *options.Options) {
+	if err := opts.Validate(); err != nil {
+		klog.ErrorS(err, "Validating options error")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	report, err := app.RunDoctor(context.Background(), opts)
+	if err != nil {
+		klog.ErrorS(err, "Failed to run doctor")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	_ = report.Write(os.Stdout)
+	if report.HasErrors() {
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+}
+
+// RunGenerateCRSConfig writes a starter Custom Resource State config for the
+// CustomResourceDefinitions of the cluster to stdout.
+func RunGenerateCRSConfig(opts *options.Options, generateCRSConfigOpts *options.GenerateCRSConfigOptions) {
+	if err := app.GenerateCRSConfig(context.Background(), os.Stdout, opts, generateCRSConfigOpts.Groups); err != nil {
+		klog.ErrorS(err, "Failed to generate Custom Resource State config")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+}
+
+// RunRBACGen writes the RBAC manifests kube-state-metrics requires with the
+// given options to stdout.
+func RunRBACGen(opts *options.Options, rbacGenOpts *options.RBACGenOptions) {
+	if err := opts.Validate(); err != nil {
+		klog.ErrorS(err, "Validating options error")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	if err := app.RBACGen(os.Stdout, opts, rbacGenOpts.ServiceAccount, rbacGenOpts.ServiceAccountNamespace); err != nil {
+		klog.ErrorS(err, "Failed to generate RBAC manifests")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+}
+
+// RunValidate validates a Custom Resource State config file, prints its report
+// and exits with 1 if errors were found, and with 2 if only warnings were
+// found in strict mode.
+func RunValidate(validateOpts *options.ValidateOptions) {
+	if validateOpts.CustomResourceConfigFile == "" {
+		klog.ErrorS(nil, "--custom-resource-state-config-file is required")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	f, err := os.Open(filepath.Clean(validateOpts.CustomResourceConfigFile))
+	if err != nil {
+		klog.ErrorS(err, "Custom Resource State Metrics file could not be opened")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	defer f.Close()
+	report := customresourcestate.Validate(f)
+	_ = report.Write(os.Stdout)
+	klog.FlushAndExit(klog.ExitFlushTimeout, report.ExitCode(validateOpts.Strict))
+}
+
+// RunMigrateCRSConfig writes a Custom Resource State config file migrated to
+// the current schema to stdout and lists the changes on stderr.
+func RunMigrateCRSConfig(migrateOpts *options.MigrateCRSConfigOptions) {
+	if migrateOpts.CustomResourceConfigFile == "" {
+		klog.ErrorS(nil, "--custom-resource-state-config-file is required")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	f, err := os.Open(filepath.Clean(migrateOpts.CustomResourceConfigFile))
+	if err != nil {
+		klog.ErrorS(err, "Custom Resource State Metrics file could not be opened")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	defer f.Close()
+	migrations, err := customresourcestate.Migrate(f, os.Stdout)
+	if err != nil {
+		klog.ErrorS(err, "Failed to migrate Custom Resource State Metrics file")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	for _, m := range migrations {
+		fmt.Fprintln(os.Stderr, m)
+	}
+	fmt.Fprintf(os.Stderr, "%d change(s)\n", len(migrations))
+	klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+}
@@ -58,6 +58,7 @@
           'persistentvolumes',
           'namespaces',
           'endpoints',
+          'events',
         ],
         verbs: ['list', 'watch'],
       },
@@ -111,6 +112,7 @@
         apiGroups: ['certificates.k8s.io'],
         resources: [
           'certificatesigningrequests',
+          'clustertrustbundles',
         ],
         verbs: ['list', 'watch'],
       },
@@ -126,6 +128,7 @@
         resources: [
           'storageclasses',
           'volumeattachments',
+          'volumeattributesclasses',
         ],
         verbs: ['list', 'watch'],
       },
@@ -163,6 +166,38 @@
         ],
         verbs: ['list', 'watch'],
       },
+      {
+        apiGroups: ['flowcontrol.apiserver.k8s.io'],
+        resources: [
+          'flowschemas',
+          'prioritylevelconfigurations',
+        ],
+        verbs: ['list', 'watch'],
+      },
+      {
+        apiGroups: ['resource.k8s.io'],
+        resources: [
+          'deviceclasses',
+          'resourceclaims',
+          'resourceclaimtemplates',
+          'resourceslices',
+        ],
+        verbs: ['list', 'watch'],
+      },
+      {
+        apiGroups: ['node.k8s.io'],
+        resources: [
+          'runtimeclasses',
+        ],
+        verbs: ['list', 'watch'],
+      },
+      {
+        apiGroups: ['apiextensions.k8s.io'],
+        resources: [
+          'customresourcedefinitions',
+        ],
+        verbs: ['list', 'watch'],
+      },
      ];
 
     {
@@ -17,6 +17,10 @@ limitations under the License.
 package main
 
 import (
+	// Embed the time zone database to parse the time zones of cron jobs
+	// regardless of the image.
+	_ "time/tzdata"
+
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
@@ -31,6 +35,19 @@ func main() {
 		internal.RunKubeStateMetricsWrapper(opts)
 	}
 	opts.AddFlags(cmd)
+	cmd.AddCommand(
+		options.NewDoctorCommand(opts, func() {
+			internal.RunDoctor(opts)
+		}),
+		options.NewGenerateCRSConfigCommand(opts, func(generateCRSConfigOpts *options.GenerateCRSConfigOptions) {
+			internal.RunGenerateCRSConfig(opts, generateCRSConfigOpts)
+		}),
+		options.NewMigrateCRSConfigCommand(internal.RunMigrateCRSConfig),
+		options.NewRBACGenCommand(opts, func(rbacGenOpts *options.RBACGenOptions) {
+			internal.RunRBACGen(opts, rbacGenOpts)
+		}),
+		options.NewValidateCommand(internal.RunValidate),
+	)
 
 	if err := opts.Parse(); err != nil {
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
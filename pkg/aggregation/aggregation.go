@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aggregation implements aggregations of the series of metric
+// families by a subset of their labels.
+package aggregation
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// Op is an aggregation operation.
+type Op string
+
+const (
+	// Sum sums the values of the aggregated series.
+	Sum Op = "sum"
+	// Count counts the aggregated series.
+	Count Op = "count"
+)
+
+// SumBy returns a family with the same name, help and type as f and one series
+// per distinct combination of the values of the given labels among the series
+// of f, whose value is the sum of the values of these series. All other labels
+// are dropped. The series are ordered by their labels.
+func SumBy(f *metric.Family, labels []string) *metric.Family {
+	return aggregate(f, Sum, labels)
+}
+
+// CountBy returns a family like SumBy, whose series count the series of f
+// instead of summing their values.
+func CountBy(f *metric.Family, labels []string) *metric.Family {
+	return aggregate(f, Count, labels)
+}
+
+func aggregate(f *metric.Family, op Op, labels []string) *metric.Family {
+	a := NewAggregator(op, labels)
+	for _, m := range f.Metrics {
+		a.Add(m)
+	}
+	return a.Family(f.Name, f.Help, f.Type)
+}
+
+// Aggregator aggregates series one by one by a subset of their labels, without
+// keeping the series themselves.
+type Aggregator struct {
+	op     Op
+	labels []string
+	groups map[string]*metric.Metric
+}
+
+// NewAggregator returns an Aggregator applying op to the series with the same
+// values of the given labels.
+func NewAggregator(op Op, labels []string) *Aggregator {
+	return &Aggregator{op: op, labels: labels, groups: map[string]*metric.Metric{}}
+}
+
+// Add adds a series to the aggregation.
+func (a *Aggregator) Add(m *metric.Metric) {
+	keyValues := groupLabels(m, a.labels)
+	key := strings.Join(keyValues, "\xff")
+	g, ok := a.groups[key]
+	if !ok {
+		g = &metric.Metric{}
+		for i := 0; i < len(keyValues); i += 2 {
+			g.LabelKeys = append(g.LabelKeys, keyValues[i])
+			g.LabelValues = append(g.LabelValues, keyValues[i+1])
+		}
+		a.groups[key] = g
+	}
+	switch a.op {
+	case Count:
+		g.Value++
+	default:
+		g.Value += m.Value
+	}
+}
+
+// Family returns the aggregated series as a family of the given name, help and
+// type. The series are ordered by their labels.
+func (a *Aggregator) Family(name, help string, t metric.Type) *metric.Family {
+	keys := make([]string, 0, len(a.groups))
+	for key := range a.groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	f := &metric.Family{Name: name, Help: help, Type: t, Metrics: make([]*metric.Metric, 0, len(keys))}
+	for _, key := range keys {
+		f.Metrics = append(f.Metrics, a.groups[key])
+	}
+	return f
+}
+
+// groupLabels returns the names and values of the given labels of m, in the
+// order of the given labels. Labels which m does not have are skipped.
+func groupLabels(m *metric.Metric, labels []string) []string {
+	keyValues := make([]string, 0, 2*len(labels))
+	for _, label := range labels {
+		for i, k := range m.LabelKeys {
+			if k == label {
+				keyValues = append(keyValues, k, m.LabelValues[i])
+				break
+			}
+		}
+	}
+	return keyValues
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregation
+
+import (
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+func TestAggregation(t *testing.T) {
+	phase := func(namespace, pod, phase string, value float64) *metric.Metric {
+		return &metric.Metric{
+			LabelKeys:   []string{"namespace", "pod", "phase"},
+			LabelValues: []string{namespace, pod, phase},
+			Value:       value,
+		}
+	}
+	f := &metric.Family{Name: "kube_pod_status_phase", Metrics: []*metric.Metric{
+		phase("b", "pod1", "Running", 1),
+		phase("b", "pod1", "Pending", 0),
+		phase("a", "pod2", "Running", 1),
+		phase("b", "pod3", "Running", 1),
+	}}
+
+	tests := []struct {
+		desc   string
+		got    *metric.Family
+		expect string
+	}{
+		{
+			desc: "sum by namespace and phase",
+			got:  SumBy(f, []string{"namespace", "phase"}),
+			expect: `kube_pod_status_phase{namespace="a",phase="Running"} 1
+kube_pod_status_phase{namespace="b",phase="Pending"} 0
+kube_pod_status_phase{namespace="b",phase="Running"} 2
+`,
+		},
+		{
+			desc:   "sum without labels",
+			got:    SumBy(f, nil),
+			expect: "kube_pod_status_phase 3\n",
+		},
+		{
+			desc: "count by missing and present labels",
+			got:  CountBy(f, []string{"node", "namespace"}),
+			expect: `kube_pod_status_phase{namespace="a"} 1
+kube_pod_status_phase{namespace="b"} 3
+`,
+		},
+	}
+	for _, test := range tests {
+		if got := string(test.got.ByteSlice()); got != test.expect {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.desc, test.expect, got)
+		}
+	}
+}
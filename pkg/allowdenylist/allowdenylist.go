@@ -58,11 +58,13 @@ func New(allow, deny map[string]struct{}) (*AllowDenyList, error) {
 	}, nil
 }
 
-// Parse parses and compiles all of the regexes in the allowDenyList.
+// Parse parses and compiles all of the regexes in the allowDenyList. Each
+// item is either an exact metric name or a regex which has to match the whole
+// name, e.g. kube_pod_container_.* matches all families of pod containers.
 func (l *AllowDenyList) Parse() error {
 	regexes := make([]*regexp.Regexp, 0, len(l.list))
 	for item := range l.list {
-		r, err := regexp.Compile(item)
+		r, err := CompilePattern(item)
 		if err != nil {
 			return err
 		}
@@ -140,6 +142,13 @@ func (l *AllowDenyList) Test(generator generator.FamilyGenerator) bool {
 	return l.IsIncluded(generator.Name)
 }
 
+// CompilePattern compiles an item of a metric allow-, deny- or opt-in list.
+// The resulting regex matches whole metric names only, such that e.g.
+// kube_pod_info does not match kube_pod_info_extra.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
 func copyList(l map[string]struct{}) map[string]struct{} {
 	newList := map[string]struct{}{}
 	for k, v := range l {
@@ -113,6 +113,29 @@ func TestInclude(t *testing.T) {
 			t.Fatal("expected included item to be included")
 		}
 	})
+	t.Run("matches whole metric names only", func(t *testing.T) {
+		denylist, err := New(map[string]struct{}{}, map[string]struct{}{"kube_pod_status_ready": {}, "kube_pod_container_.*": {}})
+		if err != nil {
+			t.Fatal("expected New() to not fail")
+		}
+		err = denylist.Parse()
+		if err != nil {
+			t.Fatalf("expected Parse() to not fail, but got error : %v", err)
+		}
+
+		if denylist.IsIncluded("kube_pod_status_ready") {
+			t.Fatal("expected exact metric name to be excluded")
+		}
+		if denylist.IsIncluded("kube_pod_container_info") {
+			t.Fatal("expected metric matching the pattern to be excluded")
+		}
+		if denylist.IsExcluded("kube_pod_status_ready_time") {
+			t.Fatal("expected metric with excluded prefix to be included")
+		}
+		if denylist.IsExcluded("kube_pod_init_container_info") {
+			t.Fatal("expected metric containing the pattern to be included")
+		}
+	})
 	t.Run("removes during pattern match when in denyist mode", func(t *testing.T) {
 		item1 := "kube_pod_container_resource_requests_cpu_cores"
 		item2 := "kube_pod_container_resource_requests_memory_bytes"
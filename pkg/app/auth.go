@@ -0,0 +1,210 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// authAllowedTTL and authDeniedTTL are the durations for which the
+	// results of reviews are cached to not review each scrape.
+	authAllowedTTL = 2 * time.Minute
+	authDeniedTTL  = 10 * time.Second
+	// maxCachedAuthResults bounds the number of cached review results.
+	maxCachedAuthResults = 1024
+)
+
+// authResult is a cached result of reviewing a token for a path.
+type authResult struct {
+	status  int
+	expires time.Time
+}
+
+// delegatingAuth authenticates requests by their bearer token via
+// TokenReviews and authorizes the authenticated users via
+// SubjectAccessReviews, as done by other Kubernetes components delegating
+// authentication and authorization to the apiserver.
+type delegatingAuth struct {
+	kubeClient clientset.Interface
+	// resource are the resource attributes users are authorized for. If nil,
+	// users are authorized for the get verb of the request path.
+	resource *authorizationv1.ResourceAttributes
+	// namespacesPath is the prefix of the paths of the metrics of single
+	// namespaces. Users are authorized for the resource in the namespace of
+	// such paths instead of the configured one.
+	namespacesPath string
+	now            func() time.Time
+
+	mtx   sync.Mutex
+	cache map[[sha256.Size]byte]authResult
+}
+
+// newDelegatingAuth returns a delegatingAuth authorizing users for the given
+// resource in the form resource[.group][/subresource] in the given namespace,
+// or for the request path if the resource is empty.
+func newDelegatingAuth(kubeClient clientset.Interface, resource, namespace string) (*delegatingAuth, error) {
+	a := &delegatingAuth{
+		kubeClient: kubeClient,
+		now:        time.Now,
+		cache:      map[[sha256.Size]byte]authResult{},
+	}
+	if resource != "" {
+		attrs, err := parseResourceAttributes(resource)
+		if err != nil {
+			return nil, err
+		}
+		attrs.Namespace = namespace
+		a.resource = attrs
+	}
+	return a, nil
+}
+
+// parseResourceAttributes parses resource[.group][/subresource] into
+// resource attributes of the get verb.
+func parseResourceAttributes(s string) (*authorizationv1.ResourceAttributes, error) {
+	attrs := &authorizationv1.ResourceAttributes{Verb: "get"}
+	resource, subresource, _ := strings.Cut(s, "/")
+	attrs.Resource, attrs.Group, _ = strings.Cut(resource, ".")
+	attrs.Subresource = subresource
+	if attrs.Resource == "" || strings.Contains(subresource, "/") {
+		return nil, fmt.Errorf("invalid resource %q, expected resource[.group][/subresource]", s)
+	}
+	return attrs, nil
+}
+
+// wrap returns a handler serving requests allowed by the reviews with next.
+// Requests of the excluded paths, e.g. health checks, are not reviewed.
+func (a *delegatingAuth) wrap(next http.Handler, excludedPaths ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, p := range excludedPaths {
+			if r.URL.Path == p {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		token, ok := bearerToken(r)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if status := a.review(r, token); status != http.StatusOK {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// review returns http.StatusOK if the token is authenticated and authorized
+// for the request, the status code of the response otherwise.
+func (a *delegatingAuth) review(r *http.Request, token string) int {
+	key := sha256.Sum256([]byte(token + "\x00" + r.URL.Path))
+	now := a.now()
+
+	a.mtx.Lock()
+	cached, ok := a.cache[key]
+	a.mtx.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.status
+	}
+
+	status, err := a.reviewToken(r, token)
+	if err != nil {
+		// Failed reviews are not cached, so that they are retried with the
+		// next request.
+		klog.ErrorS(err, "Failed to review request", "path", r.URL.Path)
+		return http.StatusInternalServerError
+	}
+
+	ttl := authAllowedTTL
+	if status != http.StatusOK {
+		ttl = authDeniedTTL
+	}
+	a.mtx.Lock()
+	if len(a.cache) >= maxCachedAuthResults {
+		a.cache = map[[sha256.Size]byte]authResult{}
+	}
+	a.cache[key] = authResult{status: status, expires: now.Add(ttl)}
+	a.mtx.Unlock()
+	return status
+}
+
+func (a *delegatingAuth) reviewToken(r *http.Request, token string) (int, error) {
+	tr, err := a.kubeClient.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create TokenReview: %w", err)
+	}
+	if !tr.Status.Authenticated {
+		return http.StatusUnauthorized, nil
+	}
+
+	user := tr.Status.User
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+		},
+	}
+	if len(user.Extra) > 0 {
+		sar.Spec.Extra = make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for k, v := range user.Extra {
+			sar.Spec.Extra[k] = authorizationv1.ExtraValue(v)
+		}
+	}
+	if a.resource != nil {
+		sar.Spec.ResourceAttributes = a.resource.DeepCopy()
+		if a.namespacesPath != "" && strings.HasPrefix(r.URL.Path, a.namespacesPath) {
+			sar.Spec.ResourceAttributes.Namespace = strings.TrimPrefix(r.URL.Path, a.namespacesPath)
+		}
+	} else {
+		sar.Spec.NonResourceAttributes = &authorizationv1.NonResourceAttributes{Path: r.URL.Path, Verb: "get"}
+	}
+	sar, err = a.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), sar, metav1.CreateOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+	if !sar.Status.Allowed {
+		klog.V(4).InfoS("Denied request", "user", user.Username, "path", r.URL.Path, "reason", sar.Status.Reason)
+		return http.StatusForbidden, nil
+	}
+	return http.StatusOK, nil
+}
+
+// bearerToken returns the bearer token of the Authorization header of r.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestDelegatingAuth(t *testing.T) {
+	var reviews int
+	var gotSAR *authorizationv1.SubjectAccessReview
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		tr := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch tr.Spec.Token {
+		case "prometheus", "other":
+			tr.Status.Authenticated = true
+			tr.Status.User = authenticationv1.UserInfo{Username: tr.Spec.Token, Groups: []string{"system:authenticated"}}
+		}
+		return true, tr, nil
+	})
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		gotSAR = action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		gotSAR.Status.Allowed = gotSAR.Spec.User == "prometheus"
+		return true, gotSAR, nil
+	})
+
+	auth, err := newDelegatingAuth(kubeClient, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := auth.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), healthzPath)
+
+	tests := []struct {
+		path   string
+		token  string
+		status int
+	}{
+		{path: metricsPath, token: "prometheus", status: http.StatusOK},
+		{path: metricsPath, token: "other", status: http.StatusForbidden},
+		{path: metricsPath, token: "invalid", status: http.StatusUnauthorized},
+		{path: metricsPath, status: http.StatusUnauthorized},
+		{path: healthzPath, status: http.StatusOK},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		if test.token != "" {
+			req.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != test.status {
+			t.Errorf("%s with token %q: expected status %d, got %d", test.path, test.token, test.status, rec.Code)
+		}
+	}
+
+	attrs := gotSAR.Spec.NonResourceAttributes
+	if attrs == nil || attrs.Path != metricsPath || attrs.Verb != "get" {
+		t.Errorf("unexpected non-resource attributes %+v", attrs)
+	}
+
+	// Results are cached.
+	reviews = 0
+	req := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+	req.Header.Set("Authorization", "Bearer prometheus")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if reviews != 0 {
+		t.Errorf("expected cached review, got %d reviews", reviews)
+	}
+}
+
+func TestDelegatingAuthNamespacesPath(t *testing.T) {
+	var gotSAR *authorizationv1.SubjectAccessReview
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		tr := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		tr.Status.Authenticated = true
+		tr.Status.User = authenticationv1.UserInfo{Username: "tenant"}
+		return true, tr, nil
+	})
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		gotSAR = action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		gotSAR.Status.Allowed = true
+		return true, gotSAR, nil
+	})
+
+	auth, err := newDelegatingAuth(kubeClient, "services/proxy", "monitoring")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.namespacesPath = namespacesPath
+	handler := auth.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for path, namespace := range map[string]string{
+		metricsPath:                 "monitoring",
+		namespacesPath + "team-a":   "team-a",
+		"/metrics/namespaces-other": "monitoring",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer tenant")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if attrs := gotSAR.Spec.ResourceAttributes; attrs == nil || attrs.Namespace != namespace || attrs.Resource != "services" {
+			t.Errorf("%s: expected services/proxy in namespace %s, got %+v", path, namespace, attrs)
+		}
+	}
+}
+
+func TestParseResourceAttributes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    authorizationv1.ResourceAttributes
+		wantErr bool
+	}{
+		{in: "services/proxy", want: authorizationv1.ResourceAttributes{Verb: "get", Resource: "services", Subresource: "proxy"}},
+		{in: "deployments.apps", want: authorizationv1.ResourceAttributes{Verb: "get", Resource: "deployments", Group: "apps"}},
+		{in: "/proxy", wantErr: true},
+		{in: "services/proxy/extra", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := parseResourceAttributes(test.in)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", test.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", test.in, err)
+		}
+		if *got != test.want {
+			t.Errorf("%s: expected %+v, got %+v", test.in, test.want, *got)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
+	ksmtypes "k8s.io/kube-state-metrics/v2/pkg/builder/types"
+	"k8s.io/kube-state-metrics/v2/pkg/features"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/optin"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// ErrRestartRequired is returned by RunKubeStateMetrics if the options config
+// file changed options which can not be applied at runtime. RunKubeStateMetrics
+// has to be run again to apply them.
+var ErrRestartRequired = errors.New("options config file changed options which require a restart")
+
+// loadConfigFile decodes the options config file into a copy of the given
+// options and validates the result.
+func loadConfigFile(base *options.Options, data []byte) (*options.Options, error) {
+	opts := base.Clone()
+	if err := yaml.Unmarshal(data, opts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal options config file: %w", err)
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid options config file: %w", err)
+	}
+	return opts, nil
+}
+
+// withoutStoreOptions returns a copy of the options without the options
+// applied by applyStoreOptions, which can be changed at runtime.
+func withoutStoreOptions(opts *options.Options) *options.Options {
+	c := opts.Clone()
+	c.MetricAllowlist, c.MetricDenylist, c.MetricOptInList, c.MetricPrefixes = nil, nil, nil, nil
+	c.AnnotationsAllowList, c.LabelsAllowList, c.LabelsDenyList = nil, nil, nil
+	c.LabelsRecommended = false
+	c.FieldSelectors, c.MaxObjects, c.LazyResources, c.AggregatedResources = nil, nil, nil, nil
+	c.TopK, c.ExcludeCompleted = nil, nil
+	return c
+}
+
+// applyStoreOptions configures the store builder with the options selecting
+// the generated metrics and labels of each object.
+func applyStoreOptions(b ksmtypes.BuilderInterface, opts *options.Options) error {
+	allowDenyList, err := allowdenylist.New(opts.MetricAllowlist, opts.MetricDenylist)
+	if err != nil {
+		return err
+	}
+	if err := allowDenyList.Parse(); err != nil {
+		return fmt.Errorf("error initializing the allowdeny list: %v", err)
+	}
+	klog.InfoS("Metric allow-denylisting", "allowDenyStatus", allowDenyList.Status())
+
+	featureGate, err := features.New(opts.FeatureGates)
+	if err != nil {
+		return fmt.Errorf("error initializing the feature gates: %v", err)
+	}
+	optInList := make(map[string]struct{}, len(opts.MetricOptInList))
+	for name := range opts.MetricOptInList {
+		optInList[name] = struct{}{}
+	}
+	for _, name := range features.OptInFamilies(featureGate) {
+		optInList[name] = struct{}{}
+	}
+	optInMetricFamilyFilter, err := optin.NewMetricFamilyFilter(optInList)
+	if err != nil {
+		return fmt.Errorf("error initializing the opt-in metric list: %v", err)
+	}
+	if optInMetricFamilyFilter.Count() > 0 {
+		klog.InfoS("Metrics which were opted into", "optInMetricsFamilyStatus", optInMetricFamilyFilter.Status())
+	}
+
+	b.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter(
+		allowDenyList,
+		optInMetricFamilyFilter,
+	))
+	b.WithLabelsDenylist(opts.LabelsDenyList)
+	b.WithAllowAnnotations(opts.AnnotationsAllowList)
+	b.WithRecommendedLabels(opts.LabelsRecommended)
+	if err := b.WithAllowLabels(opts.LabelsAllowList); err != nil {
+		return fmt.Errorf("failed to set up labels allowlist: %v", err)
+	}
+	if err := b.WithFieldSelectors(opts.FieldSelectors); err != nil {
+		return fmt.Errorf("failed to set up field selectors: %v", err)
+	}
+	if err := b.WithMaxObjects(opts.MaxObjects); err != nil {
+		return fmt.Errorf("failed to set up max objects: %v", err)
+	}
+	if err := b.WithTopK(opts.TopK); err != nil {
+		return fmt.Errorf("failed to set up top-k: %v", err)
+	}
+	if err := b.WithExcludeCompleted(opts.ExcludeCompleted); err != nil {
+		return fmt.Errorf("failed to set up excluded completed objects: %v", err)
+	}
+	if err := b.WithLazyResources(opts.LazyResources.AsSlice()); err != nil {
+		return fmt.Errorf("failed to set up lazy resources: %v", err)
+	}
+	if err := b.WithMetricPrefixes(opts.MetricPrefixes); err != nil {
+		return fmt.Errorf("failed to set up metric prefixes: %v", err)
+	}
+	if err := b.WithAggregatedResources(opts.AggregatedResources); err != nil {
+		return fmt.Errorf("failed to set up aggregated resources: %v", err)
+	}
+	return nil
+}
+
+// configReloader reloads the options config file once it changes or SIGHUP is
+// received. Changes of the options applied by applyStoreOptions are applied by
+// rebuilding the stores, other changes require a restart.
+type configReloader struct {
+	path string
+	// base are the options of the command line, which the config file
+	// overrides.
+	base    *options.Options
+	current *options.Options
+	data    []byte
+	// reconfigure rebuilds the stores after applying f to the store builder.
+	reconfigure func(f func(ksmtypes.BuilderInterface) error) error
+	// onReload is called with the result of each reload of a changed file.
+	onReload func(data []byte, err error)
+}
+
+// Run reloads the config file until ctx is done. It returns
+// ErrRestartRequired once the config file changed options which can not be
+// applied at runtime.
+func (r *configReloader) Run(ctx context.Context) error {
+	return watchConfigFile(ctx, r.path, r.reload)
+}
+
+func (r *configReloader) reload() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		r.onReload(nil, err)
+		return nil
+	}
+	if bytes.Equal(data, r.data) {
+		return nil
+	}
+	opts, err := loadConfigFile(r.base, data)
+	if err != nil {
+		r.data = data
+		r.onReload(data, err)
+		return nil
+	}
+	if !reflect.DeepEqual(withoutStoreOptions(opts), withoutStoreOptions(r.current)) {
+		return ErrRestartRequired
+	}
+	err = r.reconfigure(func(b ksmtypes.BuilderInterface) error {
+		return applyStoreOptions(b, opts)
+	})
+	r.data = data
+	r.onReload(data, err)
+	if err != nil {
+		// Keep the previous options to compare the next change to.
+		return nil
+	}
+	r.current = opts
+	return nil
+}
+
+// watchConfigFile calls reload whenever the directory of the config file
+// changes or SIGHUP is received, until ctx is done or reload returns an
+// error. Watching the directory covers files replaced by renaming, e.g.
+// mounted ConfigMaps.
+func watchConfigFile(ctx context.Context, path string, reload func() error) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch options config file: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to watch options config file: %w", err)
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			klog.InfoS("Received SIGHUP, reloading options config file", "file", path)
+		case <-watcher.Events:
+		case err := <-watcher.Errors:
+			klog.ErrorS(err, "Failed to watch options config file", "file", path)
+			continue
+		}
+		if err := reload(); err != nil {
+			return err
+		}
+	}
+}
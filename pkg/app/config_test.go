@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/internal/store"
+	ksmtypes "k8s.io/kube-state-metrics/v2/pkg/builder/types"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestConfigReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	initial := []byte("resources: [pods]\nmetric_denylist: [kube_pod_info]\n")
+	base := options.NewOptions()
+	current, err := loadConfigFile(base, initial)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reconfigured int
+	var reloadErr error
+	r := &configReloader{
+		path:    path,
+		base:    base,
+		current: current,
+		data:    initial,
+		reconfigure: func(f func(ksmtypes.BuilderInterface) error) error {
+			reconfigured++
+			return f(store.NewBuilder())
+		},
+		onReload: func(data []byte, err error) {
+			reloadErr = err
+		},
+	}
+
+	tests := []struct {
+		desc         string
+		config       string
+		reconfigured int
+		reloadErr    bool
+		restart      bool
+	}{
+		{
+			desc:         "changed metric denylist is applied at runtime",
+			config:       "resources: [pods]\nmetric_denylist: [kube_pod_created]\n",
+			reconfigured: 1,
+		},
+		{
+			desc:         "unchanged file is not applied",
+			config:       "resources: [pods]\nmetric_denylist: [kube_pod_created]\n",
+			reconfigured: 1,
+		},
+		{
+			desc:         "invalid file is not applied",
+			config:       "resources: [pods]\nmetric_denylist: kube_pod_created: 1\n",
+			reconfigured: 1,
+			reloadErr:    true,
+		},
+		{
+			desc:         "changed resources require a restart",
+			config:       "resources: [pods, nodes]\nmetric_denylist: [kube_pod_created]\n",
+			reconfigured: 1,
+			restart:      true,
+		},
+	}
+	for _, test := range tests {
+		if err := os.WriteFile(path, []byte(test.config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		reloadErr = nil
+		err := r.reload()
+		if errors.Is(err, ErrRestartRequired) != test.restart {
+			t.Errorf("%s: expected restart %v, got %v", test.desc, test.restart, err)
+		}
+		if reconfigured != test.reconfigured {
+			t.Errorf("%s: expected %d reconfigurations, got %d", test.desc, test.reconfigured, reconfigured)
+		}
+		if (reloadErr != nil) != test.reloadErr {
+			t.Errorf("%s: expected reload error %v, got %v", test.desc, test.reloadErr, reloadErr)
+		}
+	}
+	if _, ok := r.current.MetricDenylist["kube_pod_created"]; !ok {
+		t.Errorf("expected the applied options to be the current ones, got %v", r.current.MetricDenylist)
+	}
+	if len(base.MetricDenylist) != 0 {
+		t.Errorf("expected the base options not to be modified, got %v", base.MetricDenylist)
+	}
+}
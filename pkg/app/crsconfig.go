@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/dynamic"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// GenerateCRSConfig writes a starter Custom Resource State config for the
+// CustomResourceDefinitions of the cluster to w. Only CustomResourceDefinitions
+// of the given API groups are included, or all of them if groups is empty.
+// The CustomResourceDefinitions are further restricted by
+// --custom-resource-autodiscovery-selector.
+func GenerateCRSConfig(ctx context.Context, w io.Writer, opts *options.Options, groups []string) error {
+	restConfig, err := newRestConfig(opts.Apiserver, opts.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	resources, err := customresourcestate.NewDiscoverer(client, opts.CustomResourceAutodiscoverySelector).Discover(ctx)
+	if err != nil {
+		return err
+	}
+	return writeCRSConfig(w, filterResourceGroups(resources, groups))
+}
+
+// filterResourceGroups returns the resources of the given API groups, or all
+// resources if groups is empty.
+func filterResourceGroups(resources []customresourcestate.Resource, groups []string) []customresourcestate.Resource {
+	if len(groups) == 0 {
+		return resources
+	}
+	var filtered []customresourcestate.Resource
+	for _, r := range resources {
+		for _, g := range groups {
+			if r.GroupVersionKind.Group == g {
+				filtered = append(filtered, r)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// writeCRSConfig writes a Custom Resource State config of the resources to w.
+// Fields with zero values are omitted to keep the config short.
+func writeCRSConfig(w io.Writer, resources []customresourcestate.Resource) error {
+	if resources == nil {
+		resources = []customresourcestate.Resource{}
+	}
+	var node yaml.Node
+	if err := node.Encode(struct {
+		Kind string                          `yaml:"kind"`
+		Spec customresourcestate.MetricsSpec `yaml:"spec"`
+	}{
+		Kind: "CustomResourceStateMetrics",
+		Spec: customresourcestate.MetricsSpec{Resources: resources},
+	}); err != nil {
+		return err
+	}
+	pruneEmptyFields(&node)
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated from the CustomResourceDefinitions of the cluster. Review the metrics and extend them with\n")
+	buf.WriteString("# further fields of the resources before use.\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// pruneEmptyFields removes the fields of mappings whose values are null,
+// false, zero, empty strings or empty collections, except for the resources
+// list.
+func pruneEmptyFields(node *yaml.Node) {
+	for _, n := range node.Content {
+		pruneEmptyFields(n)
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value != "resources" && isEmptyNode(value) {
+			continue
+		}
+		content = append(content, key, value)
+	}
+	node.Content = content
+}
+
+func isEmptyNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		return len(node.Content) == 0
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return true
+		case "!!bool":
+			return node.Value == "false"
+		case "!!int":
+			return node.Value == "0"
+		case "!!str":
+			return strings.TrimSpace(node.Value) == ""
+		}
+	}
+	return false
+}
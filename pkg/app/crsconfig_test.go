@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+func TestWriteCRSConfig(t *testing.T) {
+	crd := func(group, kind, plural string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": plural + "." + group},
+			"spec": map[string]interface{}{
+				"group": group,
+				"names": map[string]interface{}{"kind": kind, "plural": plural},
+				"scope": "Namespaced",
+				"versions": []interface{}{map[string]interface{}{
+					"name": "v1", "served": true, "storage": true,
+					"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{"properties": map[string]interface{}{
+						"spec": map[string]interface{}{"properties": map[string]interface{}{
+							"replicas": map[string]interface{}{"type": "integer"},
+						}},
+					}}},
+				}},
+			},
+		}}
+	}
+	resources := customresourcestate.ResourcesFromCRDs([]*unstructured.Unstructured{
+		crd("example.com", "Foo", "foos"),
+		crd("other.io", "Bar", "bars"),
+	})
+	resources = filterResourceGroups(resources, []string{"example.com"})
+
+	var buf bytes.Buffer
+	if err := writeCRSConfig(&buf, resources); err != nil {
+		t.Fatal(err)
+	}
+	want := `kind: CustomResourceStateMetrics
+spec:
+  resources:
+    - metricNamePrefix: kube_customresource_foo
+      groupVersionKind:
+        group: example.com
+        version: v1
+        kind: Foo
+      labelsFromPath:
+        name:
+          - metadata
+          - name
+        namespace:
+          - metadata
+          - namespace
+      metrics:
+        - name: info
+          help: Information about the Foo.
+          each:
+            type: Info
+            info:
+              path:
+                - metadata
+        - name: created
+          help: Unix creation timestamp of the Foo.
+          each:
+            type: Gauge
+            gauge:
+              path:
+                - metadata
+                - creationTimestamp
+        - name: spec_replicas
+          help: The number of desired replicas of the Foo.
+          each:
+            type: Gauge
+            gauge:
+              path:
+                - spec
+                - replicas
+              nilBehavior: skip
+      resourcePlural: foos
+`
+	got := buf.String()
+	if !strings.HasPrefix(got, "#") || !strings.HasSuffix(got, want) {
+		t.Errorf("expected config\n%s\ngot\n%s", want, got)
+	}
+
+	// The generated config is valid.
+	factories, err := customresourcestate.FromConfig(yaml.NewDecoder(strings.NewReader(got)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(factories) != 1 || factories[0].Name() != "foos" {
+		t.Errorf("expected a factory of foos, got %d factories", len(factories))
+	}
+
+	// Without resources, an empty list is written.
+	buf.Reset()
+	if err := writeCRSConfig(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "spec:\n  resources: []\n") {
+		t.Errorf("expected an empty resources list, got\n%s", buf.String())
+	}
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"k8s.io/kube-state-metrics/v2/internal/store"
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/metricshandler"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/sharding"
+)
+
+// DoctorSeverity is the severity of a finding of the doctor subcommand.
+type DoctorSeverity string
+
+// Supported doctor severities.
+const (
+	DoctorSeverityError   DoctorSeverity = "error"
+	DoctorSeverityWarning DoctorSeverity = "warning"
+)
+
+// DoctorFinding is a problem found by the doctor subcommand.
+type DoctorFinding struct {
+	Severity DoctorSeverity
+	// Check is the name of the check which found the problem, e.g. rbac.
+	Check   string
+	Message string
+	// Hint describes how to fix the problem, if known.
+	Hint string
+}
+
+func (f DoctorFinding) String() string {
+	s := fmt.Sprintf("%s %s: %s", strings.ToUpper(string(f.Severity)), f.Check, f.Message)
+	if f.Hint != "" {
+		s += "\n  hint: " + strings.ReplaceAll(f.Hint, "\n", "\n  ")
+	}
+	return s
+}
+
+// DoctorReport is the result of the doctor subcommand.
+type DoctorReport struct {
+	Findings []DoctorFinding
+	// Checked are the resources whose permissions were checked.
+	Checked []string
+}
+
+// HasErrors returns whether the report contains errors.
+func (r *DoctorReport) HasErrors() bool {
+	return r.count(DoctorSeverityError) > 0
+}
+
+// Write writes the findings and a summary of the report to w.
+func (r *DoctorReport) Write(w io.Writer) error {
+	for _, f := range r.Findings {
+		if _, err := fmt.Fprintln(w, f); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "checked %d resource(s): %d error(s), %d warning(s)\n", len(r.Checked), r.count(DoctorSeverityError), r.count(DoctorSeverityWarning))
+	return err
+}
+
+func (r *DoctorReport) count(s DoctorSeverity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == s {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *DoctorReport) add(s DoctorSeverity, check, hint, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, DoctorFinding{
+		Severity: s,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+		Hint:     hint,
+	})
+}
+
+// RunDoctor checks against the cluster of the given options whether
+// kube-state-metrics is allowed to list and watch all enabled resources,
+// whether the custom resources it is configured for exist and whether its
+// sharding settings are consistent.
+func RunDoctor(ctx context.Context, opts *options.Options) (*DoctorReport, error) {
+	opts, factories, err := loadSubcommandOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig, err := newRestConfig(opts.Apiserver, opts.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %v", err)
+	}
+	kubeClient, err := clientset.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %v", err)
+	}
+	if _, err := kubeClient.Discovery().ServerVersion(); err != nil {
+		return nil, fmt.Errorf("failed to connect to the apiserver: %v", err)
+	}
+	return newDoctor(kubeClient, opts, factories).run(ctx), nil
+}
+
+// apiResource is a resource served by the apiserver.
+type apiResource struct {
+	kinds      map[string]string
+	namespaced bool
+}
+
+// doctor runs the checks of the doctor subcommand.
+type doctor struct {
+	kubeClient clientset.Interface
+	opts       *options.Options
+	factories  []customresource.RegistryFactory
+	report     *DoctorReport
+
+	// served are the resources served by the apiserver by group and
+	// resource, nil if they could not be discovered.
+	served map[schema.GroupResource]*apiResource
+	// namespaces are the namespaces objects are listed in, which contain
+	// only metav1.NamespaceAll for all namespaces.
+	namespaces []string
+}
+
+func newDoctor(kubeClient clientset.Interface, opts *options.Options, factories []customresource.RegistryFactory) *doctor {
+	return &doctor{kubeClient: kubeClient, opts: opts, factories: factories, report: &DoctorReport{}}
+}
+
+func (d *doctor) run(ctx context.Context) *DoctorReport {
+	d.discover()
+	d.resolveNamespaces(ctx)
+	d.checkResources(ctx)
+	d.checkCustomResources(ctx)
+	d.checkSharding(ctx)
+	return d.report
+}
+
+// discover indexes the resources served by the apiserver.
+func (d *doctor) discover() {
+	_, lists, err := d.kubeClient.Discovery().ServerGroupsAndResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			d.report.add(DoctorSeverityWarning, "discovery", "", "failed to discover the served resources, skipping checks whether resources exist: %v", err)
+			return
+		}
+		d.report.add(DoctorSeverityWarning, "discovery", "", "failed to discover some API groups, resources of them are reported as missing: %v", err)
+	}
+	d.served = map[schema.GroupResource]*apiResource{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			gr := gv.WithResource(r.Name).GroupResource()
+			res, ok := d.served[gr]
+			if !ok {
+				res = &apiResource{kinds: map[string]string{}, namespaced: r.Namespaced}
+				d.served[gr] = res
+			}
+			res.kinds[gv.Version] = r.Kind
+		}
+	}
+}
+
+// resolveNamespaces determines the namespaces objects are listed in.
+func (d *doctor) resolveNamespaces(ctx context.Context) {
+	d.namespaces = d.opts.Namespaces.GetNamespaces()
+	if d.opts.NamespacesSelector == "" {
+		return
+	}
+	d.checkAccess(ctx, "namespaces", "", "", "list", "watch")
+	list, err := d.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: d.opts.NamespacesSelector})
+	if err != nil {
+		d.report.add(DoctorSeverityWarning, "namespaces", "", "failed to list the namespaces matching --namespaces-selector, checking permissions in all namespaces: %v", err)
+		d.namespaces = []string{metav1.NamespaceAll}
+		return
+	}
+	d.namespaces = nil
+	for _, ns := range list.Items {
+		d.namespaces = append(d.namespaces, ns.Name)
+	}
+	if len(d.namespaces) == 0 {
+		d.report.add(DoctorSeverityWarning, "namespaces", "Label the namespaces to expose metrics of, or fix the selector.", "no namespaces match --namespaces-selector %q", d.opts.NamespacesSelector)
+	}
+}
+
+// enabledResources returns the enabled resources which are not custom
+// resources.
+func (d *doctor) enabledResources() []string {
+	switch {
+	case d.opts.CustomResourcesOnly:
+		return nil
+	case len(d.opts.Resources) == 0:
+		return options.DefaultResources.AsSlice()
+	default:
+		return d.opts.Resources.AsSlice()
+	}
+}
+
+// checkResources checks that the enabled resources are served and that they
+// can be listed and watched.
+func (d *doctor) checkResources(ctx context.Context) {
+	configured := map[string]bool{}
+	for _, f := range d.factories {
+		configured[f.Name()] = true
+	}
+	resources := d.enabledResources()
+	if d.opts.CustomResourceAutodiscovery {
+		resources = append(resources, "customresourcedefinitions")
+	}
+	if d.opts.PodWorkloadResolutionEnabled() {
+		resources = append(resources, "replicasets", "jobs")
+	}
+	sort.Strings(resources)
+	checked := map[string]bool{}
+	for _, resource := range resources {
+		if checked[resource] || configured[resource] {
+			continue
+		}
+		checked[resource] = true
+		group, ok := store.ResourceGroup(resource)
+		if !ok {
+			d.report.add(DoctorSeverityError, "resources", "Remove it from --resources.", "unknown resource %s", resource)
+			continue
+		}
+		gr := schema.GroupResource{Group: group, Resource: resource}
+		served, ok := d.served[gr]
+		if d.served != nil && !ok {
+			d.report.add(DoctorSeverityWarning, "resources", "Remove it from --resources if the API is not installed in the cluster.", "resource %s is not served by the apiserver, its metrics are missing", gr)
+		}
+		namespaced := store.ResourceNamespaced(resource)
+		if served != nil {
+			namespaced = served.namespaced
+		}
+		d.checkList(ctx, gr, namespaced)
+	}
+}
+
+// checkCustomResources checks that the configured custom resources exist and
+// that they can be listed and watched.
+func (d *doctor) checkCustomResources(ctx context.Context) {
+	for _, f := range d.factories {
+		u, ok := f.ExpectedType().(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		gvk := u.GroupVersionKind()
+		gr := schema.GroupResource{Group: gvk.Group, Resource: f.Name()}
+		namespaced := true
+		if d.served != nil {
+			var found bool
+			gr, namespaced, found = d.findKind(gvk)
+			if !found {
+				gr = schema.GroupResource{Group: gvk.Group, Resource: f.Name()}
+				d.report.add(DoctorSeverityError, "customresources", "Install the CustomResourceDefinition, or remove the resource from the Custom Resource State Metrics configuration.",
+					"%s of %s is not served by the apiserver", gvk.Kind, gvk.GroupVersion())
+			}
+		}
+		d.checkList(ctx, gr, namespaced)
+	}
+}
+
+// findKind returns the resource of the given kind, whether it is namespaced
+// and whether it is served.
+func (d *doctor) findKind(gvk schema.GroupVersionKind) (schema.GroupResource, bool, bool) {
+	for gr, r := range d.served {
+		if gr.Group == gvk.Group && r.kinds[gvk.Version] == gvk.Kind {
+			return gr, r.namespaced, true
+		}
+	}
+	return schema.GroupResource{}, false, false
+}
+
+// checkList checks that the given resource can be listed and watched in all
+// namespaces objects are listed in.
+func (d *doctor) checkList(ctx context.Context, gr schema.GroupResource, namespaced bool) {
+	d.report.Checked = append(d.report.Checked, gr.String())
+	if !namespaced {
+		d.checkAccess(ctx, gr.Resource, gr.Group, metav1.NamespaceAll, "list", "watch")
+		return
+	}
+	for _, ns := range d.namespaces {
+		d.checkAccess(ctx, gr.Resource, gr.Group, ns, "list", "watch")
+	}
+}
+
+// checkAccess checks whether the given verbs are allowed on the given
+// resource via SelfSubjectAccessReviews, and reports the denied ones.
+func (d *doctor) checkAccess(ctx context.Context, resource, group, namespace string, verbs ...string) {
+	var denied []string
+	for _, verb := range verbs {
+		review, err := d.kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Group:     group,
+					Resource:  resource,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			d.report.add(DoctorSeverityWarning, "rbac", "", "failed to check whether %s of %s is allowed: %v", verb, schema.GroupResource{Group: group, Resource: resource}, err)
+			continue
+		}
+		if !review.Status.Allowed {
+			denied = append(denied, verb)
+		}
+	}
+	if len(denied) == 0 {
+		return
+	}
+	gr := schema.GroupResource{Group: group, Resource: resource}
+	kind, scope := "ClusterRole", "all namespaces"
+	if namespace != metav1.NamespaceAll {
+		kind, scope = "Role", "namespace "+namespace
+	}
+	d.report.add(DoctorSeverityError, "rbac",
+		fmt.Sprintf("Add the following rule to the %s bound to the service account of kube-state-metrics:\n- apiGroups: [%q]\n  resources: [%q]\n  verbs: [%s]", kind, group, resource, strings.Join(verbs, ", ")),
+		"%s of %s denied in %s", strings.Join(denied, ", "), gr, scope)
+}
+
+// checkSharding checks that the sharding settings are consistent.
+func (d *doctor) checkSharding(ctx context.Context) {
+	opts := d.opts
+	autoSharding := opts.Pod != "" && opts.Namespace != ""
+	switch {
+	case opts.ShardingLeaseGroup != "" && autoSharding:
+		d.checkAccess(ctx, "leases", "coordination.k8s.io", opts.Namespace, "get", "list", "watch", "create", "update")
+		if opts.TotalShards != 1 {
+			d.report.add(DoctorSeverityWarning, "sharding", "Remove --shard and --total-shards.", "--shard and --total-shards are ignored, as shards are assigned by --sharding-lease-group")
+		}
+		return
+	case autoSharding:
+		d.checkAccess(ctx, "pods", "", opts.Namespace, "get")
+		d.checkAccess(ctx, "statefulsets", "apps", opts.Namespace, "get", "list", "watch")
+		shard, totalShards, err := metricshandler.ShardingFromStatefulSet(d.kubeClient, opts.Pod, opts.Namespace)
+		if err != nil {
+			d.report.add(DoctorSeverityError, "sharding", "Run kube-state-metrics as a StatefulSet for autosharding, or unset --pod or --pod-namespace.", "failed to detect the shard of pod %s/%s: %v", opts.Namespace, opts.Pod, err)
+			return
+		}
+		if int(shard) >= totalShards {
+			d.report.add(DoctorSeverityError, "sharding", "", "pod %s/%s is assigned shard %d of only %d shards", opts.Namespace, opts.Pod, shard, totalShards)
+		}
+		if opts.TotalShards != 1 {
+			d.report.add(DoctorSeverityWarning, "sharding", "Remove --shard and --total-shards.", "--shard and --total-shards are ignored, as the shard %d of %d is detected from the StatefulSet", shard, totalShards)
+		}
+		return
+	}
+	if opts.TotalShards < 1 {
+		d.report.add(DoctorSeverityError, "sharding", "", "--total-shards must be at least 1, got %d", opts.TotalShards)
+		return
+	}
+	if opts.Shard < 0 || int(opts.Shard) >= opts.TotalShards {
+		d.report.add(DoctorSeverityError, "sharding", "Set --shard to a number from 0 to --total-shards - 1 on each shard.", "--shard %d is out of range of --total-shards %d, no objects are exposed", opts.Shard, opts.TotalShards)
+	}
+	if opts.TotalShards > 1 && opts.Node != "" {
+		d.report.add(DoctorSeverityWarning, "sharding", "Use either --node or --shard and --total-shards.", "--node and --total-shards are both set, so only a shard of the pods of the node is exposed")
+	}
+	if opts.TotalShards > 1 && opts.ShardingStrategy == sharding.StrategyNamespace && len(d.namespaces) > 0 && d.namespaces[0] != metav1.NamespaceAll && len(d.namespaces) < opts.TotalShards {
+		d.report.add(DoctorSeverityWarning, "sharding", "Reduce --total-shards or use the uid sharding strategy.", "only %d namespace(s) are distributed among %d shards with the namespace sharding strategy, so some shards expose no objects", len(d.namespaces), opts.TotalShards)
+	}
+}
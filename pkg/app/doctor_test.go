@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestDoctor(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true},
+				{Name: "pods/status", Kind: "Pod", Namespaced: true},
+				{Name: "nodes", Kind: "Node"},
+			},
+		},
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "bars", Kind: "Bar", Namespaced: true},
+			},
+		},
+	}
+	var reviews []authorizationv1.ResourceAttributes
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sar := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := *sar.Spec.ResourceAttributes
+		reviews = append(reviews, attrs)
+		sar.Status.Allowed = !(attrs.Resource == "nodes" && attrs.Verb == "watch")
+		return true, sar, nil
+	})
+
+	factories, err := customresourcestate.FromConfig(yaml.NewDecoder(strings.NewReader(`
+spec:
+  resources:
+    - groupVersionKind: {group: example.com, version: v1, kind: Bar}
+      metrics:
+        - name: info
+          help: Info
+          each: {type: Info, info: {labelsFromPath: {name: [metadata, name]}}}
+    - groupVersionKind: {group: example.com, version: v1, kind: Foo}
+      metrics:
+        - name: info
+          help: Info
+          each: {type: Info, info: {labelsFromPath: {name: [metadata, name]}}}
+`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := options.NewOptions()
+	opts.Resources = options.ResourceSet{"pods": {}, "nodes": {}, "gateways": {}}
+	opts.Namespaces = options.NamespaceList{"ns1", "ns2"}
+	opts.TotalShards = 2
+	opts.Shard = 2
+	report := newDoctor(kubeClient, opts, factories).run(context.Background())
+
+	var got []string
+	for _, f := range report.Findings {
+		got = append(got, string(f.Severity)+" "+f.Message)
+	}
+	want := []string{
+		"warning resource gateways.gateway.networking.k8s.io is not served by the apiserver, its metrics are missing",
+		"error watch of nodes denied in all namespaces",
+		"error Foo of example.com/v1 is not served by the apiserver",
+		"error --shard 2 is out of range of --total-shards 2, no objects are exposed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected findings\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if !report.HasErrors() {
+		t.Error("expected errors")
+	}
+
+	// Namespaced resources are checked per namespace, cluster-scoped ones
+	// once.
+	resourceReviews := map[string]int{}
+	for _, r := range reviews {
+		if r.Resource == "pods" || r.Resource == "bars" || r.Resource == "nodes" {
+			resourceReviews[r.Resource+"/"+r.Namespace]++
+		}
+	}
+	wantReviews := map[string]int{"pods/ns1": 2, "pods/ns2": 2, "bars/ns1": 2, "bars/ns2": 2, "nodes/": 2}
+	for k, v := range wantReviews {
+		if resourceReviews[k] != v {
+			t.Errorf("expected %d reviews of %s, got %d", v, k, resourceReviews[k])
+		}
+	}
+}
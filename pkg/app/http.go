@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// newHTTPServer returns a server of the handler with the timeouts and limits
+// configured by the options.
+func newHTTPServer(handler http.Handler, opts *options.Options) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadTimeout:       opts.ServerReadTimeout,
+		ReadHeaderTimeout: opts.ServerReadHeaderTimeout,
+		WriteTimeout:      opts.ServerWriteTimeout,
+		IdleTimeout:       opts.ServerIdleTimeout,
+		MaxHeaderBytes:    opts.ServerMaxHeaderBytes,
+	}
+}
+
+// scrapeLimiter limits the number of concurrently served scrapes, as each
+// scrape buffers parts of the response and many concurrent scrapes can exhaust
+// the memory of the process.
+type scrapeLimiter struct {
+	slots    chan struct{}
+	rejected prometheus.Counter
+}
+
+// newScrapeLimiter returns a scrapeLimiter allowing the given number of
+// concurrent scrapes, or nil if the number is not positive.
+func newScrapeLimiter(max int, rejected prometheus.Counter) *scrapeLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &scrapeLimiter{slots: make(chan struct{}, max), rejected: rejected}
+}
+
+// wrap returns a handler responding with 503 Service Unavailable to requests
+// exceeding the limit and serving all other requests with next. A nil
+// scrapeLimiter returns next.
+func (l *scrapeLimiter) wrap(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case l.slots <- struct{}{}:
+			defer func() { <-l.slots }()
+			next.ServeHTTP(w, r)
+		default:
+			l.rejected.Inc()
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many concurrent scrapes", http.StatusServiceUnavailable)
+		}
+	})
+}
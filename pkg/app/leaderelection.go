@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metricshandler"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// newLeaderElector returns a LeaderElector which makes the MetricsHandler a
+// standby while this instance is not the leader.
+func newLeaderElector(kubeClient clientset.Interface, opts *options.Options, m *metricshandler.MetricsHandler, leader prometheus.Gauge) (*leaderelection.LeaderElector, error) {
+	identity := opts.Pod
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %w", err)
+		}
+		identity = hostname
+	}
+	namespace := opts.LeaderElectNamespace
+	if namespace == "" {
+		namespace = opts.Namespace
+	}
+
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Name: opts.LeaderElectLeaseName, Namespace: namespace},
+			Client:     kubeClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+		},
+		LeaseDuration:   opts.LeaderElectLeaseDuration,
+		RenewDeadline:   opts.LeaderElectRenewDeadline,
+		RetryPeriod:     opts.LeaderElectRetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            opts.LeaderElectLeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				klog.InfoS("Started leading, exposing metrics", "identity", identity)
+				leader.Set(1)
+				m.SetStandby(false)
+			},
+			OnStoppedLeading: func() {
+				klog.InfoS("Stopped leading, switching to standby", "identity", identity)
+				leader.Set(0)
+				m.SetStandby(true)
+			},
+			OnNewLeader: func(current string) {
+				if current != identity {
+					klog.InfoS("New leader elected", "leader", current)
+				}
+			},
+		},
+	})
+}
+
+// runLeaderElection takes part in leader election until ctx is done. After
+// losing the leadership, this instance stays a standby and tries to acquire
+// it again.
+func runLeaderElection(ctx context.Context, le *leaderelection.LeaderElector) error {
+	for ctx.Err() == nil {
+		le.Run(ctx)
+	}
+	return ctx.Err()
+}
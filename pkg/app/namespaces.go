@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// namespaceWatcher resolves the namespaces matching a label selector and
+// tracks changes to them.
+type namespaceWatcher struct {
+	client   clientset.Interface
+	selector string
+	denylist map[string]struct{}
+	// debounce is the time to wait for further namespace changes before the
+	// matching namespaces are recomputed.
+	debounce time.Duration
+}
+
+func newNamespaceWatcher(client clientset.Interface, selector string, denylist []string) *namespaceWatcher {
+	d := make(map[string]struct{}, len(denylist))
+	for _, ns := range denylist {
+		d[ns] = struct{}{}
+	}
+	return &namespaceWatcher{
+		client:   client,
+		selector: selector,
+		denylist: d,
+		debounce: 5 * time.Second,
+	}
+}
+
+// namespaces lists the matching namespaces once.
+func (w *namespaceWatcher) namespaces(ctx context.Context) (options.NamespaceList, error) {
+	list, err := w.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: w.selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	objs := make([]interface{}, 0, len(list.Items))
+	for i := range list.Items {
+		objs = append(objs, &list.Items[i])
+	}
+	return w.namespacesFromObjects(objs), nil
+}
+
+// run watches the matching namespaces and calls onChange with them whenever
+// they differ from the last ones, starting from initial.
+func (w *namespaceWatcher) run(ctx context.Context, initial options.NamespaceList, onChange func(options.NamespaceList) error) error {
+	api := w.client.CoreV1().Namespaces()
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.LabelSelector = w.selector
+			return api.List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.LabelSelector = w.selector
+			return api.Watch(ctx, opts)
+		},
+	}, &v1.Namespace{}, 0, cache.Indexers{})
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	})
+	go informer.Run(ctx.Done())
+
+	current := initial
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+
+		// Wait for further changes, e.g. if multiple namespaces are labeled at once.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.debounce):
+		}
+
+		namespaces := w.namespacesFromObjects(informer.GetStore().List())
+		if reflect.DeepEqual(namespaces, current) {
+			continue
+		}
+		klog.InfoS("Namespaces matching the selector changed", "namespaces", namespaces)
+		if err := onChange(namespaces); err != nil {
+			klog.ErrorS(err, "Failed to apply matching namespaces")
+			continue
+		}
+		current = namespaces
+	}
+}
+
+// namespacesFromObjects returns the sorted names of the given namespaces,
+// skipping denylisted ones.
+func (w *namespaceWatcher) namespacesFromObjects(objs []interface{}) options.NamespaceList {
+	namespaces := options.NamespaceList{}
+	for _, obj := range objs {
+		ns, ok := obj.(*v1.Namespace)
+		if !ok {
+			continue
+		}
+		if _, ok := w.denylist[ns.Name]; ok {
+			continue
+		}
+		namespaces = append(namespaces, ns.Name)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
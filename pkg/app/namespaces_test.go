@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func namespace(name string, labels map[string]string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestNamespaceWatcher(t *testing.T) {
+	payments := map[string]string{"team": "payments"}
+	client := fake.NewSimpleClientset(
+		namespace("payments-b", payments),
+		namespace("payments-a", payments),
+		namespace("payments-denied", payments),
+		namespace("checkout", map[string]string{"team": "checkout"}),
+	)
+
+	w := newNamespaceWatcher(client, "team=payments", []string{"payments-denied"})
+	w.debounce = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initial, err := w.namespaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (options.NamespaceList{"payments-a", "payments-b"}); !reflect.DeepEqual(initial, expected) {
+		t.Fatalf("expected namespaces %v, got %v", expected, initial)
+	}
+
+	changes := make(chan options.NamespaceList, 1)
+	go w.run(ctx, initial, func(n options.NamespaceList) error { //nolint:errcheck
+		changes <- n
+		return nil
+	})
+
+	if _, err := client.CoreV1().Namespaces().Update(ctx, namespace("checkout", payments), metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CoreV1().Namespaces().Delete(ctx, "payments-b", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := options.NamespaceList{"checkout", "payments-a"}
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case n := <-changes:
+			if reflect.DeepEqual(n, expected) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("expected namespaces to change to %v", expected)
+		}
+	}
+}
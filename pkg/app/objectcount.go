@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// discoverObjectCountResources returns the resources selected by
+// --object-count-resources in the version preferred by the apiserver. The
+// resources are selected as <resource>.<group>, <resource> for the core group,
+// or * for all resources which can be listed and watched. Selected resources
+// which are not served are logged and skipped.
+func discoverObjectCountResources(d discovery.DiscoveryInterface, selected options.ResourceSet) ([]metav1.APIResource, error) {
+	lists, err := discovery.ServerPreferredResources(d)
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) || len(lists) == 0 {
+			return nil, fmt.Errorf("failed to discover resources: %w", err)
+		}
+		klog.ErrorS(err, "Failed to discover the resources of some API groups, their objects are not counted")
+	}
+	_, all := selected["*"]
+	found := map[string]struct{}{}
+	var resources []metav1.APIResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse group version %q: %w", list.GroupVersion, err)
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || !hasVerbs(r.Verbs, "list", "watch") {
+				continue
+			}
+			name := r.Name
+			if gv.Group != "" {
+				name += "." + gv.Group
+			}
+			if _, ok := selected[name]; !ok && !all {
+				continue
+			}
+			found[name] = struct{}{}
+			r.Group, r.Version = gv.Group, gv.Version
+			resources = append(resources, r)
+		}
+	}
+	for name := range selected {
+		if _, ok := found[name]; !ok && name != "*" {
+			klog.InfoS("Resource selected for object counts is not served or can not be listed and watched, its objects are not counted", "resource", name)
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Group != resources[j].Group {
+			return resources[i].Group < resources[j].Group
+		}
+		return resources[i].Name < resources[j].Name
+	})
+	return resources, nil
+}
+
+// hasVerbs returns whether all given verbs are in verbs.
+func hasVerbs(verbs metav1.Verbs, want ...string) bool {
+	for _, w := range want {
+		found := false
+		for _, v := range verbs {
+			if v == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestDiscoverObjectCountResources(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "watch"}},
+				{Name: "pods/status", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"get", "patch"}},
+				{Name: "nodes", Kind: "Node", Verbs: metav1.Verbs{"get", "list", "watch"}},
+				{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: metav1.Verbs{"create"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "watch"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		desc     string
+		selected options.ResourceSet
+		expected []string
+	}{
+		{
+			desc:     "all resources which can be listed and watched",
+			selected: options.ResourceSet{"*": struct{}{}},
+			expected: []string{"nodes", "pods", "deployments.apps"},
+		},
+		{
+			desc:     "selected resources",
+			selected: options.ResourceSet{"pods": struct{}{}, "deployments.apps": struct{}{}, "foos.example.com": struct{}{}},
+			expected: []string{"pods", "deployments.apps"},
+		},
+		{
+			desc:     "resources of other groups are not selected by their name only",
+			selected: options.ResourceSet{"deployments": struct{}{}, "bindings": struct{}{}},
+		},
+	}
+	for _, test := range tests {
+		resources, err := discoverObjectCountResources(client.Discovery(), test.selected)
+		if err != nil {
+			t.Fatalf("%s: %v", test.desc, err)
+		}
+		var names []string
+		for _, r := range resources {
+			name := r.Name
+			if r.Group != "" {
+				name += "." + r.Group
+			}
+			if r.Version != "v1" {
+				t.Errorf("%s: expected version v1 of %s, got %q", test.desc, name, r.Version)
+			}
+			names = append(names, name)
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("%s: expected resources %v, got %v", test.desc, test.expected, names)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/kube-state-metrics/v2/internal/store"
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+var (
+	listWatchVerbs        = []string{"list", "watch"}
+	leaderElectionVerbs   = []string{"get", "create", "update"}
+	leaseShardingVerbs    = []string{"get", "list", "create", "update", "delete"}
+	statefulSetShardVerbs = []string{"get", "list", "watch"}
+)
+
+// rbacRule is a PolicyRule of a ClusterRole or Role.
+type rbacRule struct {
+	APIGroups []string `yaml:"apiGroups"`
+	Resources []string `yaml:"resources"`
+	Verbs     []string `yaml:"verbs"`
+}
+
+// rbacRules collects the resources of rules by their API group and verbs.
+type rbacRules map[string]map[string]struct{}
+
+func (r rbacRules) add(group, resource string, verbs ...string) {
+	key := group + "|" + strings.Join(verbs, ",")
+	if r[key] == nil {
+		r[key] = map[string]struct{}{}
+	}
+	r[key][resource] = struct{}{}
+}
+
+// list returns the rules sorted by their API group and verbs.
+func (r rbacRules) list() []rbacRule {
+	rules := make([]rbacRule, 0, len(r))
+	for key, set := range r {
+		group, verbs, _ := strings.Cut(key, "|")
+		resources := make([]string, 0, len(set))
+		for resource := range set {
+			resources = append(resources, resource)
+		}
+		sort.Strings(resources)
+		rules = append(rules, rbacRule{APIGroups: []string{group}, Resources: resources, Verbs: strings.Split(verbs, ",")})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].APIGroups[0] != rules[j].APIGroups[0] {
+			return rules[i].APIGroups[0] < rules[j].APIGroups[0]
+		}
+		return strings.Join(rules[i].Verbs, ",") < strings.Join(rules[j].Verbs, ",")
+	})
+	return rules
+}
+
+// requiredRBACRules returns the rules kube-state-metrics requires with the
+// given options in all namespaces, and per namespace. Custom resources are
+// assumed to be namespaced.
+func requiredRBACRules(opts *options.Options, factories []customresource.RegistryFactory) (rbacRules, map[string]rbacRules, error) {
+	cluster := rbacRules{}
+	namespaced := map[string]rbacRules{}
+	namespaces := opts.Namespaces.GetNamespaces()
+	allNamespaces := opts.NamespacesSelector != "" || len(namespaces) == 0 || namespaces[0] == metav1.NamespaceAll
+	addNamespaced := func(namespace, group, resource string, verbs ...string) {
+		if namespaced[namespace] == nil {
+			namespaced[namespace] = rbacRules{}
+		}
+		namespaced[namespace].add(group, resource, verbs...)
+	}
+	addListWatch := func(group, resource string, isNamespaced bool) {
+		if allNamespaces || !isNamespaced {
+			cluster.add(group, resource, listWatchVerbs...)
+			return
+		}
+		for _, ns := range namespaces {
+			addNamespaced(ns, group, resource, listWatchVerbs...)
+		}
+	}
+
+	configured := map[string]bool{}
+	for _, f := range factories {
+		configured[f.Name()] = true
+		u, ok := f.ExpectedType().(*unstructured.Unstructured)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown API group of custom resource %s", f.Name())
+		}
+		addListWatch(u.GroupVersionKind().Group, f.Name(), true)
+	}
+
+	var resources []string
+	switch {
+	case opts.CustomResourcesOnly:
+	case len(opts.Resources) == 0:
+		resources = options.DefaultResources.AsSlice()
+	default:
+		resources = opts.Resources.AsSlice()
+	}
+	if opts.PodWorkloadResolutionEnabled() {
+		resources = append(resources, "replicasets", "jobs")
+	}
+	if opts.CustomResourceAutodiscovery {
+		resources = append(resources, "customresourcedefinitions")
+	}
+	for _, resource := range resources {
+		if configured[resource] {
+			continue
+		}
+		group, ok := store.ResourceGroup(resource)
+		if !ok {
+			return nil, nil, fmt.Errorf("resource %s does not exist", resource)
+		}
+		addListWatch(group, resource, store.ResourceNamespaced(resource))
+	}
+
+	if opts.NamespacesSelector != "" {
+		cluster.add("", "namespaces", listWatchVerbs...)
+	}
+	for resource := range opts.ObjectCountResources {
+		if resource == "*" {
+			cluster.add("*", "*", listWatchVerbs...)
+			continue
+		}
+		resource, group, _ := strings.Cut(resource, ".")
+		cluster.add(group, resource, listWatchVerbs...)
+	}
+	if opts.AuthDelegation {
+		cluster.add("authentication.k8s.io", "tokenreviews", "create")
+		cluster.add("authorization.k8s.io", "subjectaccessreviews", "create")
+	}
+
+	if opts.Pod != "" && opts.Namespace != "" {
+		if opts.ShardingLeaseGroup != "" {
+			addNamespaced(opts.Namespace, "coordination.k8s.io", "leases", leaseShardingVerbs...)
+		} else {
+			addNamespaced(opts.Namespace, "", "pods", "get")
+			addNamespaced(opts.Namespace, "apps", "statefulsets", statefulSetShardVerbs...)
+		}
+	}
+	if opts.LeaderElect {
+		namespace := opts.LeaderElectNamespace
+		if namespace == "" {
+			namespace = opts.Namespace
+		}
+		addNamespaced(namespace, "coordination.k8s.io", "leases", leaderElectionVerbs...)
+	}
+	return cluster, namespaced, nil
+}
+
+// rbacObject is a ClusterRole, Role or a binding of them.
+type rbacObject struct {
+	APIVersion string        `yaml:"apiVersion"`
+	Kind       string        `yaml:"kind"`
+	Metadata   rbacMetadata  `yaml:"metadata"`
+	Rules      []rbacRule    `yaml:"rules,omitempty"`
+	RoleRef    *rbacRoleRef  `yaml:"roleRef,omitempty"`
+	Subjects   []rbacSubject `yaml:"subjects,omitempty"`
+}
+
+type rbacMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type rbacRoleRef struct {
+	APIGroup string `yaml:"apiGroup"`
+	Kind     string `yaml:"kind"`
+	Name     string `yaml:"name"`
+}
+
+type rbacSubject struct {
+	Kind      string `yaml:"kind"`
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+// RBACGen writes the ClusterRole, Roles and their bindings to the given
+// ServiceAccount which kube-state-metrics requires with the given options to
+// w. The roles and bindings are named like the ServiceAccount.
+func RBACGen(w io.Writer, opts *options.Options, serviceAccount, serviceAccountNamespace string) error {
+	opts, factories, err := loadSubcommandOptions(opts)
+	if err != nil {
+		return err
+	}
+	cluster, namespaced, err := requiredRBACRules(opts, factories)
+	if err != nil {
+		return err
+	}
+
+	subjects := []rbacSubject{{Kind: "ServiceAccount", Name: serviceAccount, Namespace: serviceAccountNamespace}}
+	var objects []rbacObject
+	if len(cluster) > 0 {
+		objects = append(objects,
+			rbacObject{
+				APIVersion: "rbac.authorization.k8s.io/v1",
+				Kind:       "ClusterRole",
+				Metadata:   rbacMetadata{Name: serviceAccount},
+				Rules:      cluster.list(),
+			},
+			rbacObject{
+				APIVersion: "rbac.authorization.k8s.io/v1",
+				Kind:       "ClusterRoleBinding",
+				Metadata:   rbacMetadata{Name: serviceAccount},
+				RoleRef:    &rbacRoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: serviceAccount},
+				Subjects:   subjects,
+			},
+		)
+	}
+	namespaces := make([]string, 0, len(namespaced))
+	for ns := range namespaced {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		objects = append(objects,
+			rbacObject{
+				APIVersion: "rbac.authorization.k8s.io/v1",
+				Kind:       "Role",
+				Metadata:   rbacMetadata{Name: serviceAccount, Namespace: ns},
+				Rules:      namespaced[ns].list(),
+			},
+			rbacObject{
+				APIVersion: "rbac.authorization.k8s.io/v1",
+				Kind:       "RoleBinding",
+				Metadata:   rbacMetadata{Name: serviceAccount, Namespace: ns},
+				RoleRef:    &rbacRoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: serviceAccount},
+				Subjects:   subjects,
+			},
+		)
+	}
+
+	var buf bytes.Buffer
+	if opts.CustomResourceAutodiscovery {
+		buf.WriteString("# --custom-resource-autodiscovery additionally requires list and watch of the discovered custom resources.\n")
+	}
+	if opts.PluginDir != "" {
+		buf.WriteString("# --plugin-dir additionally requires list and watch of the resources of the plugins.\n")
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, o := range objects {
+		if err := enc.Encode(o); err != nil {
+			return err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
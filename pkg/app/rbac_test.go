@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestRequiredRBACRules(t *testing.T) {
+	factories, err := customresourcestate.FromConfig(yaml.NewDecoder(strings.NewReader(`
+spec:
+  resources:
+    - groupVersionKind: {group: example.com, version: v1, kind: Foo}
+      metrics:
+        - name: info
+          help: Info
+          each: {type: Info, info: {labelsFromPath: {name: [metadata, name]}}}
+`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		opts          func(*options.Options)
+		wantCluster   []rbacRule
+		wantNamespace map[string][]rbacRule
+	}{
+		{
+			name: "all namespaces",
+			opts: func(o *options.Options) {
+				o.Resources = options.ResourceSet{"pods": {}, "nodes": {}, "deployments": {}}
+				o.AuthDelegation = true
+			},
+			wantCluster: []rbacRule{
+				{APIGroups: []string{""}, Resources: []string{"nodes", "pods"}, Verbs: []string{"list", "watch"}},
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"list", "watch"}},
+				{APIGroups: []string{"authentication.k8s.io"}, Resources: []string{"tokenreviews"}, Verbs: []string{"create"}},
+				{APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"subjectaccessreviews"}, Verbs: []string{"create"}},
+				{APIGroups: []string{"example.com"}, Resources: []string{"foos"}, Verbs: []string{"list", "watch"}},
+			},
+			wantNamespace: map[string][]rbacRule{},
+		},
+		{
+			name: "selected namespaces with autosharding",
+			opts: func(o *options.Options) {
+				o.Resources = options.ResourceSet{"pods": {}, "nodes": {}}
+				o.Namespaces = options.NamespaceList{"ns1", "ns2"}
+				o.Pod = "kube-state-metrics-0"
+				o.Namespace = "monitoring"
+			},
+			wantCluster: []rbacRule{
+				{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"list", "watch"}},
+			},
+			wantNamespace: map[string][]rbacRule{
+				"ns1": {
+					{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list", "watch"}},
+					{APIGroups: []string{"example.com"}, Resources: []string{"foos"}, Verbs: []string{"list", "watch"}},
+				},
+				"ns2": {
+					{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list", "watch"}},
+					{APIGroups: []string{"example.com"}, Resources: []string{"foos"}, Verbs: []string{"list", "watch"}},
+				},
+				"monitoring": {
+					{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}},
+					{APIGroups: []string{"apps"}, Resources: []string{"statefulsets"}, Verbs: []string{"get", "list", "watch"}},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := options.NewOptions()
+			test.opts(opts)
+			cluster, namespaced, err := requiredRBACRules(opts, factories)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cluster.list(); !reflect.DeepEqual(got, test.wantCluster) {
+				t.Errorf("expected cluster rules %+v, got %+v", test.wantCluster, got)
+			}
+			got := map[string][]rbacRule{}
+			for ns, rules := range namespaced {
+				got[ns] = rules.list()
+			}
+			if !reflect.DeepEqual(got, test.wantNamespace) {
+				t.Errorf("expected namespaced rules %+v, got %+v", test.wantNamespace, got)
+			}
+		})
+	}
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package app
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5" //nolint:gosec
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -29,6 +31,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -36,30 +39,46 @@ import (
 	"github.com/oklog/run"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
+	versioncollector "github.com/prometheus/client_golang/prometheus/collectors/version"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/version"
 	"github.com/prometheus/exporter-toolkit/web"
 	vpaclientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Initialize common client auth plugins.
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
 	"k8s.io/kube-state-metrics/v2/internal/store"
-	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
+	ksmtypes "k8s.io/kube-state-metrics/v2/pkg/builder/types"
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
 	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
-	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/features"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
 	"k8s.io/kube-state-metrics/v2/pkg/metricshandler"
-	"k8s.io/kube-state-metrics/v2/pkg/optin"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/otlp"
+	"k8s.io/kube-state-metrics/v2/pkg/plugin"
+	"k8s.io/kube-state-metrics/v2/pkg/recording"
+	"k8s.io/kube-state-metrics/v2/pkg/relabel"
+	"k8s.io/kube-state-metrics/v2/pkg/textfile"
 	"k8s.io/kube-state-metrics/v2/pkg/util/proc"
+	"k8s.io/kube-state-metrics/v2/pkg/watch"
 )
 
 const (
 	metricsPath = "/metrics"
 	healthzPath = "/healthz"
+	livezPath   = "/livez"
+	readyzPath  = "/readyz"
+	statePath   = "/state"
+	// namespacesPath is the prefix of the paths of the metrics of single
+	// namespaces, e.g. /metrics/namespaces/team-a.
+	namespacesPath = "/metrics/namespaces/"
 )
 
 // promLogger implements promhttp.Logger
@@ -75,26 +94,42 @@ func (pl promLogger) Log(v ...interface{}) error {
 	return nil
 }
 
-// RunKubeStateMetricsWrapper runs KSM with context cancellation.
+// RunKubeStateMetricsWrapper runs KSM with context cancellation. KSM is
+// restarted with the given options if its options config file changed options
+// which can not be applied at runtime.
 func RunKubeStateMetricsWrapper(ctx context.Context, opts *options.Options) error {
-	err := RunKubeStateMetrics(ctx, opts)
-	if ctx.Err() == context.Canceled {
-		klog.Infoln("Restarting: kube-state-metrics, metrics will be reset")
-		return nil
+	for {
+		err := RunKubeStateMetrics(ctx, opts.Clone())
+		if ctx.Err() == context.Canceled {
+			klog.Infoln("Restarting: kube-state-metrics, metrics will be reset")
+			return nil
+		}
+		if errors.Is(err, ErrRestartRequired) {
+			klog.InfoS("Restarting kube-state-metrics to apply the options config file, metrics will be reset")
+			continue
+		}
+		return err
 	}
-	return err
 }
 
 // RunKubeStateMetrics will build and run the kube-state-metrics.
 // Any out-of-tree custom resource metrics could be registered by newing a registry factory
 // which implements customresource.RegistryFactory and pass all factories into this function.
+// It returns ErrRestartRequired if the options config file changed options
+// which can not be applied at runtime.
 func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 	promLogger := promLogger{}
 
 	storeBuilder := store.NewBuilder()
 
+	featureGate, err := features.New(opts.FeatureGates)
+	if err != nil {
+		return fmt.Errorf("invalid feature gates: %v", err)
+	}
+
 	ksmMetricsRegistry := prometheus.NewRegistry()
-	ksmMetricsRegistry.MustRegister(version.NewCollector("kube_state_metrics"))
+	ksmMetricsRegistry.MustRegister(versioncollector.NewCollector("kube_state_metrics"))
+	features.RegisterMetrics(ksmMetricsRegistry, featureGate)
 	durationVec := promauto.With(ksmMetricsRegistry).NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:        "http_request_duration_seconds",
@@ -103,6 +138,14 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 			ConstLabels: prometheus.Labels{"handler": "metrics"},
 		}, []string{"method"},
 	)
+	sizeVec := promauto.With(ksmMetricsRegistry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "http_response_size_bytes",
+			Help:        "A histogram of response sizes for kube-state-metrics metrics handler.",
+			Buckets:     prometheus.ExponentialBuckets(1024, 4, 10),
+			ConstLabels: prometheus.Labels{"handler": "metrics"},
+		}, []string{"method"},
+	)
 	configHash := promauto.With(ksmMetricsRegistry).NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "kube_state_metrics_config_hash",
@@ -121,29 +164,43 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 
 	storeBuilder.WithMetrics(ksmMetricsRegistry)
 
+	var configStatuses []metricshandler.ConfigStatus
+	// baseOpts are the options of the command line, which reloads of the
+	// options config file are applied to.
+	baseOpts := opts.Clone()
+	var configFile []byte
 	got := options.GetConfigFile(*opts)
 	if got != "" {
-		configFile, err := os.ReadFile(filepath.Clean(got))
+		var err error
+		configFile, err = os.ReadFile(filepath.Clean(got))
 		if err != nil {
 			return fmt.Errorf("failed to read opts config file: %v", err)
 		}
 		// NOTE: Config value will override default values of intersecting options.
-		err = yaml.Unmarshal(configFile, opts)
+		loaded, err := loadConfigFile(baseOpts, configFile)
 		if err != nil {
 			// DO NOT end the process.
 			// We want to allow the user to still be able to fix the misconfigured config (redeploy or edit the configmaps) and reload KSM automatically once that's done.
-			klog.Warningf("failed to unmarshal opts config file: %v", err)
+			klog.Warningf("failed to load opts config file: %v", err)
 			// Wait for the next reload.
 			klog.Infof("misconfigured config detected, KSM will automatically reload on next write to the config")
 			klog.Infof("waiting for config to be fixed")
 			configSuccess.WithLabelValues("config", filepath.Clean(got)).Set(0)
-			<-ctx.Done()
-		} else {
-			configSuccess.WithLabelValues("config", filepath.Clean(got)).Set(1)
-			configSuccessTime.WithLabelValues("config", filepath.Clean(got)).SetToCurrentTime()
-			hash := md5HashAsMetricValue(configFile)
-			configHash.WithLabelValues("config", filepath.Clean(got)).Set(hash)
+			return watchConfigFile(ctx, filepath.Clean(got), func() error {
+				data, err := os.ReadFile(filepath.Clean(got))
+				if err != nil || bytes.Equal(data, configFile) {
+					return nil
+				}
+				return ErrRestartRequired
+			})
 		}
+		*opts = *loaded
+		configSuccess.WithLabelValues("config", filepath.Clean(got)).Set(1)
+		configSuccessTime.WithLabelValues("config", filepath.Clean(got)).SetToCurrentTime()
+		hash := md5HashAsMetricValue(configFile)
+		configHash.WithLabelValues("config", filepath.Clean(got)).Set(hash)
+		loadedAt := time.Now()
+		configStatuses = append(configStatuses, metricshandler.ConfigStatus{Type: "config", File: filepath.Clean(got), Successful: true, LoadedAt: &loadedAt})
 	}
 
 	// Loading custom resource state configuration from cli argument or config file
@@ -153,12 +210,36 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 	}
 
 	var factories []customresource.RegistryFactory
+	var resourceMapper *customresourcestate.ResourceMapper
 
 	if config != nil {
 		factories, err = customresourcestate.FromConfig(config)
 		if err != nil {
 			return fmt.Errorf("Parsing from Custom Resource State Metrics file failed: %v", err)
 		}
+		mapperConfig, err := newRestConfig(opts.Apiserver, opts.Kubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %v", err)
+		}
+		resourceMapper, err = customresourcestate.NewResourceMapper(mapperConfig, opts.CustomResourceMappingRefresh)
+		if err != nil {
+			return fmt.Errorf("failed to create custom resource mapper: %v", err)
+		}
+		resourceMapper.Configure(factories)
+	}
+	for _, profile := range opts.EnabledCustomResourceProfiles() {
+		profileFactories, err := profileCustomResourceFactories(profile, factories)
+		if err != nil {
+			return err
+		}
+		factories = append(factories, profileFactories...)
+	}
+	if opts.PluginDir != "" {
+		pluginFactories, err := plugin.Load(ctx, opts.PluginDir)
+		if err != nil {
+			return fmt.Errorf("failed to load plugins: %v", err)
+		}
+		factories = append(factories, pluginFactories...)
 	}
 	storeBuilder.WithCustomResourceStoreFactories(factories...)
 
@@ -171,13 +252,16 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		configSuccessTime.WithLabelValues("customresourceconfig", filepath.Clean(opts.CustomResourceConfigFile)).SetToCurrentTime()
 		hash := md5HashAsMetricValue(crcFile)
 		configHash.WithLabelValues("customresourceconfig", filepath.Clean(opts.CustomResourceConfigFile)).Set(hash)
-
+		loadedAt := time.Now()
+		configStatuses = append(configStatuses, metricshandler.ConfigStatus{Type: "customresourceconfig", File: filepath.Clean(opts.CustomResourceConfigFile), Successful: true, LoadedAt: &loadedAt})
 	}
 
 	resources := make([]string, len(factories))
+	configuredResources := map[string]bool{}
 
 	for i, factory := range factories {
 		resources[i] = factory.Name()
+		configuredResources[factory.Name()] = true
 	}
 
 	switch {
@@ -192,6 +276,33 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		klog.InfoS("Used resources", "resources", resources)
 	}
 
+	staticResources := append([]string{}, resources...)
+	var discoverer *customresourcestate.Discoverer
+	var discovered []customresourcestate.Resource
+	var restConfig *rest.Config
+	if opts.CustomResourceAutodiscovery {
+		restConfig, err = newRestConfig(opts.Apiserver, opts.Kubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %v", err)
+		}
+		dynamicClient, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %v", err)
+		}
+		discoverer = customresourcestate.NewDiscoverer(dynamicClient, opts.CustomResourceAutodiscoverySelector)
+		discovered, err = discoverer.Discover(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to discover custom resources: %v", err)
+		}
+		discoveredFactories := discoveredCustomResourceFactories(discovered, configuredResources)
+		for _, f := range discoveredFactories {
+			resources = append(resources, f.Name())
+		}
+		factories = append(factories, discoveredFactories...)
+		storeBuilder.WithCustomResourceStoreFactories(discoveredFactories...)
+		klog.InfoS("Discovered custom resources", "resources", resources[len(staticResources):])
+	}
+
 	if err := storeBuilder.WithEnabledResources(resources); err != nil {
 		return fmt.Errorf("failed to set up resources: %v", err)
 	}
@@ -205,34 +316,33 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 	}
 	storeBuilder.WithNamespaces(namespaces)
 	storeBuilder.WithFieldSelectorFilter(merged)
-
-	allowDenyList, err := allowdenylist.New(opts.MetricAllowlist, opts.MetricDenylist)
-	if err != nil {
+	if err := applyStoreOptions(storeBuilder, opts); err != nil {
 		return err
 	}
 
-	err = allowDenyList.Parse()
-	if err != nil {
-		return fmt.Errorf("error initializing the allowdeny list: %v", err)
-	}
-
-	klog.InfoS("Metric allow-denylisting", "allowDenyStatus", allowDenyList.Status())
-
-	optInMetricFamilyFilter, err := optin.NewMetricFamilyFilter(opts.MetricOptInList)
-	if err != nil {
-		return fmt.Errorf("error initializing the opt-in metric list: %v", err)
-	}
-
-	if optInMetricFamilyFilter.Count() > 0 {
-		klog.InfoS("Metrics which were opted into", "optInMetricsFamilyStatus", optInMetricFamilyFilter.Status())
-	}
-
-	storeBuilder.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter(
-		allowDenyList,
-		optInMetricFamilyFilter,
-	))
-
 	storeBuilder.WithUsingAPIServerCache(opts.UseAPIServerCache)
+	storeBuilder.WithUsingWatchList(opts.UseWatchList || featureGate.Enabled(features.WatchList))
+	storeBuilder.WithListPageSize(opts.ListPageSize)
+	// The OTLP exporter collects the metric families of the stores instead
+	// of parsing their text exposition format.
+	storeBuilder.WithKeepFamilies(opts.OTLPEndpoint != "")
+	storeBuilder.WithStartupLimits(opts.StartupConcurrency, opts.StartupJitter)
+	storeBuilder.WithWatchBackoff(watch.Backoff{
+		Initial: opts.WatchBackoffInitial,
+		Max:     opts.WatchBackoffMax,
+		Reset:   opts.WatchBackoffReset,
+	})
+	storeBuilder.WithStaleObjectGC(opts.StaleObjectGCInterval)
+	storeBuilder.WithUTF8LabelNames(opts.UTF8LabelNamesEnabled())
+	storeBuilder.WithMaxLabelValueLength(opts.MaxLabelValueLength)
+	promauto.With(ksmMetricsRegistry).NewCounterFunc(prometheus.CounterOpts{
+		Name: "kube_state_metrics_label_values_truncated_total",
+		Help: "Number of label values truncated as they exceeded --max-label-value-length when metrics were generated.",
+	}, func() float64 {
+		return float64(metric.TruncatedLabelValues())
+	})
+	storeBuilder.WithPodStatusReasons(opts.PodStatusReasons)
+	storeBuilder.WithPodWorkloadResolution(opts.PodWorkloadResolutionEnabled())
 	storeBuilder.WithGenerateStoresFunc(storeBuilder.DefaultGenerateStoresFunc())
 	storeBuilder.WithGenerateCustomResourceStoresFunc(storeBuilder.DefaultGenerateCustomResourceStoresFunc())
 
@@ -244,12 +354,37 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 	}
 	storeBuilder.WithKubeClient(kubeClient)
 	storeBuilder.WithVPAClient(vpaClient)
+	metadataClient, err := createMetadataClient(opts.Apiserver, opts.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	storeBuilder.WithMetadataClient(metadataClient)
+	dynamicClient, err := createDynamicClient(opts.Apiserver, opts.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	storeBuilder.WithDynamicClient(dynamicClient)
+	if len(opts.ObjectCountResources) > 0 {
+		objectCountResources, err := discoverObjectCountResources(kubeClient.Discovery(), opts.ObjectCountResources)
+		if err != nil {
+			return fmt.Errorf("failed to set up object count resources: %v", err)
+		}
+		storeBuilder.WithObjectCountResources(objectCountResources)
+	}
+	var nsWatcher *namespaceWatcher
+	var selectedNamespaces options.NamespaceList
+	if opts.NamespacesSelector != "" {
+		nsWatcher = newNamespaceWatcher(kubeClient, opts.NamespacesSelector, opts.NamespacesDenylist)
+		selectedNamespaces, err = nsWatcher.namespaces(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to resolve namespaces: %v", err)
+		}
+		klog.InfoS("Using namespaces matching the selector", "selector", opts.NamespacesSelector, "namespaces", selectedNamespaces)
+		storeBuilder.WithNamespaces(selectedNamespaces)
+	}
 	storeBuilder.WithCustomResourceClients(customResourceClients)
 	storeBuilder.WithSharding(opts.Shard, opts.TotalShards)
-	storeBuilder.WithAllowAnnotations(opts.AnnotationsAllowList)
-	if err := storeBuilder.WithAllowLabels(opts.LabelsAllowList); err != nil {
-		return fmt.Errorf("failed to set up labels allowlist: %v", err)
-	}
+	storeBuilder.WithShardingStrategy(opts.ShardingStrategy)
 
 	ksmMetricsRegistry.MustRegister(
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
@@ -264,6 +399,63 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		storeBuilder,
 		opts.EnableGZIPEncoding,
 	)
+	ksmMetricsRegistry.MustRegister(metricshandler.NewShardingStatsCollector(m))
+	for _, s := range configStatuses {
+		m.AddConfigStatus(s)
+	}
+
+	if opts.RelabelConfigFile != "" {
+		f, err := os.Open(filepath.Clean(opts.RelabelConfigFile))
+		if err != nil {
+			return fmt.Errorf("failed to open relabel config file: %v", err)
+		}
+		relabeler, err := relabel.LoadConfig(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to load relabel config file: %v", err)
+		}
+		m.SetRelabeler(relabeler)
+	}
+
+	if opts.RecordingRulesConfigFile != "" {
+		f, err := os.Open(filepath.Clean(opts.RecordingRulesConfigFile))
+		if err != nil {
+			return fmt.Errorf("failed to open recording rules config file: %v", err)
+		}
+		rules, err := recording.LoadConfig(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to load recording rules config file: %v", err)
+		}
+		m.SetRecordingRules(rules)
+	}
+
+	if opts.SnapshotFile != "" {
+		if err := m.LoadSnapshot(opts.SnapshotFile, opts.SnapshotMaxAge); err != nil {
+			klog.ErrorS(err, "Failed to load metrics snapshot", "path", opts.SnapshotFile)
+		}
+		// The snapshot is written once the group is interrupted, which
+		// requires to handle termination signals.
+		g.Add(run.SignalHandler(ctx, os.Interrupt, syscall.SIGTERM))
+	}
+
+	if opts.LeaderElect {
+		leader := promauto.With(ksmMetricsRegistry).NewGauge(prometheus.GaugeOpts{
+			Name: "kube_state_metrics_leader",
+			Help: "Whether this instance is the elected leader exposing metrics.",
+		})
+		le, err := newLeaderElector(kubeClient, opts, m, leader)
+		if err != nil {
+			return fmt.Errorf("failed to set up leader election: %v", err)
+		}
+		m.SetStandby(true)
+		ctxLeaderElection, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return runLeaderElection(ctxLeaderElection, le)
+		}, func(error) {
+			cancel()
+		})
+	}
 	// Run MetricsHandler
 	{
 		ctxMetricsHandler, cancel := context.WithCancel(ctx)
@@ -274,27 +466,183 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		})
 	}
 
+	if resourceMapper != nil {
+		ctxMapper, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return resourceMapper.Run(ctxMapper)
+		}, func(error) {
+			cancel()
+		})
+	}
+
+	if discoverer != nil {
+		staticClients := map[string]interface{}{}
+		for name, c := range customResourceClients {
+			if configuredResources[name] {
+				staticClients[name] = c
+			}
+		}
+		onChange := func(resources []customresourcestate.Resource) error {
+			fs := discoveredCustomResourceFactories(resources, configuredResources)
+			clients := make(map[string]interface{}, len(staticClients)+len(fs))
+			for name, c := range staticClients {
+				clients[name] = c
+			}
+			enabled := append([]string{}, staticResources...)
+			for _, f := range fs {
+				c, err := f.CreateClient(restConfig)
+				if err != nil {
+					return err
+				}
+				clients[f.Name()] = c
+				enabled = append(enabled, f.Name())
+			}
+			return m.Reconfigure(func(b ksmtypes.BuilderInterface) error {
+				b.WithCustomResourceStoreFactories(fs...)
+				b.WithCustomResourceClients(clients)
+				return b.WithEnabledResources(enabled)
+			})
+		}
+		ctxDiscoverer, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return discoverer.Run(ctxDiscoverer, discovered, onChange)
+		}, func(error) {
+			cancel()
+		})
+	}
+
+	if nsWatcher != nil {
+		onChange := func(namespaces options.NamespaceList) error {
+			return m.Reconfigure(func(b ksmtypes.BuilderInterface) error {
+				b.WithNamespaces(namespaces)
+				return nil
+			})
+		}
+		ctxNamespaces, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return nsWatcher.run(ctxNamespaces, selectedNamespaces, onChange)
+		}, func(error) {
+			cancel()
+		})
+	}
+
+	if got != "" {
+		file := filepath.Clean(got)
+		reloader := &configReloader{
+			path:        file,
+			base:        baseOpts,
+			current:     opts,
+			data:        configFile,
+			reconfigure: m.Reconfigure,
+			onReload: func(data []byte, err error) {
+				if err != nil {
+					klog.ErrorS(err, "Failed to reload options config file", "file", file)
+					configSuccess.WithLabelValues("config", file).Set(0)
+					return
+				}
+				klog.InfoS("Applied options config file", "file", file)
+				configSuccess.WithLabelValues("config", file).Set(1)
+				configSuccessTime.WithLabelValues("config", file).SetToCurrentTime()
+				configHash.WithLabelValues("config", file).Set(md5HashAsMetricValue(data))
+			},
+		}
+		ctxReloader, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return reloader.Run(ctxReloader)
+		}, func(error) {
+			cancel()
+		})
+	}
+
+	if opts.OTLPEndpoint != "" {
+		exporter, err := otlp.NewExporter(ctx, otlp.Options{
+			Endpoint:           opts.OTLPEndpoint,
+			Protocol:           opts.OTLPProtocol,
+			Compression:        opts.OTLPCompression,
+			Interval:           opts.OTLPInterval,
+			Timeout:            opts.OTLPTimeout,
+			BatchSize:          opts.OTLPBatchSize,
+			ResourceAttributes: opts.ExternalLabels,
+		}, m, ksmMetricsRegistry)
+		if err != nil {
+			return fmt.Errorf("failed to set up OTLP exporter: %v", err)
+		}
+		ctxExporter, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return exporter.Run(ctxExporter)
+		}, func(error) {
+			cancel()
+		})
+	}
+
+	if opts.TextfilePath != "" {
+		exporter, err := textfile.NewExporter(opts.TextfilePath, opts.TextfileInterval, m, ksmMetricsRegistry)
+		if err != nil {
+			return fmt.Errorf("failed to set up textfile exporter: %v", err)
+		}
+		ctxExporter, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return exporter.Run(ctxExporter)
+		}, func(error) {
+			cancel()
+		})
+	}
+
+	if opts.OTLPTracesEndpoint != "" {
+		exporter, err := otlp.NewTraceExporter(ctx, opts.OTLPTracesEndpoint, opts.OTLPTracesSampleRatio, ksmMetricsRegistry)
+		if err != nil {
+			return fmt.Errorf("failed to set up OTLP trace exporter: %v", err)
+		}
+		m.SetTracer(exporter.Tracer())
+		ctxExporter, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return exporter.Run(ctxExporter)
+		}, func(error) {
+			cancel()
+		})
+	}
+
 	tlsConfig := opts.TLSConfig
+	serverTLSConfig, err := newServerTLSConfig(opts)
+	if err != nil {
+		return err
+	}
+	metricsTLSConfig, err := newMetricsTLSConfig(opts, serverTLSConfig)
+	if err != nil {
+		return err
+	}
 
 	telemetryMux := buildTelemetryServer(ksmMetricsRegistry)
-	telemetryListenAddress := net.JoinHostPort(opts.TelemetryHost, strconv.Itoa(opts.TelemetryPort))
-	telemetryServer := http.Server{
-		Handler:           telemetryMux,
-		ReadHeaderTimeout: 5 * time.Second}
+	telemetryListenAddresses := listenAddresses(opts.TelemetryListenAddresses, opts.TelemetryHost, opts.TelemetryPort)
+	telemetryServer := newHTTPServer(telemetryMux, opts)
 	telemetryFlags := web.FlagConfig{
-		WebListenAddresses: &[]string{telemetryListenAddress},
+		WebListenAddresses: &telemetryListenAddresses,
 		WebSystemdSocket:   new(bool),
 		WebConfigFile:      &tlsConfig,
 	}
 
-	metricsMux := buildMetricsServer(m, durationVec)
-	metricsServerListenAddress := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
-	metricsServer := http.Server{
-		Handler:           metricsMux,
-		ReadHeaderTimeout: 5 * time.Second}
+	scrapesRejected := promauto.With(ksmMetricsRegistry).NewCounter(prometheus.CounterOpts{
+		Name: "kube_state_metrics_scrapes_rejected_total",
+		Help: "Number of scrapes rejected with 503 Service Unavailable as --max-concurrent-scrapes were already served.",
+	})
+	limiter := newScrapeLimiter(opts.MaxConcurrentScrapes, scrapesRejected)
+	var auth *delegatingAuth
+	if opts.AuthDelegation {
+		auth, err = newDelegatingAuth(kubeClient, opts.AuthDelegationResource, opts.AuthDelegationNamespace)
+		if err != nil {
+			return fmt.Errorf("failed to set up --auth-delegation: %w", err)
+		}
+		auth.namespacesPath = namespacesPath
+	}
+	var metricsMux http.Handler = buildMetricsServer(m, limiter, durationVec, sizeVec)
+	if auth != nil {
+		metricsMux = auth.wrap(metricsMux, healthzPath, livezPath, readyzPath)
+	}
+	metricsServerListenAddresses := listenAddresses(opts.ListenAddresses, opts.Host, opts.Port)
+	metricsServer := newHTTPServer(metricsMux, opts)
 
 	metricsFlags := web.FlagConfig{
-		WebListenAddresses: &[]string{metricsServerListenAddress},
+		WebListenAddresses: &metricsServerListenAddresses,
 		WebSystemdSocket:   new(bool),
 		WebConfigFile:      &tlsConfig,
 	}
@@ -302,8 +650,8 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 	// Run Telemetry server
 	{
 		g.Add(func() error {
-			klog.InfoS("Started kube-state-metrics self metrics server", "telemetryAddress", telemetryListenAddress)
-			return web.ListenAndServe(&telemetryServer, &telemetryFlags, promLogger)
+			klog.InfoS("Started kube-state-metrics self metrics server", "telemetryAddresses", telemetryListenAddresses)
+			return listenAndServe(telemetryServer, &telemetryFlags, serverTLSConfig, promLogger)
 		}, func(error) {
 			ctxShutDown, cancel := context.WithTimeout(ctx, 3*time.Second)
 			defer cancel()
@@ -311,33 +659,179 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		})
 	}
 	// Run Metrics server
-	{
+	switch {
+	case opts.OTLPOnly:
+		klog.InfoS("Metrics server disabled, metrics are only exported via OTLP")
+	case opts.TextfileOnly:
+		klog.InfoS("Metrics server disabled, metrics are only written to the textfile", "path", opts.TextfilePath)
+	default:
 		g.Add(func() error {
-			klog.InfoS("Started metrics server", "metricsServerAddress", metricsServerListenAddress)
-			return web.ListenAndServe(&metricsServer, &metricsFlags, promLogger)
+			klog.InfoS("Started metrics server", "metricsServerAddresses", metricsServerListenAddresses)
+			return listenAndServe(metricsServer, &metricsFlags, metricsTLSConfig, promLogger)
 		}, func(error) {
 			ctxShutDown, cancel := context.WithTimeout(ctx, 3*time.Second)
 			defer cancel()
 			metricsServer.Shutdown(ctxShutDown)
 		})
+		for address, resources := range opts.ResourceListeners {
+			address := address
+			var mux http.Handler = buildResourceMetricsServer(m.ResourcesHandler(resources), limiter, durationVec, sizeVec)
+			if auth != nil {
+				mux = auth.wrap(mux, healthzPath)
+			}
+			server := newHTTPServer(mux, opts)
+			flags := web.FlagConfig{
+				WebListenAddresses: &[]string{address},
+				WebSystemdSocket:   new(bool),
+				WebConfigFile:      &tlsConfig,
+			}
+			g.Add(func() error {
+				klog.InfoS("Started resource metrics server", "address", address, "resources", resources)
+				return listenAndServe(server, &flags, metricsTLSConfig, promLogger)
+			}, func(error) {
+				ctxShutDown, cancel := context.WithTimeout(ctx, 3*time.Second)
+				defer cancel()
+				server.Shutdown(ctxShutDown)
+			})
+		}
 	}
 
-	if err := g.Run(); err != nil {
-		return fmt.Errorf("run server group error: %v", err)
+	err = g.Run()
+	if opts.SnapshotFile != "" {
+		if err := m.WriteSnapshot(opts.SnapshotFile); err != nil {
+			klog.ErrorS(err, "Failed to write metrics snapshot", "path", opts.SnapshotFile)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("run server group error: %w", err)
 	}
 	klog.InfoS("Exited")
 	return nil
 }
 
-func createKubeClient(apiserver string, kubeconfig string, factories ...customresource.RegistryFactory) (clientset.Interface, vpaclientset.Interface, map[string]interface{}, error) {
+// listenAddresses returns the given addresses to listen on, or the address of
+// the given host and port if none are given.
+func listenAddresses(addresses []string, host string, port int) []string {
+	if len(addresses) > 0 {
+		return addresses
+	}
+	return []string{net.JoinHostPort(host, strconv.Itoa(port))}
+}
+
+// discoveredCustomResourceFactories creates the factories of discovered
+// custom resources which are not configured explicitly.
+func discoveredCustomResourceFactories(resources []customresourcestate.Resource, configured map[string]bool) []customresource.RegistryFactory {
+	var factories []customresource.RegistryFactory
+	for _, r := range resources {
+		if configured[r.GetResourceName()] {
+			continue
+		}
+		f, err := customresourcestate.NewCustomResourceMetrics(r)
+		if err != nil {
+			klog.ErrorS(err, "Failed to create metrics factory for discovered custom resource", "resource", r.GetResourceName())
+			continue
+		}
+		factories = append(factories, f)
+	}
+	return factories
+}
+
+// loadSubcommandOptions applies the options config file to the given options
+// and returns them with the factories of the configured custom resources and
+// profiles, for subcommands which inspect the configuration of
+// kube-state-metrics.
+func loadSubcommandOptions(opts *options.Options) (*options.Options, []customresource.RegistryFactory, error) {
+	if file := options.GetConfigFile(*opts); file != "" {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read opts config file: %v", err)
+		}
+		loaded, err := loadConfigFile(opts, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = loaded
+	}
+
+	config, err := resolveCustomResourceConfig(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var factories []customresource.RegistryFactory
+	if config != nil {
+		factories, err = customresourcestate.FromConfig(config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Parsing from Custom Resource State Metrics file failed: %v", err)
+		}
+	}
+	for _, profile := range opts.EnabledCustomResourceProfiles() {
+		profileFactories, err := profileCustomResourceFactories(profile, factories)
+		if err != nil {
+			return nil, nil, err
+		}
+		factories = append(factories, profileFactories...)
+	}
+	return opts, factories, nil
+}
+
+// profileCustomResourceFactories creates the factories of the resources of a
+// Custom Resource State Metrics profile which are not configured explicitly
+// or by a previous profile.
+func profileCustomResourceFactories(name string, configured []customresource.RegistryFactory) ([]customresource.RegistryFactory, error) {
+	profileFactories, err := customresourcestate.FromProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	configuredResources := map[string]bool{}
+	for _, f := range configured {
+		configuredResources[f.Name()] = true
+	}
+	var factories []customresource.RegistryFactory
+	for _, f := range profileFactories {
+		if configuredResources[f.Name()] {
+			klog.InfoS("Custom resource of profile is already configured, skipping it", "profile", name, "resource", f.Name())
+			continue
+		}
+		factories = append(factories, f)
+	}
+	return factories, nil
+}
+
+func newRestConfig(apiserver string, kubeconfig string) (*rest.Config, error) {
 	config, err := clientcmd.BuildConfigFromFlags(apiserver, kubeconfig)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
 
 	config.UserAgent = fmt.Sprintf("%s/%s (%s/%s) kubernetes/%s", "kube-state-metrics", version.Version, runtime.GOOS, runtime.GOARCH, version.Revision)
 	config.AcceptContentTypes = "application/vnd.kubernetes.protobuf,application/json"
 	config.ContentType = "application/vnd.kubernetes.protobuf"
+	// Only affects the requests of watch list ListerWatchers, see --use-watch-list.
+	config.Wrap(watch.WatchListTransport)
+	return config, nil
+}
+
+func createMetadataClient(apiserver string, kubeconfig string) (metadata.Interface, error) {
+	config, err := newRestConfig(apiserver, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.NewForConfig(config)
+}
+
+func createDynamicClient(apiserver string, kubeconfig string) (dynamic.Interface, error) {
+	config, err := newRestConfig(apiserver, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(config)
+}
+
+func createKubeClient(apiserver string, kubeconfig string, factories ...customresource.RegistryFactory) (clientset.Interface, vpaclientset.Interface, map[string]interface{}, error) {
+	config, err := newRestConfig(apiserver, kubeconfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	kubeClient, err := clientset.NewForConfig(config)
 	if err != nil {
@@ -392,7 +886,7 @@ func buildTelemetryServer(registry prometheus.Gatherer) *http.ServeMux {
 	return mux
 }
 
-func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prometheus.ObserverVec) *http.ServeMux {
+func buildMetricsServer(m *metricshandler.MetricsHandler, limiter *scrapeLimiter, durationObserver, sizeObserver prometheus.ObserverVec) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// TODO: This doesn't belong into serveMetrics
@@ -402,13 +896,16 @@ func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prome
 	mux.Handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
 	mux.Handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
 
-	mux.Handle(metricsPath, promhttp.InstrumentHandlerDuration(durationObserver, m))
+	mux.Handle("/debug/sharding", m.ShardingDebugHandler())
 
-	// Add healthzPath
-	mux.HandleFunc(healthzPath, func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(http.StatusText(http.StatusOK)))
-	})
+	mux.Handle(metricsPath, promhttp.InstrumentHandlerDuration(durationObserver, promhttp.InstrumentHandlerResponseSize(sizeObserver, limiter.wrap(m))))
+	mux.Handle(namespacesPath, promhttp.InstrumentHandlerDuration(durationObserver, promhttp.InstrumentHandlerResponseSize(sizeObserver, limiter.wrap(m.NamespaceHandler(namespacesPath)))))
+	mux.Handle(statePath, limiter.wrap(m.StateHandler()))
+
+	// Add health endpoints
+	mux.Handle(healthzPath, m.LivenessHandler())
+	mux.Handle(livezPath, m.LivenessHandler())
+	mux.Handle(readyzPath, m.ReadinessHandler())
 	// Add index
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`<html>
@@ -417,7 +914,10 @@ func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prome
              <h1>Kube Metrics</h1>
 			 <ul>
              <li><a href='` + metricsPath + `'>metrics</a></li>
+             <li><a href='` + statePath + `'>state</a></li>
              <li><a href='` + healthzPath + `'>healthz</a></li>
+             <li><a href='` + livezPath + `'>livez</a></li>
+             <li><a href='` + readyzPath + `'>readyz</a></li>
 			 </ul>
              </body>
              </html>`))
@@ -425,6 +925,18 @@ func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prome
 	return mux
 }
 
+// buildResourceMetricsServer builds the mux of an additional listener exposing
+// the metrics of some resources via the given handler.
+func buildResourceMetricsServer(handler http.Handler, limiter *scrapeLimiter, durationObserver, sizeObserver prometheus.ObserverVec) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, promhttp.InstrumentHandlerDuration(durationObserver, promhttp.InstrumentHandlerResponseSize(sizeObserver, limiter.wrap(handler))))
+	mux.HandleFunc(healthzPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(http.StatusText(http.StatusOK)))
+	})
+	return mux
+}
+
 // md5HashAsMetricValue creates an md5 hash and returns the most significant bytes that fit into a float64
 // Taken from https://github.com/prometheus/alertmanager/blob/6ef6e6868dbeb7984d2d577dd4bf75c65bf1904f/config/coordinator.go#L149
 func md5HashAsMetricValue(data []byte) float64 {
@@ -31,6 +31,8 @@ import (
 	"k8s.io/kube-state-metrics/v2/pkg/optin"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -205,9 +207,15 @@ func TestFullScrapeCycle(t *testing.T) {
 # HELP kube_pod_container_resource_limits The number of requested limit resource by a container. It is recommended to use the kube_pod_resource_limits metric exposed by kube-scheduler instead, as it is more precise.
 # HELP kube_pod_container_resource_requests The number of requested request resource by a container. It is recommended to use the kube_pod_resource_requests metric exposed by kube-scheduler instead, as it is more precise.
 # HELP kube_pod_container_state_started [STABLE] Start time in unix timestamp for a pod container.
+# HELP kube_pod_container_status_allocated_resources The resources allocated to a container by the node, which differ from its requests while an in-place resize is pending.
 # HELP kube_pod_container_status_last_terminated_exitcode Describes the exit code for the last container in terminated state.
+# HELP kube_pod_container_status_last_terminated_finished Finish time in unix timestamp of the last terminated state of the container.
+# HELP kube_pod_container_status_last_terminated_info Information about the last terminated state of the container.
 # HELP kube_pod_container_status_last_terminated_reason Describes the last reason the container was in terminated state.
+# HELP kube_pod_container_status_last_terminated_started Start time in unix timestamp of the last terminated state of the container.
 # HELP kube_pod_container_status_ready [STABLE] Describes whether the containers readiness check succeeded.
+# HELP kube_pod_container_status_resource_limits The resource limits actually applied to a running container, which differ from its limits while an in-place resize is in progress.
+# HELP kube_pod_container_status_resource_requests The resource requests actually applied to a running container, which differ from its requests while an in-place resize is in progress.
 # HELP kube_pod_container_status_restarts_total [STABLE] The number of container restarts per container.
 # HELP kube_pod_container_status_running [STABLE] Describes whether the container is currently in running state.
 # HELP kube_pod_container_status_terminated [STABLE] Describes whether the container is currently in terminated state.
@@ -216,6 +224,15 @@ func TestFullScrapeCycle(t *testing.T) {
 # HELP kube_pod_container_status_waiting_reason [STABLE] Describes the reason the container is currently in waiting state.
 # HELP kube_pod_created [STABLE] Unix creation timestamp
 # HELP kube_pod_deletion_timestamp Unix deletion timestamp
+# HELP kube_pod_effective_resource_requests The resources requested by a pod, including its sidecar containers, as computed by the scheduler without the pod overhead.
+# HELP kube_pod_ephemeral_container_info Information about an ephemeral container in a pod.
+# HELP kube_pod_ephemeral_container_state_started Start time in unix timestamp for an ephemeral container.
+# HELP kube_pod_ephemeral_container_status_restarts_total The number of restarts for the ephemeral container.
+# HELP kube_pod_ephemeral_container_status_running Describes whether the ephemeral container is currently in running state.
+# HELP kube_pod_ephemeral_container_status_terminated Describes whether the ephemeral container is currently in terminated state.
+# HELP kube_pod_ephemeral_container_status_terminated_reason Describes the reason the ephemeral container is currently in terminated state.
+# HELP kube_pod_ephemeral_container_status_waiting Describes whether the ephemeral container is currently in waiting state.
+# HELP kube_pod_ephemeral_container_status_waiting_reason Describes the reason the ephemeral container is currently in waiting state.
 # HELP kube_pod_info [STABLE] Information about pod.
 # HELP kube_pod_init_container_info [STABLE] Information about an init container in a pod.
 # HELP kube_pod_init_container_resource_limits The number of requested limit resource by an init container.
@@ -233,8 +250,11 @@ func TestFullScrapeCycle(t *testing.T) {
 # HELP kube_pod_overhead_cpu_cores The pod overhead in regards to cpu cores associated with running a pod.
 # HELP kube_pod_overhead_memory_bytes The pod overhead in regards to memory associated with running a pod.
 # HELP kube_pod_runtimeclass_name_info The runtimeclass associated with the pod.
+# HELP kube_pod_spec_os_info The operating system the pod is targeted at.
+# HELP kube_pod_container_windows_options_info The Windows specific security options of a container, including the ones inherited from the pod.
 # HELP kube_pod_owner [STABLE] Information about the Pod's owner.
 # HELP kube_pod_restart_policy [STABLE] Describes the restart policy in use by this pod.
+# HELP kube_pod_spec_preemption_policy The pods preemption policy.
 # HELP kube_pod_spec_volumes_persistentvolumeclaims_info [STABLE] Information about persistentvolumeclaim volumes in a pod.
 # HELP kube_pod_spec_volumes_persistentvolumeclaims_readonly [STABLE] Describes whether a persistentvolumeclaim is mounted read only.
 # HELP kube_pod_start_time [STABLE] Start time in unix timestamp for a pod.
@@ -244,6 +264,8 @@ func TestFullScrapeCycle(t *testing.T) {
 # HELP kube_pod_status_ready_time Readiness achieved time in unix timestamp for a pod.
 # HELP kube_pod_status_ready [STABLE] Describes whether the pod is ready to serve requests.
 # HELP kube_pod_status_reason The pod status reasons
+# HELP kube_pod_status_resize Describes whether an in-place resize of the pod is pending or in progress.
+# HELP kube_pod_status_resource_claim_info Information about the ResourceClaims generated for the resource claims of a pod.
 # HELP kube_pod_status_scheduled [STABLE] Describes the status of the scheduling process for the pod.
 # HELP kube_pod_status_scheduled_time [STABLE] Unix timestamp when pod moved into scheduled status
 # HELP kube_pod_status_unschedulable [STABLE] Describes the unschedulable status for the pod.
@@ -254,9 +276,15 @@ func TestFullScrapeCycle(t *testing.T) {
 # TYPE kube_pod_container_resource_limits gauge
 # TYPE kube_pod_container_resource_requests gauge
 # TYPE kube_pod_container_state_started gauge
+# TYPE kube_pod_container_status_allocated_resources gauge
 # TYPE kube_pod_container_status_last_terminated_exitcode gauge
+# TYPE kube_pod_container_status_last_terminated_finished gauge
+# TYPE kube_pod_container_status_last_terminated_info gauge
 # TYPE kube_pod_container_status_last_terminated_reason gauge
+# TYPE kube_pod_container_status_last_terminated_started gauge
 # TYPE kube_pod_container_status_ready gauge
+# TYPE kube_pod_container_status_resource_limits gauge
+# TYPE kube_pod_container_status_resource_requests gauge
 # TYPE kube_pod_container_status_restarts_total counter
 # TYPE kube_pod_container_status_running gauge
 # TYPE kube_pod_container_status_terminated gauge
@@ -265,6 +293,15 @@ func TestFullScrapeCycle(t *testing.T) {
 # TYPE kube_pod_container_status_waiting_reason gauge
 # TYPE kube_pod_created gauge
 # TYPE kube_pod_deletion_timestamp gauge
+# TYPE kube_pod_effective_resource_requests gauge
+# TYPE kube_pod_ephemeral_container_info gauge
+# TYPE kube_pod_ephemeral_container_state_started gauge
+# TYPE kube_pod_ephemeral_container_status_restarts_total counter
+# TYPE kube_pod_ephemeral_container_status_running gauge
+# TYPE kube_pod_ephemeral_container_status_terminated gauge
+# TYPE kube_pod_ephemeral_container_status_terminated_reason gauge
+# TYPE kube_pod_ephemeral_container_status_waiting gauge
+# TYPE kube_pod_ephemeral_container_status_waiting_reason gauge
 # TYPE kube_pod_info gauge
 # TYPE kube_pod_init_container_info gauge
 # TYPE kube_pod_init_container_resource_limits gauge
@@ -282,8 +319,11 @@ func TestFullScrapeCycle(t *testing.T) {
 # TYPE kube_pod_overhead_cpu_cores gauge
 # TYPE kube_pod_overhead_memory_bytes gauge
 # TYPE kube_pod_runtimeclass_name_info gauge
+# TYPE kube_pod_spec_os_info gauge
+# TYPE kube_pod_container_windows_options_info gauge
 # TYPE kube_pod_owner gauge
 # TYPE kube_pod_restart_policy gauge
+# TYPE kube_pod_spec_preemption_policy gauge
 # TYPE kube_pod_spec_volumes_persistentvolumeclaims_info gauge
 # TYPE kube_pod_spec_volumes_persistentvolumeclaims_readonly gauge
 # TYPE kube_pod_start_time gauge
@@ -293,6 +333,8 @@ func TestFullScrapeCycle(t *testing.T) {
 # TYPE kube_pod_status_ready gauge
 # TYPE kube_pod_status_ready_time gauge
 # TYPE kube_pod_status_reason gauge
+# TYPE kube_pod_status_resize gauge
+# TYPE kube_pod_status_resource_claim_info gauge
 # TYPE kube_pod_status_scheduled gauge
 # TYPE kube_pod_status_scheduled_time gauge
 # TYPE kube_pod_status_unschedulable gauge
@@ -315,6 +357,7 @@ kube_pod_container_resource_requests{namespace="default",pod="pod0",uid="abc-0",
 kube_pod_container_resource_requests{namespace="default",pod="pod0",uid="abc-0",container="pod1_con2",node="node1",resource="cpu",unit="core"} 0.3
 kube_pod_container_resource_requests{namespace="default",pod="pod0",uid="abc-0",container="pod1_con2",node="node1",resource="memory",unit="byte"} 2e+08
 kube_pod_container_status_last_terminated_exitcode{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1"} 137
+kube_pod_container_status_last_terminated_info{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1",reason="OOMKilled",signal="0"} 1
 kube_pod_container_status_last_terminated_reason{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1",reason="OOMKilled"} 1
 kube_pod_container_status_ready{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1"} 0
 kube_pod_container_status_ready{namespace="default",pod="pod0",uid="abc-0",container="pod1_con2"} 0
@@ -328,6 +371,11 @@ kube_pod_container_status_waiting_reason{namespace="default",pod="pod0",uid="abc
 kube_pod_container_status_waiting{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1"} 1
 kube_pod_container_status_waiting{namespace="default",pod="pod0",uid="abc-0",container="pod1_con2"} 0
 kube_pod_created{namespace="default",pod="pod0",uid="abc-0"} 1.5e+09
+kube_pod_effective_resource_requests{namespace="default",pod="pod0",uid="abc-0",node="node1",resource="cpu",unit="core"} 0.5
+kube_pod_effective_resource_requests{namespace="default",pod="pod0",uid="abc-0",node="node1",resource="ephemeral_storage",unit="byte"} 3e+08
+kube_pod_effective_resource_requests{namespace="default",pod="pod0",uid="abc-0",node="node1",resource="memory",unit="byte"} 3e+08
+kube_pod_effective_resource_requests{namespace="default",pod="pod0",uid="abc-0",node="node1",resource="nvidia_com_gpu",unit="integer"} 1
+kube_pod_effective_resource_requests{namespace="default",pod="pod0",uid="abc-0",node="node1",resource="storage",unit="byte"} 4e+08
 kube_pod_info{namespace="default",pod="pod0",uid="abc-0",host_ip="1.1.1.1",pod_ip="1.2.3.4",node="node1",created_by_kind="",created_by_name="",priority_class="",host_network="false"} 1
 kube_pod_labels{namespace="default",pod="pod0",uid="abc-0"} 1
 kube_pod_owner{namespace="default",pod="pod0",uid="abc-0",owner_kind="",owner_name="",owner_is_controller=""} 1
@@ -370,6 +418,36 @@ kube_pod_status_reason{namespace="default",pod="pod0",uid="abc-0",reason="Unexpe
 		}
 	}
 
+	reqProto := httptest.NewRequest("GET", "http://localhost:8080/metrics", nil)
+	reqProto.Header.Set("Accept", "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited")
+
+	wProto := httptest.NewRecorder()
+	handler.ServeHTTP(wProto, reqProto)
+
+	respProto := wProto.Result()
+	if got := respProto.Header.Get("Content-Type"); got != string(expfmt.FmtProtoDelim) {
+		t.Fatalf("expected protobuf content type but got %q", got)
+	}
+
+	families := map[string]*dto.MetricFamily{}
+	decoder := expfmt.NewDecoder(respProto.Body, expfmt.FmtProtoDelim)
+	for {
+		mf := &dto.MetricFamily{}
+		if err := decoder.Decode(mf); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("failed to decode protobuf response: %v", err)
+		}
+		families[mf.GetName()] = mf
+	}
+
+	if mf := families["kube_pod_info"]; mf.GetType() != dto.MetricType_GAUGE || len(mf.GetMetric()) != 1 {
+		t.Fatalf("unexpected kube_pod_info family in protobuf response: %v", mf)
+	}
+	if mf := families["kube_pod_container_status_restarts_total"]; mf.GetType() != dto.MetricType_COUNTER || mf.GetMetric()[0].GetCounter().GetValue() != 0 {
+		t.Fatalf("unexpected kube_pod_container_status_restarts_total family in protobuf response: %v", mf)
+	}
+
 	telemetryMux := buildTelemetryServer(reg)
 
 	req2 := httptest.NewRequest("GET", "http://localhost:8081/metrics", nil)
@@ -399,10 +477,14 @@ kube_state_metrics_total_shards 1
 
 	gotFiltered2 := []string{}
 	for _, l := range gotSplit2 {
-		if strings.Contains(l, "_shard") {
+		// The timestamp of the last rebalance is checked separately.
+		if strings.Contains(l, "_shard") && !strings.Contains(l, "_shard_last_rebalance") {
 			gotFiltered2 = append(gotFiltered2, l)
 		}
 	}
+	if !strings.Contains(string(body2), "\nkube_state_metrics_shard_last_rebalance_timestamp_seconds ") {
+		t.Fatalf("expected kube_state_metrics_shard_last_rebalance_timestamp_seconds to be set, got\n\n%s", body2)
+	}
 
 	sort.Strings(gotFiltered2)
 
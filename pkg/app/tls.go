@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/exporter-toolkit/web"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// certReloader serves a certificate and private key loaded from files. The
+// files are loaded again once their modification time changes, e.g. as the
+// certificate was renewed by cert-manager.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mtx      sync.Mutex
+	cert     *tls.Certificate
+	certTime time.Time
+	keyTime  time.Time
+}
+
+// newCertReloader returns a certReloader serving the given certificate and
+// private key, which have to be loadable.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := r.GetCertificate(nil); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate returns the current certificate. It implements
+// tls.Config.GetCertificate. If reloading the changed files fails, the
+// previous certificate is served.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	certStat, certErr := os.Stat(r.certFile)
+	keyStat, keyErr := os.Stat(r.keyFile)
+	if certErr == nil && keyErr == nil && certStat.ModTime().Equal(r.certTime) && keyStat.ModTime().Equal(r.keyTime) {
+		return r.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			klog.ErrorS(err, "Failed to reload TLS certificate, serving the previous certificate", "certFile", r.certFile, "keyFile", r.keyFile)
+			return r.cert, nil
+		}
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	if r.cert != nil {
+		klog.InfoS("Reloaded TLS certificate", "certFile", r.certFile, "keyFile", r.keyFile)
+	}
+	r.cert = &cert
+	if certErr == nil && keyErr == nil {
+		r.certTime = certStat.ModTime()
+		r.keyTime = keyStat.ModTime()
+	}
+	return r.cert, nil
+}
+
+// newServerTLSConfig returns the TLS configuration of the metrics and
+// telemetry servers if native TLS is configured, nil otherwise.
+func newServerTLSConfig(opts *options.Options) (*tls.Config, error) {
+	if opts.TLSCertFile == "" {
+		return nil, nil
+	}
+	reloader, err := newCertReloader(opts.TLSCertFile, opts.TLSPrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}, nil
+}
+
+// newMetricsTLSConfig returns the TLS configuration of the metrics server,
+// which additionally requires clients to present a certificate signed by the
+// client CA, if one is configured. If allowed names are configured, the common
+// name or one of the DNS, email or URI SANs of the client certificate has to
+// be one of them.
+func newMetricsTLSConfig(opts *options.Options, serverConfig *tls.Config) (*tls.Config, error) {
+	if serverConfig == nil || opts.TLSClientCAFile == "" {
+		return serverConfig, nil
+	}
+	caPEM, err := os.ReadFile(opts.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", opts.TLSClientCAFile)
+	}
+
+	config := serverConfig.Clone()
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	config.ClientCAs = pool
+	if len(opts.TLSClientAllowedNames) > 0 {
+		allowed := make(map[string]struct{}, len(opts.TLSClientAllowedNames))
+		for _, name := range opts.TLSClientAllowedNames {
+			allowed[name] = struct{}{}
+		}
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			return verifyClientName(cs.PeerCertificates, allowed)
+		}
+	}
+	return config, nil
+}
+
+// verifyClientName returns an error unless the common name or one of the SANs
+// of the verified client certificate is allowed.
+func verifyClientName(certs []*x509.Certificate, allowed map[string]struct{}) error {
+	if len(certs) == 0 {
+		return fmt.Errorf("no client certificate presented")
+	}
+	leaf := certs[0]
+	names := append([]string{leaf.Subject.CommonName}, leaf.DNSNames...)
+	names = append(names, leaf.EmailAddresses...)
+	for _, uri := range leaf.URIs {
+		names = append(names, uri.String())
+	}
+	for _, name := range names {
+		if _, ok := allowed[name]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("client certificate %q is not allowed", leaf.Subject.CommonName)
+}
+
+// listenAndServe serves the server on the addresses of flags. If tlsConfig is
+// not nil, HTTPS is served with it, otherwise the exporter-toolkit web
+// configuration of flags is used.
+func listenAndServe(server *http.Server, flags *web.FlagConfig, tlsConfig *tls.Config, logger promLogger) error {
+	if tlsConfig == nil {
+		return web.ListenAndServe(server, flags, logger)
+	}
+	server.TLSConfig = tlsConfig
+	listeners := make([]net.Listener, 0, len(*flags.WebListenAddresses))
+	for _, address := range *flags.WebListenAddresses {
+		l, err := net.Listen("tcp", address)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+		listeners = append(listeners, l)
+	}
+	errs := make(chan error, len(listeners))
+	for _, l := range listeners {
+		l := l
+		go func() {
+			errs <- server.ServeTLS(l, "", "")
+		}()
+	}
+	return <-errs
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/exporter-toolkit/web"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// testCert is a certificate and private key generated by newTestCert.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert returns a certificate with the given common name signed by
+// parent, or a self-signed CA certificate if parent is nil.
+func newTestCert(t *testing.T, commonName string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{commonName},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// writeTestCert writes a self-signed certificate with the given common name
+// and its private key to the given files.
+func writeTestCert(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	c := newTestCert(t, commonName, nil)
+	if err := os.WriteFile(certFile, c.certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, c.keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func commonName(t *testing.T, r *certReloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	if _, err := newCertReloader(certFile, keyFile); err == nil {
+		t.Fatal("expected error for missing certificate")
+	}
+
+	writeTestCert(t, certFile, keyFile, "first")
+	r, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := commonName(t, r); got != "first" {
+		t.Errorf("expected certificate first, got %s", got)
+	}
+
+	writeTestCert(t, certFile, keyFile, "second")
+	// Ensure the modification time changes on file systems with a coarse
+	// resolution.
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := commonName(t, r); got != "second" {
+		t.Errorf("expected reloaded certificate second, got %s", got)
+	}
+
+	// An invalid certificate is not served.
+	if err := os.WriteFile(certFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(certFile, future.Add(time.Minute), future.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if got := commonName(t, r); got != "second" {
+		t.Errorf("expected previous certificate second, got %s", got)
+	}
+}
+
+func TestMetricsTLSConfigClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	opts := options.NewOptions()
+	opts.TLSCertFile = filepath.Join(dir, "tls.crt")
+	opts.TLSPrivateKeyFile = filepath.Join(dir, "tls.key")
+	opts.TLSClientCAFile = filepath.Join(dir, "ca.crt")
+	opts.TLSClientAllowedNames = []string{"prometheus"}
+	writeTestCert(t, opts.TLSCertFile, opts.TLSPrivateKeyFile, "127.0.0.1")
+
+	ca := newTestCert(t, "ca", nil)
+	if err := os.WriteFile(opts.TLSClientCAFile, ca.certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	serverConfig, err := newServerTLSConfig(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metricsConfig, err := newMetricsTLSConfig(opts, serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = metricsConfig
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		client  *testCert
+		wantErr bool
+	}{
+		{name: "allowed client", client: newTestCert(t, "prometheus", ca)},
+		{name: "client name not allowed", client: newTestCert(t, "other", ca), wantErr: true},
+		{name: "client of other CA", client: newTestCert(t, "prometheus", newTestCert(t, "other-ca", nil)), wantErr: true},
+		{name: "no client certificate", wantErr: true},
+	}
+	for _, test := range tests {
+		clientConfig := &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+		if test.client != nil {
+			cert, err := tls.X509KeyPair(test.client.certPEM, test.client.keyPEM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clientConfig.Certificates = []tls.Certificate{cert}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("%s: expected error %t, got %v", test.name, test.wantErr, err)
+		}
+	}
+}
+
+func TestListenAndServeTLSMultipleAddresses(t *testing.T) {
+	dir := t.TempDir()
+	opts := options.NewOptions()
+	opts.TLSCertFile = filepath.Join(dir, "tls.crt")
+	opts.TLSPrivateKeyFile = filepath.Join(dir, "tls.key")
+	writeTestCert(t, opts.TLSCertFile, opts.TLSPrivateKeyFile, "127.0.0.1")
+	serverConfig, err := newServerTLSConfig(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var addresses []string
+	for i := 0; i < 2; i++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addresses = append(addresses, l.Addr().String())
+		l.Close()
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), ReadHeaderTimeout: time.Second}
+	flags := web.FlagConfig{WebListenAddresses: &addresses, WebSystemdSocket: new(bool), WebConfigFile: new(string)}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- listenAndServe(server, &flags, serverConfig, promLogger{})
+	}()
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}} //nolint:gosec
+	for _, address := range addresses {
+		var err error
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			var resp *http.Response
+			if resp, err = client.Get("https://" + address); err == nil {
+				resp.Body.Close()
+				break
+			}
+		}
+		if err != nil {
+			t.Errorf("expected HTTPS to be served on %s, got %v", address, err)
+		}
+	}
+
+	server.Close()
+	if err := <-errs; err != http.ErrServerClosed {
+		t.Errorf("expected the server to be closed, got %v", err)
+	}
+}
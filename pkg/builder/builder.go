@@ -18,11 +18,18 @@ package builder
 
 import (
 	"context"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	vpaclientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/transport"
 
 	internalstore "k8s.io/kube-state-metrics/v2/internal/store"
 	ksmtypes "k8s.io/kube-state-metrics/v2/pkg/builder/types"
@@ -30,6 +37,8 @@ import (
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/sharding"
+	"k8s.io/kube-state-metrics/v2/pkg/watch"
 )
 
 // Builder helps to build store. It follows the builder pattern
@@ -61,11 +70,21 @@ func (b *Builder) WithNamespaces(n options.NamespaceList) {
 	b.internal.WithNamespaces(n)
 }
 
+// WithFieldSelectors sets the field selectors of the objects to be watched per resource.
+func (b *Builder) WithFieldSelectors(fs map[string]string) error {
+	return b.internal.WithFieldSelectors(fs)
+}
+
 // WithSharding sets the shard and totalShards property of a Builder.
 func (b *Builder) WithSharding(shard int32, totalShards int) {
 	b.internal.WithSharding(shard, totalShards)
 }
 
+// WithShardingStrategy sets the strategy by which objects are assigned to shards.
+func (b *Builder) WithShardingStrategy(s sharding.Strategy) {
+	b.internal.WithShardingStrategy(s)
+}
+
 // WithContext sets the ctx property of a Builder.
 func (b *Builder) WithContext(ctx context.Context) {
 	b.internal.WithContext(ctx)
@@ -76,6 +95,20 @@ func (b *Builder) WithKubeClient(c clientset.Interface) {
 	b.internal.WithKubeClient(c)
 }
 
+// WithMetadataClient sets the metadataClient property of a Builder. If set,
+// collectors which only expose metrics derived from the object metadata watch
+// PartialObjectMetadata instead of full objects.
+func (b *Builder) WithMetadataClient(c metadata.Interface) {
+	b.internal.WithMetadataClient(c)
+}
+
+// WithDynamicClient sets the dynamicClient property of a Builder so that the
+// collectors of resources without typed clients, e.g. of the Gateway API, can
+// query their objects.
+func (b *Builder) WithDynamicClient(c dynamic.Interface) {
+	b.internal.WithDynamicClient(c)
+}
+
 // WithVPAClient sets the vpaClient property of a Builder so that the verticalpodautoscaler collector can query VPA objects.
 func (b *Builder) WithVPAClient(c vpaclientset.Interface) {
 	b.internal.WithVPAClient(c)
@@ -86,11 +119,141 @@ func (b *Builder) WithCustomResourceClients(cs map[string]interface{}) {
 	b.internal.WithCustomResourceClients(cs)
 }
 
+// WithRESTConfig creates the kube client, the VPA client and the clients of
+// the custom resource store factories configured before from the given config.
+// All requests of the clients are passed through the given transport wrappers,
+// e.g. to impersonate a user or to add audit headers. It replaces the clients
+// configured before.
+func (b *Builder) WithRESTConfig(cfg *rest.Config, wrappers ...transport.WrapperFunc) error {
+	return b.internal.WithRESTConfig(cfg, wrappers...)
+}
+
+// WithListWatchFuncs replaces the constructors of the ListerWatchers of the
+// given built-in resources, e.g. to list objects from another source than the
+// apiserver.
+func (b *Builder) WithListWatchFuncs(fs map[string]ksmtypes.ListWatchFunc) error {
+	return b.internal.WithListWatchFuncs(fs)
+}
+
+// WithTweakListOptions sets functions modifying the options of all list and
+// watch requests per resource, e.g. to add a label selector.
+func (b *Builder) WithTweakListOptions(fs map[string]func(*metav1.ListOptions)) error {
+	return b.internal.WithTweakListOptions(fs)
+}
+
 // WithUsingAPIServerCache configures whether using APIServer cache or not.
 func (b *Builder) WithUsingAPIServerCache(u bool) {
 	b.internal.WithUsingAPIServerCache(u)
 }
 
+// WithUsingWatchList configures whether the initial list of the reflectors is
+// streamed via watch list requests or not.
+func (b *Builder) WithUsingWatchList(u bool) {
+	b.internal.WithUsingWatchList(u)
+}
+
+// WithKeepFamilies configures whether the stores keep the metric families of
+// each object in addition to their text exposition format, e.g. to export
+// them via OTLP.
+func (b *Builder) WithKeepFamilies(k bool) {
+	b.internal.WithKeepFamilies(k)
+}
+
+// WithMaxObjects sets the maximum number of objects metrics are generated for
+// per resource.
+func (b *Builder) WithMaxObjects(m map[string]int) error {
+	return b.internal.WithMaxObjects(m)
+}
+
+// WithLazyResources sets the resources whose metrics are generated when they
+// are written instead of when their objects change.
+func (b *Builder) WithLazyResources(r []string) error {
+	return b.internal.WithLazyResources(r)
+}
+
+// WithMetricPrefixes sets the prefixes replacing the kube_ prefix of the
+// metric families of the given resources.
+func (b *Builder) WithMetricPrefixes(p map[string]string) error {
+	return b.internal.WithMetricPrefixes(p)
+}
+
+// WithAggregatedResources sets the resources whose metrics are summed by the
+// given labels instead of being written per object.
+func (b *Builder) WithAggregatedResources(a map[string][]string) error {
+	return b.internal.WithAggregatedResources(a)
+}
+
+// WithObjectCountResources sets the resources whose objects are counted by
+// the kube_objectcount metric.
+func (b *Builder) WithObjectCountResources(r []metav1.APIResource) {
+	b.internal.WithObjectCountResources(r)
+}
+
+// WithListPageSize sets the number of objects requested per page of the lists
+// of the reflectors. The default page size of client-go is used if it is 0.
+func (b *Builder) WithListPageSize(n int64) {
+	b.internal.WithListPageSize(n)
+}
+
+// WithStartupLimits limits the number of concurrent initial lists of the
+// reflectors and delays each of them by a random duration of up to the given
+// jitter. The number of concurrent initial lists is not limited if it is 0.
+func (b *Builder) WithStartupLimits(concurrency int, jitter time.Duration) {
+	b.internal.WithStartupLimits(concurrency, jitter)
+}
+
+// WithWatchBackoff configures the backoff of the reflectors between failed
+// list and watch attempts.
+func (b *Builder) WithWatchBackoff(backoff watch.Backoff) {
+	b.internal.WithWatchBackoff(backoff)
+}
+
+// WithUTF8LabelNames configures whether label names generated from
+// Kubernetes label and annotation keys which are not valid without UTF-8
+// support are kept verbatim instead of being sanitized.
+func (b *Builder) WithUTF8LabelNames(enabled bool) {
+	b.internal.WithUTF8LabelNames(enabled)
+}
+
+// WithMaxLabelValueLength configures the maximum length of label values
+// sourced from Kubernetes labels, annotations and custom resource fields.
+// Longer values are truncated and suffixed with their hash.
+func (b *Builder) WithMaxLabelValueLength(n int) {
+	b.internal.WithMaxLabelValueLength(n)
+}
+
+// WithPodStatusReasons configures additional pod status reasons which are
+// exposed by kube_pod_status_reason besides the well-known ones.
+func (b *Builder) WithPodStatusReasons(reasons []string) {
+	b.internal.WithPodStatusReasons(reasons)
+}
+
+// WithPodWorkloadResolution configures whether kube_pod_workload_info is
+// exposed, which resolves the workload of each Pod by following the
+// controllers of its owners.
+func (b *Builder) WithPodWorkloadResolution(enabled bool) {
+	b.internal.WithPodWorkloadResolution(enabled)
+}
+
+// WithStaleObjectGC configures the interval of listing the metadata of the
+// objects of each store to prune objects which no longer exist, e.g. as their
+// delete watch event was missed. An interval of 0 disables it.
+func (b *Builder) WithStaleObjectGC(interval time.Duration) {
+	b.internal.WithStaleObjectGC(interval)
+}
+
+// WithWatchHealth configures the Health tracking whether the list and watch
+// requests of all reflectors succeed.
+func (b *Builder) WithWatchHealth(h *watch.Health) {
+	b.internal.WithWatchHealth(h)
+}
+
+// WithTracer configures the tracer of the list and watch requests of all
+// reflectors.
+func (b *Builder) WithTracer(t trace.Tracer) {
+	b.internal.WithTracer(t)
+}
+
 // WithFamilyGeneratorFilter configures the family generator filter which decides which
 // metrics are to be exposed by the store build by the Builder.
 func (b *Builder) WithFamilyGeneratorFilter(l generator.FamilyGeneratorFilter) {
@@ -107,6 +270,31 @@ func (b *Builder) WithAllowLabels(l map[string][]string) {
 	b.internal.WithAllowLabels(l)
 }
 
+// WithTopK sets the number of most recently updated objects whose series are
+// written per resource or per metric family.
+func (b *Builder) WithTopK(k map[string]int) error {
+	return b.internal.WithTopK(k)
+}
+
+// WithExcludeCompleted sets the durations after which objects which reached a
+// terminal state are excluded per resource.
+func (b *Builder) WithExcludeCompleted(e map[string]time.Duration) error {
+	return b.internal.WithExcludeCompleted(e)
+}
+
+// WithRecommendedLabels configures whether the recommended labels, e.g.
+// app.kubernetes.io/name, are allowed for the metrics of all enabled
+// resources. It has to be called before WithAllowLabels.
+func (b *Builder) WithRecommendedLabels(enabled bool) {
+	b.internal.WithRecommendedLabels(enabled)
+}
+
+// WithLabelsDenylist configures which labels are dropped from the metrics of
+// each metric family
+func (b *Builder) WithLabelsDenylist(l map[string][]string) {
+	b.internal.WithLabelsDenylist(l)
+}
+
 // WithGenerateStoresFunc configures a custom generate store function
 func (b *Builder) WithGenerateStoresFunc(f ksmtypes.BuildStoresFunc) {
 	b.internal.WithGenerateStoresFunc(f)
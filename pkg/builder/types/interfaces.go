@@ -18,17 +18,26 @@ package types
 
 import (
 	"context"
+	"time"
 
 	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	vpaclientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/transport"
 
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/sharding"
+	"k8s.io/kube-state-metrics/v2/pkg/watch"
 )
 
 // BuilderInterface represent all methods that a Builder should implements
@@ -37,15 +46,43 @@ type BuilderInterface interface {
 	WithEnabledResources(c []string) error
 	WithNamespaces(n options.NamespaceList)
 	WithFieldSelectorFilter(fieldSelectors string)
+	WithFieldSelectors(fs map[string]string) error
+	WithMaxObjects(m map[string]int) error
+	WithLazyResources(r []string) error
+	WithMetricPrefixes(p map[string]string) error
+	WithAggregatedResources(a map[string][]string) error
+	WithObjectCountResources(r []metav1.APIResource)
 	WithSharding(shard int32, totalShards int)
+	WithShardingStrategy(s sharding.Strategy)
 	WithContext(ctx context.Context)
 	WithKubeClient(c clientset.Interface)
 	WithVPAClient(c vpaclientset.Interface)
+	WithMetadataClient(c metadata.Interface)
+	WithDynamicClient(c dynamic.Interface)
 	WithCustomResourceClients(cs map[string]interface{})
+	WithRESTConfig(cfg *rest.Config, wrappers ...transport.WrapperFunc) error
+	WithListWatchFuncs(fs map[string]ListWatchFunc) error
+	WithTweakListOptions(fs map[string]func(*metav1.ListOptions)) error
 	WithUsingAPIServerCache(u bool)
+	WithUsingWatchList(u bool)
+	WithKeepFamilies(k bool)
+	WithListPageSize(n int64)
+	WithStartupLimits(concurrency int, jitter time.Duration)
+	WithWatchBackoff(backoff watch.Backoff)
+	WithStaleObjectGC(interval time.Duration)
+	WithUTF8LabelNames(enabled bool)
+	WithMaxLabelValueLength(n int)
+	WithPodStatusReasons(reasons []string)
+	WithPodWorkloadResolution(enabled bool)
+	WithWatchHealth(h *watch.Health)
+	WithTracer(t trace.Tracer)
 	WithFamilyGeneratorFilter(l generator.FamilyGeneratorFilter)
 	WithAllowAnnotations(a map[string][]string)
 	WithAllowLabels(l map[string][]string) error
+	WithTopK(k map[string]int) error
+	WithExcludeCompleted(e map[string]time.Duration) error
+	WithRecommendedLabels(enabled bool)
+	WithLabelsDenylist(l map[string][]string)
 	WithGenerateStoresFunc(f BuildStoresFunc)
 	WithGenerateCustomResourceStoresFunc(f BuildCustomResourceStoresFunc)
 	DefaultGenerateStoresFunc() BuildStoresFunc
@@ -55,11 +92,43 @@ type BuilderInterface interface {
 	BuildStores() [][]cache.Store
 }
 
+// ListWatchFunc creates the cache.ListerWatcher of a built-in resource in the
+// given namespace, which is empty for all namespaces.
+type ListWatchFunc func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher
+
+// StoreOptions are the options of the stores of a single resource.
+type StoreOptions struct {
+	// FieldSelector selects the objects of the resource which are watched.
+	FieldSelector string
+	// ListWatchFunc replaces the ListerWatcher constructor of the resource
+	// if set.
+	ListWatchFunc ListWatchFunc
+	// TweakListOptions modifies the options of all list and watch requests
+	// of the resource if set.
+	TweakListOptions func(*metav1.ListOptions)
+	// MetricPrefix replaces the kube_ prefix of the metric families of the
+	// resource if set.
+	MetricPrefix string
+	// Lazy is whether the metrics of the resource are generated when they are
+	// written instead of when its objects change.
+	Lazy bool
+	// Aggregate is whether only the sums of the series of the resource by
+	// the AggregateBy labels are kept instead of the metrics of each object.
+	Aggregate   bool
+	AggregateBy []string
+	// ObjectLimit limits the number of objects of all stores of the resource
+	// if set.
+	ObjectLimit *metricsstore.ObjectLimit
+	// Expiry drops the metrics of completed objects of the resource if set.
+	Expiry metricsstore.ExpiryFunc
+}
+
 // BuildStoresFunc function signature that is used to return a list of cache.Store
 type BuildStoresFunc func(metricFamilies []generator.FamilyGenerator,
 	expectedType interface{},
 	listWatchFunc func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher,
 	useAPIServerCache bool,
+	opts StoreOptions,
 ) []cache.Store
 
 // BuildCustomResourceStoresFunc function signature that is used to return a list of custom resource cache.Store
@@ -68,6 +137,7 @@ type BuildCustomResourceStoresFunc func(resourceName string,
 	expectedType interface{},
 	listWatchFunc func(customResourceClient interface{}, ns string, fieldSelector string) cache.ListerWatcher,
 	useAPIServerCache bool,
+	opts StoreOptions,
 ) []cache.Store
 
 // AllowDenyLister interface for AllowDeny lister that can allow or exclude metrics by there names
@@ -62,6 +62,12 @@ type Resource struct {
 
 	// ResourcePlural sets the plural name of the resource. Defaults to the plural version of the Kind according to flect.Pluralize.
 	ResourcePlural string `yaml:"resourcePlural" json:"resourcePlural"`
+
+	// LabelAllowlist restricts the labels of all metrics of the resource to the given label names.
+	// The auto-generated GVK labels are always kept. Mutually exclusive with LabelDenylist.
+	LabelAllowlist []string `yaml:"labelAllowlist" json:"labelAllowlist"`
+	// LabelDenylist removes the given label names from all metrics of the resource. Mutually exclusive with LabelAllowlist.
+	LabelDenylist []string `yaml:"labelDenylist" json:"labelDenylist"`
 }
 
 // GetMetricNamePrefix returns the prefix to use for metrics.
@@ -95,6 +101,8 @@ type Labels struct {
 	CommonLabels map[string]string `yaml:"commonLabels" json:"commonLabels"`
 	// LabelsFromPath adds additional labels where the value is taken from a field in the resource.
 	LabelsFromPath map[string][]string `yaml:"labelsFromPath" json:"labelsFromPath"`
+	// ListSeparator joins list values of LabelsFromPath into a single label value using the given separator.
+	ListSeparator string `yaml:"listSeparator" json:"listSeparator"`
 }
 
 // Merge combines the labels from two configs, returning a new config. The other Labels will overwrite keys in this Labels.
@@ -114,9 +122,14 @@ func (l Labels) Merge(other Labels) Labels {
 	for k, v := range other.LabelsFromPath {
 		paths[k] = v
 	}
+	separator := l.ListSeparator
+	if other.ListSeparator != "" {
+		separator = other.ListSeparator
+	}
 	return Labels{
 		CommonLabels:   common,
 		LabelsFromPath: paths,
+		ListSeparator:  separator,
 	}
 }
 
@@ -26,12 +26,27 @@ const (
 	MetricTypeInfo     MetricType = "Info"
 )
 
+// NilBehavior defines how a gauge handles a missing value.
+type NilBehavior string
+
+// Supported nil behaviors.
+const (
+	// NilBehaviorZero exposes a missing value as 0.
+	NilBehaviorZero NilBehavior = "zero"
+	// NilBehaviorSkip does not expose a sample for a missing value.
+	NilBehaviorSkip NilBehavior = "skip"
+	// NilBehaviorNaN exposes a missing value as NaN.
+	NilBehaviorNaN NilBehavior = "nan"
+)
+
 // MetricMeta are variables which may used for any metric type.
 type MetricMeta struct {
 	// LabelsFromPath adds additional labels where the value of the label is taken from a field under Path.
 	LabelsFromPath map[string][]string `yaml:"labelsFromPath" json:"labelsFromPath"`
 	// Path is the path to to generate metric(s) for.
 	Path []string `yaml:"path" json:"path"`
+	// ListSeparator joins list values of LabelsFromPath into a single label value using the given separator.
+	ListSeparator string `yaml:"listSeparator" json:"listSeparator"`
 }
 
 // MetricGauge targets a Path that may be a single value, array, or object. Arrays and objects will generate a metric per element.
@@ -44,7 +59,11 @@ type MetricGauge struct {
 	// LabelFromKey adds a label with the given name if Path is an object. The label value will be the object key.
 	LabelFromKey string `yaml:"labelFromKey" json:"labelFromKey"`
 	// NilIsZero indicates that if a value is nil it will be treated as zero value.
+	// It is equivalent to setting NilBehavior to "zero".
 	NilIsZero bool `yaml:"nilIsZero" json:"nilIsZero"`
+	// NilBehavior defines how a nil value is handled: "zero" exposes 0, "skip" exposes no sample and "nan" exposes NaN.
+	// If unset, no sample is exposed and an error is logged.
+	NilBehavior NilBehavior `yaml:"nilBehavior" json:"nilBehavior"`
 }
 
 // MetricInfo is a metric which is used to expose textual information.
@@ -40,6 +40,10 @@ type customResourceMetrics struct {
 	GroupVersionKind schema.GroupVersionKind
 	ResourceName     string
 	Families         []compiledFamily
+	// resolveResource is true if the resource was not configured, but guessed
+	// from the kind. It is then resolved by the mapper, if set.
+	resolveResource bool
+	mapper          *ResourceMapper
 }
 
 var _ customresource.RegistryFactory = &customResourceMetrics{}
@@ -56,6 +60,7 @@ func NewCustomResourceMetrics(resource Resource) (customresource.RegistryFactory
 		GroupVersionKind: gvk,
 		Families:         compiled,
 		ResourceName:     resource.GetResourceName(),
+		resolveResource:  resource.ResourcePlural == "",
 	}, nil
 }
 
@@ -68,6 +73,9 @@ func (s customResourceMetrics) CreateClient(cfg *rest.Config) (interface{}, erro
 	if err != nil {
 		return nil, err
 	}
+	if s.mapper != nil {
+		return &mappedResourceClient{client: c, mapper: s.mapper, gvk: s.GroupVersionKind, fallback: s.ResourceName}, nil
+	}
 	return c.Resource(schema.GroupVersionResource{
 		Group:    s.GroupVersionKind.Group,
 		Version:  s.GroupVersionKind.Version,
@@ -77,8 +85,19 @@ func (s customResourceMetrics) CreateClient(cfg *rest.Config) (interface{}, erro
 
 func (s customResourceMetrics) MetricFamilyGenerators(_, _ []string) (result []generator.FamilyGenerator) {
 	klog.InfoS("Custom resource state added metrics", "familyNames", s.names())
+	index := map[string]int{}
+	var families [][]compiledFamily
 	for _, f := range s.Families {
-		result = append(result, famGen(f))
+		i, ok := index[f.Name]
+		if !ok {
+			i = len(families)
+			index[f.Name] = i
+			families = append(families, nil)
+		}
+		families[i] = append(families[i], f)
+	}
+	for _, fs := range families {
+		result = append(result, famGen(fs...))
 	}
 
 	return result
@@ -91,16 +110,21 @@ func (s customResourceMetrics) ExpectedType() interface{} {
 }
 
 func (s customResourceMetrics) ListWatch(customResourceClient interface{}, ns string, fieldSelector string) cache.ListerWatcher {
-	api := customResourceClient.(dynamic.NamespaceableResourceInterface).Namespace(ns)
+	api := func() dynamic.ResourceInterface {
+		if c, ok := customResourceClient.(*mappedResourceClient); ok {
+			return c.resource().Namespace(ns)
+		}
+		return customResourceClient.(dynamic.NamespaceableResourceInterface).Namespace(ns)
+	}
 	ctx := context.Background()
 	return &cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
 			options.FieldSelector = fieldSelector
-			return api.List(ctx, options)
+			return api().List(ctx, options)
 		},
 		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 			options.FieldSelector = fieldSelector
-			return api.Watch(ctx, options)
+			return api().Watch(ctx, options)
 		},
 	}
 }
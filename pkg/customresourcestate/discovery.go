@@ -0,0 +1,310 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/flect"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// crdResource is the resource of CustomResourceDefinitions.
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+var invalidMetricNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Discoverer generates a default Resource configuration for every
+// CustomResourceDefinition matching a label selector.
+type Discoverer struct {
+	client   dynamic.Interface
+	selector string
+	// debounce is the time to wait for further CRD changes before the
+	// discovered resources are recomputed.
+	debounce time.Duration
+}
+
+// NewDiscoverer returns a new Discoverer for CustomResourceDefinitions
+// matching the given label selector. An empty selector matches all of them.
+func NewDiscoverer(client dynamic.Interface, selector string) *Discoverer {
+	return &Discoverer{
+		client:   client,
+		selector: selector,
+		debounce: 5 * time.Second,
+	}
+}
+
+// Discover lists all matching CustomResourceDefinitions once and returns
+// their default configurations.
+func (d *Discoverer) Discover(ctx context.Context) ([]Resource, error) {
+	list, err := d.client.Resource(crdResource).List(ctx, metav1.ListOptions{LabelSelector: d.selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)
+	}
+	crds := make([]*unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		crds = append(crds, &list.Items[i])
+	}
+	return ResourcesFromCRDs(crds), nil
+}
+
+// Run watches all matching CustomResourceDefinitions and calls onChange with
+// the default configurations whenever they differ from the last ones,
+// starting from initial.
+func (d *Discoverer) Run(ctx context.Context, initial []Resource, onChange func([]Resource) error) error {
+	api := d.client.Resource(crdResource)
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = d.selector
+			return api.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = d.selector
+			return api.Watch(ctx, options)
+		},
+	}, &unstructured.Unstructured{}, 0, cache.Indexers{})
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	})
+	go informer.Run(ctx.Done())
+
+	current := initial
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+
+		// Wait for further changes, e.g. if multiple CRDs of an operator are installed at once.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.debounce):
+		}
+
+		var crds []*unstructured.Unstructured
+		for _, obj := range informer.GetStore().List() {
+			crds = append(crds, obj.(*unstructured.Unstructured))
+		}
+		resources := ResourcesFromCRDs(crds)
+		if reflect.DeepEqual(resources, current) {
+			continue
+		}
+		klog.InfoS("Discovered custom resources changed", "resources", resourceNames(resources))
+		if err := onChange(resources); err != nil {
+			klog.ErrorS(err, "Failed to apply discovered custom resources")
+			continue
+		}
+		current = resources
+	}
+}
+
+// ResourcesFromCRDs returns a Resource with a default set of metrics for each
+// of the given CustomResourceDefinitions, sorted by resource name.
+// CustomResourceDefinitions without a served storage version are skipped. As
+// stores are identified by the plural resource name, only the first
+// CustomResourceDefinition by name is used if multiple groups define the same
+// resource.
+func ResourcesFromCRDs(crds []*unstructured.Unstructured) []Resource {
+	sort.Slice(crds, func(i, j int) bool {
+		return crds[i].GetName() < crds[j].GetName()
+	})
+	var resources []Resource
+	seen := map[string]string{}
+	for _, crd := range crds {
+		resource, ok := resourceFromCRD(crd.Object)
+		if !ok {
+			klog.V(2).InfoS("Skipping CustomResourceDefinition without a served storage version", "crd", crd.GetName())
+			continue
+		}
+		if other, ok := seen[resource.GetResourceName()]; ok {
+			klog.InfoS("Skipping CustomResourceDefinition as its resource is already provided by another one", "crd", crd.GetName(), "other", other)
+			continue
+		}
+		seen[resource.GetResourceName()] = crd.GetName()
+		resources = append(resources, resource)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].GetResourceName() < resources[j].GetResourceName()
+	})
+	return resources
+}
+
+// resourceFromCRD generates the default configuration of a CustomResourceDefinition.
+func resourceFromCRD(crd map[string]interface{}) (Resource, bool) {
+	group, _, _ := unstructured.NestedString(crd, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd, "spec", "names", "kind")
+	plural, _, _ := unstructured.NestedString(crd, "spec", "names", "plural")
+	scope, _, _ := unstructured.NestedString(crd, "spec", "scope")
+	versions, _, _ := unstructured.NestedSlice(crd, "spec", "versions")
+
+	var version map[string]interface{}
+	for _, v := range versions {
+		v, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		served, _, _ := unstructured.NestedBool(v, "served")
+		storage, _, _ := unstructured.NestedBool(v, "storage")
+		if served && storage {
+			version = v
+			break
+		}
+	}
+	if version == nil || kind == "" || plural == "" {
+		return Resource{}, false
+	}
+	versionName, _, _ := unstructured.NestedString(version, "name")
+
+	// Metric names contain the kind, as metric families must not be defined by multiple resources.
+	prefix := "kube_customresource_" + strings.Trim(invalidMetricNameChars.ReplaceAllString(strings.ToLower(flect.Underscore(kind)), "_"), "_")
+
+	labels := map[string][]string{
+		"name": {"metadata", "name"},
+	}
+	if scope == "Namespaced" {
+		labels["namespace"] = []string{"metadata", "namespace"}
+	}
+
+	resource := Resource{
+		MetricNamePrefix: &prefix,
+		GroupVersionKind: GroupVersionKind{Group: group, Version: versionName, Kind: kind},
+		ResourcePlural:   plural,
+		Labels:           Labels{LabelsFromPath: labels},
+		Metrics: []Generator{
+			{
+				Name: "info",
+				Help: fmt.Sprintf("Information about the %s.", kind),
+				Each: Metric{
+					Type: MetricTypeInfo,
+					Info: &MetricInfo{MetricMeta: MetricMeta{Path: []string{"metadata"}}},
+				},
+			},
+			{
+				Name: "created",
+				Help: fmt.Sprintf("Unix creation timestamp of the %s.", kind),
+				Each: Metric{
+					Type:  MetricTypeGauge,
+					Gauge: &MetricGauge{MetricMeta: MetricMeta{Path: []string{"metadata", "creationTimestamp"}}},
+				},
+			},
+		},
+	}
+
+	if _, ok, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema", "properties", "status", "properties", "conditions"); ok {
+		resource.Metrics = append(resource.Metrics, Generator{
+			Name: "status_condition",
+			Help: fmt.Sprintf("The condition of the %s.", kind),
+			Each: Metric{
+				Type: MetricTypeStateSet,
+				StateSet: &MetricStateSet{
+					MetricMeta: MetricMeta{
+						Path:           []string{"status", "conditions"},
+						LabelsFromPath: map[string][]string{"condition": {"type"}},
+					},
+					LabelName: "status",
+					ValueFrom: []string{"status"},
+					List:      []string{"True", "False", "Unknown"},
+				},
+			},
+		})
+	}
+
+	for _, replicas := range []struct{ field, metric, help string }{
+		{field: "specReplicasPath", metric: "spec_replicas", help: "The number of desired replicas of the %s."},
+		{field: "statusReplicasPath", metric: "status_replicas", help: "The number of actual replicas of the %s."},
+	} {
+		jsonPath, _, _ := unstructured.NestedString(version, "subresources", "scale", replicas.field)
+		path := pathFromJSONPath(jsonPath)
+		if path == nil {
+			// Without a scale subresource, fall back to the conventional
+			// replicas fields if the schema defines them as integers.
+			path = []string{strings.TrimSuffix(replicas.metric, "_replicas"), "replicas"}
+			if !schemaHasInteger(version, path) {
+				continue
+			}
+		}
+		resource.Metrics = append(resource.Metrics, Generator{
+			Name: replicas.metric,
+			Help: fmt.Sprintf(replicas.help, kind),
+			Each: Metric{
+				Type:  MetricTypeGauge,
+				Gauge: &MetricGauge{MetricMeta: MetricMeta{Path: path}, NilBehavior: NilBehaviorSkip},
+			},
+		})
+	}
+
+	return resource, true
+}
+
+// schemaHasInteger returns whether the OpenAPI schema of a
+// CustomResourceDefinition version defines an integer property at path.
+func schemaHasInteger(version map[string]interface{}, path []string) bool {
+	fields := []string{"schema", "openAPIV3Schema"}
+	for _, p := range path {
+		fields = append(fields, "properties", p)
+	}
+	typ, _, _ := unstructured.NestedString(version, append(fields, "type")...)
+	return typ == "integer"
+}
+
+// pathFromJSONPath converts a simple JSON path like ".spec.replicas" of a
+// scale subresource to a path. It returns nil for empty or unsupported paths.
+func pathFromJSONPath(jsonPath string) []string {
+	if jsonPath == "" || strings.ContainsAny(jsonPath, "[]*") {
+		return nil
+	}
+	var path []string
+	for _, p := range strings.Split(jsonPath, ".") {
+		if p != "" {
+			path = append(path, p)
+		}
+	}
+	return path
+}
+
+func resourceNames(resources []Resource) []string {
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.GetResourceName())
+	}
+	return names
+}
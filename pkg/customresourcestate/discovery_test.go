@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func crd(name, group, kind, plural, scope string, version map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"group": group,
+			"names": map[string]interface{}{"kind": kind, "plural": plural},
+			"scope": scope,
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": true, "storage": false},
+				version,
+			},
+		},
+	}}
+	return u
+}
+
+func TestResourcesFromCRDs(t *testing.T) {
+	crds := []*unstructured.Unstructured{
+		crd("widgets.example.com", "example.com", "Widget", "widgets", "Cluster", map[string]interface{}{
+			"name": "v1", "served": true, "storage": true,
+		}),
+		crd("unserved.example.com", "example.com", "Unserved", "unserveds", "Cluster", map[string]interface{}{
+			"name": "v1", "served": false, "storage": true,
+		}),
+		crd("widgets.other.io", "other.io", "Widget", "widgets", "Cluster", map[string]interface{}{
+			"name": "v1", "served": true, "storage": true,
+		}),
+		crd("appsets.example.com", "example.com", "AppSet", "appsets", "Namespaced", map[string]interface{}{
+			"name":   "v1beta1",
+			"served": true, "storage": true,
+			"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{"properties": map[string]interface{}{
+				"status": map[string]interface{}{"properties": map[string]interface{}{
+					"conditions": map[string]interface{}{"type": "array"},
+				}},
+			}}},
+			"subresources": map[string]interface{}{"scale": map[string]interface{}{
+				"specReplicasPath":   ".spec.replicas",
+				"statusReplicasPath": ".status.replicas",
+			}},
+		}),
+		crd("pools.example.com", "example.com", "Pool", "pools", "Cluster", map[string]interface{}{
+			"name":   "v1",
+			"served": true, "storage": true,
+			"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{"properties": map[string]interface{}{
+				"spec": map[string]interface{}{"properties": map[string]interface{}{
+					"replicas": map[string]interface{}{"type": "integer"},
+				}},
+				"status": map[string]interface{}{"properties": map[string]interface{}{
+					"replicas": map[string]interface{}{"type": "string"},
+				}},
+			}}},
+		}),
+	}
+
+	resources := ResourcesFromCRDs(crds)
+	if !assert.Len(t, resources, 3) {
+		return
+	}
+
+	appSetPrefix := "kube_customresource_app_set"
+	assert.Equal(t, Resource{
+		MetricNamePrefix: &appSetPrefix,
+		GroupVersionKind: GroupVersionKind{Group: "example.com", Version: "v1beta1", Kind: "AppSet"},
+		ResourcePlural:   "appsets",
+		Labels: Labels{LabelsFromPath: map[string][]string{
+			"name":      {"metadata", "name"},
+			"namespace": {"metadata", "namespace"},
+		}},
+		Metrics: []Generator{
+			{
+				Name: "info",
+				Help: "Information about the AppSet.",
+				Each: Metric{Type: MetricTypeInfo, Info: &MetricInfo{MetricMeta: MetricMeta{Path: []string{"metadata"}}}},
+			},
+			{
+				Name: "created",
+				Help: "Unix creation timestamp of the AppSet.",
+				Each: Metric{Type: MetricTypeGauge, Gauge: &MetricGauge{MetricMeta: MetricMeta{Path: []string{"metadata", "creationTimestamp"}}}},
+			},
+			{
+				Name: "status_condition",
+				Help: "The condition of the AppSet.",
+				Each: Metric{Type: MetricTypeStateSet, StateSet: &MetricStateSet{
+					MetricMeta: MetricMeta{
+						Path:           []string{"status", "conditions"},
+						LabelsFromPath: map[string][]string{"condition": {"type"}},
+					},
+					LabelName: "status",
+					ValueFrom: []string{"status"},
+					List:      []string{"True", "False", "Unknown"},
+				}},
+			},
+			{
+				Name: "spec_replicas",
+				Help: "The number of desired replicas of the AppSet.",
+				Each: Metric{Type: MetricTypeGauge, Gauge: &MetricGauge{MetricMeta: MetricMeta{Path: []string{"spec", "replicas"}}, NilBehavior: NilBehaviorSkip}},
+			},
+			{
+				Name: "status_replicas",
+				Help: "The number of actual replicas of the AppSet.",
+				Each: Metric{Type: MetricTypeGauge, Gauge: &MetricGauge{MetricMeta: MetricMeta{Path: []string{"status", "replicas"}}, NilBehavior: NilBehaviorSkip}},
+			},
+		},
+	}, resources[0])
+
+	// Without a scale subresource, integer replicas fields of the schema are used.
+	if assert.Len(t, resources[1].Metrics, 3) {
+		assert.Equal(t, "spec_replicas", resources[1].Metrics[2].Name)
+		assert.Equal(t, []string{"spec", "replicas"}, resources[1].Metrics[2].Each.Gauge.Path)
+	}
+
+	// The first CRD by name provides the widgets resource.
+	assert.Equal(t, GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, resources[2].GroupVersionKind)
+	assert.Equal(t, map[string][]string{"name": {"metadata", "name"}}, resources[2].LabelsFromPath)
+	assert.Len(t, resources[2].Metrics, 2)
+
+	for _, r := range resources {
+		_, err := NewCustomResourceMetrics(r)
+		assert.NoError(t, err, r.GetResourceName())
+	}
+}
+
+func TestDiscoverer(t *testing.T) {
+	widgets := crd("widgets.example.com", "example.com", "Widget", "widgets", "Cluster", map[string]interface{}{
+		"name": "v1", "served": true, "storage": true,
+	})
+	gadgets := crd("gadgets.example.com", "example.com", "Gadget", "gadgets", "Cluster", map[string]interface{}{
+		"name": "v1", "served": true, "storage": true,
+	})
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		crdResource: "CustomResourceDefinitionList",
+	}, widgets)
+
+	d := NewDiscoverer(client, "")
+	d.debounce = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initial, err := d.Discover(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"widgets"}, resourceNames(initial))
+
+	changes := make(chan []Resource, 1)
+	go d.Run(ctx, initial, func(r []Resource) error { //nolint:errcheck
+		changes <- r
+		return nil
+	})
+
+	if _, err := client.Resource(crdResource).Create(ctx, gadgets, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case r := <-changes:
+		assert.Equal(t, []string{"gadgets", "widgets"}, resourceNames(r))
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected discovered resources to change")
+	}
+}
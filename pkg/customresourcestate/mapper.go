@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+)
+
+// ResourceMapper resolves the resources of custom resources configured without
+// a resourcePlural by their kind, using the discovery information of the
+// apiserver instead of guessing the resource from the kind. The discovery
+// information is cached and refreshed whenever CustomResourceDefinitions
+// change and periodically, so that custom resources whose
+// CustomResourceDefinition is created later are picked up without a restart.
+type ResourceMapper struct {
+	discovery       discovery.DiscoveryInterface
+	client          dynamic.Interface
+	refreshInterval time.Duration
+
+	mtx sync.Mutex
+	// mapper is nil until the discovery information is fetched after the
+	// last reset.
+	mapper meta.RESTMapper
+}
+
+// NewResourceMapper returns a ResourceMapper using the apiserver of the given
+// config, whose discovery information is refreshed in the given interval. It
+// is only refreshed on changes of CustomResourceDefinitions if the interval
+// is 0.
+func NewResourceMapper(cfg *rest.Config, refreshInterval time.Duration) (*ResourceMapper, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return newResourceMapper(discoveryClient, client, refreshInterval), nil
+}
+
+func newResourceMapper(discoveryClient discovery.DiscoveryInterface, client dynamic.Interface, refreshInterval time.Duration) *ResourceMapper {
+	return &ResourceMapper{
+		discovery:       discoveryClient,
+		client:          client,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// Configure makes the factories of custom resources configured without a
+// resourcePlural resolve their resource with the ResourceMapper. It must be
+// called before their clients are created. Other factories are not changed.
+func (m *ResourceMapper) Configure(factories []customresource.RegistryFactory) {
+	for _, f := range factories {
+		if c, ok := f.(*customResourceMetrics); ok && c.resolveResource {
+			c.mapper = m
+		}
+	}
+}
+
+// resource returns the resource of the given kind, or fallback if the kind is
+// not served by the apiserver.
+func (m *ResourceMapper) resource(gvk schema.GroupVersionKind, fallback string) string {
+	mapper, err := m.restMapper()
+	if err != nil {
+		klog.ErrorS(err, "Failed to discover resources of the apiserver, using the configured resource", "gvk", gvk, "resource", fallback)
+		return fallback
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		klog.V(4).InfoS("Failed to resolve resource of custom resource, using the configured resource", "gvk", gvk, "resource", fallback, "err", err)
+		return fallback
+	}
+	return mapping.Resource.Resource
+}
+
+// restMapper returns the RESTMapper of the cached discovery information,
+// fetching it if it was reset. Discovery information of groups which failed to
+// be fetched, e.g. of unavailable aggregated APIs, is missing.
+func (m *ResourceMapper) restMapper() (meta.RESTMapper, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.mapper == nil {
+		groups, err := restmapper.GetAPIGroupResources(m.discovery)
+		if err != nil && len(groups) == 0 {
+			return nil, err
+		}
+		m.mapper = restmapper.NewDiscoveryRESTMapper(groups)
+	}
+	return m.mapper, nil
+}
+
+// reset drops the cached discovery information.
+func (m *ResourceMapper) reset() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.mapper = nil
+}
+
+// Run refreshes the discovery information whenever CustomResourceDefinitions
+// change and periodically, until ctx is done. Changes are only watched if
+// CustomResourceDefinitions can be listed.
+func (m *ResourceMapper) Run(ctx context.Context) error {
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+
+	api := m.client.Resource(crdResource)
+	if _, err := api.List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+		klog.ErrorS(err, "Failed to list CustomResourceDefinitions, resources of custom resources are only resolved again periodically", "interval", m.refreshInterval)
+	} else {
+		informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return api.List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return api.Watch(ctx, options)
+			},
+		}, &unstructured.Unstructured{}, 0, cache.Indexers{})
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { notify() },
+			UpdateFunc: func(interface{}, interface{}) { notify() },
+			DeleteFunc: func(interface{}) { notify() },
+		})
+		go informer.Run(ctx.Done())
+	}
+
+	var refresh <-chan time.Time
+	if m.refreshInterval > 0 {
+		ticker := time.NewTicker(m.refreshInterval)
+		defer ticker.Stop()
+		refresh = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		case <-refresh:
+		}
+		m.reset()
+	}
+}
+
+// mappedResourceClient is the client of a custom resource whose resource is
+// resolved by a ResourceMapper on each request.
+type mappedResourceClient struct {
+	client   dynamic.Interface
+	mapper   *ResourceMapper
+	gvk      schema.GroupVersionKind
+	fallback string
+}
+
+func (c *mappedResourceClient) resource() dynamic.NamespaceableResourceInterface {
+	return c.client.Resource(schema.GroupVersionResource{
+		Group:    c.gvk.Group,
+		Version:  c.gvk.Version,
+		Resource: c.mapper.resource(c.gvk, c.fallback),
+	})
+}
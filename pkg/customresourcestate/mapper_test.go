@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+)
+
+func TestResourceMapper(t *testing.T) {
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	m := newResourceMapper(discovery, fakedynamic.NewSimpleDynamicClient(runtime.NewScheme()), 0)
+
+	configured, err := NewCustomResourceMetrics(Resource{
+		GroupVersionKind: GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Octopus"},
+		ResourcePlural:   "octopi",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	guessed, err := NewCustomResourceMetrics(Resource{
+		GroupVersionKind: GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Configure([]customresource.RegistryFactory{configured, guessed})
+
+	client, err := configured.CreateClient(&rest.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.(*mappedResourceClient); ok {
+		t.Errorf("expected the client of a configured resource not to be resolved")
+	}
+	client, err = guessed.CreateClient(&rest.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapped, ok := client.(*mappedResourceClient)
+	if !ok {
+		t.Fatalf("expected the client of a guessed resource to be resolved, got %T", client)
+	}
+
+	resource := func() string {
+		return mapped.mapper.resource(mapped.gvk, mapped.fallback)
+	}
+	if got := resource(); got != "widgets" {
+		t.Errorf("expected the guessed resource before the CRD exists, got %q", got)
+	}
+
+	discovery.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgetz", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"list", "watch"}}},
+	}}
+	if got := resource(); got != "widgets" {
+		t.Errorf("expected the cached discovery information to be used until it is reset, got %q", got)
+	}
+	m.reset()
+	if got := resource(); got != "widgetz" {
+		t.Errorf("expected the resource of the CRD once the mapper was reset, got %q", got)
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Migration is a single change made by Migrate.
+type Migration struct {
+	// Path is the location of the changed field, e.g. spec.resources[0].metrics[1].each.
+	Path    string
+	Message string
+}
+
+func (m Migration) String() string {
+	return m.Path + ": " + m.Message
+}
+
+// flatEachFields are the fields of each which were set directly on it before
+// the metric types were introduced, and belong to each.gauge now.
+var flatEachFields = []string{"path", "valueFrom", "labelFromKey", "labelsFromPath", "nilIsZero"}
+
+// Migrate rewrites deprecated constructs of a Custom Resource State
+// configuration read from r into the current schema and writes it to w. Each
+// change is explained by a comment above the changed field and returned. The
+// following constructs are migrated:
+//   - paths written as strings like "status.conditions[type=Ready].status" or
+//     JSONPath filters like "[?(@.type=='Ready')]" are converted to lists
+//   - fields of each without a type are moved to each.gauge
+//   - types in other cases like "gauge" are converted to their canonical names
+//   - nilIsZero is replaced by nilBehavior
+func Migrate(r io.Reader, w io.Writer) ([]Migration, error) {
+	m := &migrator{}
+	decoder := yaml.NewDecoder(r)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse configuration: %w", err)
+		}
+		if len(doc.Content) > 0 {
+			m.config(doc.Content[0])
+		}
+		if err := encoder.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	return m.migrations, encoder.Close()
+}
+
+type migrator struct {
+	migrations []Migration
+}
+
+// add records a migration at path and explains it by a comment above key.
+func (m *migrator) add(key *yaml.Node, path, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	m.migrations = append(m.migrations, Migration{Path: path, Message: message})
+	comment := "migrated: " + message
+	if key.HeadComment != "" {
+		comment = key.HeadComment + "\n" + comment
+	}
+	key.HeadComment = comment
+}
+
+func (m *migrator) config(root *yaml.Node) {
+	_, resources := mappingField(mappingValue(root, "spec"), "resources")
+	if resources == nil || resources.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, resource := range resources.Content {
+		path := fmt.Sprintf("spec.resources[%d]", i)
+		m.labelsFromPath(resource, path)
+		_, metrics := mappingField(resource, "metrics")
+		if metrics == nil || metrics.Kind != yaml.SequenceNode {
+			continue
+		}
+		for j, generator := range metrics.Content {
+			path := fmt.Sprintf("%s.metrics[%d]", path, j)
+			m.labelsFromPath(generator, path)
+			m.each(mappingValue(generator, "each"), path+".each")
+		}
+	}
+}
+
+func (m *migrator) each(each *yaml.Node, path string) {
+	if each == nil || each.Kind != yaml.MappingNode {
+		return
+	}
+
+	typeKey, typeValue := mappingField(each, "type")
+	if typeValue == nil {
+		var moved []*yaml.Node
+		for _, field := range flatEachFields {
+			if key, value := removeMappingField(each, field); key != nil {
+				moved = append(moved, key, value)
+			}
+		}
+		if len(moved) == 0 {
+			return
+		}
+		typeKey, typeValue = scalarNode("type"), scalarNode(string(MetricTypeGauge))
+		each.Content = append([]*yaml.Node{typeKey, typeValue, scalarNode("gauge"), {Kind: yaml.MappingNode, Content: moved}}, each.Content...)
+		m.add(typeKey, path, "fields of each without a type were moved to each.gauge")
+	}
+
+	for _, t := range []MetricType{MetricTypeGauge, MetricTypeStateSet, MetricTypeInfo} {
+		if typeValue.Value != string(t) && strings.EqualFold(typeValue.Value, string(t)) {
+			m.add(typeKey, path+".type", "type %q was renamed to %q", typeValue.Value, t)
+			typeValue.Value = string(t)
+		}
+	}
+
+	for _, field := range []string{"gauge", "stateSet", "info"} {
+		meta := mappingValue(each, field)
+		if meta == nil || meta.Kind != yaml.MappingNode {
+			continue
+		}
+		metaPath := path + "." + field
+		m.path(meta, "path", metaPath)
+		m.path(meta, "valueFrom", metaPath)
+		m.labelsFromPath(meta, metaPath)
+		if field == "gauge" {
+			m.nilIsZero(meta, metaPath)
+		}
+	}
+}
+
+// nilIsZero replaces nilIsZero of a gauge by the equivalent nilBehavior. It
+// is kept if it conflicts with nilBehavior, which is reported when loading
+// the configuration.
+func (m *migrator) nilIsZero(gauge *yaml.Node, path string) {
+	key, value := mappingField(gauge, "nilIsZero")
+	if key == nil {
+		return
+	}
+	var nilIsZero bool
+	if err := value.Decode(&nilIsZero); err != nil {
+		return
+	}
+	behaviorKey, behavior := mappingField(gauge, "nilBehavior")
+	switch {
+	case !nilIsZero:
+		removeMappingField(gauge, "nilIsZero")
+		key = gauge
+		if len(gauge.Content) > 0 {
+			key = gauge.Content[0]
+		}
+		m.add(key, path, "nilIsZero: false was removed, as it is the default")
+	case behavior == nil:
+		key.Value = "nilBehavior"
+		value.Value, value.Tag, value.Style = string(NilBehaviorZero), "!!str", 0
+		m.add(key, path+".nilIsZero", "nilIsZero: true was replaced by nilBehavior: %s", NilBehaviorZero)
+	case behavior.Value == string(NilBehaviorZero):
+		removeMappingField(gauge, "nilIsZero")
+		m.add(behaviorKey, path+".nilIsZero", "nilIsZero: true was removed, as it is implied by nilBehavior: %s", NilBehaviorZero)
+	}
+}
+
+// labelsFromPath converts the string paths of the labelsFromPath field of node.
+func (m *migrator) labelsFromPath(node *yaml.Node, path string) {
+	labels := mappingValue(node, "labelsFromPath")
+	if labels == nil || labels.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(labels.Content); i += 2 {
+		m.path(labels, labels.Content[i].Value, path+".labelsFromPath")
+	}
+}
+
+// path converts the field of node to a list if it is a string path.
+func (m *migrator) path(node *yaml.Node, field, path string) {
+	key, value := mappingField(node, field)
+	if value == nil || value.Kind != yaml.ScalarNode || value.Tag != "!!str" || value.Value == "" {
+		return
+	}
+	original := value.Value
+	list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, p := range splitPath(original) {
+		list.Content = append(list.Content, scalarNode(p))
+	}
+	*value = *list
+	m.add(key, path+"."+field, "path %q was converted to a list", original)
+}
+
+// splitPath splits a path like ".status.conditions[type=Ready].status" into
+// its parts. List indexes like "[0]" become plain indexes, and JSONPath
+// filters like "[?(@.type=='Ready')]" become list lookups like "[type=Ready]".
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	var parts []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+	}
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				current.WriteString(path[i:])
+				i = len(path)
+				continue
+			}
+			parts = append(parts, listPart(path[i+1:i+end]))
+			i += end
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return parts
+}
+
+func listPart(part string) string {
+	if _, err := strconv.Atoi(part); err == nil {
+		return part
+	}
+	if strings.HasPrefix(part, "?(@.") && strings.HasSuffix(part, ")") {
+		part = strings.Replace(part[len("?(@."):len(part)-1], "==", "=", 1)
+	}
+	part = strings.NewReplacer(`"`, "", "'", "", " ", "").Replace(part)
+	return "[" + part + "]"
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// mappingField returns the key and value nodes of a field of a mapping node.
+func mappingField(node *yaml.Node, field string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == field {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func mappingValue(node *yaml.Node, field string) *yaml.Node {
+	_, value := mappingField(node, field)
+	return value
+}
+
+// removeMappingField removes a field of a mapping node and returns its key
+// and value nodes.
+func removeMappingField(node *yaml.Node, field string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == field {
+			key, value := node.Content[i], node.Content[i+1]
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return key, value
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	config := `kind: CustomResourceStateMetrics
+spec:
+  resources:
+    - groupVersionKind: {group: example.com, version: v1, kind: Foo}
+      labelsFromPath:
+        name: metadata.name
+      metrics:
+        # The number of replicas.
+        - name: replicas
+          help: Replicas
+          each:
+            path: .spec.replicas
+            nilIsZero: true
+        - name: ready
+          help: Ready
+          each:
+            type: gauge
+            gauge:
+              path: status.conditions[?(@.type=="Ready")]
+              valueFrom: [status]
+              nilIsZero: false
+        - name: phase
+          help: Phase
+          each:
+            type: StateSet
+            stateSet:
+              path: [status]
+              labelName: phase
+              valueFrom: [phase]
+              list: [Running, Failed]
+`
+	var out bytes.Buffer
+	migrations, err := Migrate(strings.NewReader(config), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.String())
+	}
+	assert.Equal(t, []string{
+		`spec.resources[0].labelsFromPath.name: path "metadata.name" was converted to a list`,
+		`spec.resources[0].metrics[0].each: fields of each without a type were moved to each.gauge`,
+		`spec.resources[0].metrics[0].each.gauge.path: path ".spec.replicas" was converted to a list`,
+		`spec.resources[0].metrics[0].each.gauge.nilIsZero: nilIsZero: true was replaced by nilBehavior: zero`,
+		`spec.resources[0].metrics[1].each.type: type "gauge" was renamed to "Gauge"`,
+		`spec.resources[0].metrics[1].each.gauge.path: path "status.conditions[?(@.type==\"Ready\")]" was converted to a list`,
+		`spec.resources[0].metrics[1].each.gauge: nilIsZero: false was removed, as it is the default`,
+	}, got)
+
+	migrated := out.String()
+	for _, want := range []string{
+		"# The number of replicas.",
+		"# migrated: fields of each without a type were moved to each.gauge\n            type: Gauge\n            gauge:",
+		"path: [spec, replicas]",
+		"nilBehavior: zero",
+		`path: [status, conditions, '[type=Ready]']`,
+		"name: [metadata, name]",
+	} {
+		assert.Contains(t, migrated, want)
+	}
+	assert.NotRegexp(t, `(?m)^\s*nilIsZero:`, migrated)
+
+	// The migrated configuration is valid and migrating it again changes nothing.
+	for _, f := range Validate(strings.NewReader(migrated)).Findings {
+		assert.NotEqual(t, SeverityError, f.Severity, f.String())
+	}
+	migrations, err = Migrate(strings.NewReader(migrated), &out)
+	assert.NoError(t, err)
+	assert.Empty(t, migrations)
+}
+
+func TestSplitPath(t *testing.T) {
+	for path, want := range map[string][]string{
+		"spec.replicas":                           {"spec", "replicas"},
+		"$.status.conditions[0].type":             {"status", "conditions", "0", "type"},
+		".status.conditions[type=Ready].status":   {"status", "conditions", "[type=Ready]", "status"},
+		"status.conditions[?(@.type=='Ready')]":   {"status", "conditions", "[type=Ready]"},
+		`status.conditions[?(@.type == "Ready")]`: {"status", "conditions", "[type=Ready]"},
+	} {
+		assert.Equal(t, want, splitPath(path), path)
+	}
+}
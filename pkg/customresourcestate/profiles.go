@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+)
+
+// ProfileVerticalPodAutoscaler is the profile of the VerticalPodAutoscaler
+// metrics, which replaces the deprecated verticalpodautoscalers collector.
+const ProfileVerticalPodAutoscaler = "verticalpodautoscaler"
+
+//go:embed profiles/*.yaml
+var profiles embed.FS
+
+// Profiles returns the names of the profiles compiled into the binary.
+func Profiles() []string {
+	entries, _ := profiles.ReadDir("profiles")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the configuration of a profile compiled into the binary.
+func Profile(name string) ([]byte, error) {
+	data, err := profiles.ReadFile("profiles/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown Custom Resource State Metrics profile %q", name)
+	}
+	return data, nil
+}
+
+// FromProfile returns the factories of the resources of a profile.
+func FromProfile(name string) ([]customresource.RegistryFactory, error) {
+	data, err := Profile(name)
+	if err != nil {
+		return nil, err
+	}
+	factories, err := FromConfig(yaml.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return factories, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func TestProfilesValid(t *testing.T) {
+	for _, name := range Profiles() {
+		data, err := Profile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report := Validate(bytes.NewReader(data))
+		for _, f := range report.Findings {
+			t.Errorf("profile %s: unexpected finding %s", name, f)
+		}
+	}
+	if _, err := Profile("unknown"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+// TestProfiles generates the metrics of the objects in
+// testdata/profiles/<profile>.yaml and compares them to the ones in
+// testdata/profiles/<profile>.prom. Each metric of a profile has to be covered.
+func TestProfiles(t *testing.T) {
+	for _, name := range Profiles() {
+		t.Run(name, func(t *testing.T) {
+			factories, err := FromProfile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(filepath.Join("testdata", "profiles", name+".yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(filepath.Join("testdata", "profiles", name+".prom"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var objects []*unstructured.Unstructured
+			decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+			for {
+				u := &unstructured.Unstructured{}
+				if err := decoder.Decode(&u.Object); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				objects = append(objects, u)
+			}
+
+			var got strings.Builder
+			for _, f := range factories {
+				gvk := f.(*customResourceMetrics).GroupVersionKind
+				for _, g := range f.MetricFamilyGenerators(nil, nil) {
+					covered := false
+					for _, u := range objects {
+						if u.GroupVersionKind() != gvk {
+							continue
+						}
+						family := g.Generate(u)
+						covered = covered || len(family.Metrics) > 0
+						got.Write(family.ByteSlice())
+					}
+					if !covered {
+						t.Errorf("no samples of %s generated from the test objects", g.Name)
+					}
+				}
+			}
+			assert.Equal(t, string(want), got.String())
+		})
+	}
+}
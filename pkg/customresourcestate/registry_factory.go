@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/klog/v2"
 
@@ -41,11 +42,22 @@ func compile(resource Resource) ([]compiledFamily, error) {
 	resource.CommonLabels[customResourceState+"_group"] = resource.GroupVersionKind.Group
 	resource.CommonLabels[customResourceState+"_version"] = resource.GroupVersionKind.Version
 	resource.CommonLabels[customResourceState+"_kind"] = resource.GroupVersionKind.Kind
+	filter, err := compileLabelFilter(resource)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]metric.Type{}
 	for _, f := range resource.Metrics {
 		family, err := compileFamily(f, resource)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", f.Name, err)
 		}
+		// Metrics of the same name are generated as one metric family.
+		if t, ok := types[family.Name]; ok && t != family.Each.Type() {
+			return nil, fmt.Errorf("%s: type %s conflicts with type %s of the metric of the same name", f.Name, family.Each.Type(), t)
+		}
+		types[family.Name] = family.Each.Type()
+		family.LabelFilter = filter
 		families = append(families, *family)
 	}
 	return families, nil
@@ -56,7 +68,7 @@ func compileCommon(c MetricMeta) (*compiledCommon, error) {
 	if err != nil {
 		return nil, fmt.Errorf("path: %w", err)
 	}
-	eachLabelsFromPath, err := compilePaths(c.LabelsFromPath)
+	eachLabelsFromPath, err := compileLabelPaths(c.LabelsFromPath, c.ListSeparator)
 	if err != nil {
 		return nil, fmt.Errorf("labelsFromPath: %w", err)
 	}
@@ -74,7 +86,7 @@ func compileFamily(f Generator, resource Resource) (*compiledFamily, error) {
 		return nil, fmt.Errorf("compiling metric: %w", err)
 	}
 
-	labelsFromPath, err := compilePaths(labels.LabelsFromPath)
+	labelsFromPath, err := compileLabelPaths(labels.LabelsFromPath, labels.ListSeparator)
 	if err != nil {
 		return nil, fmt.Errorf("labelsFromPath: %w", err)
 	}
@@ -102,6 +114,46 @@ func fullName(resource Resource, f Generator) string {
 	return strings.Join(parts, "_")
 }
 
+// compileLabelFilter returns the label filter configured for the resource, or nil if none is configured.
+func compileLabelFilter(resource Resource) (*labelFilter, error) {
+	if len(resource.LabelAllowlist) > 0 && len(resource.LabelDenylist) > 0 {
+		return nil, errors.New("labelAllowlist and labelDenylist are mutually exclusive")
+	}
+	if len(resource.LabelAllowlist) == 0 && len(resource.LabelDenylist) == 0 {
+		return nil, nil
+	}
+	filter := &labelFilter{
+		names:     make(map[string]struct{}),
+		allowlist: len(resource.LabelAllowlist) > 0,
+	}
+	for _, name := range append(resource.LabelAllowlist, resource.LabelDenylist...) {
+		filter.names[name] = struct{}{}
+	}
+	return filter, nil
+}
+
+// labelFilter removes labels from generated metrics based on a resource wide allow- or denylist.
+type labelFilter struct {
+	names     map[string]struct{}
+	allowlist bool
+}
+
+// Apply removes all filtered labels from the given labels. The GVK labels are never removed.
+func (l *labelFilter) Apply(labels map[string]string) {
+	if l == nil {
+		return
+	}
+	for k := range labels {
+		switch k {
+		case customResourceState + "_group", customResourceState + "_version", customResourceState + "_kind":
+			continue
+		}
+		if _, ok := l.names[k]; ok != l.allowlist {
+			delete(labels, k)
+		}
+	}
+}
+
 func compilePaths(paths map[string][]string) (result map[string]valuePath, err error) {
 	result = make(map[string]valuePath)
 	for k, v := range paths {
@@ -113,6 +165,45 @@ func compilePaths(paths map[string][]string) (result map[string]valuePath, err e
 	return result, nil
 }
 
+// compileLabelPaths compiles the paths of labelsFromPath. If separator is set, list values are joined into a single
+// label value instead of being rendered as a Go list.
+func compileLabelPaths(paths map[string][]string, separator string) (map[string]valuePath, error) {
+	result, err := compilePaths(paths)
+	if err != nil || separator == "" {
+		return result, err
+	}
+	for k, p := range result {
+		if strings.HasPrefix(k, "*") {
+			continue
+		}
+		result[k] = append(p, joinListOp(separator))
+	}
+	return result, nil
+}
+
+// joinListOp returns a pathOp which joins a list of scalar values using separator.
+// Any other value is returned as is.
+func joinListOp(separator string) pathOp {
+	return pathOp{
+		part: fmt.Sprintf("join(%q)", separator),
+		op: func(m interface{}) interface{} {
+			s, ok := m.([]interface{})
+			if !ok {
+				return m
+			}
+			values := make([]string, 0, len(s))
+			for _, v := range s {
+				switch v.(type) {
+				case map[string]interface{}, []interface{}:
+					return m
+				}
+				values = append(values, fmt.Sprintf("%v", v))
+			}
+			return strings.Join(values, separator)
+		},
+	}
+}
+
 type compiledEach compiledMetric
 
 type compiledCommon struct {
@@ -159,10 +250,19 @@ func newCompiledMetric(m Metric) (compiledMetric, error) {
 		if err != nil {
 			return nil, fmt.Errorf("each.gauge.valueFrom: %w", err)
 		}
+		switch m.Gauge.NilBehavior {
+		case "", NilBehaviorZero, NilBehaviorSkip, NilBehaviorNaN:
+		default:
+			return nil, fmt.Errorf("each.gauge.nilBehavior: unknown value %q", m.Gauge.NilBehavior)
+		}
+		if m.Gauge.NilIsZero && m.Gauge.NilBehavior != "" && m.Gauge.NilBehavior != NilBehaviorZero {
+			return nil, fmt.Errorf("each.gauge: nilIsZero conflicts with nilBehavior %q", m.Gauge.NilBehavior)
+		}
 		return &compiledGauge{
 			compiledCommon: *cc,
 			ValueFrom:      valueFromPath,
 			NilIsZero:      m.Gauge.NilIsZero,
+			NilBehavior:    m.Gauge.NilBehavior,
 			labelFromKey:   m.Gauge.LabelFromKey,
 		}, nil
 	case MetricTypeInfo:
@@ -206,6 +306,7 @@ type compiledGauge struct {
 	compiledCommon
 	ValueFrom    valuePath
 	NilIsZero    bool
+	NilBehavior  NilBehavior
 	labelFromKey string
 }
 
@@ -222,6 +323,9 @@ func (c *compiledGauge) Values(v interface{}) (result []eachValue, errs []error)
 				onError(fmt.Errorf("[%s]: %w", key, err))
 				continue
 			}
+			if ev == nil {
+				continue
+			}
 			if _, ok := ev.Labels[c.labelFromKey]; ok {
 				onError(fmt.Errorf("labelFromKey (%s) generated labels conflict with labelsFromPath, consider renaming it", c.labelFromKey))
 				continue
@@ -239,6 +343,9 @@ func (c *compiledGauge) Values(v interface{}) (result []eachValue, errs []error)
 				onError(fmt.Errorf("[%d]: %w", i, err))
 				continue
 			}
+			if value == nil {
+				continue
+			}
 			addPathLabels(it, c.LabelFromPath(), value.Labels)
 			result = append(result, *value)
 		}
@@ -248,6 +355,9 @@ func (c *compiledGauge) Values(v interface{}) (result []eachValue, errs []error)
 			onError(err)
 			break
 		}
+		if value == nil {
+			break
+		}
 		addPathLabels(v, c.LabelFromPath(), value.Labels)
 		result = append(result, *value)
 	}
@@ -385,9 +495,20 @@ func less(a, b map[string]string) bool {
 	return len(aKeys) < len(bKeys)
 }
 
+// value returns the value of the gauge for it. A nil value without an error is returned if the
+// sample should be skipped.
 func (c compiledGauge) value(it interface{}) (*eachValue, error) {
 	labels := make(map[string]string)
-	value, err := toFloat64(c.ValueFrom.Get(it), c.NilIsZero)
+	got := c.ValueFrom.Get(it)
+	if got == nil {
+		switch {
+		case c.NilBehavior == NilBehaviorSkip:
+			return nil, nil
+		case c.NilBehavior == NilBehaviorNaN:
+			return &eachValue{Labels: labels, Value: math.NaN()}, nil
+		}
+	}
+	value, err := toFloat64(got, c.NilIsZero || c.NilBehavior == NilBehaviorZero)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", c.ValueFrom, err)
 	}
@@ -412,7 +533,7 @@ func (e eachValue) ToMetric() *metric.Metric {
 	// make it deterministic
 	sort.Strings(keys)
 	for _, key := range keys {
-		values = append(values, e.Labels[key])
+		values = append(values, metric.TruncateLabelValue(e.Labels[key]))
 	}
 	return &metric.Metric{
 		LabelKeys:   keys,
@@ -427,6 +548,7 @@ type compiledFamily struct {
 	Each          compiledEach
 	Labels        map[string]string
 	LabelFromPath map[string]valuePath
+	LabelFilter   *labelFilter
 	ErrorLogV     klog.Level
 }
 
@@ -573,14 +695,27 @@ func compilePath(path []string) (out valuePath, _ error) {
 	return out, nil
 }
 
-func famGen(f compiledFamily) generator.FamilyGenerator {
-	errLog := klog.V(f.ErrorLogV)
+// famGen returns the generator of a metric family. Families of the same name
+// are generated as one family, using the type and help text of the first one.
+func famGen(fs ...compiledFamily) generator.FamilyGenerator {
+	errLogs := make([]klog.Verbose, len(fs))
+	for i, f := range fs {
+		errLogs[i] = klog.V(f.ErrorLogV)
+	}
 	return generator.FamilyGenerator{
-		Name: f.Name,
-		Type: f.Each.Type(),
-		Help: f.Help,
+		Name: fs[0].Name,
+		Type: fs[0].Each.Type(),
+		Help: fs[0].Help,
 		GenerateFunc: func(obj interface{}) *metric.Family {
-			return generate(obj.(*unstructured.Unstructured), f, errLog)
+			u := obj.(*unstructured.Unstructured)
+			if len(fs) == 1 {
+				return generate(u, fs[0], errLogs[0])
+			}
+			family := &metric.Family{}
+			for i, f := range fs {
+				family.Metrics = append(family.Metrics, generate(u, f, errLogs[i]).Metrics...)
+			}
+			return family
 		},
 	}
 }
@@ -598,6 +733,7 @@ func generate(u *unstructured.Unstructured, f compiledFamily, errLog klog.Verbos
 
 	for _, v := range values {
 		v.DefaultLabels(baseLabels)
+		f.LabelFilter.Apply(v.Labels)
 		metrics = append(metrics, v.ToMetric())
 	}
 	klog.V(10).InfoS("Produced metrics for", "compiledFamilyName", f.Name, "metricsLength", len(metrics), "unstructuredName", u.GetName())
@@ -638,6 +774,13 @@ func toFloat64(value interface{}, nilIsZero bool) (float64, error) {
 		if t, e := time.Parse(time.RFC3339, value.(string)); e == nil {
 			return float64(t.Unix()), nil
 		}
+		if f, e := strconv.ParseFloat(value.(string), 64); e == nil {
+			return f, nil
+		}
+		// The string contains a quantity, e.g. a resource request.
+		if q, e := resource.ParseQuantity(value.(string)); e == nil {
+			return q.AsApproximateFloat64(), nil
+		}
 		return strconv.ParseFloat(value.(string), 64)
 	case byte:
 		v = float64(vv)
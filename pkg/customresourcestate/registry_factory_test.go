@@ -18,6 +18,7 @@ package customresourcestate
 
 import (
 	"encoding/json"
+	"math"
 	"reflect"
 	"testing"
 
@@ -36,6 +37,11 @@ func init() {
 		"spec": Obj{
 			"replicas": 1,
 			"version":  "v0.0.0",
+			"tags":     Array{"a", "b", "c"},
+			"resources": Obj{
+				"cpu":    "250m",
+				"memory": "1Gi",
+			},
 			"order": Array{
 				Obj{
 					"id":    1,
@@ -185,6 +191,15 @@ func Test_values(t *testing.T) {
 			newEachValue(t, 45, "name", "a"),
 			newEachValue(t, 66, "name", "b"),
 		}},
+		{name: "quantity", each: &compiledGauge{
+			compiledCommon: compiledCommon{
+				path: mustCompilePath(t, "spec", "resources"),
+			},
+			labelFromKey: "resource",
+		}, wantResult: []eachValue{
+			newEachValue(t, 0.25, "resource", "cpu"),
+			newEachValue(t, 1073741824, "resource", "memory"),
+		}},
 		{name: "timestamp", each: &compiledGauge{
 			compiledCommon: compiledCommon{
 				path: mustCompilePath(t, "metadata", "creationTimestamp"),
@@ -200,6 +215,20 @@ func Test_values(t *testing.T) {
 		}, wantResult: []eachValue{
 			newEachValue(t, 0),
 		}},
+		{name: "nil skip", each: &compiledGauge{
+			compiledCommon: compiledCommon{
+				path: mustCompilePath(t, "spec", "paused"),
+			},
+			NilBehavior: NilBehaviorSkip,
+		}, wantResult: nil},
+		{name: "nil zero", each: &compiledGauge{
+			compiledCommon: compiledCommon{
+				path: mustCompilePath(t, "spec", "paused"),
+			},
+			NilBehavior: NilBehaviorZero,
+		}, wantResult: []eachValue{
+			newEachValue(t, 0),
+		}},
 		{name: "info", each: &compiledInfo{
 			compiledCommon: compiledCommon{
 				labelFromPath: map[string]valuePath{
@@ -243,6 +272,67 @@ func Test_values(t *testing.T) {
 	}
 }
 
+func Test_values_nilBehaviorNaN(t *testing.T) {
+	each := &compiledGauge{
+		compiledCommon: compiledCommon{
+			path: mustCompilePath(t, "spec", "paused"),
+		},
+		NilBehavior: NilBehaviorNaN,
+	}
+	gotResult, gotErrors := scrapeValuesFor(each, cr)
+	assert.Empty(t, gotErrors)
+	assert.Len(t, gotResult, 1)
+	assert.True(t, math.IsNaN(gotResult[0].Value))
+}
+
+func Test_newCompiledMetric_nilBehavior(t *testing.T) {
+	gauge := func(nilIsZero bool, nilBehavior NilBehavior) Metric {
+		return Metric{Type: MetricTypeGauge, Gauge: &MetricGauge{NilIsZero: nilIsZero, NilBehavior: nilBehavior}}
+	}
+	_, err := newCompiledMetric(gauge(true, NilBehaviorZero))
+	assert.NoError(t, err)
+	_, err = newCompiledMetric(gauge(true, NilBehaviorNaN))
+	assert.Error(t, err)
+	_, err = newCompiledMetric(gauge(false, "foo"))
+	assert.Error(t, err)
+}
+
+func Test_compileLabelPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		paths     map[string][]string
+		separator string
+		want      map[string]string
+	}{
+		{name: "no separator", paths: map[string][]string{
+			"tags": {"spec", "tags"},
+		}, want: map[string]string{
+			"tags": "[a b c]",
+		}},
+		{name: "separator", paths: map[string][]string{
+			"tags":    {"spec", "tags"},
+			"version": {"spec", "version"},
+		}, separator: ",", want: map[string]string{
+			"tags":    "a,b,c",
+			"version": "v0.0.0",
+		}},
+		{name: "list of objects", paths: map[string][]string{
+			"order": {"spec", "order"},
+		}, separator: ",", want: map[string]string{
+			"order": "[map[id:1 value:true] map[id:3 value:false]]",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := compileLabelPaths(tt.paths, tt.separator)
+			assert.NoError(t, err)
+			m := make(map[string]string)
+			addPathLabels(cr, paths, m)
+			assert.Equal(t, tt.want, m)
+		})
+	}
+}
+
 func Test_compiledFamily_BaseLabels(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -299,6 +389,52 @@ func Test_eachValue_DefaultLabels(t *testing.T) {
 	}
 }
 
+func Test_labelFilter_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource Resource
+		labels   map[string]string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "no filter",
+			resource: Resource{},
+			labels:   map[string]string{"foo": "bar", "baz": "qux"},
+			want:     map[string]string{"foo": "bar", "baz": "qux"},
+		},
+		{
+			name:     "allowlist",
+			resource: Resource{LabelAllowlist: []string{"foo"}},
+			labels:   map[string]string{"foo": "bar", "baz": "qux", "customresource_kind": "Foo"},
+			want:     map[string]string{"foo": "bar", "customresource_kind": "Foo"},
+		},
+		{
+			name:     "denylist",
+			resource: Resource{LabelDenylist: []string{"foo", "customresource_kind"}},
+			labels:   map[string]string{"foo": "bar", "baz": "qux", "customresource_kind": "Foo"},
+			want:     map[string]string{"baz": "qux", "customresource_kind": "Foo"},
+		},
+		{
+			name:     "allowlist and denylist",
+			resource: Resource{LabelAllowlist: []string{"foo"}, LabelDenylist: []string{"baz"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := compileLabelFilter(tt.resource)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			filter.Apply(tt.labels)
+			assert.Equal(t, tt.want, tt.labels)
+		})
+	}
+}
+
 func Test_eachValue_ToMetric(t *testing.T) {
 	assert.Equal(t, &metric.Metric{
 		Value:       123,
@@ -0,0 +1,272 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is the severity of a validation finding.
+type Severity string
+
+// Supported severities.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+var (
+	metricNameRegex           = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	metricNameConventionRegex = regexp.MustCompile(`^[a-z_:][a-z0-9_:]*$`)
+	labelNameRegex            = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	// highCardinalityFields are object fields whose values are unique per object or change on every update.
+	highCardinalityFields = map[string]bool{
+		"uid":               true,
+		"resourceVersion":   true,
+		"generation":        true,
+		"managedFields":     true,
+		"creationTimestamp": true,
+	}
+)
+
+// Finding is a single result of a configuration validation.
+type Finding struct {
+	Severity Severity
+	// Resource is the GroupVersionKind of the resource the finding refers to, if any.
+	Resource string
+	// Metric is the name of the metric the finding refers to, if any.
+	Metric  string
+	Message string
+}
+
+func (f Finding) String() string {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(string(f.Severity)))
+	if f.Resource != "" {
+		b.WriteString(" " + f.Resource)
+	}
+	if f.Metric != "" {
+		b.WriteString(" " + f.Metric)
+	}
+	b.WriteString(": " + f.Message)
+	return b.String()
+}
+
+// Report is the result of a configuration validation.
+type Report struct {
+	Findings []Finding
+}
+
+// HasErrors returns true if the report contains at least one error.
+func (r *Report) HasErrors() bool {
+	return r.count(SeverityError) > 0
+}
+
+// HasWarnings returns true if the report contains at least one warning.
+func (r *Report) HasWarnings() bool {
+	return r.count(SeverityWarning) > 0
+}
+
+// ExitCode returns the exit code of the validate subcommand for the report: 1 if it contains errors, 2 if it
+// contains only warnings and strict is set, and 0 otherwise.
+func (r *Report) ExitCode(strict bool) int {
+	switch {
+	case r.HasErrors():
+		return 1
+	case r.HasWarnings() && strict:
+		return 2
+	}
+	return 0
+}
+
+// Write writes a human readable representation of the report to w.
+func (r *Report) Write(w io.Writer) error {
+	for _, f := range r.Findings {
+		if _, err := fmt.Fprintln(w, f.String()); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d error(s), %d warning(s)\n", r.count(SeverityError), r.count(SeverityWarning))
+	return err
+}
+
+func (r *Report) count(s Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == s {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) add(s Severity, resource, metric, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, Finding{
+		Severity: s,
+		Resource: resource,
+		Metric:   metric,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate performs a static validation of a Custom Resource State configuration. Besides the checks done when
+// loading the configuration, it reports unknown fields, naming convention violations, duplicate metrics and
+// label configurations which likely lead to a high cardinality.
+func Validate(r io.Reader) *Report {
+	report := &Report{}
+
+	var config struct {
+		Kind    string `yaml:"kind"`
+		Metrics `yaml:",inline"`
+	}
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		report.add(SeverityError, "", "", "failed to parse configuration: %v", err)
+		return report
+	}
+	if len(config.Spec.Resources) == 0 {
+		report.add(SeverityWarning, "", "", "no resources configured")
+	}
+
+	resourceNames := map[string]string{}
+	metricNames := map[string]string{}
+	metricTypes := map[string]MetricType{}
+	metricCommonLabels := map[string][]map[string]string{}
+	for _, resource := range config.Spec.Resources {
+		gvk := resource.GroupVersionKind
+		res := fmt.Sprintf("%s/%s, Kind=%s", gvk.Group, gvk.Version, gvk.Kind)
+		if gvk.Version == "" || gvk.Kind == "" {
+			report.add(SeverityError, res, "", "groupVersionKind requires version and kind to be set")
+		}
+		if other, ok := resourceNames[resource.GetResourceName()]; ok {
+			report.add(SeverityError, res, "", "resource %s is already configured by %s", resource.GetResourceName(), other)
+		}
+		resourceNames[resource.GetResourceName()] = res
+
+		if _, err := NewCustomResourceMetrics(resource); err != nil {
+			report.add(SeverityError, res, "", "%v", err)
+		}
+		validateLabels(report, res, "", resource.Labels)
+		if len(resource.Metrics) == 0 {
+			report.add(SeverityWarning, res, "", "no metrics configured")
+		}
+
+		for _, g := range resource.Metrics {
+			name := fullName(resource, g)
+			if !metricNameRegex.MatchString(name) {
+				report.add(SeverityError, res, name, "invalid metric name")
+			} else if !metricNameConventionRegex.MatchString(name) {
+				report.add(SeverityWarning, res, name, "metric names should be lowercase snake_case")
+			}
+			if other, ok := metricNames[name]; ok {
+				switch {
+				case other != res:
+					report.add(SeverityError, res, name, "duplicate metric, already defined by %s", other)
+				case metricTypes[name] == g.Each.Type && slices.ContainsFunc(metricCommonLabels[name], func(l map[string]string) bool {
+					return maps.Equal(l, g.Labels.CommonLabels)
+				}):
+					// Metrics of the same name are merged into one family, which only works if they are
+					// distinguished by their commonLabels. Conflicting types are reported when loading the resource.
+					report.add(SeverityError, res, name, "duplicate metric, already defined by the same resource with the same commonLabels")
+				}
+			} else {
+				metricTypes[name] = g.Each.Type
+			}
+			metricNames[name] = res
+			metricCommonLabels[name] = append(metricCommonLabels[name], g.Labels.CommonLabels)
+			if g.Help == "" {
+				report.add(SeverityWarning, res, name, "no help text")
+			}
+			validateLabels(report, res, name, g.Labels)
+			validateEach(report, res, name, g.Each)
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Severity == SeverityError && report.Findings[j].Severity != SeverityError
+	})
+	return report
+}
+
+func validateEach(report *Report, res, name string, each Metric) {
+	var meta *MetricMeta
+	switch {
+	case each.Gauge != nil:
+		meta = &each.Gauge.MetricMeta
+		validateLabelName(report, res, name, each.Gauge.LabelFromKey)
+	case each.Info != nil:
+		meta = &each.Info.MetricMeta
+		validateLabelName(report, res, name, each.Info.LabelFromKey)
+	case each.StateSet != nil:
+		meta = &each.StateSet.MetricMeta
+		validateLabelName(report, res, name, each.StateSet.LabelName)
+		if len(each.StateSet.List) == 0 {
+			report.add(SeverityError, res, name, "stateSet requires a non-empty list")
+		}
+		if len(each.StateSet.List) > 20 {
+			report.add(SeverityWarning, res, name, "stateSet list has %d entries, each of them creates a series per object", len(each.StateSet.List))
+		}
+	}
+	if meta != nil {
+		validateLabels(report, res, name, Labels{LabelsFromPath: meta.LabelsFromPath})
+	}
+}
+
+func validateLabels(report *Report, res, name string, labels Labels) {
+	for _, k := range sortedKeys(labels.CommonLabels) {
+		validateLabelName(report, res, name, k)
+	}
+	for _, k := range sortedKeys(labels.LabelsFromPath) {
+		path := labels.LabelsFromPath[k]
+		if strings.HasPrefix(k, "*") {
+			report.add(SeverityWarning, res, name, "label %q copies all fields of %v into labels", k, path)
+			continue
+		}
+		validateLabelName(report, res, name, k)
+		if len(path) > 0 && highCardinalityFields[path[len(path)-1]] {
+			report.add(SeverityWarning, res, name, "label %q from %v has a high cardinality", k, path)
+		}
+	}
+}
+
+func validateLabelName(report *Report, res, name, label string) {
+	if label == "" {
+		return
+	}
+	if !labelNameRegex.MatchString(label) || strings.HasPrefix(label, "__") {
+		report.add(SeverityError, res, name, "invalid label name %q", label)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresourcestate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name: "valid",
+			config: `
+kind: CustomResourceStateMetrics
+spec:
+  resources:
+    - groupVersionKind:
+        group: myteam.io
+        kind: Foo
+        version: v1
+      labelsFromPath:
+        name: [metadata, name]
+      metrics:
+        - name: uptime
+          help: Foo uptime
+          each:
+            type: Gauge
+            gauge:
+              path: [status, uptime]
+`,
+		},
+		{
+			name:   "unknown field",
+			config: "spec:\n  resourcess: []\n",
+			want:   []string{"ERROR: failed to parse configuration: yaml: unmarshal errors:\n  line 2: field resourcess not found in type customresourcestate.MetricsSpec"},
+		},
+		{
+			name: "naming and duplicates",
+			config: `
+spec:
+  resources:
+    - groupVersionKind:
+        group: myteam.io
+        kind: Foo
+        version: v1
+      metrics:
+        - name: Uptime
+          help: Foo uptime
+          each:
+            type: Gauge
+            gauge:
+              path: [status, uptime]
+        - name: Uptime
+          help: Foo uptime
+          labelsFromPath:
+            uid: [metadata, uid]
+            "invalid-label": [metadata, name]
+          each:
+            type: Gauge
+            gauge:
+              path: [status, uptime]
+`,
+			want: []string{
+				"ERROR myteam.io/v1, Kind=Foo kube_customresource_Uptime: duplicate metric, already defined by the same resource with the same commonLabels",
+				`ERROR myteam.io/v1, Kind=Foo kube_customresource_Uptime: invalid label name "invalid-label"`,
+				"WARNING myteam.io/v1, Kind=Foo kube_customresource_Uptime: metric names should be lowercase snake_case",
+				"WARNING myteam.io/v1, Kind=Foo kube_customresource_Uptime: metric names should be lowercase snake_case",
+				`WARNING myteam.io/v1, Kind=Foo kube_customresource_Uptime: label "uid" from [metadata uid] has a high cardinality`,
+			},
+		},
+		{
+			name: "duplicates",
+			config: `
+spec:
+  resources:
+    - groupVersionKind:
+        group: myteam.io
+        kind: Foo
+        version: v1
+      metricNamePrefix: myteam
+      metrics:
+        - name: phase
+          help: Phase
+          each:
+            type: Gauge
+            gauge:
+              path: [status, phase]
+        - name: phase
+          help: Phase
+          each:
+            type: StateSet
+            stateSet:
+              labelName: phase
+              path: [status, phase]
+              list: [Active]
+    - groupVersionKind:
+        group: myteam.io
+        kind: Bar
+        version: v1
+      metricNamePrefix: myteam
+      metrics:
+        - name: phase
+          help: Phase
+          each:
+            type: Gauge
+            gauge:
+              path: [status, phase]
+`,
+			want: []string{
+				"ERROR myteam.io/v1, Kind=Foo: phase: type stateset conflicts with type gauge of the metric of the same name",
+				"ERROR myteam.io/v1, Kind=Bar myteam_phase: duplicate metric, already defined by myteam.io/v1, Kind=Foo",
+			},
+		},
+		{
+			name: "duplicate within a resource",
+			config: `
+spec:
+  resources:
+    - groupVersionKind:
+        group: myteam.io
+        kind: Foo
+        version: v1
+      metrics:
+        - name: x
+          help: X
+          each:
+            type: Gauge
+            gauge:
+              path: [status, x]
+        - name: x
+          help: X
+          each:
+            type: Gauge
+            gauge:
+              path: [status, y]
+`,
+			want: []string{
+				"ERROR myteam.io/v1, Kind=Foo kube_customresource_x: duplicate metric, already defined by the same resource with the same commonLabels",
+			},
+		},
+		{
+			name: "merged within a resource",
+			config: `
+spec:
+  resources:
+    - groupVersionKind:
+        group: myteam.io
+        kind: Foo
+        version: v1
+      metrics:
+        - name: x
+          help: X
+          commonLabels:
+            field: x
+          each:
+            type: Gauge
+            gauge:
+              path: [status, x]
+        - name: x
+          help: X
+          commonLabels:
+            field: y
+          each:
+            type: Gauge
+            gauge:
+              path: [status, y]
+`,
+		},
+		{
+			name: "invalid metric",
+			config: `
+spec:
+  resources:
+    - groupVersionKind:
+        group: myteam.io
+        kind: Foo
+        version: v1
+      metrics:
+        - name: phase
+          help: Foo phase
+          each:
+            type: StateSet
+            stateSet:
+              labelName: phase
+              path: [status, phase]
+`,
+			want: []string{
+				"ERROR myteam.io/v1, Kind=Foo kube_customresource_phase: stateSet requires a non-empty list",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Validate(strings.NewReader(tt.config))
+			var got []string
+			for _, f := range report.Findings {
+				got = append(got, f.String())
+			}
+			assert.Equal(t, tt.want, got)
+			wantErrors := false
+			for _, w := range tt.want {
+				wantErrors = wantErrors || strings.HasPrefix(w, "ERROR")
+			}
+			assert.Equal(t, wantErrors, report.HasErrors())
+		})
+	}
+}
+
+func TestReportExitCode(t *testing.T) {
+	const resource = `
+spec:
+  resources:
+    - groupVersionKind:
+        group: myteam.io
+        kind: Foo
+        version: v1
+      metrics:
+        - name: x
+          help: X
+          each:
+            type: Gauge
+            gauge:
+              path: [status, x]
+`
+	const metric = `        - name: x
+          each:
+            type: Gauge
+            gauge:
+              path: [status, y]
+`
+	tests := []struct {
+		name   string
+		config string
+		strict bool
+		want   int
+	}{
+		{name: "valid", config: resource, want: 0},
+		{name: "valid strict", config: resource, strict: true, want: 0},
+		{name: "warnings", config: strings.Replace(resource, "help: X", "help: \"\"", 1), want: 0},
+		{name: "warnings strict", config: strings.Replace(resource, "help: X", "help: \"\"", 1), strict: true, want: 2},
+		{name: "duplicate within a resource", config: resource + metric, want: 1},
+		{name: "duplicate within a resource strict", config: resource + metric, strict: true, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Validate(strings.NewReader(tt.config)).ExitCode(tt.strict))
+		})
+	}
+}
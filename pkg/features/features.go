@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features defines the feature gates of kube-state-metrics, which
+// govern experimental functionality.
+package features
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// NodeImageMetrics enables the opt-in kube_node_status_cached_images and
+	// kube_node_status_images_size_bytes metric families, like adding them to
+	// --metric-opt-in-list.
+	NodeImageMetrics featuregate.Feature = "NodeImageMetrics"
+
+	// PodNodeSelectors enables the opt-in kube_pod_nodeselectors metric
+	// family, like adding it to --metric-opt-in-list.
+	PodNodeSelectors featuregate.Feature = "PodNodeSelectors"
+
+	// PodWorkloadResolution exposes the workload of each pod resolved via its
+	// owner chain, like --resolve-pod-workloads.
+	PodWorkloadResolution featuregate.Feature = "PodWorkloadResolution"
+
+	// UTF8LabelNames exposes UTF-8 label names to clients negotiating them,
+	// like --utf8-label-names.
+	UTF8LabelNames featuregate.Feature = "UTF8LabelNames"
+
+	// WatchList streams the initial list of resources via watch requests,
+	// like --use-watch-list.
+	WatchList featuregate.Feature = "WatchList"
+)
+
+// Lease based sharding has no feature gate, as it is enabled by the name of
+// the group passed to --sharding-lease-group.
+var defaultFeatures = map[featuregate.Feature]featuregate.FeatureSpec{
+	NodeImageMetrics:      {Default: false, PreRelease: featuregate.Alpha},
+	PodNodeSelectors:      {Default: false, PreRelease: featuregate.Alpha},
+	PodWorkloadResolution: {Default: false, PreRelease: featuregate.Alpha},
+	UTF8LabelNames:        {Default: false, PreRelease: featuregate.Alpha},
+	WatchList:             {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// optInFamilies are the opt-in metric families enabled by each feature.
+var optInFamilies = map[featuregate.Feature][]string{
+	NodeImageMetrics: {"kube_node_status_cached_images", "kube_node_status_images_size_bytes"},
+	PodNodeSelectors: {"kube_pod_nodeselectors"},
+}
+
+// New returns a feature gate of the features of kube-state-metrics with the
+// given features enabled or disabled. It returns an error for unknown
+// features.
+func New(enabled map[string]bool) (featuregate.FeatureGate, error) {
+	g := featuregate.NewFeatureGate()
+	if err := g.Add(defaultFeatures); err != nil {
+		return nil, err
+	}
+	if err := g.SetFromMap(enabled); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// KnownFeatures returns the descriptions of all features for the help text of
+// --feature-gates.
+func KnownFeatures() []string {
+	g := featuregate.NewFeatureGate()
+	if err := g.Add(defaultFeatures); err != nil {
+		return nil
+	}
+	return g.KnownFeatures()
+}
+
+// OptInFamilies returns the opt-in metric families enabled by the given
+// feature gate.
+func OptInFamilies(g featuregate.FeatureGate) []string {
+	var families []string
+	for feature, names := range optInFamilies {
+		if g.Enabled(feature) {
+			families = append(families, names...)
+		}
+	}
+	sort.Strings(families)
+	return families
+}
+
+// RegisterMetrics registers the kube_state_metrics_feature_enabled metric,
+// exposing whether each feature is enabled.
+func RegisterMetrics(r prometheus.Registerer, g featuregate.FeatureGate) {
+	enabled := promauto.With(r).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kube_state_metrics_feature_enabled",
+			Help: "Whether a feature of kube-state-metrics is enabled (1) or not (0) by its name and stage.",
+		},
+		[]string{"name", "stage"},
+	)
+	for feature, spec := range defaultFeatures {
+		v := 0.0
+		if g.Enabled(feature) {
+			v = 1
+		}
+		stage := string(spec.PreRelease)
+		if stage == "" {
+			stage = "GA"
+		}
+		enabled.WithLabelValues(string(feature), stage).Set(v)
+	}
+}
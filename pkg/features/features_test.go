@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNew(t *testing.T) {
+	g, err := New(map[string]bool{string(PodNodeSelectors): true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Enabled(PodNodeSelectors) || g.Enabled(WatchList) {
+		t.Errorf("expected only %s to be enabled", PodNodeSelectors)
+	}
+	if got, want := OptInFamilies(g), []string{"kube_pod_nodeselectors"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected opt-in families %v, got %v", want, got)
+	}
+
+	r := prometheus.NewRegistry()
+	RegisterMetrics(r, g)
+	if got := testutil.CollectAndCount(r, "kube_state_metrics_feature_enabled"); got != len(defaultFeatures) {
+		t.Errorf("expected a series per feature, got %d", got)
+	}
+
+	g, err = New(map[string]bool{string(NodeImageMetrics): true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := OptInFamilies(g), []string{"kube_node_status_cached_images", "kube_node_status_images_size_bytes"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected opt-in families %v, got %v", want, got)
+	}
+
+	if _, err := New(map[string]bool{"Unknown": true}); err == nil {
+		t.Error("expected error for unknown feature")
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ksmtest runs kube-state-metrics against a fake cluster, e.g. for
+// integration tests of collectors and Custom Resource State configurations
+// outside of this repository. A Cluster is populated with objects or
+// manifests, and Render returns the metrics kube-state-metrics exposes for
+// them in the text format.
+package ksmtest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"k8s.io/kube-state-metrics/v2/internal/store"
+	"k8s.io/kube-state-metrics/v2/pkg/builder"
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// DefaultTimeout is the time Render waits for the stores to be synced if
+// Options.Timeout is not set.
+const DefaultTimeout = 10 * time.Second
+
+// Cluster is a fake cluster holding the objects kube-state-metrics lists.
+// Objects of built-in kinds are served by a fake clientset, all other objects
+// by fake dynamic clients, e.g. as custom resources or as objects of the
+// Gateway API.
+type Cluster struct {
+	// KubeClient serves the objects of built-in kinds. Objects can also be
+	// added to it directly.
+	KubeClient *fake.Clientset
+
+	customObjects []*unstructured.Unstructured
+}
+
+// NewCluster returns a Cluster holding the given objects.
+func NewCluster(objects ...runtime.Object) (*Cluster, error) {
+	c := &Cluster{KubeClient: fake.NewSimpleClientset()}
+	if err := c.Add(objects...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Add adds objects to the cluster. Unstructured objects of built-in kinds are
+// converted to their types.
+func (c *Cluster) Add(objects ...runtime.Object) error {
+	for _, obj := range objects {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			if err := c.KubeClient.Tracker().Add(obj); err != nil {
+				return err
+			}
+			continue
+		}
+		typed, err := scheme.Scheme.New(u.GroupVersionKind())
+		if err != nil {
+			if !runtime.IsNotRegisteredError(err) {
+				return err
+			}
+			c.customObjects = append(c.customObjects, u.DeepCopy())
+			continue
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+			return fmt.Errorf("failed to convert %s %s: %w", u.GetKind(), u.GetName(), err)
+		}
+		if err := c.KubeClient.Tracker().Add(typed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadManifests adds the objects of YAML or JSON manifests read from r to the
+// cluster. Multiple manifests are separated by "---".
+func (c *Cluster) LoadManifests(r io.Reader) error {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		data, err := utilyaml.ToJSON(doc)
+		if err != nil {
+			return err
+		}
+		if string(data) == "null" {
+			continue
+		}
+		obj, err := runtime.Decode(unstructured.UnstructuredJSONScheme, data)
+		if err != nil {
+			return err
+		}
+		if list, ok := obj.(*unstructured.UnstructuredList); ok {
+			for i := range list.Items {
+				if err := c.Add(&list.Items[i]); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := c.Add(obj); err != nil {
+			return err
+		}
+	}
+}
+
+// LoadManifestFiles adds the objects of the manifest files at the given paths
+// to the cluster.
+func (c *Cluster) LoadManifestFiles(paths ...string) error {
+	for _, path := range paths {
+		f, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		err = c.LoadManifests(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Options configures how kube-state-metrics is run by Render.
+type Options struct {
+	// Resources are the enabled built-in resources, options.DefaultResources
+	// if empty.
+	Resources []string
+	// Namespaces are the namespaces objects are listed in, all namespaces if
+	// empty.
+	Namespaces options.NamespaceList
+	// AllowLabels and AllowAnnotations are the labels and annotations
+	// exposed by resource, like --metric-labels-allowlist and
+	// --metric-annotations-allowlist.
+	AllowLabels      map[string][]string
+	AllowAnnotations map[string][]string
+	// CustomResourceStateConfig is a Custom Resource State configuration.
+	// The resources it configures are enabled in addition to Resources.
+	CustomResourceStateConfig string
+	// CustomResourceFactories are further custom resources, e.g. of
+	// collectors written in Go.
+	CustomResourceFactories []customresource.RegistryFactory
+	// Timeout is the time to wait for the stores to be synced,
+	// DefaultTimeout if zero.
+	Timeout time.Duration
+}
+
+// Render runs kube-state-metrics with the given options against the cluster
+// and returns the metrics of all enabled resources in the text format, once
+// all objects were listed.
+func (c *Cluster) Render(ctx context.Context, opts Options) (string, error) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	factories := append([]customresource.RegistryFactory{}, opts.CustomResourceFactories...)
+	if opts.CustomResourceStateConfig != "" {
+		f, err := customresourcestate.FromConfig(yaml.NewDecoder(strings.NewReader(opts.CustomResourceStateConfig)))
+		if err != nil {
+			return "", fmt.Errorf("failed to load Custom Resource State configuration: %w", err)
+		}
+		factories = append(factories, f...)
+	}
+	clients, err := c.customResourceClients(factories)
+	if err != nil {
+		return "", err
+	}
+	dynamicClient, err := c.dynamicClient()
+	if err != nil {
+		return "", err
+	}
+
+	resources := opts.Resources
+	if len(resources) == 0 {
+		resources = options.DefaultResources.AsSlice()
+	}
+	resources = append([]string{}, resources...)
+	for _, f := range factories {
+		resources = append(resources, f.Name())
+	}
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = options.DefaultNamespaces
+	}
+
+	b := builder.NewBuilder()
+	b.WithMetrics(prometheus.NewRegistry())
+	b.WithContext(ctx)
+	b.WithKubeClient(c.KubeClient)
+	b.WithDynamicClient(dynamicClient)
+	b.WithCustomResourceStoreFactories(factories...)
+	b.WithCustomResourceClients(clients)
+	if err := b.WithEnabledResources(resources); err != nil {
+		return "", err
+	}
+	b.WithNamespaces(namespaces)
+	b.WithSharding(0, 1)
+	b.WithAllowLabels(opts.AllowLabels)
+	b.WithAllowAnnotations(opts.AllowAnnotations)
+	b.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter())
+	b.WithGenerateStoresFunc(b.DefaultGenerateStoresFunc())
+	b.WithGenerateCustomResourceStoresFunc(b.DefaultGenerateCustomResourceStoresFunc())
+
+	writers := b.Build()
+	if err := writers.WaitForSync(ctx); err != nil {
+		return "", fmt.Errorf("failed to wait for the stores to be synced: %w", err)
+	}
+	var buf bytes.Buffer
+	for _, w := range writers {
+		if err := w.WriteAll(&buf); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// dynamicClient returns a dynamic client serving the custom objects of the
+// cluster of the kinds the built-in collectors watch as unstructured objects,
+// e.g. of the Gateway API.
+func (c *Cluster) dynamicClient() (*dynamicfake.FakeDynamicClient, error) {
+	gvrs := store.UnstructuredResources()
+	listKinds := make(map[schema.GroupVersionResource]string, len(gvrs))
+	for gvk, gvr := range gvrs {
+		listKinds[gvr] = gvk.Kind + "List"
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for _, obj := range c.customObjects {
+		gvr, ok := gvrs[obj.GroupVersionKind()]
+		if !ok {
+			continue
+		}
+		if err := client.Tracker().Create(gvr, obj.DeepCopy(), obj.GetNamespace()); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// customResourceClients returns the clients of the custom resources of the
+// factories, serving the custom objects of the cluster of their kind.
+func (c *Cluster) customResourceClients(factories []customresource.RegistryFactory) (map[string]interface{}, error) {
+	gvrs := map[schema.GroupVersionKind]schema.GroupVersionResource{}
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, f := range factories {
+		u, ok := f.ExpectedType().(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("custom resource %s is not unstructured", f.Name())
+		}
+		gvk := u.GroupVersionKind()
+		gvr := gvk.GroupVersion().WithResource(f.Name())
+		gvrs[gvk] = gvr
+		listKinds[gvr] = gvk.Kind + "List"
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for _, obj := range c.customObjects {
+		gvr, ok := gvrs[obj.GroupVersionKind()]
+		if !ok {
+			continue
+		}
+		if err := client.Tracker().Create(gvr, obj.DeepCopy(), obj.GetNamespace()); err != nil {
+			return nil, err
+		}
+	}
+	clients := make(map[string]interface{}, len(factories))
+	for _, gvr := range gvrs {
+		clients[gvr.Resource] = client.Resource(gvr)
+	}
+	return clients, nil
+}
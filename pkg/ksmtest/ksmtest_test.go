@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ksmtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRender(t *testing.T) {
+	c, err := NewCluster(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadManifestFiles("testdata/manifests.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Render(context.Background(), Options{
+		Resources:   []string{"pods", "nodes"},
+		AllowLabels: map[string][]string{"pods": {"app"}},
+		CustomResourceStateConfig: `
+spec:
+  resources:
+    - groupVersionKind: {group: example.com, version: v1, kind: Foo}
+      labelsFromPath:
+        name: [metadata, name]
+        namespace: [metadata, namespace]
+      metrics:
+        - name: replicas
+          help: Desired replicas.
+          each:
+            type: Gauge
+            gauge:
+              path: [spec, replicas]
+`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`kube_node_info{node="node1"`,
+		`kube_pod_info{namespace="default",pod="pod1",uid="abc-123"`,
+		`kube_pod_labels{namespace="default",pod="pod1",uid="abc-123",label_app="foo"} 1`,
+		`kube_customresource_replicas{customresource_group="example.com",customresource_kind="Foo",customresource_version="v1",name="foo1",namespace="default"} 3`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected metrics to contain %s, got\n%s", want, got)
+		}
+	}
+	// Namespaces are not enabled.
+	if strings.Contains(got, "kube_namespace_") {
+		t.Errorf("expected no namespace metrics, got\n%s", got)
+	}
+}
+
+func TestRenderUnstructured(t *testing.T) {
+	c, err := NewCluster()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadManifests(strings.NewReader(`
+apiVersion: gateway.networking.k8s.io/v1
+kind: GatewayClass
+metadata:
+  name: example
+spec:
+  controllerName: example.com/gateway-controller
+`)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Render(context.Background(), Options{
+		Resources: []string{"gatewayclasses", "httproutes"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `kube_gatewayclass_info{gatewayclass="example",controller="example.com/gateway-controller"} 1`
+	if !strings.Contains(got, want) {
+		t.Errorf("expected metrics to contain %s, got\n%s", want, got)
+	}
+}
@@ -29,6 +29,7 @@ type FamilyInterface interface {
 // Family represents a set of metrics with the same name and help text.
 type Family struct {
 	Name    string
+	Help    string
 	Type    Type
 	Metrics []*Metric
 }
@@ -81,7 +81,14 @@ func labelsToString(m *strings.Builder, keys, values []string) {
 
 		for i := 0; i < len(keys); i++ {
 			m.WriteByte(separator)
-			m.WriteString(keys[i])
+			if IsLegacyLabelName(keys[i]) {
+				m.WriteString(keys[i])
+			} else {
+				// UTF-8 label names are quoted.
+				m.WriteByte('"')
+				escapeString(m, keys[i])
+				m.WriteByte('"')
+			}
 			m.WriteString("=\"")
 			escapeString(m, values[i])
 			m.WriteByte('"')
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metric
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	invalidLabelCharRE = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	matchAllCap        = regexp.MustCompile("([a-z0-9])([A-Z])")
+)
+
+// IsLegacyLabelName returns whether name is a label name which is valid
+// without UTF-8 support, i.e. matches [a-zA-Z_][a-zA-Z0-9_]*. Other label
+// names are quoted in the text representation.
+func IsLegacyLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9' && i > 0)) {
+			return false
+		}
+	}
+	return true
+}
+
+// LegacyLabelName returns the label name exposed instead of the given UTF-8
+// label name to clients without UTF-8 support. Like for label names
+// generated from Kubernetes label and annotation keys without UTF-8 support,
+// invalid characters are replaced by underscores and the name is converted
+// to snake case, e.g. label_app.kubernetes.io/name becomes
+// label_app_kubernetes_io_name.
+func LegacyLabelName(name string) string {
+	name = invalidLabelCharRE.ReplaceAllString(name, "_")
+	return strings.ToLower(matchAllCap.ReplaceAllString(name, "${1}_${2}"))
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metric
+
+import "testing"
+
+func TestLegacyLabelName(t *testing.T) {
+	tests := []struct {
+		name   string
+		legacy bool
+		want   string
+	}{
+		{name: "label_app", legacy: true, want: "label_app"},
+		{name: "label_app.kubernetes.io/name", want: "label_app_kubernetes_io_name"},
+		{name: "annotation_example.com/someKey", want: "annotation_example_com_some_key"},
+		{name: "", want: ""},
+	}
+	for _, test := range tests {
+		if got := IsLegacyLabelName(test.name); got != test.legacy {
+			t.Errorf("%q: expected legacy %t, got %t", test.name, test.legacy, got)
+		}
+		if got := LegacyLabelName(test.name); got != test.want {
+			t.Errorf("%q: expected legacy name %q, got %q", test.name, test.want, got)
+		}
+	}
+}
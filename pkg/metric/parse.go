@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metric
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseFamilies parses metric families from the text representation written by
+// the metrics stores. Unlike the Prometheus text parser, it accepts the
+// OpenMetrics info and stateset types used by kube-state-metrics.
+func ParseFamilies(r io.Reader) ([]*Family, error) {
+	var families []*Family
+	fw := NewFamilyWriter(func(f *Family) error {
+		families = append(families, f)
+		return nil
+	})
+	if _, err := io.Copy(fw, r); err != nil {
+		return nil, err
+	}
+	if err := fw.Flush(); err != nil {
+		return nil, err
+	}
+	return families, nil
+}
+
+// FamilyWriter parses the metric families written to it in the text
+// representation like ParseFamilies, and passes each family on once it is
+// complete, so that only a single family is held at a time.
+type FamilyWriter struct {
+	fn      func(*Family) error
+	current *Family
+	// line holds an incomplete line of a previous write, and n is the
+	// number of the current line.
+	line []byte
+	n    int
+}
+
+// NewFamilyWriter returns a writer passing each metric family written to it
+// to fn. Flush has to be called once all metrics are written.
+func NewFamilyWriter(fn func(*Family) error) *FamilyWriter {
+	return &FamilyWriter{fn: fn}
+}
+
+// Write implements the io.Writer interface.
+func (w *FamilyWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.line = append(w.line, p...)
+			break
+		}
+		line := p[:i]
+		if len(w.line) > 0 {
+			w.line = append(w.line, line...)
+			line = w.line
+		}
+		if err := w.parseLine(string(line)); err != nil {
+			return 0, err
+		}
+		w.line = w.line[:0]
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Flush parses a remaining incomplete line and passes on the last family.
+func (w *FamilyWriter) Flush() error {
+	if len(w.line) > 0 {
+		line := string(w.line)
+		w.line = w.line[:0]
+		if err := w.parseLine(line); err != nil {
+			return err
+		}
+	}
+	if w.current == nil {
+		return nil
+	}
+	f := w.current
+	w.current = nil
+	return w.fn(f)
+}
+
+// family returns the family of the given name, passing on the current family
+// if it is a different one.
+func (w *FamilyWriter) family(name string) (*Family, error) {
+	if w.current != nil && w.current.Name == name {
+		return w.current, nil
+	}
+	if w.current != nil {
+		if err := w.fn(w.current); err != nil {
+			return nil, err
+		}
+	}
+	w.current = &Family{Name: name}
+	return w.current, nil
+}
+
+func (w *FamilyWriter) parseLine(line string) error {
+	w.n++
+	line = strings.TrimSuffix(line, "\r")
+	switch {
+	case line == "":
+		return nil
+	case strings.HasPrefix(line, "# HELP "):
+		name, help, _ := strings.Cut(line[len("# HELP "):], " ")
+		f, err := w.family(name)
+		if err != nil {
+			return err
+		}
+		f.Help = help
+	case strings.HasPrefix(line, "# TYPE "):
+		name, t, _ := strings.Cut(line[len("# TYPE "):], " ")
+		f, err := w.family(name)
+		if err != nil {
+			return err
+		}
+		f.Type = Type(t)
+	case strings.HasPrefix(line, "#"):
+		return nil
+	default:
+		name, m, err := ParseMetric(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", w.n, err)
+		}
+		f, err := w.family(name)
+		if err != nil {
+			return err
+		}
+		f.Metrics = append(f.Metrics, m)
+	}
+	return nil
+}
+
+// ParseMetric parses a single series line of the text representation written
+// by the metrics stores and returns its name and metric.
+func ParseMetric(line string) (string, *Metric, error) {
+	m := &Metric{}
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return "", nil, fmt.Errorf("invalid metric %q", line)
+	}
+	name := line[:end]
+	rest := line[end:]
+
+	if rest[0] == '{' {
+		rest = rest[1:]
+		for {
+			if rest == "" {
+				return "", nil, fmt.Errorf("unterminated label set in %q", line)
+			}
+			if rest[0] == '}' {
+				rest = rest[1:]
+				break
+			}
+			var key string
+			if rest[0] == '"' {
+				// Quoted UTF-8 label name.
+				k, n, err := unescapeLabelValue(rest[1:])
+				if err != nil {
+					return "", nil, fmt.Errorf("label name in %q: %w", line, err)
+				}
+				key, rest = k, rest[1+n:]
+				if !strings.HasPrefix(rest, "=\"") {
+					return "", nil, fmt.Errorf("invalid label in %q", line)
+				}
+			} else {
+				eq := strings.Index(rest, "=\"")
+				if eq <= 0 {
+					return "", nil, fmt.Errorf("invalid label in %q", line)
+				}
+				key, rest = rest[:eq], rest[eq:]
+			}
+			// rest starts with the =" preceding the value.
+			value, n, err := unescapeLabelValue(rest[2:])
+			if err != nil {
+				return "", nil, fmt.Errorf("label %s in %q: %w", key, line, err)
+			}
+			m.LabelKeys = append(m.LabelKeys, key)
+			m.LabelValues = append(m.LabelValues, value)
+			rest = strings.TrimPrefix(rest[2+n:], ",")
+		}
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(rest), 64)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid value in %q: %w", line, err)
+	}
+	m.Value = value
+	return name, m, nil
+}
+
+// unescapeLabelValue reads an escaped label value up to and including the
+// closing quote. It returns the value and the number of bytes consumed.
+func unescapeLabelValue(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", 0, fmt.Errorf("unterminated escape sequence")
+			}
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated label value")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metric
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFamilies(t *testing.T) {
+	in := `# HELP kube_pod_info Information about pod.
+# TYPE kube_pod_info info
+kube_pod_info{namespace="default",pod="foo \"bar\"\\\n"} 1
+kube_pod_info{namespace="default",pod="baz"} 1
+# HELP kube_pod_created Unix creation timestamp
+# TYPE kube_pod_created gauge
+kube_pod_created 1.5e+09
+kube_pod_created{pod="nan"} NaN
+`
+	got, err := ParseFamilies(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 families, got %d", len(got))
+	}
+	want := &Family{
+		Name: "kube_pod_info",
+		Help: "Information about pod.",
+		Type: Info,
+		Metrics: []*Metric{
+			{LabelKeys: []string{"namespace", "pod"}, LabelValues: []string{"default", "foo \"bar\"\\\n"}, Value: 1},
+			{LabelKeys: []string{"namespace", "pod"}, LabelValues: []string{"default", "baz"}, Value: 1},
+		},
+	}
+	if !reflect.DeepEqual(want, got[0]) {
+		t.Errorf("expected %+v, got %+v", want, got[0])
+	}
+	if got[1].Type != Gauge || got[1].Metrics[0].Value != 1.5e+09 || len(got[1].Metrics[0].LabelKeys) != 0 {
+		t.Errorf("unexpected family %+v", got[1])
+	}
+	if !math.IsNaN(got[1].Metrics[1].Value) {
+		t.Errorf("expected NaN, got %v", got[1].Metrics[1].Value)
+	}
+
+	// The text representation must survive a round trip.
+	var b strings.Builder
+	for _, m := range want.Metrics {
+		b.WriteString(want.Name)
+		m.Write(&b)
+	}
+	roundTrip, err := ParseFamilies(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want.Metrics, roundTrip[0].Metrics) {
+		t.Errorf("expected %+v, got %+v", want.Metrics, roundTrip[0].Metrics)
+	}
+
+	if _, err := ParseFamilies(strings.NewReader(`kube_pod_info{pod="foo} 1`)); err == nil {
+		t.Error("expected error for unterminated label value")
+	}
+}
+
+func TestParseUTF8LabelNames(t *testing.T) {
+	want := &Metric{
+		LabelKeys:   []string{"namespace", "label_app.kubernetes.io/name"},
+		LabelValues: []string{"default", "foo"},
+		Value:       1,
+	}
+	var b strings.Builder
+	b.WriteString("kube_pod_labels")
+	want.Write(&b)
+	if expected := `kube_pod_labels{namespace="default","label_app.kubernetes.io/name"="foo"} 1` + "\n"; b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	name, got, err := ParseMetric(strings.TrimSuffix(b.String(), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "kube_pod_labels" || !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %+v, got %s %+v", want, name, got)
+	}
+}
+
+func TestFamilyWriter(t *testing.T) {
+	var got []string
+	fw := NewFamilyWriter(func(f *Family) error {
+		got = append(got, fmt.Sprintf("%s:%d", f.Name, len(f.Metrics)))
+		return nil
+	})
+
+	// Families are passed on once the next one starts, also if lines are
+	// split across writes.
+	for _, p := range []string{
+		"# HELP kube_pod_info Information about pod.\n# TYPE kube_pod_info info\nkube_pod_info{pod=\"foo\"} 1\nkube_pod_in",
+		"fo{pod=\"bar\"} 1\n",
+		"# HELP kube_pod_created Unix creation timestamp\n",
+	} {
+		if _, err := fw.Write([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"kube_pod_info:2"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %v before flushing, got %v", want, got)
+	}
+	if _, err := fw.Write([]byte("kube_pod_created 1.5e+09")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"kube_pod_info:2", "kube_pod_created:1"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if _, err := NewFamilyWriter(func(*Family) error { return nil }).Write([]byte("# HELP a a\nkube_pod_info{pod=\"foo} 1\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error in line 2, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metric
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// MinLabelValueLength is the minimum maximum length of truncated label
+// values, which leaves room for the hash suffix of truncated values.
+const MinLabelValueLength = 16
+
+// labelValueHashSuffixLength is the length of the suffix of truncated label
+// values, a '~' followed by 8 hex digits.
+const labelValueHashSuffixLength = 9
+
+var (
+	// maxLabelValueLength is the maximum length of label values in bytes
+	// passed to TruncateLabelValue, label values are not truncated if it
+	// is 0.
+	maxLabelValueLength int
+	// truncatedLabelValues is the number of label values truncated by
+	// TruncateLabelValue.
+	truncatedLabelValues uint64
+)
+
+// SetMaxLabelValueLength sets the maximum length of label values in bytes
+// passed to TruncateLabelValue. Label values are not truncated if it is 0.
+// The setting applies to the whole process and has to be set before metrics
+// are generated.
+func SetMaxLabelValueLength(n int) {
+	maxLabelValueLength = n
+}
+
+// TruncatedLabelValues returns the number of label values truncated by
+// TruncateLabelValue.
+func TruncatedLabelValues() uint64 {
+	return atomic.LoadUint64(&truncatedLabelValues)
+}
+
+// TruncateLabelValue truncates label values sourced from objects, e.g. from
+// Kubernetes labels and annotations, which are longer than the maximum
+// length set by SetMaxLabelValueLength. Truncated values end with a '~' and
+// the hash of the whole value, so different values with the same prefix
+// remain distinguishable.
+func TruncateLabelValue(v string) string {
+	max := maxLabelValueLength
+	if max <= 0 || len(v) <= max {
+		return v
+	}
+	atomic.AddUint64(&truncatedLabelValues, 1)
+
+	h := fnv.New32a()
+	h.Write([]byte(v))
+	n := max - labelValueHashSuffixLength
+	// Do not cut multi-byte characters.
+	for n > 0 && !utf8.RuneStart(v[n]) {
+		n--
+	}
+	return fmt.Sprintf("%s~%08x", v[:n], h.Sum32())
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metric
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateLabelValue(t *testing.T) {
+	defer SetMaxLabelValueLength(0)
+
+	long := strings.Repeat("a", 32)
+	if got := TruncateLabelValue(long); got != long {
+		t.Errorf("expected label values not to be truncated by default, got %q", got)
+	}
+
+	SetMaxLabelValueLength(MinLabelValueLength)
+	before := TruncatedLabelValues()
+	if got := TruncateLabelValue("short"); got != "short" {
+		t.Errorf("expected short label values not to be truncated, got %q", got)
+	}
+	got := TruncateLabelValue(long)
+	if len(got) != MinLabelValueLength || !strings.HasPrefix(got, "aaaaaaa~") {
+		t.Errorf("unexpected truncated label value %q", got)
+	}
+	if other := TruncateLabelValue(long + "b"); other == got {
+		t.Errorf("expected truncated label values with the same prefix to differ, got %q", other)
+	}
+	if n := TruncatedLabelValues() - before; n != 2 {
+		t.Errorf("expected 2 truncated label values, got %d", n)
+	}
+
+	got = TruncateLabelValue("aaaaaa" + strings.Repeat("ü", 8))
+	if !utf8.ValidString(got) || !strings.HasPrefix(got, "aaaaaa~") {
+		t.Errorf("expected multi-byte characters not to be cut, got %q", got)
+	}
+}
@@ -76,6 +76,7 @@ func NewOptInFamilyGenerator(name string, help string, metricType metric.Type, d
 func (g *FamilyGenerator) Generate(obj interface{}) *metric.Family {
 	family := g.GenerateFunc(obj)
 	family.Name = g.Name
+	family.Help = g.Help
 	family.Type = g.Type
 	return family
 }
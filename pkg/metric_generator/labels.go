@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// AllMetrics is the key of a labels denylist applying to all metric families.
+const AllMetrics = "*"
+
+// DropLabels returns the given family generators with the labels of the given
+// denylist removed from the metrics they generate. The denylist maps metric
+// family names, or AllMetrics, to the labels to be dropped.
+func DropLabels(denylist map[string][]string, families []FamilyGenerator) []FamilyGenerator {
+	if len(denylist) == 0 {
+		return families
+	}
+
+	result := make([]FamilyGenerator, len(families))
+	for i, f := range families {
+		drop := map[string]struct{}{}
+		for _, l := range denylist[AllMetrics] {
+			drop[l] = struct{}{}
+		}
+		for _, l := range denylist[f.Name] {
+			drop[l] = struct{}{}
+		}
+		if len(drop) > 0 {
+			generateFunc := f.GenerateFunc
+			f.GenerateFunc = func(obj interface{}) *metric.Family {
+				family := generateFunc(obj)
+				for _, m := range family.Metrics {
+					dropMetricLabels(m, drop)
+				}
+				return family
+			}
+		}
+		result[i] = f
+	}
+	return result
+}
+
+// dropMetricLabels removes the given labels from m. New label slices are
+// allocated, as generators may share them between metrics.
+func dropMetricLabels(m *metric.Metric, drop map[string]struct{}) {
+	keys := make([]string, 0, len(m.LabelKeys))
+	values := make([]string, 0, len(m.LabelValues))
+	for i, k := range m.LabelKeys {
+		if _, ok := drop[k]; ok {
+			continue
+		}
+		keys = append(keys, k)
+		values = append(values, m.LabelValues[i])
+	}
+	m.LabelKeys, m.LabelValues = keys, values
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+func TestDropLabels(t *testing.T) {
+	labelKeys := []string{"namespace", "pod", "uid", "container_id"}
+	newFamilyGenerator := func(name string) FamilyGenerator {
+		return *NewFamilyGenerator(name, "help", metric.Gauge, "", func(obj interface{}) *metric.Family {
+			return &metric.Family{Metrics: []*metric.Metric{
+				{LabelKeys: labelKeys, LabelValues: []string{"ns", "pod", "uid", "cid"}, Value: 1},
+			}}
+		})
+	}
+	families := []FamilyGenerator{
+		newFamilyGenerator("kube_pod_info"),
+		newFamilyGenerator("kube_pod_container_info"),
+	}
+
+	denylist := map[string][]string{
+		AllMetrics:                {"uid"},
+		"kube_pod_container_info": {"container_id"},
+	}
+	expected := map[string]string{
+		"kube_pod_info":           `kube_pod_info{namespace="ns",pod="pod",container_id="cid"} 1` + "\n",
+		"kube_pod_container_info": `kube_pod_container_info{namespace="ns",pod="pod"} 1` + "\n",
+	}
+	for _, f := range DropLabels(denylist, families) {
+		if got := string(f.Generate(nil).ByteSlice()); got != expected[f.Name] {
+			t.Errorf("%s: expected %q, got %q", f.Name, expected[f.Name], got)
+		}
+	}
+	if len(labelKeys) != 4 || labelKeys[2] != "uid" {
+		t.Errorf("expected label keys shared between metrics to be unchanged, got %v", labelKeys)
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import "strings"
+
+// DefaultPrefix is the prefix of the names of the metric families of the
+// built-in collectors.
+const DefaultPrefix = "kube_"
+
+// WithPrefix returns the given family generators with the DefaultPrefix of
+// their names replaced by prefix, e.g. kube_deployment_created becomes
+// k8s_deployment_created for the prefix k8s_. Names without the
+// DefaultPrefix are kept.
+func WithPrefix(prefix string, families []FamilyGenerator) []FamilyGenerator {
+	if prefix == "" || prefix == DefaultPrefix {
+		return families
+	}
+
+	result := make([]FamilyGenerator, len(families))
+	for i, f := range families {
+		if strings.HasPrefix(f.Name, DefaultPrefix) {
+			f.Name = prefix + strings.TrimPrefix(f.Name, DefaultPrefix)
+		}
+		result[i] = f
+	}
+	return result
+}
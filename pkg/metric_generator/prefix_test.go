@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+func TestWithPrefix(t *testing.T) {
+	newFamilyGenerator := func(name string) FamilyGenerator {
+		return *NewFamilyGenerator(name, "help", metric.Gauge, "", func(obj interface{}) *metric.Family {
+			return &metric.Family{Metrics: []*metric.Metric{{Value: 1}}}
+		})
+	}
+	families := []FamilyGenerator{
+		newFamilyGenerator("kube_deployment_created"),
+		newFamilyGenerator("kube_deployment_spec_replicas"),
+		newFamilyGenerator("deployment_unprefixed"),
+	}
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{
+			prefix:   "",
+			expected: []string{"kube_deployment_created", "kube_deployment_spec_replicas", "deployment_unprefixed"},
+		},
+		{
+			prefix:   "k8s_",
+			expected: []string{"k8s_deployment_created", "k8s_deployment_spec_replicas", "deployment_unprefixed"},
+		},
+	}
+	for _, test := range tests {
+		for i, f := range WithPrefix(test.prefix, families) {
+			if f.Name != test.expected[i] {
+				t.Errorf("prefix %q: expected name %s, got %s", test.prefix, test.expected[i], f.Name)
+			}
+			if got := f.Generate(nil).Name; got != test.expected[i] {
+				t.Errorf("prefix %q: expected generated family %s, got %s", test.prefix, test.expected[i], got)
+			}
+		}
+	}
+	if families[0].Name != "kube_deployment_created" {
+		t.Errorf("expected the given family generators not to be modified, got %s", families[0].Name)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/kube-state-metrics/v2/pkg/aggregation"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// aggregateGroup is the running sum of the series of all objects of a store
+// with the same values of the aggregated labels.
+type aggregateGroup struct {
+	metric metric.Metric
+	// objects is the number of objects with series in the sum. The group
+	// is dropped once it is 0.
+	objects int
+}
+
+// aggregateContribution is the sum of the series of a single object which is
+// added to the aggregateGroup of the given key.
+type aggregateContribution struct {
+	key   string
+	value float64
+}
+
+// SetAggregateBy configures the MetricsStore to keep running sums of the
+// series of all objects by the given labels instead of the metrics of each
+// object, e.g. the number of pods per namespace and phase. All other labels
+// are dropped. It must be called before the store is populated.
+func (s *MetricsStore) SetAggregateBy(labels []string) {
+	s.aggregate = true
+	s.aggregateBy = labels
+	s.sums = newAggregateSums(len(s.headers))
+}
+
+func newAggregateSums(families int) []map[string]*aggregateGroup {
+	sums := make([]map[string]*aggregateGroup, families)
+	for i := range sums {
+		sums[i] = map[string]*aggregateGroup{}
+	}
+	return sums
+}
+
+// addAggregates adds the series of the given metric families of an object to
+// the sums, replacing the series previously added for the object. The caller
+// must hold the lock.
+func (s *MetricsStore) addAggregates(uid types.UID, families []metric.FamilyInterface) {
+	s.removeAggregates(uid)
+	contributions := make([][]aggregateContribution, len(families))
+	for i, f := range families {
+		f.Inspect(func(f metric.Family) {
+			summed := aggregation.SumBy(&f, s.aggregateBy)
+			contributions[i] = make([]aggregateContribution, len(summed.Metrics))
+			for j, m := range summed.Metrics {
+				key := aggregateKey(m)
+				g, ok := s.sums[i][key]
+				if !ok {
+					g = &aggregateGroup{metric: metric.Metric{LabelKeys: m.LabelKeys, LabelValues: m.LabelValues}}
+					s.sums[i][key] = g
+				}
+				g.metric.Value += m.Value
+				g.objects++
+				contributions[i][j] = aggregateContribution{key: key, value: m.Value}
+			}
+		})
+	}
+	s.aggregates[uid] = contributions
+}
+
+// removeAggregates subtracts the series of the object with the given id from
+// the sums. The caller must hold the lock.
+func (s *MetricsStore) removeAggregates(uid types.UID) {
+	contributions, ok := s.aggregates[uid]
+	if !ok {
+		return
+	}
+	for i, family := range contributions {
+		for _, c := range family {
+			g := s.sums[i][c.key]
+			g.objects--
+			if g.objects == 0 {
+				delete(s.sums[i], c.key)
+				continue
+			}
+			g.metric.Value -= c.value
+		}
+	}
+	delete(s.aggregates, uid)
+}
+
+// aggregateKey returns the key of the aggregateGroup of the given summed
+// series, which only has the aggregated labels.
+func aggregateKey(m *metric.Metric) string {
+	b := strings.Builder{}
+	for i := range m.LabelKeys {
+		b.WriteString(m.LabelKeys[i])
+		b.WriteByte(0xff)
+		b.WriteString(m.LabelValues[i])
+		b.WriteByte(0xff)
+	}
+	return b.String()
+}
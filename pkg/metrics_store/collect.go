@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// syncPollInterval is the interval in which WaitForSync checks whether the
+// stores are synced.
+const syncPollInterval = 100 * time.Millisecond
+
+// Collect returns the metric families of all writers in the order in which
+// they are written, e.g. for programs embedding kube-state-metrics which
+// expose the metrics themselves. It returns the error of ctx if ctx is done
+// before all writers were collected.
+func (l MetricsWriterList) Collect(ctx context.Context) ([]*metric.Family, error) {
+	var families []*metric.Family
+	buf := &bytes.Buffer{}
+	for _, m := range l {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		buf.Reset()
+		if err := m.WriteAll(buf); err != nil {
+			return nil, err
+		}
+		f, err := metric.ParseFamilies(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse metrics of resource %s: %w", m.Resource(), err)
+		}
+		families = append(families, f...)
+	}
+	return families, nil
+}
+
+// Synced returns whether the stores of all writers were populated by an
+// initial list.
+func (l MetricsWriterList) Synced() bool {
+	for _, m := range l {
+		if !m.Synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitForSync blocks until the stores of all writers were populated by an
+// initial list, so that Collect returns the metrics of all objects. It returns
+// the error of ctx if ctx is done before.
+func (l MetricsWriterList) WaitForSync(ctx context.Context) error {
+	ticker := time.NewTicker(syncPollInterval)
+	defer ticker.Stop()
+	for !l.Synced() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
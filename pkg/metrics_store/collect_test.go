@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+func TestCollect(t *testing.T) {
+	store := metricsstore.NewMetricsStore([]string{"# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge"}, func(obj interface{}) []metric.FamilyInterface {
+		return []metric.FamilyInterface{&metric.Family{
+			Name:    "kube_pod_info",
+			Metrics: []*metric.Metric{{LabelKeys: []string{"pod"}, LabelValues: []string{obj.(*v1.Pod).Name}, Value: 1}},
+		}}
+	})
+	writers := metricsstore.MetricsWriterList{metricsstore.NewResourceMetricsWriter("pods", store)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := writers.WaitForSync(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the wait for the sync to time out, got %v", err)
+	}
+	if err := store.Replace([]interface{}{&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", UID: "uid1"}}}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := writers.WaitForSync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := writers.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*metric.Family{{
+		Name:    "kube_pod_info",
+		Help:    "Info",
+		Type:    metric.Gauge,
+		Metrics: []*metric.Metric{{LabelKeys: []string{"pod"}, LabelValues: []string{"pod1"}, Value: 1}},
+	}}
+	if !reflect.DeepEqual(families, want) {
+		t.Errorf("expected families %+v, got %+v", want, families)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := writers.Collect(cancelled); err != context.Canceled {
+		t.Errorf("expected the collection to be cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+)
+
+// labelValueEscaper escapes label values in the text exposition format.
+var labelValueEscaper = strings.NewReplacer("\\", `\\`, "\n", `\n`, "\"", `\"`)
+
+// ExternalLabels are constant labels appended to all series written to the
+// writer returned by NewExternalLabelsWriter.
+type ExternalLabels struct {
+	names []string
+	// pairs holds the rendered name="value" pair of each label in the order
+	// of names.
+	pairs [][]byte
+}
+
+// NewExternalLabels returns the given labels, which are expected to have valid
+// label names.
+func NewExternalLabels(labels map[string]string) *ExternalLabels {
+	l := &ExternalLabels{}
+	for name := range labels {
+		l.names = append(l.names, name)
+	}
+	sort.Strings(l.names)
+	for _, name := range l.names {
+		l.pairs = append(l.pairs, []byte(name+"=\""+labelValueEscaper.Replace(labels[name])+"\""))
+	}
+	return l
+}
+
+// NewExternalLabelsWriter returns a writer appending the given labels to all
+// series written to w in the text exposition format. Labels of the series take
+// precedence over external labels with the same name. Flush has to be called
+// once all metrics are written.
+func NewExternalLabelsWriter(w io.Writer, labels *ExternalLabels) *LineWriter {
+	return NewLineWriter(w, func(dst, line []byte) []byte {
+		if line[0] == '#' || line[0] == '\n' {
+			return append(dst, line...)
+		}
+		return labels.appendTo(dst, line)
+	})
+}
+
+// appendTo appends the series line with the external labels added to dst.
+func (l *ExternalLabels) appendTo(dst, line []byte) []byte {
+	nameEnd := bytes.IndexAny(line, "{ ")
+	if nameEnd < 0 {
+		return append(dst, line...)
+	}
+	dst = append(dst, line[:nameEnd]...)
+	dst = append(dst, '{')
+
+	rest := line[nameEnd:]
+	var existing [][]byte
+	if rest[0] == '{' {
+		end := labelsEnd(rest)
+		if end < 0 {
+			// Not a valid series, which is written as is.
+			return append(dst[:len(dst)-nameEnd-1], line...)
+		}
+		existing = labelNames(rest[1:end])
+		dst = append(dst, rest[1:end]...)
+		rest = rest[end+1:]
+	}
+
+	sep := len(existing) > 0
+	for i, name := range l.names {
+		if containsName(existing, name) {
+			continue
+		}
+		if sep {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, l.pairs[i]...)
+		sep = true
+	}
+	dst = append(dst, '}')
+	return append(dst, rest...)
+}
+
+// labelsEnd returns the index of the brace closing the labels starting at the
+// beginning of s, skipping braces in quoted label values.
+func labelsEnd(s []byte) int {
+	quoted := false
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case '}':
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// labelNames returns the names of the comma-separated name="value" pairs of s.
+func labelNames(s []byte) [][]byte {
+	var names [][]byte
+	for len(s) > 0 {
+		eq := bytes.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		names = append(names, bytes.TrimSpace(bytes.TrimLeft(s[:eq], ",")))
+		s = s[eq+1:]
+		// Skip the quoted value.
+		if len(s) == 0 || s[0] != '"' {
+			break
+		}
+		i := 1
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' {
+				i++
+			}
+		}
+		if i >= len(s) {
+			break
+		}
+		s = s[i+1:]
+	}
+	return names
+}
+
+func containsName(names [][]byte, name string) bool {
+	for _, n := range names {
+		if string(n) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExternalLabelsWriter(t *testing.T) {
+	labels := NewExternalLabels(map[string]string{"region": "eu-west-1", "cluster": "prod-eu"})
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\n",
+			want: "# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\n",
+		},
+		{
+			in:   "kube_pod_info{namespace=\"default\",pod=\"pod1\"} 1\n",
+			want: "kube_pod_info{namespace=\"default\",pod=\"pod1\",cluster=\"prod-eu\",region=\"eu-west-1\"} 1\n",
+		},
+		{
+			in:   "kube_state_metrics_standby 1\n",
+			want: "kube_state_metrics_standby{cluster=\"prod-eu\",region=\"eu-west-1\"} 1\n",
+		},
+		{
+			in:   "kube_node_labels{node=\"n1\",cluster=\"own\"} 1\n",
+			want: "kube_node_labels{node=\"n1\",cluster=\"own\",region=\"eu-west-1\"} 1\n",
+		},
+		{
+			in:   "kube_pod_annotations{annotation_x=\"a}\\\"b\",pod=\"p\"} 1\n",
+			want: "kube_pod_annotations{annotation_x=\"a}\\\"b\",pod=\"p\",cluster=\"prod-eu\",region=\"eu-west-1\"} 1\n",
+		},
+	}
+
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		w := NewExternalLabelsWriter(buf, labels)
+		// Lines split across writes are rewritten once complete.
+		for i := 0; i < len(test.in); i += 7 {
+			end := i + 7
+			if end > len(test.in) {
+				end = len(test.in)
+			}
+			if _, err := w.Write([]byte(test.in[i:end])); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("expected %q, got %q", test.want, got)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// NewFilterWriter returns a writer passing on the metrics written to w in the
+// text exposition format if they are of one of the given families and have a
+// namespace label with one of the given namespaces. Families or namespaces
+// are not filtered if nil. Flush has to be called once all metrics are
+// written.
+func NewFilterWriter(w io.Writer, families, namespaces map[string]struct{}) *LineWriter {
+	return NewLineWriter(w, func(dst, line []byte) []byte {
+		if bytes.HasPrefix(line, []byte("# HELP ")) || bytes.HasPrefix(line, []byte("# TYPE ")) {
+			rest := line[len("# HELP "):]
+			if end := bytes.IndexByte(rest, ' '); end >= 0 && !contains(families, string(rest[:end])) {
+				return dst
+			}
+			return append(dst, line...)
+		}
+		if line[0] == '#' || line[0] == '\n' {
+			return append(dst, line...)
+		}
+
+		nameEnd := bytes.IndexAny(line, "{ ")
+		if nameEnd < 0 {
+			return append(dst, line...)
+		}
+		if !contains(families, string(line[:nameEnd])) {
+			return dst
+		}
+		if namespaces != nil {
+			namespace, ok := labelValue(line[nameEnd:], "namespace")
+			if !ok || !contains(namespaces, namespace) {
+				return dst
+			}
+		}
+		return append(dst, line...)
+	})
+}
+
+// contains returns whether the set contains s. A nil set contains any string.
+func contains(set map[string]struct{}, s string) bool {
+	if set == nil {
+		return true
+	}
+	_, ok := set[s]
+	return ok
+}
+
+// labelValue returns the unescaped value of the named label of the labels
+// starting at the beginning of s, e.g. {namespace="default",pod="pod1"}.
+func labelValue(s []byte, name string) (string, bool) {
+	if len(s) == 0 || s[0] != '{' {
+		return "", false
+	}
+	end := labelsEnd(s)
+	if end < 0 {
+		return "", false
+	}
+	labels := s[1:end]
+	for len(labels) > 0 {
+		eq := bytes.IndexByte(labels, '=')
+		if eq < 0 || eq+1 >= len(labels) || labels[eq+1] != '"' {
+			return "", false
+		}
+		n := bytes.TrimSpace(bytes.TrimLeft(labels[:eq], ","))
+		i := eq + 2
+		for ; i < len(labels) && labels[i] != '"'; i++ {
+			if labels[i] == '\\' {
+				i++
+			}
+		}
+		if i >= len(labels) {
+			return "", false
+		}
+		if string(n) == name {
+			value, err := strconv.Unquote(string(labels[eq+1 : i+1]))
+			if err != nil {
+				return "", false
+			}
+			return value, true
+		}
+		labels = labels[i+1:]
+	}
+	return "", false
+}
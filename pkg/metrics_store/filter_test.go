@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFilterWriter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{in: "kube_pod_info{namespace=\"team-a\",pod=\"p\"} 1\n", want: true},
+		{in: "kube_pod_info{pod=\"p\",namespace=\"team-a\"} 1\n", want: true},
+		{in: "kube_pod_labels{label_x=\"namespace=\\\"team-a\\\"\",namespace=\"team-b\"} 1\n", want: false},
+		{in: "kube_node_info{node=\"n\"} 1\n", want: false},
+		{in: "kube_state_metrics_standby 1\n", want: false},
+	}
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		w := NewFilterWriter(buf, nil, map[string]struct{}{"team-a": {}})
+		if _, err := w.Write([]byte(test.in)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.Len() > 0; got != test.want {
+			t.Errorf("%q: expected kept %t, got %t", test.in, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// NewLegacyLabelNamesWriter returns a writer replacing quoted UTF-8 label names
+// of the series written to w in the text exposition format by their legacy
+// names, for clients without UTF-8 support. Label names which are equal once
+// replaced are suffixed like conflicting label names generated from
+// Kubernetes labels, e.g. label_app_conflict1. Flush has to be called once all
+// metrics are written.
+func NewLegacyLabelNamesWriter(w io.Writer) *LineWriter {
+	return NewLineWriter(w, func(dst, line []byte) []byte {
+		if line[0] == '#' || (!bytes.Contains(line, []byte(`{"`)) && !bytes.Contains(line, []byte(`,"`))) {
+			return append(dst, line...)
+		}
+		name, m, err := metric.ParseMetric(string(bytes.TrimSuffix(line, []byte("\n"))))
+		if err != nil {
+			// Not a valid series, which is written as is.
+			return append(dst, line...)
+		}
+		m.LabelKeys = legacyLabelNames(m.LabelKeys)
+		var b strings.Builder
+		b.WriteString(name)
+		m.Write(&b)
+		return append(dst, b.String()...)
+	})
+}
+
+// legacyLabelNames returns the legacy names of the given label names, with
+// conflicting names suffixed in their order.
+func legacyLabelNames(names []string) []string {
+	legacy := make([]string, len(names))
+	counts := make(map[string]int, len(names))
+	for i, name := range names {
+		if !metric.IsLegacyLabelName(name) {
+			name = metric.LegacyLabelName(name)
+		}
+		legacy[i] = name
+		counts[name]++
+	}
+	seen := make(map[string]int, len(names))
+	for i, name := range legacy {
+		if counts[name] > 1 {
+			seen[name]++
+			legacy[i] = fmt.Sprintf("%s_conflict%d", name, seen[name])
+		}
+	}
+	return legacy
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLegacyLabelNamesWriter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "# HELP kube_pod_labels Labels\n# TYPE kube_pod_labels gauge\n",
+			want: "# HELP kube_pod_labels Labels\n# TYPE kube_pod_labels gauge\n",
+		},
+		{
+			in:   "kube_pod_labels{namespace=\"default\",label_app=\"a,\\\"b\"} 1\n",
+			want: "kube_pod_labels{namespace=\"default\",label_app=\"a,\\\"b\"} 1\n",
+		},
+		{
+			in:   "kube_pod_labels{namespace=\"default\",\"label_app.kubernetes.io/name\"=\"foo\"} 1\n",
+			want: "kube_pod_labels{namespace=\"default\",label_app_kubernetes_io_name=\"foo\"} 1\n",
+		},
+		{
+			in:   "kube_pod_labels{\"label_app.name\"=\"a\",label_app_name=\"b\"} 1\n",
+			want: "kube_pod_labels{label_app_name_conflict1=\"a\",label_app_name_conflict2=\"b\"} 1\n",
+		},
+	}
+
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		w := NewLegacyLabelNamesWriter(buf)
+		if _, err := w.Write([]byte(test.in)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("expected %q, got %q", test.want, got)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import "sync"
+
+// ObjectLimit limits the number of objects of one or more MetricsStores, e.g.
+// of all stores of a resource. Objects beyond the limit are not added to the
+// stores but counted as truncated. A nil ObjectLimit does not limit the
+// number of objects.
+type ObjectLimit struct {
+	max int
+
+	mtx       sync.Mutex
+	objects   int
+	truncated int
+}
+
+// NewObjectLimit returns an ObjectLimit of the given number of objects.
+func NewObjectLimit(max int) *ObjectLimit {
+	return &ObjectLimit{max: max}
+}
+
+// Truncated returns the number of objects which were not added to the stores
+// as the limit was reached.
+func (l *ObjectLimit) Truncated() int {
+	if l == nil {
+		return 0
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.truncated
+}
+
+// acquire returns whether another object may be added and counts it if so.
+func (l *ObjectLimit) acquire() bool {
+	if l == nil {
+		return true
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.objects >= l.max {
+		return false
+	}
+	l.objects++
+	return true
+}
+
+// release uncounts removed objects and truncated objects which are no longer
+// truncated, as they were deleted or added.
+func (l *ObjectLimit) release(objects, truncated int) {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.objects -= objects
+	l.truncated -= truncated
+}
+
+// truncate counts an object which was not added.
+func (l *ObjectLimit) truncate() {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.truncated++
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+func TestObjectLimit(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		return []metric.FamilyInterface{&metric.Family{Name: "kube_pod_info", Metrics: []*metric.Metric{{Value: 1}}}}
+	}
+	limit := NewObjectLimit(2)
+	// Stores of two namespaces share the limit.
+	s1 := NewMetricsStore([]string{"# HELP kube_pod_info Info"}, genFunc)
+	s1.SetObjectLimit(limit)
+	s2 := NewMetricsStore([]string{"# HELP kube_pod_info Info"}, genFunc)
+	s2.SetObjectLimit(limit)
+
+	pod := func(uid string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}}
+	}
+	assertStats := func(desc string, s *MetricsStore, objects, truncated, limitTruncated int) {
+		t.Helper()
+		stats := s.Stats()
+		if stats.Objects != objects || stats.Truncated != truncated || limit.Truncated() != limitTruncated {
+			t.Errorf("%s: expected %d objects, %d truncated and %d truncated in total, got %d, %d and %d",
+				desc, objects, truncated, limitTruncated, stats.Objects, stats.Truncated, limit.Truncated())
+		}
+	}
+
+	for _, uid := range []string{"a", "b"} {
+		if err := s1.Add(pod(uid)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, uid := range []string{"c", "c", "d"} {
+		if err := s2.Add(pod(uid)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertStats("limit reached", s2, 0, 2, 2)
+
+	// Updates of added objects are applied once the limit is reached.
+	if err := s1.Update(pod("a")); err != nil {
+		t.Fatal(err)
+	}
+	assertStats("update", s1, 2, 0, 2)
+
+	// Deleted objects make room for truncated objects once they are updated.
+	if err := s1.Delete(pod("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.Update(pod("c")); err != nil {
+		t.Fatal(err)
+	}
+	assertStats("delete", s2, 1, 1, 1)
+
+	if err := s2.Replace([]interface{}{pod("e")}, ""); err != nil {
+		t.Fatal(err)
+	}
+	assertStats("replace", s2, 1, 0, 0)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"io"
+)
+
+// LineWriter rewrites each line of the metrics written to it in the text
+// exposition format before writing it to the underlying writer.
+type LineWriter struct {
+	w io.Writer
+	// rewrite appends the rewritten line to dst. Lines are passed including
+	// their newline and are never empty.
+	rewrite func(dst, line []byte) []byte
+	// line holds an incomplete line of a previous write.
+	line []byte
+	buf  []byte
+}
+
+// NewLineWriter returns a writer rewriting each line written to it with the
+// given function before writing it to w. Flush has to be called once all
+// metrics are written.
+func NewLineWriter(w io.Writer, rewrite func(dst, line []byte) []byte) *LineWriter {
+	return &LineWriter{w: w, rewrite: rewrite}
+}
+
+// Write implements the io.Writer interface.
+func (l *LineWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			l.line = append(l.line, p...)
+			break
+		}
+		line := p[:i+1]
+		if len(l.line) > 0 {
+			l.line = append(l.line, line...)
+			line = l.line
+		}
+		if err := l.writeLine(line); err != nil {
+			return 0, err
+		}
+		l.line = l.line[:0]
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Flush writes a remaining incomplete line.
+func (l *LineWriter) Flush() error {
+	if len(l.line) == 0 {
+		return nil
+	}
+	err := l.writeLine(l.line)
+	l.line = l.line[:0]
+	return err
+}
+
+func (l *LineWriter) writeLine(line []byte) error {
+	l.buf = l.rewrite(l.buf[:0], line)
+	_, err := l.w.Write(l.buf)
+	return err
+}
@@ -18,6 +18,7 @@ package metricsstore
 
 import (
 	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,30 +32,107 @@ import (
 type MetricsStore struct {
 	// Protects metrics
 	mutex sync.RWMutex
-	// metrics is a map indexed by Kubernetes object id, containing a slice of
-	// metric families, containing a slice of metrics. We need to keep metrics
-	// grouped by metric families in order to zip families with their help text in
+	// metrics is a map indexed by Kubernetes object id, containing the metric
+	// families of each object. We need to keep metrics grouped by metric
+	// families in order to zip families with their help text in
 	// MetricsStore.WriteAll().
-	metrics map[types.UID][][]byte
+	metrics map[types.UID]objectMetrics
+	// lazy is true if the metrics of objects are generated whenever they are
+	// written instead of being stored in metrics.
+	lazy bool
+	// objects is a map indexed by Kubernetes object id, containing the
+	// objects of a lazy store.
+	objects map[types.UID]lazyObject
+	// keepFamilies is true if the metric families of each object are kept in
+	// families in addition to their text exposition format in metrics.
+	keepFamilies bool
+	families     map[types.UID][]metric.FamilyInterface
+	// aggregate is true if only the sums of the series of all objects by the
+	// aggregateBy labels are kept in sums, by metric family, instead of the
+	// metrics of each object. aggregates contains the series each object
+	// added to the sums, so that they can be subtracted once it changes.
+	aggregate   bool
+	aggregateBy []string
+	sums        []map[string]*aggregateGroup
+	aggregates  map[types.UID][][]aggregateContribution
 	// headers contains the header (TYPE and HELP) of each metric family. It is
 	// later on zipped with with their corresponding metric families in
 	// MetricStore.WriteAll().
 	headers []string
+	// synced is true once the store was populated by an initial list.
+	synced bool
+	// createdAt and syncedAt are the times the store was created and first
+	// populated by an initial list.
+	createdAt time.Time
+	syncedAt  time.Time
+	// limit limits the number of objects in the metrics map. Objects beyond
+	// it are only tracked in truncated.
+	limit     *ObjectLimit
+	truncated map[types.UID]struct{}
+	// expiry returns the time after which the metrics of an object are
+	// dropped, e.g. as it completed. The expiry times of the objects of the
+	// store are tracked in expires.
+	expiry  ExpiryFunc
+	expires map[types.UID]time.Time
 
 	// generateMetricsFunc generates metrics based on a given Kubernetes object
 	// and returns them grouped by metric family.
 	generateMetricsFunc func(interface{}) []metric.FamilyInterface
 }
 
+// ExpiryFunc returns the time after which the metrics of the given object are
+// dropped, and whether the object expires at all.
+type ExpiryFunc func(obj interface{}) (time.Time, bool)
+
+// lazyObject is an object of a lazy store and the time it was last updated.
+type lazyObject struct {
+	obj     interface{}
+	updated int64
+}
+
 // NewMetricsStore returns a new MetricsStore
 func NewMetricsStore(headers []string, generateFunc func(interface{}) []metric.FamilyInterface) *MetricsStore {
 	return &MetricsStore{
 		generateMetricsFunc: generateFunc,
 		headers:             headers,
-		metrics:             map[types.UID][][]byte{},
+		metrics:             map[types.UID]objectMetrics{},
+		objects:             map[types.UID]lazyObject{},
+		families:            map[types.UID][]metric.FamilyInterface{},
+		aggregates:          map[types.UID][][]aggregateContribution{},
+		truncated:           map[types.UID]struct{}{},
+		expires:             map[types.UID]time.Time{},
+		createdAt:           time.Now(),
 	}
 }
 
+// SetObjectLimit limits the number of objects of the MetricsStore, possibly
+// shared with other stores. It must be called before the store is populated.
+func (s *MetricsStore) SetObjectLimit(l *ObjectLimit) {
+	s.limit = l
+}
+
+// SetExpiry configures the MetricsStore to drop the metrics of objects once
+// they expire, e.g. objects which completed a while ago. Objects which expire
+// later are dropped by Resync. It must be called before the store is
+// populated.
+func (s *MetricsStore) SetExpiry(f ExpiryFunc) {
+	s.expiry = f
+}
+
+// SetLazy configures the MetricsStore to store objects and generate their
+// metrics whenever they are written instead of whenever they are added. It
+// must be called before the store is populated.
+func (s *MetricsStore) SetLazy() {
+	s.lazy = true
+}
+
+// SetKeepFamilies configures the MetricsStore to keep the metric families of
+// each object in addition to their text exposition format, so that Families
+// returns them. It must be called before the store is populated.
+func (s *MetricsStore) SetKeepFamilies() {
+	s.keepFamilies = true
+}
+
 // Implementing k8s.io/client-go/tools/cache.Store interface
 
 // Add inserts adds to the MetricsStore by calling the metrics generator functions and
@@ -68,14 +146,50 @@ func (s *MetricsStore) Add(obj interface{}) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	families := s.generateMetricsFunc(obj)
-	familyStrings := make([][]byte, len(families))
-
-	for i, f := range families {
-		familyStrings[i] = f.ByteSlice()
+	uid := o.GetUID()
+	if s.expiry != nil {
+		if expires, ok := s.expiry(obj); ok {
+			if !time.Now().Before(expires) {
+				s.delete(uid)
+				return nil
+			}
+			s.expires[uid] = expires
+		} else {
+			delete(s.expires, uid)
+		}
+	}
+	if !s.contains(uid) {
+		_, truncated := s.truncated[uid]
+		if !s.limit.acquire() {
+			if !truncated {
+				s.truncated[uid] = struct{}{}
+				s.limit.truncate()
+			}
+			return nil
+		}
+		if truncated {
+			delete(s.truncated, uid)
+			s.limit.release(0, 1)
+		}
 	}
 
-	s.metrics[o.GetUID()] = familyStrings
+	if s.aggregate {
+		s.addAggregates(uid, s.generateMetricsFunc(obj))
+		return nil
+	}
+	if s.lazy {
+		updated := lastUpdated(o)
+		// Managed fields are never used by metric generators and are
+		// often the largest part of an object.
+		o.SetManagedFields(nil)
+		s.objects[uid] = lazyObject{obj: obj, updated: updated}
+		return nil
+	}
+	families := s.generateMetricsFunc(obj)
+	s.metrics[uid] = newObjectMetrics(families, lastUpdated(o))
+	if s.keepFamilies {
+		s.families[uid] = families
+	}
 
 	return nil
 }
@@ -97,11 +211,64 @@ func (s *MetricsStore) Delete(obj interface{}) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	delete(s.metrics, o.GetUID())
+	s.delete(o.GetUID())
 
 	return nil
 }
 
+// UIDs returns the ids of all objects of the MetricsStore, including the
+// truncated ones.
+func (s *MetricsStore) UIDs() []types.UID {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	uids := make([]types.UID, 0, len(s.metrics)+len(s.objects)+len(s.aggregates)+len(s.truncated))
+	for uid := range s.metrics {
+		uids = append(uids, uid)
+	}
+	for uid := range s.objects {
+		uids = append(uids, uid)
+	}
+	for uid := range s.aggregates {
+		uids = append(uids, uid)
+	}
+	for uid := range s.truncated {
+		uids = append(uids, uid)
+	}
+	return uids
+}
+
+// DeleteUID deletes the object with the given id from the MetricsStore, e.g.
+// as it no longer exists but no delete event was received for it. It returns
+// whether the object was in the store.
+func (s *MetricsStore) DeleteUID(uid types.UID) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.delete(uid)
+}
+
+// delete deletes the object with the given id and returns whether it was in
+// the store. The caller must hold the lock.
+func (s *MetricsStore) delete(uid types.UID) bool {
+	delete(s.expires, uid)
+	deleted := false
+	if s.contains(uid) {
+		delete(s.metrics, uid)
+		delete(s.objects, uid)
+		delete(s.families, uid)
+		s.removeAggregates(uid)
+		s.limit.release(1, 0)
+		deleted = true
+	}
+	if _, ok := s.truncated[uid]; ok {
+		delete(s.truncated, uid)
+		s.limit.release(0, 1)
+		deleted = true
+	}
+	return deleted
+}
+
 // List implements the List method of the store interface.
 func (s *MetricsStore) List() []interface{} {
 	return nil
@@ -126,7 +293,16 @@ func (s *MetricsStore) GetByKey(key string) (item interface{}, exists bool, err
 // given list.
 func (s *MetricsStore) Replace(list []interface{}, _ string) error {
 	s.mutex.Lock()
-	s.metrics = map[types.UID][][]byte{}
+	s.limit.release(len(s.metrics)+len(s.objects)+len(s.aggregates), len(s.truncated))
+	s.metrics = map[types.UID]objectMetrics{}
+	s.objects = map[types.UID]lazyObject{}
+	s.families = map[types.UID][]metric.FamilyInterface{}
+	s.aggregates = map[types.UID][][]aggregateContribution{}
+	if s.aggregate {
+		s.sums = newAggregateSums(len(s.headers))
+	}
+	s.truncated = map[types.UID]struct{}{}
+	s.expires = map[types.UID]time.Time{}
 	s.mutex.Unlock()
 
 	for _, o := range list {
@@ -136,10 +312,130 @@ func (s *MetricsStore) Replace(list []interface{}, _ string) error {
 		}
 	}
 
+	s.mutex.Lock()
+	if !s.synced {
+		s.synced = true
+		s.syncedAt = time.Now()
+	}
+	s.mutex.Unlock()
+
 	return nil
 }
 
-// Resync implements the Resync method of the store interface.
+// Resync implements the Resync method of the store interface. It drops the
+// metrics of expired objects.
 func (s *MetricsStore) Resync() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for uid, expires := range s.expires {
+		if !now.Before(expires) {
+			s.delete(uid)
+		}
+	}
 	return nil
 }
+
+// Families calls fn with the index and the metric family of each metric
+// family of each object, or of the sums of a store configured by
+// SetAggregateBy. The metric families of objects are only known to stores
+// configured by SetKeepFamilies or SetLazy, the metrics of other stores are
+// only kept in the text exposition format. fn must not modify the metrics.
+func (s *MetricsStore) Families(fn func(i int, f metric.Family)) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	switch {
+	case s.aggregate:
+		for i, groups := range s.sums {
+			metrics := make([]*metric.Metric, 0, len(groups))
+			for _, g := range groups {
+				m := g.metric
+				metrics = append(metrics, &m)
+			}
+			fn(i, metric.Family{Metrics: metrics})
+		}
+	case s.lazy:
+		for _, o := range s.objects {
+			for i, f := range s.generateMetricsFunc(o.obj) {
+				f.Inspect(func(f metric.Family) { fn(i, f) })
+			}
+		}
+	default:
+		for _, families := range s.families {
+			for i, f := range families {
+				f.Inspect(func(f metric.Family) { fn(i, f) })
+			}
+		}
+	}
+}
+
+// Synced returns whether the MetricsStore was populated by an initial list.
+func (s *MetricsStore) Synced() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.synced
+}
+
+// Stats are statistics of the metrics of one or more stores.
+type Stats struct {
+	// Objects is the number of objects.
+	Objects int
+	// Series is the number of series generated for the objects. The series
+	// of lazy stores are not counted, and only the summed series of stores
+	// configured by SetAggregateBy are.
+	Series int
+	// Bytes is the size of the series in the text exposition format, not
+	// counting the series of lazy and aggregating stores.
+	Bytes int
+	// Truncated is the number of objects without series, as the object
+	// limit was reached.
+	Truncated int
+	// Omitted is the number of objects beyond the TopK limit of each
+	// limited metric family of a MetricsWriter, i.e. the objects whose
+	// series of the family are not written. It counts objects, including
+	// those without series of the family.
+	Omitted map[string]int
+	// SyncDuration is the duration from the creation of the stores until
+	// they were populated by their initial list, or 0 if not all of them are
+	// yet.
+	SyncDuration time.Duration
+}
+
+// Stats returns statistics of the metrics of the MetricsStore. The metrics of
+// lazy stores are not generated to count them, so only their objects are
+// counted.
+func (s *MetricsStore) Stats() Stats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats := Stats{Objects: len(s.metrics) + len(s.objects) + len(s.aggregates), Truncated: len(s.truncated)}
+	for _, o := range s.metrics {
+		stats.Series += o.series()
+		stats.Bytes += len(o.data)
+	}
+	for _, groups := range s.sums {
+		stats.Series += len(groups)
+	}
+	if s.synced {
+		stats.SyncDuration = s.syncedAt.Sub(s.createdAt)
+	}
+	return stats
+}
+
+// contains returns whether metrics are generated for the object with the
+// given id. The caller must hold the lock.
+func (s *MetricsStore) contains(uid types.UID) bool {
+	if s.aggregate {
+		_, ok := s.aggregates[uid]
+		return ok
+	}
+	if s.lazy {
+		_, ok := s.objects[uid]
+		return ok
+	}
+	_, ok := s.metrics[uid]
+	return ok
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -94,3 +95,120 @@ func TestObjectsSameNameDifferentNamespaces(t *testing.T) {
 		}
 	}
 }
+
+func TestLazyMetricsStore(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		s := obj.(*v1.Service)
+		return []metric.FamilyInterface{&metric.Family{
+			Name: "kube_service_spec_type",
+			Metrics: []*metric.Metric{{
+				LabelKeys:   []string{"service", "type"},
+				LabelValues: []string{s.Name, string(s.Spec.Type)},
+				Value:       1,
+			}},
+		}}
+	}
+	ms := NewMetricsStore([]string{"# HELP kube_service_spec_type Type of the service."}, genFunc)
+	ms.SetLazy()
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "service",
+			UID:           "uid",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP},
+	}
+	if err := ms.Add(service); err != nil {
+		t.Fatal(err)
+	}
+	if service.ManagedFields != nil {
+		t.Errorf("expected the managed fields of the stored object to be dropped")
+	}
+	if len(ms.metrics) != 0 {
+		t.Errorf("expected no precomputed metrics, got %d", len(ms.metrics))
+	}
+
+	updated := service.DeepCopy()
+	updated.Spec.Type = v1.ServiceTypeLoadBalancer
+	if err := ms.Update(updated); err != nil {
+		t.Fatal(err)
+	}
+	w := strings.Builder{}
+	if err := NewMetricsWriter(ms).WriteAll(&w); err != nil {
+		t.Fatal(err)
+	}
+	want := "# HELP kube_service_spec_type Type of the service.\nkube_service_spec_type{service=\"service\",type=\"LoadBalancer\"} 1\n"
+	if w.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, w.String())
+	}
+	if stats := ms.Stats(); stats.Objects != 1 || stats.Series != 0 {
+		t.Errorf("expected 1 object without counted series, got %+v", stats)
+	}
+
+	if err := ms.Delete(updated); err != nil {
+		t.Fatal(err)
+	}
+	if stats := ms.Stats(); stats.Objects != 0 {
+		t.Errorf("expected no objects after the deletion, got %+v", stats)
+	}
+}
+
+func TestMetricsStoreExpiry(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		o, _ := meta.Accessor(obj)
+		return []metric.FamilyInterface{&metric.Family{
+			Name: "kube_pod_info",
+			Metrics: []*metric.Metric{{
+				LabelKeys:   []string{"pod"},
+				LabelValues: []string{o.GetName()},
+				Value:       1,
+			}},
+		}}
+	}
+	ms := NewMetricsStore([]string{"# HELP kube_pod_info Information about pod."}, genFunc)
+	expires := map[string]time.Time{
+		"expired":  time.Now().Add(-time.Minute),
+		"expiring": time.Now().Add(50 * time.Millisecond),
+	}
+	ms.SetExpiry(func(obj interface{}) (time.Time, bool) {
+		o, _ := meta.Accessor(obj)
+		t, ok := expires[o.GetName()]
+		return t, ok
+	})
+
+	pod := func(name string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}}
+	}
+	if err := ms.Replace([]interface{}{pod("running"), pod("expiring")}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := ms.Add(pod("expired")); err != nil {
+		t.Fatal(err)
+	}
+	if stats := ms.Stats(); stats.Objects != 2 {
+		t.Errorf("expected 2 objects, got %+v", stats)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if err := ms.Resync(); err != nil {
+		t.Fatal(err)
+	}
+	w := strings.Builder{}
+	if err := NewMetricsWriter(ms).WriteAll(&w); err != nil {
+		t.Fatal(err)
+	}
+	want := "# HELP kube_pod_info Information about pod.\nkube_pod_info{pod=\"running\"} 1\n"
+	if w.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, w.String())
+	}
+
+	// Objects which are updated into an expired state are dropped.
+	expires["running"] = time.Now()
+	if err := ms.Update(pod("running")); err != nil {
+		t.Fatal(err)
+	}
+	if stats := ms.Stats(); stats.Objects != 0 {
+		t.Errorf("expected no objects, got %+v", stats)
+	}
+}
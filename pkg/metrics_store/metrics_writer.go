@@ -17,8 +17,16 @@ limitations under the License.
 package metricsstore
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/kube-state-metrics/v2/pkg/aggregation"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
 )
 
 // MetricsWriterList represent a list of MetricsWriter
@@ -32,7 +40,14 @@ type MetricsWriterList []*MetricsWriter
 // metrics with the same name coming from different stores end up grouped together.
 // It also ensures that the metric headers are only written out once.
 type MetricsWriter struct {
-	stores []*MetricsStore
+	stores   []*MetricsStore
+	resource string
+	// topK is the number of most recently updated objects whose series are
+	// written for all metric families, and topKFamilies for individual
+	// metric families by their name. The series of all objects are written
+	// if 0.
+	topK         int
+	topKFamilies map[string]int
 }
 
 // NewMetricsWriter creates a new MetricsWriter.
@@ -42,6 +57,172 @@ func NewMetricsWriter(stores ...*MetricsStore) *MetricsWriter {
 	}
 }
 
+// NewResourceMetricsWriter creates a new MetricsWriter for the stores of the
+// given resource.
+func NewResourceMetricsWriter(resource string, stores ...*MetricsStore) *MetricsWriter {
+	return &MetricsWriter{
+		stores:   stores,
+		resource: resource,
+	}
+}
+
+// TopK configures the MetricsWriter to only write the series of the given
+// number of most recently updated objects for all metric families, or for
+// individual metric families by their name. A limit of a metric family takes
+// precedence. The series of all objects are written if the limit is 0.
+func (m *MetricsWriter) TopK(objects int, families map[string]int) {
+	m.topK = objects
+	m.topKFamilies = families
+}
+
+// familyTopK returns the limits of the number of objects whose series are
+// written by metric family index, or nil if the series of all objects are
+// written.
+func (m MetricsWriter) familyTopK() []int {
+	if m.topK == 0 && len(m.topKFamilies) == 0 {
+		return nil
+	}
+	headers := m.stores[0].headers
+	limits := make([]int, len(headers))
+	for i, h := range headers {
+		limits[i] = m.topK
+		if k, ok := m.topKFamilies[headerFamilyName(h)]; ok {
+			limits[i] = k
+		}
+	}
+	return limits
+}
+
+// headerFamilyName returns the name of the metric family of the given header.
+func headerFamilyName(header string) string {
+	for _, prefix := range []string{"# HELP ", "# TYPE "} {
+		if strings.HasPrefix(header, prefix) {
+			name, _, _ := strings.Cut(header[len(prefix):], " ")
+			return name
+		}
+	}
+	return ""
+}
+
+// headerFamily returns a metric family without metrics with the name, help
+// text and type of the given header.
+func headerFamily(header string) *metric.Family {
+	f := &metric.Family{}
+	for _, line := range strings.Split(header, "\n") {
+		if rest, ok := strings.CutPrefix(line, "# HELP "); ok {
+			f.Name, f.Help, _ = strings.Cut(rest, " ")
+		} else if rest, ok := strings.CutPrefix(line, "# TYPE "); ok {
+			_, t, _ := strings.Cut(rest, " ")
+			f.Type = metric.Type(t)
+		}
+	}
+	return f
+}
+
+// Families returns the metric families of all stores with at least one
+// series, with the series of all stores merged by family. The stores need to
+// be configured by SetKeepFamilies, SetLazy or SetAggregateBy. Unlike
+// WriteAll, it does not limit the series to those of the TopK objects.
+func (m MetricsWriter) Families() []*metric.Family {
+	if len(m.stores) == 0 {
+		return nil
+	}
+	families := make([]*metric.Family, len(m.stores[0].headers))
+	for i, h := range m.stores[0].headers {
+		families[i] = headerFamily(h)
+	}
+	for _, s := range m.stores {
+		s.Families(func(i int, f metric.Family) {
+			families[i].Metrics = append(families[i].Metrics, f.Metrics...)
+		})
+	}
+
+	nonEmpty := families[:0]
+	for _, f := range families {
+		if len(f.Metrics) > 0 {
+			nonEmpty = append(nonEmpty, f)
+		}
+	}
+	return nonEmpty
+}
+
+// recentObjectMetrics returns the metrics of the k most recently updated
+// objects of all stores ordered from the most to the least recently updated
+// object.
+func recentObjectMetrics(metrics []map[types.UID]objectMetrics, k int) []objectMetrics {
+	type entry struct {
+		uid types.UID
+		o   objectMetrics
+	}
+	h := newRecentHeap(k, func(a, b entry) bool {
+		if a.o.updated != b.o.updated {
+			return a.o.updated < b.o.updated
+		}
+		return a.uid > b.uid
+	})
+	for _, storeMetrics := range metrics {
+		for uid, o := range storeMetrics {
+			h.add(entry{uid: uid, o: o})
+		}
+	}
+	entries := h.sorted()
+	recent := make([]objectMetrics, len(entries))
+	for i, e := range entries {
+		recent[i] = e.o
+	}
+	return recent
+}
+
+// Resource returns the resource of the underlying stores, if known.
+func (m MetricsWriter) Resource() string {
+	return m.resource
+}
+
+// Stats returns statistics of the metrics of the underlying stores. The sync
+// duration is the one of the store synced last.
+func (m MetricsWriter) Stats() Stats {
+	var stats Stats
+	synced := true
+	for _, s := range m.stores {
+		st := s.Stats()
+		stats.Objects += st.Objects
+		stats.Series += st.Series
+		stats.Bytes += st.Bytes
+		stats.Truncated += st.Truncated
+		if st.SyncDuration == 0 {
+			synced = false
+		} else if st.SyncDuration > stats.SyncDuration {
+			stats.SyncDuration = st.SyncDuration
+		}
+	}
+	if !synced {
+		stats.SyncDuration = 0
+	}
+	// The series of aggregating stores are sums and not limited.
+	if limits := m.familyTopK(); limits != nil && !m.stores[0].aggregate {
+		for i, k := range limits {
+			if k > 0 && stats.Objects > k {
+				if stats.Omitted == nil {
+					stats.Omitted = map[string]int{}
+				}
+				stats.Omitted[headerFamilyName(m.stores[0].headers[i])] = stats.Objects - k
+			}
+		}
+	}
+	return stats
+}
+
+// Synced returns whether all underlying stores were populated by an initial
+// list.
+func (m MetricsWriter) Synced() bool {
+	for _, s := range m.stores {
+		if !s.Synced() {
+			return false
+		}
+	}
+	return true
+}
+
 // WriteAll writes out metrics from the underlying stores to the given writer.
 //
 // WriteAll writes metrics so that the ones with the same name
@@ -58,15 +239,41 @@ func (m MetricsWriter) WriteAll(w io.Writer) error {
 		}(s)
 	}
 
+	if m.stores[0].aggregate {
+		return m.writeAggregated(w)
+	}
+	if m.stores[0].lazy {
+		return m.writeLazy(w)
+	}
+
+	metrics := make([]map[types.UID]objectMetrics, len(m.stores))
+	for i, s := range m.stores {
+		metrics[i] = s.metrics
+	}
+	limits := m.familyTopK()
+	var recent []objectMetrics
+	if limits != nil {
+		recent = recentObjectMetrics(metrics, slices.Max(limits))
+	}
+
 	for i, help := range m.stores[0].headers {
 		_, err := w.Write([]byte(help + "\n"))
 		if err != nil {
 			return fmt.Errorf("failed to write help text: %v", err)
 		}
 
-		for _, s := range m.stores {
-			for _, metricFamilies := range s.metrics {
-				_, err := w.Write(metricFamilies[i])
+		if limits != nil && limits[i] > 0 {
+			for _, o := range recent[:min(limits[i], len(recent))] {
+				if _, err := w.Write(o.family(i)); err != nil {
+					return fmt.Errorf("failed to write metrics family: %v", err)
+				}
+			}
+			continue
+		}
+
+		for _, storeMetrics := range metrics {
+			for _, o := range storeMetrics {
+				_, err := w.Write(o.family(i))
 				if err != nil {
 					return fmt.Errorf("failed to write metrics family: %v", err)
 				}
@@ -75,3 +282,107 @@ func (m MetricsWriter) WriteAll(w io.Writer) error {
 	}
 	return nil
 }
+
+// writeLazy writes the metrics of lazy stores, whose read locks have to be
+// held. The metrics of one object at a time are generated and appended to the
+// series of their metric families, which are written once all objects were
+// iterated so that the series of each metric family are grouped together.
+func (m MetricsWriter) writeLazy(w io.Writer) error {
+	headers := m.stores[0].headers
+	limits := m.familyTopK()
+	series := make([]bytes.Buffer, len(headers))
+	generate := func(s *MetricsStore, o lazyObject, rank int) {
+		for i, f := range s.generateMetricsFunc(o.obj) {
+			if limits == nil || limits[i] == 0 || rank < limits[i] {
+				series[i].Write(f.ByteSlice())
+			}
+		}
+	}
+
+	if limits == nil {
+		for _, s := range m.stores {
+			for _, o := range s.objects {
+				generate(s, o, 0)
+			}
+		}
+	} else {
+		// The metrics of objects beyond the limits of all metric
+		// families are not generated.
+		k := 0
+		for _, l := range limits {
+			if l == 0 {
+				k = -1
+				break
+			}
+			if l > k {
+				k = l
+			}
+		}
+		for rank, o := range recentLazyObjects(m.stores, k) {
+			generate(o.store, o.lazyObject, rank)
+		}
+	}
+
+	for i, help := range headers {
+		if _, err := w.Write([]byte(help + "\n")); err != nil {
+			return fmt.Errorf("failed to write help text: %v", err)
+		}
+		if _, err := w.Write(series[i].Bytes()); err != nil {
+			return fmt.Errorf("failed to write metrics family: %v", err)
+		}
+	}
+	return nil
+}
+
+// storeLazyObject is an object of a lazy store.
+type storeLazyObject struct {
+	lazyObject
+	store *MetricsStore
+	uid   types.UID
+}
+
+// recentLazyObjects returns the k most recently updated objects of the given
+// lazy stores, or all of them if k is negative, ordered from the most to the
+// least recently updated object like recentObjectMetrics.
+func recentLazyObjects(stores []*MetricsStore, k int) []storeLazyObject {
+	if k < 0 {
+		k = 0
+		for _, s := range stores {
+			k += len(s.objects)
+		}
+	}
+	h := newRecentHeap(k, func(a, b storeLazyObject) bool {
+		if a.updated != b.updated {
+			return a.updated < b.updated
+		}
+		return a.uid > b.uid
+	})
+	for _, s := range stores {
+		for uid, o := range s.objects {
+			h.add(storeLazyObject{lazyObject: o, store: s, uid: uid})
+		}
+	}
+	return h.sorted()
+}
+
+// writeAggregated writes the sums of stores configured by SetAggregateBy,
+// whose read locks have to be held. The sums of all stores are added up, as
+// the stores of a resource may have different objects with the same values of
+// the aggregated labels.
+func (m MetricsWriter) writeAggregated(w io.Writer) error {
+	for i, help := range m.stores[0].headers {
+		if _, err := w.Write([]byte(help + "\n")); err != nil {
+			return fmt.Errorf("failed to write help text: %v", err)
+		}
+		a := aggregation.NewAggregator(aggregation.Sum, m.stores[0].aggregateBy)
+		for _, s := range m.stores {
+			for _, g := range s.sums[i] {
+				a.Add(&g.metric)
+			}
+		}
+		if _, err := w.Write(a.Family(headerFamilyName(help), "", "").ByteSlice()); err != nil {
+			return fmt.Errorf("failed to write metrics family: %v", err)
+		}
+	}
+	return nil
+}
@@ -17,12 +17,15 @@ limitations under the License.
 package metricsstore_test
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"k8s.io/kube-state-metrics/v2/pkg/metric"
 	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
@@ -231,3 +234,230 @@ func TestWriteAllWithMultipleStores(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteAllAggregated(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		pod := obj.(*v1.Pod)
+		return []metric.FamilyInterface{&metric.Family{
+			Name: "kube_pod_status_phase",
+			Metrics: []*metric.Metric{{
+				LabelKeys:   []string{"namespace", "pod", "phase"},
+				LabelValues: []string{pod.Namespace, pod.Name, string(pod.Status.Phase)},
+				Value:       1,
+			}},
+		}}
+	}
+	headers := []string{"# HELP kube_pod_status_phase The pods current phase.\n# TYPE kube_pod_status_phase gauge"}
+	store1 := metricsstore.NewMetricsStore(headers, genFunc)
+	store1.SetAggregateBy([]string{"namespace", "phase"})
+	store2 := metricsstore.NewMetricsStore(headers, genFunc)
+	store2.SetAggregateBy([]string{"namespace", "phase"})
+	pods := []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns1"}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns1"}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "ns2"}, Status: v1.PodStatus{Phase: v1.PodPending}},
+	}
+	for i, pod := range pods {
+		pod.UID = types.UID(pod.Name)
+		store := store1
+		if i == 2 {
+			store = store2
+		}
+		if err := store.Add(pod); err != nil {
+			t.Fatalf("failed to add pod %d: %v", i, err)
+		}
+	}
+
+	mw := metricsstore.NewResourceMetricsWriter("pods", store1, store2)
+	write := func() string {
+		w := strings.Builder{}
+		if err := mw.WriteAll(&w); err != nil {
+			t.Fatal(err)
+		}
+		return w.String()
+	}
+	expected := `# HELP kube_pod_status_phase The pods current phase.
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="ns1",phase="Running"} 2
+kube_pod_status_phase{namespace="ns2",phase="Pending"} 1
+`
+	if got := write(); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+
+	// The sums are updated as the objects change, and sums without any
+	// objects are dropped.
+	pods[2].Status.Phase = v1.PodRunning
+	if err := store2.Update(pods[2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := store1.Delete(pods[0]); err != nil {
+		t.Fatal(err)
+	}
+	expected = `# HELP kube_pod_status_phase The pods current phase.
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="ns1",phase="Running"} 1
+kube_pod_status_phase{namespace="ns2",phase="Running"} 1
+`
+	if got := write(); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+	if stats := mw.Stats(); stats.Objects != 2 || stats.Series != 2 {
+		t.Errorf("expected 2 objects and 2 series, got %+v", stats)
+	}
+
+	// The sums of aggregating stores are not limited by TopK.
+	mw.TopK(1, nil)
+	if got := write(); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+	if omitted := mw.Stats().Omitted; omitted != nil {
+		t.Errorf("expected no omitted objects, got %v", omitted)
+	}
+}
+
+func TestWriteAllTopK(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("lazy=%t", lazy), func(t *testing.T) {
+			testWriteAllTopK(t, lazy)
+		})
+	}
+}
+
+func testWriteAllTopK(t *testing.T, lazy bool) {
+	generated := 0
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		generated++
+		o, err := meta.Accessor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		families := make([]metric.FamilyInterface, 0, 2)
+		for _, name := range []string{"kube_job_info", "kube_job_created"} {
+			families = append(families, &metric.Family{
+				Name: name,
+				Metrics: []*metric.Metric{
+					{
+						LabelKeys:   []string{"uid"},
+						LabelValues: []string{string(o.GetUID())},
+						Value:       1,
+					},
+				},
+			})
+		}
+		return families
+	}
+	headers := []string{
+		"# HELP kube_job_info Information about job.\n# TYPE kube_job_info gauge",
+		"# HELP kube_job_created Unix creation timestamp\n# TYPE kube_job_created gauge",
+	}
+	s1 := metricsstore.NewMetricsStore(headers, genFunc)
+	s2 := metricsstore.NewMetricsStore(headers, genFunc)
+	if lazy {
+		s1.SetLazy()
+		s2.SetLazy()
+	}
+	updated := func(sec int64) *metav1.Time {
+		t := metav1.Unix(sec, 0)
+		return &t
+	}
+	jobs := []struct {
+		store *metricsstore.MetricsStore
+		job   *metav1.PartialObjectMetadata
+	}{
+		{s1, &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{UID: "old", CreationTimestamp: *updated(100)}}},
+		{s2, &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{UID: "updated", CreationTimestamp: *updated(100), ManagedFields: []metav1.ManagedFieldsEntry{{Time: updated(400)}}}}},
+		{s1, &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{UID: "new", CreationTimestamp: *updated(300)}}},
+	}
+	for _, j := range jobs {
+		if err := j.store.Add(j.job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mw := metricsstore.NewResourceMetricsWriter("jobs", s1, s2)
+	mw.TopK(2, map[string]int{"kube_job_created": 1})
+	generated = 0
+	w := strings.Builder{}
+	if err := mw.WriteAll(&w); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+	want := `# HELP kube_job_info Information about job.
+# TYPE kube_job_info gauge
+kube_job_info{uid="updated"} 1
+kube_job_info{uid="new"} 1
+# HELP kube_job_created Unix creation timestamp
+# TYPE kube_job_created gauge
+kube_job_created{uid="updated"} 1
+`
+	if w.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", w.String(), want)
+	}
+	if lazy && generated != 2 {
+		t.Errorf("expected the metrics of the 2 most recently updated objects to be generated, got %d", generated)
+	}
+
+	omitted := mw.Stats().Omitted
+	if len(omitted) != 2 || omitted["kube_job_info"] != 1 || omitted["kube_job_created"] != 2 {
+		t.Errorf("unexpected omitted objects %v", omitted)
+	}
+}
+
+func TestFamilies(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		pod := obj.(*v1.Pod)
+		return []metric.FamilyInterface{
+			&metric.Family{
+				Name: "kube_pod_info",
+				Metrics: []*metric.Metric{{
+					LabelKeys:   []string{"namespace", "pod"},
+					LabelValues: []string{pod.Namespace, pod.Name},
+					Value:       1,
+				}},
+			},
+			&metric.Family{Name: "kube_pod_deletion_timestamp"},
+		}
+	}
+	headers := []string{
+		"# HELP kube_pod_info [STABLE] Information about pod.\n# TYPE kube_pod_info gauge",
+		"# HELP kube_pod_deletion_timestamp Unix deletion timestamp\n# TYPE kube_pod_deletion_timestamp gauge",
+	}
+	store := metricsstore.NewMetricsStore(headers, genFunc)
+	store.SetKeepFamilies()
+	lazyStore := metricsstore.NewMetricsStore(headers, genFunc)
+	lazyStore.SetLazy()
+	pods := []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{UID: "a", Name: "a", Namespace: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{UID: "b", Name: "b", Namespace: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{UID: "c", Name: "c", Namespace: "ns2"}},
+	}
+	for _, pod := range pods[:2] {
+		if err := store.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := lazyStore.Add(pods[2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(pods[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	families := metricsstore.NewMetricsWriter(store, lazyStore).Families()
+	// Families without series are omitted.
+	if len(families) != 1 {
+		t.Fatalf("expected 1 family, got %d", len(families))
+	}
+	f := families[0]
+	if f.Name != "kube_pod_info" || f.Help != "[STABLE] Information about pod." || f.Type != metric.Gauge {
+		t.Errorf("unexpected family %s %q %s", f.Name, f.Help, f.Type)
+	}
+	got := make([]string, 0, len(f.Metrics))
+	for _, m := range f.Metrics {
+		got = append(got, strings.Join(m.LabelValues, "/"))
+	}
+	sort.Strings(got)
+	if expected := []string{"ns1/b", "ns2/c"}; strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected series %v, got %v", expected, got)
+	}
+}
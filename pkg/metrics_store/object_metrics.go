@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// objectMetrics are the metric families of a single object in the text
+// exposition format. Instead of a slice per metric family, all families are
+// kept in a single buffer of the exact size, which reduces the number of
+// allocations and the heap overhead per object to two slices.
+type objectMetrics struct {
+	// data contains the metric families one after another.
+	data []byte
+	// ends contains the offset in data at which each metric family ends.
+	ends []uint32
+	// updated is the time the object was last updated as Unix timestamp.
+	updated int64
+}
+
+// newObjectMetrics encodes the given metric families of an object updated at
+// the given time into objectMetrics.
+func newObjectMetrics(families []metric.FamilyInterface, updated int64) objectMetrics {
+	encoded := make([][]byte, len(families))
+	size := 0
+	for i, f := range families {
+		encoded[i] = f.ByteSlice()
+		size += len(encoded[i])
+	}
+
+	o := objectMetrics{
+		data:    make([]byte, 0, size),
+		ends:    make([]uint32, len(families)),
+		updated: updated,
+	}
+	for i, b := range encoded {
+		o.data = append(o.data, b...)
+		o.ends[i] = uint32(len(o.data))
+	}
+	return o
+}
+
+// family returns the i-th metric family.
+func (o objectMetrics) family(i int) []byte {
+	start := uint32(0)
+	if i > 0 {
+		start = o.ends[i-1]
+	}
+	return o.data[start:o.ends[i]]
+}
+
+// series returns the number of series of all metric families.
+func (o objectMetrics) series() int {
+	return bytes.Count(o.data, []byte{'\n'})
+}
+
+// lastUpdated returns the time the object was last updated as Unix timestamp,
+// which is the latest of its creation, deletion and managed fields times.
+func lastUpdated(o metav1.Object) int64 {
+	updated := o.GetCreationTimestamp().Unix()
+	if t := o.GetDeletionTimestamp(); t != nil && t.Unix() > updated {
+		updated = t.Unix()
+	}
+	for _, f := range o.GetManagedFields() {
+		if f.Time != nil && f.Time.Unix() > updated {
+			updated = f.Time.Unix()
+		}
+	}
+	return updated
+}
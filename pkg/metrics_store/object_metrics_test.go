@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+func TestObjectMetrics(t *testing.T) {
+	families := []metric.FamilyInterface{
+		&metric.Family{Name: "kube_pod_info", Metrics: []*metric.Metric{
+			{LabelKeys: []string{"pod"}, LabelValues: []string{"a"}, Value: 1},
+		}},
+		&metric.Family{Name: "kube_pod_empty"},
+		&metric.Family{Name: "kube_pod_labels", Metrics: []*metric.Metric{
+			{LabelKeys: []string{"pod"}, LabelValues: []string{"a"}, Value: 1},
+			{LabelKeys: []string{"pod"}, LabelValues: []string{"b"}, Value: 1},
+		}},
+	}
+
+	o := newObjectMetrics(families, 0)
+	if len(o.data) != cap(o.data) {
+		t.Errorf("expected a buffer of the exact size, got length %d and capacity %d", len(o.data), cap(o.data))
+	}
+	for i, f := range families {
+		if got, want := string(o.family(i)), string(f.ByteSlice()); got != want {
+			t.Errorf("family %d: expected %q, got %q", i, want, got)
+		}
+	}
+	if got := o.series(); got != 3 {
+		t.Errorf("expected 3 series, got %d", got)
+	}
+}
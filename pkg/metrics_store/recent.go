@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// recentHeap is a min-heap of objects by the time they were last updated,
+// which keeps the k most recently updated of the objects added to it. This
+// avoids sorting all objects of a resource to write the series of a few.
+type recentHeap[T any] struct {
+	k       int
+	objects []T
+	// before returns whether a was updated before b. Objects updated at
+	// the same time need to be ordered as well for the result to be stable.
+	before func(a, b T) bool
+}
+
+func newRecentHeap[T any](k int, before func(a, b T) bool) *recentHeap[T] {
+	return &recentHeap[T]{k: k, before: before}
+}
+
+func (h *recentHeap[T]) Len() int           { return len(h.objects) }
+func (h *recentHeap[T]) Less(i, j int) bool { return h.before(h.objects[i], h.objects[j]) }
+func (h *recentHeap[T]) Swap(i, j int)      { h.objects[i], h.objects[j] = h.objects[j], h.objects[i] }
+func (h *recentHeap[T]) Push(x any)         { h.objects = append(h.objects, x.(T)) }
+
+func (h *recentHeap[T]) Pop() any {
+	o := h.objects[len(h.objects)-1]
+	h.objects = h.objects[:len(h.objects)-1]
+	return o
+}
+
+// add adds the object, replacing the least recently updated object once the
+// heap holds k objects if it was updated after it.
+func (h *recentHeap[T]) add(o T) {
+	if len(h.objects) < h.k {
+		heap.Push(h, o)
+		return
+	}
+	if len(h.objects) > 0 && h.before(h.objects[0], o) {
+		h.objects[0] = o
+		heap.Fix(h, 0)
+	}
+}
+
+// sorted returns the objects of the heap ordered from the most to the least
+// recently updated object.
+func (h *recentHeap[T]) sorted() []T {
+	sort.Slice(h.objects, func(i, j int) bool {
+		return h.before(h.objects[j], h.objects[i])
+	})
+	return h.objects
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecentHeap(t *testing.T) {
+	type object struct {
+		uid     string
+		updated int64
+	}
+	objects := []object{{"a", 100}, {"b", 400}, {"c", 300}, {"d", 200}, {"e", 300}, {"f", 50}}
+	before := func(a, b object) bool {
+		if a.updated != b.updated {
+			return a.updated < b.updated
+		}
+		return a.uid > b.uid
+	}
+
+	for k, want := range map[int][]object{
+		0: nil,
+		1: {{"b", 400}},
+		3: {{"b", 400}, {"c", 300}, {"e", 300}},
+		8: {{"b", 400}, {"c", 300}, {"e", 300}, {"d", 200}, {"a", 100}, {"f", 50}},
+	} {
+		h := newRecentHeap(k, before)
+		for _, o := range objects {
+			h.add(o)
+		}
+		if got := h.sorted(); !reflect.DeepEqual(got, want) {
+			t.Errorf("k=%d: expected %v, got %v", k, want, got)
+		}
+	}
+}
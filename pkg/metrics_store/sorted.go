@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"io"
+	"sort"
+)
+
+// SortedWriter buffers the metrics written to it in the text exposition
+// format and writes them to the underlying writer on Flush with the families
+// sorted by name and the series of each family sorted by name and labels, so
+// that the same metrics are always written as the same bytes.
+type SortedWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+// sortedFamily is a family of the metrics written to a SortedWriter.
+type sortedFamily struct {
+	name    []byte
+	headers [][]byte
+	series  [][]byte
+}
+
+// NewSortedWriter returns a writer sorting the metrics written to it before
+// writing them to w. Flush has to be called once all metrics are written.
+func NewSortedWriter(w io.Writer) *SortedWriter {
+	return &SortedWriter{w: w}
+}
+
+// Write implements the io.Writer interface.
+func (s *SortedWriter) Write(p []byte) (int, error) {
+	return s.buf.Write(p)
+}
+
+// Flush writes the sorted metrics to the underlying writer.
+func (s *SortedWriter) Flush() error {
+	var families []*sortedFamily
+	var current *sortedFamily
+	data := s.buf.Bytes()
+	for len(data) > 0 {
+		var line []byte
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i+1], data[i+1:]
+		} else {
+			line, data = append(append([]byte{}, data...), '\n'), nil
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		if line[0] == '#' {
+			name := headerName(line)
+			if current == nil || len(current.series) > 0 || (name != nil && !bytes.Equal(current.name, name)) {
+				current = &sortedFamily{name: name}
+				families = append(families, current)
+			}
+			current.headers = append(current.headers, line)
+			continue
+		}
+		if current == nil {
+			current = &sortedFamily{name: seriesName(line)}
+			families = append(families, current)
+		}
+		current.series = append(current.series, line)
+	}
+
+	sort.SliceStable(families, func(i, j int) bool {
+		return bytes.Compare(families[i].name, families[j].name) < 0
+	})
+	var out bytes.Buffer
+	out.Grow(s.buf.Len())
+	for _, f := range families {
+		sort.SliceStable(f.series, func(i, j int) bool {
+			return bytes.Compare(f.series[i], f.series[j]) < 0
+		})
+		for _, line := range f.headers {
+			out.Write(line)
+		}
+		for _, line := range f.series {
+			out.Write(line)
+		}
+	}
+	s.buf.Reset()
+	_, err := s.w.Write(out.Bytes())
+	return err
+}
+
+// headerName returns the family name of a HELP or TYPE line, or nil for other
+// comments.
+func headerName(line []byte) []byte {
+	fields := bytes.Fields(line)
+	if len(fields) < 3 || (!bytes.Equal(fields[1], []byte("HELP")) && !bytes.Equal(fields[1], []byte("TYPE"))) {
+		return nil
+	}
+	return fields[2]
+}
+
+// seriesName returns the metric name of a series line.
+func seriesName(line []byte) []byte {
+	if i := bytes.IndexAny(line, "{ "); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSortedWriter(t *testing.T) {
+	in := "kube_state_metrics_standby 0\n" +
+		"# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\n" +
+		"kube_pod_info{namespace=\"ns2\",pod=\"b\"} 1\n" +
+		"kube_pod_info{namespace=\"ns1\",pod=\"c\"} 1\n" +
+		"kube_pod_info{namespace=\"ns1\",pod=\"a\"} 1\n" +
+		"# HELP kube_deployment_labels Labels\n# TYPE kube_deployment_labels gauge\n" +
+		"# HELP kube_node_info Info\n# TYPE kube_node_info gauge\n" +
+		"kube_node_info{node=\"n2\"} 1\n" +
+		"kube_node_info{node=\"n1\"} 1"
+	want := "# HELP kube_deployment_labels Labels\n# TYPE kube_deployment_labels gauge\n" +
+		"# HELP kube_node_info Info\n# TYPE kube_node_info gauge\n" +
+		"kube_node_info{node=\"n1\"} 1\n" +
+		"kube_node_info{node=\"n2\"} 1\n" +
+		"# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\n" +
+		"kube_pod_info{namespace=\"ns1\",pod=\"a\"} 1\n" +
+		"kube_pod_info{namespace=\"ns1\",pod=\"c\"} 1\n" +
+		"kube_pod_info{namespace=\"ns2\",pod=\"b\"} 1\n" +
+		"kube_state_metrics_standby 0\n"
+
+	buf := &bytes.Buffer{}
+	w := NewSortedWriter(buf)
+	for i := 0; i < len(in); i += 7 {
+		end := i + 7
+		if end > len(in) {
+			end = len(in)
+		}
+		if _, err := w.Write([]byte(in[i:end])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing to be written before Flush, got %q", buf.String())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// compressor is a compressing writer which can be reused for multiple responses.
+type compressor interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// compressors holds a pool of compressors per supported content encoding.
+// Compressors keep large internal buffers, so reusing them considerably
+// reduces the allocations of each scrape.
+var compressors = map[string]*sync.Pool{
+	"gzip": {New: func() interface{} { return gzip.NewWriter(nil) }},
+}
+
+// supportedEncodings lists the supported content encodings in order of
+// preference if a client accepts several of them with the same quality.
+var supportedEncodings = []string{"gzip"}
+
+// negotiateEncoding returns the supported content encoding preferred by the
+// given Accept-Encoding header, or an empty string if the response should not
+// be compressed.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(key) != "q" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				q = 0
+			} else {
+				q = v
+			}
+		}
+		qualities[coding] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range supportedEncodings {
+		q, ok := qualities[encoding]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// getCompressor returns a pooled compressor for the given encoding writing to w.
+func getCompressor(encoding string, w io.Writer) compressor {
+	c := compressors[encoding].Get().(compressor)
+	c.Reset(w)
+	return c
+}
+
+// putCompressor returns a compressor to its pool. It must be closed beforehand.
+func putCompressor(encoding string, c compressor) {
+	c.Reset(nil)
+	compressors[encoding].Put(c)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{acceptEncoding: "", want: ""},
+		{acceptEncoding: "identity", want: ""},
+		{acceptEncoding: "gzip", want: "gzip"},
+		{acceptEncoding: "GZIP", want: "gzip"},
+		{acceptEncoding: "deflate, gzip;q=0.5", want: "gzip"},
+		{acceptEncoding: "gzip;q=0", want: ""},
+		{acceptEncoding: "gzip;q=invalid", want: ""},
+		{acceptEncoding: "*", want: "gzip"},
+		{acceptEncoding: "*, gzip;q=0", want: ""},
+	}
+
+	for _, test := range tests {
+		if got := negotiateEncoding(test.acceptEncoding); got != test.want {
+			t.Errorf("negotiateEncoding(%q): expected %q, got %q", test.acceptEncoding, test.want, got)
+		}
+	}
+}
+
+func TestPooledCompressor(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		buf := &bytes.Buffer{}
+		c := getCompressor("gzip", buf)
+		if _, err := c.Write([]byte("kube_pod_info 1\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+		putCompressor("gzip", c)
+
+		r, err := gzip.NewReader(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "kube_pod_info 1\n" {
+			t.Errorf("expected decompressed response %q, got %q", "kube_pod_info 1\n", got)
+		}
+	}
+}
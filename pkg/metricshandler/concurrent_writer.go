@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+// encodeBuffers holds the buffers resources are encoded into by
+// writeResourcesConcurrently. The buffers of large resources grow to several
+// megabytes, so reusing them considerably reduces the allocations of each
+// scrape.
+var encodeBuffers = sync.Pool{New: func() interface{} { return &bytes.Buffer{} }}
+
+type encodedResource struct {
+	buf *bytes.Buffer
+	err error
+}
+
+// writeResourcesConcurrently encodes the metrics of the given writers with the
+// given number of workers and writes them to w in the order of the writers.
+// The encoded metrics of each resource are written as soon as the ones of all
+// preceding resources are, and at most one buffer per worker is held at any
+// time, which bounds the memory of a scrape.
+func (m *MetricsHandler) writeResourcesConcurrently(ctx context.Context, w io.Writer, writers metricsstore.MetricsWriterList, workers int) error {
+	results := make([]chan encodedResource, len(writers))
+	for i := range results {
+		// Buffered, so that workers never block once writing w failed.
+		results[i] = make(chan encodedResource, 1)
+	}
+	// slots is acquired before a resource is encoded and released once it was
+	// written to w.
+	slots := make(chan struct{}, workers)
+	done := make(chan struct{})
+	// Workers are waited for on return, as the caller's read lock has to be
+	// held while encoding.
+	var wg sync.WaitGroup
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, mw := range writers {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			wg.Add(1)
+			go func(result chan<- encodedResource, mw *metricsstore.MetricsWriter) {
+				defer wg.Done()
+				buf := encodeBuffers.Get().(*bytes.Buffer)
+				buf.Reset()
+				err := m.writeResource(ctx, buf, mw)
+				result <- encodedResource{buf: buf, err: err}
+			}(results[i], mw)
+		}
+	}()
+
+	for _, result := range results {
+		r := <-result
+		err := r.err
+		if err == nil {
+			_, err = w.Write(r.buf.Bytes())
+		}
+		encodeBuffers.Put(r.buf)
+		<-slots
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestWriteResourcesConcurrently(t *testing.T) {
+	sequential := New(&options.Options{}, nil, nil, false)
+	concurrent := New(&options.Options{ScrapeWorkers: 2}, nil, nil, false)
+	for i := 0; i < 3; i++ {
+		sequential.metricsWriters = append(sequential.metricsWriters, newScopeTestWriters(t)...)
+		concurrent.metricsWriters = append(concurrent.metricsWriters, newScopeTestWriters(t)...)
+	}
+
+	// Objects of a store are written in random order, so only a single pod
+	// is written per resource.
+	scope := parseScrapeScope(url.Values{"namespace": {"team-a"}})
+	want := &strings.Builder{}
+	if err := sequential.writeText(context.Background(), want, scope, false); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		got := &strings.Builder{}
+		if err := concurrent.writeText(context.Background(), got, scope, false); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want.String() {
+			t.Fatalf("expected the metrics in the order of the resources\n%s\ngot\n%s", want, got)
+		}
+	}
+	if len(concurrent.scrapeBytes) != 2 {
+		t.Errorf("expected the written bytes of 2 resources, got %v", concurrent.scrapeBytes)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ConfigStatus is the status of a configuration file loaded on startup.
+type ConfigStatus struct {
+	// Type is the type of the configuration, e.g. config or
+	// customresourceconfig.
+	Type string `json:"type"`
+	File string `json:"file"`
+	// Successful is whether the configuration was loaded successfully.
+	Successful bool `json:"successful"`
+	// LoadedAt is the time the configuration was loaded successfully.
+	LoadedAt *time.Time `json:"loadedAt,omitempty"`
+}
+
+// healthStatus is the JSON representation of the status served by the health
+// endpoints.
+type healthStatus struct {
+	// Status is ok if the instance is live, respectively ready.
+	Status      string           `json:"status"`
+	Ready       bool             `json:"ready"`
+	Synced      bool             `json:"synced"`
+	Standby     bool             `json:"standby"`
+	Shard       int32            `json:"shard"`
+	TotalShards int              `json:"totalShards"`
+	Resources   []resourceHealth `json:"resources"`
+	// Watches are the last errors of the list and watch requests of the
+	// reflectors by the type of their objects, e.g. *v1.Pod.
+	Watches []watchHealth  `json:"watches,omitempty"`
+	Configs []ConfigStatus `json:"configs,omitempty"`
+}
+
+type resourceHealth struct {
+	Resource string `json:"resource"`
+	Synced   bool   `json:"synced"`
+	Objects  int    `json:"objects"`
+}
+
+type watchHealth struct {
+	Type          string    `json:"type"`
+	LastError     string    `json:"lastError"`
+	LastErrorTime time.Time `json:"lastErrorTime"`
+}
+
+// AddConfigStatus adds the status of a loaded configuration file to the
+// status served by the health endpoints. It must be called before Run.
+func (m *MetricsHandler) AddConfigStatus(s ConfigStatus) {
+	m.configs = append(m.configs, s)
+}
+
+// LivenessHandler returns a handler responding with OK. With the verbose query
+// parameter, it serves the status of the stores, their last list or watch
+// errors, the loaded configuration and the shard of this instance as JSON. It
+// always responds with 200 while the instance serves requests.
+func (m *MetricsHandler) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := m.healthStatus()
+		status.Status = "ok"
+		writeHealthStatus(w, r, http.StatusOK, status)
+	})
+}
+
+// ReadinessHandler returns a handler serving the same status as
+// LivenessHandler. It responds with 503 until all stores were populated by
+// their initial list or the ready timeout passed since the MetricsHandler was
+// created.
+func (m *MetricsHandler) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := m.healthStatus()
+		if !status.Ready {
+			status.Status = "not ready"
+			writeHealthStatus(w, r, http.StatusServiceUnavailable, status)
+			return
+		}
+		status.Status = "ok"
+		writeHealthStatus(w, r, http.StatusOK, status)
+	})
+}
+
+func (m *MetricsHandler) healthStatus() healthStatus {
+	m.mtx.RLock()
+	status := healthStatus{
+		Synced:      m.synced(),
+		Standby:     m.IsStandby(),
+		Shard:       m.curShard,
+		TotalShards: m.curTotalShards,
+		Resources:   make([]resourceHealth, 0, len(m.metricsWriters)),
+		Configs:     m.configs,
+	}
+	for _, mw := range m.metricsWriters {
+		status.Resources = append(status.Resources, resourceHealth{
+			Resource: mw.Resource(),
+			Synced:   mw.Synced(),
+			Objects:  mw.Stats().Objects,
+		})
+	}
+	m.mtx.RUnlock()
+	sort.Slice(status.Resources, func(i, j int) bool {
+		return status.Resources[i].Resource < status.Resources[j].Resource
+	})
+
+	if m.watchHealth != nil {
+		for typ, err := range m.watchHealth.LastErrors() {
+			status.Watches = append(status.Watches, watchHealth{Type: typ, LastError: err.Message, LastErrorTime: err.Time})
+		}
+	}
+	sort.Slice(status.Watches, func(i, j int) bool {
+		return status.Watches[i].Type < status.Watches[j].Type
+	})
+
+	status.Ready = status.Synced || status.Standby || (m.opts.ReadyTimeout > 0 && time.Since(m.createdAt) >= m.opts.ReadyTimeout)
+	return status
+}
+
+// writeHealthStatus writes the status as JSON if the request has the verbose
+// query parameter, and the text of the status code otherwise.
+func writeHealthStatus(w http.ResponseWriter, r *http.Request, code int, status healthStatus) {
+	if _, ok := r.URL.Query()["verbose"]; !ok {
+		w.WriteHeader(code)
+		w.Write([]byte(http.StatusText(code)))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		klog.ErrorS(err, "Failed to write health status")
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/watch"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	opts := &options.Options{ReadyTimeout: time.Hour}
+	m := New(opts, nil, nil, false)
+	m.watchHealth = watch.NewHealth(0)
+	m.AddConfigStatus(ConfigStatus{Type: "config", File: "/etc/ksm/config.yaml"})
+
+	lw := watch.NewHealthTrackingListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return nil, errors.New("forbidden")
+		},
+	}, m.watchHealth.Track(context.Background(), "*v1.Pod"))
+	lw.List(metav1.ListOptions{}) //nolint:errcheck
+
+	get := func(h http.Handler) (int, healthStatus) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?verbose", nil))
+		var status healthStatus
+		if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, status
+	}
+
+	// Without the verbose parameter, only the status code and its text are
+	// written.
+	rec := httptest.NewRecorder()
+	m.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "OK" {
+		t.Errorf("expected OK, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Stores are not synced before sharding is configured.
+	code, status := get(m.ReadinessHandler())
+	if code != http.StatusServiceUnavailable || status.Ready || status.Status != "not ready" {
+		t.Errorf("expected not to be ready, got %d %+v", code, status)
+	}
+	if len(status.Watches) != 1 || status.Watches[0].Type != "*v1.Pod" || status.Watches[0].LastError != "forbidden" {
+		t.Errorf("expected the last error of listing pods, got %+v", status.Watches)
+	}
+	if len(status.Configs) != 1 || status.Configs[0].Successful {
+		t.Errorf("expected the failed config, got %+v", status.Configs)
+	}
+	if code, status := get(m.LivenessHandler()); code != http.StatusOK || status.Status != "ok" {
+		t.Errorf("expected to be live, got %d %+v", code, status)
+	}
+
+	m.ctx = context.Background()
+	m.curShard, m.curTotalShards = 1, 2
+	m.metricsWriters = metricsstore.MetricsWriterList{metricsstore.NewResourceMetricsWriter("pods", metricsstore.NewMetricsStore(nil, nil))}
+	code, status = get(m.ReadinessHandler())
+	if code != http.StatusServiceUnavailable || len(status.Resources) != 1 || status.Resources[0].Synced || status.Shard != 1 || status.TotalShards != 2 {
+		t.Errorf("expected not to be ready with an unsynced store, got %d %+v", code, status)
+	}
+
+	// Readiness does not wait for the stores once the ready timeout passed.
+	m.createdAt = time.Now().Add(-opts.ReadyTimeout)
+	if code, status := get(m.ReadinessHandler()); code != http.StatusOK || !status.Ready || status.Synced {
+		t.Errorf("expected to be ready after the ready timeout, got %d %+v", code, status)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// leaseShardingGroupLabel is the label of the Leases of all members of a
+// sharding group.
+const leaseShardingGroupLabel = "kube-state-metrics/sharding-group"
+
+// observedLease is a Lease renewal as observed by this instance. Leases are
+// expired based on the local time of the observation instead of the renew time
+// of the Lease, so that clock skew between members does not matter.
+type observedLease struct {
+	renewTime  metav1.MicroTime
+	observedAt time.Time
+}
+
+// leaseSharding assigns shards to the members of a sharding group. Each
+// member holds a Lease labeled with the group. The members sorted by their
+// identity are assigned the shards, so the group rebalances whenever a member
+// joins or its Lease expires.
+type leaseSharding struct {
+	client        kubernetes.Interface
+	namespace     string
+	identity      string
+	group         string
+	leaseDuration time.Duration
+	now           func() time.Time
+
+	observed map[string]observedLease
+}
+
+func newLeaseSharding(client kubernetes.Interface, namespace, identity, group string, leaseDuration time.Duration) *leaseSharding {
+	return &leaseSharding{
+		client:        client,
+		namespace:     namespace,
+		identity:      identity,
+		group:         group,
+		leaseDuration: leaseDuration,
+		now:           time.Now,
+		observed:      map[string]observedLease{},
+	}
+}
+
+// sync renews the Lease of this instance and returns the shard assigned to it
+// based on the current members of the group.
+func (l *leaseSharding) sync(ctx context.Context) (int32, int, error) {
+	if err := l.renew(ctx); err != nil {
+		return 0, 0, err
+	}
+	members, err := l.members(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i, member := range members {
+		if member == l.identity {
+			return int32(i), len(members), nil
+		}
+	}
+	return 0, 0, fmt.Errorf("lease of %s is missing in sharding group %s", l.identity, l.group)
+}
+
+// renew creates or renews the Lease of this instance.
+func (l *leaseSharding) renew(ctx context.Context) error {
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+	durationSeconds := int32(l.leaseDuration.Seconds())
+	renewTime := metav1.NewMicroTime(l.now())
+
+	lease, err := leases.Get(ctx, l.identity, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      l.identity,
+				Namespace: l.namespace,
+				Labels:    map[string]string{leaseShardingGroupLabel: l.group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &l.identity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create lease: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get lease: %w", err)
+	}
+
+	if lease.Labels == nil {
+		lease.Labels = map[string]string{}
+	}
+	lease.Labels[leaseShardingGroupLabel] = l.group
+	lease.Spec.HolderIdentity = &l.identity
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &renewTime
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	return nil
+}
+
+// members returns the sorted identities of all members of the group with an
+// unexpired Lease. Expired Leases are deleted.
+func (l *leaseSharding) members(ctx context.Context) ([]string, error) {
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+	list, err := leases.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{leaseShardingGroupLabel: l.group}).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leases: %w", err)
+	}
+
+	now := l.now()
+	observed := make(map[string]observedLease, len(list.Items))
+	var members []string
+	for i := range list.Items {
+		lease := &list.Items[i]
+		var renewTime metav1.MicroTime
+		if lease.Spec.RenewTime != nil {
+			renewTime = *lease.Spec.RenewTime
+		}
+		o, ok := l.observed[lease.Name]
+		if !ok || !o.renewTime.Equal(&renewTime) {
+			o = observedLease{renewTime: renewTime, observedAt: now}
+		}
+		observed[lease.Name] = o
+
+		duration := l.leaseDuration
+		if lease.Spec.LeaseDurationSeconds != nil {
+			duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+		}
+		if lease.Name != l.identity && now.Sub(o.observedAt) > duration {
+			klog.InfoS("Deleting expired sharding lease", "lease", klog.KObj(lease))
+			err := leases.Delete(ctx, lease.Name, metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+			})
+			if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+				klog.ErrorS(err, "Failed to delete expired sharding lease", "lease", klog.KObj(lease))
+			}
+			continue
+		}
+		members = append(members, lease.Name)
+	}
+	l.observed = observed
+
+	sort.Strings(members)
+	return members, nil
+}
+
+// release deletes the Lease of this instance, so that the remaining members
+// rebalance without waiting for it to expire.
+func (l *leaseSharding) release(ctx context.Context) error {
+	err := l.client.CoordinationV1().Leases(l.namespace).Delete(ctx, l.identity, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
+
+// runLeaseSharding configures sharding based on the members of the lease
+// sharding group and re-configures it whenever the members change.
+func (m *MetricsHandler) runLeaseSharding(ctx context.Context) error {
+	klog.InfoS("Lease based autosharding enabled", "group", m.opts.ShardingLeaseGroup, "identity", klog.KRef(m.opts.Namespace, m.opts.Pod))
+	l := newLeaseSharding(m.kubeClient, m.opts.Namespace, m.opts.Pod, m.opts.ShardingLeaseGroup, m.opts.ShardingLeaseDuration)
+	defer func() {
+		ctxRelease, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := l.release(ctxRelease); err != nil {
+			klog.ErrorS(err, "Failed to release sharding lease")
+		}
+	}()
+
+	ticker := time.NewTicker(l.leaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		shard, totalShards, err := l.sync(ctx)
+		if err != nil {
+			klog.ErrorS(err, "Failed to sync sharding leases")
+		} else {
+			m.mtx.RLock()
+			shardingUnchanged := m.curShard == shard && m.curTotalShards == totalShards
+			m.mtx.RUnlock()
+
+			if !shardingUnchanged {
+				m.ConfigureSharding(ctx, shard, totalShards)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLeaseSharding(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	members := map[string]*leaseSharding{}
+	for _, name := range []string{"ksm-c", "ksm-a", "ksm-b"} {
+		l := newLeaseSharding(client, "kube-system", name, "ksm", 15*time.Second)
+		l.now = clock
+		members[name] = l
+	}
+	// A member of another group must not be considered.
+	other := newLeaseSharding(client, "kube-system", "other", "other", 15*time.Second)
+	if err := other.renew(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range members {
+		if err := l.renew(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, want := range map[string]int32{"ksm-a": 0, "ksm-b": 1, "ksm-c": 2} {
+		shard, totalShards, err := members[name].sync(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shard != want || totalShards != 3 {
+			t.Errorf("%s: expected shard %d of 3, got %d of %d", name, want, shard, totalShards)
+		}
+	}
+
+	// ksm-a stops renewing its lease and expires.
+	for i := 0; i < 4; i++ {
+		now = now.Add(5 * time.Second)
+		for _, name := range []string{"ksm-b", "ksm-c"} {
+			if _, _, err := members[name].sync(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for name, want := range map[string]int32{"ksm-b": 0, "ksm-c": 1} {
+		shard, totalShards, err := members[name].sync(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shard != want || totalShards != 2 {
+			t.Errorf("%s: expected shard %d of 2, got %d of %d", name, want, shard, totalShards)
+		}
+	}
+	if _, err := client.CoordinationV1().Leases("kube-system").Get(ctx, "ksm-a", metav1.GetOptions{}); err == nil {
+		t.Error("expected expired lease to be deleted")
+	}
+
+	// ksm-c leaves the group.
+	if err := members["ksm-c"].release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	shard, totalShards, err := members["ksm-b"].sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shard != 0 || totalShards != 1 {
+		t.Errorf("ksm-b: expected shard 0 of 1, got %d of %d", shard, totalShards)
+	}
+}
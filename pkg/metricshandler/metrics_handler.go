@@ -17,7 +17,6 @@ limitations under the License.
 package metricshandler
 
 import (
-	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -26,7 +25,15 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/protobuf/proto"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -35,8 +42,13 @@ import (
 	"k8s.io/klog/v2"
 
 	ksmtypes "k8s.io/kube-state-metrics/v2/pkg/builder/types"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
 	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/otlp"
+	"k8s.io/kube-state-metrics/v2/pkg/recording"
+	"k8s.io/kube-state-metrics/v2/pkg/relabel"
+	"k8s.io/kube-state-metrics/v2/pkg/watch"
 )
 
 // MetricsHandler is a http.Handler that exposes the main kube-state-metrics
@@ -46,10 +58,43 @@ type MetricsHandler struct {
 	kubeClient         kubernetes.Interface
 	storeBuilder       ksmtypes.BuilderInterface
 	enableGZIPEncoding bool
-
+	// responseCache is nil if scrape response caching is disabled.
+	responseCache *responseCache
+	// standby is 1 while this instance is a standby replica, which does not
+	// expose the generated metrics.
+	standby int32
+	// watchHealth tracks the list and watch requests of all reflectors. It
+	// is nil if the MetricsHandler has no store builder.
+	watchHealth *watch.Health
+	// configs are the statuses of the configuration files loaded on startup.
+	configs []ConfigStatus
+	// createdAt is the start of the ready timeout.
+	createdAt time.Time
+	// snapshot is nil unless a metrics snapshot was loaded.
+	snapshot *snapshot
+	// externalLabels is nil if no external labels are configured.
+	externalLabels *metricsstore.ExternalLabels
+	// relabeler is nil if no relabeling rules are configured.
+	relabeler *relabel.Relabeler
+	// recordingRules is nil if no recording rules are configured.
+	recordingRules *recording.Rules
+	// tracer traces scrapes and builds of the stores. It does not record
+	// spans unless tracing is enabled by SetTracer.
+	tracer trace.Tracer
+	// listenedResources are the resources exposed by additional listeners,
+	// which are not exposed by ServeHTTP. It is nil if there are none.
+	listenedResources map[string]struct{}
+
+	// scrapeStatsMtx protects scrapeBytes, the number of bytes written per
+	// resource by the last scrape.
+	scrapeStatsMtx sync.Mutex
+	scrapeBytes    map[string]int
+
+	// ctx is the parent context of all stores, set once sharding is configured.
+	ctx    context.Context
 	cancel func()
 
-	// mtx protects metricsWriters, curShard, and curTotalShards
+	// mtx protects ctx, metricsWriters, curShard, and curTotalShards
 	mtx            *sync.RWMutex
 	metricsWriters metricsstore.MetricsWriterList
 	curShard       int32
@@ -58,41 +103,120 @@ type MetricsHandler struct {
 
 // New creates and returns a new MetricsHandler with the given options.
 func New(opts *options.Options, kubeClient kubernetes.Interface, storeBuilder ksmtypes.BuilderInterface, enableGZIPEncoding bool) *MetricsHandler {
-	return &MetricsHandler{
+	m := &MetricsHandler{
 		opts:               opts,
 		kubeClient:         kubeClient,
 		storeBuilder:       storeBuilder,
 		enableGZIPEncoding: enableGZIPEncoding,
 		mtx:                &sync.RWMutex{},
+		scrapeBytes:        map[string]int{},
+		createdAt:          time.Now(),
+		tracer:             noop.NewTracerProvider().Tracer(""),
+	}
+	if opts.ScrapeCacheTTL > 0 {
+		m.responseCache = newResponseCache(opts.ScrapeCacheTTL)
+	}
+	for _, resources := range opts.ResourceListeners {
+		if m.listenedResources == nil {
+			m.listenedResources = map[string]struct{}{}
+		}
+		for _, r := range resources {
+			m.listenedResources[r] = struct{}{}
+		}
+	}
+	if len(opts.ExternalLabels) > 0 {
+		m.externalLabels = metricsstore.NewExternalLabels(opts.ExternalLabels)
+	}
+	if storeBuilder != nil {
+		m.watchHealth = watch.NewHealth(opts.StaleThreshold)
+		storeBuilder.WithWatchHealth(m.watchHealth)
+	}
+	return m
+}
+
+// SetRelabeler configures the relabeling rules applied to all metrics. It must
+// be called before Run.
+func (m *MetricsHandler) SetRelabeler(r *relabel.Relabeler) {
+	m.relabeler = r
+}
+
+// SetTracer configures the tracer of scrapes, builds of the stores and the
+// list and watch requests of their reflectors. It must be called before Run.
+func (m *MetricsHandler) SetTracer(t trace.Tracer) {
+	m.tracer = t
+	if m.storeBuilder != nil {
+		m.storeBuilder.WithTracer(t)
 	}
 }
 
 // ConfigureSharding (re-)configures sharding. Re-configuration can be done
 // concurrently.
 func (m *MetricsHandler) ConfigureSharding(ctx context.Context, shard int32, totalShards int) {
+	if m.responseCache != nil {
+		// Responses of the previous configuration are dropped once the lock is
+		// released. Invalidating while holding it could deadlock with a render.
+		defer m.responseCache.invalidate()
+	}
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
+	m.configureSharding(ctx, shard, totalShards)
+}
+
+// Reconfigure applies f to the store builder and rebuilds all stores with the
+// current sharding settings. Reconfiguration can be done concurrently. If
+// sharding was not configured yet, the stores are built once it is.
+func (m *MetricsHandler) Reconfigure(f func(storeBuilder ksmtypes.BuilderInterface) error) error {
+	if m.responseCache != nil {
+		defer m.responseCache.invalidate()
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if err := f(m.storeBuilder); err != nil {
+		return err
+	}
+	if m.ctx == nil {
+		return nil
+	}
+	m.configureSharding(m.ctx, m.curShard, m.curTotalShards)
+	return nil
+}
+
+// configureSharding (re-)builds all stores. The caller must hold the lock.
+func (m *MetricsHandler) configureSharding(ctx context.Context, shard int32, totalShards int) {
 	if m.cancel != nil {
 		m.cancel()
 	}
 	if totalShards != 1 {
 		klog.InfoS("Configuring sharding of this instance to be shard index (zero-indexed) out of total shards", "shard", shard, "totalShards", totalShards)
 	}
+	_, span := m.tracer.Start(context.Background(), "build stores", trace.WithAttributes(
+		attribute.Int("kube_state_metrics.shard", int(shard)),
+		attribute.Int("kube_state_metrics.total_shards", totalShards),
+	))
+	defer span.End()
+	m.ctx = ctx
 	ctx, m.cancel = context.WithCancel(ctx)
 	m.storeBuilder.WithSharding(shard, totalShards)
 	m.storeBuilder.WithContext(ctx)
 	m.metricsWriters = m.storeBuilder.Build()
+	span.SetAttributes(attribute.Int("kube_state_metrics.stores", len(m.metricsWriters)))
 	m.curShard = shard
 	m.curTotalShards = totalShards
 }
 
 // Run configures the MetricsHandler's sharding and if autosharding is enabled
-// re-configures sharding on re-sharding events. Run should only be called
-// once.
+// re-configures sharding on re-sharding events. Autosharding is based on the
+// StatefulSet of the pod or, if a sharding lease group is set, on the Leases
+// of the group. Run should only be called once.
 func (m *MetricsHandler) Run(ctx context.Context) error {
 	autoSharding := len(m.opts.Pod) > 0 && len(m.opts.Namespace) > 0
 
+	if autoSharding && m.opts.ShardingLeaseGroup != "" {
+		return m.runLeaseSharding(ctx)
+	}
+
 	if !autoSharding {
 		klog.InfoS("Autosharding disabled")
 		m.ConfigureSharding(ctx, m.opts.Shard, m.opts.TotalShards)
@@ -175,44 +299,357 @@ func (m *MetricsHandler) Run(ctx context.Context) error {
 	return ctx.Err()
 }
 
+// SetRecordingRules configures the recording rules evaluated whenever metrics
+// are written. It must be called before Run.
+func (m *MetricsHandler) SetRecordingRules(r *recording.Rules) {
+	m.recordingRules = r
+}
+
 // ServeHTTP implements the http.Handler interface. It writes all generated
-// metrics to the response body.
+// metrics, except the ones of resources exposed by additional listeners, to the
+// response body. The Prometheus protobuf format is used if it is negotiated by
+// the client, the text format otherwise. If a scrape cache TTL is configured,
+// responses are rendered at most once per TTL.
 func (m *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	m.mtx.RLock()
-	defer m.mtx.RUnlock()
+	scope := parseScrapeScope(r.URL.Query())
+	if m.listenedResources != nil {
+		scope = scope.exclude(m.listenedResources)
+	}
+	m.serve(w, r, scope)
+}
+
+// ResourcesHandler returns a handler serving the metrics of the given
+// resources like ServeHTTP, e.g. for an additional listener.
+func (m *MetricsHandler) ResourcesHandler(resources []string) http.Handler {
+	set := make(map[string]struct{}, len(resources))
+	for _, r := range resources {
+		set[r] = struct{}{}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.serve(w, r, parseScrapeScope(r.URL.Query()).restrict(set))
+	})
+}
+
+// NamespaceHandler returns a handler serving only the metrics of the namespace
+// named by the remainder of the request path after the given prefix, e.g.
+// team-a of /metrics/namespaces/team-a, like ServeHTTP. Metrics without a
+// namespace label, e.g. of nodes, are not served.
+func (m *MetricsHandler) NamespaceHandler(prefix string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace := strings.TrimPrefix(r.URL.Path, prefix)
+		if namespace == "" || namespace == r.URL.Path || strings.Contains(namespace, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		scope := parseScrapeScope(r.URL.Query()).restrictNamespace(namespace)
+		if m.listenedResources != nil {
+			scope = scope.exclude(m.listenedResources)
+		}
+		m.serve(w, r, scope)
+	})
+}
+
+// serve writes the generated metrics of the scope to the response body. The
+// scrape is traced as part of the trace of the scraper, if it propagates one
+// via the traceparent header.
+func (m *MetricsHandler) serve(w http.ResponseWriter, r *http.Request, scope *scrapeScope) {
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := m.tracer.Start(ctx, "scrape", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
 	resHeader := w.Header()
-	var writer io.Writer = w
 
-	resHeader.Set("Content-Type", `text/plain; version=`+"0.0.4")
+	format := expfmt.Negotiate(r.Header)
+	if format.FormatType() == expfmt.TypeProtoDelim {
+		format = expfmt.FmtProtoDelim
+		resHeader.Set("Content-Type", string(format))
+	} else {
+		format = expfmt.FmtText
+		resHeader.Set("Content-Type", `text/plain; version=`+"0.0.4")
+	}
+	utf8Names := m.opts.UTF8LabelNamesEnabled() && negotiateUTF8Names(r.Header.Get("Accept"), format)
+	if utf8Names {
+		resHeader.Set("Content-Type", utf8ContentType(format))
+	}
 
+	var encoding string
 	if m.enableGZIPEncoding {
-		// Gzip response if requested. Taken from
-		// github.com/prometheus/client_golang/prometheus/promhttp.decorateWriter.
-		reqHeader := r.Header.Get("Accept-Encoding")
-		parts := strings.Split(reqHeader, ",")
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if part == "gzip" || strings.HasPrefix(part, "gzip;") {
-				writer = gzip.NewWriter(writer)
-				resHeader.Set("Content-Encoding", "gzip")
-			}
+		resHeader.Add("Vary", "Accept-Encoding")
+		encoding = negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding != "" {
+			resHeader.Set("Content-Encoding", encoding)
 		}
 	}
 
-	for _, w := range m.metricsWriters {
-		err := w.WriteAll(writer)
-		if err != nil {
+	span.SetAttributes(
+		attribute.String("http.response.format", string(format)),
+		attribute.String("kube_state_metrics.scope", scope.String()),
+	)
+	if m.responseCache == nil {
+		m.render(ctx, w, format, encoding, scope, utf8Names)
+		return
+	}
+	body := m.responseCache.get(responseCacheKey{format: format, encoding: encoding, scope: scope.String(), utf8Names: utf8Names}, func(w io.Writer) {
+		m.render(ctx, w, format, encoding, scope, utf8Names)
+	})
+	if _, err := w.Write(body); err != nil {
+		klog.ErrorS(err, "Failed to write metrics")
+	}
+}
+
+// render writes all generated metrics of the scope to w in the given format,
+// compressed with the given content encoding if it is not empty. UTF-8 label
+// names are kept if utf8Names is true.
+func (m *MetricsHandler) render(ctx context.Context, w io.Writer, format expfmt.Format, encoding string, scope *scrapeScope, utf8Names bool) {
+	ctx, span := m.tracer.Start(ctx, "render")
+	defer span.End()
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	if encoding != "" {
+		c := getCompressor(encoding, w)
+		defer func() {
+			// The compressor has to be closed to flush the response.
+			if err := c.Close(); err != nil {
+				klog.ErrorS(err, "Failed to close the writer")
+			}
+			putCompressor(encoding, c)
+		}()
+		w = c
+	}
+
+	if m.IsStandby() {
+		if err := writeStandby(w, format); err != nil {
+			klog.ErrorS(err, "Failed to write metrics")
+		}
+		return
+	}
+	if format == expfmt.FmtProtoDelim {
+		if err := m.writeProtobuf(ctx, w, format, scope, utf8Names); err != nil {
+			otlp.SetSpanError(span, err)
 			klog.ErrorS(err, "Failed to write metrics")
 		}
+		return
 	}
+	if err := m.writeText(ctx, w, scope, utf8Names); err != nil {
+		otlp.SetSpanError(span, err)
+		klog.ErrorS(err, "Failed to write metrics")
+	}
+}
 
-	// In case we gzipped the response, we have to close the writer.
-	if closer, ok := writer.(io.Closer); ok {
-		err := closer.Close()
-		if err != nil {
-			klog.ErrorS(err, "Failed to close the writer")
+// writeProtobuf writes all generated metrics of the scope to w using the given
+// protobuf format. Each family is encoded once it was written in the text
+// format, so that only a single family is held at a time. The caller must
+// hold the read lock.
+func (m *MetricsHandler) writeProtobuf(ctx context.Context, w io.Writer, format expfmt.Format, scope *scrapeScope, utf8Names bool) error {
+	enc := expfmt.NewEncoder(w, format)
+	fw := metric.NewFamilyWriter(func(f *metric.Family) error {
+		// Families without metrics are only written as headers in the text
+		// format and are not valid in the protobuf format.
+		if len(f.Metrics) == 0 {
+			return nil
+		}
+		return enc.Encode(familyToProto(f))
+	})
+	if err := m.writeText(ctx, fw, scope, utf8Names); err != nil {
+		return err
+	}
+	return fw.Flush()
+}
+
+// familyToProto converts a metric family to its protobuf representation. The
+// OpenMetrics info and stateset types are exposed as gauges.
+func familyToProto(f *metric.Family) *dto.MetricFamily {
+	t := dto.MetricType_GAUGE
+	switch f.Type {
+	case metric.Counter:
+		t = dto.MetricType_COUNTER
+	case metric.Gauge, metric.Info, metric.StateSet:
+	default:
+		t = dto.MetricType_UNTYPED
+	}
+
+	mf := &dto.MetricFamily{
+		Name:   proto.String(f.Name),
+		Help:   proto.String(f.Help),
+		Type:   t.Enum(),
+		Metric: make([]*dto.Metric, 0, len(f.Metrics)),
+	}
+	for _, m := range f.Metrics {
+		pm := &dto.Metric{Label: make([]*dto.LabelPair, len(m.LabelKeys))}
+		for i := range m.LabelKeys {
+			pm.Label[i] = &dto.LabelPair{Name: proto.String(m.LabelKeys[i]), Value: proto.String(m.LabelValues[i])}
+		}
+		switch t {
+		case dto.MetricType_COUNTER:
+			pm.Counter = &dto.Counter{Value: proto.Float64(m.Value)}
+		case dto.MetricType_GAUGE:
+			pm.Gauge = &dto.Gauge{Value: proto.Float64(m.Value)}
+		default:
+			pm.Untyped = &dto.Untyped{Value: proto.Float64(m.Value)}
+		}
+		mf.Metric = append(mf.Metric, pm)
+	}
+	return mf
+}
+
+// Write writes all generated metrics to w in the text exposition format.
+// Standby instances do not write any metrics. While a loaded snapshot is
+// served, the snapshot is written instead. External labels are appended to
+// all series.
+func (m *MetricsHandler) Write(w io.Writer) error {
+	if m.IsStandby() {
+		return nil
+	}
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	return m.writeText(context.Background(), w, nil, false)
+}
+
+// Families returns the metric families of all resources with the relabeling
+// rules applied, e.g. to export them via OTLP. The stores need to keep the
+// metric families of their objects, see WithKeepFamilies of the store
+// builder. Recording rules and external labels are not applied, and no
+// families are returned while this instance is a standby replica.
+func (m *MetricsHandler) Families() []*metric.Family {
+	if m.IsStandby() {
+		return nil
+	}
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	var families []*metric.Family
+	for _, mw := range m.metricsWriters {
+		for _, f := range mw.Families() {
+			if m.relabeler != nil && !m.relabeler.RelabelFamily(f) {
+				continue
+			}
+			families = append(families, f)
+		}
+	}
+	return families
+}
+
+// writeText writes all generated metrics of the scope, or the snapshot while it
+// is served, to w in the text exposition format with the families of the
+// recording rules added, the relabeling rules applied and the external labels
+// appended. The scope applies to the metrics before they are recorded and
+// relabeled. Unless utf8Names is true, UTF-8 label names are replaced by their
+// legacy names last, such that relabeling and recording rules always match
+// the UTF-8 names. With --sort-metrics, the families and series are sorted
+// after all of these. The caller must hold the read lock.
+func (m *MetricsHandler) writeText(ctx context.Context, w io.Writer, scope *scrapeScope, utf8Names bool) error {
+	var writers []interface{ Flush() error }
+	if m.opts.SortMetrics {
+		sw := metricsstore.NewSortedWriter(w)
+		writers = append(writers, sw)
+		w = sw
+	}
+	if m.opts.UTF8LabelNamesEnabled() && !utf8Names {
+		lw := metricsstore.NewLegacyLabelNamesWriter(w)
+		writers = append(writers, lw)
+		w = lw
+	}
+	if m.externalLabels != nil {
+		elw := metricsstore.NewExternalLabelsWriter(w, m.externalLabels)
+		writers = append(writers, elw)
+		w = elw
+	}
+	if m.relabeler != nil {
+		rw := m.relabeler.NewWriter(w)
+		writers = append(writers, rw)
+		w = rw
+	}
+	if m.recordingRules != nil {
+		rw := m.recordingRules.NewWriter(w)
+		writers = append(writers, rw)
+		w = rw
+	}
+	if sw := scope.newWriter(w); sw != nil {
+		writers = append(writers, sw)
+		w = sw
+	}
+	if err := m.writeMetrics(ctx, w, scope); err != nil {
+		return err
+	}
+	// The outermost writer is flushed first, so that its remaining line is
+	// passed on.
+	for i := len(writers) - 1; i >= 0; i-- {
+		if err := writers[i].Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMetrics writes all generated metrics of the resources of the scope, or
+// the snapshot of them while it is served, to w in the text exposition
+// format. The caller must hold the read lock. Writing the metrics of each
+// resource is traced as a child span of the span of ctx.
+func (m *MetricsHandler) writeMetrics(ctx context.Context, w io.Writer, scope *scrapeScope) error {
+	if ok, err := m.writeSnapshot(w, scope); ok || err != nil {
+		return err
+	}
+	var writers metricsstore.MetricsWriterList
+	for _, mw := range m.metricsWriters {
+		if scope.includesResource(mw.Resource()) {
+			writers = append(writers, mw)
+		}
+	}
+	if m.opts.ScrapeWorkers > 1 {
+		if err := m.writeResourcesConcurrently(ctx, w, writers, m.opts.ScrapeWorkers); err != nil {
+			return err
 		}
+		return m.writeStale(w)
+	}
+	for _, mw := range writers {
+		if err := m.writeResource(ctx, w, mw); err != nil {
+			return err
+		}
+	}
+	return m.writeStale(w)
+}
+
+// writeResource writes the metrics of a single resource to w and records the
+// number of written bytes.
+func (m *MetricsHandler) writeResource(ctx context.Context, w io.Writer, mw *metricsstore.MetricsWriter) error {
+	cw := &countingWriter{w: w}
+	_, span := m.tracer.Start(ctx, "write "+mw.Resource(), trace.WithAttributes(attribute.String("k8s.resource", mw.Resource())))
+	err := mw.WriteAll(cw)
+	span.SetAttributes(attribute.Int("kube_state_metrics.bytes", cw.n))
+	otlp.SetSpanError(span, err)
+	span.End()
+	if err != nil {
+		return err
+	}
+	if mw.Resource() != "" {
+		m.scrapeStatsMtx.Lock()
+		m.scrapeBytes[mw.Resource()] = cw.n
+		m.scrapeStatsMtx.Unlock()
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// ShardingFromStatefulSet returns the shard and the total number of shards
+// StatefulSet based autosharding assigns to the given Pod.
+func ShardingFromStatefulSet(kubeClient kubernetes.Interface, podName, namespaceName string) (int32, int, error) {
+	ss, err := detectStatefulSet(kubeClient, podName, namespaceName)
+	if err != nil {
+		return 0, 0, err
 	}
+	return shardingSettingsFromStatefulSet(ss, podName)
 }
 
 func shardingSettingsFromStatefulSet(ss *appsv1.StatefulSet, podName string) (nominal int32, totalReplicas int, err error) {
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestServeTraceparent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.NeverSample())))
+	m := New(&options.Options{}, nil, nil, false)
+	m.SetTracer(provider.Tracer(""))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	m.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected the spans of the scrape and the render of the sampled trace of the scraper, got %d", len(spans))
+	}
+	scrape := spans[1]
+	if scrape.Name() != "scrape" || scrape.SpanKind() != trace.SpanKindServer {
+		t.Errorf("unexpected span %s of kind %s", scrape.Name(), scrape.SpanKind())
+	}
+	if got := scrape.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the trace ID of the scraper, got %s", got)
+	}
+	if got := scrape.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("expected the span of the scraper as parent, got %s", got)
+	}
+	if spans[0].Name() != "render" || spans[0].Parent().SpanID() != scrape.SpanContext().SpanID() {
+		t.Errorf("expected the render span to be a child of the scrape span, got %s", spans[0].Name())
+	}
+
+	// Scrapes without a trace of the scraper are sampled by the sampler.
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := len(recorder.Ended()); got != 2 {
+		t.Errorf("expected no spans of unsampled scrapes, got %d", got-2)
+	}
+}
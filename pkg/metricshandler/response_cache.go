@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+// responseCacheKey identifies a rendered response. Responses differ by
+// exposition format, content encoding, scrape scope and whether UTF-8 label
+// names are kept.
+type responseCacheKey struct {
+	format    expfmt.Format
+	encoding  string
+	scope     string
+	utf8Names bool
+}
+
+type cachedResponse struct {
+	body     []byte
+	rendered time.Time
+}
+
+// responseCache keeps rendered responses for a fixed duration, so that
+// scrapes arriving within that duration are served without walking all
+// stores again. Concurrent requests for an expired response wait for a single
+// render instead of rendering it themselves.
+type responseCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mtx     sync.Mutex
+	entries map[responseCacheKey]*cachedResponse
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[responseCacheKey]*cachedResponse{},
+	}
+}
+
+// get returns the cached response for key, calling render to create it if no
+// response younger than the TTL is cached.
+func (c *responseCache) get(key responseCacheKey, render func(w io.Writer)) []byte {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := c.now()
+	if e, ok := c.entries[key]; ok && now.Sub(e.rendered) < c.ttl {
+		return e.body
+	}
+
+	buf := &bytes.Buffer{}
+	render(buf)
+	c.entries[key] = &cachedResponse{body: buf.Bytes(), rendered: now}
+	return buf.Bytes()
+}
+
+// invalidate drops all cached responses.
+func (c *responseCache) invalidate() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries = map[responseCacheKey]*cachedResponse{}
+}
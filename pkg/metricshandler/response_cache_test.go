@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+func TestResponseCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newResponseCache(10 * time.Second)
+	c.now = func() time.Time { return now }
+
+	renders := 0
+	render := func(w io.Writer) {
+		renders++
+		fmt.Fprintf(w, "render %d", renders)
+	}
+	text := responseCacheKey{format: expfmt.FmtText}
+	gzipped := responseCacheKey{format: expfmt.FmtText, encoding: "gzip"}
+
+	steps := []struct {
+		advance time.Duration
+		key     responseCacheKey
+		want    string
+	}{
+		{key: text, want: "render 1"},
+		{advance: 9 * time.Second, key: text, want: "render 1"},
+		{key: gzipped, want: "render 2"},
+		{advance: time.Second, key: text, want: "render 3"},
+		{key: gzipped, want: "render 2"},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if got := string(c.get(step.key, render)); got != step.want {
+			t.Errorf("step %d: expected %q, got %q", i, step.want, got)
+		}
+	}
+
+	c.invalidate()
+	if got := string(c.get(gzipped, render)); got != "render 4" {
+		t.Errorf("expected invalidated response to be rendered again, got %q", got)
+	}
+}
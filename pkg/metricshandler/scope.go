@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+// Query parameters of the metrics endpoint restricting a scrape to a part of
+// the metrics. Each parameter accepts comma-separated values and may be
+// repeated.
+const (
+	scopeResources  = "resources"
+	scopeNamespaces = "namespace"
+	scopeMetrics    = "metrics"
+)
+
+// scrapeScope restricts a scrape to the metrics of some resources, namespaces
+// or families. A nil set does not restrict the scrape.
+type scrapeScope struct {
+	resources  map[string]struct{}
+	namespaces map[string]struct{}
+	families   map[string]struct{}
+	// excluded are resources whose metrics are not part of the scope.
+	excluded map[string]struct{}
+}
+
+// parseScrapeScope returns the scope of the query parameters of a scrape, or
+// nil if the scrape is not restricted.
+func parseScrapeScope(query url.Values) *scrapeScope {
+	s := &scrapeScope{
+		resources:  queryValues(query, scopeResources),
+		namespaces: queryValues(query, scopeNamespaces),
+		families:   queryValues(query, scopeMetrics),
+	}
+	if s.resources == nil && s.namespaces == nil && s.families == nil {
+		return nil
+	}
+	return s
+}
+
+// queryValues returns the set of comma-separated values of the query
+// parameter, or nil if the parameter is not set.
+func queryValues(query url.Values, key string) map[string]struct{} {
+	params, ok := query[key]
+	if !ok {
+		return nil
+	}
+	values := map[string]struct{}{}
+	for _, p := range params {
+		for _, v := range strings.Split(p, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values[v] = struct{}{}
+			}
+		}
+	}
+	return values
+}
+
+// restrict returns the scope restricted to the given resources.
+func (s *scrapeScope) restrict(resources map[string]struct{}) *scrapeScope {
+	restricted := &scrapeScope{resources: resources}
+	if s == nil {
+		return restricted
+	}
+	restricted.namespaces, restricted.families, restricted.excluded = s.namespaces, s.families, s.excluded
+	if s.resources != nil {
+		restricted.resources = map[string]struct{}{}
+		for r := range s.resources {
+			if _, ok := resources[r]; ok {
+				restricted.resources[r] = struct{}{}
+			}
+		}
+	}
+	return restricted
+}
+
+// restrictNamespace returns the scope restricted to the given namespace.
+func (s *scrapeScope) restrictNamespace(namespace string) *scrapeScope {
+	restricted := &scrapeScope{namespaces: map[string]struct{}{}}
+	if s != nil {
+		restricted.resources, restricted.families, restricted.excluded = s.resources, s.families, s.excluded
+	}
+	if s == nil || s.namespaces == nil {
+		restricted.namespaces[namespace] = struct{}{}
+	} else if _, ok := s.namespaces[namespace]; ok {
+		restricted.namespaces[namespace] = struct{}{}
+	}
+	return restricted
+}
+
+// exclude returns the scope without the given resources.
+func (s *scrapeScope) exclude(resources map[string]struct{}) *scrapeScope {
+	excluded := &scrapeScope{excluded: resources}
+	if s != nil {
+		excluded.resources, excluded.namespaces, excluded.families = s.resources, s.namespaces, s.families
+	}
+	return excluded
+}
+
+// includesResource returns whether the metrics of the resource are part of
+// the scope.
+func (s *scrapeScope) includesResource(resource string) bool {
+	if s == nil {
+		return true
+	}
+	if _, ok := s.excluded[resource]; ok {
+		return false
+	}
+	if s.resources == nil {
+		return true
+	}
+	_, ok := s.resources[resource]
+	return ok
+}
+
+// newWriter returns a writer filtering the metrics written to w by the
+// namespaces and families of the scope, or nil if it does not filter them.
+func (s *scrapeScope) newWriter(w io.Writer) *metricsstore.LineWriter {
+	if s == nil || (s.namespaces == nil && s.families == nil) {
+		return nil
+	}
+	return metricsstore.NewFilterWriter(w, s.families, s.namespaces)
+}
+
+// String returns a canonical representation of the scope, which identifies
+// cached responses of the scope.
+func (s *scrapeScope) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join([]string{setString(s.resources), setString(s.namespaces), setString(s.families), setString(s.excluded)}, ";")
+}
+
+func setString(set map[string]struct{}) string {
+	if set == nil {
+		return "*"
+	}
+	values := make([]string, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
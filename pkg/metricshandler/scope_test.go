@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// newScopeTestWriters returns metrics writers of pods in the namespaces team-a
+// and team-b and of a node.
+func newScopeTestWriters(t *testing.T) metricsstore.MetricsWriterList {
+	pods := metricsstore.NewMetricsStore([]string{
+		"# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge",
+		"# HELP kube_pod_created Created\n# TYPE kube_pod_created gauge",
+	}, func(obj interface{}) []metric.FamilyInterface {
+		pod := obj.(*v1.Pod)
+		labels := []*metric.Metric{{LabelKeys: []string{"namespace", "pod"}, LabelValues: []string{pod.Namespace, pod.Name}, Value: 1}}
+		return []metric.FamilyInterface{
+			&metric.Family{Name: "kube_pod_info", Metrics: labels},
+			&metric.Family{Name: "kube_pod_created", Metrics: labels},
+		}
+	})
+	nodes := metricsstore.NewMetricsStore([]string{"# HELP kube_node_info Info\n# TYPE kube_node_info gauge"}, func(obj interface{}) []metric.FamilyInterface {
+		return []metric.FamilyInterface{&metric.Family{
+			Name:    "kube_node_info",
+			Metrics: []*metric.Metric{{LabelKeys: []string{"node"}, LabelValues: []string{obj.(*v1.Node).Name}, Value: 1}},
+		}}
+	})
+	for _, pod := range []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "team-a", UID: "uid1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "team-b", UID: "uid2"}},
+	} {
+		if err := pods.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := nodes.Add(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", UID: "uid3"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	return metricsstore.MetricsWriterList{
+		metricsstore.NewResourceMetricsWriter("pods", pods),
+		metricsstore.NewResourceMetricsWriter("nodes", nodes),
+	}
+}
+
+func TestScrapeScope(t *testing.T) {
+	m := New(&options.Options{ScrapeCacheTTL: time.Minute}, nil, nil, false)
+	m.metricsWriters = newScopeTestWriters(t)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: "?resources=nodes",
+			want:  "# HELP kube_node_info Info\n# TYPE kube_node_info gauge\nkube_node_info{node=\"node1\"} 1\n",
+		},
+		{
+			query: "?namespace=team-a&metrics=kube_pod_info",
+			want:  "# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\nkube_pod_info{namespace=\"team-a\",pod=\"pod1\"} 1\n",
+		},
+		{
+			query: "?resources=pods&metrics=kube_pod_info,kube_node_info&namespace=team-a&namespace=team-b",
+			want: "# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\n" +
+				"kube_pod_info{namespace=\"team-a\",pod=\"pod1\"} 1\nkube_pod_info{namespace=\"team-b\",pod=\"pod2\"} 1\n",
+		},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics"+test.query, nil))
+		// The order of the objects of a store is not deterministic.
+		if got := rec.Body.String(); sortedLines(got) != sortedLines(test.want) {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.query, test.want, got)
+		}
+	}
+}
+
+func sortedLines(s string) string {
+	lines := strings.Split(s, "\n")
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func TestResourcesHandler(t *testing.T) {
+	m := New(&options.Options{ResourceListeners: options.ResourceListeners{":8082": {"nodes"}}}, nil, nil, false)
+	m.metricsWriters = newScopeTestWriters(t)
+
+	scrape := func(h http.Handler, query string) string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics"+query, nil))
+		return rec.Body.String()
+	}
+
+	nodes := "# HELP kube_node_info Info\n# TYPE kube_node_info gauge\nkube_node_info{node=\"node1\"} 1\n"
+	if got := scrape(m.ResourcesHandler([]string{"nodes"}), ""); got != nodes {
+		t.Errorf("expected node metrics %q, got %q", nodes, got)
+	}
+	if got := scrape(m.ResourcesHandler([]string{"nodes"}), "?resources=pods"); got != "" {
+		t.Errorf("expected no metrics of resources of other listeners, got %q", got)
+	}
+	if got := scrape(m, ""); strings.Contains(got, "kube_node_info") || !strings.Contains(got, "kube_pod_info") {
+		t.Errorf("expected only pod metrics from the main listener, got %q", got)
+	}
+}
+
+func TestNamespaceHandler(t *testing.T) {
+	m := New(&options.Options{}, nil, nil, false)
+	m.metricsWriters = newScopeTestWriters(t)
+	h := m.NamespaceHandler("/metrics/namespaces/")
+
+	tests := []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{
+			path:   "/metrics/namespaces/team-a",
+			status: http.StatusOK,
+			want: "# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\nkube_pod_info{namespace=\"team-a\",pod=\"pod1\"} 1\n" +
+				"# HELP kube_pod_created Created\n# TYPE kube_pod_created gauge\nkube_pod_created{namespace=\"team-a\",pod=\"pod1\"} 1\n" +
+				"# HELP kube_node_info Info\n# TYPE kube_node_info gauge\n",
+		},
+		{
+			path:   "/metrics/namespaces/team-a?metrics=kube_pod_info&namespace=team-b",
+			status: http.StatusOK,
+			want:   "# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\n",
+		},
+		{
+			path:   "/metrics/namespaces/",
+			status: http.StatusNotFound,
+		},
+		{
+			path:   "/metrics/namespaces/team-a/pods",
+			status: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if rec.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.path, test.status, rec.Code)
+		}
+		if got := rec.Body.String(); test.status == http.StatusOK && got != test.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.path, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/sharding"
+)
+
+var (
+	shardObjectsDesc = prometheus.NewDesc(
+		"kube_state_metrics_shard_objects",
+		"Number of objects of a resource assigned to this shard",
+		[]string{"resource"}, nil,
+	)
+	shardSeriesDesc = prometheus.NewDesc(
+		"kube_state_metrics_shard_series",
+		"Number of series generated for the objects of a resource assigned to this shard",
+		[]string{"resource"}, nil,
+	)
+	shardBytesDesc = prometheus.NewDesc(
+		"kube_state_metrics_shard_bytes",
+		"Size of the series generated for the objects of a resource assigned to this shard in the text exposition format",
+		[]string{"resource"}, nil,
+	)
+	shardSyncDurationDesc = prometheus.NewDesc(
+		"kube_state_metrics_shard_sync_duration_seconds",
+		"Duration from the last build of the stores of a resource until they were populated by their initial list",
+		[]string{"resource"}, nil,
+	)
+	objectsTruncatedDesc = prometheus.NewDesc(
+		"kube_state_metrics_objects_truncated",
+		"Number of objects of a resource assigned to this shard without metrics, as --max-objects of the resource was reached",
+		[]string{"resource"}, nil,
+	)
+	objectsOmittedDesc = prometheus.NewDesc(
+		"kube_state_metrics_objects_omitted",
+		"Number of objects of a resource assigned to this shard whose series of a metric family are omitted, as they are not among the --top-k most recently updated objects",
+		[]string{"resource", "family"}, nil,
+	)
+	shardScrapeBytesDesc = prometheus.NewDesc(
+		"kube_state_metrics_shard_scrape_bytes",
+		"Number of bytes written for a resource by the last scrape before compression",
+		[]string{"resource"}, nil,
+	)
+)
+
+// shardingStatsCollector collects statistics of the stores of a
+// MetricsHandler.
+type shardingStatsCollector struct {
+	m *MetricsHandler
+}
+
+// NewShardingStatsCollector returns a prometheus.Collector exposing the number
+// of objects and series, their size, the number of truncated objects and the
+// sync duration of the stores per resource assigned to the shard of the given
+// MetricsHandler, as well as the number of bytes written per resource by the
+// last scrape.
+func NewShardingStatsCollector(m *MetricsHandler) prometheus.Collector {
+	return &shardingStatsCollector{m: m}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *shardingStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- shardObjectsDesc
+	ch <- shardSeriesDesc
+	ch <- shardBytesDesc
+	ch <- shardSyncDurationDesc
+	ch <- objectsTruncatedDesc
+	ch <- objectsOmittedDesc
+	ch <- shardScrapeBytesDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *shardingStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.m.mtx.RLock()
+	defer c.m.mtx.RUnlock()
+
+	c.m.scrapeStatsMtx.Lock()
+	defer c.m.scrapeStatsMtx.Unlock()
+
+	for _, mw := range c.m.metricsWriters {
+		resource := mw.Resource()
+		if resource == "" {
+			continue
+		}
+		stats := mw.Stats()
+		ch <- prometheus.MustNewConstMetric(shardObjectsDesc, prometheus.GaugeValue, float64(stats.Objects), resource)
+		ch <- prometheus.MustNewConstMetric(shardSeriesDesc, prometheus.GaugeValue, float64(stats.Series), resource)
+		ch <- prometheus.MustNewConstMetric(shardBytesDesc, prometheus.GaugeValue, float64(stats.Bytes), resource)
+		ch <- prometheus.MustNewConstMetric(objectsTruncatedDesc, prometheus.GaugeValue, float64(stats.Truncated), resource)
+		for family, n := range stats.Omitted {
+			ch <- prometheus.MustNewConstMetric(objectsOmittedDesc, prometheus.GaugeValue, float64(n), resource, family)
+		}
+		if stats.SyncDuration > 0 {
+			ch <- prometheus.MustNewConstMetric(shardSyncDurationDesc, prometheus.GaugeValue, stats.SyncDuration.Seconds(), resource)
+		}
+		if n, ok := c.m.scrapeBytes[resource]; ok {
+			ch <- prometheus.MustNewConstMetric(shardScrapeBytesDesc, prometheus.GaugeValue, float64(n), resource)
+		}
+	}
+}
+
+// shardAssignment is the response of the sharding debug handler.
+type shardAssignment struct {
+	UID               types.UID         `json:"uid"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Strategy          sharding.Strategy `json:"strategy"`
+	Shard             int32             `json:"shard"`
+	TotalShards       int               `json:"totalShards"`
+	CurrentShard      int32             `json:"currentShard"`
+	AssignedToCurrent bool              `json:"assignedToCurrentShard"`
+}
+
+// ShardingDebugHandler returns a http.Handler which reports the shard an
+// object is assigned to with the current sharding settings. The object is
+// identified by the uid query parameter and, for the namespace sharding
+// strategy, by the namespace query parameter, which is sufficient for
+// namespaced objects. For namespaces, their name has to be passed as
+// namespace.
+func (m *MetricsHandler) ShardingDebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uid := types.UID(r.URL.Query().Get("uid"))
+		namespace := r.URL.Query().Get("namespace")
+		strategy := m.opts.ShardingStrategy
+		if strategy == "" {
+			strategy = sharding.StrategyUID
+		}
+		if uid == "" && (strategy != sharding.StrategyNamespace || namespace == "") {
+			http.Error(w, "the uid query parameter is required", http.StatusBadRequest)
+			return
+		}
+
+		m.mtx.RLock()
+		currentShard, totalShards := m.curShard, m.curTotalShards
+		m.mtx.RUnlock()
+		if totalShards == 0 {
+			http.Error(w, "sharding is not configured yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		shard := sharding.ShardOf(strategy, uid, namespace, totalShards)
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(shardAssignment{
+			UID:               uid,
+			Namespace:         namespace,
+			Strategy:          strategy,
+			Shard:             shard,
+			TotalShards:       totalShards,
+			CurrentShard:      currentShard,
+			AssignedToCurrent: shard == currentShard,
+		})
+		if err != nil {
+			klog.ErrorS(err, "Failed to write sharding assignment")
+		}
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/sharding"
+)
+
+func TestShardingStatsCollector(t *testing.T) {
+	store := metricsstore.NewMetricsStore([]string{"# HELP kube_pod_info Info"}, func(obj interface{}) []metric.FamilyInterface {
+		pod := obj.(*v1.Pod)
+		return []metric.FamilyInterface{&metric.Family{
+			Name: "kube_pod_info",
+			Metrics: []*metric.Metric{
+				{LabelKeys: []string{"pod", "state"}, LabelValues: []string{pod.Name, "a"}, Value: 1},
+				{LabelKeys: []string{"pod", "state"}, LabelValues: []string{pod.Name, "b"}, Value: 0},
+			},
+		}}
+	})
+	var pods []interface{}
+	for _, name := range []string{"pod1", "pod2"} {
+		pods = append(pods, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}})
+	}
+	if err := store.Replace(pods, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(&options.Options{}, nil, nil, false)
+	m.metricsWriters = metricsstore.MetricsWriterList{metricsstore.NewResourceMetricsWriter("pods", store)}
+	if err := m.Write(io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP kube_state_metrics_shard_bytes Size of the series generated for the objects of a resource assigned to this shard in the text exposition format
+# TYPE kube_state_metrics_shard_bytes gauge
+kube_state_metrics_shard_bytes{resource="pods"} 152
+# HELP kube_state_metrics_shard_objects Number of objects of a resource assigned to this shard
+# TYPE kube_state_metrics_shard_objects gauge
+kube_state_metrics_shard_objects{resource="pods"} 2
+# HELP kube_state_metrics_shard_scrape_bytes Number of bytes written for a resource by the last scrape before compression
+# TYPE kube_state_metrics_shard_scrape_bytes gauge
+kube_state_metrics_shard_scrape_bytes{resource="pods"} 178
+# HELP kube_state_metrics_shard_series Number of series generated for the objects of a resource assigned to this shard
+# TYPE kube_state_metrics_shard_series gauge
+kube_state_metrics_shard_series{resource="pods"} 4
+`
+	c := NewShardingStatsCollector(m)
+	names := []string{"kube_state_metrics_shard_bytes", "kube_state_metrics_shard_objects", "kube_state_metrics_shard_scrape_bytes", "kube_state_metrics_shard_series"}
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), names...); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c, "kube_state_metrics_shard_sync_duration_seconds"); n != 1 {
+		t.Errorf("expected sync duration of synced store, got %d series", n)
+	}
+}
+
+func TestShardingDebugHandler(t *testing.T) {
+	tests := []struct {
+		strategy sharding.Strategy
+		query    string
+		status   int
+		want     shardAssignment
+	}{
+		{
+			query:  "",
+			status: http.StatusBadRequest,
+		},
+		{
+			query:  "uid=test_uid",
+			status: http.StatusOK,
+			want: shardAssignment{
+				UID:               "test_uid",
+				Strategy:          sharding.StrategyUID,
+				Shard:             sharding.ShardOf(sharding.StrategyUID, "test_uid", "", 3),
+				TotalShards:       3,
+				CurrentShard:      1,
+				AssignedToCurrent: sharding.ShardOf(sharding.StrategyUID, "test_uid", "", 3) == 1,
+			},
+		},
+		{
+			strategy: sharding.StrategyNamespace,
+			query:    "namespace=ns1",
+			status:   http.StatusOK,
+			want: shardAssignment{
+				Namespace:         "ns1",
+				Strategy:          sharding.StrategyNamespace,
+				Shard:             sharding.ShardOf(sharding.StrategyNamespace, "", "ns1", 3),
+				TotalShards:       3,
+				CurrentShard:      1,
+				AssignedToCurrent: sharding.ShardOf(sharding.StrategyNamespace, "", "ns1", 3) == 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		m := New(&options.Options{ShardingStrategy: test.strategy}, nil, nil, false)
+		m.curShard, m.curTotalShards = 1, 3
+
+		rec := httptest.NewRecorder()
+		m.ShardingDebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/sharding?"+test.query, nil))
+		if rec.Code != test.status {
+			t.Errorf("%q: expected status %d, got %d", test.query, test.status, rec.Code)
+			continue
+		}
+		if test.status != http.StatusOK {
+			continue
+		}
+		var got shardAssignment
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%q: expected %+v, got %+v", test.query, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// snapshotResourcePrefix starts the comment preceding the metrics of each
+// resource in a snapshot, followed by the name of the resource.
+const snapshotResourcePrefix = "# kube-state-metrics snapshot of resource "
+
+// snapshot holds the metrics persisted by a previous instance. They are served
+// until all stores of this instance are populated by their initial list.
+type snapshot struct {
+	mtx sync.Mutex
+	// resources holds the metrics of each resource in the text exposition
+	// format, or is nil once the snapshot is no longer served.
+	resources []snapshotResource
+	// timestamp is the family of the time the snapshot was taken at.
+	timestamp []byte
+	createdAt time.Time
+	maxAge    time.Duration
+	now       func() time.Time
+}
+
+// snapshotResource holds the metrics of a single resource of a snapshot.
+// Snapshots of previous versions hold the metrics of all resources in a
+// single snapshotResource without a resource name.
+type snapshotResource struct {
+	resource string
+	data     []byte
+}
+
+// LoadSnapshot loads the metrics snapshot written by WriteSnapshot to path.
+// The snapshot is served until all stores are synced, unless it is older than
+// the max age. A missing snapshot is not an error. LoadSnapshot must be
+// called before Run.
+func (m *MetricsHandler) LoadSnapshot(path string, maxAge time.Duration) error {
+	f, err := os.Open(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		klog.InfoS("No metrics snapshot found", "path", path)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open metrics snapshot: %w", err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read metrics snapshot: %w", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read metrics snapshot: %w", err)
+	}
+
+	s := &snapshot{createdAt: r.ModTime, maxAge: maxAge, now: time.Now}
+	if s.expired() {
+		klog.InfoS("Ignoring expired metrics snapshot", "path", path, "createdAt", s.createdAt)
+		return nil
+	}
+	// The time the snapshot was taken at is exposed along with it, so that
+	// it is visible that the metrics do not reflect the current state.
+	buf := &bytes.Buffer{}
+	err = writeFamily(buf, metric.Family{
+		Name:    "kube_state_metrics_snapshot_timestamp_seconds",
+		Help:    "Time the served snapshot of the metrics was taken at, while the stores are not yet synced after a restart.",
+		Type:    metric.Gauge,
+		Metrics: []*metric.Metric{{Value: float64(s.createdAt.Unix())}},
+	})
+	if err != nil {
+		return err
+	}
+	s.timestamp = buf.Bytes()
+	s.resources = splitSnapshot(data)
+	klog.InfoS("Serving metrics snapshot until all stores are synced", "path", path, "createdAt", s.createdAt)
+	m.snapshot = s
+	return nil
+}
+
+// WriteSnapshot atomically writes the metrics of all stores to path. Nothing is
+// written if not all stores are synced, so that an incomplete snapshot does not
+// replace a previous one.
+func (m *MetricsHandler) WriteSnapshot(path string) error {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	if !m.synced() {
+		klog.InfoS("Not writing metrics snapshot as not all stores are synced", "path", path)
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := gzip.NewWriter(tmp)
+	w.ModTime = time.Now()
+	for _, mw := range m.metricsWriters {
+		// The metrics of each resource are preceded by its name, so that
+		// only the resources of the scope of a scrape are served.
+		if _, err := io.WriteString(w, snapshotResourcePrefix+mw.Resource()+"\n"); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write metrics snapshot: %w", err)
+		}
+		if err := mw.WriteAll(w); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write metrics snapshot: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	klog.InfoS("Wrote metrics snapshot", "path", path)
+	return nil
+}
+
+// synced returns whether sharding is configured and all stores were populated
+// by their initial list. The caller must hold the read lock.
+func (m *MetricsHandler) synced() bool {
+	if m.ctx == nil {
+		return false
+	}
+	for _, mw := range m.metricsWriters {
+		if !mw.Synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// splitSnapshot splits the data of a snapshot into the metrics of its
+// resources.
+func splitSnapshot(data []byte) []snapshotResource {
+	prefix := []byte(snapshotResourcePrefix)
+	resources := []snapshotResource{}
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line = data[:i+1]
+		}
+		data = data[len(line):]
+		if bytes.HasPrefix(line, prefix) {
+			resources = append(resources, snapshotResource{resource: string(bytes.TrimSpace(line[len(prefix):]))})
+			continue
+		}
+		if len(resources) == 0 {
+			resources = append(resources, snapshotResource{})
+		}
+		current := &resources[len(resources)-1]
+		current.data = append(current.data, line...)
+	}
+	return resources
+}
+
+// writeSnapshot writes the metrics of the snapshot of the resources of the
+// scope to w in the text exposition format, while the snapshot is served
+// instead of the metrics of the stores. It returns false once all stores are
+// synced or the snapshot expired. The caller must hold the read lock.
+func (m *MetricsHandler) writeSnapshot(w io.Writer, scope *scrapeScope) (bool, error) {
+	s := m.snapshot
+	if s == nil {
+		return false, nil
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.resources == nil {
+		return false, nil
+	}
+	if m.synced() || s.expired() {
+		klog.InfoS("Stopped serving metrics snapshot")
+		s.resources = nil
+		return false, nil
+	}
+	for _, r := range s.resources {
+		if !scope.includesResource(r.resource) {
+			continue
+		}
+		if _, err := w.Write(r.data); err != nil {
+			return true, err
+		}
+	}
+	_, err := w.Write(s.timestamp)
+	return true, err
+}
+
+// expired returns whether the snapshot is older than its max age.
+func (s *snapshot) expired() bool {
+	return s.maxAge > 0 && s.now().Sub(s.createdAt) > s.maxAge
+}
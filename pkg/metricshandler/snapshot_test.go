@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func newPodInfoStore() *metricsstore.MetricsStore {
+	return metricsstore.NewMetricsStore([]string{"# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge"}, func(obj interface{}) []metric.FamilyInterface {
+		pod := obj.(*v1.Pod)
+		return []metric.FamilyInterface{&metric.Family{
+			Name:    "kube_pod_info",
+			Metrics: []*metric.Metric{{LabelKeys: []string{"pod"}, LabelValues: []string{pod.Name}, Value: 1}},
+		}}
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.gz")
+	pod1 := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", UID: types.UID("pod1")}}
+	pod2 := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod2", UID: types.UID("pod2")}}
+
+	// The previous instance writes its metrics on shutdown, once synced.
+	store := newPodInfoStore()
+	previous := New(&options.Options{}, nil, nil, false)
+	previous.ctx = context.Background()
+	previous.metricsWriters = metricsstore.MetricsWriterList{metricsstore.NewMetricsWriter(store)}
+	if err := store.Add(pod1); err != nil {
+		t.Fatal(err)
+	}
+	if err := previous.WriteSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := previous.LoadSnapshot(path, 0); err != nil || previous.snapshot != nil {
+		t.Fatalf("expected no snapshot to be written while not synced, got %v", err)
+	}
+	if err := store.Replace([]interface{}{pod1}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := previous.WriteSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The snapshot is served until the stores of the next instance are synced.
+	store = newPodInfoStore()
+	m := New(&options.Options{}, nil, nil, false)
+	m.ctx = context.Background()
+	m.metricsWriters = metricsstore.MetricsWriterList{metricsstore.NewMetricsWriter(store)}
+	if err := m.LoadSnapshot(path, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if m.snapshot == nil {
+		t.Fatal("expected snapshot to be loaded")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := m.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\nkube_pod_info{pod=\"pod1\"} 1\n" +
+		"# HELP kube_state_metrics_snapshot_timestamp_seconds Time the served snapshot of the metrics was taken at, while the stores are not yet synced after a restart.\n" +
+		"# TYPE kube_state_metrics_snapshot_timestamp_seconds gauge\n" +
+		"kube_state_metrics_snapshot_timestamp_seconds " + strconv.FormatFloat(float64(m.snapshot.createdAt.Unix()), 'g', -1, 64) + "\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	if err := store.Replace([]interface{}{pod2}, ""); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := m.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	expected = "# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge\nkube_pod_info{pod=\"pod2\"} 1\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSnapshotMaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.gz")
+	previous := New(&options.Options{}, nil, nil, false)
+	previous.ctx = context.Background()
+	if err := previous.WriteSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(&options.Options{}, nil, nil, false)
+	m.metricsWriters = metricsstore.MetricsWriterList{metricsstore.NewMetricsWriter(newPodInfoStore())}
+	if err := m.LoadSnapshot(path, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if m.snapshot == nil {
+		t.Fatal("expected snapshot to be loaded")
+	}
+	m.snapshot.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	buf := &bytes.Buffer{}
+	if err := m.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "kube_state_metrics_snapshot_timestamp_seconds") {
+		t.Errorf("expected expired snapshot not to be served, got %q", buf.String())
+	}
+}
+
+func TestSnapshotScope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.gz")
+	pods, configMaps := newPodInfoStore(), newPodInfoStore()
+	for store, name := range map[*metricsstore.MetricsStore]string{pods: "pod", configMaps: "configmap"} {
+		if err := store.Replace([]interface{}{&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}}}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	previous := New(&options.Options{}, nil, nil, false)
+	previous.ctx = context.Background()
+	previous.metricsWriters = metricsstore.MetricsWriterList{
+		metricsstore.NewResourceMetricsWriter("pods", pods),
+		metricsstore.NewResourceMetricsWriter("configmaps", configMaps),
+	}
+	if err := previous.WriteSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(&options.Options{}, nil, nil, false)
+	m.ctx = context.Background()
+	m.metricsWriters = metricsstore.MetricsWriterList{
+		metricsstore.NewResourceMetricsWriter("pods", newPodInfoStore()),
+		metricsstore.NewResourceMetricsWriter("configmaps", newPodInfoStore()),
+	}
+	if err := m.LoadSnapshot(path, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the snapshot of the resources of the scope is served.
+	buf := &bytes.Buffer{}
+	scope := (*scrapeScope)(nil).restrict(map[string]struct{}{"pods": {}})
+	if err := m.writeText(context.Background(), buf, scope, false); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if !strings.Contains(got, `kube_pod_info{pod="pod"} 1`) || strings.Contains(got, `pod="configmap"`) {
+		t.Errorf("expected only the snapshot of pods, got %q", got)
+	}
+	if strings.Contains(got, snapshotResourcePrefix) || !strings.Contains(got, "kube_state_metrics_snapshot_timestamp_seconds") {
+		t.Errorf("expected the snapshot without resource comments and with its timestamp, got %q", got)
+	}
+}
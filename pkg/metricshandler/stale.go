@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// writeStale writes whether the metrics of each resource are stale, as the
+// list and watch requests of its reflectors have been failing for longer than
+// the stale threshold. Nothing is written if staleness tracking is disabled.
+func (m *MetricsHandler) writeStale(w io.Writer) error {
+	if m.watchHealth == nil || m.watchHealth.Threshold() <= 0 {
+		return nil
+	}
+	stale := m.watchHealth.Stale()
+	resources := make([]string, 0, len(stale))
+	for resource := range stale {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	f := metric.Family{
+		Name: "kube_state_metrics_stale",
+		Help: "Whether the metrics of a resource are stale, as kube-state-metrics failed to list or watch it for longer than the stale threshold.",
+		Type: metric.Gauge,
+	}
+	for _, resource := range resources {
+		var v float64
+		if stale[resource] {
+			v = 1
+		}
+		f.Metrics = append(f.Metrics, &metric.Metric{LabelKeys: []string{"resource"}, LabelValues: []string{resource}, Value: v})
+	}
+	return writeFamily(w, f)
+}
+
+// writeFamily writes a metric family including its header in the text
+// exposition format.
+func writeFamily(w io.Writer, f metric.Family) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s", f.Name, f.Help, f.Name, f.Type, f.ByteSlice())
+	return err
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/watch"
+)
+
+func TestStaleMetric(t *testing.T) {
+	m := New(&options.Options{}, nil, nil, false)
+	m.watchHealth = watch.NewHealth(time.Nanosecond)
+
+	ctx := context.Background()
+	for resource, err := range map[string]error{"*v1.Pod": errors.New("connection refused"), "*v1.Node": nil} {
+		err := err
+		lw := watch.NewHealthTrackingListerWatcher(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return &v1.PodList{}, err
+			},
+		}, m.watchHealth.Track(ctx, resource))
+		lw.List(metav1.ListOptions{}) //nolint:errcheck
+	}
+	time.Sleep(time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	expected := "# HELP kube_state_metrics_stale Whether the metrics of a resource are stale, as kube-state-metrics failed to list or watch it for longer than the stale threshold.\n" +
+		"# TYPE kube_state_metrics_stale gauge\n" +
+		"kube_state_metrics_stale{resource=\"*v1.Node\"} 0\n" +
+		"kube_state_metrics_stale{resource=\"*v1.Pod\"} 1\n"
+	if got := rec.Body.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/prometheus/common/expfmt"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// standbyFamily is the only metric family exposed by standby instances.
+var standbyFamily = metric.Family{
+	Name:    "kube_state_metrics_standby",
+	Help:    "Whether this instance is a standby replica, which does not expose any other metrics.",
+	Type:    metric.Gauge,
+	Metrics: []*metric.Metric{{Value: 1}},
+}
+
+// SetStandby sets whether this instance is a standby replica. Stores of
+// standby instances are kept up to date, but only a marker metric is exposed,
+// so that a standby instance can take over without gaps or duplicate series.
+func (m *MetricsHandler) SetStandby(standby bool) {
+	var v int32
+	if standby {
+		v = 1
+	}
+	if atomic.SwapInt32(&m.standby, v) != v && m.responseCache != nil {
+		m.responseCache.invalidate()
+	}
+}
+
+// IsStandby returns whether this instance is a standby replica.
+func (m *MetricsHandler) IsStandby() bool {
+	return atomic.LoadInt32(&m.standby) == 1
+}
+
+// writeStandby writes the standby marker metric to w in the given format.
+func writeStandby(w io.Writer, format expfmt.Format) error {
+	if format == expfmt.FmtProtoDelim {
+		return expfmt.NewEncoder(w, format).Encode(familyToProto(&standbyFamily))
+	}
+	return writeFamily(w, standbyFamily)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestStandby(t *testing.T) {
+	store := metricsstore.NewMetricsStore([]string{"# HELP kube_pod_info Info\n# TYPE kube_pod_info gauge"}, func(obj interface{}) []metric.FamilyInterface {
+		return []metric.FamilyInterface{&metric.Family{
+			Name:    "kube_pod_info",
+			Metrics: []*metric.Metric{{LabelKeys: []string{"pod"}, LabelValues: []string{obj.(*v1.Pod).Name}, Value: 1}},
+		}}
+	})
+	store.SetKeepFamilies()
+	if err := store.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", UID: "uid1"}}); err != nil {
+		t.Fatal(err)
+	}
+	m := New(&options.Options{ScrapeCacheTTL: time.Minute}, nil, nil, false)
+	m.metricsWriters = metricsstore.MetricsWriterList{metricsstore.NewMetricsWriter(store)}
+
+	scrape := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if body := scrape("").Body.String(); !strings.Contains(body, `kube_pod_info{pod="pod1"} 1`) {
+		t.Fatalf("expected metrics to be exposed, got %q", body)
+	}
+	if families := m.Families(); len(families) != 1 || families[0].Name != "kube_pod_info" {
+		t.Fatalf("expected the kube_pod_info family, got %v", families)
+	}
+
+	m.SetStandby(true)
+	expected := "# HELP kube_state_metrics_standby Whether this instance is a standby replica, which does not expose any other metrics.\n" +
+		"# TYPE kube_state_metrics_standby gauge\n" +
+		"kube_state_metrics_standby 1\n"
+	if body := scrape("").Body.String(); body != expected {
+		t.Errorf("expected standby response %q, got %q", expected, body)
+	}
+
+	rec := scrape(string(expfmt.FmtProtoDelim))
+	mf := &dto.MetricFamily{}
+	if err := expfmt.NewDecoder(rec.Body, expfmt.FmtProtoDelim).Decode(mf); err != nil {
+		t.Fatal(err)
+	}
+	if mf.GetName() != "kube_state_metrics_standby" || mf.GetMetric()[0].GetGauge().GetValue() != 1 {
+		t.Errorf("unexpected protobuf standby response %v", mf)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := m.Write(buf); err != nil || buf.Len() != 0 {
+		t.Errorf("expected no metrics to be written by a standby instance, got %q, %v", buf.String(), err)
+	}
+	if families := m.Families(); families != nil {
+		t.Errorf("expected no metric families of a standby instance, got %v", families)
+	}
+
+	m.SetStandby(false)
+	if body := scrape("").Body.String(); !strings.Contains(body, `kube_pod_info{pod="pod1"} 1`) {
+		t.Errorf("expected metrics to be exposed after leaving standby, got %q", body)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// stateResponse is the JSON representation of all metrics served by the
+// state endpoint.
+type stateResponse struct {
+	Families []stateFamily `json:"families"`
+}
+
+type stateFamily struct {
+	Name    string        `json:"name"`
+	Help    string        `json:"help,omitempty"`
+	Type    metric.Type   `json:"type,omitempty"`
+	Samples []stateSample `json:"samples"`
+}
+
+type stateSample struct {
+	Labels map[string]string `json:"labels"`
+	Value  stateValue        `json:"value"`
+}
+
+// stateValue encodes NaN and infinite values, which are not valid JSON
+// numbers, as strings in the format of the text exposition format.
+type stateValue float64
+
+func (v stateValue) MarshalJSON() ([]byte, error) {
+	f := float64(v)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return []byte(`"` + strconv.FormatFloat(f, 'g', -1, 64) + `"`), nil
+	}
+	return json.Marshal(f)
+}
+
+// StateHandler returns a handler serving the same metrics as ServeHTTP as
+// structured JSON, e.g. for consumers which do not parse the text exposition
+// format. Scrapes may be scoped by the same query parameters.
+func (m *MetricsHandler) StateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := parseScrapeScope(r.URL.Query())
+		if m.listenedResources != nil {
+			scope = scope.exclude(m.listenedResources)
+		}
+
+		buf := &bytes.Buffer{}
+		var err error
+		if m.IsStandby() {
+			err = writeStandby(buf, expfmt.FmtText)
+		} else {
+			m.mtx.RLock()
+			err = m.writeText(r.Context(), buf, scope, true)
+			m.mtx.RUnlock()
+		}
+		if err != nil {
+			klog.ErrorS(err, "Failed to write metrics")
+			http.Error(w, "failed to write metrics", http.StatusInternalServerError)
+			return
+		}
+		families, err := metric.ParseFamilies(buf)
+		if err != nil {
+			klog.ErrorS(err, "Failed to parse metrics")
+			http.Error(w, "failed to parse metrics", http.StatusInternalServerError)
+			return
+		}
+
+		resp := stateResponse{Families: make([]stateFamily, 0, len(families))}
+		for _, f := range families {
+			sf := stateFamily{Name: f.Name, Help: f.Help, Type: f.Type, Samples: make([]stateSample, 0, len(f.Metrics))}
+			for _, s := range f.Metrics {
+				labels := make(map[string]string, len(s.LabelKeys))
+				for i := range s.LabelKeys {
+					labels[s.LabelKeys[i]] = s.LabelValues[i]
+				}
+				sf.Samples = append(sf.Samples, stateSample{Labels: labels, Value: stateValue(s.Value)})
+			}
+			resp.Families = append(resp.Families, sf)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			klog.ErrorS(err, "Failed to write state")
+		}
+	})
+}
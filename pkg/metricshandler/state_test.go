@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestStateHandler(t *testing.T) {
+	m := New(&options.Options{}, nil, nil, false)
+	m.metricsWriters = newScopeTestWriters(t)
+
+	rec := httptest.NewRecorder()
+	m.StateHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state?metrics=kube_pod_info&namespace=team-a", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected content type application/json, got %s", ct)
+	}
+	expected := `{"families":[{"name":"kube_pod_info","help":"Info","type":"gauge","samples":[{"labels":{"namespace":"team-a","pod":"pod1"},"value":1}]}]}` + "\n"
+	if got := rec.Body.String(); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	m.SetStandby(true)
+	rec = httptest.NewRecorder()
+	m.StateHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	expected = `{"families":[{"name":"kube_state_metrics_standby","help":"Whether this instance is a standby replica, which does not expose any other metrics.","type":"gauge","samples":[{"labels":{},"value":1}]}]}` + "\n"
+	if got := rec.Body.String(); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
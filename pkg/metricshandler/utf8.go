@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+// utf8Escaping is the value of the escaping parameter of the media types of
+// the Accept header of clients which support UTF-8 label names.
+const utf8Escaping = "allow-utf-8"
+
+// negotiateUTF8Names returns whether the client accepts UTF-8 label names in
+// the given format, i.e. whether the media type of the format is accepted with
+// the escaping=allow-utf-8 parameter.
+func negotiateUTF8Names(accept string, format expfmt.Format) bool {
+	want := "text/plain"
+	if format == expfmt.FmtProtoDelim {
+		want = expfmt.ProtoType
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != want {
+			continue
+		}
+		if params["escaping"] == utf8Escaping {
+			return true
+		}
+	}
+	return false
+}
+
+// utf8ContentType returns the content type of responses in the given format
+// with UTF-8 label names.
+func utf8ContentType(format expfmt.Format) string {
+	if format == expfmt.FmtProtoDelim {
+		return string(format) + "; escaping=" + utf8Escaping
+	}
+	return "text/plain; version=1.0.0; charset=utf-8; escaping=" + utf8Escaping
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+func TestNegotiateUTF8Names(t *testing.T) {
+	tests := []struct {
+		accept string
+		format expfmt.Format
+		want   bool
+	}{
+		{accept: "", format: expfmt.FmtText, want: false},
+		{accept: "text/plain;version=0.0.4", format: expfmt.FmtText, want: false},
+		{accept: "text/plain;version=1.0.0;escaping=allow-utf-8", format: expfmt.FmtText, want: true},
+		{accept: "text/plain;version=1.0.0;escaping=underscores", format: expfmt.FmtText, want: false},
+		{
+			accept: "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;escaping=allow-utf-8;q=0.5,text/plain;version=0.0.4;q=0.3",
+			format: expfmt.FmtProtoDelim,
+			want:   true,
+		},
+		{
+			accept: "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.5,text/plain;version=1.0.0;escaping=allow-utf-8;q=0.3",
+			format: expfmt.FmtProtoDelim,
+			want:   false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := negotiateUTF8Names(test.accept, test.format); got != test.want {
+			t.Errorf("negotiateUTF8Names(%q, %q): expected %t, got %t", test.accept, test.format, test.want, got)
+		}
+	}
+}
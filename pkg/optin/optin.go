@@ -21,12 +21,14 @@ import (
 	"sort"
 	"strings"
 
+	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
 
 // MetricFamilyFilter filters metric families which are defined as opt-in by their generator.FamilyGenerator
 type MetricFamilyFilter struct {
-	metrics []*regexp.Regexp
+	patterns []string
+	metrics  []*regexp.Regexp
 }
 
 // Test tests if a given generator is an opt-in metric family and was passed as an opt-in metric family at startup
@@ -44,10 +46,7 @@ func (filter MetricFamilyFilter) Test(generator generator.FamilyGenerator) bool
 
 // Status returns the metrics contained within the filter as a comma-separated string
 func (filter MetricFamilyFilter) Status() string {
-	asStrings := make([]string, 0)
-	for _, metric := range filter.metrics {
-		asStrings = append(asStrings, metric.String())
-	}
+	asStrings := append([]string(nil), filter.patterns...)
 	// sort the strings for the sake of ux such that the resulting status is consistent
 	sort.Strings(asStrings)
 	return strings.Join(asStrings, ", ")
@@ -58,15 +57,19 @@ func (filter MetricFamilyFilter) Count() int {
 	return len(filter.metrics)
 }
 
-// NewMetricFamilyFilter creates new MetricFamilyFilter instances.
+// NewMetricFamilyFilter creates new MetricFamilyFilter instances. Each metric
+// is either an exact metric name or a regex which has to match the whole name
+// of opt-in metric families, e.g. kube_pod_container_.*.
 func NewMetricFamilyFilter(metrics map[string]struct{}) (*MetricFamilyFilter, error) {
-	regexes := make([]*regexp.Regexp, 0)
+	patterns := make([]string, 0, len(metrics))
+	regexes := make([]*regexp.Regexp, 0, len(metrics))
 	for metric := range metrics {
-		regex, err := regexp.Compile(metric)
+		regex, err := allowdenylist.CompilePattern(metric)
 		if err != nil {
 			return nil, err
 		}
+		patterns = append(patterns, metric)
 		regexes = append(regexes, regex)
 	}
-	return &MetricFamilyFilter{regexes}, nil
+	return &MetricFamilyFilter{patterns: patterns, metrics: regexes}, nil
 }
@@ -33,6 +33,9 @@ func TestFilter(t *testing.T) {
 		{"kube_pod_container_status_running", true, "kube_pod_container_status_.+", true},
 		{"kube_pod_container_status_terminated", true, "kube_pod_container_status_running", false},
 		{"kube_pod_container_status_reason", true, "kube_pod_container_status_(running|terminated)", false},
+		{"kube_pod_container_status_running", true, "kube_pod_container_.*", true},
+		{"kube_pod_init_container_status_running", true, "kube_pod_container_.*", false},
+		{"kube_pod_container_status_running_seconds", true, "kube_pod_container_status_running", false},
 		{"kube_node_info", false, "", true},
 	}
 
@@ -0,0 +1,38 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewDoctorCommand returns the doctor subcommand, which checks the options
+// against a cluster by calling run. It accepts the flags of the root command
+// and must be called after AddFlags.
+func NewDoctorCommand(o *Options, run func()) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check whether kube-state-metrics is able to run with the given flags against a cluster.",
+		Long:  "Check whether the service account of kube-state-metrics is allowed to list and watch all enabled resources and configured custom resources, whether the custom resources exist and whether the sharding settings are consistent. Exits with 1 if errors were found.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			run()
+		},
+	}
+	cmd.Flags().AddFlagSet(o.cmd.Flags())
+	return cmd
+}
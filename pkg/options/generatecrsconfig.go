@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// GenerateCRSConfigOptions are the options of the generate-crs-config
+// subcommand.
+type GenerateCRSConfigOptions struct {
+	Groups []string
+}
+
+// NewGenerateCRSConfigCommand returns the generate-crs-config subcommand,
+// which writes a starter Custom Resource State config for the
+// CustomResourceDefinitions of a cluster by calling run with its own options.
+// It accepts the flags of the root command and must be called after AddFlags.
+func NewGenerateCRSConfigCommand(o *Options, run func(generateCRSConfigOpts *GenerateCRSConfigOptions)) *cobra.Command {
+	generateCRSConfigOpts := &GenerateCRSConfigOptions{}
+	cmd := &cobra.Command{
+		Use:     "generate-crs-config",
+		Short:   "Generate a starter Custom Resource State config from the CustomResourceDefinitions of a cluster.",
+		Long:    "Generate a Custom Resource State config with info, created, status condition and replicas metrics inferred from the schemas of the CustomResourceDefinitions of a cluster, as a starting point for metrics of installed operators.",
+		Example: "kube-state-metrics generate-crs-config --kubeconfig ~/.kube/config --group cert-manager.io > crs.yaml",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			run(generateCRSConfigOpts)
+		},
+	}
+	cmd.Flags().AddFlagSet(o.cmd.Flags())
+	cmd.Flags().StringSliceVar(&generateCRSConfigOpts.Groups, "group", nil, "API groups of the CustomResourceDefinitions to generate the config for, defaults to all groups.")
+	return cmd
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// MigrateCRSConfigOptions are the options of the migrate-crs-config
+// subcommand.
+type MigrateCRSConfigOptions struct {
+	CustomResourceConfigFile string
+}
+
+// NewMigrateCRSConfigCommand returns the migrate-crs-config subcommand, which
+// rewrites a Custom Resource State config file into the current schema by
+// calling run with its options.
+func NewMigrateCRSConfigCommand(run func(o *MigrateCRSConfigOptions)) *cobra.Command {
+	o := &MigrateCRSConfigOptions{}
+	cmd := &cobra.Command{
+		Use:   "migrate-crs-config",
+		Short: "Migrate a Custom Resource State Metrics config file to the current schema.",
+		Long:  "Rewrite deprecated constructs of a Custom Resource State Metrics config file, like string paths, fields of each without a type and nilIsZero, into the current schema. The migrated config is written to stdout with a comment above each changed field, the changes are listed on stderr.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			run(o)
+		},
+	}
+	cmd.Flags().StringVar(&o.CustomResourceConfigFile, "custom-resource-state-config-file", "", "Path to the Custom Resource State Metrics config file to migrate")
+	return cmd
+}
@@ -19,42 +19,134 @@ package options
 import (
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/version"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/featuregate"
 	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+	"k8s.io/kube-state-metrics/v2/pkg/features"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	"k8s.io/kube-state-metrics/v2/pkg/otlp"
+	"k8s.io/kube-state-metrics/v2/pkg/sharding"
 )
 
 // Options are the configurable parameters for kube-state-metrics.
 type Options struct {
-	AnnotationsAllowList     LabelsAllowList `yaml:"annotations_allow_list"`
-	Apiserver                string          `yaml:"apiserver"`
-	CustomResourceConfig     string          `yaml:"custom_resource_config"`
-	CustomResourceConfigFile string          `yaml:"custom_resource_config_file"`
-	CustomResourcesOnly      bool            `yaml:"custom_resources_only"`
-	EnableGZIPEncoding       bool            `yaml:"enable_gzip_encoding"`
-	Help                     bool            `yaml:"help"`
-	Host                     string          `yaml:"host"`
-	Kubeconfig               string          `yaml:"kubeconfig"`
-	LabelsAllowList          LabelsAllowList `yaml:"labels_allow_list"`
-	MetricAllowlist          MetricSet       `yaml:"metric_allowlist"`
-	MetricDenylist           MetricSet       `yaml:"metric_denylist"`
-	MetricOptInList          MetricSet       `yaml:"metric_opt_in_list"`
-	Namespace                string          `yaml:"namespace"`
-	Namespaces               NamespaceList   `yaml:"namespaces"`
-	NamespacesDenylist       NamespaceList   `yaml:"namespaces_denylist"`
-	Node                     NodeType        `yaml:"node"`
-	Pod                      string          `yaml:"pod"`
-	Port                     int             `yaml:"port"`
-	Resources                ResourceSet     `yaml:"resources"`
-	Shard                    int32           `yaml:"shard"`
-	TLSConfig                string          `yaml:"tls_config"`
-	TelemetryHost            string          `yaml:"telemetry_host"`
-	TelemetryPort            int             `yaml:"telemetry_port"`
-	TotalShards              int             `yaml:"total_shards"`
-	UseAPIServerCache        bool            `yaml:"use_api_server_cache"`
+	AggregatedResources                 LabelsAllowList   `yaml:"aggregated_resources"`
+	AnnotationsAllowList                LabelsAllowList   `yaml:"annotations_allow_list"`
+	Apiserver                           string            `yaml:"apiserver"`
+	AuthDelegation                      bool              `yaml:"auth_delegation"`
+	AuthDelegationNamespace             string            `yaml:"auth_delegation_namespace"`
+	AuthDelegationResource              string            `yaml:"auth_delegation_resource"`
+	CustomResourceAutodiscovery         bool              `yaml:"custom_resource_autodiscovery"`
+	CustomResourceAutodiscoverySelector string            `yaml:"custom_resource_autodiscovery_selector"`
+	CustomResourceMappingRefresh        time.Duration     `yaml:"custom_resource_mapping_refresh"`
+	CustomResourceProfiles              []string          `yaml:"custom_resource_profiles"`
+	CustomResourceConfig                string            `yaml:"custom_resource_config"`
+	CustomResourceConfigFile            string            `yaml:"custom_resource_config_file"`
+	CustomResourcesOnly                 bool              `yaml:"custom_resources_only"`
+	EnableGZIPEncoding                  bool              `yaml:"enable_gzip_encoding"`
+	EnableVPAMetrics                    bool              `yaml:"enable_vpa_metrics"`
+	ExcludeCompleted                    ExcludeCompleted  `yaml:"exclude_completed"`
+	ExternalLabels                      map[string]string `yaml:"external_labels"`
+	FeatureGates                        map[string]bool   `yaml:"feature_gates"`
+	FieldSelectors                      FieldSelectors    `yaml:"field_selectors"`
+	Help                                bool              `yaml:"help"`
+	Host                                string            `yaml:"host"`
+	Kubeconfig                          string            `yaml:"kubeconfig"`
+	LabelsAllowList                     LabelsAllowList   `yaml:"labels_allow_list"`
+	LabelsDenyList                      LabelsAllowList   `yaml:"labels_deny_list"`
+	LabelsRecommended                   bool              `yaml:"labels_recommended"`
+	LazyResources                       ResourceSet       `yaml:"lazy_resources"`
+	LeaderElect                         bool              `yaml:"leader_elect"`
+	ListPageSize                        int64             `yaml:"list_page_size"`
+	ListenAddresses                     []string          `yaml:"listen_addresses"`
+	LeaderElectLeaseDuration            time.Duration     `yaml:"leader_elect_lease_duration"`
+	LeaderElectLeaseName                string            `yaml:"leader_elect_lease_name"`
+	LeaderElectNamespace                string            `yaml:"leader_elect_namespace"`
+	LeaderElectRenewDeadline            time.Duration     `yaml:"leader_elect_renew_deadline"`
+	LeaderElectRetryPeriod              time.Duration     `yaml:"leader_elect_retry_period"`
+	MaxConcurrentScrapes                int               `yaml:"max_concurrent_scrapes"`
+	MaxLabelValueLength                 int               `yaml:"max_label_value_length"`
+	MaxObjects                          MaxObjects        `yaml:"max_objects"`
+	MetricAllowlist                     MetricSet         `yaml:"metric_allowlist"`
+	MetricDenylist                      MetricSet         `yaml:"metric_denylist"`
+	MetricOptInList                     MetricSet         `yaml:"metric_opt_in_list"`
+	MetricPrefixes                      map[string]string `yaml:"metric_prefixes"`
+	Namespace                           string            `yaml:"namespace"`
+	Namespaces                          NamespaceList     `yaml:"namespaces"`
+	NamespacesDenylist                  NamespaceList     `yaml:"namespaces_denylist"`
+	NamespacesSelector                  string            `yaml:"namespaces_selector"`
+	Node                                NodeType          `yaml:"node"`
+	ObjectCountResources                ResourceSet       `yaml:"object_count_resources"`
+	OTLPBatchSize                       int               `yaml:"otlp_batch_size"`
+	OTLPCompression                     string            `yaml:"otlp_compression"`
+	OTLPEndpoint                        string            `yaml:"otlp_endpoint"`
+	OTLPInterval                        time.Duration     `yaml:"otlp_interval"`
+	OTLPOnly                            bool              `yaml:"otlp_only"`
+	OTLPProtocol                        string            `yaml:"otlp_protocol"`
+	OTLPTimeout                         time.Duration     `yaml:"otlp_timeout"`
+	OTLPTracesEndpoint                  string            `yaml:"otlp_traces_endpoint"`
+	OTLPTracesSampleRatio               float64           `yaml:"otlp_traces_sample_ratio"`
+	PluginDir                           string            `yaml:"plugin_dir"`
+	Pod                                 string            `yaml:"pod"`
+	PodStatusReasons                    []string          `yaml:"pod_status_reasons"`
+	Port                                int               `yaml:"port"`
+	ReadyTimeout                        time.Duration     `yaml:"ready_timeout"`
+	ResolvePodWorkloads                 bool              `yaml:"resolve_pod_workloads"`
+	RecordingRulesConfigFile            string            `yaml:"recording_rules_config_file"`
+	RelabelConfigFile                   string            `yaml:"relabel_config_file"`
+	Resources                           ResourceSet       `yaml:"resources"`
+	ScrapeCacheTTL                      time.Duration     `yaml:"scrape_cache_ttl"`
+	ScrapeWorkers                       int               `yaml:"scrape_workers"`
+	Shard                               int32             `yaml:"shard"`
+	ShardingLeaseDuration               time.Duration     `yaml:"sharding_lease_duration"`
+	ResourceListeners                   ResourceListeners `yaml:"resource_listeners"`
+	ServerIdleTimeout                   time.Duration     `yaml:"server_idle_timeout"`
+	ServerMaxHeaderBytes                int               `yaml:"server_max_header_bytes"`
+	ServerReadHeaderTimeout             time.Duration     `yaml:"server_read_header_timeout"`
+	ServerReadTimeout                   time.Duration     `yaml:"server_read_timeout"`
+	ServerWriteTimeout                  time.Duration     `yaml:"server_write_timeout"`
+	ShardingLeaseGroup                  string            `yaml:"sharding_lease_group"`
+	ShardingStrategy                    sharding.Strategy `yaml:"sharding_strategy"`
+	SnapshotFile                        string            `yaml:"snapshot_file"`
+	SnapshotMaxAge                      time.Duration     `yaml:"snapshot_max_age"`
+	SortMetrics                         bool              `yaml:"sort_metrics"`
+	StaleObjectGCInterval               time.Duration     `yaml:"stale_object_gc_interval"`
+	StaleThreshold                      time.Duration     `yaml:"stale_threshold"`
+	StartupConcurrency                  int               `yaml:"startup_concurrency"`
+	StartupJitter                       time.Duration     `yaml:"startup_jitter"`
+	TLSCertFile                         string            `yaml:"tls_cert_file"`
+	TLSClientAllowedNames               []string          `yaml:"tls_client_allowed_names"`
+	TLSClientCAFile                     string            `yaml:"tls_client_ca_file"`
+	TLSConfig                           string            `yaml:"tls_config"`
+	TLSPrivateKeyFile                   string            `yaml:"tls_private_key_file"`
+	TelemetryHost                       string            `yaml:"telemetry_host"`
+	TelemetryListenAddresses            []string          `yaml:"telemetry_listen_addresses"`
+	TelemetryPort                       int               `yaml:"telemetry_port"`
+	TextfileInterval                    time.Duration     `yaml:"textfile_interval"`
+	TopK                                MaxObjects        `yaml:"top_k"`
+	TextfileOnly                        bool              `yaml:"textfile_only"`
+	TextfilePath                        string            `yaml:"textfile_path"`
+	TotalShards                         int               `yaml:"total_shards"`
+	UTF8LabelNames                      bool              `yaml:"utf8_label_names"`
+	UseAPIServerCache                   bool              `yaml:"use_api_server_cache"`
+	UseWatchList                        bool              `yaml:"use_watch_list"`
+	WatchBackoffInitial                 time.Duration     `yaml:"watch_backoff_initial"`
+	WatchBackoffMax                     time.Duration     `yaml:"watch_backoff_max"`
+	WatchBackoffReset                   time.Duration     `yaml:"watch_backoff_reset"`
 
 	Config string
 
@@ -66,6 +158,25 @@ func GetConfigFile(opt Options) string {
 	return opt.Config
 }
 
+// Clone returns a copy of the options whose maps can be modified, e.g. by
+// decoding a config file into it, without modifying the options.
+func (o *Options) Clone() *Options {
+	c := *o
+	v := reflect.ValueOf(&c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Map || f.IsNil() || !f.CanSet() {
+			continue
+		}
+		m := reflect.MakeMapWithSize(f.Type(), f.Len())
+		for iter := f.MapRange(); iter.Next(); {
+			m.SetMapIndex(iter.Key(), iter.Value())
+		}
+		f.Set(m)
+	}
+	return &c
+}
+
 // NewOptions returns a new instance of `Options`.
 func NewOptions() *Options {
 	return &Options{
@@ -75,6 +186,15 @@ func NewOptions() *Options {
 		MetricOptInList:      MetricSet{},
 		AnnotationsAllowList: LabelsAllowList{},
 		LabelsAllowList:      LabelsAllowList{},
+		LabelsDenyList:       LabelsAllowList{},
+		FieldSelectors:       FieldSelectors{},
+		MaxObjects:           MaxObjects{},
+		ExcludeCompleted:     ExcludeCompleted{},
+		TopK:                 MaxObjects{},
+		LazyResources:        ResourceSet{},
+		AggregatedResources:  LabelsAllowList{},
+		ObjectCountResources: ResourceSet{},
+		ResourceListeners:    ResourceListeners{},
 	}
 }
 
@@ -118,32 +238,109 @@ func (o *Options) AddFlags(cmd *cobra.Command) {
 
 	autoshardingNotice := "When set, it is expected that --pod and --pod-namespace are both set. Most likely this should be passed via the downward API. This is used for auto-detecting sharding. If set, this has preference over statically configured sharding. This is experimental, it may be removed without notice."
 
+	o.cmd.Flags().BoolVar(&o.AuthDelegation, "auth-delegation", false, "Authenticate requests of the metrics server by their bearer token via TokenReviews and authorize them via SubjectAccessReviews. Users are authorized for the get verb of the request path, e.g. /metrics, unless --auth-delegation-resource is set. /healthz, /livez and /readyz are not authorized.")
+	o.cmd.Flags().StringVar(&o.AuthDelegationResource, "auth-delegation-resource", "", "Resource in the form resource[.group][/subresource] users are authorized for the get verb of with --auth-delegation, e.g. 'services/proxy'. Authorizes the request path if empty.")
+	o.cmd.Flags().StringVar(&o.AuthDelegationNamespace, "auth-delegation-namespace", "", "Namespace of --auth-delegation-resource. Cluster-scoped if empty.")
+	o.cmd.Flags().BoolVar(&o.CustomResourceAutodiscovery, "custom-resource-autodiscovery", false, "Generate default Custom Resource State metrics (info, created, status conditions and replicas) for all installed CustomResourceDefinitions. Resources configured via --custom-resource-state-config take precedence (experimental)")
 	o.cmd.Flags().BoolVar(&o.CustomResourcesOnly, "custom-resource-state-only", false, "Only provide Custom Resource State metrics (experimental)")
 	o.cmd.Flags().BoolVar(&o.EnableGZIPEncoding, "enable-gzip-encoding", false, "Gzip responses when requested by clients via 'Accept-Encoding: gzip' header.")
+	o.cmd.Flags().BoolVar(&o.EnableVPAMetrics, "enable-vpa-metrics", false, "Generate the VerticalPodAutoscaler metrics of the deprecated verticalpodautoscalers resource from the autoscaling.k8s.io CustomResourceDefinitions, using the Custom Resource State Metrics profile compiled into the binary. Resources configured via --custom-resource-state-config take precedence (experimental)")
 	o.cmd.Flags().BoolVarP(&o.Help, "help", "h", false, "Print Help text")
+	o.cmd.Flags().BoolVar(&o.LeaderElect, "leader-elect", false, "Elect a leader among multiple replicas using a Lease. All replicas keep their caches up to date, but only the leader exposes metrics. Standby replicas only expose the kube_state_metrics_standby metric, so that they can take over without duplicate series.")
+	o.cmd.Flags().BoolVar(&o.OTLPOnly, "otlp-only", false, "Only export metrics via OTLP and do not start the metrics server. Requires --otlp-endpoint.")
+	o.cmd.Flags().StringSliceVar(&o.PodStatusReasons, "pod-status-reasons", nil, "Comma-separated list of additional pod status reasons to expose in kube_pod_status_reason, e.g. reasons of newer kubelets. The reasons Evicted, NodeAffinity, NodeLost, Shutdown and UnexpectedAdmissionError are always exposed.")
+	o.cmd.Flags().BoolVar(&o.ResolvePodWorkloads, "resolve-pod-workloads", false, "Expose kube_pod_workload_info with the workload of each pod, e.g. the Deployment of the ReplicaSet owning the pod. It requires watching the metadata of all ReplicaSets and Jobs in the enabled namespaces, independent of sharding.")
+	o.cmd.Flags().BoolVar(&o.UTF8LabelNames, "utf8-label-names", false, "Expose labels generated from Kubernetes label and annotation keys which contain characters invalid in legacy Prometheus label names, e.g. app.kubernetes.io/name, verbatim using the quoted UTF-8 label name syntax to clients negotiating 'escaping=allow-utf-8' via the Accept header. Other clients receive the sanitized label names (experimental).")
 	o.cmd.Flags().BoolVarP(&o.UseAPIServerCache, "use-apiserver-cache", "", false, "Sets resourceVersion=0 for ListWatch requests, using cached resources from the apiserver instead of an etcd quorum read.")
+	o.cmd.Flags().Int64Var(&o.ListPageSize, "list-page-size", 0, "Number of objects requested per page when listing resources. Smaller pages reduce the cost of each request to the apiserver at the cost of more requests. Lists served from the apiserver cache with --use-apiserver-cache are not paged. Defaults to the page size of client-go if 0.")
+	o.cmd.Flags().IntVar(&o.StartupConcurrency, "startup-concurrency", 0, "Maximum number of resources, respectively namespaces of resources, listed concurrently when kube-state-metrics starts, e.g. to stay within the API Priority and Fairness limits of the apiserver in large clusters. Unlimited if 0.")
+	o.cmd.Flags().DurationVar(&o.StartupJitter, "startup-jitter", 0, "Maximum random delay of the initial list of each resource, respectively namespace of a resource, when kube-state-metrics starts, which spreads the initial lists of multiple instances started at the same time. Disabled if 0.")
+	o.cmd.Flags().DurationVar(&o.WatchBackoffInitial, "watch-backoff-initial", 800*time.Millisecond, "Backoff of reflectors after a failed attempt to list and watch a resource, which is doubled after each subsequent failure up to --watch-backoff-max. Each backoff is extended by a random jitter of up to its duration. Uses the backoff of client-go if 0.")
+	o.cmd.Flags().DurationVar(&o.WatchBackoffMax, "watch-backoff-max", 30*time.Second, "Maximum backoff of reflectors between failed attempts to list and watch a resource, before jitter.")
+	o.cmd.Flags().DurationVar(&o.WatchBackoffReset, "watch-backoff-reset", 2*time.Minute, "Duration without failed attempts to list and watch a resource after which the backoff of its reflectors is reset to --watch-backoff-initial.")
+	o.cmd.Flags().BoolVarP(&o.UseWatchList, "use-watch-list", "", false, "Stream the initial list of resources via watch requests instead of list requests, which reduces the apiserver memory usage when kube-state-metrics starts. Falls back to list requests if the WatchList feature is not enabled in the apiserver (experimental).")
 	o.cmd.Flags().Int32Var(&o.Shard, "shard", int32(0), "The instances shard nominal (zero indexed) within the total number of shards. (default 0)")
 	o.cmd.Flags().IntVar(&o.Port, "port", 8080, `Port to expose metrics on.`)
 	o.cmd.Flags().IntVar(&o.TelemetryPort, "telemetry-port", 8081, `Port to expose kube-state-metrics self metrics on.`)
 	o.cmd.Flags().IntVar(&o.TotalShards, "total-shards", 1, "The total number of shards. Sharding is disabled when total shards is set to 1.")
 	o.cmd.Flags().StringVar(&o.Apiserver, "apiserver", "", `The URL of the apiserver to use as a master`)
+	o.cmd.Flags().StringVar(&o.PluginDir, "plugin-dir", "", "Directory of collector plugins. Each executable in the directory is started and collects the metrics of a custom resource via the plugin protocol, see docs/developer/guide.md. The resources of the plugins are enabled in addition to --resources (experimental).")
+	o.cmd.Flags().StringVar(&o.LeaderElectLeaseName, "leader-elect-lease-name", "kube-state-metrics", "Name of the Lease used for leader election.")
+	o.cmd.Flags().StringVar(&o.LeaderElectNamespace, "leader-elect-namespace", "", "Namespace of the Lease used for leader election. Defaults to --pod-namespace.")
+	o.cmd.Flags().StringVar(&o.ShardingLeaseGroup, "sharding-lease-group", "", "Name of a group of identical kube-state-metrics replicas, e.g. of a Deployment, which assign shards among each other using Leases in the namespace of --pod-namespace. Requires --pod and --pod-namespace. Takes preference over StatefulSet based autosharding. This is experimental.")
+	o.cmd.Flags().StringVar((*string)(&o.ShardingStrategy), "sharding-strategy", string(sharding.StrategyUID), fmt.Sprintf("The strategy by which objects are assigned to shards, one of %q. With 'namespace', all objects of a namespace are assigned to the same shard.", sharding.Strategies))
+	o.cmd.Flags().DurationVar(&o.CustomResourceMappingRefresh, "custom-resource-mapping-refresh", 10*time.Minute, "Interval in which the resources of custom resources configured without a resourcePlural in the --custom-resource-state-config are resolved again from the discovery information of the apiserver. They are also resolved again whenever CustomResourceDefinitions change, if kube-state-metrics is allowed to list and watch them. Only resolved on changes if 0.")
+	o.cmd.Flags().StringVar(&o.CustomResourceAutodiscoverySelector, "custom-resource-autodiscovery-selector", "", "Label selector of the CustomResourceDefinitions to generate metrics for with --custom-resource-autodiscovery, e.g. 'monitoring=enabled'. Defaults to all CustomResourceDefinitions.")
+	o.cmd.Flags().StringSliceVar(&o.CustomResourceProfiles, "custom-resource-profiles", nil, fmt.Sprintf("Comma-separated list of Custom Resource State Metrics profiles compiled into the binary to enable, generating curated metrics of common custom resources. Resources configured via --custom-resource-state-config take precedence. Available profiles: %s (experimental)", strings.Join(customresourcestate.Profiles(), ",")))
 	o.cmd.Flags().StringVar(&o.CustomResourceConfig, "custom-resource-state-config", "", "Inline Custom Resource State Metrics config YAML (experimental)")
 	o.cmd.Flags().StringVar(&o.CustomResourceConfigFile, "custom-resource-state-config-file", "", "Path to a Custom Resource State Metrics config file (experimental)")
 	o.cmd.Flags().StringVar(&o.Host, "host", "::", `Host to expose metrics on.`)
+	o.cmd.Flags().StringArrayVar(&o.ListenAddresses, "listen-address", nil, "Address to expose metrics on in the format host:port, e.g. '[::1]:8080' or '10.0.0.1:8080'. Can be repeated to listen on multiple addresses, e.g. on an IPv4 and an IPv6 address. Overrides --host and --port if set.")
+	o.cmd.Flags().StringArrayVar(&o.TelemetryListenAddresses, "telemetry-listen-address", nil, "Address to expose kube-state-metrics self metrics on in the format host:port. Can be repeated to listen on multiple addresses. Overrides --telemetry-host and --telemetry-port if set.")
 	o.cmd.Flags().StringVar(&o.Kubeconfig, "kubeconfig", "", "Absolute path to the kubeconfig file")
 	o.cmd.Flags().StringVar(&o.Namespace, "pod-namespace", "", "Name of the namespace of the pod specified by --pod. "+autoshardingNotice)
 	o.cmd.Flags().StringVar(&o.Pod, "pod", "", "Name of the pod that contains the kube-state-metrics container. "+autoshardingNotice)
+	o.cmd.Flags().StringVar(&o.OTLPEndpoint, "otlp-endpoint", "", "OTLP endpoint of an OpenTelemetry collector to periodically push all metrics to, e.g. 'http://otel-collector:4318' or 'http://otel-collector:4317' with --otlp-protocol=grpc. TLS is used for https endpoints. If the endpoint of the http/protobuf protocol has no path, '/v1/metrics' is used.")
+	o.cmd.Flags().StringVar(&o.OTLPProtocol, "otlp-protocol", otlp.ProtocolHTTPProtobuf, fmt.Sprintf("Protocol of --otlp-endpoint, one of %q.", []string{otlp.ProtocolGRPC, otlp.ProtocolHTTPProtobuf}))
+	o.cmd.Flags().StringVar(&o.OTLPCompression, "otlp-compression", otlp.CompressionGzip, fmt.Sprintf("Compression of the requests to --otlp-endpoint, one of %q.", []string{otlp.CompressionGzip, otlp.CompressionNone}))
+	o.cmd.Flags().IntVar(&o.OTLPBatchSize, "otlp-batch-size", 10000, "Maximum number of data points per request to --otlp-endpoint. The metrics of an export are split into as many requests as needed.")
+	o.cmd.Flags().StringVar(&o.OTLPTracesEndpoint, "otlp-traces-endpoint", "", "OTLP/HTTP endpoint of an OpenTelemetry collector to export traces of list and watch requests, store builds and scrapes to, e.g. 'http://otel-collector:4318'. If the endpoint has no path, '/v1/traces' is used. Tracing is disabled if empty.")
+	o.cmd.Flags().Float64Var(&o.OTLPTracesSampleRatio, "otlp-traces-sample-ratio", 1, "Ratio of traces exported to --otlp-traces-endpoint, between 0 and 1. Scrapes which are part of a trace propagated via the traceparent header keep its sampling decision.")
+	o.cmd.Flags().DurationVar(&o.LeaderElectLeaseDuration, "leader-elect-lease-duration", 15*time.Second, "Duration that standby replicas wait before trying to acquire the leadership of a leader that stopped renewing it.")
+	o.cmd.Flags().DurationVar(&o.LeaderElectRenewDeadline, "leader-elect-renew-deadline", 10*time.Second, "Duration that the leader retries renewing its leadership before giving it up.")
+	o.cmd.Flags().DurationVar(&o.LeaderElectRetryPeriod, "leader-elect-retry-period", 2*time.Second, "Duration that replicas wait between tries of acquiring or renewing the leadership.")
+	o.cmd.Flags().DurationVar(&o.OTLPInterval, "otlp-interval", 30*time.Second, "Interval in which metrics are pushed to the OTLP endpoint.")
+	o.cmd.Flags().DurationVar(&o.OTLPTimeout, "otlp-timeout", 10*time.Second, "Timeout of each request to the OTLP endpoint, including retries.")
+	o.cmd.Flags().DurationVar(&o.SnapshotMaxAge, "snapshot-max-age", 30*time.Minute, "Maximum age of the snapshot loaded from --snapshot-file. Older snapshots are not served, and a loaded snapshot stops being served once it exceeds the age even if not all stores are synced yet. Unlimited if 0.")
+	o.cmd.Flags().StringVar(&o.SnapshotFile, "snapshot-file", "", "Path to a file, e.g. on a persistent volume, the metrics of all stores are written to on shutdown. On startup, the snapshot is loaded from the file and served until all stores are synced, which avoids a gap in the metrics while the stores are populated after a restart.")
+	o.cmd.Flags().DurationVar(&o.ServerIdleTimeout, "server-idle-timeout", 2*time.Minute, "Maximum duration the metrics and telemetry servers keep idle keep-alive connections open. If 0, --server-read-timeout is used.")
+	o.cmd.Flags().DurationVar(&o.ServerReadHeaderTimeout, "server-read-header-timeout", 5*time.Second, "Maximum duration the metrics and telemetry servers wait for the headers of a request.")
+	o.cmd.Flags().DurationVar(&o.ServerReadTimeout, "server-read-timeout", 0, "Maximum duration the metrics and telemetry servers wait for a complete request. Unlimited if 0.")
+	o.cmd.Flags().DurationVar(&o.ServerWriteTimeout, "server-write-timeout", 0, "Maximum duration of writing a response of the metrics and telemetry servers, which should exceed the duration of writing all metrics. Unlimited if 0.")
+	o.cmd.Flags().IntVar(&o.ServerMaxHeaderBytes, "server-max-header-bytes", 1<<20, "Maximum size of the headers of requests to the metrics and telemetry servers.")
+	o.cmd.Flags().IntVar(&o.MaxConcurrentScrapes, "max-concurrent-scrapes", 0, "Maximum number of concurrently served scrapes of the metrics endpoint. Further scrapes are rejected with 503 Service Unavailable and counted by kube_state_metrics_scrapes_rejected_total. Unlimited if 0.")
+	o.cmd.Flags().StringVar(&o.TextfilePath, "textfile-path", "", "Path of a file all metrics are periodically written to in the text exposition format, e.g. for the textfile collector of the node_exporter. The file is replaced atomically. Disabled if empty.")
+	o.cmd.Flags().DurationVar(&o.TextfileInterval, "textfile-interval", 30*time.Second, "Interval in which metrics are written to --textfile-path.")
+	o.cmd.Flags().BoolVar(&o.TextfileOnly, "textfile-only", false, "Only write metrics to --textfile-path and do not start the metrics server.")
+	o.cmd.Flags().DurationVar(&o.StaleObjectGCInterval, "stale-object-gc-interval", 0, "Interval of listing the metadata of the objects of each resource to prune the metrics of objects which no longer exist, e.g. as their delete watch event was missed. Objects are pruned once they are missing in two consecutive lists. Pruned objects are counted by the kube_state_metrics_stale_objects_pruned_total metric. Disabled if 0.")
+	o.cmd.Flags().DurationVar(&o.StaleThreshold, "stale-threshold", 0, "Duration after which the metrics of a resource are marked as stale by the kube_state_metrics_stale metric if listing or watching it keeps failing, e.g. as the apiserver is unreachable. The cached metrics are still served. Disabled if 0.")
+	o.cmd.Flags().DurationVar(&o.ReadyTimeout, "ready-timeout", 0, "Duration after startup after which /readyz reports ready even if not all stores were populated by their initial list yet. /readyz waits for all stores if 0.")
+	o.cmd.Flags().DurationVar(&o.ScrapeCacheTTL, "scrape-cache-ttl", 0, "Duration for which a rendered /metrics response is served to subsequent scrapes with the same format and encoding. This avoids rendering all metrics for each of multiple scrapers. Disabled if 0.")
+	o.cmd.Flags().IntVar(&o.ScrapeWorkers, "scrape-workers", 1, "Number of resources whose metrics are encoded concurrently for each scrape. The encoded metrics are streamed to the response in the order of the resources, buffering the metrics of at most this number of resources. Metrics are written directly to the response if 0 or 1.")
+	o.cmd.Flags().BoolVar(&o.SortMetrics, "sort-metrics", false, "Sort the metric families by name and their series by labels when writing metrics, so that the same metrics are always written as the same bytes, e.g. for golden file tests and diffing responses. The whole response is buffered before it is written.")
+	o.cmd.Flags().DurationVar(&o.ShardingLeaseDuration, "sharding-lease-duration", 15*time.Second, "Duration after which the Lease of a member of the --sharding-lease-group expires if it is not renewed. Leases are renewed every third of the duration.")
+	o.cmd.Flags().StringVar(&o.RelabelConfigFile, "relabel-config-file", "", "Path to a YAML file with rules renaming, relabeling and dropping metric families when they are exposed, e.g. to adapt them to internal naming conventions.")
+	o.cmd.Flags().StringVar(&o.RecordingRulesConfigFile, "recording-rules-config-file", "", "Path to a YAML file with recording rules aggregating the series of metric families by labels into additional metric families when they are exposed, e.g. the number of pods per namespace and phase.")
 	o.cmd.Flags().StringVar(&o.TLSConfig, "tls-config", "", "Path to the TLS configuration file")
+	o.cmd.Flags().StringVar(&o.TLSCertFile, "tls-cert-file", "", "Path to a PEM encoded certificate to serve the metrics and telemetry servers with via HTTPS. The certificate and private key are reloaded once the files change. Requires --tls-private-key-file, can not be used together with --tls-config.")
+	o.cmd.Flags().StringVar(&o.TLSClientCAFile, "tls-client-ca-file", "", "Path to a PEM encoded CA bundle. If set, clients of the metrics server have to present a certificate signed by one of the CAs. Requires --tls-cert-file.")
+	o.cmd.Flags().StringSliceVar(&o.TLSClientAllowedNames, "tls-client-allowed-names", nil, "Comma-separated list of names allowed to scrape the metrics server, matched against the common name and the DNS, email and URI SANs of the client certificate. All clients with a valid certificate are allowed if empty. Requires --tls-client-ca-file.")
+	o.cmd.Flags().StringVar(&o.TLSPrivateKeyFile, "tls-private-key-file", "", "Path to the PEM encoded private key of --tls-cert-file.")
 	o.cmd.Flags().StringVar(&o.TelemetryHost, "telemetry-host", "::", `Host to expose kube-state-metrics self metrics on.`)
-	o.cmd.Flags().StringVar(&o.Config, "config", "", "Path to the kube-state-metrics options config file")
+	o.cmd.Flags().StringVar(&o.Config, "config", "", "Path to the kube-state-metrics options config file. It is reloaded on changes and on SIGHUP.")
 	o.cmd.Flags().StringVar((*string)(&o.Node), "node", "", "Name of the node that contains the kube-state-metrics pod. Most likely it should be passed via the downward API. This is used for daemonset sharding. Only available for resources (pod metrics) that support spec.nodeName fieldSelector. This is experimental.")
-	o.cmd.Flags().Var(&o.AnnotationsAllowList, "metric-annotations-allowlist", "Comma-separated list of Kubernetes annotations keys that will be used in the resource' labels metric. By default the metric contains only name and namespace labels. To include additional annotations provide a list of resource names in their plural form and Kubernetes annotation keys you would like to allow for them (Example: '=namespaces=[kubernetes.io/team,...],pods=[kubernetes.io/team],...)'. A single '*' can be provided per resource instead to allow any annotations, but that has severe performance implications (Example: '=pods=[*]').")
-	o.cmd.Flags().Var(&o.LabelsAllowList, "metric-labels-allowlist", "Comma-separated list of additional Kubernetes label keys that will be used in the resource' labels metric. By default the metric contains only name and namespace labels. To include additional labels provide a list of resource names in their plural form and Kubernetes label keys you would like to allow for them (Example: '=namespaces=[k8s-label-1,k8s-label-n,...],pods=[app],...)'. A single '*' can be provided per resource instead to allow any labels, but that has severe performance implications (Example: '=pods=[*]'). Additionally, an asterisk (*) can be provided as a key, which will resolve to all resources, i.e., assuming '--resources=deployments,pods', '=*=[*]' will resolve to '=deployments=[*],pods=[*]'.")
-	o.cmd.Flags().Var(&o.MetricAllowlist, "metric-allowlist", "Comma-separated list of metrics to be exposed. This list comprises of exact metric names and/or regex patterns. The allowlist and denylist are mutually exclusive.")
-	o.cmd.Flags().Var(&o.MetricDenylist, "metric-denylist", "Comma-separated list of metrics not to be enabled. This list comprises of exact metric names and/or regex patterns. The allowlist and denylist are mutually exclusive.")
-	o.cmd.Flags().Var(&o.MetricOptInList, "metric-opt-in-list", "Comma-separated list of metrics which are opt-in and not enabled by default. This is in addition to the metric allow- and denylists")
+	o.cmd.Flags().Var(&o.AnnotationsAllowList, "metric-annotations-allowlist", "Comma-separated list of Kubernetes annotations keys that will be used in the resource' labels metric. By default the metric contains only name and namespace labels. To include additional annotations provide a list of resource names in their plural form and Kubernetes annotation keys you would like to allow for them (Example: '=namespaces=[kubernetes.io/team,...],pods=[kubernetes.io/team],...)'. A single '*' can be provided per resource instead to allow any annotations, but that has severe performance implications (Example: '=pods=[*]'). Keys containing '*' are patterns, in which each '*' matches any sequence of characters (Example: '=pods=[prometheus.io/*]').")
+	o.cmd.Flags().Var(cliflag.NewMapStringBool(&o.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for experimental features. Options are:\n"+strings.Join(features.KnownFeatures(), "\n"))
+	o.cmd.Flags().StringToStringVar(&o.ExternalLabels, "external-labels", nil, "Comma-separated list of labels and their values to be appended to all exposed series, e.g. 'cluster=prod-eu,region=eu-west-1'. Labels of a series take precedence over external labels of the same name.")
+	o.cmd.Flags().Var(&o.ResourceListeners, "resource-listeners", "Additional listeners of the metrics server exposing only the metrics of the given resources, which are then no longer exposed by the main listener, in the format address=[resource1,resource2,resourceN...],addressN=[...], e.g. ':8082=[pods,nodes]'.")
+	o.cmd.Flags().Var(&o.FieldSelectors, "field-selectors", "Comma-separated list of resources and the field selectors of the objects to be watched for them, e.g. 'pods=[spec.nodeName!=,status.phase!=Succeeded],jobs=[status.successful=0]'. Multiple selectors of a resource are ANDed. Only fields supported as field selectors by the API server for the respective resource can be used.")
+	o.cmd.Flags().IntVar(&o.MaxLabelValueLength, "max-label-value-length", 0, "Maximum length in bytes of label values sourced from Kubernetes labels, annotations and custom resource fields. Longer values are truncated and suffixed with a '~' and the hash of the whole value, and counted by the kube_state_metrics_label_values_truncated_total metric. Label values are not truncated if 0.")
+	o.cmd.Flags().Var(&o.MaxObjects, "max-objects", "Comma-separated list of resources and the maximum number of their objects metrics are generated for, e.g. 'pods=50000,jobs=10000'. Objects beyond the limit are counted by the kube_state_metrics_objects_truncated metric instead. The limit applies to all namespaces of a resource together.")
+	o.cmd.Flags().Var(&o.ExcludeCompleted, "exclude-completed", "Comma-separated list of resources and the duration after which their objects which reached a terminal state are excluded, e.g. 'pods=30m,jobs=0s'. Succeeded and Failed pods are excluded once their last container terminated, Jobs once they completed or failed. Supported resources: jobs, pods.")
+	o.cmd.Flags().Var(&o.TopK, "top-k", "Comma-separated list of resources or metric families and the number of most recently updated objects whose series are exposed, e.g. 'jobs=500,kube_pod_status_reason=1000'. A limit of a resource applies to all its metric families, a limit of a metric family takes precedence. The series of the other objects are omitted when metrics are written and their number is exposed by the kube_state_metrics_objects_omitted gauge. Objects are updated when they are created or deleted, or their managed fields change.")
+	o.cmd.Flags().Var(&o.LabelsAllowList, "metric-labels-allowlist", "Comma-separated list of additional Kubernetes label keys that will be used in the resource' labels metric. By default the metric contains only name and namespace labels. To include additional labels provide a list of resource names in their plural form and Kubernetes label keys you would like to allow for them (Example: '=namespaces=[k8s-label-1,k8s-label-n,...],pods=[app],...)'. A single '*' can be provided per resource instead to allow any labels, but that has severe performance implications (Example: '=pods=[*]'). Keys containing '*' are patterns, in which each '*' matches any sequence of characters (Example: '=pods=[app.kubernetes.io/*]'). Additionally, an asterisk (*) can be provided as a key, which will resolve to all resources, i.e., assuming '--resources=deployments,pods', '=*=[*]' will resolve to '=deployments=[*],pods=[*]'.")
+	o.cmd.Flags().BoolVar(&o.LabelsRecommended, "metric-labels-recommended", false, "Add the recommended Kubernetes labels app.kubernetes.io/name, app.kubernetes.io/instance, app.kubernetes.io/version, app.kubernetes.io/component, app.kubernetes.io/part-of and app.kubernetes.io/managed-by to the labels allowed by --metric-labels-allowlist for all resources.")
+	o.cmd.Flags().Var(&o.LabelsDenyList, "metric-labels-denylist", "Comma-separated list of metric families and the labels to be dropped from their metrics, e.g. to reduce cardinality (Example: '=kube_pod_container_info=[container_id,image_id],*=[uid]'). Labels listed for '*' are dropped from all metric families. Dropping labels which identify a series results in duplicate series.")
+	o.cmd.Flags().Var(&o.MetricAllowlist, "metric-allowlist", "Comma-separated list of metrics to be exposed. This list comprises of exact metric names and/or regex patterns matching whole metric names. The allowlist and denylist are mutually exclusive.")
+	o.cmd.Flags().Var(&o.MetricDenylist, "metric-denylist", "Comma-separated list of metrics not to be enabled. This list comprises of exact metric names and/or regex patterns matching whole metric names. The allowlist and denylist are mutually exclusive.")
+	o.cmd.Flags().Var(&o.MetricOptInList, "metric-opt-in-list", "Comma-separated list of metrics which are opt-in and not enabled by default. This list comprises of exact metric names and/or regex patterns matching whole metric names, e.g. kube_pod_container_.*. This is in addition to the metric allow- and denylists")
+	o.cmd.Flags().Var(cliflag.NewMapStringString(&o.MetricPrefixes), "metric-prefixes", "Comma-separated list of resources and the prefix replacing the kube_ prefix of the names of their metric families, e.g. 'deployments=k8s_' exposes kube_deployment_created as k8s_deployment_created. Metric allow-, deny- and opt-in lists as well as the labels denylist refer to the original names.")
 	o.cmd.Flags().Var(&o.Namespaces, "namespaces", fmt.Sprintf("Comma-separated list of namespaces to be enabled. Defaults to %q", &DefaultNamespaces))
 	o.cmd.Flags().Var(&o.NamespacesDenylist, "namespaces-denylist", "Comma-separated list of namespaces not to be enabled. If namespaces and namespaces-denylist are both set, only namespaces that are excluded in namespaces-denylist will be used.")
+	o.cmd.Flags().StringVar(&o.NamespacesSelector, "namespaces-selector", "", "Label selector of the namespaces to be enabled, e.g. team=payments. The matching namespaces are tracked and stores are added or removed as namespaces change. Can not be used together with namespaces.")
+	o.cmd.Flags().Var(&o.AggregatedResources, "aggregated-resources", "Comma-separated list of resources whose metrics are exposed as sums by the given labels instead of per object, e.g. pods=[namespace,phase]. All other labels are dropped and the values of the series with the same values of the given labels are summed, e.g. counting the pods per namespace and phase for kube_pod_status_phase.")
+	o.cmd.Flags().Var(&o.ObjectCountResources, "object-count-resources", "Comma-separated list of resources whose objects are counted per namespace by the kube_objectcount metric, e.g. pods,deployments.apps,certificates.cert-manager.io, or * for all resources which can be listed and watched. Only the metadata of the objects is watched, so the objects are counted even if the collectors of the resources are disabled. The resources are discovered on startup.")
+	o.cmd.Flags().Var(&o.LazyResources, "lazy-resources", "Comma-separated list of resources whose metrics are generated on each scrape from the cached objects instead of whenever an object changes. This reduces the memory and CPU usage between scrapes if objects are larger than their metrics or change frequently, at the cost of the CPU usage of each scrape.")
 	o.cmd.Flags().Var(&o.Resources, "resources", fmt.Sprintf("Comma-separated list of Resources to be enabled. Defaults to %q", &DefaultResources))
 }
 
@@ -158,8 +355,212 @@ func (o *Options) Usage() {
 	_ = o.cmd.Flags().FlagUsages()
 }
 
+// EnabledCustomResourceProfiles returns the Custom Resource State Metrics
+// profiles enabled via --custom-resource-profiles and --enable-vpa-metrics.
+func (o *Options) EnabledCustomResourceProfiles() []string {
+	profiles := append([]string{}, o.CustomResourceProfiles...)
+	if o.EnableVPAMetrics && !containsString(profiles, customresourcestate.ProfileVerticalPodAutoscaler) {
+		profiles = append(profiles, customresourcestate.ProfileVerticalPodAutoscaler)
+	}
+	return profiles
+}
+
+// UTF8LabelNamesEnabled returns whether UTF-8 label names are exposed, via
+// --utf8-label-names or the UTF8LabelNames feature gate.
+func (o *Options) UTF8LabelNamesEnabled() bool {
+	return o.UTF8LabelNames || o.featureEnabled(features.UTF8LabelNames)
+}
+
+// PodWorkloadResolutionEnabled returns whether the workloads of pods are
+// resolved, via --resolve-pod-workloads or the PodWorkloadResolution feature
+// gate.
+func (o *Options) PodWorkloadResolutionEnabled() bool {
+	return o.ResolvePodWorkloads || o.featureEnabled(features.PodWorkloadResolution)
+}
+
+// featureEnabled returns whether the feature is enabled via --feature-gates.
+// Invalid feature gates are rejected by Validate.
+func (o *Options) featureEnabled(f featuregate.Feature) bool {
+	g, err := features.New(o.FeatureGates)
+	return err == nil && g.Enabled(f)
+}
+
 // Validate validates arguments
 func (o *Options) Validate() error {
+	if o.OTLPOnly && o.OTLPEndpoint == "" {
+		return fmt.Errorf("--otlp-only requires --otlp-endpoint to be set")
+	}
+	if o.TextfileOnly && o.TextfilePath == "" {
+		return fmt.Errorf("--textfile-only requires --textfile-path to be set")
+	}
+	if o.MaxLabelValueLength != 0 && o.MaxLabelValueLength < metric.MinLabelValueLength {
+		return fmt.Errorf("--max-label-value-length must be 0 or at least %d", metric.MinLabelValueLength)
+	}
+	if o.OTLPEndpoint != "" && o.OTLPProtocol != otlp.ProtocolGRPC && o.OTLPProtocol != otlp.ProtocolHTTPProtobuf {
+		return fmt.Errorf("--otlp-protocol must be %s or %s", otlp.ProtocolGRPC, otlp.ProtocolHTTPProtobuf)
+	}
+	if o.OTLPEndpoint != "" && o.OTLPCompression != otlp.CompressionGzip && o.OTLPCompression != otlp.CompressionNone {
+		return fmt.Errorf("--otlp-compression must be %s or %s", otlp.CompressionGzip, otlp.CompressionNone)
+	}
+	if o.OTLPEndpoint != "" && (o.OTLPBatchSize <= 0 || o.OTLPTimeout <= 0) {
+		return fmt.Errorf("--otlp-batch-size and --otlp-timeout must be greater than 0")
+	}
+	if o.OTLPTracesSampleRatio < 0 || o.OTLPTracesSampleRatio > 1 {
+		return fmt.Errorf("--otlp-traces-sample-ratio must be between 0 and 1")
+	}
+	if !o.AuthDelegation && (o.AuthDelegationResource != "" || o.AuthDelegationNamespace != "") {
+		return fmt.Errorf("--auth-delegation-resource and --auth-delegation-namespace require --auth-delegation to be set")
+	}
+	if (o.TLSCertFile == "") != (o.TLSPrivateKeyFile == "") {
+		return fmt.Errorf("--tls-cert-file and --tls-private-key-file must be set together")
+	}
+	if o.TLSCertFile != "" && o.TLSConfig != "" {
+		return fmt.Errorf("--tls-cert-file can not be used together with --tls-config")
+	}
+	if o.TLSClientCAFile != "" && o.TLSCertFile == "" {
+		return fmt.Errorf("--tls-client-ca-file requires --tls-cert-file to be set")
+	}
+	if len(o.TLSClientAllowedNames) > 0 && o.TLSClientCAFile == "" {
+		return fmt.Errorf("--tls-client-allowed-names requires --tls-client-ca-file to be set")
+	}
+	for _, address := range o.ListenAddresses {
+		if err := validateListenAddress(address); err != nil {
+			return fmt.Errorf("invalid --listen-address %q: %w", address, err)
+		}
+	}
+	for _, address := range o.TelemetryListenAddresses {
+		if err := validateListenAddress(address); err != nil {
+			return fmt.Errorf("invalid --telemetry-listen-address %q: %w", address, err)
+		}
+	}
+	for name := range o.ExternalLabels {
+		if !model.LabelName(name).IsValidLegacy() {
+			return fmt.Errorf("invalid --external-labels label name %q", name)
+		}
+	}
+	if o.ServerIdleTimeout < 0 || o.ServerReadHeaderTimeout < 0 || o.ServerReadTimeout < 0 || o.ServerWriteTimeout < 0 {
+		return fmt.Errorf("--server-*-timeout flags must not be negative")
+	}
+	if o.ServerMaxHeaderBytes < 0 {
+		return fmt.Errorf("--server-max-header-bytes must not be negative")
+	}
+	listened := map[string]string{}
+	for address, resources := range o.ResourceListeners {
+		for _, r := range resources {
+			if other, ok := listened[r]; ok {
+				return fmt.Errorf("--resource-listeners: resource %s is exposed by both %s and %s", r, other, address)
+			}
+			listened[r] = address
+		}
+	}
+	available := customresourcestate.Profiles()
+	for _, profile := range o.CustomResourceProfiles {
+		if !containsString(available, profile) {
+			return fmt.Errorf("unknown --custom-resource-profiles profile %q, available profiles: %s", profile, strings.Join(available, ","))
+		}
+	}
+	if _, ok := o.Resources["verticalpodautoscalers"]; ok && containsString(o.EnabledCustomResourceProfiles(), customresourcestate.ProfileVerticalPodAutoscaler) {
+		return fmt.Errorf("the %s profile replaces the verticalpodautoscalers resource and can not be used together with it", customresourcestate.ProfileVerticalPodAutoscaler)
+	}
+	if o.MaxConcurrentScrapes < 0 {
+		return fmt.Errorf("--max-concurrent-scrapes must not be negative")
+	}
+	if len(o.MetricAllowlist) != 0 && len(o.MetricDenylist) != 0 {
+		return fmt.Errorf("--metric-allowlist and --metric-denylist are mutually exclusive")
+	}
+	for resource, prefix := range o.MetricPrefixes {
+		if !model.IsValidLegacyMetricName(prefix) {
+			return fmt.Errorf("invalid --metric-prefixes prefix %q of resource %s", prefix, resource)
+		}
+	}
+	for flag, list := range map[string]MetricSet{
+		"--metric-allowlist":   o.MetricAllowlist,
+		"--metric-denylist":    o.MetricDenylist,
+		"--metric-opt-in-list": o.MetricOptInList,
+	} {
+		for pattern := range list {
+			if _, err := allowdenylist.CompilePattern(pattern); err != nil {
+				return fmt.Errorf("invalid %s pattern %q: %w", flag, pattern, err)
+			}
+		}
+	}
+	if _, err := features.New(o.FeatureGates); err != nil {
+		return fmt.Errorf("invalid --feature-gates: %w", err)
+	}
+	if o.StaleObjectGCInterval < 0 {
+		return fmt.Errorf("--stale-object-gc-interval must not be negative")
+	}
+	if o.StaleThreshold < 0 {
+		return fmt.Errorf("--stale-threshold must not be negative")
+	}
+	if o.SnapshotMaxAge < 0 {
+		return fmt.Errorf("--snapshot-max-age must not be negative")
+	}
+	for resource, limit := range o.MaxObjects {
+		if limit <= 0 {
+			return fmt.Errorf("--max-objects of resource %s must be positive", resource)
+		}
+	}
+	if o.ReadyTimeout < 0 {
+		return fmt.Errorf("--ready-timeout must not be negative")
+	}
+	if o.ScrapeCacheTTL < 0 {
+		return fmt.Errorf("--scrape-cache-ttl must not be negative")
+	}
+	if o.ListPageSize < 0 {
+		return fmt.Errorf("--list-page-size must not be negative")
+	}
+	if o.StartupConcurrency < 0 {
+		return fmt.Errorf("--startup-concurrency must not be negative")
+	}
+	if o.StartupJitter < 0 {
+		return fmt.Errorf("--startup-jitter must not be negative")
+	}
+	if o.WatchBackoffInitial < 0 {
+		return fmt.Errorf("--watch-backoff-initial must not be negative")
+	}
+	if o.WatchBackoffMax < 0 {
+		return fmt.Errorf("--watch-backoff-max must not be negative")
+	}
+	if o.WatchBackoffInitial > 0 && o.WatchBackoffMax > 0 && o.WatchBackoffMax < o.WatchBackoffInitial {
+		return fmt.Errorf("--watch-backoff-max must not be less than --watch-backoff-initial")
+	}
+	if o.WatchBackoffReset < 0 {
+		return fmt.Errorf("--watch-backoff-reset must not be negative")
+	}
+	if o.CustomResourceMappingRefresh < 0 {
+		return fmt.Errorf("--custom-resource-mapping-refresh must not be negative")
+	}
+	if o.ScrapeWorkers < 0 {
+		return fmt.Errorf("--scrape-workers must not be negative")
+	}
+	if o.NamespacesSelector != "" {
+		if len(o.Namespaces) > 0 && !o.Namespaces.IsAllNamespaces() {
+			return fmt.Errorf("--namespaces-selector can not be used together with --namespaces")
+		}
+		if _, err := labels.Parse(o.NamespacesSelector); err != nil {
+			return fmt.Errorf("invalid --namespaces-selector: %w", err)
+		}
+	}
+	if o.ShardingStrategy != "" && o.ShardingStrategy != sharding.StrategyUID && o.ShardingStrategy != sharding.StrategyNamespace {
+		return fmt.Errorf("invalid --sharding-strategy %q, must be one of %q", o.ShardingStrategy, sharding.Strategies)
+	}
+	if o.LeaderElect {
+		if o.LeaderElectNamespace == "" && o.Namespace == "" {
+			return fmt.Errorf("--leader-elect requires --leader-elect-namespace or --pod-namespace to be set")
+		}
+		if o.LeaderElectLeaseDuration <= o.LeaderElectRenewDeadline {
+			return fmt.Errorf("--leader-elect-lease-duration must be greater than --leader-elect-renew-deadline")
+		}
+	}
+	if o.ShardingLeaseGroup != "" {
+		if o.Pod == "" || o.Namespace == "" {
+			return fmt.Errorf("--sharding-lease-group requires --pod and --pod-namespace to be set")
+		}
+		if o.ShardingLeaseDuration < 3*time.Second {
+			return fmt.Errorf("--sharding-lease-duration must be at least 3s")
+		}
+	}
 	shardableResource := "pods"
 	if o.Node == "" {
 		return nil
@@ -171,3 +572,25 @@ func (o *Options) Validate() error {
 	}
 	return nil
 }
+
+// validateListenAddress returns an error if address is not in the format
+// host:port.
+func validateListenAddress(address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -63,3 +63,35 @@ func TestOptionsParse(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionsClone(t *testing.T) {
+	opts := NewOptions()
+	opts.Resources = ResourceSet{"pods": {}}
+	opts.LabelsAllowList = LabelsAllowList{"pods": {"app"}}
+
+	c := opts.Clone()
+	c.Resources["nodes"] = struct{}{}
+	c.LabelsAllowList["nodes"] = []string{"zone"}
+	c.Port = 8082
+
+	if len(opts.Resources) != 1 || len(opts.LabelsAllowList) != 1 || opts.Port != 0 {
+		t.Errorf("expected modifying the clone not to modify the options, got %+v", opts)
+	}
+}
+
+func TestOptionsFeatureGates(t *testing.T) {
+	opts := NewOptions()
+	if opts.UTF8LabelNamesEnabled() || opts.PodWorkloadResolutionEnabled() {
+		t.Error("expected features to be disabled by default")
+	}
+
+	opts.UTF8LabelNames = true
+	if !opts.UTF8LabelNamesEnabled() {
+		t.Error("expected UTF-8 label names to be enabled by --utf8-label-names")
+	}
+
+	opts.FeatureGates = map[string]bool{"PodWorkloadResolution": true}
+	if !opts.PodWorkloadResolutionEnabled() {
+		t.Error("expected pod workload resolution to be enabled by its feature gate")
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// RBACGenOptions are the options of the rbac-gen subcommand.
+type RBACGenOptions struct {
+	ServiceAccount          string
+	ServiceAccountNamespace string
+}
+
+// NewRBACGenCommand returns the rbac-gen subcommand, which writes the RBAC
+// manifests required with the options by calling run with its own options.
+// It accepts the flags of the root command and must be called after AddFlags.
+func NewRBACGenCommand(o *Options, run func(rbacGenOpts *RBACGenOptions)) *cobra.Command {
+	rbacGenOpts := &RBACGenOptions{}
+	cmd := &cobra.Command{
+		Use:   "rbac-gen",
+		Short: "Generate the ClusterRole and Roles kube-state-metrics requires with the given flags.",
+		Long:  "Generate the ClusterRole, Roles and their bindings to the service account of kube-state-metrics, which are required with the given flags. Namespaced resources are granted per namespace if --namespaces is set.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			run(rbacGenOpts)
+		},
+	}
+	cmd.Flags().AddFlagSet(o.cmd.Flags())
+	cmd.Flags().StringVar(&rbacGenOpts.ServiceAccount, "service-account", "kube-state-metrics", "Name of the ServiceAccount of kube-state-metrics, which the roles and bindings are named after.")
+	cmd.Flags().StringVar(&rbacGenOpts.ServiceAccountNamespace, "service-account-namespace", "kube-system", "Namespace of the ServiceAccount of kube-state-metrics.")
+	return cmd
+}
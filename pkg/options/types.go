@@ -18,9 +18,13 @@ package options
 
 import (
 	"errors"
+	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/fields"
 
 	"k8s.io/klog/v2"
@@ -30,6 +34,14 @@ import (
 
 var errLabelsAllowListFormat = errors.New("invalid format, metric=[label1,label2,labeln...],metricN=[]")
 
+var errFieldSelectorsFormat = errors.New("invalid format, resource=[field-selector],resourceN=[field-selector]")
+
+var errResourceListenersFormat = errors.New("invalid format, address=[resource1,resource2,resourceN...],addressN=[]")
+
+var errMaxObjectsFormat = errors.New("invalid format, resource=limit,resourceN=limitN")
+
+var errExcludeCompletedFormat = errors.New("invalid format, resource=duration,resourceN=durationN")
+
 // MetricSet represents a collection which has a unique set of metrics.
 type MetricSet map[string]struct{}
 
@@ -67,6 +79,17 @@ func (ms *MetricSet) Type() string {
 	return "string"
 }
 
+// UnmarshalYAML decodes a list, a comma-separated string or the keys of a
+// mapping of metrics into the MetricSet.
+func (ms *MetricSet) UnmarshalYAML(value *yaml.Node) error {
+	set, err := unmarshalSetYAML(value)
+	if err != nil {
+		return err
+	}
+	*ms = set
+	return nil
+}
+
 // ResourceSet represents a collection which has a unique set of resources.
 type ResourceSet map[string]struct{}
 
@@ -104,6 +127,52 @@ func (r *ResourceSet) Type() string {
 	return "string"
 }
 
+// UnmarshalYAML decodes a list, a comma-separated string or the keys of a
+// mapping of resources into the ResourceSet.
+func (r *ResourceSet) UnmarshalYAML(value *yaml.Node) error {
+	set, err := unmarshalSetYAML(value)
+	if err != nil {
+		return err
+	}
+	*r = set
+	return nil
+}
+
+// unmarshalSetYAML decodes a list, a comma-separated string or the keys of a
+// mapping into a set.
+func unmarshalSetYAML(value *yaml.Node) (map[string]struct{}, error) {
+	var values []string
+	switch value.Kind {
+	case yaml.SequenceNode:
+		if err := value.Decode(&values); err != nil {
+			return nil, err
+		}
+	case yaml.ScalarNode:
+		var s string
+		if err := value.Decode(&s); err != nil {
+			return nil, err
+		}
+		values = strings.Split(s, ",")
+	case yaml.MappingNode:
+		m := map[string]interface{}{}
+		if err := value.Decode(&m); err != nil {
+			return nil, err
+		}
+		for k := range m {
+			values = append(values, k)
+		}
+	default:
+		return nil, fmt.Errorf("line %d: expected a list of strings", value.Line)
+	}
+	set := map[string]struct{}{}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			set[v] = struct{}{}
+		}
+	}
+	return set, nil
+}
+
 // NodeType represents a nodeName to query from.
 type NodeType string
 
@@ -304,3 +373,204 @@ func (l *LabelsAllowList) String() string {
 func (l *LabelsAllowList) Type() string {
 	return "string"
 }
+
+// FieldSelectors represents field selectors of the objects to be watched per resource.
+type FieldSelectors map[string]string
+
+// Set converts a comma-separated string of resources and their field selectors and sets the FieldSelectors.
+// Value is in the following format:
+// resource=[field-selector],another-resource=[field-selector]
+// Example: pods=[spec.nodeName!=,status.phase!=Succeeded],jobs=[status.successful=0]
+func (f *FieldSelectors) Set(value string) error {
+	m := make(map[string]string, len(*f))
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		i := strings.Index(rest, "=[")
+		if i <= 0 || strings.Contains(rest[:i], ",") {
+			return errFieldSelectorsFormat
+		}
+		name := strings.TrimSpace(rest[:i])
+		rest = rest[i+2:]
+		j := strings.Index(rest, "]")
+		if j < 0 {
+			return errFieldSelectorsFormat
+		}
+		selector, err := fields.ParseSelector(rest[:j])
+		if err != nil {
+			return fmt.Errorf("invalid field selector for resource %s: %w", name, err)
+		}
+		m[name] = selector.String()
+		rest = rest[j+1:]
+		if rest == "" {
+			break
+		}
+		if rest[0] != ',' || len(rest) == 1 {
+			return errFieldSelectorsFormat
+		}
+		rest = rest[1:]
+	}
+	*f = m
+	return nil
+}
+
+func (f *FieldSelectors) String() string {
+	s := make([]string, 0, len(*f))
+	for resource, selector := range *f {
+		s = append(s, fmt.Sprintf("%s=[%s]", resource, selector))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// Type returns a descriptive string about the FieldSelectors type.
+func (f *FieldSelectors) Type() string {
+	return "string"
+}
+
+// ResourceListeners represents the resources exposed by additional listeners
+// per listen address.
+type ResourceListeners map[string][]string
+
+// Set converts a comma-separated string of listen addresses and their
+// resources and sets the ResourceListeners.
+// Value is in the following format:
+// address=[resource1,resource2,resourceN...],addressN=[...]
+// Example: :8082=[pods,nodes],:8083=[configmaps]
+func (l *ResourceListeners) Set(value string) error {
+	m := make(map[string][]string, len(*l))
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		i := strings.Index(rest, "=[")
+		if i <= 0 || strings.Contains(rest[:i], ",") {
+			return errResourceListenersFormat
+		}
+		address := strings.TrimSpace(rest[:i])
+		rest = rest[i+2:]
+		j := strings.Index(rest, "]")
+		if j <= 0 {
+			return errResourceListenersFormat
+		}
+		var resources []string
+		for _, r := range strings.Split(rest[:j], ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				resources = append(resources, r)
+			}
+		}
+		if len(resources) == 0 {
+			return errResourceListenersFormat
+		}
+		m[address] = resources
+		rest = rest[j+1:]
+		if rest == "" {
+			break
+		}
+		if rest[0] != ',' || len(rest) == 1 {
+			return errResourceListenersFormat
+		}
+		rest = rest[1:]
+	}
+	*l = m
+	return nil
+}
+
+func (l *ResourceListeners) String() string {
+	s := make([]string, 0, len(*l))
+	for address, resources := range *l {
+		s = append(s, fmt.Sprintf("%s=[%s]", address, strings.Join(resources, ",")))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// Type returns a descriptive string about the ResourceListeners type.
+func (l *ResourceListeners) Type() string {
+	return "string"
+}
+
+// MaxObjects represents the maximum number of objects with metrics per
+// resource.
+type MaxObjects map[string]int
+
+// Set converts a comma-separated string of resources and their limits and sets
+// the MaxObjects.
+// Value is in the following format:
+// resource=limit,another-resource=limit
+// Example: pods=50000,jobs=10000
+func (m *MaxObjects) Set(value string) error {
+	limits := make(map[string]int, len(*m))
+	for _, l := range strings.Split(value, ",") {
+		if l = strings.TrimSpace(l); l == "" {
+			continue
+		}
+		resource, limit, ok := strings.Cut(l, "=")
+		resource = strings.TrimSpace(resource)
+		if !ok || resource == "" {
+			return errMaxObjectsFormat
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid limit of resource %s, expected a positive number: %q", resource, limit)
+		}
+		limits[resource] = n
+	}
+	*m = limits
+	return nil
+}
+
+func (m *MaxObjects) String() string {
+	s := make([]string, 0, len(*m))
+	for resource, limit := range *m {
+		s = append(s, fmt.Sprintf("%s=%d", resource, limit))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// Type returns a descriptive string about the MaxObjects type.
+func (m *MaxObjects) Type() string {
+	return "string"
+}
+
+// ExcludeCompleted represents the duration after which objects which reached
+// a terminal state are excluded per resource.
+type ExcludeCompleted map[string]time.Duration
+
+// Set converts a comma-separated string of resources and durations and sets
+// the ExcludeCompleted.
+// Value is in the following format:
+// resource=duration,another-resource=duration
+// Example: pods=30m,jobs=0s
+func (e *ExcludeCompleted) Set(value string) error {
+	durations := make(map[string]time.Duration, len(*e))
+	for _, d := range strings.Split(value, ",") {
+		if d = strings.TrimSpace(d); d == "" {
+			continue
+		}
+		resource, duration, ok := strings.Cut(d, "=")
+		resource = strings.TrimSpace(resource)
+		if !ok || resource == "" {
+			return errExcludeCompletedFormat
+		}
+		after, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil || after < 0 {
+			return fmt.Errorf("invalid duration of resource %s, expected a non-negative duration: %q", resource, duration)
+		}
+		durations[resource] = after
+	}
+	*e = durations
+	return nil
+}
+
+func (e *ExcludeCompleted) String() string {
+	s := make([]string, 0, len(*e))
+	for resource, after := range *e {
+		s = append(s, fmt.Sprintf("%s=%s", resource, after))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// Type returns a descriptive string about the ExcludeCompleted type.
+func (e *ExcludeCompleted) Type() string {
+	return "string"
+}
@@ -19,6 +19,9 @@ package options
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestResourceSetSet(t *testing.T) {
@@ -392,3 +395,238 @@ func TestLabelsAllowListSet(t *testing.T) {
 		}
 	}
 }
+
+func TestFieldSelectorsSet(t *testing.T) {
+	tests := []struct {
+		Desc   string
+		Value  string
+		Wanted FieldSelectors
+		err    bool
+	}{
+		{
+			Desc:   "empty field selectors",
+			Value:  "",
+			Wanted: FieldSelectors{},
+		},
+		{
+			Desc:   "one resource",
+			Value:  "pods=[status.phase!=Succeeded]",
+			Wanted: FieldSelectors{"pods": "status.phase!=Succeeded"},
+		},
+		{
+			Desc:  "multiple resources and selectors",
+			Value: "pods=[spec.nodeName!=,status.phase!=Succeeded],jobs=[status.successful=0]",
+			Wanted: FieldSelectors{
+				"pods": "spec.nodeName!=,status.phase!=Succeeded",
+				"jobs": "status.successful=0",
+			},
+		},
+		{
+			Desc:   "[invalid] missing bracket",
+			Value:  "pods=[status.phase!=Succeeded",
+			Wanted: FieldSelectors{},
+			err:    true,
+		},
+		{
+			Desc:   "[invalid] no comma between resources",
+			Value:  "pods=[status.phase!=Succeeded]jobs=[status.successful=0]",
+			Wanted: FieldSelectors{},
+			err:    true,
+		},
+		{
+			Desc:   "[invalid] trailing comma",
+			Value:  "pods=[status.phase!=Succeeded],",
+			Wanted: FieldSelectors{},
+			err:    true,
+		},
+		{
+			Desc:   "[invalid] no resource",
+			Value:  "=[status.phase!=Succeeded]",
+			Wanted: FieldSelectors{},
+			err:    true,
+		},
+		{
+			Desc:   "[invalid] field selector",
+			Value:  "pods=[status.phase]",
+			Wanted: FieldSelectors{},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		fs := &FieldSelectors{}
+		gotError := fs.Set(test.Value)
+		if (gotError != nil) != test.err || !reflect.DeepEqual(*fs, test.Wanted) {
+			t.Errorf("Test error for Desc: %s\n Want: \n%+v\n Got: \n%#+v\n Got Error: %#v", test.Desc, test.Wanted, *fs, gotError)
+		}
+	}
+}
+
+func TestResourceListenersSet(t *testing.T) {
+	tests := []struct {
+		Desc   string
+		Value  string
+		Wanted ResourceListeners
+		err    bool
+	}{
+		{
+			Desc:   "empty resource listeners",
+			Value:  "",
+			Wanted: ResourceListeners{},
+		},
+		{
+			Desc:  "multiple listeners",
+			Value: ":8082=[pods,nodes],[::1]:8083=[configmaps]",
+			Wanted: ResourceListeners{
+				":8082":      {"pods", "nodes"},
+				"[::1]:8083": {"configmaps"},
+			},
+		},
+		{
+			Desc:   "[invalid] no resources",
+			Value:  ":8082=[]",
+			Wanted: ResourceListeners{},
+			err:    true,
+		},
+		{
+			Desc:   "[invalid] missing bracket",
+			Value:  ":8082=[pods",
+			Wanted: ResourceListeners{},
+			err:    true,
+		},
+		{
+			Desc:   "[invalid] trailing comma",
+			Value:  ":8082=[pods],",
+			Wanted: ResourceListeners{},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		l := &ResourceListeners{}
+		gotError := l.Set(test.Value)
+		if (gotError != nil) != test.err || !reflect.DeepEqual(*l, test.Wanted) {
+			t.Errorf("Test error for Desc: %s. Want: %+v. Got: %+v. Wanted Error: %v, Got Error: %v", test.Desc, test.Wanted, *l, test.err, gotError)
+		}
+	}
+}
+
+func TestResourceSetUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		Desc   string
+		Value  string
+		Wanted ResourceSet
+		err    bool
+	}{
+		{
+			Desc:   "list",
+			Value:  "resources: [pods, nodes]",
+			Wanted: ResourceSet{"pods": {}, "nodes": {}},
+		},
+		{
+			Desc:   "comma-separated string",
+			Value:  "resources: pods,nodes",
+			Wanted: ResourceSet{"pods": {}, "nodes": {}},
+		},
+		{
+			Desc:   "mapping",
+			Value:  "resources: {pods: {}, nodes: {}}",
+			Wanted: ResourceSet{"pods": {}, "nodes": {}},
+		},
+		{
+			Desc:  "[invalid] nested list",
+			Value: "resources: [[pods]]",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		var o struct {
+			Resources ResourceSet `yaml:"resources"`
+		}
+		gotError := yaml.Unmarshal([]byte(test.Value), &o)
+		if (gotError != nil) != test.err || !reflect.DeepEqual(o.Resources, test.Wanted) {
+			t.Errorf("Test error for Desc: %s. Want: %+v. Got: %+v. Wanted Error: %v, Got Error: %v", test.Desc, test.Wanted, o.Resources, test.err, gotError)
+		}
+	}
+}
+
+func TestExcludeCompletedSet(t *testing.T) {
+	tests := []struct {
+		Desc   string
+		Value  string
+		Wanted ExcludeCompleted
+		err    bool
+	}{
+		{
+			Desc:   "empty exclude completed",
+			Value:  "",
+			Wanted: ExcludeCompleted{},
+		},
+		{
+			Desc:   "multiple resources",
+			Value:  "pods=30m, jobs=0s",
+			Wanted: ExcludeCompleted{"pods": 30 * time.Minute, "jobs": 0},
+		},
+		{
+			Desc:   "[invalid] missing duration",
+			Value:  "pods",
+			Wanted: ExcludeCompleted{},
+			err:    true,
+		},
+		{
+			Desc:   "[invalid] negative duration",
+			Value:  "pods=-1m",
+			Wanted: ExcludeCompleted{},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		e := &ExcludeCompleted{}
+		gotError := e.Set(test.Value)
+		if (gotError != nil) != test.err || !reflect.DeepEqual(*e, test.Wanted) {
+			t.Errorf("Test error for Desc: %s. Want: %+v. Got: %+v. Wanted Error: %v, Got Error: %v", test.Desc, test.Wanted, *e, test.err, gotError)
+		}
+	}
+}
+
+func TestMaxObjectsSet(t *testing.T) {
+	tests := []struct {
+		Desc   string
+		Value  string
+		Wanted MaxObjects
+		err    bool
+	}{
+		{
+			Desc:   "empty max objects",
+			Value:  "",
+			Wanted: MaxObjects{},
+		},
+		{
+			Desc:   "multiple resources",
+			Value:  "pods=50000, jobs=10000",
+			Wanted: MaxObjects{"pods": 50000, "jobs": 10000},
+		},
+		{
+			Desc:   "[invalid] missing limit",
+			Value:  "pods",
+			Wanted: MaxObjects{},
+			err:    true,
+		},
+		{
+			Desc:   "[invalid] zero limit",
+			Value:  "pods=0",
+			Wanted: MaxObjects{},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		m := &MaxObjects{}
+		gotError := m.Set(test.Value)
+		if (gotError != nil) != test.err || !reflect.DeepEqual(*m, test.Wanted) {
+			t.Errorf("Test error for Desc: %s. Want: %+v. Got: %+v. Wanted Error: %v, Got Error: %v", test.Desc, test.Wanted, *m, test.err, gotError)
+		}
+	}
+}
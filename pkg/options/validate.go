@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ValidateOptions are the options of the validate subcommand.
+type ValidateOptions struct {
+	CustomResourceConfigFile string
+	Strict                   bool
+}
+
+// NewValidateCommand returns the validate subcommand, which validates a
+// Custom Resource State config file by calling run with its options.
+func NewValidateCommand(run func(o *ValidateOptions)) *cobra.Command {
+	o := &ValidateOptions{}
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a Custom Resource State Metrics config file.",
+		Long:  "Validate a Custom Resource State Metrics config file. Exits with 1 if errors were found, and with 2 if only warnings were found and --strict is set.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			run(o)
+		},
+	}
+	cmd.Flags().StringVar(&o.CustomResourceConfigFile, "custom-resource-state-config-file", "", "Path to the Custom Resource State Metrics config file to validate")
+	cmd.Flags().BoolVar(&o.Strict, "strict", false, "Exit with a non-zero code if warnings were found")
+	return cmd
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otlp pushes the generated metrics to an OpenTelemetry collector
+// using the OTLP exporters of the OpenTelemetry SDK.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+const (
+	defaultPath = "/v1/metrics"
+
+	// ProtocolGRPC and ProtocolHTTPProtobuf are the supported OTLP
+	// protocols, named as in OTEL_EXPORTER_OTLP_PROTOCOL.
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+
+	// CompressionGzip and CompressionNone are the supported compressions of
+	// export requests.
+	CompressionGzip = "gzip"
+	CompressionNone = "none"
+)
+
+// Source returns the metric families to export.
+type Source interface {
+	Families() []*metric.Family
+}
+
+// Options are the options of an Exporter.
+type Options struct {
+	// Endpoint is the URL of the OTLP endpoint. Its scheme is http or https,
+	// which enables TLS. If it has no path, the default OTLP/HTTP metrics
+	// path is used for ProtocolHTTPProtobuf.
+	Endpoint string
+	// Protocol is ProtocolGRPC or ProtocolHTTPProtobuf.
+	Protocol string
+	// Compression is CompressionGzip or CompressionNone.
+	Compression string
+	// Interval is the interval in which all metrics are exported.
+	Interval time.Duration
+	// Timeout is the timeout of each export request, including retries.
+	Timeout time.Duration
+	// BatchSize is the maximum number of data points per export request.
+	BatchSize int
+	// ResourceAttributes are added to the attributes of the exported
+	// resource, e.g. the external labels.
+	ResourceAttributes map[string]string
+}
+
+// Exporter periodically pushes all metrics of a Source to an OTLP endpoint.
+type Exporter struct {
+	opts      Options
+	source    Source
+	exporter  sdkmetric.Exporter
+	resource  *sdkresource.Resource
+	startTime time.Time
+
+	exportsTotal *prometheus.CounterVec
+}
+
+// NewExporter returns a new Exporter pushing the metrics of source as
+// configured by opts.
+func NewExporter(ctx context.Context, opts Options, source Source, r prometheus.Registerer) (*Exporter, error) {
+	u, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: scheme must be http or https", opts.Endpoint)
+	}
+	if opts.Interval <= 0 {
+		return nil, fmt.Errorf("invalid OTLP export interval %s", opts.Interval)
+	}
+	if opts.Timeout <= 0 {
+		return nil, fmt.Errorf("invalid OTLP export timeout %s", opts.Timeout)
+	}
+	if opts.BatchSize <= 0 {
+		return nil, fmt.Errorf("invalid OTLP batch size %d", opts.BatchSize)
+	}
+	if opts.Compression != CompressionGzip && opts.Compression != CompressionNone {
+		return nil, fmt.Errorf("invalid OTLP compression %q: must be %s or %s", opts.Compression, CompressionGzip, CompressionNone)
+	}
+
+	var exporter sdkmetric.Exporter
+	switch opts.Protocol {
+	case ProtocolGRPC:
+		grpcOpts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpointURL(u.String()),
+			otlpmetricgrpc.WithTimeout(opts.Timeout),
+		}
+		if opts.Compression == CompressionGzip {
+			grpcOpts = append(grpcOpts, otlpmetricgrpc.WithCompressor(CompressionGzip))
+		}
+		exporter, err = otlpmetricgrpc.New(ctx, grpcOpts...)
+	case ProtocolHTTPProtobuf:
+		if u.Path == "" || u.Path == "/" {
+			u.Path = defaultPath
+		}
+		compression := otlpmetrichttp.NoCompression
+		if opts.Compression == CompressionGzip {
+			compression = otlpmetrichttp.GzipCompression
+		}
+		exporter, err = otlpmetrichttp.New(ctx,
+			otlpmetrichttp.WithEndpointURL(u.String()),
+			otlpmetrichttp.WithTimeout(opts.Timeout),
+			otlpmetrichttp.WithCompression(compression),
+		)
+	default:
+		return nil, fmt.Errorf("invalid OTLP protocol %q: must be %s or %s", opts.Protocol, ProtocolGRPC, ProtocolHTTPProtobuf)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	opts.Endpoint = u.String()
+
+	return &Exporter{
+		opts:      opts,
+		source:    source,
+		exporter:  exporter,
+		resource:  newResource(opts.ResourceAttributes),
+		startTime: time.Now(),
+		exportsTotal: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_otlp_exports_total",
+				Help: "Number of total OTLP exports in kube-state-metrics",
+			},
+			[]string{"result"},
+		),
+	}, nil
+}
+
+// newResource returns the resource of kube-state-metrics with the given
+// additional attributes.
+func newResource(attributes map[string]string) *sdkresource.Resource {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := []attribute.KeyValue{
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", version.Version),
+	}
+	for _, k := range keys {
+		kvs = append(kvs, attribute.String(k, attributes[k]))
+	}
+	return sdkresource.NewSchemaless(kvs...)
+}
+
+// Run exports all metrics every interval until the context is cancelled, and
+// shuts down the exporter then.
+func (e *Exporter) Run(ctx context.Context) error {
+	klog.InfoS("Started OTLP exporter", "endpoint", e.opts.Endpoint, "protocol", e.opts.Protocol, "interval", e.opts.Interval)
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			ctxShutdown, cancel := context.WithTimeout(context.Background(), e.opts.Timeout)
+			defer cancel()
+			if err := e.exporter.Shutdown(ctxShutdown); err != nil {
+				klog.ErrorS(err, "Failed to shut down OTLP exporter", "endpoint", e.opts.Endpoint)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+			if err := e.Export(ctx); err != nil {
+				e.exportsTotal.WithLabelValues("error").Inc()
+				klog.ErrorS(err, "Failed to export metrics via OTLP", "endpoint", e.opts.Endpoint)
+				continue
+			}
+			e.exportsTotal.WithLabelValues("success").Inc()
+		}
+	}
+}
+
+// Export pushes all metrics of the source once, in requests of at most
+// BatchSize data points. The series of a metric family may be split across
+// requests.
+func (e *Exporter) Export(ctx context.Context) error {
+	now := time.Now()
+	var (
+		metrics []metricdata.Metrics
+		points  int
+	)
+	flush := func() error {
+		if len(metrics) == 0 {
+			return nil
+		}
+		rm := &metricdata.ResourceMetrics{
+			Resource: e.resource,
+			ScopeMetrics: []metricdata.ScopeMetrics{{
+				Scope:   instrumentation.Scope{Name: serviceName, Version: version.Version},
+				Metrics: metrics,
+			}},
+		}
+		metrics, points = nil, 0
+		return e.exporter.Export(ctx, rm)
+	}
+
+	for _, f := range e.source.Families() {
+		for start := 0; start < len(f.Metrics); {
+			end := min(len(f.Metrics), start+e.opts.BatchSize-points)
+			metrics = append(metrics, e.metricData(f, f.Metrics[start:end], now))
+			points += end - start
+			start = end
+			if points >= e.opts.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return flush()
+}
+
+// metricData returns the given series of the metric family f as OTLP metric.
+// Counters are exported as cumulative sums since the start of the Exporter,
+// all other types as gauges.
+func (e *Exporter) metricData(f *metric.Family, series []*metric.Metric, now time.Time) metricdata.Metrics {
+	points := make([]metricdata.DataPoint[float64], len(series))
+	for i, m := range series {
+		kvs := make([]attribute.KeyValue, len(m.LabelKeys))
+		for j := range m.LabelKeys {
+			kvs[j] = attribute.String(m.LabelKeys[j], m.LabelValues[j])
+		}
+		points[i] = metricdata.DataPoint[float64]{
+			Attributes: attribute.NewSet(kvs...),
+			Time:       now,
+			Value:      m.Value,
+		}
+		if f.Type == metric.Counter {
+			points[i].StartTime = e.startTime
+		}
+	}
+
+	md := metricdata.Metrics{Name: f.Name, Description: f.Help}
+	if f.Type == metric.Counter {
+		md.Data = metricdata.Sum[float64]{
+			DataPoints:  points,
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+		}
+	} else {
+		md.Data = metricdata.Gauge[float64]{DataPoints: points}
+	}
+	return md
+}
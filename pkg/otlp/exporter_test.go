@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+type sourceFunc func() []*metric.Family
+
+func (f sourceFunc) Families() []*metric.Family {
+	return f()
+}
+
+var testSource = sourceFunc(func() []*metric.Family {
+	return []*metric.Family{
+		{
+			Name: "kube_pod_info",
+			Help: "Information about pod.",
+			Type: metric.Gauge,
+			Metrics: []*metric.Metric{
+				{LabelKeys: []string{"namespace", "pod", "node"}, LabelValues: []string{"default", "foo", ""}, Value: 1},
+				{LabelKeys: []string{"namespace", "pod", "node"}, LabelValues: []string{"default", "bar", ""}, Value: 1},
+			},
+		},
+		{
+			Name: "kube_pod_restarts_total",
+			Help: "Restarts.",
+			Type: metric.Counter,
+			Metrics: []*metric.Metric{
+				{LabelKeys: []string{"pod"}, LabelValues: []string{"foo"}, Value: math.NaN()},
+			},
+		},
+	}
+})
+
+func testOptions(endpoint, protocol string) Options {
+	return Options{
+		Endpoint:           endpoint,
+		Protocol:           protocol,
+		Compression:        CompressionGzip,
+		Interval:           time.Minute,
+		Timeout:            5 * time.Second,
+		BatchSize:          10,
+		ResourceAttributes: map[string]string{"cluster": "prod"},
+	}
+}
+
+func TestExportHTTP(t *testing.T) {
+	var (
+		gotPath        string
+		gotContentType string
+		got            colmetricpb.ExportMetricsServiceRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotContentType = r.Header.Get("Content-Type")
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("expected gzip encoding, got %q", r.Header.Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := proto.Unmarshal(body, &got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer srv.Close()
+
+	e, err := NewExporter(context.Background(), testOptions(srv.URL, ProtocolHTTPProtobuf), testSource, prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Export(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != defaultPath {
+		t.Errorf("expected path %s, got %s", defaultPath, gotPath)
+	}
+	if gotContentType != "application/x-protobuf" {
+		t.Errorf("expected content type application/x-protobuf, got %s", gotContentType)
+	}
+	checkRequests(t, []*colmetricpb.ExportMetricsServiceRequest{&got})
+}
+
+type metricsServer struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+
+	mtx      sync.Mutex
+	requests []*colmetricpb.ExportMetricsServiceRequest
+}
+
+func (s *metricsServer) Export(_ context.Context, req *colmetricpb.ExportMetricsServiceRequest) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.requests = append(s.requests, req)
+	return &colmetricpb.ExportMetricsServiceResponse{}, nil
+}
+
+func TestExportGRPCBatches(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	ms := &metricsServer{}
+	colmetricpb.RegisterMetricsServiceServer(srv, ms)
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	defer srv.Stop()
+
+	opts := testOptions("http://"+l.Addr().String(), ProtocolGRPC)
+	opts.BatchSize = 2
+	e, err := NewExporter(context.Background(), opts, testSource, prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Export(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The 3 series are sent in 2 requests of at most 2 data points.
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+	if len(ms.requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(ms.requests))
+	}
+	checkRequests(t, ms.requests)
+}
+
+// checkRequests checks that the requests contain the metrics of testSource.
+func checkRequests(t *testing.T, requests []*colmetricpb.ExportMetricsServiceRequest) {
+	t.Helper()
+
+	var metrics []*metricpb.Metric
+	for _, req := range requests {
+		rm := req.GetResourceMetrics()[0]
+		attrs := map[string]string{}
+		for _, kv := range rm.GetResource().GetAttributes() {
+			attrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+		}
+		if attrs["service.name"] != serviceName || attrs["cluster"] != "prod" {
+			t.Errorf("unexpected resource attributes %v", attrs)
+		}
+		metrics = append(metrics, rm.GetScopeMetrics()[0].GetMetrics()...)
+	}
+
+	var infoPoints []*metricpb.NumberDataPoint
+	var restarts *metricpb.Metric
+	for _, m := range metrics {
+		switch m.GetName() {
+		case "kube_pod_info":
+			if m.GetDescription() != "Information about pod." {
+				t.Errorf("unexpected description %q", m.GetDescription())
+			}
+			infoPoints = append(infoPoints, m.GetGauge().GetDataPoints()...)
+		case "kube_pod_restarts_total":
+			restarts = m
+		default:
+			t.Errorf("unexpected metric %s", m.GetName())
+		}
+	}
+
+	if len(infoPoints) != 2 {
+		t.Fatalf("expected 2 data points of kube_pod_info, got %d", len(infoPoints))
+	}
+	point := infoPoints[0]
+	if point.GetAsDouble() != 1 {
+		t.Errorf("expected value 1, got %v", point.GetAsDouble())
+	}
+	attrs := map[string]string{}
+	for _, kv := range point.GetAttributes() {
+		attrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+	}
+	// Empty label values are sent as empty strings.
+	if v, ok := attrs["node"]; len(attrs) != 3 || !ok || v != "" {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+
+	if restarts == nil {
+		t.Fatal("expected kube_pod_restarts_total")
+	}
+	sum := restarts.GetSum()
+	if !sum.GetIsMonotonic() || sum.GetAggregationTemporality() != metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+		t.Errorf("unexpected sum %v", sum)
+	}
+	if v := sum.GetDataPoints()[0].GetAsDouble(); !math.IsNaN(v) {
+		t.Errorf("expected NaN, got %v", v)
+	}
+	if sum.GetDataPoints()[0].GetStartTimeUnixNano() == 0 {
+		t.Error("expected the start time of the sum to be set")
+	}
+}
+
+func TestNewExporterInvalidOptions(t *testing.T) {
+	for name, opts := range map[string]Options{
+		"endpoint without scheme": testOptions("otel-collector:4317", ProtocolGRPC),
+		"unknown protocol":        testOptions("http://otel-collector:4318", "http/json"),
+	} {
+		if _, err := NewExporter(context.Background(), opts, testSource, prometheus.NewRegistry()); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/version"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultTracesPath = "/v1/traces"
+	serviceName       = "kube-state-metrics"
+
+	// tracesShutdownTimeout is the time the remaining spans have to be
+	// exported once the TraceExporter is stopped.
+	tracesShutdownTimeout = 3 * time.Second
+)
+
+// TraceExporter records spans and exports them in batches to an OTLP/HTTP
+// endpoint. Traces are sampled by the given ratio, unless they are part of a
+// trace of a caller, e.g. propagated via the traceparent header of a scrape,
+// whose sampling decision is kept.
+type TraceExporter struct {
+	endpoint    string
+	sampleRatio float64
+	provider    *sdktrace.TracerProvider
+}
+
+// NewTraceExporter returns a new TraceExporter exporting to the given
+// endpoint. If the endpoint has no path, the default OTLP/HTTP traces path is
+// used. The sample ratio is the fraction of traces which are recorded.
+func NewTraceExporter(ctx context.Context, endpoint string, sampleRatio float64, r prometheus.Registerer) (*TraceExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP traces endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP traces endpoint %q: scheme must be http or https", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = defaultTracesPath
+	}
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(u.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(&countingSpanExporter{
+			SpanExporter: exporter,
+			endpoint:     u.String(),
+			exportsTotal: promauto.With(r).NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "kube_state_metrics_otlp_trace_exports_total",
+					Help: "Number of total OTLP trace exports in kube-state-metrics",
+				},
+				[]string{"result"},
+			),
+		}),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(newResource(nil)),
+	)
+	return &TraceExporter{endpoint: u.String(), sampleRatio: sampleRatio, provider: provider}, nil
+}
+
+// Tracer returns the tracer of the spans of kube-state-metrics.
+func (t *TraceExporter) Tracer() trace.Tracer {
+	return t.provider.Tracer(serviceName, trace.WithInstrumentationVersion(version.Version))
+}
+
+// Run waits until the context is cancelled and shuts down the exporter then,
+// which exports the remaining spans once more.
+func (t *TraceExporter) Run(ctx context.Context) error {
+	klog.InfoS("Started OTLP trace exporter", "endpoint", t.endpoint, "sampleRatio", t.sampleRatio)
+	<-ctx.Done()
+	ctxShutdown, cancel := context.WithTimeout(context.Background(), tracesShutdownTimeout)
+	defer cancel()
+	if err := t.provider.Shutdown(ctxShutdown); err != nil {
+		klog.ErrorS(err, "Failed to shut down OTLP trace exporter", "endpoint", t.endpoint)
+	}
+	return ctx.Err()
+}
+
+// countingSpanExporter counts the exports of a SpanExporter by their result.
+type countingSpanExporter struct {
+	sdktrace.SpanExporter
+	endpoint     string
+	exportsTotal *prometheus.CounterVec
+}
+
+func (e *countingSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := e.SpanExporter.ExportSpans(ctx, spans); err != nil {
+		e.exportsTotal.WithLabelValues("error").Inc()
+		klog.ErrorS(err, "Failed to export traces via OTLP", "endpoint", e.endpoint)
+		return err
+	}
+	e.exportsTotal.WithLabelValues("success").Inc()
+	return nil
+}
+
+// SetSpanError marks the span as failed with the given error, if it is not
+// nil.
+func SetSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
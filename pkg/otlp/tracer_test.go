@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// tracesServer is an OTLP/HTTP traces endpoint recording the exported spans.
+type tracesServer struct {
+	*httptest.Server
+
+	mtx   sync.Mutex
+	paths []string
+	spans []*tracepb.Span
+}
+
+func newTracesServer(t *testing.T) *tracesServer {
+	s := &tracesServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var req coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		s.paths = append(s.paths, r.URL.Path)
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				s.spans = append(s.spans, ss.Spans...)
+			}
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	return s
+}
+
+// stop stops the TraceExporter, which exports the remaining spans.
+func stop(t *testing.T, e *TraceExporter) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := e.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the exporter to be stopped, got %v", err)
+	}
+}
+
+func TestTraceExporter(t *testing.T) {
+	srv := newTracesServer(t)
+	defer srv.Close()
+
+	r := prometheus.NewRegistry()
+	e, err := NewTraceExporter(context.Background(), srv.URL, 1, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := e.Tracer()
+
+	ctx, root := tracer.Start(context.Background(), "scrape", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tracer.Start(ctx, "write pods")
+	SetSpanError(child, errors.New("broken pipe"))
+	child.End()
+	root.End()
+	stop(t, e)
+
+	if len(srv.paths) != 1 || srv.paths[0] != defaultTracesPath {
+		t.Errorf("expected one export to %s, got %v", defaultTracesPath, srv.paths)
+	}
+	if len(srv.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(srv.spans))
+	}
+	c, p := srv.spans[0], srv.spans[1]
+	if p.Name != "scrape" || p.Kind != tracepb.Span_SPAN_KIND_SERVER || len(p.ParentSpanId) != 0 {
+		t.Errorf("unexpected root span %v", p)
+	}
+	if c.Name != "write pods" || !bytes.Equal(c.TraceId, p.TraceId) || !bytes.Equal(c.ParentSpanId, p.SpanId) {
+		t.Errorf("unexpected child span %v", c)
+	}
+	if c.Status.GetCode() != tracepb.Status_STATUS_CODE_ERROR || c.Status.GetMessage() != "broken pipe" {
+		t.Errorf("unexpected child span status %v", c.Status)
+	}
+	want := `
+		# HELP kube_state_metrics_otlp_trace_exports_total Number of total OTLP trace exports in kube-state-metrics
+		# TYPE kube_state_metrics_otlp_trace_exports_total counter
+		kube_state_metrics_otlp_trace_exports_total{result="success"} 1
+	`
+	if err := testutil.GatherAndCompare(r, strings.NewReader(want), "kube_state_metrics_otlp_trace_exports_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTraceExporterSampling(t *testing.T) {
+	srv := newTracesServer(t)
+	defer srv.Close()
+
+	e, err := NewTraceExporter(context.Background(), srv.URL, 0, prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := e.Tracer()
+
+	// Traces started by kube-state-metrics are sampled by the ratio.
+	_, unsampled := tracer.Start(context.Background(), "list pods")
+	if unsampled.IsRecording() {
+		t.Errorf("expected spans of unsampled traces not to be recorded")
+	}
+	unsampled.End()
+
+	// Traces of callers keep their sampling decision.
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	header := http.Header{}
+	header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(header))
+	_, sampled := tracer.Start(ctx, "scrape", trace.WithSpanKind(trace.SpanKindServer))
+	sampled.End()
+	stop(t, e)
+
+	if len(srv.spans) != 1 {
+		t.Fatalf("expected only the span of the sampled trace of the caller, got %d", len(srv.spans))
+	}
+	if got := hex.EncodeToString(srv.spans[0].TraceId); got != traceID {
+		t.Errorf("expected trace ID %s of the caller, got %s", traceID, got)
+	}
+	if got := hex.EncodeToString(srv.spans[0].ParentSpanId); got != "00f067aa0ba902b7" {
+		t.Errorf("expected the span of the caller as parent, got %s", got)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+// handshakeTimeout is the time a plugin has to write its handshake.
+const handshakeTimeout = 10 * time.Second
+
+// factory is a customresource.RegistryFactory generating the metrics of a
+// plugin.
+type factory struct {
+	handshake Handshake
+	process   *process
+}
+
+var _ customresource.RegistryFactory = &factory{}
+
+// Load starts all executables in the given directory as plugins and returns a
+// customresource.RegistryFactory for each of them. The plugins are stopped
+// once ctx is done.
+func Load(ctx context.Context, dir string) ([]customresource.RegistryFactory, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+	var factories []customresource.RegistryFactory
+	names := map[string]string{}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugin %s: %w", e.Name(), err)
+		}
+		if info.IsDir() || info.Mode()&0o111 == 0 {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		p := &process{ctx: ctx, path: path}
+		h, err := p.start()
+		if err != nil {
+			return nil, fmt.Errorf("failed to start plugin %s: %w", path, err)
+		}
+		p.handshake = h
+		if other, ok := names[h.ResourcePlural]; ok {
+			return nil, fmt.Errorf("plugins %s and %s collect the same resource %s", other, path, h.ResourcePlural)
+		}
+		names[h.ResourcePlural] = path
+		klog.InfoS("Loaded plugin", "plugin", path, "resource", h.ResourcePlural)
+		factories = append(factories, &factory{handshake: h, process: p})
+	}
+	return factories, nil
+}
+
+func (f *factory) gvk() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: f.handshake.GroupVersionKind.Group, Version: f.handshake.GroupVersionKind.Version, Kind: f.handshake.GroupVersionKind.Kind}
+}
+
+func (f *factory) Name() string {
+	return f.handshake.ResourcePlural
+}
+
+func (f *factory) CreateClient(cfg *rest.Config) (interface{}, error) {
+	c, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return c.Resource(f.gvk().GroupVersion().WithResource(f.handshake.ResourcePlural)), nil
+}
+
+func (f *factory) MetricFamilyGenerators(_, _ []string) []generator.FamilyGenerator {
+	generators := make([]generator.FamilyGenerator, 0, len(f.handshake.Families))
+	for _, h := range f.handshake.Families {
+		name := h.Name
+		generators = append(generators, *generator.NewFamilyGenerator(h.Name, h.Help, h.Type, "", func(obj interface{}) *metric.Family {
+			return familyOf(f.process.generate(obj.(*unstructured.Unstructured)), name)
+		}))
+	}
+	return generators
+}
+
+func (f *factory) ExpectedType() interface{} {
+	u := unstructured.Unstructured{}
+	u.SetGroupVersionKind(f.gvk())
+	return &u
+}
+
+func (f *factory) ListWatch(customResourceClient interface{}, ns string, fieldSelector string) cache.ListerWatcher {
+	api := customResourceClient.(dynamic.NamespaceableResourceInterface).Namespace(ns)
+	ctx := context.Background()
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return api.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return api.Watch(ctx, options)
+		},
+	}
+}
+
+// familyOf returns the series of the family of the given name of a response.
+// Labels are sorted by their names.
+func familyOf(families map[string][]Sample, name string) *metric.Family {
+	samples := families[name]
+	f := &metric.Family{Metrics: make([]*metric.Metric, 0, len(samples))}
+	for _, s := range samples {
+		m := &metric.Metric{Value: s.Value, LabelKeys: make([]string, 0, len(s.Labels))}
+		for k := range s.Labels {
+			m.LabelKeys = append(m.LabelKeys, k)
+		}
+		sort.Strings(m.LabelKeys)
+		m.LabelValues = make([]string, len(m.LabelKeys))
+		for i, k := range m.LabelKeys {
+			m.LabelValues[i] = s.Labels[k]
+		}
+		f.Metrics = append(f.Metrics, m)
+	}
+	return f
+}
+
+// validate returns an error if a handshake is not supported.
+func (h Handshake) validate() error {
+	if h.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d, expected %d", h.ProtocolVersion, ProtocolVersion)
+	}
+	if h.ResourcePlural == "" || h.GroupVersionKind.Version == "" || h.GroupVersionKind.Kind == "" {
+		return fmt.Errorf("resourcePlural and the version and kind of groupVersionKind must be set")
+	}
+	for _, f := range h.Families {
+		if f.Name == "" {
+			return fmt.Errorf("metric families must have a name")
+		}
+	}
+	return nil
+}
+
+func decodeHandshake(line []byte) (Handshake, error) {
+	var h Handshake
+	if err := json.Unmarshal(line, &h); err != nil {
+		return h, fmt.Errorf("invalid handshake: %w", err)
+	}
+	return h, h.validate()
+}
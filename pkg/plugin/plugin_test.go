@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// TestHelperPlugin is run as plugin by the other tests.
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("KSM_TEST_PLUGIN") == "" {
+		return
+	}
+	h := Handshake{
+		ProtocolVersion:  ProtocolVersion,
+		GroupVersionKind: GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"},
+		ResourcePlural:   "foos",
+		Families:         []FamilyHeader{{Name: "kube_foo_info", Help: "Information about foos.", Type: metric.Gauge}},
+	}
+	if os.Getenv("KSM_TEST_PLUGIN") == "changed" {
+		h.Families = append(h.Families, FamilyHeader{Name: "kube_foo_status", Help: "Status of foos.", Type: metric.Gauge})
+	}
+	err := Serve(os.Stdin, os.Stdout, h, func(obj *unstructured.Unstructured) (map[string][]Sample, error) {
+		switch obj.GetName() {
+		case "broken":
+			return nil, fmt.Errorf("broken foo")
+		case "hanging":
+			time.Sleep(time.Hour)
+		}
+		return map[string][]Sample{
+			"kube_foo_info": {{Labels: map[string]string{"name": obj.GetName(), "namespace": obj.GetNamespace()}, Value: 1}},
+		}, nil
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func newFoo(name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.com/v1")
+	u.SetKind("Foo")
+	u.SetNamespace("default")
+	u.SetName(name)
+	u.SetUID(types.UID(name))
+	return u
+}
+
+// writeHelperPlugin writes a plugin running TestHelperPlugin with the given
+// value of KSM_TEST_PLUGIN to dir.
+func writeHelperPlugin(t *testing.T, dir string, mode string) {
+	t.Helper()
+	script := fmt.Sprintf("#!/bin/sh\nKSM_TEST_PLUGIN=%s exec %q -test.run='^TestHelperPlugin$'\n", mode, os.Args[0])
+	if err := os.WriteFile(filepath.Join(dir, "foo"), []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeHelperPlugin(t, dir, "1")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factories, err := Load(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(factories) != 1 || factories[0].Name() != "foos" {
+		t.Fatalf("expected the plugin of foos to be loaded, got %v", factories)
+	}
+	generators := factories[0].MetricFamilyGenerators(nil, nil)
+	if len(generators) != 1 || generators[0].Name != "kube_foo_info" || generators[0].Type != metric.Gauge {
+		t.Fatalf("expected the declared family, got %+v", generators)
+	}
+
+	want := &metric.Family{
+		Name:    "kube_foo_info",
+		Help:    "Information about foos.",
+		Type:    metric.Gauge,
+		Metrics: []*metric.Metric{{LabelKeys: []string{"name", "namespace"}, LabelValues: []string{"foo1", "default"}, Value: 1}},
+	}
+	if got := generators[0].Generate(newFoo("foo1")); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := generators[0].Generate(newFoo("broken")); len(got.Metrics) != 0 {
+		t.Errorf("expected no series of objects the plugin failed for, got %+v", got.Metrics)
+	}
+
+	// Crashed plugins are restarted after the restart backoff.
+	p := factories[0].(*factory).process
+	p.mtx.Lock()
+	_ = p.cmd.Process.Kill()
+	p.mtx.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	if got := generators[0].Generate(newFoo("foo2")); len(got.Metrics) != 0 {
+		t.Errorf("expected no series while the plugin is not running, got %+v", got.Metrics)
+	}
+	p.mtx.Lock()
+	p.startedAt = time.Time{}
+	p.mtx.Unlock()
+	if got := generators[0].Generate(newFoo("foo3")); len(got.Metrics) != 1 {
+		t.Errorf("expected the series of the restarted plugin, got %+v", got.Metrics)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { requestTimeout = timeout }(requestTimeout)
+	requestTimeout = 100 * time.Millisecond
+
+	dir := t.TempDir()
+	writeHelperPlugin(t, dir, "1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factories, err := Load(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generators := factories[0].MetricFamilyGenerators(nil, nil)
+	if got := generators[0].Generate(newFoo("hanging")); len(got.Metrics) != 0 {
+		t.Errorf("expected no series of objects the plugin did not answer for, got %+v", got.Metrics)
+	}
+
+	// Plugins which do not answer in time are stopped and restarted after
+	// the restart backoff.
+	p := factories[0].(*factory).process
+	p.mtx.Lock()
+	if p.cmd != nil {
+		t.Error("expected the plugin to be stopped")
+	}
+	p.startedAt = time.Time{}
+	p.mtx.Unlock()
+	if got := generators[0].Generate(newFoo("foo1")); len(got.Metrics) != 1 {
+		t.Errorf("expected the series of the restarted plugin, got %+v", got.Metrics)
+	}
+}
+
+func TestRestartHandshakeChanged(t *testing.T) {
+	dir := t.TempDir()
+	writeHelperPlugin(t, dir, "1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factories, err := Load(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generators := factories[0].MetricFamilyGenerators(nil, nil)
+
+	// The plugin is updated and crashes, so that it is restarted with a
+	// handshake declaring another family.
+	writeHelperPlugin(t, dir, "changed")
+	p := factories[0].(*factory).process
+	p.mtx.Lock()
+	p.stop()
+	p.startedAt = time.Time{}
+	p.mtx.Unlock()
+	if got := generators[0].Generate(newFoo("foo1")); len(got.Metrics) != 0 {
+		t.Errorf("expected no series of a plugin with a changed handshake, got %+v", got.Metrics)
+	}
+
+	// The plugin is not restarted again, even if its handshake is restored.
+	writeHelperPlugin(t, dir, "1")
+	p.mtx.Lock()
+	p.startedAt = time.Time{}
+	p.mtx.Unlock()
+	if got := generators[0].Generate(newFoo("foo2")); len(got.Metrics) != 0 {
+		t.Errorf("expected no series of a plugin with a changed handshake, got %+v", got.Metrics)
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.cmd != nil {
+		t.Error("expected the plugin to be stopped")
+	}
+}
+
+func TestHandshakeValidate(t *testing.T) {
+	tests := []struct {
+		handshake string
+		wantErr   bool
+	}{
+		{handshake: `{"protocolVersion":1,"groupVersionKind":{"version":"v1","kind":"Foo"},"resourcePlural":"foos"}`},
+		{handshake: `{"protocolVersion":2,"groupVersionKind":{"version":"v1","kind":"Foo"},"resourcePlural":"foos"}`, wantErr: true},
+		{handshake: `{"protocolVersion":1,"groupVersionKind":{"version":"v1","kind":"Foo"}}`, wantErr: true},
+		{handshake: `{"protocolVersion":1,"groupVersionKind":{"version":"v1","kind":"Foo"},"resourcePlural":"foos","families":[{}]}`, wantErr: true},
+		{handshake: `not json`, wantErr: true},
+	}
+	for _, test := range tests {
+		if _, err := decodeHandshake([]byte(test.handshake)); (err != nil) != test.wantErr {
+			t.Errorf("%s: expected error %v, got %v", test.handshake, test.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+)
+
+// restartBackoff is the minimum time between two starts of a plugin, so that
+// failing plugins are not restarted for every object.
+const restartBackoff = 10 * time.Second
+
+// requestTimeout is the time a plugin has to answer a request. Plugins which
+// do not answer in time are stopped, as requests are serialized and block
+// the updates of the store of the resource.
+var requestTimeout = 10 * time.Second
+
+var (
+	errNotRunning       = errors.New("plugin is not running")
+	errHandshakeChanged = errors.New("plugin changed its handshake since it was loaded, restart kube-state-metrics to load it again")
+)
+
+// process is a running plugin. Plugins answer one request at a time, so all
+// requests are serialized.
+type process struct {
+	ctx  context.Context
+	path string
+	// handshake is the handshake of the first start, which the families are
+	// registered by. A plugin which is restarted with a different handshake
+	// is stopped for good.
+	handshake Handshake
+
+	mtx       sync.Mutex
+	cmd       *exec.Cmd
+	stdin     io.Closer
+	enc       *json.Encoder
+	lines     *bufio.Scanner
+	startedAt time.Time
+	// changed is set once the plugin was restarted with a different
+	// handshake.
+	changed bool
+	// key and families cache the response of the last object, as the
+	// generators of all families of an object are called one after another.
+	key      string
+	families map[string][]Sample
+}
+
+// start starts the plugin and reads its handshake. It stops the plugin once
+// ctx is done.
+func (p *process) start() (Handshake, error) {
+	p.startedAt = time.Now()
+	cmd := exec.CommandContext(p.ctx, p.path) //nolint:gosec
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return Handshake{}, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return Handshake{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		return Handshake{}, err
+	}
+	stop := func() {
+		_ = stdin.Close()
+		_ = cmd.Process.Kill()
+		go func() { _ = cmd.Wait() }()
+	}
+
+	lines := newScanner(stdout)
+	read := make(chan error, 1)
+	go func() {
+		read <- scan(lines)
+	}()
+	select {
+	case err = <-read:
+	case <-time.After(handshakeTimeout):
+		err = errors.New("timed out waiting for the handshake")
+	}
+	if err != nil {
+		stop()
+		return Handshake{}, err
+	}
+	h, err := decodeHandshake(lines.Bytes())
+	if err != nil {
+		stop()
+		return Handshake{}, err
+	}
+
+	p.cmd, p.stdin, p.enc, p.lines = cmd, stdin, json.NewEncoder(stdin), lines
+	go func() {
+		<-p.ctx.Done()
+		p.mtx.Lock()
+		defer p.mtx.Unlock()
+		if p.cmd == cmd {
+			p.stop()
+		}
+	}()
+	return h, nil
+}
+
+// stop stops the plugin, which is restarted by the next request.
+func (p *process) stop() {
+	if p.cmd == nil {
+		return
+	}
+	cmd := p.cmd
+	_ = p.stdin.Close()
+	_ = cmd.Process.Kill()
+	go func() { _ = cmd.Wait() }()
+	p.cmd, p.stdin, p.enc, p.lines = nil, nil, nil, nil
+}
+
+// generate returns the series of the families of the given object. Errors
+// are logged and result in no series.
+func (p *process) generate(obj *unstructured.Unstructured) map[string][]Sample {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	key := string(obj.GetUID()) + "/" + obj.GetResourceVersion()
+	if key == p.key {
+		return p.families
+	}
+	families, err := p.request(obj)
+	if err != nil {
+		klog.ErrorS(err, "Failed to generate metrics via plugin", "plugin", p.path, "namespace", obj.GetNamespace(), "name", obj.GetName())
+	}
+	p.key, p.families = key, families
+	return families
+}
+
+func (p *process) request(obj *unstructured.Unstructured) (map[string][]Sample, error) {
+	if p.changed {
+		return nil, errHandshakeChanged
+	}
+	if p.cmd == nil {
+		if time.Since(p.startedAt) < restartBackoff {
+			return nil, errNotRunning
+		}
+		klog.InfoS("Restarting plugin", "plugin", p.path)
+		h, err := p.start()
+		if err != nil {
+			return nil, fmt.Errorf("failed to restart plugin: %w", err)
+		}
+		if !reflect.DeepEqual(h, p.handshake) {
+			p.stop()
+			p.changed = true
+			return nil, errHandshakeChanged
+		}
+	}
+
+	// The request is written and its response read by a goroutine, so that
+	// plugins which do not answer can be stopped, which ends the goroutine.
+	enc, lines := p.enc, p.lines
+	done := make(chan error, 1)
+	go func() {
+		if err := enc.Encode(Request{Object: obj}); err != nil {
+			done <- fmt.Errorf("failed to write request: %w", err)
+			return
+		}
+		if err := scan(lines); err != nil {
+			done <- fmt.Errorf("failed to read response: %w", err)
+			return
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			p.stop()
+			return nil, err
+		}
+	case <-time.After(requestTimeout):
+		p.stop()
+		return nil, errors.New("timed out waiting for the response")
+	}
+
+	var resp Response
+	if err := json.Unmarshal(lines.Bytes(), &resp); err != nil {
+		p.stop()
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Families, nil
+}
+
+// scan reads the next line, returning io.ErrUnexpectedEOF if the plugin
+// closed its stdout.
+func scan(lines *bufio.Scanner) error {
+	if lines.Scan() {
+		return nil
+	}
+	if err := lines.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
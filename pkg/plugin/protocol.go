@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin implements collectors of custom resources in separate
+// executables, which are discovered in a plugin directory.
+//
+// kube-state-metrics starts each executable of the plugin directory and
+// communicates with it via JSON lines on its stdin and stdout. The plugin
+// first writes a Handshake declaring the resource it collects and its metric
+// families. kube-state-metrics then lists and watches the resource and writes a
+// Request for each added or updated object, which the plugin answers with a
+// Response containing the series of its metric families. The series of deleted
+// objects are removed by kube-state-metrics.
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// ProtocolVersion is the version of the plugin protocol implemented by this
+// package.
+const ProtocolVersion = 1
+
+// Handshake is written by a plugin once it started.
+type Handshake struct {
+	// ProtocolVersion must be ProtocolVersion.
+	ProtocolVersion int `json:"protocolVersion"`
+	// GroupVersionKind is the kind of the collected objects.
+	GroupVersionKind GroupVersionKind `json:"groupVersionKind"`
+	// ResourcePlural is the resource of the collected objects, e.g. foos.
+	// It also is the name of the resource to enable via --resources.
+	ResourcePlural string `json:"resourcePlural"`
+	// Families are the metric families generated for each object.
+	Families []FamilyHeader `json:"families"`
+}
+
+// GroupVersionKind is the group, version and kind of the objects collected by
+// a plugin.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// FamilyHeader declares a metric family generated by a plugin.
+type FamilyHeader struct {
+	Name string      `json:"name"`
+	Help string      `json:"help"`
+	Type metric.Type `json:"type"`
+}
+
+// Request is written to a plugin for each added or updated object.
+type Request struct {
+	Object *unstructured.Unstructured `json:"object"`
+}
+
+// Response is written by a plugin for each Request.
+type Response struct {
+	// Families are the series of the object by the names of their families.
+	// Families missing in the response have no series for the object.
+	Families map[string][]Sample `json:"families,omitempty"`
+	// Error is logged by kube-state-metrics if set. The object then has no
+	// series.
+	Error string `json:"error,omitempty"`
+}
+
+// Sample is a single series of a metric family.
+type Sample struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// Serve implements the plugin side of the protocol: it writes the handshake to
+// w and answers each request read from r with the series returned by generate,
+// until r is closed. Plugins written in Go call it with os.Stdin and
+// os.Stdout.
+func Serve(r io.Reader, w io.Writer, h Handshake, generate func(obj *unstructured.Unstructured) (map[string][]Sample, error)) error {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(h); err != nil {
+		return fmt.Errorf("failed to write handshake: %w", err)
+	}
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else if families, err := generate(req.Object); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Families = families
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// newScanner returns a scanner of JSON lines of up to 16MiB, which is large
+// enough for any object.
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return scanner
+}
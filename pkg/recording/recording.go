@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recording implements recording rules aggregating metric families
+// into new metric families when they are written to a response.
+package recording
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"k8s.io/kube-state-metrics/v2/pkg/aggregation"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+// Config is the configuration of the recording rules.
+type Config struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is a recording rule aggregating the series of a metric family into a
+// new metric family whenever metrics are written.
+type Rule struct {
+	// Record is the name of the recorded family.
+	Record string `yaml:"record"`
+	// Family is the name of the aggregated family.
+	Family string `yaml:"family"`
+	// Op is the aggregation operation, sum or count.
+	Op aggregation.Op `yaml:"op"`
+	// By are the labels the series are aggregated by. All other labels are
+	// dropped.
+	By []string `yaml:"by"`
+	// Help is the help text of the recorded family. It defaults to a
+	// description of the rule.
+	Help string `yaml:"help"`
+}
+
+// Rules evaluates recording rules over metrics written in the text exposition
+// format.
+type Rules struct {
+	rules []Rule
+	// families are the indices of the rules by the name of the family they
+	// aggregate.
+	families map[string][]int
+}
+
+// LoadConfig reads a recording rules configuration from r and returns its
+// Rules.
+func LoadConfig(r io.Reader) (*Rules, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	var c Config
+	if err := decoder.Decode(&c); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode recording rules config: %w", err)
+	}
+	return New(c)
+}
+
+// New validates the given configuration and returns its Rules.
+func New(c Config) (*Rules, error) {
+	r := &Rules{families: map[string][]int{}}
+	records := map[string]struct{}{}
+	for i, rule := range c.Rules {
+		if !model.IsValidLegacyMetricName(rule.Record) {
+			return nil, fmt.Errorf("rule %d: invalid record name %q", i, rule.Record)
+		}
+		if _, ok := records[rule.Record]; ok {
+			return nil, fmt.Errorf("rule %d: duplicate record name %q", i, rule.Record)
+		}
+		records[rule.Record] = struct{}{}
+		if !model.IsValidLegacyMetricName(rule.Family) {
+			return nil, fmt.Errorf("rule %d: invalid family name %q", i, rule.Family)
+		}
+		switch rule.Op {
+		case aggregation.Sum, aggregation.Count:
+		default:
+			return nil, fmt.Errorf("rule %d: unknown op %q", i, rule.Op)
+		}
+		for _, label := range rule.By {
+			if !model.LabelName(label).IsValidLegacy() {
+				return nil, fmt.Errorf("rule %d: invalid label name %q", i, label)
+			}
+		}
+		if rule.Help == "" {
+			rule.Help = fmt.Sprintf("%s of %s by %s.", strings.ToUpper(string(rule.Op[:1]))+string(rule.Op[1:]), rule.Family, strings.Join(rule.By, ","))
+		}
+		r.rules = append(r.rules, rule)
+		r.families[rule.Family] = append(r.families[rule.Family], len(r.rules)-1)
+	}
+	return r, nil
+}
+
+// Writer passes the metrics written to it on to the underlying writer and
+// aggregates the series of the families of the rules. The recorded families
+// are written once it is flushed.
+type Writer struct {
+	w           io.Writer
+	lines       *metricsstore.LineWriter
+	rules       *Rules
+	aggregators []*aggregation.Aggregator
+}
+
+// NewWriter returns a writer evaluating the rules over the metrics written to
+// w in the text exposition format. Flush has to be called once all metrics are
+// written.
+func (r *Rules) NewWriter(w io.Writer) *Writer {
+	rw := &Writer{w: w, rules: r, aggregators: make([]*aggregation.Aggregator, len(r.rules))}
+	for i, rule := range r.rules {
+		rw.aggregators[i] = aggregation.NewAggregator(rule.Op, rule.By)
+	}
+	rw.lines = metricsstore.NewLineWriter(w, rw.record)
+	return rw
+}
+
+// Write implements the io.Writer interface.
+func (rw *Writer) Write(p []byte) (int, error) {
+	return rw.lines.Write(p)
+}
+
+// Flush writes a remaining incomplete line and the recorded families.
+func (rw *Writer) Flush() error {
+	if err := rw.lines.Flush(); err != nil {
+		return err
+	}
+	for i, rule := range rw.rules.rules {
+		f := rw.aggregators[i].Family(rule.Record, rule.Help, metric.Gauge)
+		header := fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", f.Name, f.Help, f.Name, f.Type)
+		if _, err := rw.w.Write(append([]byte(header), f.ByteSlice()...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record adds the series of line to the aggregators of its family and appends
+// the line to dst unchanged.
+func (rw *Writer) record(dst, line []byte) []byte {
+	dst = append(dst, line...)
+	if line[0] == '#' || line[0] == '\n' {
+		return dst
+	}
+	end := bytes.IndexAny(line, "{ ")
+	if end <= 0 {
+		return dst
+	}
+	rules, ok := rw.rules.families[string(line[:end])]
+	if !ok {
+		return dst
+	}
+	_, m, err := metric.ParseMetric(string(bytes.TrimSuffix(line, []byte("\n"))))
+	if err != nil {
+		// Not a valid series, which is not recorded.
+		return dst
+	}
+	for _, i := range rules {
+		rw.aggregators[i].Add(m)
+	}
+	return dst
+}
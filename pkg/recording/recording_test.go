@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const input = `# HELP kube_pod_status_phase The pods current phase.
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="a",pod="pod1",phase="Running"} 1
+kube_pod_status_phase{namespace="a",pod="pod1",phase="Pending"} 0
+kube_pod_status_phase{namespace="a",pod="pod2",phase="Running"} 1
+kube_pod_status_phase{namespace="b",pod="pod3",phase="Pending"} 1
+kube_pod_status_phase{namespace="b",pod="pod3",phase="Running"} 0
+`
+
+func TestRules(t *testing.T) {
+	config := `
+rules:
+- record: namespace_phase:kube_pod_status_phase:sum
+  family: kube_pod_status_phase
+  op: sum
+  by: [namespace, phase]
+- record: namespace:kube_pod_status_phase:count
+  family: kube_pod_status_phase
+  op: count
+  by: [namespace]
+  help: Number of phase series per namespace.
+- record: node:kube_node_info:count
+  family: kube_node_info
+  op: count
+  by: [node]
+`
+	want := input + `# HELP namespace_phase:kube_pod_status_phase:sum Sum of kube_pod_status_phase by namespace,phase.
+# TYPE namespace_phase:kube_pod_status_phase:sum gauge
+namespace_phase:kube_pod_status_phase:sum{namespace="a",phase="Pending"} 0
+namespace_phase:kube_pod_status_phase:sum{namespace="a",phase="Running"} 2
+namespace_phase:kube_pod_status_phase:sum{namespace="b",phase="Pending"} 1
+namespace_phase:kube_pod_status_phase:sum{namespace="b",phase="Running"} 0
+# HELP namespace:kube_pod_status_phase:count Number of phase series per namespace.
+# TYPE namespace:kube_pod_status_phase:count gauge
+namespace:kube_pod_status_phase:count{namespace="a"} 3
+namespace:kube_pod_status_phase:count{namespace="b"} 2
+# HELP node:kube_node_info:count Count of kube_node_info by node.
+# TYPE node:kube_node_info:count gauge
+`
+
+	r, err := LoadConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		buf := &bytes.Buffer{}
+		w := r.NewWriter(buf)
+		// Written in two parts to split a line.
+		if _, err := w.Write([]byte(input[:100])); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(input[100:])); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("scrape %d: expected\n%s\ngot\n%s", i, want, got)
+		}
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	for _, config := range []string{
+		"rules:\n- record: a\n  family: b\n  op: avg\n",
+		"rules:\n- record: invalid-name\n  family: b\n  op: sum\n",
+		"rules:\n- record: a\n  family: b\n  op: sum\n  by: [invalid-label]\n",
+		"rules:\n- record: a\n  family: b\n  op: sum\n- record: a\n  family: c\n  op: sum\n",
+		"rules:\n- record: a\n  family: b\n  op: sum\n  unknown: field\n",
+	} {
+		if _, err := LoadConfig(strings.NewReader(config)); err == nil {
+			t.Errorf("expected error for config %q", config)
+		}
+	}
+}
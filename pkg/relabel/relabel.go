@@ -0,0 +1,313 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package relabel implements rules renaming, relabeling and dropping metric
+// families when they are written to a response.
+package relabel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+// Action is the action of a relabeling rule.
+type Action string
+
+const (
+	// DropFamily drops the families matching the rule.
+	DropFamily Action = "drop_family"
+	// RenameFamily renames the families matching the rule to the replacement.
+	RenameFamily Action = "rename_family"
+	// RenameLabel renames the label of the rule to the replacement in the
+	// families matching the rule.
+	RenameLabel Action = "rename_label"
+	// Replace sets the label of the rule to the replacement in the families
+	// matching the rule, if the current value of the label matches the value
+	// regex of the rule. A label set to an empty value is removed.
+	Replace Action = "replace"
+)
+
+// Config is the configuration of the relabeling rules.
+type Config struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is a relabeling rule. Rules are applied in order, each to the result of
+// the previous rules.
+type Rule struct {
+	Action Action `yaml:"action"`
+	// Family is a regex matched against the complete name of a family. Rules
+	// without a family apply to all families.
+	Family string `yaml:"family"`
+	// Label is the label of the rename_label and replace actions.
+	Label string `yaml:"label"`
+	// Value is a regex matched against the complete value of the label of the
+	// replace action. Rules without a value match any value.
+	Value string `yaml:"value"`
+	// Replacement is the new family name, label name or label value. For the
+	// rename_family and replace actions, it may refer to capture groups of
+	// the family or value regex, e.g. ${1}.
+	Replacement string `yaml:"replacement"`
+}
+
+type rule struct {
+	action      Action
+	family      *regexp.Regexp
+	label       string
+	value       *regexp.Regexp
+	replacement string
+}
+
+// family is the result of applying the rules to a family name.
+type family struct {
+	drop bool
+	name string
+	// rules are the label rules applying to the family.
+	rules []*rule
+}
+
+// Relabeler applies relabeling rules to metrics written in the text exposition
+// format.
+type Relabeler struct {
+	rules []*rule
+
+	mtx sync.RWMutex
+	// families caches the result of applying the rules to a family name.
+	families map[string]*family
+}
+
+// LoadConfig reads a relabeling configuration from r and returns its
+// Relabeler.
+func LoadConfig(r io.Reader) (*Relabeler, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	var c Config
+	if err := decoder.Decode(&c); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode relabel config: %w", err)
+	}
+	return New(c)
+}
+
+// New validates the given configuration and returns its Relabeler.
+func New(c Config) (*Relabeler, error) {
+	r := &Relabeler{families: map[string]*family{}}
+	for i, cr := range c.Rules {
+		rl := &rule{action: cr.Action, label: cr.Label, replacement: cr.Replacement}
+		var err error
+		if rl.family, err = anchoredRegexp(cr.Family); err != nil {
+			return nil, fmt.Errorf("rule %d: invalid family regex: %w", i, err)
+		}
+		if rl.value, err = anchoredRegexp(cr.Value); err != nil {
+			return nil, fmt.Errorf("rule %d: invalid value regex: %w", i, err)
+		}
+
+		switch cr.Action {
+		case DropFamily:
+		case RenameFamily:
+			if cr.Replacement == "" {
+				return nil, fmt.Errorf("rule %d: %s requires a replacement", i, cr.Action)
+			}
+		case RenameLabel:
+			if !model.LabelName(cr.Replacement).IsValidLegacy() {
+				return nil, fmt.Errorf("rule %d: invalid label name %q", i, cr.Replacement)
+			}
+			fallthrough
+		case Replace:
+			if !model.LabelName(cr.Label).IsValidLegacy() {
+				return nil, fmt.Errorf("rule %d: invalid label name %q", i, cr.Label)
+			}
+		default:
+			return nil, fmt.Errorf("rule %d: unknown action %q", i, cr.Action)
+		}
+		r.rules = append(r.rules, rl)
+	}
+	return r, nil
+}
+
+// anchoredRegexp compiles the regex matching complete strings. An empty regex
+// matches any string.
+func anchoredRegexp(re string) (*regexp.Regexp, error) {
+	if re == "" {
+		re = ".*"
+	}
+	return regexp.Compile("^(?:" + re + ")$")
+}
+
+// NewWriter returns a writer applying the relabeling rules to the metrics
+// written to w in the text exposition format. Flush has to be called once all
+// metrics are written.
+func (r *Relabeler) NewWriter(w io.Writer) *metricsstore.LineWriter {
+	return metricsstore.NewLineWriter(w, r.relabel)
+}
+
+// relabel appends the relabeled line to dst.
+func (r *Relabeler) relabel(dst, line []byte) []byte {
+	switch {
+	case bytes.HasPrefix(line, []byte("# HELP ")), bytes.HasPrefix(line, []byte("# TYPE ")):
+		rest := line[len("# HELP "):]
+		end := bytes.IndexByte(rest, ' ')
+		if end < 0 {
+			return append(dst, line...)
+		}
+		f := r.family(string(rest[:end]))
+		if f.drop {
+			return dst
+		}
+		dst = append(dst, line[:len("# HELP ")]...)
+		dst = append(dst, f.name...)
+		return append(dst, rest[end:]...)
+	case line[0] == '#' || line[0] == '\n':
+		return append(dst, line...)
+	}
+
+	end := bytes.IndexAny(line, "{ ")
+	if end <= 0 {
+		return append(dst, line...)
+	}
+	f := r.family(string(line[:end]))
+	if f.drop {
+		return dst
+	}
+	if len(f.rules) == 0 {
+		dst = append(dst, f.name...)
+		return append(dst, line[end:]...)
+	}
+
+	_, m, err := metric.ParseMetric(string(bytes.TrimSuffix(line, []byte("\n"))))
+	if err != nil {
+		// Not a valid series, which is written as is.
+		return append(dst, line...)
+	}
+	for _, rl := range f.rules {
+		rl.apply(m)
+	}
+	return append(dst, metric.Family{Name: f.name, Metrics: []*metric.Metric{m}}.ByteSlice()...)
+}
+
+// RelabelFamily applies the rules to the metric family f, e.g. before it is
+// exported in another format than the text exposition format. It returns
+// false if the family is dropped. The metrics of f are replaced by relabeled
+// copies instead of being modified.
+func (r *Relabeler) RelabelFamily(f *metric.Family) bool {
+	rf := r.family(f.Name)
+	if rf.drop {
+		return false
+	}
+	f.Name = rf.name
+	if len(rf.rules) == 0 {
+		return true
+	}
+	metrics := make([]*metric.Metric, len(f.Metrics))
+	for i, m := range f.Metrics {
+		c := &metric.Metric{
+			LabelKeys:   append([]string(nil), m.LabelKeys...),
+			LabelValues: append([]string(nil), m.LabelValues...),
+			Value:       m.Value,
+		}
+		for _, rl := range rf.rules {
+			rl.apply(c)
+		}
+		metrics[i] = c
+	}
+	f.Metrics = metrics
+	return true
+}
+
+// family returns the result of applying the rules to the family name.
+func (r *Relabeler) family(name string) *family {
+	r.mtx.RLock()
+	f, ok := r.families[name]
+	r.mtx.RUnlock()
+	if ok {
+		return f
+	}
+
+	f = &family{name: name}
+	for _, rl := range r.rules {
+		if !rl.family.MatchString(f.name) {
+			continue
+		}
+		switch rl.action {
+		case DropFamily:
+			f.drop = true
+		case RenameFamily:
+			f.name = rl.family.ReplaceAllString(f.name, rl.replacement)
+		default:
+			f.rules = append(f.rules, rl)
+		}
+		if f.drop {
+			break
+		}
+	}
+
+	r.mtx.Lock()
+	r.families[name] = f
+	r.mtx.Unlock()
+	return f
+}
+
+// apply applies the label rule to m.
+func (rl *rule) apply(m *metric.Metric) {
+	switch rl.action {
+	case RenameLabel:
+		for i, k := range m.LabelKeys {
+			if k == rl.label {
+				m.LabelKeys[i] = rl.replacement
+			}
+		}
+	case Replace:
+		i := labelIndex(m, rl.label)
+		var value string
+		if i < len(m.LabelKeys) {
+			value = m.LabelValues[i]
+		}
+		if !rl.value.MatchString(value) {
+			return
+		}
+		value = rl.value.ReplaceAllString(value, rl.replacement)
+		switch {
+		case value == "" && i < len(m.LabelKeys):
+			m.LabelKeys = append(m.LabelKeys[:i], m.LabelKeys[i+1:]...)
+			m.LabelValues = append(m.LabelValues[:i], m.LabelValues[i+1:]...)
+		case value == "":
+		case i < len(m.LabelKeys):
+			m.LabelValues[i] = value
+		default:
+			m.LabelKeys = append(m.LabelKeys, rl.label)
+			m.LabelValues = append(m.LabelValues, value)
+		}
+	}
+}
+
+// labelIndex returns the index of the label in m, or the number of labels of m
+// if it does not have the label.
+func labelIndex(m *metric.Metric, label string) int {
+	for i, k := range m.LabelKeys {
+		if k == label {
+			return i
+		}
+	}
+	return len(m.LabelKeys)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package relabel
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+const input = `# HELP kube_pod_info Information about pod.
+# TYPE kube_pod_info gauge
+kube_pod_info{namespace="default",pod="pod1",uid="u1",created_by_kind="ReplicaSet"} 1
+# HELP kube_pod_status_phase The pods current phase.
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="default",pod="pod1",phase="Running"} 1
+# HELP kube_pod_created Unix creation timestamp
+# TYPE kube_pod_created gauge
+kube_pod_created{namespace="default",pod="pod1"} 1.5e+09
+`
+
+func TestRelabeler(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name: "drop family",
+			config: `
+rules:
+- action: drop_family
+  family: kube_pod_(info|created)
+`,
+			want: `# HELP kube_pod_status_phase The pods current phase.
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="default",pod="pod1",phase="Running"} 1
+`,
+		},
+		{
+			name: "rename family and label",
+			config: `
+rules:
+- action: rename_family
+  family: kube_pod_(.*)
+  replacement: k8s_pod_${1}
+- action: rename_label
+  family: k8s_pod_status_phase
+  label: phase
+  replacement: pod_phase
+- action: drop_family
+  family: k8s_pod_(info|created)
+`,
+			want: `# HELP k8s_pod_status_phase The pods current phase.
+# TYPE k8s_pod_status_phase gauge
+k8s_pod_status_phase{namespace="default",pod="pod1",pod_phase="Running"} 1
+`,
+		},
+		{
+			name: "replace label values",
+			config: `
+rules:
+- action: replace
+  family: kube_pod_info
+  label: created_by_kind
+  value: Replica(.*)
+  replacement: ${1}
+- action: replace
+  label: uid
+  replacement: ""
+- action: replace
+  family: kube_pod_created
+  label: cluster
+  replacement: prod
+- action: drop_family
+  family: kube_pod_status_phase
+`,
+			want: `# HELP kube_pod_info Information about pod.
+# TYPE kube_pod_info gauge
+kube_pod_info{namespace="default",pod="pod1",created_by_kind="Set"} 1
+# HELP kube_pod_created Unix creation timestamp
+# TYPE kube_pod_created gauge
+kube_pod_created{namespace="default",pod="pod1",cluster="prod"} 1.5e+09
+`,
+		},
+	}
+
+	for _, test := range tests {
+		r, err := LoadConfig(strings.NewReader(test.config))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		buf := &bytes.Buffer{}
+		w := r.NewWriter(buf)
+		if _, err := w.Write([]byte(input)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.want, got)
+		}
+
+		// Relabeling families gives the same result.
+		families, err := metric.ParseFamilies(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Reset()
+		for _, f := range families {
+			if !r.RelabelFamily(f) {
+				continue
+			}
+			fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", f.Name, f.Help, f.Name, f.Type)
+			buf.Write(f.ByteSlice())
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s: expected relabeled families\n%s\ngot\n%s", test.name, test.want, got)
+		}
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	for _, config := range []string{
+		"rules:\n- action: unknown\n",
+		"rules:\n- action: rename_family\n  family: kube_pod_info\n",
+		"rules:\n- action: rename_label\n  label: pod\n  replacement: invalid-name\n",
+		"rules:\n- action: drop_family\n  family: \"(\"\n",
+		"rules:\n- action: drop_family\n  unknown: field\n",
+	} {
+		if _, err := LoadConfig(strings.NewReader(config)); err == nil {
+			t.Errorf("expected error for config %q", config)
+		}
+	}
+}
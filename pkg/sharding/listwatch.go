@@ -20,13 +20,32 @@ import (
 	"hash/fnv"
 
 	jump "github.com/dgryski/go-jump"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
 )
 
+// Strategy defines by which key objects are assigned to shards.
+type Strategy string
+
+const (
+	// StrategyUID assigns objects to shards by their UID.
+	StrategyUID Strategy = "uid"
+	// StrategyNamespace assigns objects to shards by their namespace, so that
+	// all objects of a namespace are assigned to the same shard. Namespaces
+	// are assigned by their name, which makes them land on the same shard as
+	// the objects within them. Other cluster-scoped objects are assigned by
+	// their UID.
+	StrategyNamespace Strategy = "namespace"
+)
+
+// Strategies are all available sharding strategies.
+var Strategies = []Strategy{StrategyUID, StrategyNamespace}
+
 type shardedListWatch struct {
 	sharding *sharding
 	lw       cache.ListerWatcher
@@ -35,13 +54,20 @@ type shardedListWatch struct {
 // NewShardedListWatch returns a new shardedListWatch via the cache.ListerWatcher interface.
 // In the case of no sharding needed, it returns the provided cache.ListerWatcher
 func NewShardedListWatch(shard int32, totalShards int, lw cache.ListerWatcher) cache.ListerWatcher {
+	return NewShardedListWatchWithStrategy(shard, totalShards, StrategyUID, lw)
+}
+
+// NewShardedListWatchWithStrategy returns a new shardedListWatch assigning
+// objects to shards with the given strategy.
+// In the case of no sharding needed, it returns the provided cache.ListerWatcher
+func NewShardedListWatchWithStrategy(shard int32, totalShards int, strategy Strategy, lw cache.ListerWatcher) cache.ListerWatcher {
 	// This is an "optimization" as this configuration means no sharding is to
 	// be performed.
 	if shard == 0 && totalShards == 1 {
 		return lw
 	}
 
-	return &shardedListWatch{sharding: &sharding{shard: shard, totalShards: totalShards}, lw: lw}
+	return &shardedListWatch{sharding: &sharding{shard: shard, totalShards: totalShards, strategy: strategy}, lw: lw}
 }
 
 func (s *shardedListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
@@ -94,10 +120,27 @@ func (s *shardedListWatch) Watch(options metav1.ListOptions) (watch.Interface, e
 type sharding struct {
 	shard       int32
 	totalShards int
+	strategy    Strategy
 }
 
 func (s *sharding) keep(o metav1.Object) bool {
+	namespace := o.GetNamespace()
+	if _, ok := o.(*v1.Namespace); ok {
+		namespace = o.GetName()
+	}
+	return ShardOf(s.strategy, o.GetUID(), namespace, s.totalShards) == s.shard
+}
+
+// ShardOf returns the shard an object with the given UID and namespace is
+// assigned to with the given strategy. The namespace of cluster-scoped
+// objects is empty, except for namespaces, which are expected to be passed
+// with their name.
+func ShardOf(strategy Strategy, uid types.UID, namespace string, totalShards int) int32 {
+	key := string(uid)
+	if strategy == StrategyNamespace && namespace != "" {
+		key = namespace
+	}
 	h := fnv.New64a()
-	h.Write([]byte(o.GetUID()))
-	return jump.Hash(h.Sum64(), s.totalShards) == s.shard
+	h.Write([]byte(key))
+	return jump.Hash(h.Sum64(), totalShards)
 }
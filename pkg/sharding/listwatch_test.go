@@ -54,3 +54,38 @@ func TestSharding(t *testing.T) {
 		t.Fatal("Shard two should not pick up the object.")
 	}
 }
+
+func TestNamespaceSharding(t *testing.T) {
+	objects := []metav1.Object{
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "configmap1", Namespace: "ns1", UID: types.UID("uid1")}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns1", UID: types.UID("uid2")}},
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "ns1", UID: types.UID("uid3")}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", UID: types.UID("uid4")}},
+	}
+
+	for _, totalShards := range []int{2, 3, 5} {
+		var owner int32 = -1
+		for shard := int32(0); shard < int32(totalShards); shard++ {
+			s := &sharding{shard: shard, totalShards: totalShards, strategy: StrategyNamespace}
+			for _, o := range objects {
+				if !s.keep(o) {
+					continue
+				}
+				if owner != -1 && owner != shard {
+					t.Fatalf("%d shards: objects of namespace ns1 are assigned to shards %d and %d", totalShards, owner, shard)
+				}
+				owner = shard
+			}
+		}
+		if owner == -1 {
+			t.Fatalf("%d shards: no shard picked up the objects", totalShards)
+		}
+	}
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", UID: types.UID("test_uid")}}
+	uid := &sharding{shard: 0, totalShards: 2}
+	namespace := &sharding{shard: 0, totalShards: 2, strategy: StrategyNamespace}
+	if uid.keep(node) != namespace.keep(node) {
+		t.Error("Cluster-scoped objects should be assigned by their UID.")
+	}
+}
@@ -26,11 +26,13 @@ const (
 	LabelOrdinal = "shard_ordinal"
 )
 
-// Metrics stores the pointers of kube_state_metrics_shard_ordinal
-// and kube_state_metrics_total_shards metrics.
+// Metrics stores the pointers of kube_state_metrics_shard_ordinal,
+// kube_state_metrics_total_shards and
+// kube_state_metrics_shard_last_rebalance_timestamp_seconds metrics.
 type Metrics struct {
-	Ordinal *prometheus.GaugeVec
-	Total   prometheus.Gauge
+	Ordinal       *prometheus.GaugeVec
+	Total         prometheus.Gauge
+	LastRebalance prometheus.Gauge
 }
 
 // NewShardingMetrics takes in a prometheus registry and initializes
@@ -49,5 +51,11 @@ func NewShardingMetrics(r prometheus.Registerer) *Metrics {
 				Help: "Number of total shards this instance is aware of",
 			},
 		),
+		LastRebalance: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Name: "kube_state_metrics_shard_last_rebalance_timestamp_seconds",
+				Help: "Unix timestamp of the last change of the shard ordinal or the total shards of this instance",
+			},
+		),
 	}
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package textfile implements periodically writing all metrics to a file in
+// the text exposition format, e.g. for the textfile collector of the
+// node_exporter.
+package textfile
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/klog/v2"
+)
+
+// Source writes all metrics in the text exposition format.
+type Source interface {
+	Write(w io.Writer) error
+}
+
+// Exporter periodically writes all metrics of a Source to a file.
+type Exporter struct {
+	path     string
+	interval time.Duration
+	source   Source
+
+	exportsTotal *prometheus.CounterVec
+}
+
+// NewExporter returns a new Exporter writing to the given path.
+func NewExporter(path string, interval time.Duration, source Source, r prometheus.Registerer) (*Exporter, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid textfile export interval %s", interval)
+	}
+	return &Exporter{
+		path:     path,
+		interval: interval,
+		source:   source,
+		exportsTotal: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_textfile_exports_total",
+				Help: "Number of total textfile exports in kube-state-metrics",
+			},
+			[]string{"result"},
+		),
+	}, nil
+}
+
+// Run writes all metrics every interval until the context is cancelled.
+func (e *Exporter) Run(ctx context.Context) error {
+	klog.InfoS("Started textfile exporter", "path", e.path, "interval", e.interval)
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := e.Export(); err != nil {
+				e.exportsTotal.WithLabelValues("error").Inc()
+				klog.ErrorS(err, "Failed to export metrics to textfile", "path", e.path)
+				continue
+			}
+			e.exportsTotal.WithLabelValues("success").Inc()
+		}
+	}
+}
+
+// Export writes all metrics of the source once. The file is replaced
+// atomically, so that readers never see a partially written file.
+func (e *Exporter) Export() error {
+	tmp, err := os.CreateTemp(filepath.Dir(e.path), filepath.Base(e.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create textfile: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	if err := e.source.Write(w); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write textfile: %w", err)
+	}
+	// The file is read by other processes, e.g. the node_exporter.
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write textfile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write textfile: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), e.path); err != nil {
+		return fmt.Errorf("failed to write textfile: %w", err)
+	}
+	return nil
+}
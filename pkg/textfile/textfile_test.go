@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package textfile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type sourceFunc func(w io.Writer) error
+
+func (f sourceFunc) Write(w io.Writer) error {
+	return f(w)
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kube-state-metrics.prom")
+	metrics := "# HELP kube_pod_info Information about pod.\n# TYPE kube_pod_info gauge\nkube_pod_info{pod=\"foo\"} 1\n"
+	var sourceErr error
+	source := sourceFunc(func(w io.Writer) error {
+		if _, err := io.WriteString(w, metrics); err != nil {
+			return err
+		}
+		return sourceErr
+	})
+
+	e, err := NewExporter(path, time.Minute, source, prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Export(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != metrics {
+		t.Errorf("expected %q, got %q", metrics, got)
+	}
+
+	// A failed export keeps the previous file.
+	sourceErr = errors.New("failed")
+	metrics = "partial"
+	if err := e.Export(); err == nil {
+		t.Fatal("expected export to fail")
+	}
+	got, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) == metrics {
+		t.Errorf("expected previous file to be kept, got %q", got)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected temporary files to be removed, got %d files", len(entries))
+	}
+}
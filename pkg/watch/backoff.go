@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+)
+
+// defaultBackoffReset is the duration without failures after which the backoff
+// of client-go is reset.
+const defaultBackoffReset = 2 * time.Minute
+
+// Backoff configures the exponential backoff of reflectors between failed list
+// and watch attempts. Each backoff is extended by a random jitter of up to its
+// duration. The zero value uses the backoff of client-go, which starts at 800ms
+// and is capped at 30s.
+type Backoff struct {
+	// Initial is the backoff after the first failure, which is doubled after
+	// each subsequent failure.
+	Initial time.Duration
+	// Max caps the backoff.
+	Max time.Duration
+	// Reset is the duration without failures after which the backoff is
+	// reset to Initial. Defaults to 2m.
+	Reset time.Duration
+}
+
+// RunReflector runs the reflector until stopCh is closed like
+// (*cache.Reflector).Run, but backs off between failed attempts to list and
+// watch as configured.
+func (b Backoff) RunReflector(r *cache.Reflector, stopCh <-chan struct{}) {
+	if b.Initial <= 0 {
+		r.Run(stopCh)
+		return
+	}
+	wait.BackoffUntil(func() {
+		if err := r.ListAndWatch(stopCh); err != nil {
+			cache.DefaultWatchErrorHandler(wait.ContextForChannel(stopCh), r, err)
+		}
+	}, b.manager(clock.RealClock{}), true, stopCh)
+}
+
+func (b Backoff) manager(c clock.Clock) wait.BackoffManager {
+	max := b.Max
+	if max < b.Initial {
+		max = b.Initial
+	}
+	reset := b.Reset
+	if reset <= 0 {
+		reset = defaultBackoffReset
+	}
+	return wait.NewExponentialBackoffManager(b.Initial, max, reset, 2.0, 1.0, c)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// PrunableStore is a store whose objects can be deleted by their id.
+type PrunableStore interface {
+	Synced() bool
+	UIDs() []types.UID
+	DeleteUID(uid types.UID) bool
+}
+
+// ListUIDsFunc lists the ids of all existing objects a store may contain.
+type ListUIDsFunc func(ctx context.Context) (map[types.UID]struct{}, error)
+
+// GarbageCollector prunes objects of a store which no longer exist, e.g. as
+// the delete watch event of the object was missed and the reflector did not
+// relist since. Objects are pruned once they are missing in two consecutive
+// lists, such that delete events which are in flight are not raced.
+type GarbageCollector struct {
+	store    PrunableStore
+	list     ListUIDsFunc
+	metrics  *ListWatchMetrics
+	resource string
+	// missing are the ids of the objects which were missing in the
+	// previous list.
+	missing map[types.UID]struct{}
+}
+
+// NewGarbageCollector returns a new GarbageCollector of the given store of
+// the given resource, which lists the existing objects with list.
+func NewGarbageCollector(store PrunableStore, list ListUIDsFunc, metrics *ListWatchMetrics, resource string) *GarbageCollector {
+	return &GarbageCollector{
+		store:    store,
+		list:     list,
+		metrics:  metrics,
+		resource: resource,
+	}
+}
+
+// Run collects garbage every interval until ctx is done.
+func (c *GarbageCollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := c.Collect(ctx); err != nil && ctx.Err() == nil {
+			klog.ErrorS(err, "Failed to list objects to prune stale objects", "resource", c.resource)
+		}
+	}
+}
+
+// Collect prunes the objects of the store which were missing in the
+// previous and the current list and returns their number. Stores which are
+// not synced yet are skipped.
+func (c *GarbageCollector) Collect(ctx context.Context) (int, error) {
+	if !c.store.Synced() {
+		return 0, nil
+	}
+	// Objects added to the store after the list started are not in the
+	// store yet, so they are never considered missing.
+	uids := c.store.UIDs()
+	existing, err := c.list(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	missing := map[types.UID]struct{}{}
+	pruned := 0
+	for _, uid := range uids {
+		if _, ok := existing[uid]; ok {
+			continue
+		}
+		if _, ok := c.missing[uid]; !ok {
+			missing[uid] = struct{}{}
+			continue
+		}
+		if c.store.DeleteUID(uid) {
+			pruned++
+		}
+	}
+	c.missing = missing
+	if pruned > 0 {
+		klog.InfoS("Pruned stale objects", "resource", c.resource, "count", pruned)
+		if c.metrics != nil {
+			c.metrics.StaleObjectsPruned.WithLabelValues(c.resource).Add(float64(pruned))
+		}
+	}
+	return pruned, nil
+}
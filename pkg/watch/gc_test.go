@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+func TestGarbageCollector(t *testing.T) {
+	store := metricsstore.NewMetricsStore([]string{}, func(obj interface{}) []metric.FamilyInterface {
+		return nil
+	})
+	pod := func(uid types.UID) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: string(uid), UID: uid}}
+	}
+	var list []interface{}
+	for _, uid := range []types.UID{"a", "b", "ghost"} {
+		list = append(list, pod(uid))
+	}
+	if err := store.Replace(list, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := map[types.UID]struct{}{"a": {}, "b": {}}
+	metrics := NewListWatchMetrics(prometheus.NewRegistry())
+	gc := NewGarbageCollector(store, func(ctx context.Context) (map[types.UID]struct{}, error) {
+		return existing, nil
+	}, metrics, "*v1.Pod")
+
+	if pruned, err := gc.Collect(context.Background()); err != nil || pruned != 0 {
+		t.Fatalf("expected objects missing in a single list not to be pruned, got %d, %v", pruned, err)
+	}
+	// b is deleted and its delete event is only received after the next list.
+	delete(existing, "b")
+	if pruned, err := gc.Collect(context.Background()); err != nil || pruned != 1 {
+		t.Fatalf("expected the object missing in two lists to be pruned, got %d, %v", pruned, err)
+	}
+	if err := store.Delete(pod("b")); err != nil {
+		t.Fatal(err)
+	}
+	if pruned, err := gc.Collect(context.Background()); err != nil || pruned != 0 {
+		t.Fatalf("expected deleted objects not to be pruned, got %d, %v", pruned, err)
+	}
+
+	if uids := store.UIDs(); len(uids) != 1 || uids[0] != "a" {
+		t.Errorf("expected only the existing object to be left, got %v", uids)
+	}
+	if got := testutil.ToFloat64(metrics.StaleObjectsPruned.WithLabelValues("*v1.Pod")); got != 1 {
+		t.Errorf("expected 1 pruned object to be counted, got %v", got)
+	}
+}
+
+func TestGarbageCollectorSkipsUnsyncedStores(t *testing.T) {
+	store := metricsstore.NewMetricsStore([]string{}, func(obj interface{}) []metric.FamilyInterface {
+		return nil
+	})
+	listed := false
+	gc := NewGarbageCollector(store, func(ctx context.Context) (map[types.UID]struct{}, error) {
+		listed = true
+		return nil, nil
+	}, nil, "*v1.Pod")
+	if _, err := gc.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if listed {
+		t.Error("expected unsynced stores not to be listed")
+	}
+}
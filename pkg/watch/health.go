@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Health tracks whether the list and watch requests of reflectors succeed.
+// A resource is stale if the requests of any of its reflectors have been
+// failing for longer than a threshold.
+type Health struct {
+	threshold time.Duration
+	now       func() time.Time
+
+	mtx      sync.Mutex
+	trackers map[*HealthTracker]struct{}
+}
+
+// NewHealth returns a new Health considering resources stale after their
+// requests have been failing for longer than the given threshold.
+func NewHealth(threshold time.Duration) *Health {
+	return &Health{
+		threshold: threshold,
+		now:       time.Now,
+		trackers:  map[*HealthTracker]struct{}{},
+	}
+}
+
+// Threshold returns the duration after which a resource is stale. Staleness
+// tracking is disabled if it is 0.
+func (h *Health) Threshold() time.Duration {
+	return h.threshold
+}
+
+// Track returns a HealthTracker for a reflector of the given resource. It is
+// tracked until ctx is done.
+func (h *Health) Track(ctx context.Context, resource string) *HealthTracker {
+	t := &HealthTracker{resource: resource, now: h.now}
+	h.mtx.Lock()
+	h.trackers[t] = struct{}{}
+	h.mtx.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mtx.Lock()
+		delete(h.trackers, t)
+		h.mtx.Unlock()
+	}()
+	return t
+}
+
+// Stale returns whether each tracked resource is stale.
+func (h *Health) Stale() map[string]bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	now := h.now()
+	stale := make(map[string]bool, len(h.trackers))
+	for t := range h.trackers {
+		failingSince := t.failingSinceTime()
+		stale[t.resource] = stale[t.resource] || (!failingSince.IsZero() && now.Sub(failingSince) > h.threshold)
+	}
+	return stale
+}
+
+// LastErrors returns the last error of a list or watch request of each
+// tracked resource which had a failed request.
+func (h *Health) LastErrors() map[string]RequestError {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	errs := map[string]RequestError{}
+	for t := range h.trackers {
+		last := t.lastErr()
+		if last.Time.IsZero() {
+			continue
+		}
+		if prev, ok := errs[t.resource]; !ok || last.Time.After(prev.Time) {
+			errs[t.resource] = last
+		}
+	}
+	return errs
+}
+
+// RequestError is a failed list or watch request.
+type RequestError struct {
+	Message string
+	Time    time.Time
+}
+
+// HealthTracker tracks whether the requests of a single reflector succeed.
+type HealthTracker struct {
+	resource string
+	now      func() time.Time
+
+	mtx sync.Mutex
+	// failingSince is the time of the first failed request since the last
+	// successful one. It is zero if the last request succeeded.
+	failingSince time.Time
+	// lastError is the last failed request, which is kept once requests
+	// succeed again.
+	lastError RequestError
+}
+
+func (t *HealthTracker) observe(err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if err == nil {
+		t.failingSince = time.Time{}
+		return
+	}
+	now := t.now()
+	t.lastError = RequestError{Message: err.Error(), Time: now}
+	if t.failingSince.IsZero() {
+		t.failingSince = now
+	}
+}
+
+func (t *HealthTracker) lastErr() RequestError {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.lastError
+}
+
+func (t *HealthTracker) failingSinceTime() time.Time {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.failingSince
+}
+
+type healthTrackingListerWatcher struct {
+	lw      cache.ListerWatcher
+	tracker *HealthTracker
+}
+
+// NewHealthTrackingListerWatcher returns a cache.ListerWatcher reporting the
+// outcome of all list and watch requests to the given HealthTracker.
+func NewHealthTrackingListerWatcher(lw cache.ListerWatcher, tracker *HealthTracker) cache.ListerWatcher {
+	return &healthTrackingListerWatcher{lw: lw, tracker: tracker}
+}
+
+// List is a wrapper func around the cache.ListerWatcher.List func.
+func (h *healthTrackingListerWatcher) List(options metav1.ListOptions) (runtime.Object, error) {
+	res, err := h.lw.List(options)
+	h.tracker.observe(err)
+	return res, err
+}
+
+// Watch is a wrapper func around the cache.ListerWatcher.Watch func.
+func (h *healthTrackingListerWatcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	res, err := h.lw.Watch(options)
+	h.tracker.observe(err)
+	return res, err
+}
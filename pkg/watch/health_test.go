@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestHealth(t *testing.T) {
+	now := time.Now()
+	h := NewHealth(time.Minute)
+	h.now = func() time.Time { return now }
+
+	var listErr error
+	newLW := func(tracker *HealthTracker) cache.ListerWatcher {
+		return NewHealthTrackingListerWatcher(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return &v1.PodList{}, listErr
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}, tracker)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pods1 := newLW(h.Track(ctx, "*v1.Pod"))
+	pods2 := newLW(h.Track(ctx, "*v1.Pod"))
+	nodes := newLW(h.Track(context.Background(), "*v1.Node"))
+
+	for _, lw := range []cache.ListerWatcher{pods1, pods2, nodes} {
+		lw.List(metav1.ListOptions{}) //nolint:errcheck
+	}
+	if got, want := h.Stale(), map[string]bool{"*v1.Pod": false, "*v1.Node": false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// A single failing reflector makes the resource stale once the threshold is exceeded.
+	listErr = errors.New("connection refused")
+	pods2.List(metav1.ListOptions{}) //nolint:errcheck
+	now = now.Add(30 * time.Second)
+	pods2.List(metav1.ListOptions{}) //nolint:errcheck
+	if h.Stale()["*v1.Pod"] {
+		t.Error("expected pods not to be stale before the threshold is exceeded")
+	}
+	now = now.Add(31 * time.Second)
+	if got, want := h.Stale(), map[string]bool{"*v1.Pod": true, "*v1.Node": false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// A successful request recovers the resource.
+	if _, err := pods2.Watch(metav1.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if h.Stale()["*v1.Pod"] {
+		t.Error("expected pods not to be stale after a successful request")
+	}
+	// The last error is kept after recovering.
+	if got, want := h.LastErrors(), map[string]RequestError{"*v1.Pod": {Message: "connection refused", Time: now.Add(-31 * time.Second)}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// Trackers of stopped reflectors are removed.
+	cancel()
+	if err := waitFor(func() bool { _, ok := h.Stale()["*v1.Pod"]; return !ok }); err != nil {
+		t.Error("expected trackers of stopped reflectors to be removed")
+	}
+}
+
+func waitFor(cond func() bool) error {
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return errors.New("timed out")
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// StartupLimiter paces the initial lists of reflectors, so that starting
+// kube-state-metrics in large clusters does not exceed the API Priority and
+// Fairness limits of the apiserver. A nil StartupLimiter does not limit the
+// initial lists.
+type StartupLimiter struct {
+	// slots limits the number of concurrent initial lists. It is nil if
+	// their number is not limited.
+	slots  chan struct{}
+	jitter time.Duration
+}
+
+// NewStartupLimiter returns a StartupLimiter allowing the given number of
+// concurrent initial lists, which are each delayed by a random duration of up
+// to the given jitter. The number of concurrent lists is not limited if
+// concurrency is 0.
+func NewStartupLimiter(concurrency int, jitter time.Duration) *StartupLimiter {
+	l := &StartupLimiter{jitter: jitter}
+	if concurrency > 0 {
+		l.slots = make(chan struct{}, concurrency)
+	}
+	return l
+}
+
+// acquire waits for the jitter and a free slot, or until ctx is done.
+func (l *StartupLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if l.jitter > 0 {
+		select {
+		case <-time.After(time.Duration(rand.Int63n(int64(l.jitter)))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if l.slots == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *StartupLimiter) release() {
+	if l == nil || l.slots == nil {
+		return
+	}
+	<-l.slots
+}
+
+// startupListerWatcher paces the initial list of a reflector with a
+// StartupLimiter and records its duration. The initial list may consist of
+// multiple pages.
+type startupListerWatcher struct {
+	lw       cache.ListerWatcher
+	ctx      context.Context
+	limiter  *StartupLimiter
+	metrics  *ListWatchMetrics
+	resource string
+
+	// listing is true while the pages of the initial list are requested.
+	listing bool
+	listed  bool
+	start   time.Time
+}
+
+// NewStartupListerWatcher returns a ListerWatcher pacing the initial list of lw
+// with the given StartupLimiter and recording its duration in the
+// kube_state_metrics_initial_list_duration_seconds metric of the resource.
+// Lists after the initial list are not paced.
+func NewStartupListerWatcher(ctx context.Context, lw cache.ListerWatcher, limiter *StartupLimiter, metrics *ListWatchMetrics, resource string) cache.ListerWatcher {
+	return &startupListerWatcher{lw: lw, ctx: ctx, limiter: limiter, metrics: metrics, resource: resource}
+}
+
+// List implements the cache.ListerWatcher interface. The reflector lists
+// sequentially, so no locking is required.
+func (s *startupListerWatcher) List(options metav1.ListOptions) (runtime.Object, error) {
+	if s.listed {
+		return s.lw.List(options)
+	}
+	if !s.listing {
+		if err := s.limiter.acquire(s.ctx); err != nil {
+			return nil, err
+		}
+		s.listing = true
+		s.start = time.Now()
+	}
+
+	res, err := s.lw.List(options)
+	if err == nil {
+		if m, metaErr := meta.ListAccessor(res); metaErr == nil && m.GetContinue() != "" {
+			// Further pages follow.
+			return res, nil
+		}
+		s.listed = true
+		if s.metrics != nil && s.metrics.InitialListDuration != nil {
+			s.metrics.InitialListDuration.WithLabelValues(s.resource).Set(time.Since(s.start).Seconds())
+		}
+	}
+	// Failed initial lists are retried with a new slot after the backoff of
+	// the reflector.
+	s.listing = false
+	s.limiter.release()
+	return res, err
+}
+
+// Watch implements the cache.ListerWatcher interface.
+func (s *startupListerWatcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return s.lw.Watch(options)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestStartupListerWatcher(t *testing.T) {
+	metrics := NewListWatchMetrics(prometheus.NewRegistry())
+	limiter := NewStartupLimiter(1, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Each list consists of two pages.
+	newLW := func(resource string) cache.ListerWatcher {
+		return NewStartupListerWatcher(ctx, &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				list := &v1.PodList{}
+				if options.Continue == "" {
+					list.Continue = "next"
+				}
+				return list, nil
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}, limiter, metrics, resource)
+	}
+	pods, nodes := newLW("*v1.Pod"), newLW("*v1.Node")
+
+	if _, err := pods.List(metav1.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	listed := make(chan struct{})
+	go func() {
+		defer close(listed)
+		if _, err := nodes.List(metav1.ListOptions{}); err != nil {
+			t.Error(err)
+		}
+	}()
+	select {
+	case <-listed:
+		t.Fatal("expected the initial list of nodes to wait for the one of pods")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if _, err := pods.List(metav1.ListOptions{Continue: "next"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-listed:
+	case <-time.After(time.Second):
+		t.Fatal("expected the initial list of nodes to start once the one of pods completed")
+	}
+	if got := testutil.CollectAndCount(metrics.InitialListDuration); got != 1 {
+		t.Errorf("expected the initial list duration of 1 resource, got %d", got)
+	}
+
+	// Relists are not limited, even though the initial list of nodes has
+	// not completed.
+	if _, err := pods.List(metav1.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	if _, err := newLW("*v1.Service").List(metav1.ListOptions{}); err == nil {
+		t.Error("expected the initial list to fail once the context is done")
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// tweakedListerWatcher modifies the options of all list and watch requests.
+type tweakedListerWatcher struct {
+	lw    cache.ListerWatcher
+	tweak func(*metav1.ListOptions)
+}
+
+// NewTweakedListerWatcher returns a cache.ListerWatcher calling tweak with the
+// options of each list and watch request before it is sent, e.g. to add a
+// label selector.
+func NewTweakedListerWatcher(lw cache.ListerWatcher, tweak func(*metav1.ListOptions)) cache.ListerWatcher {
+	return &tweakedListerWatcher{lw: lw, tweak: tweak}
+}
+
+// List is a wrapper func around the cache.ListerWatcher.List func.
+func (t *tweakedListerWatcher) List(options metav1.ListOptions) (runtime.Object, error) {
+	t.tweak(&options)
+	return t.lw.List(options)
+}
+
+// Watch is a wrapper func around the cache.ListerWatcher.Watch func.
+func (t *tweakedListerWatcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	t.tweak(&options)
+	return t.lw.Watch(options)
+}
@@ -17,25 +17,77 @@ limitations under the License.
 package watch
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/kube-state-metrics/v2/pkg/otlp"
 )
 
-// ListWatchMetrics stores the pointers of kube_state_metrics_[list|watch]_total metrics.
+// ListWatchMetrics stores the pointers of kube_state_metrics_[list|watch]_total,
+// kube_state_metrics_list_watch_errors_total,
+// kube_state_metrics_watch_errors_total,
+// kube_state_metrics_last_successful_list_timestamp_seconds,
+// kube_state_metrics_stale_objects_pruned_total and
+// kube_state_metrics_initial_list_duration_seconds metrics.
 type ListWatchMetrics struct {
-	WatchTotal *prometheus.CounterVec
-	ListTotal  *prometheus.CounterVec
+	WatchTotal          *prometheus.CounterVec
+	ListTotal           *prometheus.CounterVec
+	ErrorsTotal         *prometheus.CounterVec
+	WatchErrorsTotal    *prometheus.CounterVec
+	LastSuccessfulList  *prometheus.GaugeVec
+	StaleObjectsPruned  *prometheus.CounterVec
+	InitialListDuration *prometheus.GaugeVec
 }
 
 // NewListWatchMetrics takes in a prometheus registry and initializes
-// and registers the kube_state_metrics_list_total and
-// kube_state_metrics_watch_total metrics. It returns those registered metrics.
+// and registers the kube_state_metrics_list_total,
+// kube_state_metrics_watch_total, kube_state_metrics_list_watch_errors_total,
+// kube_state_metrics_watch_errors_total,
+// kube_state_metrics_last_successful_list_timestamp_seconds,
+// kube_state_metrics_stale_objects_pruned_total and
+// kube_state_metrics_initial_list_duration_seconds metrics. It returns those registered metrics.
 func NewListWatchMetrics(r prometheus.Registerer) *ListWatchMetrics {
 	return &ListWatchMetrics{
+		ErrorsTotal: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_list_watch_errors_total",
+				Help: "Number of failed list and watch requests in kube-state-metrics by group, version and resource, and the reason of the error",
+			},
+			[]string{"operation", "group", "version", "resource", "reason"},
+		),
+		WatchErrorsTotal: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_watch_errors_total",
+				Help: "Number of failed watch requests and errors received on established watches in kube-state-metrics by resource and the reason of the error",
+			},
+			[]string{"resource", "reason"},
+		),
+		LastSuccessfulList: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kube_state_metrics_last_successful_list_timestamp_seconds",
+				Help: "Unix timestamp of the last completed list of all pages of a reflector by group, version and resource",
+			},
+			[]string{"group", "version", "resource"},
+		),
+		StaleObjectsPruned: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_stale_objects_pruned_total",
+				Help: "Number of objects pruned from the stores of the resource as they no longer existed, e.g. as their delete watch event was missed",
+			},
+			[]string{"resource"},
+		),
 		WatchTotal: promauto.With(r).NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "kube_state_metrics_watch_total",
@@ -50,6 +102,13 @@ func NewListWatchMetrics(r prometheus.Registerer) *ListWatchMetrics {
 			},
 			[]string{"result", "resource"},
 		),
+		InitialListDuration: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kube_state_metrics_initial_list_duration_seconds",
+				Help: "Duration of the last completed initial list of a reflector of the resource in seconds, including all pages and excluding the time waiting for the startup limits",
+			},
+			[]string{"resource"},
+		),
 	}
 }
 
@@ -59,16 +118,27 @@ type InstrumentedListerWatcher struct {
 	lw                cache.ListerWatcher
 	metrics           *ListWatchMetrics
 	resource          string
+	gvr               schema.GroupVersionResource
 	useAPIServerCache bool
+	tracer            trace.Tracer
 }
 
 // NewInstrumentedListerWatcher returns a new InstrumentedListerWatcher.
 func NewInstrumentedListerWatcher(lw cache.ListerWatcher, metrics *ListWatchMetrics, resource string, useAPIServerCache bool) cache.ListerWatcher {
+	return NewInstrumentedListerWatcherForGVR(lw, metrics, resource, schema.GroupVersionResource{}, useAPIServerCache, noop.NewTracerProvider().Tracer(""))
+}
+
+// NewInstrumentedListerWatcherForGVR returns a new InstrumentedListerWatcher,
+// which additionally counts errors by the given group, version and resource
+// and traces the list and watch requests with the given tracer.
+func NewInstrumentedListerWatcherForGVR(lw cache.ListerWatcher, metrics *ListWatchMetrics, resource string, gvr schema.GroupVersionResource, useAPIServerCache bool, tracer trace.Tracer) cache.ListerWatcher {
 	return &InstrumentedListerWatcher{
 		lw:                lw,
 		metrics:           metrics,
 		resource:          resource,
+		gvr:               gvr,
 		useAPIServerCache: useAPIServerCache,
+		tracer:            tracer,
 	}
 }
 
@@ -80,25 +150,89 @@ func (i *InstrumentedListerWatcher) List(options metav1.ListOptions) (res runtim
 		options.ResourceVersion = "0"
 	}
 
+	span := i.startSpan("list", options)
+	defer span.End()
+
 	res, err = i.lw.List(options)
 	if err != nil {
+		otlp.SetSpanError(span, err)
 		i.metrics.ListTotal.WithLabelValues("error", i.resource).Inc()
+		i.countError("list", err)
 		return
 	}
 
+	span.SetAttributes(attribute.Int("k8s.list.items", meta.LenList(res)))
 	i.metrics.ListTotal.WithLabelValues("success", i.resource).Inc()
+	if i.metrics.LastSuccessfulList != nil {
+		if list, err := meta.ListAccessor(res); err == nil && list.GetContinue() == "" {
+			i.metrics.LastSuccessfulList.WithLabelValues(i.gvr.Group, i.gvr.Version, i.gvr.Resource).SetToCurrentTime()
+		}
+	}
 	return
 }
 
 // Watch is a wrapper func around the cache.ListerWatcher.Watch func. It increases the success/error
 // counters based on the outcome of the Watch operation it instruments.
 func (i *InstrumentedListerWatcher) Watch(options metav1.ListOptions) (res watch.Interface, err error) {
+	span := i.startSpan("watch", options)
+	defer span.End()
+
 	res, err = i.lw.Watch(options)
 	if err != nil {
+		otlp.SetSpanError(span, err)
 		i.metrics.WatchTotal.WithLabelValues("error", i.resource).Inc()
+		i.countError("watch", err)
+		i.countWatchError(err)
 		return
 	}
 
 	i.metrics.WatchTotal.WithLabelValues("success", i.resource).Inc()
+	if i.metrics.WatchErrorsTotal != nil {
+		res = watch.Filter(res, func(event watch.Event) (watch.Event, bool) {
+			if event.Type == watch.Error {
+				i.countWatchError(apierrors.FromObject(event.Object))
+			}
+			return event, true
+		})
+	}
 	return
 }
+
+// startSpan starts the span of a list or watch request.
+func (i *InstrumentedListerWatcher) startSpan(operation string, options metav1.ListOptions) trace.Span {
+	_, span := i.tracer.Start(context.Background(), operation+" "+i.resource, trace.WithAttributes(
+		attribute.String("k8s.resource", i.resource),
+		attribute.String("k8s.group", i.gvr.Group),
+		attribute.String("k8s.version", i.gvr.Version),
+		attribute.String("k8s.resource_version", options.ResourceVersion),
+	))
+	return span
+}
+
+// countWatchError increases the watch error counter by the reason of the
+// error, e.g. Expired if the watched resource version is too old.
+func (i *InstrumentedListerWatcher) countWatchError(err error) {
+	if i.metrics.WatchErrorsTotal == nil {
+		return
+	}
+	i.metrics.WatchErrorsTotal.WithLabelValues(i.resource, errorReason(err)).Inc()
+}
+
+// countError increases the error counter of the operation by the reason of the
+// error, e.g. Forbidden or Timeout.
+func (i *InstrumentedListerWatcher) countError(operation string, err error) {
+	if i.metrics.ErrorsTotal == nil {
+		return
+	}
+	i.metrics.ErrorsTotal.WithLabelValues(operation, i.gvr.Group, i.gvr.Version, i.gvr.Resource, errorReason(err)).Inc()
+}
+
+// errorReason returns the reason of an apiserver error, or Unknown for other
+// errors.
+func errorReason(err error) string {
+	reason := string(apierrors.ReasonForError(err))
+	if reason == "" {
+		reason = "Unknown"
+	}
+	return reason
+}
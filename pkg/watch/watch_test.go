@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/trace/noop"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestInstrumentedListerWatcherWatchErrors(t *testing.T) {
+	metrics := NewListWatchMetrics(prometheus.NewRegistry())
+	fake := watch.NewFake()
+	failing := true
+	lw := NewInstrumentedListerWatcherForGVR(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			list := &v1.PodList{}
+			if options.Continue == "" {
+				list.Continue = "next"
+			}
+			return list, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			if failing {
+				return nil, errors.New("connection refused")
+			}
+			return fake, nil
+		},
+	}, metrics, "*v1.Pod", schema.GroupVersionResource{Version: "v1", Resource: "pods"}, false, noop.NewTracerProvider().Tracer(""))
+
+	if _, err := lw.List(metav1.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.CollectAndCount(metrics.LastSuccessfulList); got != 0 {
+		t.Errorf("expected no successful list before the last page was listed, got %d", got)
+	}
+	if _, err := lw.List(metav1.ListOptions{Continue: "next"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(metrics.LastSuccessfulList.WithLabelValues("", "v1", "pods")); got <= 0 {
+		t.Errorf("expected the timestamp of the last successful list, got %v", got)
+	}
+
+	if _, err := lw.Watch(metav1.ListOptions{}); err == nil {
+		t.Fatal("expected the watch to fail")
+	}
+	failing = false
+	w, err := lw.Watch(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go fake.Error(&apierrors.NewResourceExpired("too old resource version").ErrStatus)
+	if event := <-w.ResultChan(); event.Type != watch.Error {
+		t.Errorf("expected the error event to be passed through, got %s", event.Type)
+	}
+	w.Stop()
+
+	for reason, want := range map[string]float64{"Unknown": 1, "Expired": 1} {
+		if got := testutil.ToFloat64(metrics.WatchErrorsTotal.WithLabelValues("*v1.Pod", reason)); got != want {
+			t.Errorf("expected %v watch errors with reason %s, got %v", want, reason, got)
+		}
+	}
+}
+
+func TestBackoffManager(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	manager := Backoff{Initial: time.Second, Max: 4 * time.Second}.manager(clock)
+
+	// The backoff is jittered by up to its duration.
+	for _, base := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		timer := manager.Backoff()
+		clock.Step(base - time.Nanosecond)
+		select {
+		case <-timer.C():
+			t.Fatalf("expected the backoff to be at least %s", base)
+		default:
+		}
+		clock.Step(base)
+		select {
+		case <-timer.C():
+		default:
+			t.Fatalf("expected the backoff to be at most %s", 2*base)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// initialEventsEndAnnotation marks the bookmark event which is sent once all
+// initial events of a watch list request have been sent.
+const initialEventsEndAnnotation = "k8s.io/initial-events-end"
+
+// errWatchListUnsupported is returned if the apiserver did not respond to a
+// watch list request as expected, e.g. as the feature is disabled.
+var errWatchListUnsupported = errors.New("watch list is not supported")
+
+// WatchListTransport returns a http.RoundTripper requesting the initial
+// events of watch requests created by a watch list ListerWatcher. As the
+// ListOptions of the used client-go version do not support the
+// sendInitialEvents parameter, it is added to all watch requests with
+// resourceVersionMatch=NotOlderThan, which is otherwise invalid for watches.
+func WatchListTransport(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		q := req.URL.Query()
+		if q.Get("watch") != "true" || q.Get("resourceVersionMatch") != string(metav1.ResourceVersionMatchNotOlderThan) || q.Get("sendInitialEvents") != "" {
+			return rt.RoundTrip(req)
+		}
+		req = req.Clone(req.Context())
+		q.Set("sendInitialEvents", "true")
+		req.URL.RawQuery = q.Encode()
+		return rt.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// watchListListerWatcher lists objects by streaming them via a watch request
+// instead of a list request, which avoids large list responses having to be
+// buffered by the apiserver. The clients used by the wrapped ListerWatcher
+// must use the WatchListTransport.
+type watchListListerWatcher struct {
+	lw cache.ListerWatcher
+	// fallback is set to 1 once the apiserver rejected a watch list request.
+	fallback int32
+}
+
+// NewWatchListListerWatcher returns a cache.ListerWatcher listing objects
+// via the streaming watch list feature. If the apiserver does not support it,
+// regular list requests are used.
+func NewWatchListListerWatcher(lw cache.ListerWatcher) cache.ListerWatcher {
+	return &watchListListerWatcher{lw: lw}
+}
+
+// List streams all objects via a watch request. It falls back to a regular
+// list request if the apiserver does not support watch list.
+func (w *watchListListerWatcher) List(options metav1.ListOptions) (runtime.Object, error) {
+	if atomic.LoadInt32(&w.fallback) == 1 {
+		return w.lw.List(options)
+	}
+	list, err := w.watchList(options)
+	if err == nil {
+		return list, nil
+	}
+	if !errors.Is(err, errWatchListUnsupported) && !apierrors.IsBadRequest(err) && !apierrors.IsInvalid(err) {
+		return nil, err
+	}
+	klog.InfoS("Watch list is not supported, falling back to list requests", "err", err)
+	atomic.StoreInt32(&w.fallback, 1)
+	return w.lw.List(options)
+}
+
+func (w *watchListListerWatcher) watchList(options metav1.ListOptions) (runtime.Object, error) {
+	watcher, err := w.lw.Watch(metav1.ListOptions{
+		LabelSelector:        options.LabelSelector,
+		FieldSelector:        options.FieldSelector,
+		ResourceVersion:      options.ResourceVersion,
+		ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+		AllowWatchBookmarks:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.Stop()
+
+	list := &metav1.List{Items: []runtime.RawExtension{}}
+	for event := range watcher.ResultChan() {
+		switch event.Type {
+		case watch.Added:
+			list.Items = append(list.Items, runtime.RawExtension{Object: event.Object})
+		case watch.Bookmark:
+			a, err := meta.Accessor(event.Object)
+			if err != nil {
+				return nil, err
+			}
+			if a.GetAnnotations()[initialEventsEndAnnotation] == "true" {
+				list.ResourceVersion = a.GetResourceVersion()
+				return list, nil
+			}
+		case watch.Error:
+			return nil, apierrors.FromObject(event.Object)
+		default:
+			return nil, fmt.Errorf("%w: unexpected %s event", errWatchListUnsupported, event.Type)
+		}
+	}
+	return nil, fmt.Errorf("%w: watch closed before all initial events were received", errWatchListUnsupported)
+}
+
+// Watch is a wrapper func around the cache.ListerWatcher.Watch func.
+func (w *watchListListerWatcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return w.lw.Watch(options)
+}
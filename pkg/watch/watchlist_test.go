@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"net/http"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestWatchListListerWatcher(t *testing.T) {
+	var watchOptions metav1.ListOptions
+	lists := 0
+	lw := NewWatchListListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			lists++
+			return &v1.ConfigMapList{}, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			watchOptions = options
+			w := watch.NewFakeWithChanSize(3, false)
+			w.Add(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a"}})
+			w.Add(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b"}})
+			w.Action(watch.Bookmark, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				ResourceVersion: "42",
+				Annotations:     map[string]string{initialEventsEndAnnotation: "true"},
+			}})
+			return w, nil
+		},
+	})
+
+	list, err := lw.List(metav1.ListOptions{FieldSelector: "metadata.name!=c", ResourceVersion: "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watchOptions.ResourceVersionMatch != metav1.ResourceVersionMatchNotOlderThan || !watchOptions.AllowWatchBookmarks ||
+		watchOptions.FieldSelector != "metadata.name!=c" || watchOptions.ResourceVersion != "0" {
+		t.Errorf("unexpected watch options %+v", watchOptions)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected 2 items, got %d", len(items))
+	}
+	listMeta, err := meta.ListAccessor(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listMeta.GetResourceVersion() != "42" {
+		t.Errorf("expected resource version 42, got %q", listMeta.GetResourceVersion())
+	}
+	if lists != 0 {
+		t.Errorf("expected no list requests, got %d", lists)
+	}
+}
+
+func TestWatchListListerWatcherFallback(t *testing.T) {
+	lists, watches := 0, 0
+	lw := NewWatchListListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			lists++
+			return &v1.ConfigMapList{}, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			watches++
+			return nil, apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "", field.ErrorList{
+				field.Forbidden(field.NewPath("resourceVersionMatch"), "resourceVersionMatch is forbidden for watch"),
+			})
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := lw.List(metav1.ListOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if watches != 1 || lists != 2 {
+		t.Errorf("expected a single watch list attempt and 2 list requests, got %d and %d", watches, lists)
+	}
+}
+
+func TestWatchListTransport(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{
+			url:  "https://apiserver/api/v1/configmaps?allowWatchBookmarks=true&resourceVersionMatch=NotOlderThan&watch=true",
+			want: "allowWatchBookmarks=true&resourceVersionMatch=NotOlderThan&sendInitialEvents=true&watch=true",
+		},
+		{
+			url:  "https://apiserver/api/v1/configmaps?watch=true",
+			want: "watch=true",
+		},
+		{
+			url:  "https://apiserver/api/v1/configmaps?limit=500&resourceVersionMatch=NotOlderThan",
+			want: "limit=500&resourceVersionMatch=NotOlderThan",
+		},
+	}
+
+	for _, test := range tests {
+		var got string
+		rt := WatchListTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			got = req.URL.RawQuery
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}))
+		req, err := http.NewRequest(http.MethodGet, test.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%s: expected query %q, got %q", test.url, test.want, got)
+		}
+	}
+}
@@ -254,19 +254,41 @@ func TestDefaultCollectorMetricsAvailable(t *testing.T) {
 
 	resources := map[string]struct{}{}
 	nonDefaultResources := map[string]bool{
-		"clusterrole":           true,
-		"clusterrolebinding":    true,
-		"endpointslice":         true,
-		"ingressclass":          true,
-		"role":                  true,
-		"rolebinding":           true,
-		"serviceaccount":        true,
-		"verticalpodautoscaler": true,
+		"adminnetworkpolicy":         true,
+		"baselineadminnetworkpolicy": true,
+		"clusterrole":                true,
+		"clusterrolebinding":         true,
+		"clustertrustbundle":         true,
+		"customresourcedefinition":   true,
+		"deviceclass":                true,
+		"endpointslice":              true,
+		"event":                      true,
+		"flowschema":                 true,
+		"gateway":                    true,
+		"gatewayclass":               true,
+		"httproute":                  true,
+		"ingressclass":               true,
+		"prioritylevelconfiguration": true,
+		"resourceclaim":              true,
+		"resourceclaimtemplate":      true,
+		"resourceslice":              true,
+		"role":                       true,
+		"rolebinding":                true,
+		"runtimeclass":               true,
+		"serviceaccount":             true,
+		"verticalpodautoscaler":      true,
+		"volumeattributesclass":      true,
 	}
 	nonResources := map[string]bool{
-		"builder":   true,
-		"utils":     true,
-		"testutils": true,
+		"builder":          true,
+		"dra":              true,
+		"gatewayapi":       true,
+		"metadata":         true,
+		"networkpolicyapi": true,
+		"objectcount":      true,
+		"utils":            true,
+		"testutils":        true,
+		"unstructured":     true,
 	}
 
 	files, err := os.ReadDir("../../internal/store/")